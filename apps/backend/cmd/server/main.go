@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -27,7 +28,9 @@ import (
 	"github.com/opena2a/identity/backend/internal/infrastructure/auth"
 	"github.com/opena2a/identity/backend/internal/infrastructure/cache"
 	"github.com/opena2a/identity/backend/internal/infrastructure/email"
+	"github.com/opena2a/identity/backend/internal/infrastructure/netguard"
 	"github.com/opena2a/identity/backend/internal/infrastructure/repository"
+	"github.com/opena2a/identity/backend/internal/infrastructure/spiffe"
 	"github.com/opena2a/identity/backend/internal/interfaces/http/handlers"
 	"github.com/opena2a/identity/backend/internal/interfaces/http/middleware"
 )
@@ -118,7 +121,12 @@ func main() {
 	}
 
 	// Initialize application services
-	services, keyVault := initServices(db, repos, cacheService, oauthRepo, jwtService, emailService)
+	services, keyVault := initServices(db, repos, cacheService, oauthRepo, jwtService, emailService, cfg)
+
+	// Register scheduled maintenance jobs
+	if err := registerScheduledJobs(services, repos, cfg); err != nil {
+		log.Fatal("Failed to register scheduled jobs:", err)
+	}
 
 	// Initialize handlers
 	h := initHandlers(services, repos, jwtService, keyVault, cfg, db)
@@ -248,12 +256,35 @@ func main() {
 	sdkAPI.Post("/agents/:id/capability-requests", h.CapabilityRequest.CreateCapabilityRequest) // SDK capability request creation
 	sdkAPI.Post("/agents/:id/mcp-servers", h.Agent.AddMCPServersToAgent)                  // SDK MCP registration
 	sdkAPI.Post("/agents/:id/detection/report", h.Detection.ReportDetection)              // SDK MCP detection and integration reporting
+	sdkAPI.Post("/agents/:id/data-transfers", h.DataTransfer.ReportDataTransfers)         // SDK data transfer reporting (MCP calls)
 	
 	// ✅ Action verification for SDK (API key auth)
 	sdkAPI.Post("/verifications", h.Verification.CreateVerification)                 // Request verification for agent action (SDK)
 	sdkAPI.Get("/verifications/:id", h.Verification.GetVerification)                 // Get verification status by ID (SDK)
 	sdkAPI.Post("/verifications/:id/result", h.Verification.SubmitVerificationResult) // Submit verification result (SDK)
 
+	// ✅ OAuth 2.0 authorization server for agents (clients authenticate with signed assertions)
+	app.Get("/.well-known/oauth-authorization-server", h.OAuth.Metadata)
+	app.Get("/.well-known/openid-configuration", h.SigningKey.OpenIDConfiguration)
+	app.Get("/.well-known/jwks.json", h.SigningKey.JWKS)
+	oauth := app.Group("/api/v1/oauth")
+	oauth.Use(middleware.StrictRateLimitMiddleware())
+	oauth.Post("/token", h.OAuth.Token)
+	oauth.Post("/introspect", h.OAuth.Introspect)
+	oauth.Post("/revoke", h.OAuth.Revoke)
+	oauth.Get("/jwks", h.OAuth.JWKS)
+
+	// ✅ A2A Agent Cards, one public URL per published agent
+	app.Get("/a2a/agents/:id/.well-known/agent.json", h.AgentCard.WellKnownCard)
+
+	// ✅ Decentralized identifiers and credential status lists (did:web resolves under the issuer URL)
+	app.Get("/.well-known/did.json", h.Credential.IssuerDIDDocument)
+	app.Get("/agents/:id/did.json", h.Credential.AgentDIDDocument)
+	app.Get("/credentials/status/:id", h.Credential.StatusList)
+
+	// ✅ Agent CA certificate (trust anchor for the X.509-SVIDs it issues)
+	app.Get("/pki/ca.pem", h.WorkloadIdentity.CACertificate)
+
 	// API v1 routes (JWT authenticated)
 	v1 := app.Group("/api/v1")
 	setupRoutes(v1, h, services, jwtService, repos.SDKToken, db)
@@ -268,6 +299,22 @@ func main() {
 		log.Printf("💾 Redis: disabled (running without caching)")
 	}
 
+	// ✅ Start scheduled maintenance jobs (only one replica runs each job via advisory locks)
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	if cfg.Scheduler.Enabled {
+		services.JobScheduler.Start(schedulerCtx)
+	} else {
+		log.Println("ℹ️  Job scheduler disabled (SCHEDULER_ENABLED=false) - jobs can still be triggered manually")
+	}
+
+	// ✅ Start SIEM forwarding (every replica forwards the events it produces)
+	siemCtx, stopSIEM := context.WithCancel(context.Background())
+	defer stopSIEM()
+	if err := services.SIEMForwarder.Start(siemCtx); err != nil {
+		log.Printf("⚠️  Failed to start SIEM forwarder: %v", err)
+	}
+
 	// Graceful shutdown
 	go func() {
 		if err := app.Listen(":" + port); err != nil {
@@ -281,6 +328,10 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopScheduler()
+	services.JobScheduler.Wait()
+	stopSIEM()
+	services.SIEMForwarder.Wait()
 
 	if err := app.Shutdown(); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
@@ -289,6 +340,189 @@ func main() {
 	log.Println("Server exited")
 }
 
+// registerScheduledJobs registers the background maintenance jobs with the job scheduler
+func registerScheduledJobs(services *Services, repos *Repositories, cfg *config.Config) error {
+	jobs := services.JobScheduler
+
+	if err := jobs.Register(
+		"invalidate_expired_attestations",
+		"Mark MCP attestations past their expiry as invalid",
+		"*/15 * * * *",
+		5*time.Minute,
+		services.MCPAttestation.InvalidateExpiredAttestations,
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"recalculate_confidence_scores",
+		"Recalculate attestation confidence scores for all MCP servers",
+		"0 * * * *",
+		30*time.Minute,
+		services.MCPAttestation.RecalculateAllConfidenceScores,
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"cleanup_expired_sdk_tokens",
+		"Delete expired SDK tokens",
+		"30 3 * * *",
+		10*time.Minute,
+		services.SDKToken.CleanupExpiredTokens,
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"cleanup_expired_agent_tokens",
+		"Delete expired agent access tokens and client assertion IDs",
+		"45 3 * * *",
+		10*time.Minute,
+		services.AgentToken.CleanupExpired,
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"cleanup_expired_refresh_tokens",
+		"Delete refresh token families whose tokens have all expired",
+		"50 3 * * *",
+		10*time.Minute,
+		services.RefreshToken.CleanupExpired,
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"cleanup_expired_agent_certificates",
+		"Delete agent certificates that expired over a week ago",
+		"55 3 * * *",
+		10*time.Minute,
+		services.WorkloadIdentity.CleanupExpiredCertificates,
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"reconcile_siem_sinks",
+		"Start, restart or stop this instance's SIEM forwarding workers for sinks changed on other instances",
+		"* * * * *",
+		time.Minute,
+		services.SIEMForwarder.Reconcile,
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"rotate_jwt_signing_keys",
+		"Promote the next user JWT signing key when the active one is due and drop expired keys",
+		"5 * * * *",
+		5*time.Minute,
+		services.SigningKey.Rotate,
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"proactive_alert_checks",
+		"Raise alerts for expiring API keys and low trust scores in every organization",
+		"0 */6 * * *",
+		30*time.Minute,
+		func(ctx context.Context) error {
+			orgIDs, err := repos.Organization.ListActiveIDs()
+			if err != nil {
+				return err
+			}
+			failed := 0
+			for _, orgID := range orgIDs {
+				if err := services.Alert.RunProactiveChecks(ctx, orgID); err != nil {
+					log.Printf("⚠️  Proactive checks failed for organization %s: %v", orgID, err)
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("proactive checks failed for %d of %d organizations", failed, len(orgIDs))
+			}
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"create_audit_checkpoints",
+		"Verify new audit log records and sign a checkpoint of every organization's audit chain",
+		"0 * * * *",
+		30*time.Minute,
+		func(ctx context.Context) error {
+			_, err := services.AuditChain.CreateCheckpoints(ctx)
+			return err
+		},
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"process_access_reviews",
+		"Remind access reviewers and close out campaigns past their due date",
+		"0 * * * *",
+		30*time.Minute,
+		services.AccessReview.ProcessCampaigns,
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"purge_expired_data",
+		"Delete records past each organization's retention period, skipping tables on legal hold",
+		"15 4 * * *",
+		2*time.Hour,
+		func(ctx context.Context) error {
+			_, err := services.DataRetention.RunPurge(ctx)
+			return err
+		},
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"prune_data_transfers",
+		"Delete data transfer records older than the longest data_exfiltration policy window",
+		"45 4 * * *",
+		30*time.Minute,
+		services.DataExfiltration.PruneTransfers,
+	); err != nil {
+		return err
+	}
+
+	if err := jobs.Register(
+		"expire_verification_approvals",
+		"Time out agent actions nobody approved or denied before their deadline",
+		"* * * * *",
+		time.Minute,
+		services.VerificationApproval.ExpirePending,
+	); err != nil {
+		return err
+	}
+
+	if cfg.MCPHealth.Enabled {
+		if err := jobs.Register(
+			"mcp_health_checks",
+			"Re-verify MCP servers (reachability + Ed25519 challenge-response)",
+			"@every "+cfg.MCPHealth.Interval.String(),
+			cfg.MCPHealth.Interval,
+			services.MCPHealthMonitor.RunScheduledChecks,
+		); err != nil {
+			return err
+		}
+	} else {
+		log.Println("ℹ️  MCP health monitor disabled (MCP_HEALTH_CHECK_ENABLED=false)")
+	}
+
+	return nil
+}
+
 func initDatabase(cfg *config.Config) (*sql.DB, error) {
 	// Build connection string using key=value format to avoid URL encoding issues
 	// This format works better with passwords containing special characters
@@ -348,6 +582,24 @@ type Repositories struct {
 	MCPServer         *repository.MCPServerRepository
 	MCPCapability     *repository.MCPServerCapabilityRepository // ✅ For MCP server capabilities
 	MCPAttestation    *repository.MCPAttestationRepository       // ✅ For agent attestation of MCPs
+	MCPToolSurface    *repository.MCPToolSurfaceRepository       // ✅ For MCP tool-surface snapshots (rug-pull detection)
+	MCPHealth         *repository.MCPServerHealthRepository      // ✅ For scheduled MCP re-verification history
+	ScheduledJob      *repository.ScheduledJobRepository         // ✅ For scheduled job run history and leader election
+	SIEMSink          *repository.SIEMSinkRepository             // ✅ For SIEM forwarding destinations
+	AccessReview      *repository.AccessReviewRepository         // ✅ For access review campaigns
+	DataRetention     *repository.DataRetentionRepository        // ✅ For retention rules and legal holds
+	ActivityBaseline  *repository.AgentActivityBaselineRepository // ✅ For learned per-agent activity baselines
+	DataTransfer      *repository.DataTransferRepository          // ✅ For data exfiltration byte accounting
+	VerificationApproval *repository.VerificationApprovalRepository // ✅ For the human approval queue
+	ActionCatalog     *repository.ActionCatalogRepository        // ✅ For per-organization action risk catalogs
+	Quota             *repository.QuotaRepository                // ✅ For plan quota usage counters
+	AgentToken        *repository.AgentTokenRepository           // ✅ For issued agent OAuth access tokens
+	SigningKey        *repository.SigningKeyRepository           // ✅ For rotating user JWT signing keys
+	RefreshToken      *repository.RefreshTokenRepository         // ✅ For refresh token families (user sessions)
+	AgentCard         *repository.AgentCardRepository            // ✅ For published A2A Agent Cards
+	AgentCredential   *repository.AgentCredentialRepository      // ✅ For agent verifiable credentials and status lists
+	SPIFFEBinding     *repository.SPIFFEBindingRepository        // ✅ For SPIFFE ID patterns bound to agents
+	AgentCertificate  *repository.AgentCertificateRepository     // ✅ For X.509-SVIDs issued to agents
 	Security          *repository.SecurityRepository
 	SecurityPolicy    *repository.SecurityPolicyRepository // ✅ For configurable security policies
 	Webhook           *repository.WebhookRepository
@@ -376,6 +628,24 @@ func initRepositories(db *sql.DB) (*Repositories, *repository.OAuthRepositoryPos
 		MCPServer:         repository.NewMCPServerRepository(db),
 		MCPCapability:     repository.NewMCPServerCapabilityRepository(db), // ✅ For MCP server capabilities
 		MCPAttestation:    repository.NewMCPAttestationRepository(db),       // ✅ For agent attestation of MCPs
+		MCPToolSurface:    repository.NewMCPToolSurfaceRepository(db),       // ✅ For MCP tool-surface snapshots (rug-pull detection)
+		MCPHealth:         repository.NewMCPServerHealthRepository(db),      // ✅ For scheduled MCP re-verification history
+		ScheduledJob:      repository.NewScheduledJobRepository(db),         // ✅ For scheduled job run history and leader election
+		SIEMSink:          repository.NewSIEMSinkRepository(db),             // ✅ For SIEM forwarding destinations
+		AccessReview:      repository.NewAccessReviewRepository(db),         // ✅ For access review campaigns
+		DataRetention:     repository.NewDataRetentionRepository(db),        // ✅ For retention rules and legal holds
+		ActivityBaseline:  repository.NewAgentActivityBaselineRepository(db), // ✅ For learned per-agent activity baselines
+		DataTransfer:      repository.NewDataTransferRepository(db),         // ✅ For data exfiltration byte accounting
+		VerificationApproval: repository.NewVerificationApprovalRepository(db), // ✅ For the human approval queue
+		ActionCatalog:     repository.NewActionCatalogRepository(db),        // ✅ For per-organization action risk catalogs
+		Quota:             repository.NewQuotaRepository(db),                // ✅ For plan quota usage counters
+		AgentToken:        repository.NewAgentTokenRepository(db),           // ✅ For issued agent OAuth access tokens
+		SigningKey:        repository.NewSigningKeyRepository(db),           // ✅ For rotating user JWT signing keys
+		RefreshToken:      repository.NewRefreshTokenRepository(db),         // ✅ For refresh token families (user sessions)
+		AgentCard:         repository.NewAgentCardRepository(db),            // ✅ For published A2A Agent Cards
+		AgentCredential:   repository.NewAgentCredentialRepository(db),      // ✅ For agent verifiable credentials and status lists
+		SPIFFEBinding:     repository.NewSPIFFEBindingRepository(db),        // ✅ For SPIFFE ID patterns bound to agents
+		AgentCertificate:  repository.NewAgentCertificateRepository(db),     // ✅ For X.509-SVIDs issued to agents
 		Security:          repository.NewSecurityRepository(db),
 		SecurityPolicy:    repository.NewSecurityPolicyRepository(db), // ✅ For configurable security policies
 		Webhook:           repository.NewWebhookRepository(db),
@@ -396,9 +666,29 @@ type Services struct {
 	Audit             *application.AuditService
 	Alert             *application.AlertService
 	Compliance        *application.ComplianceService
+	EvidencePack      *application.ComplianceEvidenceService // ✅ For framework evidence packs
 	MCP               *application.MCPService
 	MCPCapability     *application.MCPCapabilityService     // ✅ For MCP server capability management
 	MCPAttestation    *application.MCPAttestationService    // ✅ For agent attestation of MCPs
+	MCPToolSurface    *application.MCPToolSurfaceService    // ✅ For MCP tool-surface change detection
+	MCPHealthMonitor  *application.MCPHealthMonitorService  // ✅ For scheduled MCP re-verification
+	JobScheduler      *application.JobSchedulerService      // ✅ For scheduled maintenance jobs
+	AuditChain        *application.AuditChainService        // ✅ For audit log tamper detection
+	SIEMForwarder     *application.SIEMForwarderService     // ✅ For streaming security events to SIEMs
+	AccessReview      *application.AccessReviewService      // ✅ For access review campaigns
+	DataRetention     *application.DataRetentionService     // ✅ For retention rules, legal holds and purges
+	AnomalyDetection  *application.AnomalyDetectionService  // ✅ For baseline anomaly detection on agent activity
+	DataExfiltration  *application.DataExfiltrationService  // ✅ For data exfiltration thresholds and export blocking
+	VerificationApproval *application.VerificationApprovalService // ✅ For human approval of high-risk actions
+	ActionCatalog     *application.ActionCatalogService     // ✅ For action risk tiers, required trust and capabilities
+	Quota             *application.QuotaService             // ✅ For plan quota enforcement and usage reporting
+	OrganizationHierarchy *application.OrganizationHierarchyService // ✅ For child organizations and subtree scopes
+	AgentToken        *application.AgentTokenService        // ✅ For the agent OAuth 2.0 authorization server
+	SigningKey        *application.SigningKeyService        // ✅ For asymmetric user JWT signing and the JWKS
+	RefreshToken      *application.RefreshTokenService      // ✅ For session refresh token rotation and reuse detection
+	AgentCard         *application.AgentCardService         // ✅ For signing and verifying A2A Agent Cards
+	Credential        *application.CredentialService        // ✅ For agent DIDs and verifiable credentials
+	WorkloadIdentity  *application.WorkloadIdentityService  // ✅ For SPIFFE agent authentication and the agent CA
 	Security          *application.SecurityService
 	SecurityPolicy    *application.SecurityPolicyService // ✅ For policy-based enforcement
 	Webhook           *application.WebhookService
@@ -411,7 +701,7 @@ type Services struct {
 	Detection         *application.DetectionService         // ✅ For MCP auto-detection (SDK + Direct API)
 }
 
-func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCache, oauthRepo *repository.OAuthRepositoryPostgres, jwtService *auth.JWTService, emailService domain.EmailService) (*Services, *crypto.KeyVault) {
+func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCache, oauthRepo *repository.OAuthRepositoryPostgres, jwtService *auth.JWTService, emailService domain.EmailService, cfg *config.Config) (*Services, *crypto.KeyVault) {
 	// ✅ Initialize KeyVault for secure private key storage
 	keyVault, err := crypto.NewKeyVaultFromEnv()
 	if err != nil {
@@ -419,10 +709,41 @@ func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCach
 	}
 	log.Println("✅ KeyVault initialized for automatic key generation")
 
+	// ✅ Sign user tokens with rotating asymmetric keys published at /.well-known/jwks.json
+	// The same keys sign agent verifiable credentials, which outlive any token
+	signingKeyRetention := jwtService.MaxTokenLifetime()
+	if application.MaxCredentialValidity > signingKeyRetention {
+		signingKeyRetention = application.MaxCredentialValidity
+	}
+	signingKeyService := application.NewSigningKeyService(
+		repos.SigningKey,
+		keyVault, // ✅ Private keys are stored encrypted
+		cfg.JWT.SigningAlgorithm,
+		cfg.JWT.KeyRotationInterval,
+		signingKeyRetention, // ✅ Retired keys verify until the tokens and credentials they signed expire
+	)
+	if err := signingKeyService.Rotate(context.Background()); err != nil {
+		log.Fatal("Failed to initialize JWT signing keys:", err)
+	}
+	jwtService.UseSigningKeys(signingKeyService, cfg.AgentToken.Issuer, cfg.JWT.AcceptLegacyHMAC)
+	log.Printf("✅ User tokens signed with %s keys", cfg.JWT.SigningAlgorithm)
+
+	// ✅ Initialize SIEM forwarder first: audit records, alerts and verification events written
+	// through these repositories are also streamed to each organization's SIEM sinks
+	outboundPolicy, err := netguard.NewPolicy(cfg.Outbound.AllowedNetworks)
+	if err != nil {
+		log.Fatal("Failed to parse OUTBOUND_ALLOWED_NETWORKS:", err)
+	}
+	siemForwarderService := application.NewSIEMForwarderService(repos.SIEMSink, outboundPolicy)
+	siemAuditLogs := application.NewSIEMAuditLogRepository(repos.AuditLog, siemForwarderService)
+	siemAlerts := application.NewSIEMAlertRepository(repos.Alert, siemForwarderService)
+	siemVerificationEvents := application.NewSIEMVerificationEventRepository(repos.VerificationEvent, siemForwarderService)
+
 	// ✅ Initialize Security Policy Service for policy-based enforcement
 	securityPolicyService := application.NewSecurityPolicyService(
 		repos.SecurityPolicy,
-		repos.Alert,
+		siemAlerts,
+		repos.Organization, // ✅ For policies inherited from parent organizations
 	)
 
 	// Create services
@@ -439,26 +760,46 @@ func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCach
 		repos.Organization,
 	)
 
-	auditService := application.NewAuditService(repos.AuditLog)
+	auditService := application.NewAuditService(siemAuditLogs)
 
 	trustCalculator := application.NewTrustCalculator(
 		repos.TrustScore,
 		repos.APIKey,
 		repos.AuditLog,
 		repos.Capability,
-		repos.Agent,  // For fetching agent data
-		repos.Alert,  // For security alerts scoring
+		repos.Agent,     // For fetching agent data
+		repos.Alert,     // For security alerts scoring
+		repos.MCPHealth, // For uptime scoring from MCP server health checks
 	)
 
 	// ✅ Initialize drift detection service BEFORE verification event service
 	driftDetectionService := application.NewDriftDetectionService(
 		repos.Agent,
-		repos.Alert,
+		siemAlerts,
+	)
+
+	// ✅ Action risk catalog - consulted by both verification paths and MCP capability detection
+	actionCatalogService := application.NewActionCatalogService(repos.ActionCatalog)
+
+	// ✅ Parent/child organizations - subtree admin scopes and rolled-up analytics
+	organizationHierarchyService := application.NewOrganizationHierarchyService(
+		repos.Organization,
+		repos.Organization, // ✅ Also implements OrganizationHierarchyRepository
+		repos.User,
+		actionCatalogService, // ✅ For seeding the action catalog of child organizations
+	)
+
+	// ✅ Plan quotas - enforced when agents, users and MCP servers are added and on verify-action calls
+	quotaService := application.NewQuotaService(
+		repos.Organization,
+		repos.Quota,
+		siemAlerts,         // ✅ For 80% soft-limit warnings
+		repos.Organization, // ✅ Child organizations share their top-level organization's quotas
 	)
 
 	// ✅ Initialize verification event service BEFORE agent service
 	verificationEventService := application.NewVerificationEventService(
-		repos.VerificationEvent,
+		siemVerificationEvents,
 		repos.Agent,
 		driftDetectionService,
 	)
@@ -468,10 +809,12 @@ func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCach
 		trustCalculator,
 		repos.TrustScore,
 		keyVault,                    // ✅ NEW: Inject KeyVault for automatic key generation
-		repos.Alert,                 // ✅ NEW: Inject AlertRepository for security alerts
+		siemAlerts,                  // ✅ NEW: Inject AlertRepository for security alerts
 		securityPolicyService,       // ✅ NEW: Inject SecurityPolicyService for policy evaluation
 		repos.Capability,            // ✅ NEW: Inject CapabilityRepository for capability checks
 		verificationEventService,    // ✅ NEW: Inject VerificationEventService for creating verification events
+		actionCatalogService,        // ✅ Inject ActionCatalogService for action risk tiers and required capabilities
+		quotaService,                // ✅ Inject QuotaService for the plan's agent quota
 	)
 
 	apiKeyService := application.NewAPIKeyService(
@@ -480,29 +823,52 @@ func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCach
 	)
 
 	alertService := application.NewAlertService(
-		repos.Alert,
+		siemAlerts,
 		repos.Agent,
+		repos.APIKey,
 	)
 
 	complianceService := application.NewComplianceService(
 		repos.AuditLog,
+		repos.AuditLog, // ✅ For streamed audit log exports
 		repos.Agent,
 		repos.User,
 	)
 
+	// ✅ Initialize MCP tool-surface service BEFORE MCP capability service
+	mcpToolSurfaceService := application.NewMCPToolSurfaceService(
+		repos.MCPToolSurface,
+		repos.MCPServer,
+		siemAlerts,
+		repos.SecurityPolicy, // ✅ config_drift policies decide whether drifted servers are suspended
+	)
+
 	// ✅ Initialize MCP capability service BEFORE MCP service
 	mcpCapabilityService := application.NewMCPCapabilityService(
 		repos.MCPCapability,
 		repos.MCPServer,
+		mcpToolSurfaceService,               // ✅ For tool-surface change (rug-pull) detection
+		application.NewMCPMetadataScanner(), // ✅ For prompt-injection / tool-poisoning detection
+		siemAlerts,
 	)
 
 	mcpService := application.NewMCPService(
 		repos.MCPServer,
-		repos.VerificationEvent,
+		siemVerificationEvents,
 		repos.User,
 		keyVault,             // ✅ For automatic key generation
 		mcpCapabilityService, // ✅ For automatic capability detection
 		repos.Agent,          // ✅ For connected agents tracking
+		quotaService,         // ✅ For the plan's MCP server quota
+	)
+
+	// ✅ Initialize MCP health monitor AFTER MCP service (reuses its challenge-response)
+	mcpHealthMonitorService := application.NewMCPHealthMonitorService(
+		mcpService,
+		repos.MCPServer,
+		repos.MCPHealth,
+		siemVerificationEvents,
+		siemAlerts,
 	)
 
 	// ✅ Initialize MCP Attestation Service for agent attestation of MCPs
@@ -515,13 +881,62 @@ func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCach
 	securityService := application.NewSecurityService(
 		repos.Security,
 		repos.Agent,
-		repos.Alert, // ✅ For converting alerts to threats (NO MOCK DATA!)
+		siemAlerts, // ✅ For converting alerts to threats (NO MOCK DATA!)
 	)
 
 	webhookService := application.NewWebhookService(
 		repos.Webhook,
 	)
 
+	// ✅ Initialize job scheduler (jobs are registered in registerScheduledJobs)
+	jobSchedulerService := application.NewJobSchedulerService(repos.ScheduledJob)
+
+	// ✅ Initialize audit chain service (signed checkpoints + tamper detection)
+	auditSigningKey, err := application.LoadAuditSigningKey(cfg.Audit.SigningKey)
+	if err != nil {
+		log.Fatal("Failed to load audit signing key:", err)
+	}
+	auditTrustedKeys, err := application.ParseAuditPublicKeys(cfg.Audit.TrustedPublicKeys)
+	if err != nil {
+		log.Fatal("Failed to parse trusted audit public keys:", err)
+	}
+	auditChainService := application.NewAuditChainService(repos.AuditLog, auditSigningKey, auditTrustedKeys)
+
+	// ✅ Initialize agent OAuth 2.0 authorization server (client credentials + token exchange)
+	agentTokenSigningKey, err := application.LoadAgentTokenSigningKey(cfg.AgentToken.SigningKey)
+	if err != nil {
+		log.Fatal("Failed to load agent token signing key:", err)
+	}
+	agentTokenTrustedKeys, err := application.ParseAuditPublicKeys(cfg.AgentToken.TrustedPublicKeys)
+	if err != nil {
+		log.Fatal("Failed to parse trusted agent token public keys:", err)
+	}
+	agentTokenService := application.NewAgentTokenService(
+		repos.Agent,
+		repos.Capability,
+		repos.User,
+		repos.AgentToken,
+		jwtService, // ✅ Validates user tokens presented for token exchange
+		agentTokenSigningKey,
+		agentTokenTrustedKeys,
+		cfg.AgentToken.Issuer,
+		cfg.AgentToken.TTL,
+	)
+
+	// ✅ Initialize evidence pack service (declarative framework catalogs, signed with the audit key)
+	evidencePackService, err := application.NewComplianceEvidenceService(
+		repos.User,
+		repos.APIKey,
+		repos.Agent,
+		repos.CapabilityRequest,
+		repos.AuditLog,
+		auditChainService,
+		auditSigningKey,
+	)
+	if err != nil {
+		log.Fatal("Failed to load compliance catalogs:", err)
+	}
+
 	// Initialize RegistrationService for email/password user registration workflow
 	registrationService := application.NewRegistrationService(
 		oauthRepo, // Still uses oauth_repository for now (will be renamed in later step)
@@ -529,6 +944,8 @@ func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCach
 		repos.Organization, // ✅ NEW: Organization repository for auto-creating orgs
 		auditService,
 		emailService, // ✅ NEW: Email service for password reset and admin notifications
+		actionCatalogService, // ✅ For seeding the default action catalog of new organizations
+		quotaService,         // ✅ For the plan's user quota when approving registrations
 	)
 
 	tagService := application.NewTagService(
@@ -544,9 +961,10 @@ func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCach
 	capabilityService := application.NewCapabilityService(
 		repos.Capability,
 		repos.Agent,
-		repos.AuditLog,
+		siemAuditLogs,
 		trustCalculator,
 		repos.TrustScore,
+		actionCatalogService, // ✅ For mapping MCP tool names to capabilities
 	)
 
 	capabilityRequestService := application.NewCapabilityRequestService(
@@ -555,6 +973,134 @@ func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCach
 		repos.Agent,
 	)
 
+	// ✅ Initialize access review service (revokes through the capability and API key services)
+	accessReviewService := application.NewAccessReviewService(
+		repos.AccessReview,
+		repos.User,
+		repos.Agent,
+		repos.Capability,
+		repos.APIKey,
+		capabilityService,
+		apiKeyService,
+		emailService,    // ✅ For reviewer reminders
+		auditSigningKey, // ✅ Completed campaigns are signed like audit checkpoints
+	)
+
+	// ✅ Initialize data retention service (purges are recorded in the audit log and forwarded to SIEMs)
+	dataRetentionService := application.NewDataRetentionService(
+		repos.DataRetention,
+		siemAuditLogs,
+		auditChainService, // ✅ Audit logs are only purged up to a signed checkpoint
+		repos.Organization,
+	)
+
+	// ✅ Initialize anomaly detection (unusual_activity policies decide alerting and blocking)
+	anomalyDetectionService := application.NewAnomalyDetectionService(
+		repos.ActivityBaseline,
+		repos.Security,
+		securityPolicyService,
+		siemAlerts,
+	)
+
+	// ✅ Initialize data exfiltration accounting (data_exfiltration policies set thresholds and blocking)
+	dataExfiltrationService := application.NewDataExfiltrationService(
+		repos.DataTransfer,
+		securityPolicyService,
+		siemAlerts,
+	)
+
+	// ✅ Initialize the approval queue for high-risk actions (approvers are notified by email and webhook)
+	verificationApprovalService := application.NewVerificationApprovalService(
+		repos.VerificationApproval,
+		repos.User,
+		verificationEventService, // ✅ Verification events are completed when an approver decides
+		webhookService,
+		emailService,
+		cfg.JWT.Secret, // ✅ Signing key for one-click approval links is derived from it
+		cfg.Approval.Timeout,
+	)
+	// ✅ SDK callbacks cannot reach internal addresses, and must use https outside development
+	verificationApprovalService.RestrictCallbacks(outboundPolicy, cfg.Server.Environment != "development")
+
+	// ✅ Track refresh token families so a replayed refresh token signs its session out
+	refreshTokenService := application.NewRefreshTokenService(
+		repos.RefreshToken,
+		repos.User,
+		siemAlerts,   // ✅ Reuse alerts are forwarded to SIEM sinks
+		emailService, // ✅ Users are told when a session is signed out
+		jwtService,
+	)
+	jwtService.UseSessionChecker(refreshTokenService) // ✅ Signed-out sessions' access tokens stop working
+
+	// ✅ Publish agents as A2A Agent Cards signed with the user token keys (verifiable via the JWKS)
+	agentCardService := application.NewAgentCardService(
+		repos.AgentCard,
+		repos.Agent,
+		repos.Capability, // ✅ Cards carry the agent's granted capabilities
+		repos.Organization,
+		signingKeyService,
+		cfg.AgentToken.Issuer,
+	)
+
+	// ✅ Agent DIDs and verifiable credentials, signed with the same keys (published in AIM's did:web document)
+	credentialService := application.NewCredentialService(
+		repos.AgentCredential,
+		repos.Agent,
+		repos.Capability, // ✅ Credentials assert the agent's granted capabilities
+		repos.Organization,
+		signingKeyService,
+		cfg.AgentToken.Issuer,
+	)
+
+	// ✅ SPIFFE workload identity: agents may authenticate with X.509-SVIDs or JWT-SVIDs
+	spiffeBundles, err := spiffe.LoadBundles(cfg.SPIFFE.TrustBundles)
+	if err != nil {
+		log.Fatal("Failed to load SPIFFE trust bundles:", err)
+	}
+	workloadIdentityService := application.NewWorkloadIdentityService(
+		repos.SPIFFEBinding,
+		repos.AgentCertificate,
+		repos.Agent,
+		spiffeBundles,
+		cfg.SPIFFE.JWTAudience,
+		cfg.AgentToken.Issuer,
+	)
+	if cfg.SPIFFE.ClientCertHeader != "" {
+		if err := workloadIdentityService.TrustForwardedCertificates(cfg.SPIFFE.ClientCertHeader, cfg.SPIFFE.TrustedProxies); err != nil {
+			log.Fatal("Invalid SPIFFE_CLIENT_CERT_HEADER/SPIFFE_TRUSTED_PROXIES:", err)
+		}
+	}
+	if cfg.SPIFFE.CAEnabled {
+		trustDomain := cfg.SPIFFE.CATrustDomain
+		if trustDomain == "" {
+			issuerURL, err := url.Parse(cfg.AgentToken.Issuer)
+			if err != nil {
+				log.Fatal("Failed to derive the agent CA trust domain:", err)
+			}
+			trustDomain = strings.ToLower(issuerURL.Hostname())
+		}
+
+		var agentCA *spiffe.CA
+		if cfg.SPIFFE.CACertFile == "" {
+			log.Printf("⚠️  AGENT_CA_CERT_FILE not set - using an ephemeral agent CA (development only)")
+			agentCA, err = spiffe.NewEphemeralCA(trustDomain, 30*24*time.Hour)
+		} else {
+			certificatePEM, readErr := os.ReadFile(cfg.SPIFFE.CACertFile)
+			if readErr != nil {
+				log.Fatal("Failed to read agent CA certificate:", readErr)
+			}
+			keyPEM, readErr := os.ReadFile(cfg.SPIFFE.CAKeyFile)
+			if readErr != nil {
+				log.Fatal("Failed to read agent CA key:", readErr)
+			}
+			agentCA, err = spiffe.NewCA(trustDomain, certificatePEM, keyPEM)
+		}
+		if err != nil {
+			log.Fatal("Failed to initialize agent CA:", err)
+		}
+		workloadIdentityService.UseCA(agentCA, cfg.SPIFFE.CertificateTTL)
+	}
+
 	detectionService := application.NewDetectionService(
 		db,
 		trustCalculator, // ✅ NEW: Inject trust calculator for proper risk assessment
@@ -570,9 +1116,29 @@ func initServices(db *sql.DB, repos *Repositories, cacheService *cache.RedisCach
 		Audit:             auditService,
 		Alert:             alertService,
 		Compliance:        complianceService,
+		EvidencePack:      evidencePackService, // ✅ For framework evidence packs
 		MCP:               mcpService,
 		MCPCapability:     mcpCapabilityService,     // ✅ For MCP server capability management
 		MCPAttestation:    mcpAttestationService,    // ✅ For agent attestation of MCPs
+		MCPToolSurface:    mcpToolSurfaceService,    // ✅ For MCP tool-surface change detection
+		MCPHealthMonitor:  mcpHealthMonitorService,  // ✅ For scheduled MCP re-verification
+		JobScheduler:      jobSchedulerService,      // ✅ For scheduled maintenance jobs
+		AuditChain:        auditChainService,        // ✅ For audit log tamper detection
+		SIEMForwarder:     siemForwarderService,     // ✅ For streaming security events to SIEMs
+		AccessReview:      accessReviewService,      // ✅ For access review campaigns
+		DataRetention:     dataRetentionService,     // ✅ For retention rules, legal holds and purges
+		AnomalyDetection:  anomalyDetectionService,  // ✅ For baseline anomaly detection on agent activity
+		DataExfiltration:  dataExfiltrationService,  // ✅ For data exfiltration thresholds and export blocking
+		VerificationApproval: verificationApprovalService, // ✅ For human approval of high-risk actions
+		ActionCatalog:     actionCatalogService,     // ✅ For action risk tiers, required trust and capabilities
+		Quota:             quotaService,             // ✅ For plan quota enforcement and usage reporting
+		OrganizationHierarchy: organizationHierarchyService, // ✅ For child organizations and subtree scopes
+		AgentToken:        agentTokenService,        // ✅ For the agent OAuth 2.0 authorization server
+		SigningKey:        signingKeyService,        // ✅ For asymmetric user JWT signing and the JWKS
+		RefreshToken:      refreshTokenService,      // ✅ For session refresh token rotation and reuse detection
+		AgentCard:         agentCardService,         // ✅ For signing and verifying A2A Agent Cards
+		Credential:        credentialService,        // ✅ For agent DIDs and verifiable credentials
+		WorkloadIdentity:  workloadIdentityService,  // ✅ For SPIFFE agent authentication and the agent CA
 		Security:          securityService,
 		SecurityPolicy:    securityPolicyService, // ✅ For policy-based enforcement
 		Webhook:           webhookService,
@@ -595,6 +1161,24 @@ type Handlers struct {
 	Compliance         *handlers.ComplianceHandler
 	MCP                *handlers.MCPHandler
 	MCPAttestation     *handlers.MCPAttestationHandler    // ✅ For agent attestation of MCPs
+	MCPToolSurface     *handlers.MCPToolSurfaceHandler    // ✅ For MCP tool-surface review
+	MCPHealth          *handlers.MCPHealthHandler         // ✅ For MCP re-verification health
+	ScheduledJob       *handlers.ScheduledJobHandler      // ✅ For scheduled maintenance job admin
+	AuditChain         *handlers.AuditChainHandler        // ✅ For audit log integrity verification
+	SIEMSink           *handlers.SIEMSinkHandler          // ✅ For SIEM sink management
+	AccessReview       *handlers.AccessReviewHandler      // ✅ For access review campaigns
+	DataRetention      *handlers.DataRetentionHandler     // ✅ For retention rules and legal holds
+	DataTransfer       *handlers.DataTransferHandler      // ✅ For SDK data transfer reports and data usage
+	VerificationApproval *handlers.VerificationApprovalHandler // ✅ For deciding actions awaiting approval
+	ActionCatalog      *handlers.ActionCatalogHandler     // ✅ For managing the action risk catalog
+	Quota              *handlers.QuotaHandler             // ✅ For organization quota usage
+	Organization       *handlers.OrganizationHandler      // ✅ For child organizations and rolled-up analytics
+	OAuth              *handlers.OAuthHandler             // ✅ For agent OAuth token, introspection and revocation endpoints
+	SigningKey         *handlers.SigningKeyHandler        // ✅ For the JWKS and OpenID Connect discovery
+	Session            *handlers.SessionHandler           // ✅ For listing and signing out user sessions
+	AgentCard          *handlers.AgentCardHandler         // ✅ For A2A Agent Card publishing and verification
+	Credential         *handlers.CredentialHandler        // ✅ For agent DIDs and verifiable credentials
+	WorkloadIdentity   *handlers.WorkloadIdentityHandler  // ✅ For SPIFFE bindings and agent certificates
 	Security           *handlers.SecurityHandler
 	SecurityPolicy     *handlers.SecurityPolicyHandler // ✅ For policy management
 	Analytics          *handlers.AnalyticsHandler
@@ -619,6 +1203,7 @@ func initHandlers(services *Services, repos *Repositories, jwtService *auth.JWTS
 			services.Auth,
 			jwtService,
 			repos.Organization,
+			services.RefreshToken, // ✅ Logins start a refresh token family
 		),
 		Agent: handlers.NewAgentHandler(
 			services.Agent,
@@ -628,6 +1213,9 @@ func initHandlers(services *Services, repos *Repositories, jwtService *auth.JWTS
 			handlers.NewTrustScoreHandler(services.Trust, services.Agent, services.Audit),
 			services.Alert,             // ✅ For creating security alerts on capability violations
 			services.VerificationEvent, // ✅ For recording action verification attempts in Security Dashboard
+			services.AnomalyDetection,  // ✅ For flagging actions that deviate from the agent's baseline
+			services.DataExfiltration,  // ✅ For blocking data exports over the exfiltration threshold
+			services.Quota,             // ✅ For the plan's monthly verification quota
 		),
 		APIKey: handlers.NewAPIKeyHandler(
 			services.APIKey,
@@ -649,6 +1237,7 @@ func initHandlers(services *Services, repos *Repositories, jwtService *auth.JWTS
 		),
 		Compliance: handlers.NewComplianceHandler(
 			services.Compliance,
+			services.EvidencePack, // ✅ For framework evidence packs
 			services.Audit,
 		),
 		MCP: handlers.NewMCPHandler(
@@ -662,9 +1251,85 @@ func initHandlers(services *Services, repos *Repositories, jwtService *auth.JWTS
 			services.MCPAttestation,
 			services.Audit,
 		),
+		MCPToolSurface: handlers.NewMCPToolSurfaceHandler(
+			services.MCP,
+			services.MCPToolSurface,
+			services.Audit,
+		),
+		MCPHealth: handlers.NewMCPHealthHandler(
+			services.MCP,
+			services.MCPHealthMonitor,
+			services.Audit,
+		),
+		ScheduledJob: handlers.NewScheduledJobHandler(
+			services.JobScheduler,
+			services.Audit,
+		),
+		AuditChain: handlers.NewAuditChainHandler(
+			services.AuditChain,
+			services.Audit,
+		),
+		SIEMSink: handlers.NewSIEMSinkHandler(
+			services.SIEMForwarder,
+			services.Audit,
+		),
+		AccessReview: handlers.NewAccessReviewHandler(
+			services.AccessReview,
+			services.Audit,
+		),
+		DataRetention: handlers.NewDataRetentionHandler(
+			services.DataRetention,
+			services.Audit,
+		),
+		DataTransfer: handlers.NewDataTransferHandler(
+			services.DataExfiltration,
+			services.Agent,
+		),
+		VerificationApproval: handlers.NewVerificationApprovalHandler(
+			services.VerificationApproval,
+			services.Audit,
+		),
+		ActionCatalog: handlers.NewActionCatalogHandler(
+			services.ActionCatalog,
+			services.Audit,
+		),
+		Quota: handlers.NewQuotaHandler(
+			services.Quota,
+		),
+		Organization: handlers.NewOrganizationHandler(
+			services.OrganizationHierarchy,
+			services.Audit,
+		),
+		OAuth: handlers.NewOAuthHandler(
+			services.AgentToken,
+			services.Audit,
+		),
+		SigningKey: handlers.NewSigningKeyHandler(
+			services.SigningKey,
+			services.AgentToken,
+			jwtService,
+		),
+		Session: handlers.NewSessionHandler(
+			services.RefreshToken,
+			services.Audit,
+		),
+		AgentCard: handlers.NewAgentCardHandler(
+			services.AgentCard,
+			services.Audit,
+		),
+		Credential: handlers.NewCredentialHandler(
+			services.Credential,
+			services.Audit,
+		),
+		WorkloadIdentity: handlers.NewWorkloadIdentityHandler(
+			services.WorkloadIdentity,
+			services.Audit,
+		),
 		Security: handlers.NewSecurityHandler(
 			services.Security,
 			services.Audit,
+			services.Agent,
+			services.AnomalyDetection, // ✅ For viewing and resetting activity baselines
 		),
 		SecurityPolicy: handlers.NewSecurityPolicyHandler(
 			services.SecurityPolicy,
@@ -688,6 +1353,11 @@ func initHandlers(services *Services, repos *Repositories, jwtService *auth.JWTS
 			services.Audit,
 			services.Trust,
 			services.VerificationEvent,
+			services.AnomalyDetection, // ✅ For flagging actions that deviate from the agent's baseline
+			services.DataExfiltration, // ✅ For blocking data exports over the exfiltration threshold
+			services.VerificationApproval, // ✅ For parking high-risk actions for human approval
+			services.ActionCatalog,        // ✅ For each action's risk tier and required trust
+			services.Quota,                // ✅ For the plan's monthly verification quota
 		),
 		VerificationEvent: handlers.NewVerificationEventHandler(
 			services.VerificationEvent,
@@ -701,6 +1371,7 @@ func initHandlers(services *Services, repos *Repositories, jwtService *auth.JWTS
 			services.Registration, // ✅ Renamed from OAuth to Registration
 			services.Auth,
 			jwtService,
+			services.RefreshToken, // ✅ Logins start a refresh token family
 		),
 		Tag: handlers.NewTagHandler(
 			services.Tag,
@@ -715,6 +1386,7 @@ func initHandlers(services *Services, repos *Repositories, jwtService *auth.JWTS
 		AuthRefresh: handlers.NewAuthRefreshHandler(
 			jwtService,
 			services.SDKToken,
+			services.RefreshToken, // ✅ Session refresh tokens rotate within their family
 		),
 		SDKTokenRecovery: handlers.NewSDKTokenRecoveryHandler(
 			services.SDKToken,
@@ -761,6 +1433,9 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// sdkTokenTrackingMiddleware := middleware.NewSDKTokenTrackingMiddleware(sdkTokenRepo)
 	// v1.Use(sdkTokenTrackingMiddleware.Handler()) // Apply to all API routes
 
+	// ✅ Organization scope - users may act on descendant organizations via the X-Organization-ID header
+	orgScope := middleware.OrganizationScopeMiddleware(services.OrganizationHierarchy)
+
 	// ✅ Public routes (NO authentication required) - Self-registration API
 	public := v1.Group("/public")
 	public.Use(middleware.OptionalAuthMiddleware(jwtService))                               // Try to extract user from JWT if present
@@ -772,6 +1447,10 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	public.Post("/forgot-password", h.PublicRegistration.ForgotPassword)                    // 🚀 Password reset request
 	public.Post("/reset-password", h.PublicRegistration.ResetPassword)                      // 🚀 Password reset with token
 	public.Post("/request-access", h.PublicRegistration.RequestAccess)                      // 🚀 Request platform access (no password required)
+	public.Get("/verification-approvals/:token", middleware.StrictRateLimitMiddleware(), h.VerificationApproval.ShowDecisionLink)
+	public.Post("/verification-approvals/:token", middleware.StrictRateLimitMiddleware(), h.VerificationApproval.SubmitDecisionLink)
+	public.Post("/a2a/verify", middleware.RateLimitMiddleware(), h.AgentCard.VerifyCard) // Verify an A2A Agent Card before delegating work
+	public.Post("/credentials/verify", middleware.RateLimitMiddleware(), h.Credential.VerifyCredential) // Verify an agent's verifiable credential
 
 	// Auth routes (no authentication required)
 	auth := v1.Group("/auth")
@@ -785,11 +1464,18 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	authProtected.Use(middleware.AuthMiddleware(jwtService)) // Apply middleware using Use() instead of inline
 	authProtected.Get("/me", h.Auth.Me)
 	authProtected.Post("/change-password", h.Auth.ChangePassword)
+	authProtected.Get("/sessions", h.Session.ListSessions)          // List signed-in devices
+	authProtected.Delete("/sessions/:id", h.Session.RevokeSession) // Sign a device out remotely
 
 	// Organization routes (authentication required)
 	organizations := v1.Group("/organizations")
 	organizations.Use(middleware.AuthMiddleware(jwtService))
+	organizations.Use(orgScope)
 	organizations.Get("/current", h.Auth.GetCurrentOrganization)
+	organizations.Get("/current/usage", h.Quota.GetUsage)
+	organizations.Get("/current/children", h.Organization.ListChildren)
+	organizations.Post("/current/children", middleware.AdminMiddleware(), h.Organization.CreateChild)
+	organizations.Get("/current/tree", h.Organization.GetTree)
 
 	// SDK routes (authentication required) - Download pre-configured SDK
 	sdk := v1.Group("/sdk")
@@ -810,7 +1496,7 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// Path: /api/v1/detection/agents/:id/report (instead of /api/v1/agents/:id/detection/report)
 	// ✅ FIX: Use JWT authentication for web UI access, API key for SDK programmatic access
 	detection := v1.Group("/detection")
-	detection.Use(middleware.Ed25519AgentMiddleware(services.Agent)) // ✅ Try Ed25519 first (for SDK agents)
+	detection.Use(middleware.Ed25519AgentMiddleware(services.Agent, services.WorkloadIdentity)) // ✅ Try Ed25519 or SPIFFE SVIDs first (for SDK agents)
 	detection.Use(middleware.AuthMiddleware(jwtService))             // ✅ Fallback to JWT (for web UI)
 	detection.Use(middleware.RateLimitMiddleware())
 	detection.Post("/agents/:id/report", h.Detection.ReportDetection)
@@ -821,8 +1507,9 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 
 	// Agents routes - All other agent endpoints with dual authentication (Ed25519 or JWT)
 	agents := v1.Group("/agents")
-	agents.Use(middleware.Ed25519AgentMiddleware(services.Agent)) // ✅ Try Ed25519 first (for SDK agents)
+	agents.Use(middleware.Ed25519AgentMiddleware(services.Agent, services.WorkloadIdentity)) // ✅ Try Ed25519 or SPIFFE SVIDs first (for SDK agents)
 	agents.Use(middleware.AuthMiddleware(jwtService))             // ✅ Fallback to JWT (for web UI)
+	agents.Use(orgScope)
 	agents.Use(middleware.RateLimitMiddleware())
 	agents.Get("/", h.Agent.ListAgents)
 	agents.Post("/", middleware.MemberMiddleware(), h.Agent.CreateAgent)
@@ -847,11 +1534,28 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	agents.Put("/:id/mcp-servers", middleware.MemberMiddleware(), h.Agent.AddMCPServersToAgent)                // Add MCP servers (bulk)
 	agents.Delete("/:id/mcp-servers/:mcp_id", middleware.MemberMiddleware(), h.Agent.RemoveMCPServerFromAgent) // Remove single MCP
 	agents.Post("/:id/mcp-servers/detect", middleware.MemberMiddleware(), h.Agent.DetectAndMapMCPServers)      // Auto-detect MCPs from config
+	// Revoke every attestation made by a compromised agent (confidence scores are recomputed immediately)
+	agents.Post("/:id/attestations/revoke", middleware.AdminMiddleware(), h.MCPAttestation.RevokeAgentAttestations)
 	// Trust Score management - RESTful endpoints under /agents/:id/trust-score/*
 	agents.Get("/:id/trust-score", h.Agent.GetAgentTrustScore)                                                      // Get current trust score
 	agents.Get("/:id/trust-score/history", h.Agent.GetAgentTrustScoreHistory)                                       // Get trust score history
 	agents.Put("/:id/trust-score", middleware.AdminMiddleware(), h.Agent.UpdateAgentTrustScore)                     // Manually update score (admin)
 	agents.Post("/:id/trust-score/recalculate", middleware.ManagerMiddleware(), h.Agent.RecalculateAgentTrustScore) // Recalculate score
+	// A2A Agent Card, DIDs and verifiable credentials
+	agents.Get("/:id/card", h.AgentCard.GetCard)                                             // Published A2A Agent Card
+	agents.Put("/:id/card", middleware.ManagerMiddleware(), h.AgentCard.PublishCard)         // Publish or update the A2A Agent Card
+	agents.Delete("/:id/card", middleware.ManagerMiddleware(), h.AgentCard.UnpublishCard)    // Revoke the A2A Agent Card
+	agents.Get("/:id/did", h.Credential.GetAgentDIDs)                                                                          // did:web, did:key and DID document
+	agents.Get("/:id/verifiable-credentials", h.Credential.ListCredentials)                                                    // Credentials issued to the agent
+	agents.Post("/:id/verifiable-credentials", middleware.ManagerMiddleware(), h.Credential.IssueCredential)                   // Issue a VC-JWT
+	agents.Delete("/:id/verifiable-credentials/:credentialId", middleware.ManagerMiddleware(), h.Credential.RevokeCredential) // Revoke via the status list
+	// SPIFFE workload identity
+	agents.Get("/:id/spiffe-bindings", h.WorkloadIdentity.ListBindings)                                                // SPIFFE ID patterns the agent authenticates as
+	agents.Post("/:id/spiffe-bindings", middleware.ManagerMiddleware(), h.WorkloadIdentity.CreateBinding)              // Bind a SPIFFE ID pattern
+	agents.Delete("/:id/spiffe-bindings/:bindingId", middleware.ManagerMiddleware(), h.WorkloadIdentity.DeleteBinding) // Remove a binding
+	agents.Get("/:id/certificate", h.WorkloadIdentity.AgentCertificate)                                                // Current X.509-SVID (the agent's certificateUrl)
+	agents.Post("/:id/certificate", h.WorkloadIdentity.IssueCertificate)                                               // Issue an X.509-SVID (the agent itself, or a manager)
+
 	// Agent security endpoints - Key vault and audit logs per agent
 	agents.Get("/:id/key-vault", h.Agent.GetAgentKeyVault)   // Get agent's key vault info (public key, expiration, rotation status)
 	agents.Get("/:id/audit-logs", h.Agent.GetAgentAuditLogs) // Get audit logs for specific agent (with pagination)
@@ -859,6 +1563,7 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// API keys routes (authentication required)
 	apiKeys := v1.Group("/api-keys")
 	apiKeys.Use(middleware.AuthMiddleware(jwtService))
+	apiKeys.Use(orgScope)
 	apiKeys.Use(middleware.RateLimitMiddleware())
 	apiKeys.Get("/", h.APIKey.ListAPIKeys)
 	apiKeys.Post("/", middleware.MemberMiddleware(), h.APIKey.CreateAPIKey)
@@ -868,6 +1573,7 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// Trust score routes (authentication required)
 	trust := v1.Group("/trust-score")
 	trust.Use(middleware.AuthMiddleware(jwtService))
+	trust.Use(orgScope)
 	trust.Post("/calculate/:id", middleware.ManagerMiddleware(), h.TrustScore.CalculateTrustScore)
 	trust.Get("/agents/:id", h.TrustScore.GetTrustScore)
 	trust.Get("/agents/:id/breakdown", h.TrustScore.GetTrustScoreBreakdown) // Detailed breakdown with weights and contributions
@@ -876,6 +1582,7 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// Admin routes (admin only)
 	admin := v1.Group("/admin")
 	admin.Use(middleware.AuthMiddleware(jwtService))
+	admin.Use(orgScope)
 	admin.Use(middleware.AdminMiddleware())
 	admin.Use(middleware.RateLimitMiddleware())
 
@@ -885,6 +1592,9 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	admin.Post("/users/:id/approve", h.Admin.ApproveUser)
 	admin.Post("/users/:id/reject", h.Admin.RejectUser)
 	admin.Put("/users/:id/role", h.Admin.UpdateUserRole)
+	admin.Put("/users/:id/organization", h.Organization.MoveUser) // Move within the admin's organization subtree
+	admin.Post("/agents/:id/tokens/revoke", h.OAuth.RevokeAgentTokens) // Revoke all OAuth access tokens of an agent
+	admin.Get("/signing-keys", h.SigningKey.ListSigningKeys)           // User JWT signing keys and their rotation state
 
 	// User lifecycle management (soft delete and hard delete)
 	admin.Post("/users/:id/deactivate", h.Admin.DeactivateUser) // Soft delete - sets deleted_at
@@ -900,6 +1610,26 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 
 	// Audit logs
 	admin.Get("/audit-logs", h.Admin.GetAuditLogs)
+	admin.Get("/audit-logs/verify", h.AuditChain.VerifyChain)
+	admin.Get("/audit-logs/checkpoints", h.AuditChain.GetCheckpoints)
+	admin.Post("/audit-logs/checkpoints", h.AuditChain.CreateCheckpoint)
+
+	// SIEM forwarding (admin only)
+	admin.Get("/siem-sinks", h.SIEMSink.ListSinks)
+	admin.Post("/siem-sinks", h.SIEMSink.CreateSink)
+	admin.Get("/siem-sinks/:id", h.SIEMSink.GetSink)
+	admin.Put("/siem-sinks/:id", h.SIEMSink.UpdateSink)
+	admin.Delete("/siem-sinks/:id", h.SIEMSink.DeleteSink)
+	admin.Get("/siem-sinks/:id/metrics", h.SIEMSink.GetSinkMetrics)
+	admin.Post("/siem-sinks/:id/test", h.SIEMSink.TestSink)
+
+	// Access review campaigns (admin only)
+	admin.Get("/access-reviews", h.AccessReview.ListCampaigns)
+	admin.Post("/access-reviews", h.AccessReview.CreateCampaign)
+	admin.Get("/access-reviews/:id", h.AccessReview.GetCampaign)
+	admin.Post("/access-reviews/:id/complete", h.AccessReview.CompleteCampaign)
+	admin.Post("/access-reviews/:id/cancel", h.AccessReview.CancelCampaign)
+	admin.Get("/access-reviews/:id/evidence", h.AccessReview.GetEvidence)
 
 	// Alerts
 	admin.Get("/alerts", h.Admin.GetAlerts)
@@ -910,6 +1640,11 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// Dashboard stats
 	admin.Get("/dashboard/stats", h.Admin.GetDashboardStats)
 
+	// Scheduled maintenance jobs (admin only)
+	admin.Get("/jobs", h.ScheduledJob.ListJobs)
+	admin.Get("/jobs/:name/runs", h.ScheduledJob.GetJobRuns)
+	admin.Post("/jobs/:name/trigger", h.ScheduledJob.TriggerJob)
+
 	// Security Policy Management routes (admin only)
 	admin.Get("/security-policies", h.SecurityPolicy.ListPolicies)
 	admin.Get("/security-policies/:id", h.SecurityPolicy.GetPolicy)
@@ -918,6 +1653,14 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	admin.Delete("/security-policies/:id", h.SecurityPolicy.DeletePolicy)
 	admin.Patch("/security-policies/:id/toggle", h.SecurityPolicy.TogglePolicy)
 
+	// Action risk catalog routes (admin only)
+	admin.Get("/action-catalog", h.ActionCatalog.ListActions)
+	admin.Post("/action-catalog", h.ActionCatalog.CreateAction)
+	admin.Post("/action-catalog/restore-defaults", h.ActionCatalog.RestoreDefaults)
+	admin.Get("/action-catalog/:id", h.ActionCatalog.GetAction)
+	admin.Put("/action-catalog/:id", h.ActionCatalog.UpdateAction)
+	admin.Delete("/action-catalog/:id", h.ActionCatalog.DeleteAction)
+
 	// Capability Request Management routes (admin only)
 	admin.Get("/capability-requests", h.CapabilityRequest.ListCapabilityRequests)
 	admin.Get("/capability-requests/:id", h.CapabilityRequest.GetCapabilityRequest)
@@ -928,6 +1671,7 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// Basic compliance features - Advanced features (SOC 2, HIPAA, GDPR, ISO 27001) reserved for premium
 	compliance := v1.Group("/compliance")
 	compliance.Use(middleware.AuthMiddleware(jwtService))
+	compliance.Use(orgScope)
 	compliance.Use(middleware.AdminMiddleware())
 	compliance.Use(middleware.RateLimitMiddleware()) // Changed from StrictRateLimitMiddleware to allow multiple simultaneous requests
 	compliance.Get("/status", h.Compliance.GetComplianceStatus)
@@ -936,6 +1680,16 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	compliance.Get("/access-review", h.Compliance.GetAccessReview)
 	compliance.Post("/check", h.Compliance.RunComplianceCheck)
 	compliance.Get("/export", h.Compliance.ExportComplianceReport) // Export compliance report
+	compliance.Get("/audit-log/export", h.Compliance.ExportAuditLog) // Stream audit log (CSV, NDJSON, Parquet)
+	compliance.Get("/frameworks", h.Compliance.ListFrameworks)
+	compliance.Get("/evidence-packs/:framework", h.Compliance.GetEvidencePack) // Evidence pack (JSON or signed zip)
+	compliance.Get("/retention", h.DataRetention.GetRetentionPolicies)
+	compliance.Post("/retention/purge", h.DataRetention.RunPurge) // Purge expired records now
+	compliance.Put("/retention/:table", h.DataRetention.SetRetentionRule)
+	compliance.Delete("/retention/:table", h.DataRetention.DeleteRetentionRule)
+	compliance.Get("/legal-holds", h.DataRetention.ListLegalHolds)
+	compliance.Post("/legal-holds", h.DataRetention.CreateLegalHold)
+	compliance.Post("/legal-holds/:id/release", h.DataRetention.ReleaseLegalHold)
 	// Data retention and violations endpoints removed
 
 	// MCP Server routes (authentication required)
@@ -943,7 +1697,7 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// CRITICAL: These MUST be registered BEFORE JWT-protected routes to avoid middleware conflicts
 	// These endpoints use Ed25519 authentication (agent-to-backend) instead of JWT (user-to-backend)
 	mcpServersAgentAuth := v1.Group("/mcp-servers")
-	mcpServersAgentAuth.Use(middleware.Ed25519AgentMiddleware(services.Agent)) // Ed25519 signature verification
+	mcpServersAgentAuth.Use(middleware.Ed25519AgentMiddleware(services.Agent, services.WorkloadIdentity)) // Ed25519 signature or SPIFFE SVID verification
 	mcpServersAgentAuth.Use(middleware.RateLimitMiddleware())
 	mcpServersAgentAuth.Post("/:id/attest", h.MCPAttestation.AttestMCP)                 // ✅ Submit agent attestation (Ed25519 signed)
 	mcpServersAgentAuth.Get("/:id/attestations", h.MCPAttestation.GetMCPAttestations)   // ✅ Get all attestations for this MCP
@@ -952,6 +1706,7 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// Standard MCP Server management endpoints - Use JWT authentication (user-to-backend)
 	mcpServers := v1.Group("/mcp-servers")
 	mcpServers.Use(middleware.AuthMiddleware(jwtService))
+	mcpServers.Use(orgScope)
 	mcpServers.Use(middleware.RateLimitMiddleware())
 	mcpServers.Get("/", h.MCP.ListMCPServers)
 	mcpServers.Post("/", middleware.MemberMiddleware(), h.MCP.CreateMCPServer)
@@ -963,31 +1718,47 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	mcpServers.Get("/:id/verification-status", h.MCP.GetVerificationStatus)
 	mcpServers.Get("/:id/capabilities", h.MCP.GetMCPServerCapabilities)        // ✅ Get detected capabilities
 	mcpServers.Get("/:id/verification-events", h.MCP.GetMCPVerificationEvents) // ✅ Get verification events for MCP server
+	// Tool-surface change detection - hashed snapshots of tools/descriptions/schemas (rug-pull protection)
+	mcpServers.Get("/:id/tool-surface", h.MCPToolSurface.ListToolSurfaceSnapshots)
+	mcpServers.Post("/:id/tool-surface/approve", middleware.AdminMiddleware(), h.MCPToolSurface.ApproveToolSurface)
+	mcpServers.Post("/:id/tool-surface/reject", middleware.AdminMiddleware(), h.MCPToolSurface.RejectToolSurface)
+	// Scheduled re-verification - health status, check history and uptime
+	mcpServers.Get("/:id/health", h.MCPHealth.GetMCPServerHealth)
+	mcpServers.Post("/:id/health/check", middleware.ManagerMiddleware(), h.MCPHealth.CheckMCPServerHealth)
+	// Attestation revocation - recomputes the MCP server's confidence score immediately
+	mcpServers.Post("/:id/attestations/:attestationId/revoke", middleware.AdminMiddleware(), h.MCPAttestation.RevokeAttestation)
 	// Runtime verification endpoint - CORE functionality
 	mcpServers.Post("/:id/verify-action", h.MCP.VerifyMCPAction)
 
 	// Security routes (admin/manager)
 	security := v1.Group("/security")
 	security.Use(middleware.AuthMiddleware(jwtService))
+	security.Use(orgScope)
 	security.Use(middleware.ManagerMiddleware())
 	security.Use(middleware.RateLimitMiddleware())
 	security.Get("/threats", h.Security.GetThreats)
 	security.Get("/anomalies", h.Security.GetAnomalies)
 	security.Get("/metrics", h.Security.GetSecurityMetrics)
+	security.Get("/agents/:id/baseline", h.Security.GetAgentBaseline)
+	security.Delete("/agents/:id/baseline", h.Security.ResetAgentBaseline) // Relearn from scratch
+	security.Get("/agents/:id/data-usage", h.DataTransfer.GetAgentDataUsage)
 
 	// Analytics routes (authentication required)
 	analytics := v1.Group("/analytics")
 	analytics.Use(middleware.AuthMiddleware(jwtService))
+	analytics.Use(orgScope)
 	analytics.Use(middleware.RateLimitMiddleware())
 	analytics.Get("/dashboard", h.Analytics.GetDashboardStats) // Viewer-accessible dashboard stats
 	analytics.Get("/usage", h.Analytics.GetUsageStatistics)
 	analytics.Get("/trends", h.Analytics.GetTrustScoreTrends)
 	analytics.Get("/verification-activity", h.Analytics.GetVerificationActivity) // New endpoint for chart
 	analytics.Get("/agents/activity", h.Analytics.GetAgentActivity)
+	analytics.Get("/rollup", h.Organization.GetRollup) // Rolled up across descendant organizations
 
 	// Webhook routes (authentication required)
 	webhooks := v1.Group("/webhooks")
 	webhooks.Use(middleware.AuthMiddleware(jwtService))
+	webhooks.Use(orgScope)
 	webhooks.Use(middleware.RateLimitMiddleware())
 	webhooks.Post("/", middleware.MemberMiddleware(), h.Webhook.CreateWebhook)
 	webhooks.Get("/", h.Webhook.ListWebhooks)
@@ -999,6 +1770,7 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// Verification routes (authentication required) - Agent action verification
 	verifications := v1.Group("/verifications")
 	verifications.Use(middleware.AuthMiddleware(jwtService))
+	verifications.Use(orgScope)
 	verifications.Use(middleware.RateLimitMiddleware())
 	verifications.Post("/", h.Verification.CreateVerification)                 // Request verification for agent action
 	verifications.Get("/:id", h.Verification.GetVerification)                  // Get verification status by ID
@@ -1007,6 +1779,7 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// Verification Event routes (authentication required) - Real-time monitoring
 	verificationEvents := v1.Group("/verification-events")
 	verificationEvents.Use(middleware.AuthMiddleware(jwtService))
+	verificationEvents.Use(orgScope)
 	verificationEvents.Use(middleware.RateLimitMiddleware())
 	verificationEvents.Get("/", h.VerificationEvent.ListVerificationEvents)
 	verificationEvents.Get("/recent", h.VerificationEvent.GetRecentEvents)
@@ -1021,6 +1794,7 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// Tag routes (authentication required)
 	tags := v1.Group("/tags")
 	tags.Use(middleware.AuthMiddleware(jwtService))
+	tags.Use(orgScope)
 	tags.Use(middleware.RateLimitMiddleware())
 	tags.Get("/", h.Tag.GetTags)
 	tags.Post("/", middleware.MemberMiddleware(), h.Tag.CreateTag)
@@ -1046,11 +1820,31 @@ func setupRoutes(v1 fiber.Router, h *Handlers, services *Services, jwtService *a
 	// Capabilities routes (authentication required) - List all available capability types
 	capabilities := v1.Group("/capabilities")
 	capabilities.Use(middleware.AuthMiddleware(jwtService))
+	capabilities.Use(orgScope)
 	capabilities.Get("/", h.Capability.ListCapabilities)
 
+	// Human approval queue for high-risk agent actions (authentication required)
+	verificationApprovals := v1.Group("/verification-approvals")
+	verificationApprovals.Use(middleware.AuthMiddleware(jwtService))
+	verificationApprovals.Use(orgScope)
+	verificationApprovals.Use(middleware.RateLimitMiddleware())
+	verificationApprovals.Get("/", h.VerificationApproval.ListApprovals)
+	verificationApprovals.Get("/:id", h.VerificationApproval.GetApproval)
+	verificationApprovals.Post("/:id/approve", middleware.ManagerMiddleware(), h.VerificationApproval.ApproveVerification)
+	verificationApprovals.Post("/:id/deny", middleware.ManagerMiddleware(), h.VerificationApproval.DenyVerification)
+
+	// Access review routes for assigned reviewers (authentication required)
+	accessReviews := v1.Group("/access-reviews")
+	accessReviews.Use(middleware.AuthMiddleware(jwtService))
+	accessReviews.Use(orgScope)
+	accessReviews.Use(middleware.RateLimitMiddleware())
+	accessReviews.Get("/my-items", h.AccessReview.ListMyItems)
+	accessReviews.Post("/items/:id/decision", h.AccessReview.DecideItem)
+
 	// Capability Request routes (authentication required)
 	capabilityRequests := v1.Group("/capability-requests")
 	capabilityRequests.Use(middleware.AuthMiddleware(jwtService))
+	capabilityRequests.Use(orgScope)
 	capabilityRequests.Use(middleware.RateLimitMiddleware())
 
 	// MCP server tag routes (under /mcp-servers/:id/tags)
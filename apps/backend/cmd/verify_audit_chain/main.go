@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/repository"
+)
+
+// Verify the hash-chained audit log of one or all organizations.
+// Exits with status 1 when any chain is broken.
+func main() {
+	var (
+		databaseURL string
+		orgFlag     string
+		publicKeys  string
+		jsonOutput  bool
+	)
+	flag.StringVar(&databaseURL, "database-url", os.Getenv("DATABASE_URL"), "PostgreSQL connection URL")
+	flag.StringVar(&orgFlag, "org", "", "Organization ID to verify (default: all organizations)")
+	flag.StringVar(&publicKeys, "public-keys", os.Getenv("AUDIT_TRUSTED_PUBLIC_KEYS"), "Comma-separated base64 Ed25519 public keys trusted for checkpoints")
+	flag.BoolVar(&jsonOutput, "json", false, "Print results as JSON")
+	flag.Parse()
+
+	if databaseURL == "" {
+		log.Fatal("❌ DATABASE_URL environment variable or -database-url is required")
+	}
+
+	trustedKeys, err := application.ParseAuditPublicKeys(strings.Split(publicKeys, ","))
+	if err != nil {
+		log.Fatalf("❌ Invalid -public-keys: %v", err)
+	}
+	// The server's current signing key is trusted when available
+	if signingKey := os.Getenv("AUDIT_SIGNING_KEY"); signingKey != "" {
+		privateKey, err := application.LoadAuditSigningKey(signingKey)
+		if err != nil {
+			log.Fatalf("❌ Invalid AUDIT_SIGNING_KEY: %v", err)
+		}
+		trustedKeys = append(trustedKeys, privateKey.Public().(ed25519.PublicKey))
+	}
+	if len(trustedKeys) == 0 {
+		log.Println("⚠️  No trusted public keys configured; every checkpoint will be reported as untrusted")
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("❌ Failed to ping database: %v", err)
+	}
+
+	auditRepo := repository.NewAuditLogRepository(db)
+	service := application.NewAuditChainService(auditRepo, nil, trustedKeys)
+
+	var orgIDs []uuid.UUID
+	if orgFlag != "" {
+		orgID, err := uuid.Parse(orgFlag)
+		if err != nil {
+			log.Fatalf("❌ Invalid -org: %v", err)
+		}
+		orgIDs = append(orgIDs, orgID)
+	} else {
+		heads, err := auditRepo.ListChainHeads()
+		if err != nil {
+			log.Fatalf("❌ Failed to list audit chains: %v", err)
+		}
+		for _, head := range heads {
+			orgIDs = append(orgIDs, head.OrganizationID)
+		}
+	}
+
+	ctx := context.Background()
+	results := make([]*domain.AuditChainVerification, 0, len(orgIDs))
+	broken := 0
+	for _, orgID := range orgIDs {
+		result, err := service.VerifyChain(ctx, orgID)
+		if err != nil {
+			log.Fatalf("❌ Failed to verify audit chain for organization %s: %v", orgID, err)
+		}
+		if !result.Valid {
+			broken++
+		}
+		results = append(results, result)
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			log.Fatalf("❌ Failed to encode results: %v", err)
+		}
+	} else {
+		for _, result := range results {
+			printResult(result)
+		}
+		fmt.Printf("\n%d organizations verified, %d broken\n", len(results), broken)
+	}
+
+	if broken > 0 {
+		os.Exit(1)
+	}
+}
+
+func printResult(result *domain.AuditChainVerification) {
+	if result.Valid {
+		fmt.Printf("✅ %s: %d entries, %d checkpoints verified\n",
+			result.OrganizationID, result.EntriesVerified, result.CheckpointsVerified)
+		return
+	}
+
+	brk := result.FirstBrokenLink
+	fmt.Printf("🚨 %s: chain broken at sequence %d (%s)\n", result.OrganizationID, brk.SequenceNumber, brk.Reason)
+	if brk.EntryID != nil {
+		fmt.Printf("   entry:    %s\n", brk.EntryID)
+	}
+	if brk.Expected != "" {
+		fmt.Printf("   expected: %s\n", brk.Expected)
+	}
+	if brk.Actual != "" {
+		fmt.Printf("   actual:   %s\n", brk.Actual)
+	}
+	fmt.Printf("   %d entries verified before the break\n", result.EntriesVerified)
+}
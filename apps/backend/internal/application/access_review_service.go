@@ -0,0 +1,688 @@
+package application
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// DefaultAccessReviewReminderDays is how long before the due date reviewers start getting reminders
+const DefaultAccessReviewReminderDays = 3
+
+// accessReviewReminderInterval throttles reminders to one per reviewer and campaign per day
+// (slightly under 24h so an hourly job does not drift past the daily slot)
+const accessReviewReminderInterval = 23 * time.Hour
+
+// capabilityRevoker revokes agent capability grants (CapabilityService)
+type capabilityRevoker interface {
+	RevokeCapability(ctx context.Context, capabilityID uuid.UUID, revokedBy *uuid.UUID) error
+}
+
+// apiKeyRevoker revokes API keys (APIKeyService)
+type apiKeyRevoker interface {
+	RevokeAPIKey(ctx context.Context, keyID, orgID uuid.UUID) error
+}
+
+// AccessReviewCampaignRequest is the input for starting a campaign
+type AccessReviewCampaignRequest struct {
+	Name               string                        `json:"name"`
+	Description        string                        `json:"description"`
+	Scope              []domain.AccessReviewItemType `json:"scope"`       // Defaults to every item type
+	ReviewerID         *uuid.UUID                    `json:"reviewer_id"` // Defaults to the campaign creator
+	AssignAgentOwners  bool                          `json:"assign_agent_owners"`
+	AutoRevokeOnExpiry bool                          `json:"auto_revoke_on_expiry"`
+	ReminderDaysBefore *int                          `json:"reminder_days_before"`
+	DueAt              time.Time                     `json:"due_at"`
+}
+
+// AccessReviewSnapshot is the content sealed as evidence when a campaign completes
+type AccessReviewSnapshot struct {
+	Campaign *domain.AccessReviewCampaign `json:"campaign"`
+	Items    []*domain.AccessReviewItem   `json:"items"`
+	SealedAt time.Time                    `json:"sealed_at"`
+}
+
+// AccessReviewService runs access review campaigns: reviewers certify or revoke every user role,
+// agent capability grant and API key in scope, revocations are applied immediately, and a
+// completed campaign is sealed as signed, immutable evidence
+type AccessReviewService struct {
+	reviewRepo     domain.AccessReviewRepository
+	userRepo       domain.UserRepository
+	agentRepo      domain.AgentRepository
+	capabilityRepo domain.CapabilityRepository
+	apiKeyRepo     domain.APIKeyRepository
+	capabilities   capabilityRevoker
+	apiKeys        apiKeyRevoker
+	emailService   domain.EmailService
+	signingKey     ed25519.PrivateKey
+	now            func() time.Time
+}
+
+// NewAccessReviewService creates a new access review service. emailService and signingKey
+// may be nil (no reminders, unsigned evidence).
+func NewAccessReviewService(
+	reviewRepo domain.AccessReviewRepository,
+	userRepo domain.UserRepository,
+	agentRepo domain.AgentRepository,
+	capabilityRepo domain.CapabilityRepository,
+	apiKeyRepo domain.APIKeyRepository,
+	capabilities capabilityRevoker,
+	apiKeys apiKeyRevoker,
+	emailService domain.EmailService,
+	signingKey ed25519.PrivateKey,
+) *AccessReviewService {
+	return &AccessReviewService{
+		reviewRepo:     reviewRepo,
+		userRepo:       userRepo,
+		agentRepo:      agentRepo,
+		capabilityRepo: capabilityRepo,
+		apiKeyRepo:     apiKeyRepo,
+		capabilities:   capabilities,
+		apiKeys:        apiKeys,
+		emailService:   emailService,
+		signingKey:     signingKey,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// CreateCampaign snapshots the access in scope and assigns every item to a reviewer
+func (s *AccessReviewService) CreateCampaign(
+	ctx context.Context,
+	orgID, createdBy uuid.UUID,
+	req AccessReviewCampaignRequest,
+) (*domain.AccessReviewCampaign, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("invalid access review: name is required")
+	}
+	if !req.DueAt.After(s.now()) {
+		return nil, fmt.Errorf("invalid access review: due_at must be in the future")
+	}
+
+	scope := req.Scope
+	if len(scope) == 0 {
+		scope = []domain.AccessReviewItemType{
+			domain.AccessReviewItemUserRole,
+			domain.AccessReviewItemCapabilityGrant,
+			domain.AccessReviewItemAPIKey,
+		}
+	}
+	inScope := map[domain.AccessReviewItemType]bool{}
+	for _, itemType := range scope {
+		switch itemType {
+		case domain.AccessReviewItemUserRole, domain.AccessReviewItemCapabilityGrant, domain.AccessReviewItemAPIKey:
+			inScope[itemType] = true
+		default:
+			return nil, fmt.Errorf("invalid access review: unknown scope %q", itemType)
+		}
+	}
+
+	reminderDays := DefaultAccessReviewReminderDays
+	if req.ReminderDaysBefore != nil {
+		if *req.ReminderDaysBefore < 0 {
+			return nil, fmt.Errorf("invalid access review: reminder_days_before must not be negative")
+		}
+		reminderDays = *req.ReminderDaysBefore
+	}
+
+	users, err := s.userRepo.GetByOrganization(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+	activeUsers := map[uuid.UUID]*domain.User{}
+	for _, user := range users {
+		if user.Status == domain.UserStatusActive {
+			activeUsers[user.ID] = user
+		}
+	}
+
+	reviewerID := createdBy
+	if req.ReviewerID != nil {
+		reviewerID = *req.ReviewerID
+	}
+	reviewer, ok := activeUsers[reviewerID]
+	if !ok || (reviewer.Role != domain.RoleAdmin && reviewer.Role != domain.RoleManager) {
+		return nil, fmt.Errorf("invalid access review: reviewer must be an active admin or manager of the organization")
+	}
+
+	campaign := &domain.AccessReviewCampaign{
+		ID:                 uuid.New(),
+		OrganizationID:     orgID,
+		Name:               req.Name,
+		Description:        req.Description,
+		Scope:              scope,
+		DefaultReviewerID:  reviewerID,
+		AssignAgentOwners:  req.AssignAgentOwners,
+		AutoRevokeOnExpiry: req.AutoRevokeOnExpiry,
+		ReminderDaysBefore: reminderDays,
+		DueAt:              req.DueAt.UTC(),
+		Status:             domain.AccessReviewCampaignActive,
+		CreatedBy:          createdBy,
+	}
+
+	items := []*domain.AccessReviewItem{}
+
+	if inScope[domain.AccessReviewItemUserRole] {
+		for _, user := range users {
+			if user.Status != domain.UserStatusActive {
+				continue
+			}
+			items = append(items, &domain.AccessReviewItem{
+				ItemType:   domain.AccessReviewItemUserRole,
+				ResourceID: user.ID,
+				Subject:    user.Email,
+				Access:     string(user.Role),
+				ReviewerID: independentReviewer(user.ID, reviewerID, createdBy, users),
+				Decision:   domain.AccessReviewDecisionPending,
+			})
+		}
+	}
+
+	if inScope[domain.AccessReviewItemCapabilityGrant] || inScope[domain.AccessReviewItemAPIKey] {
+		agents, err := s.agentRepo.GetByOrganization(orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load agents: %w", err)
+		}
+
+		agentsByID := map[uuid.UUID]*domain.Agent{}
+		for _, agent := range agents {
+			agentsByID[agent.ID] = agent
+		}
+
+		// Agent owners review their own agents' access when requested and still active
+		agentReviewer := func(agentID uuid.UUID) uuid.UUID {
+			if agent, ok := agentsByID[agentID]; ok && req.AssignAgentOwners {
+				if _, active := activeUsers[agent.CreatedBy]; active {
+					return agent.CreatedBy
+				}
+			}
+			return reviewerID
+		}
+
+		if inScope[domain.AccessReviewItemCapabilityGrant] {
+			for _, agent := range agents {
+				if agent.Status == domain.AgentStatusRevoked {
+					continue
+				}
+				grants, err := s.capabilityRepo.GetActiveCapabilitiesByAgentID(agent.ID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load capabilities of agent %s: %w", agent.ID, err)
+				}
+				for _, grant := range grants {
+					agentID := agent.ID
+					items = append(items, &domain.AccessReviewItem{
+						ItemType:   domain.AccessReviewItemCapabilityGrant,
+						ResourceID: grant.ID,
+						AgentID:    &agentID,
+						Subject:    agent.Name,
+						Access:     grant.CapabilityType,
+						ReviewerID: agentReviewer(agent.ID),
+						Decision:   domain.AccessReviewDecisionPending,
+					})
+				}
+			}
+		}
+
+		if inScope[domain.AccessReviewItemAPIKey] {
+			keys, err := s.apiKeyRepo.GetByOrganization(orgID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load API keys: %w", err)
+			}
+			for _, key := range keys {
+				if !key.IsActive {
+					continue
+				}
+				agentID := key.AgentID
+				subject := agentID.String()
+				if agent, ok := agentsByID[agentID]; ok {
+					subject = agent.Name
+				}
+				items = append(items, &domain.AccessReviewItem{
+					ItemType:   domain.AccessReviewItemAPIKey,
+					ResourceID: key.ID,
+					AgentID:    &agentID,
+					Subject:    subject,
+					Access:     fmt.Sprintf("%s (%s…)", key.Name, key.Prefix),
+					ReviewerID: agentReviewer(agentID),
+					Decision:   domain.AccessReviewDecisionPending,
+				})
+			}
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("invalid access review: no access in scope")
+	}
+
+	if err := s.reviewRepo.CreateCampaign(campaign, items); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("✅ Access review campaign %q started with %d items (org %s)\n", campaign.Name, len(items), orgID)
+
+	return s.reviewRepo.GetCampaign(campaign.ID)
+}
+
+// independentReviewer keeps users from certifying their own role: their item goes to the
+// campaign creator or, failing that, another active admin
+func independentReviewer(subjectID, reviewerID, createdBy uuid.UUID, users []*domain.User) uuid.UUID {
+	if subjectID != reviewerID {
+		return reviewerID
+	}
+	if createdBy != subjectID {
+		return createdBy
+	}
+	for _, user := range users {
+		if user.ID != subjectID && user.Status == domain.UserStatusActive && user.Role == domain.RoleAdmin {
+			return user.ID
+		}
+	}
+	return reviewerID // Single-admin organization; the self-certification is visible in the evidence
+}
+
+// ListCampaigns lists an organization's campaigns, newest first
+func (s *AccessReviewService) ListCampaigns(
+	ctx context.Context,
+	orgID uuid.UUID,
+	status *domain.AccessReviewCampaignStatus,
+	limit, offset int,
+) ([]*domain.AccessReviewCampaign, int, error) {
+	return s.reviewRepo.ListCampaigns(orgID, status, limit, offset)
+}
+
+// GetCampaign returns a campaign of the organization
+func (s *AccessReviewService) GetCampaign(ctx context.Context, orgID, campaignID uuid.UUID) (*domain.AccessReviewCampaign, error) {
+	campaign, err := s.reviewRepo.GetCampaign(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.OrganizationID != orgID {
+		return nil, fmt.Errorf("access review campaign not found")
+	}
+	return campaign, nil
+}
+
+// GetCampaignItems returns the items of a campaign, optionally narrowed by reviewer and decision
+func (s *AccessReviewService) GetCampaignItems(
+	ctx context.Context,
+	orgID, campaignID uuid.UUID,
+	reviewerID *uuid.UUID,
+	decision *domain.AccessReviewDecision,
+) ([]*domain.AccessReviewItem, error) {
+	if _, err := s.GetCampaign(ctx, orgID, campaignID); err != nil {
+		return nil, err
+	}
+	return s.reviewRepo.ListItems(domain.AccessReviewItemFilter{
+		CampaignID: &campaignID,
+		ReviewerID: reviewerID,
+		Decision:   decision,
+	})
+}
+
+// ListPendingItemsForReviewer returns the undecided items assigned to a reviewer in active campaigns
+func (s *AccessReviewService) ListPendingItemsForReviewer(ctx context.Context, orgID, reviewerID uuid.UUID) ([]*domain.AccessReviewItem, error) {
+	pending := domain.AccessReviewDecisionPending
+	items, err := s.reviewRepo.ListItems(domain.AccessReviewItemFilter{
+		ReviewerID: &reviewerID,
+		Decision:   &pending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*domain.AccessReviewItem{}
+	for _, item := range items {
+		if item.OrganizationID == orgID {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// DecideItem certifies or revokes an item. Only the assigned reviewer (or an admin) may decide,
+// and nobody may decide their own user role. Revocations are applied before the decision is
+// recorded, so a failed revocation leaves the item pending. When the last item is decided the
+// campaign is completed and sealed.
+func (s *AccessReviewService) DecideItem(
+	ctx context.Context,
+	orgID, itemID, userID uuid.UUID,
+	isAdmin bool,
+	decision domain.AccessReviewDecision,
+	justification string,
+) (*domain.AccessReviewItem, error) {
+	if decision != domain.AccessReviewDecisionCertified && decision != domain.AccessReviewDecisionRevoked {
+		return nil, fmt.Errorf("invalid decision: must be certified or revoked")
+	}
+
+	item, err := s.reviewRepo.GetItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.OrganizationID != orgID {
+		return nil, fmt.Errorf("access review item not found")
+	}
+	if item.ReviewerID != userID && !isAdmin {
+		return nil, fmt.Errorf("not the assigned reviewer")
+	}
+	if item.ItemType == domain.AccessReviewItemUserRole && item.ResourceID == userID {
+		return nil, fmt.Errorf("reviewers cannot decide on their own access")
+	}
+	if item.Decision != domain.AccessReviewDecisionPending {
+		return nil, fmt.Errorf("access review item already decided")
+	}
+
+	campaign, err := s.reviewRepo.GetCampaign(item.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != domain.AccessReviewCampaignActive {
+		return nil, fmt.Errorf("access review campaign is not active")
+	}
+
+	if decision == domain.AccessReviewDecisionRevoked {
+		if err := s.revoke(ctx, item, &userID); err != nil {
+			return nil, fmt.Errorf("failed to revoke access: %w", err)
+		}
+	}
+
+	decidedAt := s.now()
+	item.Decision = decision
+	item.DecidedBy = &userID
+	item.DecidedAt = &decidedAt
+	item.Justification = strings.TrimSpace(justification)
+	if err := s.reviewRepo.DecideItem(item); err != nil {
+		return nil, err
+	}
+
+	if err := s.completeIfDecided(ctx, item.CampaignID); err != nil {
+		fmt.Printf("⚠️  Failed to complete access review campaign %s: %v\n", item.CampaignID, err)
+	}
+
+	return item, nil
+}
+
+// revoke removes the reviewed access. Capability grants and API keys are revoked through their
+// services; a revoked user role drops the user to viewer, and a revoked viewer role suspends the user.
+func (s *AccessReviewService) revoke(ctx context.Context, item *domain.AccessReviewItem, revokedBy *uuid.UUID) error {
+	switch item.ItemType {
+	case domain.AccessReviewItemCapabilityGrant:
+		return s.capabilities.RevokeCapability(ctx, item.ResourceID, revokedBy)
+
+	case domain.AccessReviewItemAPIKey:
+		return s.apiKeys.RevokeAPIKey(ctx, item.ResourceID, item.OrganizationID)
+
+	case domain.AccessReviewItemUserRole:
+		user, err := s.userRepo.GetByID(item.ResourceID)
+		if err != nil {
+			return err
+		}
+		if user.OrganizationID != item.OrganizationID {
+			return fmt.Errorf("user does not belong to organization")
+		}
+		if user.Role != domain.RoleViewer {
+			return s.userRepo.UpdateRole(user.ID, domain.RoleViewer)
+		}
+		user.Status = domain.UserStatusSuspended
+		return s.userRepo.Update(user)
+	}
+
+	return fmt.Errorf("unknown access review item type %q", item.ItemType)
+}
+
+// CompleteCampaign completes a campaign once every item is decided
+func (s *AccessReviewService) CompleteCampaign(ctx context.Context, orgID, campaignID uuid.UUID) (*domain.AccessReviewCampaign, error) {
+	campaign, err := s.GetCampaign(ctx, orgID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != domain.AccessReviewCampaignActive {
+		return nil, fmt.Errorf("access review campaign is not active")
+	}
+	if campaign.PendingItems > 0 {
+		return nil, fmt.Errorf("access review campaign has %d pending items", campaign.PendingItems)
+	}
+
+	if err := s.seal(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return s.reviewRepo.GetCampaign(campaignID)
+}
+
+// CancelCampaign stops an active campaign; decisions already made stay applied
+func (s *AccessReviewService) CancelCampaign(ctx context.Context, orgID, campaignID uuid.UUID) error {
+	if _, err := s.GetCampaign(ctx, orgID, campaignID); err != nil {
+		return err
+	}
+	return s.reviewRepo.UpdateCampaignStatus(campaignID, domain.AccessReviewCampaignCancelled, nil)
+}
+
+// GetEvidence returns the sealed evidence of a completed campaign and whether its hash and
+// signature still verify
+func (s *AccessReviewService) GetEvidence(ctx context.Context, orgID, campaignID uuid.UUID) (*domain.AccessReviewEvidence, bool, error) {
+	if _, err := s.GetCampaign(ctx, orgID, campaignID); err != nil {
+		return nil, false, err
+	}
+
+	evidence, err := s.reviewRepo.GetEvidence(campaignID)
+	if err != nil {
+		return nil, false, err
+	}
+	if evidence == nil {
+		return nil, false, fmt.Errorf("access review evidence not found")
+	}
+
+	return evidence, s.verifyEvidence(evidence), nil
+}
+
+func (s *AccessReviewService) verifyEvidence(evidence *domain.AccessReviewEvidence) bool {
+	sum := sha256.Sum256(evidence.Snapshot)
+	if hex.EncodeToString(sum[:]) != evidence.SHA256 {
+		return false
+	}
+	if evidence.Signature == "" || s.signingKey == nil {
+		return evidence.Signature == ""
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(evidence.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(s.signingKey.Public().(ed25519.PublicKey), evidence.Snapshot, signature)
+}
+
+func (s *AccessReviewService) completeIfDecided(ctx context.Context, campaignID uuid.UUID) error {
+	campaign, err := s.reviewRepo.GetCampaign(campaignID)
+	if err != nil {
+		return err
+	}
+	if campaign.Status != domain.AccessReviewCampaignActive || campaign.PendingItems > 0 {
+		return nil
+	}
+	return s.seal(ctx, campaign)
+}
+
+// seal stores the campaign and all decisions as signed evidence and marks the campaign completed.
+// The evidence row is written first; it cannot be changed afterwards.
+func (s *AccessReviewService) seal(ctx context.Context, campaign *domain.AccessReviewCampaign) error {
+	existing, err := s.reviewRepo.GetEvidence(campaign.ID)
+	if err != nil {
+		return err
+	}
+
+	completedAt := s.now()
+	if existing == nil {
+		items, err := s.reviewRepo.ListItems(domain.AccessReviewItemFilter{CampaignID: &campaign.ID})
+		if err != nil {
+			return err
+		}
+
+		sealed := *campaign
+		sealed.Status = domain.AccessReviewCampaignCompleted
+		sealed.CompletedAt = &completedAt
+
+		snapshot, err := json.Marshal(AccessReviewSnapshot{
+			Campaign: &sealed,
+			Items:    items,
+			SealedAt: completedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal access review evidence: %w", err)
+		}
+
+		sum := sha256.Sum256(snapshot)
+		evidence := &domain.AccessReviewEvidence{
+			CampaignID:     campaign.ID,
+			OrganizationID: campaign.OrganizationID,
+			Snapshot:       snapshot,
+			SHA256:         hex.EncodeToString(sum[:]),
+		}
+		if s.signingKey != nil {
+			evidence.KeyID = AuditSigningKeyID(s.signingKey.Public().(ed25519.PublicKey))
+			evidence.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(s.signingKey, snapshot))
+		}
+
+		if err := s.reviewRepo.CreateEvidence(evidence); err != nil {
+			return err
+		}
+	}
+
+	if err := s.reviewRepo.UpdateCampaignStatus(campaign.ID, domain.AccessReviewCampaignCompleted, &completedAt); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Access review campaign %q completed and sealed (org %s)\n", campaign.Name, campaign.OrganizationID)
+	return nil
+}
+
+// ProcessCampaigns is the scheduled sweep over active campaigns: it reminds reviewers with
+// pending items from ReminderDaysBefore days before the due date, and at the due date revokes
+// still-pending items of auto-revoking campaigns and seals them. Campaigns without auto-revoke
+// stay open (and keep reminding) until their reviewers finish.
+func (s *AccessReviewService) ProcessCampaigns(ctx context.Context) error {
+	campaigns, err := s.reviewRepo.ListActiveCampaigns()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, campaign := range campaigns {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.processCampaign(ctx, campaign); err != nil {
+			fmt.Printf("⚠️  Failed to process access review campaign %s: %v\n", campaign.ID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to process %d of %d access review campaigns", failed, len(campaigns))
+	}
+	return nil
+}
+
+func (s *AccessReviewService) processCampaign(ctx context.Context, campaign *domain.AccessReviewCampaign) error {
+	now := s.now()
+
+	if campaign.PendingItems == 0 {
+		return s.seal(ctx, campaign)
+	}
+
+	pending := domain.AccessReviewDecisionPending
+	items, err := s.reviewRepo.ListItems(domain.AccessReviewItemFilter{CampaignID: &campaign.ID, Decision: &pending})
+	if err != nil {
+		return err
+	}
+
+	if !now.Before(campaign.DueAt) && campaign.AutoRevokeOnExpiry {
+		for _, item := range items {
+			decidedAt := now
+			item.Decision = domain.AccessReviewDecisionRevoked
+			item.DecidedAt = &decidedAt
+			item.Justification = "Not reviewed before the due date"
+			if err := s.revoke(ctx, item, nil); err != nil {
+				item.RevocationError = err.Error()
+				fmt.Printf("⚠️  Failed to auto-revoke %s %s: %v\n", item.ItemType, item.ResourceID, err)
+			}
+			if err := s.reviewRepo.DecideItem(item); err != nil && err.Error() != "access review item already decided" {
+				return err
+			}
+		}
+		return s.completeIfDecided(ctx, campaign.ID)
+	}
+
+	if now.Before(campaign.DueAt.AddDate(0, 0, -campaign.ReminderDaysBefore)) {
+		return nil
+	}
+	return s.sendReminders(campaign, items, now)
+}
+
+// sendReminders emails each reviewer with pending items at most once per reminder interval
+func (s *AccessReviewService) sendReminders(campaign *domain.AccessReviewCampaign, items []*domain.AccessReviewItem, now time.Time) error {
+	if s.emailService == nil {
+		return nil
+	}
+
+	byReviewer := map[uuid.UUID][]*domain.AccessReviewItem{}
+	for _, item := range items {
+		if item.LastRemindedAt != nil && now.Sub(*item.LastRemindedAt) < accessReviewReminderInterval {
+			continue
+		}
+		byReviewer[item.ReviewerID] = append(byReviewer[item.ReviewerID], item)
+	}
+
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:3000"
+	}
+
+	for reviewerID, reviewerItems := range byReviewer {
+		reviewer, err := s.userRepo.GetByID(reviewerID)
+		if err != nil {
+			fmt.Printf("⚠️  Access review reminder skipped, reviewer %s not found: %v\n", reviewerID, err)
+			continue
+		}
+
+		name := reviewer.Name
+		if name == "" {
+			name = reviewer.Email
+		}
+		templateData := domain.EmailTemplateData{
+			UserName:     name,
+			UserEmail:    reviewer.Email,
+			DashboardURL: frontendURL + "/dashboard/access-reviews/" + campaign.ID.String(),
+			Timestamp:    now,
+			CustomData: map[string]interface{}{
+				"CampaignName": campaign.Name,
+				"PendingItems": len(reviewerItems),
+				"DueAt":        campaign.DueAt.Format("January 2, 2006 15:04 MST"),
+				"Overdue":      !now.Before(campaign.DueAt),
+			},
+		}
+
+		if err := s.emailService.SendTemplatedEmail(domain.TemplateAccessReviewReminder, reviewer.Email, templateData); err != nil {
+			fmt.Printf("⚠️  Failed to send access review reminder to %s: %v\n", reviewer.Email, err)
+			continue
+		}
+
+		ids := make([]uuid.UUID, len(reviewerItems))
+		for i, item := range reviewerItems {
+			ids[i] = item.ID
+		}
+		if err := s.reviewRepo.MarkItemsReminded(ids, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
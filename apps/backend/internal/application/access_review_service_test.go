@@ -0,0 +1,383 @@
+package application
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAccessReviewRepository keeps campaigns, items and evidence in memory
+type fakeAccessReviewRepository struct {
+	campaigns map[uuid.UUID]*domain.AccessReviewCampaign
+	items     map[uuid.UUID]*domain.AccessReviewItem
+	evidence  map[uuid.UUID]*domain.AccessReviewEvidence
+}
+
+func newFakeAccessReviewRepository() *fakeAccessReviewRepository {
+	return &fakeAccessReviewRepository{
+		campaigns: map[uuid.UUID]*domain.AccessReviewCampaign{},
+		items:     map[uuid.UUID]*domain.AccessReviewItem{},
+		evidence:  map[uuid.UUID]*domain.AccessReviewEvidence{},
+	}
+}
+
+func (r *fakeAccessReviewRepository) CreateCampaign(campaign *domain.AccessReviewCampaign, items []*domain.AccessReviewItem) error {
+	stored := *campaign
+	r.campaigns[campaign.ID] = &stored
+	for _, item := range items {
+		item.ID = uuid.New()
+		item.CampaignID = campaign.ID
+		item.OrganizationID = campaign.OrganizationID
+		copied := *item
+		r.items[item.ID] = &copied
+	}
+	return nil
+}
+
+func (r *fakeAccessReviewRepository) GetCampaign(id uuid.UUID) (*domain.AccessReviewCampaign, error) {
+	campaign, ok := r.campaigns[id]
+	if !ok {
+		return nil, fmt.Errorf("access review campaign not found")
+	}
+	result := *campaign
+	result.TotalItems, result.PendingItems, result.CertifiedItems, result.RevokedItems = 0, 0, 0, 0
+	for _, item := range r.items {
+		if item.CampaignID != id {
+			continue
+		}
+		result.TotalItems++
+		switch item.Decision {
+		case domain.AccessReviewDecisionPending:
+			result.PendingItems++
+		case domain.AccessReviewDecisionCertified:
+			result.CertifiedItems++
+		case domain.AccessReviewDecisionRevoked:
+			result.RevokedItems++
+		}
+	}
+	return &result, nil
+}
+
+func (r *fakeAccessReviewRepository) ListCampaigns(orgID uuid.UUID, status *domain.AccessReviewCampaignStatus, limit, offset int) ([]*domain.AccessReviewCampaign, int, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeAccessReviewRepository) ListActiveCampaigns() ([]*domain.AccessReviewCampaign, error) {
+	campaigns := []*domain.AccessReviewCampaign{}
+	for id, campaign := range r.campaigns {
+		if campaign.Status == domain.AccessReviewCampaignActive {
+			loaded, _ := r.GetCampaign(id)
+			campaigns = append(campaigns, loaded)
+		}
+	}
+	return campaigns, nil
+}
+
+func (r *fakeAccessReviewRepository) UpdateCampaignStatus(id uuid.UUID, status domain.AccessReviewCampaignStatus, completedAt *time.Time) error {
+	campaign := r.campaigns[id]
+	if campaign.Status != domain.AccessReviewCampaignActive {
+		return fmt.Errorf("access review campaign is not active")
+	}
+	campaign.Status = status
+	campaign.CompletedAt = completedAt
+	return nil
+}
+
+func (r *fakeAccessReviewRepository) GetItem(id uuid.UUID) (*domain.AccessReviewItem, error) {
+	item, ok := r.items[id]
+	if !ok {
+		return nil, fmt.Errorf("access review item not found")
+	}
+	copied := *item
+	return &copied, nil
+}
+
+func (r *fakeAccessReviewRepository) ListItems(filter domain.AccessReviewItemFilter) ([]*domain.AccessReviewItem, error) {
+	items := []*domain.AccessReviewItem{}
+	for _, item := range r.items {
+		if filter.CampaignID != nil && item.CampaignID != *filter.CampaignID {
+			continue
+		}
+		if filter.ReviewerID != nil && item.ReviewerID != *filter.ReviewerID {
+			continue
+		}
+		if filter.Decision != nil && item.Decision != *filter.Decision {
+			continue
+		}
+		copied := *item
+		items = append(items, &copied)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Subject+items[i].Access < items[j].Subject+items[j].Access })
+	return items, nil
+}
+
+func (r *fakeAccessReviewRepository) DecideItem(item *domain.AccessReviewItem) error {
+	stored := r.items[item.ID]
+	if stored.Decision != domain.AccessReviewDecisionPending {
+		return fmt.Errorf("access review item already decided")
+	}
+	copied := *item
+	r.items[item.ID] = &copied
+	return nil
+}
+
+func (r *fakeAccessReviewRepository) MarkItemsReminded(ids []uuid.UUID, at time.Time) error {
+	for _, id := range ids {
+		r.items[id].LastRemindedAt = &at
+	}
+	return nil
+}
+
+func (r *fakeAccessReviewRepository) CreateEvidence(evidence *domain.AccessReviewEvidence) error {
+	if _, exists := r.evidence[evidence.CampaignID]; exists {
+		return fmt.Errorf("failed to create access review evidence: duplicate")
+	}
+	r.evidence[evidence.CampaignID] = evidence
+	return nil
+}
+
+func (r *fakeAccessReviewRepository) GetEvidence(campaignID uuid.UUID) (*domain.AccessReviewEvidence, error) {
+	return r.evidence[campaignID], nil
+}
+
+// MockAccessRevoker is a mock of the capability and API key services' revocation methods
+type MockAccessRevoker struct {
+	mock.Mock
+}
+
+func (m *MockAccessRevoker) RevokeCapability(ctx context.Context, capabilityID uuid.UUID, revokedBy *uuid.UUID) error {
+	args := m.Called(ctx, capabilityID, revokedBy)
+	return args.Error(0)
+}
+
+func (m *MockAccessRevoker) RevokeAPIKey(ctx context.Context, keyID, orgID uuid.UUID) error {
+	args := m.Called(ctx, keyID, orgID)
+	return args.Error(0)
+}
+
+// createTestReviewUsers creates two admins and a member of the organization, plus a deactivated
+// member that is out of scope for reviews. users lists all of them.
+func createTestReviewUsers(orgID uuid.UUID) (admin, admin2, member *domain.User, users []*domain.User) {
+	user := func(email string, role domain.UserRole, status domain.UserStatus) *domain.User {
+		return &domain.User{ID: uuid.New(), OrganizationID: orgID, Email: email, Role: role, Status: status}
+	}
+	admin = user("admin@example.com", domain.RoleAdmin, domain.UserStatusActive)
+	admin2 = user("admin2@example.com", domain.RoleAdmin, domain.UserStatusActive)
+	member = user("member@example.com", domain.RoleMember, domain.UserStatusActive)
+	users = []*domain.User{admin, admin2, member, user("gone@example.com", domain.RoleMember, domain.UserStatusDeactivated)}
+	return admin, admin2, member, users
+}
+
+// createTestReviewedAgent creates an agent owned by ownerID with one capability grant and one
+// active API key
+func createTestReviewedAgent(orgID, ownerID uuid.UUID) (*domain.Agent, *domain.AgentCapability, *domain.APIKey) {
+	agent := &domain.Agent{ID: uuid.New(), OrganizationID: orgID, Name: "billing-agent", Status: domain.AgentStatusVerified, CreatedBy: ownerID}
+	grant := &domain.AgentCapability{ID: uuid.New(), AgentID: agent.ID, CapabilityType: "data:export"}
+	apiKey := &domain.APIKey{ID: uuid.New(), OrganizationID: orgID, AgentID: agent.ID, Name: "ci", Prefix: "aim_1234", IsActive: true}
+	return agent, grant, apiKey
+}
+
+func createTestAccessReviewService(t *testing.T, users []*domain.User, agent *domain.Agent, grant *domain.AgentCapability, apiKey *domain.APIKey) (*AccessReviewService, *fakeAccessReviewRepository, *MockUserRepository, *MockAccessRevoker, *MockEmailService) {
+	_, signingKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	orgID := agent.OrganizationID
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByOrganization", orgID).Return(users, nil)
+	for _, user := range users {
+		userRepo.On("GetByID", user.ID).Return(user, nil)
+	}
+	agentRepo := new(MockAgentRepository)
+	agentRepo.On("GetByOrganization", orgID).Return([]*domain.Agent{agent}, nil)
+	capabilityRepo := new(MockCapabilityRepository)
+	capabilityRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{grant}, nil)
+	apiKeyRepo := new(MockAPIKeyRepository)
+	apiKeyRepo.On("GetByOrganization", orgID).Return([]*domain.APIKey{
+		apiKey,
+		{ID: uuid.New(), OrganizationID: orgID, AgentID: agent.ID, Name: "old", Prefix: "aim_0000", IsActive: false},
+	}, nil)
+	revoker := new(MockAccessRevoker)
+	emailService := new(MockEmailService)
+
+	repo := newFakeAccessReviewRepository()
+	service := NewAccessReviewService(repo, userRepo, agentRepo, capabilityRepo, apiKeyRepo, revoker, revoker, emailService, signingKey)
+	return service, repo, userRepo, revoker, emailService
+}
+
+// createTestCampaign creates a campaign due in two weeks unless req says otherwise and returns
+// its items by reviewed resource
+func createTestCampaign(t *testing.T, service *AccessReviewService, orgID, createdBy uuid.UUID, req AccessReviewCampaignRequest) (*domain.AccessReviewCampaign, map[uuid.UUID]*domain.AccessReviewItem) {
+	if req.Name == "" {
+		req.Name = "Q4 access review"
+	}
+	if req.DueAt.IsZero() {
+		req.DueAt = service.now().AddDate(0, 0, 14)
+	}
+	campaign, err := service.CreateCampaign(context.Background(), orgID, createdBy, req)
+	require.NoError(t, err)
+
+	items, err := service.GetCampaignItems(context.Background(), orgID, campaign.ID, nil, nil)
+	require.NoError(t, err)
+	byResource := map[uuid.UUID]*domain.AccessReviewItem{}
+	for _, item := range items {
+		byResource[item.ResourceID] = item
+	}
+	return campaign, byResource
+}
+
+func TestAccessReview_CreateAssignsIndependentReviewers(t *testing.T) {
+	orgID := uuid.New()
+	admin, admin2, member, users := createTestReviewUsers(orgID)
+	agent, grant, apiKey := createTestReviewedAgent(orgID, member.ID)
+	service, _, _, _, _ := createTestAccessReviewService(t, users, agent, grant, apiKey)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	campaign, items := createTestCampaign(t, service, orgID, admin.ID, AccessReviewCampaignRequest{AssignAgentOwners: true})
+
+	// 3 active users, 1 capability grant, 1 active API key (inactive users and keys are out of scope)
+	assert.Equal(t, 5, campaign.TotalItems)
+	assert.Equal(t, 5, campaign.PendingItems)
+
+	// The reviewer's own role goes to another admin
+	assert.Equal(t, admin2.ID, items[admin.ID].ReviewerID)
+	assert.Equal(t, admin.ID, items[member.ID].ReviewerID)
+
+	// Agent owners review their agents' grants and keys
+	assert.Equal(t, member.ID, items[grant.ID].ReviewerID)
+	assert.Equal(t, member.ID, items[apiKey.ID].ReviewerID)
+	assert.Equal(t, "data:export", items[grant.ID].Access)
+
+	_, err := service.CreateCampaign(context.Background(), orgID, admin.ID, AccessReviewCampaignRequest{
+		Name:       "bad reviewer",
+		ReviewerID: &member.ID,
+		DueAt:      now.AddDate(0, 0, 7),
+	})
+	assert.ErrorContains(t, err, "reviewer must be an active admin or manager")
+}
+
+func TestAccessReview_DecisionsApplyRevocations(t *testing.T) {
+	orgID := uuid.New()
+	admin, admin2, member, users := createTestReviewUsers(orgID)
+	agent, grant, apiKey := createTestReviewedAgent(orgID, member.ID)
+	service, repo, userRepo, revoker, _ := createTestAccessReviewService(t, users, agent, grant, apiKey)
+	revoker.On("RevokeCapability", mock.Anything, grant.ID, &admin.ID).Return(nil).Once()
+	revoker.On("RevokeAPIKey", mock.Anything, apiKey.ID, orgID).Return(nil).Once()
+	userRepo.On("UpdateRole", member.ID, domain.RoleViewer).Return(nil)
+	ctx := context.Background()
+	_, items := createTestCampaign(t, service, orgID, admin.ID, AccessReviewCampaignRequest{})
+
+	// Only the assigned reviewer (or an admin) may decide, and never on their own role
+	_, err := service.DecideItem(ctx, orgID, items[grant.ID].ID, member.ID, false, domain.AccessReviewDecisionRevoked, "")
+	assert.EqualError(t, err, "not the assigned reviewer")
+	_, err = service.DecideItem(ctx, orgID, items[admin2.ID].ID, admin2.ID, true, domain.AccessReviewDecisionCertified, "")
+	assert.EqualError(t, err, "reviewers cannot decide on their own access")
+
+	_, err = service.DecideItem(ctx, orgID, items[grant.ID].ID, admin.ID, true, domain.AccessReviewDecisionRevoked, "No longer exports data")
+	require.NoError(t, err)
+	_, err = service.DecideItem(ctx, orgID, items[apiKey.ID].ID, admin.ID, true, domain.AccessReviewDecisionRevoked, "")
+	require.NoError(t, err)
+	_, err = service.DecideItem(ctx, orgID, items[member.ID].ID, admin.ID, true, domain.AccessReviewDecisionRevoked, "")
+	require.NoError(t, err)
+
+	revoker.AssertExpectations(t)
+	userRepo.AssertCalled(t, "UpdateRole", member.ID, domain.RoleViewer)
+
+	_, err = service.DecideItem(ctx, orgID, items[grant.ID].ID, admin.ID, true, domain.AccessReviewDecisionCertified, "")
+	assert.EqualError(t, err, "access review item already decided")
+
+	// A failed revocation leaves the item pending
+	service, repo, _, revoker, _ = createTestAccessReviewService(t, users, agent, grant, apiKey)
+	revoker.On("RevokeAPIKey", mock.Anything, apiKey.ID, orgID).Return(fmt.Errorf("API key does not belong to organization"))
+	_, items = createTestCampaign(t, service, orgID, admin.ID, AccessReviewCampaignRequest{})
+	_, err = service.DecideItem(ctx, orgID, items[apiKey.ID].ID, admin.ID, true, domain.AccessReviewDecisionRevoked, "")
+	assert.Error(t, err)
+	assert.Equal(t, domain.AccessReviewDecisionPending, repo.items[items[apiKey.ID].ID].Decision)
+}
+
+func TestAccessReview_LastDecisionSealsEvidence(t *testing.T) {
+	orgID := uuid.New()
+	admin, admin2, member, users := createTestReviewUsers(orgID)
+	agent, grant, apiKey := createTestReviewedAgent(orgID, member.ID)
+	service, _, _, _, _ := createTestAccessReviewService(t, users, agent, grant, apiKey)
+	ctx := context.Background()
+	campaign, items := createTestCampaign(t, service, orgID, admin.ID, AccessReviewCampaignRequest{Scope: []domain.AccessReviewItemType{domain.AccessReviewItemUserRole}})
+
+	for resourceID, item := range items {
+		reviewer := admin.ID
+		if resourceID == admin.ID {
+			reviewer = admin2.ID
+		}
+		_, err := service.DecideItem(ctx, orgID, item.ID, reviewer, true, domain.AccessReviewDecisionCertified, "Still required")
+		require.NoError(t, err)
+	}
+
+	completed, err := service.GetCampaign(ctx, orgID, campaign.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccessReviewCampaignCompleted, completed.Status)
+
+	evidence, verified, err := service.GetEvidence(ctx, orgID, campaign.ID)
+	require.NoError(t, err)
+	assert.True(t, verified)
+	assert.NotEmpty(t, evidence.Signature)
+	assert.Contains(t, string(evidence.Snapshot), `"decision":"certified"`)
+
+	// Evidence that no longer matches its hash does not verify
+	evidence.Snapshot = []byte(string(evidence.Snapshot[:len(evidence.Snapshot)-1]) + " }")
+	assert.False(t, service.verifyEvidence(evidence))
+
+	_, err = service.DecideItem(ctx, orgID, items[member.ID].ID, admin.ID, true, domain.AccessReviewDecisionRevoked, "")
+	assert.EqualError(t, err, "access review item already decided")
+}
+
+func TestAccessReview_RemindersAndAutoRevoke(t *testing.T) {
+	orgID := uuid.New()
+	admin, _, member, users := createTestReviewUsers(orgID)
+	agent, grant, apiKey := createTestReviewedAgent(orgID, member.ID)
+	service, repo, _, revoker, emailService := createTestAccessReviewService(t, users, agent, grant, apiKey)
+	revoker.On("RevokeAPIKey", mock.Anything, apiKey.ID, orgID).Return(nil).Once()
+	emailService.On("SendTemplatedEmail", domain.TemplateAccessReviewReminder, "admin@example.com", mock.Anything).Return(nil)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+	campaign, items := createTestCampaign(t, service, orgID, admin.ID, AccessReviewCampaignRequest{
+		Scope:              []domain.AccessReviewItemType{domain.AccessReviewItemCapabilityGrant, domain.AccessReviewItemAPIKey},
+		AutoRevokeOnExpiry: true,
+		DueAt:              now.AddDate(0, 0, 2),
+	})
+
+	// Inside the reminder window: one email per reviewer, then throttled
+	require.NoError(t, service.ProcessCampaigns(ctx))
+	emailService.AssertNumberOfCalls(t, "SendTemplatedEmail", 1)
+	require.NoError(t, service.ProcessCampaigns(ctx))
+	emailService.AssertNumberOfCalls(t, "SendTemplatedEmail", 1)
+
+	_, err := service.DecideItem(ctx, orgID, items[grant.ID].ID, admin.ID, true, domain.AccessReviewDecisionCertified, "")
+	require.NoError(t, err)
+
+	// At the due date the undecided API key is revoked and the campaign sealed
+	service.now = func() time.Time { return campaign.DueAt.Add(time.Minute) }
+	require.NoError(t, service.ProcessCampaigns(ctx))
+
+	revoker.AssertExpectations(t)
+	revoker.AssertNotCalled(t, "RevokeCapability", mock.Anything, mock.Anything, mock.Anything)
+
+	item := repo.items[items[apiKey.ID].ID]
+	assert.Equal(t, domain.AccessReviewDecisionRevoked, item.Decision)
+	assert.Nil(t, item.DecidedBy)
+
+	completed, err := service.GetCampaign(ctx, orgID, campaign.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccessReviewCampaignCompleted, completed.Status)
+	assert.NotNil(t, repo.evidence[campaign.ID])
+}
@@ -0,0 +1,308 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// actionRiskTierDefaults fill in the required trust, trust multiplier and approval requirement
+// of catalog entries that only set a tier. Actions missing from the catalog are treated as medium.
+var actionRiskTierDefaults = map[domain.ActionRiskTier]struct {
+	requiredTrust    float64
+	trustMultiplier  float64
+	requiresApproval bool
+}{
+	domain.ActionRiskLow:    {requiredTrust: 0.3, trustMultiplier: 1.0},
+	domain.ActionRiskMedium: {requiredTrust: 0.5, trustMultiplier: 0.8},
+	domain.ActionRiskHigh:   {requiredTrust: 0.7, trustMultiplier: 0.5, requiresApproval: true},
+}
+
+// DefaultActionCatalog returns the catalog seeded for new organizations
+func DefaultActionCatalog() []*domain.ActionCatalogEntry {
+	entry := func(action, description string, tier domain.ActionRiskTier, multiplier float64, capability string, aliases ...string) *domain.ActionCatalogEntry {
+		defaults := actionRiskTierDefaults[tier]
+		if aliases == nil {
+			aliases = []string{}
+		}
+		return &domain.ActionCatalogEntry{
+			Action:             action,
+			Description:        description,
+			RiskTier:           tier,
+			RequiredTrust:      defaults.requiredTrust,
+			TrustMultiplier:    multiplier,
+			RequiredCapability: capability,
+			RequiresApproval:   defaults.requiresApproval,
+			Aliases:            aliases,
+		}
+	}
+
+	return []*domain.ActionCatalogEntry{
+		// Low risk (read-only)
+		entry("read_database", "Read rows from a database", domain.ActionRiskLow, 1.0, domain.CapabilityDBQuery, "query_database"),
+		entry("read_file", "Read a file", domain.ActionRiskLow, 1.0, domain.CapabilityFileRead),
+		entry("query_api", "Call an external API without side effects", domain.ActionRiskLow, 1.0, domain.CapabilityAPICall, "call_api"),
+		// Medium risk (modifications)
+		entry("write_database", "Insert or update database rows", domain.ActionRiskMedium, 0.8, domain.CapabilityDBWrite),
+		entry("write_file", "Create or modify a file", domain.ActionRiskMedium, 0.8, domain.CapabilityFileWrite),
+		entry("send_email", "Send an email", domain.ActionRiskMedium, 0.8, ""),
+		entry("modify_config", "Change configuration", domain.ActionRiskMedium, 0.7, ""),
+		entry("export_data", "Export data out of the organization", domain.ActionRiskMedium, 0.8, domain.CapabilityDataExport),
+		// High risk (destructive)
+		entry("delete_data", "Delete data", domain.ActionRiskHigh, 0.5, ""),
+		entry("delete_file", "Delete a file", domain.ActionRiskHigh, 0.5, domain.CapabilityFileDelete),
+		entry("execute_command", "Run a shell command", domain.ActionRiskHigh, 0.3, domain.CapabilitySystemAdmin),
+		entry("admin_action", "Perform an administrative operation", domain.ActionRiskHigh, 0.3, domain.CapabilitySystemAdmin),
+	}
+}
+
+// uncatalogedAction describes an action the organization's catalog does not list
+func uncatalogedAction(name string) *domain.ActionCatalogEntry {
+	defaults := actionRiskTierDefaults[domain.ActionRiskMedium]
+	return &domain.ActionCatalogEntry{
+		Action:          name,
+		RiskTier:        domain.ActionRiskMedium,
+		RequiredTrust:   defaults.requiredTrust,
+		TrustMultiplier: defaults.trustMultiplier,
+		Aliases:         []string{},
+	}
+}
+
+// BuiltinAction resolves name against the default catalog; used while an organization's catalog
+// is unavailable so verification never runs without risk tiers
+func BuiltinAction(name string) *domain.ActionCatalogEntry {
+	name = normalizeActionName(name)
+	for _, entry := range DefaultActionCatalog() {
+		if entry.Matches(name) {
+			return entry
+		}
+	}
+	return uncatalogedAction(name)
+}
+
+func normalizeActionName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ActionCatalogRequest creates or replaces an action catalog entry. Unset trust values and
+// approval requirement default from the risk tier.
+type ActionCatalogRequest struct {
+	Action             string                `json:"action"`
+	Description        string                `json:"description"`
+	RiskTier           domain.ActionRiskTier `json:"risk_tier"`
+	RequiredTrust      *float64              `json:"required_trust"`
+	TrustMultiplier    *float64              `json:"trust_multiplier"`
+	RequiredCapability string                `json:"required_capability"`
+	RequiresApproval   *bool                 `json:"requires_approval"`
+	Aliases            []string              `json:"aliases"`
+}
+
+// ActionCatalogService manages each organization's action risk catalog and resolves the actions
+// agents request (and the MCP tools they call) to an entry
+type ActionCatalogService struct {
+	catalogRepo domain.ActionCatalogRepository
+	seeded      sync.Map // organization ID -> struct{}, organizations known to have a catalog
+}
+
+// NewActionCatalogService creates a new action catalog service
+func NewActionCatalogService(catalogRepo domain.ActionCatalogRepository) *ActionCatalogService {
+	return &ActionCatalogService{
+		catalogRepo: catalogRepo,
+	}
+}
+
+// SeedDefaults adds the default entries the organization does not have yet. Entries an admin
+// edited are left alone.
+func (s *ActionCatalogService) SeedDefaults(ctx context.Context, orgID uuid.UUID) (int, error) {
+	added, err := s.catalogRepo.Seed(orgID, DefaultActionCatalog())
+	if err != nil {
+		return 0, err
+	}
+	s.seeded.Store(orgID, struct{}{})
+
+	return added, nil
+}
+
+// ensureSeeded seeds the default catalog for organizations that predate it
+func (s *ActionCatalogService) ensureSeeded(ctx context.Context, orgID uuid.UUID) error {
+	if _, ok := s.seeded.Load(orgID); ok {
+		return nil
+	}
+
+	entries, err := s.catalogRepo.List(orgID)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		s.seeded.Store(orgID, struct{}{})
+		return nil
+	}
+
+	added, err := s.SeedDefaults(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📚 Seeded %d default action catalog entries for organization %s\n", added, orgID)
+
+	return nil
+}
+
+// Resolve returns the catalog entry for an action or MCP tool name. Names the catalog does not
+// list resolve to a medium-risk entry; it never returns nil.
+func (s *ActionCatalogService) Resolve(ctx context.Context, orgID uuid.UUID, name string) *domain.ActionCatalogEntry {
+	name = normalizeActionName(name)
+
+	if err := s.ensureSeeded(ctx, orgID); err != nil {
+		fmt.Printf("⚠️  Action catalog unavailable for organization %s, using defaults: %v\n", orgID, err)
+		return BuiltinAction(name)
+	}
+
+	entry, err := s.catalogRepo.GetByName(orgID, name)
+	if err != nil {
+		fmt.Printf("⚠️  Action catalog lookup of %s failed, using defaults: %v\n", name, err)
+		return BuiltinAction(name)
+	}
+	if entry == nil {
+		return uncatalogedAction(name)
+	}
+
+	return entry
+}
+
+// CapabilityForTool maps an MCP tool name to the capability type it requires
+func (s *ActionCatalogService) CapabilityForTool(ctx context.Context, orgID uuid.UUID, toolName string) string {
+	if entry := s.Resolve(ctx, orgID, toolName); entry.RequiredCapability != "" {
+		return entry.RequiredCapability
+	}
+	return fmt.Sprintf("%s:%s", domain.CapabilityMCPToolUse, toolName)
+}
+
+// List returns the organization's catalog, seeding the defaults on first use
+func (s *ActionCatalogService) List(ctx context.Context, orgID uuid.UUID) ([]*domain.ActionCatalogEntry, error) {
+	if err := s.ensureSeeded(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.catalogRepo.List(orgID)
+}
+
+// Get returns a single catalog entry
+func (s *ActionCatalogService) Get(ctx context.Context, orgID, id uuid.UUID) (*domain.ActionCatalogEntry, error) {
+	return s.catalogRepo.GetByID(orgID, id)
+}
+
+// Create adds an action to the organization's catalog
+func (s *ActionCatalogService) Create(ctx context.Context, orgID, userID uuid.UUID, req ActionCatalogRequest) (*domain.ActionCatalogEntry, error) {
+	if err := s.ensureSeeded(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	entry := &domain.ActionCatalogEntry{
+		OrganizationID: orgID,
+		CreatedBy:      &userID,
+	}
+	if err := s.apply(orgID, entry, req); err != nil {
+		return nil, err
+	}
+	if err := s.catalogRepo.Create(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Update replaces a catalog entry
+func (s *ActionCatalogService) Update(ctx context.Context, orgID, id uuid.UUID, req ActionCatalogRequest) (*domain.ActionCatalogEntry, error) {
+	entry, err := s.catalogRepo.GetByID(orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(orgID, entry, req); err != nil {
+		return nil, err
+	}
+	if err := s.catalogRepo.Update(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Delete removes an action from the catalog; it is then treated as medium risk
+func (s *ActionCatalogService) Delete(ctx context.Context, orgID, id uuid.UUID) error {
+	return s.catalogRepo.Delete(orgID, id)
+}
+
+// apply validates req and copies it onto entry
+func (s *ActionCatalogService) apply(orgID uuid.UUID, entry *domain.ActionCatalogEntry, req ActionCatalogRequest) error {
+	action := normalizeActionName(req.Action)
+	if action == "" || strings.ContainsAny(action, " \t\n") {
+		return fmt.Errorf("invalid action catalog entry: action must be a non-empty name without spaces")
+	}
+	defaults, ok := actionRiskTierDefaults[req.RiskTier]
+	if !ok {
+		return fmt.Errorf("invalid action catalog entry: risk_tier must be low, medium or high")
+	}
+
+	requiredTrust := defaults.requiredTrust
+	if req.RequiredTrust != nil {
+		requiredTrust = *req.RequiredTrust
+	}
+	if requiredTrust < 0 || requiredTrust > 1 {
+		return fmt.Errorf("invalid action catalog entry: required_trust must be between 0 and 1")
+	}
+	trustMultiplier := defaults.trustMultiplier
+	if req.TrustMultiplier != nil {
+		trustMultiplier = *req.TrustMultiplier
+	}
+	if trustMultiplier <= 0 || trustMultiplier > 1 {
+		return fmt.Errorf("invalid action catalog entry: trust_multiplier must be greater than 0 and at most 1")
+	}
+	requiresApproval := defaults.requiresApproval
+	if req.RequiresApproval != nil {
+		requiresApproval = *req.RequiresApproval
+	}
+
+	aliases := []string{}
+	seen := map[string]bool{action: true}
+	for _, alias := range req.Aliases {
+		alias = normalizeActionName(alias)
+		if alias == "" || seen[alias] {
+			continue
+		}
+		if strings.ContainsAny(alias, " \t\n") {
+			return fmt.Errorf("invalid action catalog entry: alias %q contains spaces", alias)
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+	}
+
+	// Every name must resolve to exactly one entry
+	existing, err := s.catalogRepo.List(orgID)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.ID == entry.ID {
+			continue
+		}
+		for name := range seen {
+			if other.Matches(name) {
+				return fmt.Errorf("action catalog entry already exists for %s (%s)", name, other.Action)
+			}
+		}
+	}
+
+	entry.Action = action
+	entry.Description = strings.TrimSpace(req.Description)
+	entry.RiskTier = req.RiskTier
+	entry.RequiredTrust = requiredTrust
+	entry.TrustMultiplier = trustMultiplier
+	entry.RequiredCapability = strings.TrimSpace(req.RequiredCapability)
+	entry.RequiresApproval = requiresApproval
+	entry.Aliases = aliases
+
+	return nil
+}
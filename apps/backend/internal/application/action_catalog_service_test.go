@@ -0,0 +1,220 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeActionCatalogRepository keeps catalog entries in memory
+type fakeActionCatalogRepository struct {
+	entries map[uuid.UUID][]*domain.ActionCatalogEntry
+	err     error
+	seeds   int
+}
+
+func newFakeActionCatalogRepository() *fakeActionCatalogRepository {
+	return &fakeActionCatalogRepository{entries: map[uuid.UUID][]*domain.ActionCatalogEntry{}}
+}
+
+func (r *fakeActionCatalogRepository) Create(entry *domain.ActionCatalogEntry) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	r.entries[entry.OrganizationID] = append(r.entries[entry.OrganizationID], entry)
+	return nil
+}
+
+func (r *fakeActionCatalogRepository) Update(entry *domain.ActionCatalogEntry) error {
+	if _, err := r.GetByID(entry.OrganizationID, entry.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *fakeActionCatalogRepository) Delete(orgID, id uuid.UUID) error {
+	for i, entry := range r.entries[orgID] {
+		if entry.ID == id {
+			r.entries[orgID] = append(r.entries[orgID][:i], r.entries[orgID][i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("action catalog entry not found")
+}
+
+func (r *fakeActionCatalogRepository) GetByID(orgID, id uuid.UUID) (*domain.ActionCatalogEntry, error) {
+	for _, entry := range r.entries[orgID] {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("action catalog entry not found")
+}
+
+func (r *fakeActionCatalogRepository) GetByName(orgID uuid.UUID, name string) (*domain.ActionCatalogEntry, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, entry := range r.entries[orgID] {
+		if entry.Matches(name) {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeActionCatalogRepository) List(orgID uuid.UUID) ([]*domain.ActionCatalogEntry, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return append([]*domain.ActionCatalogEntry{}, r.entries[orgID]...), nil
+}
+
+func (r *fakeActionCatalogRepository) Seed(orgID uuid.UUID, entries []*domain.ActionCatalogEntry) (int, error) {
+	r.seeds++
+	added := 0
+	for _, entry := range entries {
+		if existing, _ := r.GetByName(orgID, entry.Action); existing != nil && existing.Action == entry.Action {
+			continue
+		}
+		entry.OrganizationID = orgID
+		r.Create(entry)
+		added++
+	}
+	return added, nil
+}
+
+func TestActionCatalogService_ResolveSeedsDefaultsAndResolvesAliases(t *testing.T) {
+	repo := newFakeActionCatalogRepository()
+	service := NewActionCatalogService(repo)
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	action := service.Resolve(ctx, orgID, "Query_Database")
+	assert.Equal(t, "read_database", action.Action)
+	assert.Equal(t, domain.ActionRiskLow, action.RiskTier)
+	assert.Equal(t, domain.CapabilityDBQuery, action.RequiredCapability)
+	assert.Len(t, repo.entries[orgID], len(DefaultActionCatalog()))
+
+	action = service.Resolve(ctx, orgID, "delete_file")
+	assert.Equal(t, domain.ActionRiskHigh, action.RiskTier)
+	assert.Equal(t, 0.7, action.RequiredTrust)
+	assert.True(t, action.RequiresApproval)
+	assert.Equal(t, 1, repo.seeds, "defaults are seeded once per organization")
+
+	// Actions missing from the catalog are medium risk
+	action = service.Resolve(ctx, orgID, "summarize_ticket")
+	assert.Equal(t, "summarize_ticket", action.Action)
+	assert.Equal(t, domain.ActionRiskMedium, action.RiskTier)
+	assert.Equal(t, 0.5, action.RequiredTrust)
+	assert.Equal(t, 0.8, action.TrustMultiplier)
+	assert.False(t, action.RequiresApproval)
+}
+
+func TestActionCatalogService_ResolveHonorsOrganizationEdits(t *testing.T) {
+	repo := newFakeActionCatalogRepository()
+	service := NewActionCatalogService(repo)
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	entries, err := service.List(ctx, orgID)
+	require.NoError(t, err)
+	var sendEmail *domain.ActionCatalogEntry
+	for _, entry := range entries {
+		if entry.Action == "send_email" {
+			sendEmail = entry
+		}
+	}
+	require.NotNil(t, sendEmail)
+
+	approval := true
+	_, err = service.Update(ctx, orgID, sendEmail.ID, ActionCatalogRequest{
+		Action:   "send_email",
+		RiskTier: domain.ActionRiskHigh,
+		Aliases:  []string{"gmail_send", "GMAIL_SEND", "outlook_send"},
+		// required_trust and trust_multiplier default from the tier
+		RequiresApproval: &approval,
+	})
+	require.NoError(t, err)
+
+	action := service.Resolve(ctx, orgID, "gmail_send")
+	assert.Equal(t, "send_email", action.Action)
+	assert.Equal(t, domain.ActionRiskHigh, action.RiskTier)
+	assert.Equal(t, 0.7, action.RequiredTrust)
+	assert.Equal(t, 0.5, action.TrustMultiplier)
+	assert.True(t, action.RequiresApproval)
+	assert.Equal(t, []string{"gmail_send", "outlook_send"}, action.Aliases)
+
+	// Another organization keeps the defaults
+	other := service.Resolve(ctx, uuid.New(), "send_email")
+	assert.Equal(t, domain.ActionRiskMedium, other.RiskTier)
+}
+
+func TestActionCatalogService_CreateValidates(t *testing.T) {
+	repo := newFakeActionCatalogRepository()
+	service := NewActionCatalogService(repo)
+	ctx := context.Background()
+	orgID, userID := uuid.New(), uuid.New()
+	tooHigh := 1.5
+
+	tests := []struct {
+		name    string
+		req     ActionCatalogRequest
+		wantErr string
+	}{
+		{"missing action", ActionCatalogRequest{RiskTier: domain.ActionRiskLow}, "invalid action catalog entry: action"},
+		{"unknown tier", ActionCatalogRequest{Action: "rotate_keys", RiskTier: "extreme"}, "invalid action catalog entry: risk_tier"},
+		{"trust out of range", ActionCatalogRequest{Action: "rotate_keys", RiskTier: domain.ActionRiskHigh, RequiredTrust: &tooHigh}, "invalid action catalog entry: required_trust"},
+		{"duplicate action", ActionCatalogRequest{Action: "delete_file", RiskTier: domain.ActionRiskHigh}, "action catalog entry already exists for delete_file"},
+		{"alias used elsewhere", ActionCatalogRequest{Action: "rotate_keys", RiskTier: domain.ActionRiskHigh, Aliases: []string{"call_api"}}, "action catalog entry already exists for call_api (query_api)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Create(ctx, orgID, userID, tt.req)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	entry, err := service.Create(ctx, orgID, userID, ActionCatalogRequest{
+		Action:             "rotate_keys",
+		RiskTier:           domain.ActionRiskHigh,
+		RequiredCapability: domain.CapabilitySystemAdmin,
+		Aliases:            []string{"kms_rotate"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0.7, entry.RequiredTrust)
+	assert.True(t, entry.RequiresApproval)
+	assert.Equal(t, &userID, entry.CreatedBy)
+	assert.Equal(t, domain.CapabilitySystemAdmin, service.CapabilityForTool(ctx, orgID, "kms_rotate"))
+}
+
+func TestActionCatalogService_CapabilityForTool(t *testing.T) {
+	service := NewActionCatalogService(newFakeActionCatalogRepository())
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	assert.Equal(t, domain.CapabilityFileRead, service.CapabilityForTool(ctx, orgID, "read_file"))
+	assert.Equal(t, domain.CapabilityAPICall, service.CapabilityForTool(ctx, orgID, "call_api"))
+	assert.Equal(t, domain.CapabilitySystemAdmin, service.CapabilityForTool(ctx, orgID, "execute_command"))
+	assert.Equal(t, "mcp:tool_use:search_docs", service.CapabilityForTool(ctx, orgID, "search_docs"))
+	// Cataloged actions without a capability also fall back to mcp:tool_use
+	assert.Equal(t, "mcp:tool_use:send_email", service.CapabilityForTool(ctx, orgID, "send_email"))
+}
+
+func TestActionCatalogService_FallsBackToDefaultsWhenUnavailable(t *testing.T) {
+	repo := newFakeActionCatalogRepository()
+	repo.err = fmt.Errorf("connection refused")
+	service := NewActionCatalogService(repo)
+
+	action := service.Resolve(context.Background(), uuid.New(), "execute_command")
+	assert.Equal(t, domain.ActionRiskHigh, action.RiskTier)
+	assert.Equal(t, 0.3, action.TrustMultiplier)
+	assert.True(t, action.RequiresApproval)
+	assert.Equal(t, 0, repo.seeds)
+}
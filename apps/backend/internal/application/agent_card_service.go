@@ -0,0 +1,518 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/auth"
+)
+
+var (
+	ErrAgentCardNotFound    = errors.New("agent card not found")
+	ErrAgentCardUnavailable = errors.New("agent is not verified or has been suspended")
+	ErrInvalidAgentCard     = errors.New("invalid agent card")
+)
+
+// agentCardTTL is how long a signed Agent Card is valid. Cards are generated on every fetch, so
+// a short lifetime keeps copies in circulation close to the agent's current state.
+const agentCardTTL = 24 * time.Hour
+
+// maxAgentCardSkills bounds the number of skills an agent can declare
+const maxAgentCardSkills = 50
+
+// AgentCardRequest publishes an agent as an A2A Agent Card
+type AgentCardRequest struct {
+	URL                string              `json:"url"` // The agent's A2A service endpoint
+	Skills             []domain.AgentSkill `json:"skills"`
+	Streaming          bool                `json:"streaming"`
+	PushNotifications  bool                `json:"pushNotifications"`
+	DefaultInputModes  []string            `json:"defaultInputModes"`
+	DefaultOutputModes []string            `json:"defaultOutputModes"`
+}
+
+// agentCardHeader is the protected header of an Agent Card signature
+type agentCardHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ,omitempty"`
+	Jku string `json:"jku,omitempty"`
+	Iss string `json:"iss"`
+	Sub string `json:"sub"` // Agent ID
+	Jti string `json:"jti"` // Card ID
+	Iat int64  `json:"iat"`
+	Exp int64  `json:"exp"`
+}
+
+// AgentCardService generates and signs A2A Agent Cards from agent metadata and verifies cards
+// presented by other agents. Cards are signed with the keys that sign user tokens, so they can be
+// checked against AIM's published JWKS as well as through Verify.
+type AgentCardService struct {
+	cardRepo       domain.AgentCardRepository
+	agentRepo      domain.AgentRepository
+	capabilityRepo domain.CapabilityRepository
+	orgRepo        domain.OrganizationRepository
+	keys           auth.SigningKeySource
+	issuer         string
+	now            func() time.Time
+}
+
+// NewAgentCardService creates a new Agent Card service
+func NewAgentCardService(
+	cardRepo domain.AgentCardRepository,
+	agentRepo domain.AgentRepository,
+	capabilityRepo domain.CapabilityRepository,
+	orgRepo domain.OrganizationRepository,
+	keys auth.SigningKeySource,
+	issuer string,
+) *AgentCardService {
+	return &AgentCardService{
+		cardRepo:       cardRepo,
+		agentRepo:      agentRepo,
+		capabilityRepo: capabilityRepo,
+		orgRepo:        orgRepo,
+		keys:           keys,
+		issuer:         strings.TrimRight(issuer, "/"),
+		now:            time.Now,
+	}
+}
+
+// CardURL returns the public URL an agent's card is served at
+func (s *AgentCardService) CardURL(agentID uuid.UUID) string {
+	return s.issuer + "/a2a/agents/" + agentID.String() + "/.well-known/agent.json"
+}
+
+// VerificationURL returns the endpoint that verifies Agent Cards
+func (s *AgentCardService) VerificationURL() string {
+	return s.issuer + "/api/v1/public/a2a/verify"
+}
+
+// Publish publishes an agent's card, or replaces the declared fields of its published card
+func (s *AgentCardService) Publish(ctx context.Context, orgID, agentID, userID uuid.UUID, req *AgentCardRequest) (*domain.AgentCardRecord, error) {
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil || agent.OrganizationID != orgID {
+		return nil, fmt.Errorf("agent not found")
+	}
+	if err := validateAgentCardRequest(req); err != nil {
+		return nil, err
+	}
+
+	inputModes := req.DefaultInputModes
+	if len(inputModes) == 0 {
+		inputModes = []string{"text/plain"}
+	}
+	outputModes := req.DefaultOutputModes
+	if len(outputModes) == 0 {
+		outputModes = []string{"text/plain"}
+	}
+	skills := make([]domain.AgentSkill, len(req.Skills))
+	for i, skill := range req.Skills {
+		if skill.Tags == nil {
+			skill.Tags = []string{}
+		}
+		skills[i] = skill
+	}
+
+	now := s.now()
+	record, err := s.cardRepo.GetActiveByAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+	isNew := record == nil
+	if isNew {
+		record = &domain.AgentCardRecord{
+			ID:             uuid.New(),
+			AgentID:        agentID,
+			OrganizationID: orgID,
+			CreatedAt:      now,
+		}
+	}
+	record.URL = req.URL
+	record.Skills = skills
+	record.Streaming = req.Streaming
+	record.PushNotifications = req.PushNotifications
+	record.DefaultInputModes = inputModes
+	record.DefaultOutputModes = outputModes
+	record.PublishedBy = &userID
+	record.UpdatedAt = now
+
+	if isNew {
+		err = s.cardRepo.Create(record)
+	} else {
+		err = s.cardRepo.Update(record)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func validateAgentCardRequest(req *AgentCardRequest) error {
+	endpoint, err := url.Parse(req.URL)
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return fmt.Errorf("%w: url must be the agent's absolute http(s) A2A endpoint", ErrInvalidAgentCard)
+	}
+	if len(req.Skills) > maxAgentCardSkills {
+		return fmt.Errorf("%w: at most %d skills can be declared", ErrInvalidAgentCard, maxAgentCardSkills)
+	}
+
+	seen := map[string]bool{}
+	for _, skill := range req.Skills {
+		if strings.TrimSpace(skill.ID) == "" || strings.TrimSpace(skill.Name) == "" {
+			return fmt.Errorf("%w: every skill needs an id and a name", ErrInvalidAgentCard)
+		}
+		if seen[skill.ID] {
+			return fmt.Errorf("%w: duplicate skill id %q", ErrInvalidAgentCard, skill.ID)
+		}
+		seen[skill.ID] = true
+	}
+
+	return nil
+}
+
+// GetRecord returns an agent's published card
+func (s *AgentCardService) GetRecord(ctx context.Context, orgID, agentID uuid.UUID) (*domain.AgentCardRecord, error) {
+	record, err := s.cardRepo.GetActiveByAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.OrganizationID != orgID {
+		return nil, ErrAgentCardNotFound
+	}
+	return record, nil
+}
+
+// Unpublish revokes an agent's card; cards already handed out stop verifying
+func (s *AgentCardService) Unpublish(ctx context.Context, orgID, agentID uuid.UUID) error {
+	record, err := s.GetRecord(ctx, orgID, agentID)
+	if err != nil {
+		return err
+	}
+	return s.cardRepo.Revoke(record.ID, "unpublished", s.now())
+}
+
+// SignedCard generates and signs an agent's published card
+func (s *AgentCardService) SignedCard(ctx context.Context, agentID uuid.UUID) (*domain.AgentCard, error) {
+	record, err := s.cardRepo.GetActiveByAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrAgentCardNotFound
+	}
+
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil {
+		return nil, ErrAgentCardNotFound
+	}
+	if agent.Status != domain.AgentStatusVerified || agent.IsCompromised {
+		return nil, ErrAgentCardUnavailable
+	}
+
+	card, err := s.buildCard(record, agent)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sign(card, record, agent); err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
+
+// buildCard generates the unsigned card of an agent
+func (s *AgentCardService) buildCard(record *domain.AgentCardRecord, agent *domain.Agent) (*domain.AgentCard, error) {
+	name := agent.DisplayName
+	if name == "" {
+		name = agent.Name
+	}
+	description := agent.Description
+	if description == "" {
+		description = name
+	}
+	version := agent.Version
+	if version == "" {
+		version = "1.0.0"
+	}
+
+	capabilities, err := grantedCapabilityTypes(s.capabilityRepo, agent.ID)
+	if err != nil {
+		return nil, err
+	}
+	identity := map[string]interface{}{
+		"agentId":         agent.ID.String(),
+		"organizationId":  agent.OrganizationID.String(),
+		"capabilities":    capabilities,
+		"trustScore":      agent.TrustScore,
+		"verificationUrl": s.VerificationURL(),
+	}
+	if agent.PublicKey != nil && *agent.PublicKey != "" {
+		identity["publicKey"] = *agent.PublicKey
+		identity["keyAlgorithm"] = agent.KeyAlgorithm
+	}
+
+	card := &domain.AgentCard{
+		ProtocolVersion:  domain.A2AProtocolVersion,
+		Name:             name,
+		Description:      description,
+		URL:              record.URL,
+		Version:          version,
+		DocumentationURL: agent.DocumentationURL,
+		Capabilities: domain.AgentCardCapabilities{
+			Streaming:         record.Streaming,
+			PushNotifications: record.PushNotifications,
+			Extensions: []domain.AgentExtension{{
+				URI:         domain.AgentCardIdentityExtension,
+				Description: "Identity, granted capabilities and trust score issued by Agent Identity Management",
+				Params:      identity,
+			}},
+		},
+		DefaultInputModes:  record.DefaultInputModes,
+		DefaultOutputModes: record.DefaultOutputModes,
+		Skills:             record.Skills,
+	}
+
+	if org, err := s.orgRepo.GetByID(agent.OrganizationID); err == nil && org != nil {
+		providerURL := s.issuer
+		if org.Domain != "" {
+			providerURL = "https://" + org.Domain
+		}
+		card.Provider = &domain.AgentProvider{Organization: org.Name, URL: providerURL}
+	}
+
+	return card, nil
+}
+
+// grantedCapabilityTypes returns the capability types currently granted to an agent
+func grantedCapabilityTypes(capabilityRepo domain.CapabilityRepository, agentID uuid.UUID) ([]string, error) {
+	granted, err := capabilityRepo.GetActiveCapabilitiesByAgentID(agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load capabilities: %w", err)
+	}
+	capabilities := make([]string, 0, len(granted))
+	for _, capability := range granted {
+		capabilities = append(capabilities, capability.CapabilityType)
+	}
+	return capabilities, nil
+}
+
+// sign adds a detached JWS over the canonical card, naming the card and agent in its header
+func (s *AgentCardService) sign(card *domain.AgentCard, record *domain.AgentCardRecord, agent *domain.Agent) error {
+	kid, alg, key, err := s.keys.CurrentSigningKey()
+	if err != nil {
+		return fmt.Errorf("failed to get signing key: %w", err)
+	}
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	now := s.now()
+	header, err := json.Marshal(agentCardHeader{
+		Alg: alg,
+		Kid: kid,
+		Typ: "JOSE",
+		Jku: s.issuer + "/.well-known/jwks.json",
+		Iss: s.issuer,
+		Sub: agent.ID.String(),
+		Jti: record.ID.String(),
+		Iat: now.Unix(),
+		Exp: now.Add(agentCardTTL).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode signature header: %w", err)
+	}
+
+	card.Signatures = nil
+	payload, err := canonicalJSON(card)
+	if err != nil {
+		return err
+	}
+
+	protected := base64.RawURLEncoding.EncodeToString(header)
+	signature, err := method.Sign(protected+"."+base64.RawURLEncoding.EncodeToString(payload), key)
+	if err != nil {
+		return fmt.Errorf("failed to sign agent card: %w", err)
+	}
+
+	card.Signatures = []domain.AgentCardSignature{{
+		Protected: protected,
+		Signature: base64.RawURLEncoding.EncodeToString(signature),
+	}}
+	return nil
+}
+
+// Verify checks a card's signature and issuer, whether it or its agent has been revoked, and the
+// agent's current trust score against minTrustScore. Problems are reported in the result; an
+// error means the check itself could not be completed.
+func (s *AgentCardService) Verify(ctx context.Context, rawCard json.RawMessage, minTrustScore *float64) (*domain.AgentCardVerification, error) {
+	result := &domain.AgentCardVerification{}
+	fail := func(format string, args ...interface{}) {
+		result.Errors = append(result.Errors, fmt.Sprintf(format, args...))
+	}
+
+	header, ok := s.verifySignature(rawCard, fail)
+	if !ok {
+		return result, nil
+	}
+
+	now := s.now()
+	issuedAt := time.Unix(header.Iat, 0).UTC()
+	expiresAt := time.Unix(header.Exp, 0).UTC()
+	result.Issuer = header.Iss
+	result.IssuedAt = &issuedAt
+	result.ExpiresAt = &expiresAt
+	if !now.Before(expiresAt) {
+		fail("card expired at %s", expiresAt.Format(time.RFC3339))
+	}
+	if issuedAt.After(now.Add(time.Minute)) {
+		fail("card is issued in the future")
+	}
+
+	cardID, err := uuid.Parse(header.Jti)
+	if err != nil {
+		fail("signature does not name a card")
+		return result, nil
+	}
+	agentID, err := uuid.Parse(header.Sub)
+	if err != nil {
+		fail("signature does not name an agent")
+		return result, nil
+	}
+	result.CardID = &cardID
+	result.AgentID = &agentID
+
+	record, err := s.cardRepo.GetByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.AgentID != agentID {
+		fail("card is not known to this issuer")
+		return result, nil
+	}
+	if record.RevokedAt != nil {
+		result.Revoked = true
+		fail("card was revoked at %s", record.RevokedAt.UTC().Format(time.RFC3339))
+	}
+
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil {
+		result.Revoked = true
+		fail("agent no longer exists")
+		return result, nil
+	}
+	trustScore := agent.TrustScore
+	result.AgentName = agent.Name
+	result.AgentStatus = agent.Status
+	result.TrustScore = &trustScore
+
+	switch {
+	case agent.Status == domain.AgentStatusRevoked || agent.Status == domain.AgentStatusSuspended:
+		result.Revoked = true
+		fail("agent is %s", agent.Status)
+	case agent.Status != domain.AgentStatusVerified:
+		fail("agent is not verified")
+	}
+	if agent.IsCompromised {
+		result.Revoked = true
+		fail("agent is marked as compromised")
+	}
+	if minTrustScore != nil && trustScore < *minTrustScore {
+		fail("trust score %.2f is below the required %.2f", trustScore, *minTrustScore)
+	}
+
+	if result.Capabilities, err = grantedCapabilityTypes(s.capabilityRepo, agentID); err != nil {
+		return nil, err
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result, nil
+}
+
+// verifySignature returns the protected header of the first card signature by this issuer that
+// verifies against the canonical card
+func (s *AgentCardService) verifySignature(rawCard json.RawMessage, fail func(string, ...interface{})) (*agentCardHeader, bool) {
+	var card map[string]interface{}
+	if err := json.Unmarshal(rawCard, &card); err != nil || card == nil {
+		fail("card is not a JSON object")
+		return nil, false
+	}
+
+	var signatures []domain.AgentCardSignature
+	if raw, ok := card["signatures"]; ok {
+		encoded, _ := json.Marshal(raw)
+		if err := json.Unmarshal(encoded, &signatures); err != nil {
+			fail("card signatures are malformed")
+			return nil, false
+		}
+	}
+	if len(signatures) == 0 {
+		fail("card is not signed")
+		return nil, false
+	}
+
+	delete(card, "signatures")
+	payload, err := canonicalJSON(card)
+	if err != nil {
+		fail("card cannot be canonicalized")
+		return nil, false
+	}
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+
+	for _, signature := range signatures {
+		rawHeader, err := base64.RawURLEncoding.DecodeString(signature.Protected)
+		if err != nil {
+			continue
+		}
+		header := &agentCardHeader{}
+		if err := json.Unmarshal(rawHeader, header); err != nil || header.Iss != s.issuer {
+			continue
+		}
+
+		alg, key, err := s.keys.VerificationKey(header.Kid)
+		if err != nil || alg != header.Alg {
+			continue
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(signature.Signature)
+		if err != nil {
+			continue
+		}
+		if err := jwt.GetSigningMethod(alg).Verify(signature.Protected+"."+encodedPayload, sig, key); err == nil {
+			return header, true
+		}
+	}
+
+	fail("card has no valid signature from %s", s.issuer)
+	return nil, false
+}
+
+// canonicalJSON serializes a value with JCS (RFC 8785): object keys sorted, no insignificant
+// whitespace and no HTML escaping
+func canonicalJSON(value interface{}) ([]byte, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil, fmt.Errorf("failed to normalize JSON: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(normalized); err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,222 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgentCardRepository keeps published cards in memory
+type fakeAgentCardRepository struct {
+	cards map[uuid.UUID]*domain.AgentCardRecord
+}
+
+func (r *fakeAgentCardRepository) Create(record *domain.AgentCardRecord) error {
+	copied := *record
+	r.cards[record.ID] = &copied
+	return nil
+}
+
+func (r *fakeAgentCardRepository) Update(record *domain.AgentCardRecord) error {
+	return r.Create(record)
+}
+
+func (r *fakeAgentCardRepository) GetByID(id uuid.UUID) (*domain.AgentCardRecord, error) {
+	if record, ok := r.cards[id]; ok {
+		copied := *record
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeAgentCardRepository) GetActiveByAgent(agentID uuid.UUID) (*domain.AgentCardRecord, error) {
+	for _, record := range r.cards {
+		if record.AgentID == agentID && record.RevokedAt == nil {
+			copied := *record
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAgentCardRepository) Revoke(id uuid.UUID, reason string, at time.Time) error {
+	r.cards[id].RevokedAt = &at
+	r.cards[id].RevokeReason = &reason
+	return nil
+}
+
+// createTestCardAgent creates a verified agent with an Ed25519 key
+func createTestCardAgent() *domain.Agent {
+	publicKey := "MCowBQYDK2VwAyEAGb9ECWmEzf6FQbrBZ9w7lshQhqowtrbLDFw4rXAxZuE="
+	return &domain.Agent{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Name:           "invoice-bot",
+		DisplayName:    "Invoice Bot",
+		Description:    "Reconciles supplier invoices",
+		Status:         domain.AgentStatusVerified,
+		Version:        "2.1.0",
+		PublicKey:      &publicKey,
+		KeyAlgorithm:   "Ed25519",
+		TrustScore:     0.82,
+	}
+}
+
+func createTestAgentCardService(t *testing.T, agent *domain.Agent) *AgentCardService {
+	clock := time.Now().UTC()
+	keys, _ := newTestSigningKeyService(t, "EdDSA", &clock)
+	require.NoError(t, keys.Rotate(context.Background()))
+
+	agentRepo := new(MockAgentRepository)
+	agentRepo.On("GetByID", agent.ID).Return(agent, nil)
+	capabilityRepo := new(MockCapabilityRepository)
+	capabilityRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{
+		{AgentID: agent.ID, CapabilityType: "invoices:read"},
+	}, nil)
+	orgRepo := new(MockOrganizationRepository)
+	orgRepo.On("GetByID", agent.OrganizationID).Return(&domain.Organization{ID: agent.OrganizationID, Name: "Acme", Domain: "acme.example.com"}, nil)
+
+	return NewAgentCardService(
+		&fakeAgentCardRepository{cards: map[uuid.UUID]*domain.AgentCardRecord{}},
+		agentRepo,
+		capabilityRepo,
+		orgRepo,
+		keys,
+		"https://aim.example.com/",
+	)
+}
+
+// publishTestAgentCard publishes a card for the agent and returns it as served
+func publishTestAgentCard(t *testing.T, service *AgentCardService, agent *domain.Agent) json.RawMessage {
+	_, err := service.Publish(context.Background(), agent.OrganizationID, agent.ID, uuid.New(), &AgentCardRequest{
+		URL: "https://invoice-bot.acme.example.com/a2a",
+		Skills: []domain.AgentSkill{{
+			ID:          "reconcile",
+			Name:        "Reconcile invoices",
+			Description: "Matches invoices against purchase orders",
+			Tags:        []string{"finance"},
+		}},
+	})
+	require.NoError(t, err)
+
+	card, err := service.SignedCard(context.Background(), agent.ID)
+	require.NoError(t, err)
+	raw, err := json.Marshal(card)
+	require.NoError(t, err)
+	return raw
+}
+
+func TestAgentCardService_SignedCardVerifies(t *testing.T) {
+	agent := createTestCardAgent()
+	service := createTestAgentCardService(t, agent)
+	raw := publishTestAgentCard(t, service, agent)
+	ctx := context.Background()
+
+	var card domain.AgentCard
+	require.NoError(t, json.Unmarshal(raw, &card))
+	assert.Equal(t, domain.A2AProtocolVersion, card.ProtocolVersion)
+	assert.Equal(t, "Invoice Bot", card.Name)
+	assert.Equal(t, "https://invoice-bot.acme.example.com/a2a", card.URL)
+	assert.Equal(t, "https://acme.example.com", card.Provider.URL)
+	assert.Equal(t, []string{"text/plain"}, card.DefaultInputModes)
+	require.Len(t, card.Skills, 1)
+	require.Len(t, card.Capabilities.Extensions, 1)
+	identity := card.Capabilities.Extensions[0]
+	assert.Equal(t, domain.AgentCardIdentityExtension, identity.URI)
+	assert.Equal(t, agent.ID.String(), identity.Params["agentId"])
+	assert.Equal(t, []interface{}{"invoices:read"}, identity.Params["capabilities"])
+	require.Len(t, card.Signatures, 1)
+
+	// Key order and whitespace do not matter: the signature covers the canonical card
+	var reordered map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &reordered))
+	indented, err := json.MarshalIndent(reordered, "", "    ")
+	require.NoError(t, err)
+
+	result, err := service.Verify(ctx, indented, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, result.Errors)
+	assert.Equal(t, "https://aim.example.com", result.Issuer)
+	assert.Equal(t, agent.ID, *result.AgentID)
+	assert.Equal(t, 0.82, *result.TrustScore)
+	assert.Equal(t, []string{"invoices:read"}, result.Capabilities)
+
+	// A tampered card fails
+	reordered["url"] = "https://attacker.example.com/a2a"
+	tampered, err := json.Marshal(reordered)
+	require.NoError(t, err)
+	result, err = service.Verify(ctx, tampered, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors, "card has no valid signature from https://aim.example.com")
+
+	// The verifier's trust threshold is checked against the current score
+	minimum := 0.9
+	result, err = service.Verify(ctx, raw, &minimum)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors, "trust score 0.82 is below the required 0.90")
+}
+
+func TestAgentCardService_RevocationAndExpiry(t *testing.T) {
+	agent := createTestCardAgent()
+	service := createTestAgentCardService(t, agent)
+	raw := publishTestAgentCard(t, service, agent)
+	ctx := context.Background()
+
+	// Cards expire a day after signing
+	service.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+	result, err := service.Verify(ctx, raw, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.False(t, result.Revoked)
+	service.now = time.Now
+
+	// Suspending the agent revokes its cards and stops serving new ones
+	agent.Status = domain.AgentStatusSuspended
+	result, err = service.Verify(ctx, raw, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.True(t, result.Revoked)
+	_, err = service.SignedCard(ctx, agent.ID)
+	require.ErrorIs(t, err, ErrAgentCardUnavailable)
+	agent.Status = domain.AgentStatusVerified
+
+	// Unpublishing revokes cards already handed out
+	require.NoError(t, service.Unpublish(ctx, agent.OrganizationID, agent.ID))
+	result, err = service.Verify(ctx, raw, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.True(t, result.Revoked)
+	_, err = service.SignedCard(ctx, agent.ID)
+	require.ErrorIs(t, err, ErrAgentCardNotFound)
+
+	// Other organizations cannot publish the agent
+	_, err = service.Publish(ctx, uuid.New(), agent.ID, uuid.New(), &AgentCardRequest{URL: "https://example.com"})
+	require.Error(t, err)
+}
+
+func TestAgentCardService_RejectsInvalidDeclarations(t *testing.T) {
+	agent := createTestCardAgent()
+	service := createTestAgentCardService(t, agent)
+	ctx := context.Background()
+
+	for _, req := range []*AgentCardRequest{
+		{URL: "invoice-bot"},
+		{URL: "ftp://invoice-bot.acme.example.com"},
+		{URL: "https://invoice-bot.acme.example.com", Skills: []domain.AgentSkill{{ID: "reconcile"}}},
+		{URL: "https://invoice-bot.acme.example.com", Skills: []domain.AgentSkill{
+			{ID: "reconcile", Name: "Reconcile"},
+			{ID: "reconcile", Name: "Reconcile again"},
+		}},
+	} {
+		_, err := service.Publish(ctx, agent.OrganizationID, agent.ID, uuid.New(), req)
+		require.ErrorIs(t, err, ErrInvalidAgentCard)
+	}
+}
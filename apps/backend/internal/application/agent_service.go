@@ -2,9 +2,8 @@ package application
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,6 +21,8 @@ type AgentService struct {
 	policyService          *SecurityPolicyService             // ✅ For policy-based enforcement
 	capabilityRepo         domain.CapabilityRepository        // ✅ For checking agent capabilities
 	verificationEventService *VerificationEventService        // ✅ For creating verification events
+	actionCatalog          *ActionCatalogService              // ✅ For action risk tiers and required capabilities
+	quotaService           *QuotaService                      // ✅ For the plan's agent quota
 }
 
 // NewAgentService creates a new agent service
@@ -34,6 +35,8 @@ func NewAgentService(
 	policyService *SecurityPolicyService,           // ✅ NEW: Security Policy Service
 	capabilityRepo domain.CapabilityRepository,     // ✅ NEW: CapabilityRepository for capability checks
 	verificationEventService *VerificationEventService, // ✅ NEW: For creating verification events
+	actionCatalog *ActionCatalogService,            // ✅ For action risk tiers and required capabilities
+	quotaService *QuotaService,                     // ✅ For the plan's agent quota
 ) *AgentService {
 	return &AgentService{
 		agentRepo:              agentRepo,
@@ -44,6 +47,8 @@ func NewAgentService(
 		policyService:          policyService,
 		capabilityRepo:         capabilityRepo,
 		verificationEventService: verificationEventService,
+		actionCatalog:          actionCatalog,
+		quotaService:           quotaService,
 	}
 }
 
@@ -73,6 +78,13 @@ func (s *AgentService) CreateAgent(ctx context.Context, req *CreateAgentRequest,
 		return nil, fmt.Errorf("invalid agent_type")
 	}
 
+	// Enforce the plan's agent quota
+	if s.quotaService != nil {
+		if err := s.quotaService.CheckQuota(ctx, orgID, domain.QuotaAgents); err != nil {
+			return nil, err
+		}
+	}
+
 	// ✅ AUTOMATIC KEY GENERATION - Zero effort for developers
 	// Generate Ed25519 key pair automatically
 	keyPair, err := crypto.GenerateEd25519KeyPair()
@@ -381,9 +393,19 @@ func (s *AgentService) HasCapability(ctx context.Context, agentID uuid.UUID, act
 		return false, nil
 	}
 
+	// Resolve the capability the action catalog requires for this action
+	var action *domain.ActionCatalogEntry
+	if s.actionCatalog != nil {
+		agent, err := s.agentRepo.GetByID(agentID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get agent: %w", err)
+		}
+		action = s.actionCatalog.Resolve(ctx, agent.OrganizationID, actionType)
+	}
+
 	// Check if action matches any capability
 	for _, capability := range capabilities {
-		if s.matchesCapability(actionType, resource, capability.CapabilityType) {
+		if s.capabilityAuthorizes(action, actionType, resource, capability.CapabilityType) {
 			return true, nil
 		}
 	}
@@ -419,6 +441,22 @@ func (s *AgentService) VerifyAction(
 		return false, "Agent is marked as compromised - all actions denied", auditID, nil
 	}
 
+	// 3b. Enforce the trust and approval requirements of the organization's action catalog
+	var action *domain.ActionCatalogEntry
+	if s.actionCatalog != nil {
+		action = s.actionCatalog.Resolve(ctx, agent.OrganizationID, actionType)
+
+		if trust := action.EffectiveTrust(agent.TrustScore); trust < action.RequiredTrust {
+			return false, fmt.Sprintf("Trust score %.2f below required %.2f for action %s", trust, action.RequiredTrust, actionType), auditID, nil
+		}
+		if action.RequiresApproval {
+			return false, fmt.Sprintf(
+				"Action %s is %s risk and requires human approval - request it through the verifications API",
+				actionType, action.RiskTier,
+			), auditID, nil
+		}
+	}
+
 	// 4. ✅ CAPABILITY-BASED ACCESS CONTROL (CBAC)
 	// This is what prevents EchoLeak and similar attacks
 	//
@@ -448,7 +486,7 @@ func (s *AgentService) VerifyAction(
 
 	for _, capability := range activeCapabilities {
 		capabilityTypes = append(capabilityTypes, capability.CapabilityType)
-		if s.matchesCapability(actionType, resource, capability.CapabilityType) {
+		if s.capabilityAuthorizes(action, actionType, resource, capability.CapabilityType) {
 			hasCapability = true
 		}
 	}
@@ -528,6 +566,21 @@ func (s *AgentService) VerifyAction(
 	return true, "Action matches registered capabilities", auditID, nil
 }
 
+// capabilityAuthorizes reports whether a granted capability covers an action, either by the
+// requested name, the catalog action it is an alias of, or the capability the catalog requires
+func (s *AgentService) capabilityAuthorizes(action *domain.ActionCatalogEntry, actionType, resource, capability string) bool {
+	if s.matchesCapability(actionType, resource, capability) {
+		return true
+	}
+	if action == nil {
+		return false
+	}
+	if action.Action != actionType && s.matchesCapability(action.Action, resource, capability) {
+		return true
+	}
+	return action.RequiredCapability != "" && s.matchesCapability(action.RequiredCapability, resource, capability)
+}
+
 // matchesCapability checks if an action matches a registered capability
 // Supports exact matching and wildcard patterns
 func (s *AgentService) matchesCapability(actionType string, resource string, capability string) bool {
@@ -779,21 +832,26 @@ func (s *AgentService) GetAgentMCPServers(
 // Auto-Detection of MCP Servers
 // ========================================
 
-// DetectMCPServersRequest represents request to auto-detect MCP servers from config
+// DetectMCPServersRequest represents request to auto-detect MCP servers from an uploaded client config
 type DetectMCPServersRequest struct {
-	ConfigPath   string `json:"config_path"`    // Path to Claude Desktop config file
-	AutoRegister bool   `json:"auto_register"`  // Whether to auto-register discovered MCPs
-	DryRun       bool   `json:"dry_run"`        // Preview changes without applying
+	ConfigContent string          `json:"config_content"`          // Contents of the AI client's MCP config file
+	ConfigFormat  MCPConfigFormat `json:"config_format,omitempty"` // claude_desktop, cursor, vscode, windsurf or continue; detected when empty
+	AutoRegister  bool            `json:"auto_register"`           // Whether to auto-register discovered MCPs
+	DryRun        bool            `json:"dry_run"`                 // Preview changes without applying
 }
 
 // DetectedMCPServer represents an MCP server detected from config
 type DetectedMCPServer struct {
 	Name       string                 `json:"name"`
-	Command    string                 `json:"command"`
-	Args       []string               `json:"args"`
-	Env        map[string]string      `json:"env,omitempty"`
-	Confidence float64                `json:"confidence"` // 0-100
-	Source     string                 `json:"source"`     // "claude_desktop_config"
+	Command    string                 `json:"command,omitempty"`
+	Args       []string               `json:"args,omitempty"`
+	Env        map[string]string      `json:"env,omitempty"` // Values are redacted
+	URL        string                 `json:"url,omitempty"` // Remote (sse/http) servers
+	Transport  string                 `json:"transport"`     // "stdio", "sse" or "http"
+	Package      *domain.MCPPackageRef `json:"package,omitempty"`       // stdio servers launched by npx, uvx, docker...
+	IdentityHash string                `json:"identity_hash,omitempty"` // stdio servers; see ComputeMCPStdioIdentity
+	Confidence float64                `json:"confidence"`    // 0-100
+	Source     string                 `json:"source"`        // "claude_desktop_config", "vscode_config", ...
 	Metadata   map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -803,11 +861,14 @@ type DetectMCPServersResult struct {
 	RegisteredCount  int                 `json:"registered_count"`
 	MappedCount      int                 `json:"mapped_count"`
 	TotalTalksTo     int                 `json:"total_talks_to"`
+	ConfigFormat     MCPConfigFormat     `json:"config_format"`
 	DryRun           bool                `json:"dry_run"`
+	IdentityDrift    []MCPIdentityDrift  `json:"identity_drift,omitempty"` // stdio servers whose launch spec changed
 	ErrorsEncountered []string           `json:"errors_encountered,omitempty"`
 }
 
-// DetectMCPServersFromConfig auto-detects MCP servers from Claude Desktop config
+// DetectMCPServersFromConfig auto-detects MCP servers from an uploaded Claude Desktop, Cursor,
+// VS Code, Windsurf or Continue config
 func (s *AgentService) DetectMCPServersFromConfig(
 	ctx context.Context,
 	agentID uuid.UUID,
@@ -816,39 +877,70 @@ func (s *AgentService) DetectMCPServersFromConfig(
 	orgID uuid.UUID,
 	userID uuid.UUID,
 ) (*DetectMCPServersResult, error) {
-	// 1. Validate request
-	if req.ConfigPath == "" {
-		return nil, fmt.Errorf("config_path is required")
-	}
-
-	// 2. Parse Claude Desktop config file
-	detectedServers, err := s.parseClaudeDesktopConfig(req.ConfigPath)
+	// 1. Parse the uploaded config
+	detectedServers, format, err := parseMCPClientConfig([]byte(req.ConfigContent), req.ConfigFormat)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+		return nil, err
 	}
 
-	// 3. If dry run, return immediately with detected servers
+	// 2. If dry run, return immediately with detected servers
 	if req.DryRun {
 		return &DetectMCPServersResult{
 			DetectedServers: detectedServers,
+			ConfigFormat:    format,
 			DryRun:          true,
 		}, nil
 	}
 
-	// 4. Auto-register new MCP servers if requested
+	// 3. Auto-register new MCP servers if requested
 	registeredCount := 0
 	mcpServerIdentifiers := []string{}
 	errorsEncountered := []string{}
+	identityDrift := []MCPIdentityDrift{}
 
 	if req.AutoRegister {
 		for _, detected := range detectedServers {
-			// Try to register the MCP server
-			// Note: CreateMCPServerRequest expects URL, but Claude config uses command/args
-			// We'll use the name as a placeholder URL for now
+			if detected.Transport == string(domain.MCPTransportStdio) {
+				envNames := make([]string, 0, len(detected.Env))
+				for name := range detected.Env {
+					envNames = append(envNames, name)
+				}
+
+				// Local servers are identified by their launch spec, not by a URL
+				registration, err := mcpService.RegisterStdioServer(ctx, &CreateMCPServerRequest{
+					Name:        detected.Name,
+					Description: fmt.Sprintf("Auto-detected from %s config. Command: %s", format, detected.Command),
+					Transport:   domain.MCPTransportStdio,
+					Command:     detected.Command,
+					Args:        detected.Args,
+					EnvNames:    envNames,
+					Package:     detected.Package,
+				}, orgID, userID)
+				if err != nil {
+					errorsEncountered = append(errorsEncountered,
+						fmt.Sprintf("MCP '%s': %v", detected.Name, err))
+					mcpServerIdentifiers = append(mcpServerIdentifiers, detected.Name)
+					continue
+				}
+
+				if registration.Created {
+					registeredCount++
+				}
+				if registration.Drift != nil {
+					identityDrift = append(identityDrift, *registration.Drift)
+					s.raiseMCPIdentityDriftAlert(agentID, orgID, registration.Drift)
+				}
+
+				// The same command line registered under another name is the same server
+				mcpServerIdentifiers = append(mcpServerIdentifiers, registration.Server.Name)
+				continue
+			}
+
 			registerReq := &CreateMCPServerRequest{
 				Name:        detected.Name,
-				Description: fmt.Sprintf("Auto-detected from Claude Desktop config. Command: %s", detected.Command),
-				URL:         fmt.Sprintf("mcp://%s", detected.Name), // Placeholder URL for local MCP servers
+				Description: fmt.Sprintf("Auto-detected from %s config (%s)", format, detected.Transport),
+				URL:         detected.URL,
+				Transport:   domain.MCPTransport(detected.Transport),
 			}
 
 			_, err := mcpService.CreateMCPServer(ctx, registerReq, orgID, userID)
@@ -869,71 +961,58 @@ func (s *AgentService) DetectMCPServersFromConfig(
 		}
 	}
 
-	// 5. Add detected MCP servers to agent's talks_to list
+	// 4. Add detected MCP servers to agent's talks_to list
 	agent, addedServers, err := s.AddMCPServers(ctx, agentID, mcpServerIdentifiers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to map MCP servers to agent: %w", err)
 	}
 
-	// 6. Return results
+	// 5. Return results
 	return &DetectMCPServersResult{
 		DetectedServers:   detectedServers,
 		RegisteredCount:   registeredCount,
 		MappedCount:       len(addedServers),
 		TotalTalksTo:      len(agent.TalksTo),
+		ConfigFormat:      format,
 		DryRun:            false,
+		IdentityDrift:     identityDrift,
 		ErrorsEncountered: errorsEncountered,
 	}, nil
 }
 
-// parseClaudeDesktopConfig parses Claude Desktop config JSON file
-func (s *AgentService) parseClaudeDesktopConfig(configPath string) ([]DetectedMCPServer, error) {
-	// Expand tilde (~) in path to home directory
-	if len(configPath) > 0 && configPath[0] == '~' {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get user home directory: %w", err)
-		}
-		configPath = homeDir + configPath[1:]
+// raiseMCPIdentityDriftAlert alerts on a stdio server launched with a different command line than
+// the one it was registered with, which can mean a swapped package or binary
+func (s *AgentService) raiseMCPIdentityDriftAlert(agentID, orgID uuid.UUID, drift *MCPIdentityDrift) {
+	if s.alertRepo == nil {
+		return
 	}
 
-	// Read config file
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
-
-	// Parse JSON
-	var config struct {
-		MCPServers map[string]struct {
-			Command string            `json:"command"`
-			Args    []string          `json:"args"`
-			Env     map[string]string `json:"env"`
-		} `json:"mcpServers"`
-	}
-
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config JSON: %w", err)
-	}
-
-	// Convert to DetectedMCPServer structs
-	detectedServers := []DetectedMCPServer{}
-	for name, serverConfig := range config.MCPServers {
-		detected := DetectedMCPServer{
-			Name:       name,
-			Command:    serverConfig.Command,
-			Args:       serverConfig.Args,
-			Env:        serverConfig.Env,
-			Confidence: 100.0, // High confidence for config file detection
-			Source:     "claude_desktop_config",
-			Metadata: map[string]interface{}{
-				"config_path": configPath,
-			},
-		}
-		detectedServers = append(detectedServers, detected)
+	agentName := agentID.String()
+	if agent, err := s.agentRepo.GetByID(agentID); err == nil && agent != nil {
+		agentName = agent.DisplayName
+	}
+
+	alert := &domain.Alert{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		AlertType:      domain.AlertTypeConfigurationDrift,
+		Severity:       domain.AlertSeverityHigh,
+		Title:          fmt.Sprintf("MCP Server Launch Command Changed: %s", drift.Name),
+		Description: fmt.Sprintf(
+			"Agent '%s' launches local MCP server '%s' with a different %s than the registered one.\n\n"+
+				"**Registered identity:** %s\n**Detected identity:** %s\n\n"+
+				"Review the agent's MCP config. If the change is legitimate, re-register the server.",
+			agentName, drift.Name, strings.Join(drift.ChangedFields, ", "), drift.RegisteredIdentity, drift.DetectedIdentity,
+		),
+		ResourceType:   "mcp_server",
+		ResourceID:     drift.ServerID,
+		IsAcknowledged: false,
+		CreatedAt:      time.Now(),
 	}
 
-	return detectedServers, nil
+	if err := s.alertRepo.Create(alert); err != nil {
+		fmt.Printf("⚠️  Failed to create MCP identity drift alert for %s: %v\n", drift.Name, err)
+	}
 }
 
 // GetAgentByName retrieves an agent by name within an organization
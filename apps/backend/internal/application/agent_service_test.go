@@ -72,6 +72,14 @@ func (m *AgentServiceMockTrustScoreRepository) GetHistory(agentID uuid.UUID, lim
 	return args.Get(0).([]*domain.TrustScore), args.Error(1)
 }
 
+func (m *AgentServiceMockTrustScoreRepository) GetHistoryAuditTrail(agentID uuid.UUID, limit int) ([]*domain.TrustScoreHistoryEntry, error) {
+	args := m.Called(agentID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TrustScoreHistoryEntry), args.Error(1)
+}
+
 // AgentServiceMockSecurityPolicyRepository for testing
 type AgentServiceMockSecurityPolicyRepository struct {
 	mock.Mock
@@ -0,0 +1,568 @@
+package application
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/auth"
+)
+
+// OAuth 2.0 error codes (RFC 6749 section 5.2, RFC 8693 section 2.2.2)
+const (
+	OAuthErrorInvalidRequest       = "invalid_request"
+	OAuthErrorInvalidClient        = "invalid_client"
+	OAuthErrorInvalidGrant         = "invalid_grant"
+	OAuthErrorUnsupportedGrantType = "unsupported_grant_type"
+	OAuthErrorInvalidScope         = "invalid_scope"
+)
+
+// maxClientAssertionLifetime bounds how far in the future a client assertion may expire, which
+// also bounds how long its jti must be remembered for replay detection
+const maxClientAssertionLifetime = 10 * time.Minute
+
+// OAuthError is an OAuth 2.0 error response
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func oauthError(code, format string, args ...interface{}) *OAuthError {
+	return &OAuthError{Code: code, Description: fmt.Sprintf(format, args...)}
+}
+
+// AgentTokenRequest is a token endpoint request. Clients authenticate with private_key_jwt:
+// a client assertion signed with the agent's registered Ed25519 key.
+type AgentTokenRequest struct {
+	GrantType           string
+	ClientID            string
+	ClientAssertionType string
+	ClientAssertion     string
+	Scope               string // Space-separated capabilities; empty requests all of them
+	Audience            string // "audience" or "resource" parameter; defaults to the issuer
+	SubjectToken        string // Token exchange: the user access token the agent acts on behalf of
+	SubjectTokenType    string
+}
+
+// AgentTokenResponse is a token endpoint response
+type AgentTokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+	Scope           string `json:"scope,omitempty"`
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+}
+
+// AgentTokenClaims are the claims of an agent access token (RFC 9068 JWT profile)
+type AgentTokenClaims struct {
+	ClientID       string                 `json:"client_id"`
+	Scope          string                 `json:"scope,omitempty"`
+	AgentID        string                 `json:"agent_id"`
+	OrganizationID string                 `json:"organization_id"`
+	Capabilities   []string               `json:"capabilities"`
+	TrustScore     float64                `json:"trust_score"`
+	Actor          map[string]interface{} `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// AgentTokenService is an OAuth 2.0 authorization server for agents. It issues short-lived,
+// capability-scoped JWT access tokens through the client credentials grant and, for agents
+// acting on behalf of a user, through token exchange. Tokens are signed with an Ed25519 key
+// published in the JWKS so resource servers can verify them offline.
+type AgentTokenService struct {
+	agentRepo      domain.AgentRepository
+	capabilityRepo domain.CapabilityRepository
+	userRepo       domain.UserRepository
+	tokenRepo      domain.AgentTokenRepository
+	jwtService     *auth.JWTService
+	signingKey     ed25519.PrivateKey
+	trustedKeys    []ed25519.PublicKey
+	issuer         string
+	ttl            time.Duration
+	now            func() time.Time
+}
+
+// NewAgentTokenService creates a new agent token service
+func NewAgentTokenService(
+	agentRepo domain.AgentRepository,
+	capabilityRepo domain.CapabilityRepository,
+	userRepo domain.UserRepository,
+	tokenRepo domain.AgentTokenRepository,
+	jwtService *auth.JWTService,
+	signingKey ed25519.PrivateKey,
+	trustedKeys []ed25519.PublicKey,
+	issuer string,
+	ttl time.Duration,
+) *AgentTokenService {
+	return &AgentTokenService{
+		agentRepo:      agentRepo,
+		capabilityRepo: capabilityRepo,
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		jwtService:     jwtService,
+		signingKey:     signingKey,
+		trustedKeys:    trustedKeys,
+		issuer:         strings.TrimSuffix(issuer, "/"),
+		ttl:            ttl,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// LoadAgentTokenSigningKey decodes a base64 Ed25519 seed or private key, generating an
+// ephemeral key when none is configured
+func LoadAgentTokenSigningKey(encoded string) (ed25519.PrivateKey, error) {
+	if encoded == "" {
+		fmt.Println("⚠️  AGENT_TOKEN_SIGNING_KEY not set, generating ephemeral agent token signing key (development only)")
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate agent token signing key: %w", err)
+		}
+		return privateKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode agent token signing key: %w", err)
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("invalid agent token signing key size: expected %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+}
+
+// JWKThumbprint returns the RFC 7638 thumbprint of an Ed25519 public key, used as its "kid"
+func JWKThumbprint(publicKey ed25519.PublicKey) string {
+	jwk, _ := jsonWebKey("EdDSA", publicKey)
+	return jwk.KeyID
+}
+
+// Issuer returns the authorization server's issuer identifier
+func (s *AgentTokenService) Issuer() string {
+	return s.issuer
+}
+
+// TokenEndpoint returns the URL client assertions may name as their audience
+func (s *AgentTokenService) TokenEndpoint() string {
+	return s.issuer + "/api/v1/oauth/token"
+}
+
+// JWKS returns the current signing key and the trusted previous keys
+func (s *AgentTokenService) JWKS() *domain.JSONWebKeySet {
+	set := &domain.JSONWebKeySet{Keys: []domain.JSONWebKey{}}
+	for _, key := range s.verificationKeys() {
+		jwk, _ := jsonWebKey("EdDSA", key)
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func (s *AgentTokenService) verificationKeys() []ed25519.PublicKey {
+	keys := []ed25519.PublicKey{s.signingKey.Public().(ed25519.PublicKey)}
+	return append(keys, s.trustedKeys...)
+}
+
+// IssueToken handles a token endpoint request
+func (s *AgentTokenService) IssueToken(ctx context.Context, req AgentTokenRequest) (*AgentTokenResponse, error) {
+	if req.GrantType != domain.GrantTypeClientCredentials && req.GrantType != domain.GrantTypeTokenExchange {
+		return nil, oauthError(OAuthErrorUnsupportedGrantType, "grant_type %q is not supported", req.GrantType)
+	}
+
+	agent, err := s.AuthenticateClient(ctx, req.ClientID, req.ClientAssertionType, req.ClientAssertion)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := s.grantScope(agent, req.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	audience := strings.Fields(req.Audience)
+	if len(audience) == 0 {
+		audience = []string{s.issuer}
+	}
+
+	now := s.now()
+	record := &domain.AgentAccessToken{
+		ID:             uuid.New(),
+		AgentID:        agent.ID,
+		OrganizationID: agent.OrganizationID,
+		GrantType:      req.GrantType,
+		Subject:        agent.ID.String(),
+		Scope:          scope,
+		Audience:       audience,
+		IssuedAt:       now,
+		ExpiresAt:      now.Add(s.ttl),
+	}
+
+	var actor map[string]interface{}
+	issuedTokenType := ""
+	if req.GrantType == domain.GrantTypeTokenExchange {
+		user, subjectExpiry, err := s.validateSubjectToken(agent, req.SubjectToken, req.SubjectTokenType)
+		if err != nil {
+			return nil, err
+		}
+		record.Subject = user.ID.String()
+		record.ActorUserID = &user.ID
+		if subjectExpiry.Before(record.ExpiresAt) {
+			record.ExpiresAt = subjectExpiry
+		}
+		actor = map[string]interface{}{"sub": agent.ID.String()}
+		issuedTokenType = domain.TokenTypeAccessToken
+	}
+
+	accessToken, err := s.sign(agent, record, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.Create(record); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("🎫 Issued %s access token %s to agent %s (scope: %s)\n", req.GrantType, record.ID, agent.ID, strings.Join(scope, " "))
+
+	return &AgentTokenResponse{
+		AccessToken:     accessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(record.ExpiresAt.Sub(now).Seconds()),
+		Scope:           strings.Join(scope, " "),
+		IssuedTokenType: issuedTokenType,
+	}, nil
+}
+
+// AuthenticateClient verifies a private_key_jwt client assertion (RFC 7523) and returns the
+// agent it identifies. The assertion must be signed with the agent's registered key (or its
+// previous key during a rotation grace period), name the agent as iss and sub, name this server
+// as audience, expire within ten minutes and carry a jti that was never used before.
+func (s *AgentTokenService) AuthenticateClient(ctx context.Context, clientID, assertionType, assertion string) (*domain.Agent, error) {
+	if assertionType != domain.ClientAssertionTypeJWT || assertion == "" {
+		return nil, oauthError(OAuthErrorInvalidClient, "client authentication requires a %s client assertion", domain.ClientAssertionTypeJWT)
+	}
+
+	var agent *domain.Agent
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(assertion, claims, func(token *jwt.Token) (interface{}, error) {
+		if claims.Issuer == "" || claims.Issuer != claims.Subject {
+			return nil, errors.New("iss and sub must both be the client_id")
+		}
+		if clientID != "" && clientID != claims.Subject {
+			return nil, errors.New("client_id does not match the assertion subject")
+		}
+		agentID, err := uuid.Parse(claims.Subject)
+		if err != nil {
+			return nil, errors.New("unknown client")
+		}
+		agent, err = s.agentRepo.GetByID(agentID)
+		if err != nil || agent == nil {
+			return nil, errors.New("unknown client")
+		}
+		keys := s.agentPublicKeys(agent)
+		if len(keys.Keys) == 0 {
+			return nil, errors.New("client has no registered public key")
+		}
+		return keys, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.now),
+		jwt.WithLeeway(30*time.Second),
+	)
+	if err != nil {
+		return nil, oauthError(OAuthErrorInvalidClient, "invalid client assertion: %v", err)
+	}
+
+	if !audienceContains(claims.Audience, s.issuer, s.TokenEndpoint()) {
+		return nil, oauthError(OAuthErrorInvalidClient, "client assertion audience must be %s", s.TokenEndpoint())
+	}
+	if claims.ExpiresAt.Time.After(s.now().Add(maxClientAssertionLifetime)) {
+		return nil, oauthError(OAuthErrorInvalidClient, "client assertion may not be valid for more than %s", maxClientAssertionLifetime)
+	}
+	if claims.ID == "" {
+		return nil, oauthError(OAuthErrorInvalidClient, "client assertion requires a jti")
+	}
+	if agent.Status != domain.AgentStatusVerified || agent.IsCompromised {
+		return nil, oauthError(OAuthErrorInvalidClient, "agent is not verified")
+	}
+
+	fresh, err := s.tokenRepo.RecordAssertion(agent.ID, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		fmt.Printf("🚨 Replayed client assertion %s for agent %s\n", claims.ID, agent.ID)
+		return nil, oauthError(OAuthErrorInvalidClient, "client assertion has already been used")
+	}
+
+	return agent, nil
+}
+
+// agentPublicKeys returns the keys an agent's assertions may be signed with
+func (s *AgentTokenService) agentPublicKeys(agent *domain.Agent) jwt.VerificationKeySet {
+	encoded := []*string{agent.PublicKey}
+	if agent.KeyRotationGraceUntil != nil && s.now().Before(*agent.KeyRotationGraceUntil) {
+		encoded = append(encoded, agent.PreviousPublicKey)
+	}
+
+	set := jwt.VerificationKeySet{}
+	for _, value := range encoded {
+		if value == nil || *value == "" {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(*value)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			continue
+		}
+		set.Keys = append(set.Keys, ed25519.PublicKey(raw))
+	}
+	return set
+}
+
+// grantScope intersects the requested scope with the agent's active capabilities
+func (s *AgentTokenService) grantScope(agent *domain.Agent, requested string) ([]string, error) {
+	capabilities, err := s.capabilityRepo.GetActiveCapabilitiesByAgentID(agent.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent capabilities: %w", err)
+	}
+
+	granted := make(map[string]bool, len(capabilities))
+	for _, capability := range capabilities {
+		granted[capability.CapabilityType] = true
+	}
+
+	fields := strings.Fields(requested)
+	if len(fields) == 0 {
+		scope := make([]string, 0, len(granted))
+		for capability := range granted {
+			scope = append(scope, capability)
+		}
+		sort.Strings(scope)
+		return scope, nil
+	}
+
+	scope := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !granted[field] {
+			return nil, oauthError(OAuthErrorInvalidScope, "agent has no active %q capability", field)
+		}
+		if !seen[field] {
+			seen[field] = true
+			scope = append(scope, field)
+		}
+	}
+	return scope, nil
+}
+
+// validateSubjectToken checks the user access token of a token exchange request and returns
+// the user and the token's expiry
+func (s *AgentTokenService) validateSubjectToken(agent *domain.Agent, token, tokenType string) (*domain.User, time.Time, error) {
+	if token == "" {
+		return nil, time.Time{}, oauthError(OAuthErrorInvalidRequest, "subject_token is required")
+	}
+	if tokenType != domain.TokenTypeAccessToken && tokenType != domain.TokenTypeJWT {
+		return nil, time.Time{}, oauthError(OAuthErrorInvalidRequest, "subject_token_type must be %s", domain.TokenTypeAccessToken)
+	}
+	if s.jwtService == nil {
+		return nil, time.Time{}, oauthError(OAuthErrorInvalidGrant, "token exchange is not available")
+	}
+
+	claims, err := s.jwtService.ValidateToken(token)
+	if err != nil || claims.ExpiresAt == nil {
+		return nil, time.Time{}, oauthError(OAuthErrorInvalidGrant, "subject_token is invalid or expired")
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, time.Time{}, oauthError(OAuthErrorInvalidGrant, "subject_token does not identify a user")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user == nil {
+		return nil, time.Time{}, oauthError(OAuthErrorInvalidGrant, "subject_token user not found")
+	}
+	if user.Status != domain.UserStatusActive {
+		return nil, time.Time{}, oauthError(OAuthErrorInvalidGrant, "subject_token user is not active")
+	}
+	if user.OrganizationID != agent.OrganizationID {
+		return nil, time.Time{}, oauthError(OAuthErrorInvalidGrant, "agent and user belong to different organizations")
+	}
+
+	return user, claims.ExpiresAt.Time, nil
+}
+
+func (s *AgentTokenService) sign(agent *domain.Agent, record *domain.AgentAccessToken, actor map[string]interface{}) (string, error) {
+	claims := AgentTokenClaims{
+		ClientID:       agent.ID.String(),
+		Scope:          strings.Join(record.Scope, " "),
+		AgentID:        agent.ID.String(),
+		OrganizationID: agent.OrganizationID.String(),
+		Capabilities:   record.Scope,
+		TrustScore:     agent.TrustScore,
+		Actor:          actor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   record.Subject,
+			Audience:  record.Audience,
+			ID:        record.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(record.IssuedAt),
+			NotBefore: jwt.NewNumericDate(record.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(record.ExpiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["typ"] = "at+jwt"
+	token.Header["kid"] = JWKThumbprint(s.signingKey.Public().(ed25519.PublicKey))
+
+	signed, err := token.SignedString(s.signingKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign agent access token: %w", err)
+	}
+	return signed, nil
+}
+
+// parseAccessToken verifies the signature, issuer and lifetime of an agent access token
+func (s *AgentTokenService) parseAccessToken(token string) (*AgentTokenClaims, error) {
+	claims := &AgentTokenClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		for _, key := range s.verificationKeys() {
+			if JWKThumbprint(key) == kid {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.now),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Introspect reports whether an access token is active (RFC 7662). Only tokens issued within
+// the calling agent's organization are disclosed; anything else is reported inactive.
+func (s *AgentTokenService) Introspect(ctx context.Context, caller *domain.Agent, token string) *domain.TokenIntrospection {
+	inactive := &domain.TokenIntrospection{Active: false}
+
+	claims, err := s.parseAccessToken(token)
+	if err != nil || claims.OrganizationID != caller.OrganizationID.String() {
+		return inactive
+	}
+	record, err := s.lookup(claims)
+	if err != nil || record == nil || !record.IsActive(s.now()) {
+		return inactive
+	}
+
+	// A token dies with its agent's verification
+	agent, err := s.agentRepo.GetByID(record.AgentID)
+	if err != nil || agent == nil || agent.Status != domain.AgentStatusVerified || agent.IsCompromised {
+		return inactive
+	}
+
+	trustScore := agent.TrustScore
+	return &domain.TokenIntrospection{
+		Active:         true,
+		Scope:          claims.Scope,
+		ClientID:       claims.ClientID,
+		TokenType:      "Bearer",
+		Subject:        claims.Subject,
+		Audience:       claims.Audience,
+		Issuer:         claims.Issuer,
+		ID:             claims.ID,
+		IssuedAt:       claims.IssuedAt.Unix(),
+		NotBefore:      claims.NotBefore.Unix(),
+		ExpiresAt:      claims.ExpiresAt.Unix(),
+		AgentID:        claims.AgentID,
+		OrganizationID: claims.OrganizationID,
+		Capabilities:   claims.Capabilities,
+		TrustScore:     &trustScore,
+		Actor:          claims.Actor,
+	}
+}
+
+func (s *AgentTokenService) lookup(claims *AgentTokenClaims) (*domain.AgentAccessToken, error) {
+	id, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.tokenRepo.GetByID(id)
+}
+
+// Revoke revokes an access token (RFC 7009). Agents may only revoke their own tokens; invalid
+// and foreign tokens are ignored, as the RFC requires the endpoint to respond identically.
+func (s *AgentTokenService) Revoke(ctx context.Context, caller *domain.Agent, token string) error {
+	claims, err := s.parseAccessToken(token)
+	if err != nil || claims.AgentID != caller.ID.String() {
+		return nil
+	}
+	record, err := s.lookup(claims)
+	if err != nil || record == nil {
+		return err
+	}
+
+	if err := s.tokenRepo.Revoke(record.ID, s.now()); err != nil {
+		return err
+	}
+	fmt.Printf("🔒 Agent %s revoked access token %s\n", caller.ID, record.ID)
+	return nil
+}
+
+// RevokeAgentTokens revokes every active access token of an agent in the organization
+func (s *AgentTokenService) RevokeAgentTokens(ctx context.Context, orgID, agentID uuid.UUID) (int64, error) {
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil || agent.OrganizationID != orgID {
+		return 0, fmt.Errorf("agent not found")
+	}
+
+	revoked, err := s.tokenRepo.RevokeByAgent(agentID, s.now())
+	if err != nil {
+		return 0, err
+	}
+	fmt.Printf("🔒 Revoked %d access tokens of agent %s\n", revoked, agentID)
+	return revoked, nil
+}
+
+// CleanupExpired deletes expired access tokens and client assertion IDs
+func (s *AgentTokenService) CleanupExpired(ctx context.Context) error {
+	deleted, err := s.tokenRepo.DeleteExpired(s.now())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		fmt.Printf("🧹 Deleted %d expired agent access tokens\n", deleted)
+	}
+	return nil
+}
+
+func audienceContains(values []string, candidates ...string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if value == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,301 @@
+package application
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgentTokenRepository keeps issued tokens and used assertion IDs in memory
+type fakeAgentTokenRepository struct {
+	tokens     map[uuid.UUID]*domain.AgentAccessToken
+	assertions map[string]bool
+}
+
+func newFakeAgentTokenRepository() *fakeAgentTokenRepository {
+	return &fakeAgentTokenRepository{
+		tokens:     map[uuid.UUID]*domain.AgentAccessToken{},
+		assertions: map[string]bool{},
+	}
+}
+
+func (r *fakeAgentTokenRepository) Create(token *domain.AgentAccessToken) error {
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *fakeAgentTokenRepository) GetByID(id uuid.UUID) (*domain.AgentAccessToken, error) {
+	return r.tokens[id], nil
+}
+
+func (r *fakeAgentTokenRepository) Revoke(id uuid.UUID, at time.Time) error {
+	if token, ok := r.tokens[id]; ok && token.RevokedAt == nil {
+		token.RevokedAt = &at
+	}
+	return nil
+}
+
+func (r *fakeAgentTokenRepository) RevokeByAgent(agentID uuid.UUID, at time.Time) (int64, error) {
+	var revoked int64
+	for _, token := range r.tokens {
+		if token.AgentID == agentID && token.IsActive(at) {
+			token.RevokedAt = &at
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *fakeAgentTokenRepository) RecordAssertion(agentID uuid.UUID, jti string, expiresAt time.Time) (bool, error) {
+	key := agentID.String() + "/" + jti
+	if r.assertions[key] {
+		return false, nil
+	}
+	r.assertions[key] = true
+	return true, nil
+}
+
+func (r *fakeAgentTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// createTestTokenAgent creates a verified agent and the private key it signs assertions with
+func createTestTokenAgent(t *testing.T) (*domain.Agent, ed25519.PrivateKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	encoded := base64.StdEncoding.EncodeToString(publicKey)
+
+	return &domain.Agent{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Name:           "billing-bot",
+		Status:         domain.AgentStatusVerified,
+		PublicKey:      &encoded,
+		TrustScore:     0.82,
+	}, privateKey
+}
+
+func createTestAgentTokenService(t *testing.T, agent *domain.Agent) (*AgentTokenService, *fakeAgentTokenRepository, *MockUserRepository) {
+	t.Setenv("JWT_SECRET", "agent-token-test-secret")
+
+	signingKey, err := LoadAgentTokenSigningKey("")
+	require.NoError(t, err)
+
+	agentRepo := new(MockAgentRepository)
+	agentRepo.On("GetByID", agent.ID).Return(agent, nil)
+	capabilityRepo := new(MockCapabilityRepository)
+	capabilityRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{
+		{ID: uuid.New(), AgentID: agent.ID, CapabilityType: "file:read"},
+		{ID: uuid.New(), AgentID: agent.ID, CapabilityType: "api:call"},
+	}, nil)
+	userRepo := new(MockUserRepository)
+
+	tokens := newFakeAgentTokenRepository()
+	service := NewAgentTokenService(
+		agentRepo,
+		capabilityRepo,
+		userRepo,
+		tokens,
+		auth.NewJWTService(),
+		signingKey,
+		nil,
+		"https://aim.example.com/",
+		15*time.Minute,
+	)
+
+	return service, tokens, userRepo
+}
+
+// signAgentAssertion signs a private_key_jwt client assertion for the agent
+func signAgentAssertion(t *testing.T, service *AgentTokenService, agent *domain.Agent, key ed25519.PrivateKey, jti string) string {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.RegisteredClaims{
+		Issuer:    agent.ID.String(),
+		Subject:   agent.ID.String(),
+		Audience:  jwt.ClaimStrings{service.TokenEndpoint()},
+		ID:        jti,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(2 * time.Minute)),
+	})
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func createTestTokenRequest(t *testing.T, service *AgentTokenService, agent *domain.Agent, key ed25519.PrivateKey, grantType, scope string) AgentTokenRequest {
+	return AgentTokenRequest{
+		GrantType:           grantType,
+		ClientID:            agent.ID.String(),
+		ClientAssertionType: domain.ClientAssertionTypeJWT,
+		ClientAssertion:     signAgentAssertion(t, service, agent, key, uuid.NewString()),
+		Scope:               scope,
+	}
+}
+
+func requireOAuthError(t *testing.T, err error, code string) {
+	t.Helper()
+	var oauthErr *OAuthError
+	require.True(t, errors.As(err, &oauthErr), "expected OAuth error, got %v", err)
+	assert.Equal(t, code, oauthErr.Code)
+}
+
+func TestAgentTokenService_ClientCredentials(t *testing.T) {
+	agent, agentKey := createTestTokenAgent(t)
+	service, tokens, _ := createTestAgentTokenService(t, agent)
+	ctx := context.Background()
+
+	resp, err := service.IssueToken(ctx, createTestTokenRequest(t, service, agent, agentKey, domain.GrantTypeClientCredentials, ""))
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, int64(900), resp.ExpiresIn)
+	assert.Equal(t, "api:call file:read", resp.Scope)
+
+	claims, err := service.parseAccessToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "https://aim.example.com", claims.Issuer)
+	assert.Equal(t, agent.ID.String(), claims.Subject)
+	assert.Equal(t, agent.ID.String(), claims.AgentID)
+	assert.Equal(t, agent.OrganizationID.String(), claims.OrganizationID)
+	assert.Equal(t, []string{"api:call", "file:read"}, claims.Capabilities)
+	assert.Equal(t, 0.82, claims.TrustScore)
+	assert.Nil(t, claims.Actor)
+	assert.Contains(t, tokens.tokens, uuid.MustParse(claims.ID))
+}
+
+func TestAgentTokenService_RejectsInvalidAssertions(t *testing.T) {
+	agent, agentKey := createTestTokenAgent(t)
+	service, _, _ := createTestAgentTokenService(t, agent)
+	ctx := context.Background()
+
+	// Replayed assertion
+	req := createTestTokenRequest(t, service, agent, agentKey, domain.GrantTypeClientCredentials, "")
+	_, err := service.IssueToken(ctx, req)
+	require.NoError(t, err)
+	_, err = service.IssueToken(ctx, req)
+	requireOAuthError(t, err, OAuthErrorInvalidClient)
+
+	// Signed with another key
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, err = service.IssueToken(ctx, createTestTokenRequest(t, service, agent, otherKey, domain.GrantTypeClientCredentials, ""))
+	requireOAuthError(t, err, OAuthErrorInvalidClient)
+
+	// Unverified agent
+	agent.Status = domain.AgentStatusSuspended
+	_, err = service.IssueToken(ctx, createTestTokenRequest(t, service, agent, agentKey, domain.GrantTypeClientCredentials, ""))
+	requireOAuthError(t, err, OAuthErrorInvalidClient)
+}
+
+func TestAgentTokenService_ScopeLimitedToCapabilities(t *testing.T) {
+	agent, agentKey := createTestTokenAgent(t)
+	service, _, _ := createTestAgentTokenService(t, agent)
+	ctx := context.Background()
+
+	resp, err := service.IssueToken(ctx, createTestTokenRequest(t, service, agent, agentKey, domain.GrantTypeClientCredentials, "file:read"))
+	require.NoError(t, err)
+	assert.Equal(t, "file:read", resp.Scope)
+
+	_, err = service.IssueToken(ctx, createTestTokenRequest(t, service, agent, agentKey, domain.GrantTypeClientCredentials, "file:read db:write"))
+	requireOAuthError(t, err, OAuthErrorInvalidScope)
+}
+
+func TestAgentTokenService_TokenExchange(t *testing.T) {
+	agent, agentKey := createTestTokenAgent(t)
+	service, _, userRepo := createTestAgentTokenService(t, agent)
+	user := &domain.User{ID: uuid.New(), OrganizationID: agent.OrganizationID, Email: "alice@acme.example.com", Status: domain.UserStatusActive}
+	userRepo.On("GetByID", user.ID).Return(user, nil)
+	ctx := context.Background()
+
+	userToken, err := auth.NewJWTService().GenerateAccessToken(user.ID.String(), user.OrganizationID.String(), user.Email, "member")
+	require.NoError(t, err)
+
+	req := createTestTokenRequest(t, service, agent, agentKey, domain.GrantTypeTokenExchange, "api:call")
+	req.SubjectToken = userToken
+	req.SubjectTokenType = domain.TokenTypeAccessToken
+	resp, err := service.IssueToken(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TokenTypeAccessToken, resp.IssuedTokenType)
+
+	claims, err := service.parseAccessToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID.String(), claims.Subject)
+	assert.Equal(t, map[string]interface{}{"sub": agent.ID.String()}, claims.Actor)
+	assert.Equal(t, []string{"api:call"}, claims.Capabilities)
+
+	// Users of another organization cannot be impersonated
+	user.OrganizationID = uuid.New()
+	req = createTestTokenRequest(t, service, agent, agentKey, domain.GrantTypeTokenExchange, "")
+	req.SubjectToken = userToken
+	req.SubjectTokenType = domain.TokenTypeAccessToken
+	_, err = service.IssueToken(ctx, req)
+	requireOAuthError(t, err, OAuthErrorInvalidGrant)
+}
+
+func TestAgentTokenService_IntrospectAndRevoke(t *testing.T) {
+	agent, agentKey := createTestTokenAgent(t)
+	service, _, _ := createTestAgentTokenService(t, agent)
+	ctx := context.Background()
+
+	resp, err := service.IssueToken(ctx, createTestTokenRequest(t, service, agent, agentKey, domain.GrantTypeClientCredentials, ""))
+	require.NoError(t, err)
+
+	introspection := service.Introspect(ctx, agent, resp.AccessToken)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, agent.ID.String(), introspection.ClientID)
+	require.NotNil(t, introspection.TrustScore)
+	assert.Equal(t, 0.82, *introspection.TrustScore)
+
+	// Other organizations learn nothing about the token
+	outsider := &domain.Agent{ID: uuid.New(), OrganizationID: uuid.New()}
+	assert.False(t, service.Introspect(ctx, outsider, resp.AccessToken).Active)
+	require.NoError(t, service.Revoke(ctx, outsider, resp.AccessToken))
+	assert.True(t, service.Introspect(ctx, agent, resp.AccessToken).Active)
+
+	require.NoError(t, service.Revoke(ctx, agent, resp.AccessToken))
+	assert.False(t, service.Introspect(ctx, agent, resp.AccessToken).Active)
+	assert.False(t, service.Introspect(ctx, agent, "not-a-token").Active)
+
+	// Admin revocation of every token of the agent
+	resp, err = service.IssueToken(ctx, createTestTokenRequest(t, service, agent, agentKey, domain.GrantTypeClientCredentials, ""))
+	require.NoError(t, err)
+	revoked, err := service.RevokeAgentTokens(ctx, agent.OrganizationID, agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), revoked)
+	assert.False(t, service.Introspect(ctx, agent, resp.AccessToken).Active)
+}
+
+func TestAgentTokenService_JWKS(t *testing.T) {
+	agent, agentKey := createTestTokenAgent(t)
+	service, _, _ := createTestAgentTokenService(t, agent)
+
+	resp, err := service.IssueToken(context.Background(), createTestTokenRequest(t, service, agent, agentKey, domain.GrantTypeClientCredentials, ""))
+	require.NoError(t, err)
+
+	header, err := base64.RawURLEncoding.DecodeString(strings.Split(resp.AccessToken, ".")[0])
+	require.NoError(t, err)
+
+	jwks := service.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	key := jwks.Keys[0]
+	assert.Equal(t, "OKP", key.KeyType)
+	assert.Equal(t, "Ed25519", key.Curve)
+	assert.Equal(t, "EdDSA", key.Alg)
+	assert.Contains(t, string(header), `"kid":"`+key.KeyID+`"`)
+	assert.Contains(t, string(header), `"typ":"at+jwt"`)
+
+	// RFC 8037 appendix A.3 example key
+	x, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	require.NoError(t, err)
+	assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", JWKThumbprint(ed25519.PublicKey(x)))
+}
@@ -3,25 +3,32 @@ package application
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/opena2a/identity/backend/internal/domain"
 )
 
+// APIKeyExpiryWarningWindow is how far ahead of expiry an API key alert is raised
+const APIKeyExpiryWarningWindow = 7 * 24 * time.Hour
+
 // AlertService handles alert management
 type AlertService struct {
-	alertRepo domain.AlertRepository
-	agentRepo domain.AgentRepository
+	alertRepo  domain.AlertRepository
+	agentRepo  domain.AgentRepository
+	apiKeyRepo domain.APIKeyRepository
 }
 
 // NewAlertService creates a new alert service
 func NewAlertService(
 	alertRepo domain.AlertRepository,
 	agentRepo domain.AgentRepository,
+	apiKeyRepo domain.APIKeyRepository,
 ) *AlertService {
 	return &AlertService{
-		alertRepo: alertRepo,
-		agentRepo: agentRepo,
+		alertRepo:  alertRepo,
+		agentRepo:  agentRepo,
+		apiKeyRepo: apiKeyRepo,
 	}
 }
 
@@ -44,12 +51,61 @@ func (s *AlertService) CountUnacknowledged(ctx context.Context, orgID uuid.UUID)
 	return len(alerts), nil
 }
 
-// CheckAPIKeyExpiry checks for expiring API keys and creates alerts
-// NOTE: This method is not currently used but kept for future expansion
-// when API key expiry tracking is added to the system
+// CheckAPIKeyExpiry creates alerts for active API keys that expire within APIKeyExpiryWarningWindow
 func (s *AlertService) CheckAPIKeyExpiry(ctx context.Context, orgID uuid.UUID) error {
-	// TODO: Implement when API key repository is added to AlertService
-	// For now, this is a no-op
+	keys, err := s.apiKeyRepo.GetByOrganization(orgID)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.alertRepo.GetUnacknowledged(orgID)
+	if err != nil {
+		return err
+	}
+	alerted := make(map[uuid.UUID]bool)
+	for _, a := range existing {
+		if a.AlertType == domain.AlertAPIKeyExpiring {
+			alerted[a.ResourceID] = true
+		}
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		if !key.IsActive || key.ExpiresAt == nil || key.ExpiresAt.Sub(now) > APIKeyExpiryWarningWindow || alerted[key.ID] {
+			continue
+		}
+
+		severity := domain.AlertSeverityWarning
+		title := fmt.Sprintf("API Key '%s' Expires Soon", key.Name)
+		if !key.ExpiresAt.After(now) {
+			severity = domain.AlertSeverityHigh
+			title = fmt.Sprintf("API Key '%s' Has Expired", key.Name)
+		}
+
+		alert := &domain.Alert{
+			ID:             uuid.New(),
+			OrganizationID: orgID,
+			AlertType:      domain.AlertAPIKeyExpiring,
+			Severity:       severity,
+			Title:          title,
+			Description: fmt.Sprintf(
+				"API key **%s** (prefix `%s`) for agent %s expires at %s.\n\n"+
+					"**Recommended Actions:**\n"+
+					"1. Create a replacement API key for the agent\n"+
+					"2. Update the SDK or integration using this key\n"+
+					"3. Revoke the old key once traffic has moved",
+				key.Name, key.Prefix, key.AgentID, key.ExpiresAt.Format(time.RFC3339),
+			),
+			ResourceType: "api_key",
+			ResourceID:   key.ID,
+			CreatedAt:    now,
+		}
+
+		if err := s.alertRepo.Create(alert); err != nil {
+			return fmt.Errorf("failed to create API key expiry alert: %w", err)
+		}
+	}
+
 	return nil
 }
 
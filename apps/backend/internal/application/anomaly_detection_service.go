@@ -0,0 +1,594 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+const (
+	defaultActivityWindow          = 10 * time.Minute
+	defaultBaselinePersistInterval = time.Minute
+	activityHourlyAlpha            = 0.05 // Weight of the latest completed hour in the hourly mean
+	maxActivityIdleHours           = 24 * 7
+	maxActivityProfileEntries      = 1000 // Per categorical profile; the rarest entry is evicted
+)
+
+// anomalyRecorder is the part of domain.SecurityRepository the detector writes to
+type anomalyRecorder interface {
+	CreateAnomaly(anomaly *domain.Anomaly) error
+}
+
+// activityDetectionConfig holds the tunables of an unusual_activity policy's rules
+type activityDetectionConfig struct {
+	detectors         map[domain.ActivityDetector]bool
+	spikeMultiplier   float64 // Window volume above this multiple of the baseline is a spike
+	minSpikeEvents    float64 // ...and above this absolute count
+	offHoursShare     float64 // Hours of the day with less than this share of activity are off-hours
+	minBaselineEvents int64   // Nothing is flagged while the baseline is still learning
+	minBaselineHours  float64
+}
+
+// defaultActivityDetectors are enabled when a policy does not list detectors. New resources are
+// left out because resource strings (file paths, queries) are usually too varied.
+var defaultActivityDetectors = []domain.ActivityDetector{
+	domain.ActivityDetectorRateSpike,
+	domain.ActivityDetectorOffHours,
+	domain.ActivityDetectorNewAction,
+	domain.ActivityDetectorNewMCPServer,
+	domain.ActivityDetectorNewSourceIP,
+}
+
+func newActivityDetectionConfig(policy *domain.SecurityPolicy) activityDetectionConfig {
+	var rules map[string]interface{}
+	if policy != nil {
+		rules = policy.Rules
+	}
+
+	config := activityDetectionConfig{
+		detectors:         map[domain.ActivityDetector]bool{},
+		spikeMultiplier:   policyRuleFloat(rules, "spike_multiplier", 5),
+		minSpikeEvents:    policyRuleFloat(rules, "min_spike_events", 20),
+		offHoursShare:     policyRuleFloat(rules, "off_hours_share", 0.02),
+		minBaselineEvents: int64(policyRuleFloat(rules, "min_baseline_events", 50)),
+		minBaselineHours:  policyRuleFloat(rules, "min_baseline_hours", 24),
+	}
+
+	detectors := policyRuleStrings(rules, "detectors")
+	if len(detectors) == 0 {
+		for _, detector := range defaultActivityDetectors {
+			detectors = append(detectors, string(detector))
+		}
+	}
+	for _, detector := range detectors {
+		config.detectors[domain.ActivityDetector(detector)] = true
+	}
+
+	return config
+}
+
+// activityFinding is one deviation from the baseline
+type activityFinding struct {
+	detector    domain.ActivityDetector
+	key         string // Cooldown key: the detector, plus the new value for novelty detectors
+	anomalyType domain.AnomalyType
+	severity    domain.AlertSeverity
+	title       string
+	description string
+}
+
+// agentActivityState is the in-memory state of one agent: its baseline, the timestamps in the
+// sliding window and when each finding was last recorded
+type agentActivityState struct {
+	mu          sync.Mutex
+	baseline    *domain.AgentActivityBaseline
+	window      []time.Time
+	flaggedAt   map[string]time.Time
+	persistedAt time.Time
+	dirty       bool
+}
+
+// AnomalyDetectionService learns a per-agent baseline from verify-action and verification
+// requests and flags rate spikes, off-hours activity and never-before-seen actions, resources,
+// MCP servers and source IPs as anomalies. unusual_activity policies decide which detectors run,
+// their thresholds, and whether anomalies at or above the policy's severity threshold alert or
+// block. Without a policy anomalies are recorded but neither alerted nor blocked.
+//
+// The sliding window is kept in memory per instance; baselines are persisted at most once per
+// persist interval per agent.
+type AnomalyDetectionService struct {
+	baselineRepo  domain.AgentActivityBaselineRepository
+	anomalyRepo   anomalyRecorder
+	policyService *SecurityPolicyService
+	alertRepo     domain.AlertRepository
+
+	mu     sync.Mutex
+	agents map[uuid.UUID]*agentActivityState
+
+	now             func() time.Time
+	window          time.Duration
+	persistInterval time.Duration
+}
+
+// NewAnomalyDetectionService creates a new anomaly detection service
+func NewAnomalyDetectionService(
+	baselineRepo domain.AgentActivityBaselineRepository,
+	anomalyRepo anomalyRecorder,
+	policyService *SecurityPolicyService,
+	alertRepo domain.AlertRepository,
+) *AnomalyDetectionService {
+	return &AnomalyDetectionService{
+		baselineRepo:    baselineRepo,
+		anomalyRepo:     anomalyRepo,
+		policyService:   policyService,
+		alertRepo:       alertRepo,
+		agents:          make(map[uuid.UUID]*agentActivityState),
+		now:             func() time.Time { return time.Now().UTC() },
+		window:          defaultActivityWindow,
+		persistInterval: defaultBaselinePersistInterval,
+	}
+}
+
+// ObserveActivity checks one request against the agent's baseline, records and alerts on
+// anomalies as the agent's unusual_activity policy requires, and learns from the request unless it
+// is blocked. Errors loading the baseline or policies are returned with a nil result; callers
+// should fail open.
+func (s *AnomalyDetectionService) ObserveActivity(ctx context.Context, agent *domain.Agent, observation domain.ActivityObservation) (*domain.ActivityAnomalyResult, error) {
+	if observation.OccurredAt.IsZero() {
+		observation.OccurredAt = s.now()
+	}
+	observation.OrganizationID = agent.OrganizationID
+	observation.AgentID = agent.ID
+	if observation.AgentName == "" {
+		observation.AgentName = agent.DisplayName
+	}
+
+	var policy *domain.SecurityPolicy
+	if s.policyService != nil {
+		var err error
+		policy, err = s.policyService.FindApplicablePolicy(ctx, agent, domain.PolicyTypeUnusualActivity)
+		if err != nil {
+			return nil, err
+		}
+	}
+	config := newActivityDetectionConfig(policy)
+
+	state, err := s.agentState(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	// Slide the window; every attempt counts towards volume, including blocked ones
+	cutoff := observation.OccurredAt.Add(-s.window)
+	kept := state.window[:0]
+	for _, at := range state.window {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+	state.window = append(kept, observation.OccurredAt)
+
+	rollActivityHour(state.baseline, observation.OccurredAt)
+	findings := s.detect(state, observation, config)
+
+	result := &domain.ActivityAnomalyResult{Anomalies: []*domain.Anomaly{}}
+	triggered := []activityFinding{}
+	for _, finding := range findings {
+		if policy != nil && policy.EnforcementAction != domain.EnforcementAllow &&
+			alertSeverityRank(finding.severity) >= alertSeverityRank(policy.SeverityThreshold) {
+			triggered = append(triggered, finding)
+		}
+
+		// Repeated findings are recorded once per window, but still enforced
+		if last, ok := state.flaggedAt[finding.key]; ok && observation.OccurredAt.Sub(last) < s.window {
+			continue
+		}
+		state.flaggedAt[finding.key] = observation.OccurredAt
+
+		anomaly := &domain.Anomaly{
+			ID:             uuid.New(),
+			OrganizationID: agent.OrganizationID,
+			AnomalyType:    finding.anomalyType,
+			Severity:       finding.severity,
+			Title:          finding.title,
+			Description:    finding.description,
+			ResourceType:   "agent",
+			ResourceID:     agent.ID,
+			Confidence:     baselineConfidence(state.baseline),
+			CreatedAt:      observation.OccurredAt,
+		}
+		if err := s.anomalyRepo.CreateAnomaly(anomaly); err != nil {
+			fmt.Printf("⚠️  Failed to record %s anomaly for agent %s: %v\n", finding.detector, agent.Name, err)
+			continue
+		}
+		result.Anomalies = append(result.Anomalies, anomaly)
+	}
+
+	if len(triggered) > 0 {
+		result.PolicyName = policy.Name
+		result.Blocked = policy.EnforcementAction == domain.EnforcementBlockAndAlert
+		result.Reason = fmt.Sprintf("Unusual activity (%s) detected by security policy '%s'",
+			strings.Join(activityFindingDetectors(triggered), ", "), policy.Name)
+
+		// Alert on newly recorded anomalies only, so a blocked burst raises a single alert
+		if len(result.Anomalies) > 0 {
+			result.Alerted = s.raiseUnusualActivityAlert(agent, policy, triggered, result.Blocked)
+		}
+	}
+
+	if !result.Blocked {
+		learnActivity(state.baseline, observation)
+		state.dirty = true
+	}
+	s.persist(state)
+
+	return result, nil
+}
+
+// GetBaseline returns the agent's learned baseline, or nil when nothing has been observed yet
+func (s *AnomalyDetectionService) GetBaseline(ctx context.Context, agent *domain.Agent) (*domain.AgentActivityBaseline, error) {
+	state, err := s.agentState(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.baseline.TotalEvents == 0 {
+		return nil, nil
+	}
+	// Copy the profiles so the caller can read them while the agent stays active
+	baseline := *state.baseline
+	baseline.Actions = copyActivityProfile(state.baseline.Actions)
+	baseline.Resources = copyActivityProfile(state.baseline.Resources)
+	baseline.MCPServers = copyActivityProfile(state.baseline.MCPServers)
+	baseline.SourceIPs = copyActivityProfile(state.baseline.SourceIPs)
+	return &baseline, nil
+}
+
+// ResetBaseline discards the agent's baseline so it is learned again, e.g. after its role changed
+func (s *AnomalyDetectionService) ResetBaseline(ctx context.Context, agentID uuid.UUID) error {
+	s.mu.Lock()
+	delete(s.agents, agentID)
+	s.mu.Unlock()
+
+	return s.baselineRepo.Delete(agentID)
+}
+
+// agentState returns the cached state of an agent, loading its baseline on first use
+func (s *AnomalyDetectionService) agentState(agent *domain.Agent) (*agentActivityState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.agents[agent.ID]; ok {
+		return state, nil
+	}
+
+	baseline, err := s.baselineRepo.GetByAgent(agent.ID)
+	if err != nil {
+		return nil, err
+	}
+	if baseline == nil {
+		baseline = &domain.AgentActivityBaseline{
+			AgentID:        agent.ID,
+			OrganizationID: agent.OrganizationID,
+		}
+	}
+	ensureActivityProfiles(baseline)
+
+	state := &agentActivityState{
+		baseline:    baseline,
+		flaggedAt:   make(map[string]time.Time),
+		persistedAt: s.now(),
+	}
+	s.agents[agent.ID] = state
+	return state, nil
+}
+
+// detect compares the observation with a mature baseline
+func (s *AnomalyDetectionService) detect(state *agentActivityState, observation domain.ActivityObservation, config activityDetectionConfig) []activityFinding {
+	baseline := state.baseline
+	if baseline.TotalEvents < config.minBaselineEvents ||
+		observation.OccurredAt.Sub(baseline.FirstSeenAt).Hours() < config.minBaselineHours {
+		return nil
+	}
+
+	findings := []activityFinding{}
+	name := observation.AgentName
+
+	if config.detectors[domain.ActivityDetectorRateSpike] {
+		expected := baseline.HourlyMean * s.window.Hours()
+		threshold := math.Max(config.spikeMultiplier*expected, config.minSpikeEvents)
+		if count := float64(len(state.window)); count > threshold {
+			severity := domain.AlertSeverityHigh
+			if count > 2*threshold {
+				severity = domain.AlertSeverityCritical
+			}
+			findings = append(findings, activityFinding{
+				detector:    domain.ActivityDetectorRateSpike,
+				key:         string(domain.ActivityDetectorRateSpike),
+				anomalyType: domain.AnomalyTypeAbnormalTraffic,
+				severity:    severity,
+				title:       fmt.Sprintf("Activity Spike: %s", name),
+				description: fmt.Sprintf("Agent '%s' made %d requests in the last %s; its baseline is %.1f (alert threshold %.0f).",
+					name, len(state.window), s.window, expected, threshold),
+			})
+		}
+	}
+
+	if config.detectors[domain.ActivityDetectorOffHours] {
+		var total int64
+		for _, count := range baseline.HourOfDay {
+			total += count
+		}
+		hour := observation.OccurredAt.UTC().Hour()
+		if share := float64(baseline.HourOfDay[hour]) / float64(total); share < config.offHoursShare {
+			findings = append(findings, activityFinding{
+				detector:    domain.ActivityDetectorOffHours,
+				key:         fmt.Sprintf("%s:%d", domain.ActivityDetectorOffHours, hour),
+				anomalyType: domain.AnomalyTypeUnusualAccessPattern,
+				severity:    domain.AlertSeverityWarning,
+				title:       fmt.Sprintf("Off-Hours Activity: %s", name),
+				description: fmt.Sprintf("Agent '%s' is active at %02d:00 UTC, an hour with %.1f%% of its usual activity.",
+					name, hour, share*100),
+			})
+		}
+	}
+
+	novelty := []struct {
+		detector    domain.ActivityDetector
+		value       string
+		seen        map[string]int64
+		anomalyType domain.AnomalyType
+		severity    domain.AlertSeverity
+		label       string
+	}{
+		{domain.ActivityDetectorNewAction, observation.Action, baseline.Actions, domain.AnomalyTypeUnusualAPIUsage, domain.AlertSeverityHigh, "action"},
+		{domain.ActivityDetectorNewResource, observation.Resource, baseline.Resources, domain.AnomalyTypeUnusualAccessPattern, domain.AlertSeverityWarning, "resource"},
+		{domain.ActivityDetectorNewMCPServer, observation.MCPServer, baseline.MCPServers, domain.AnomalyTypeUnusualAccessPattern, domain.AlertSeverityHigh, "MCP server"},
+		{domain.ActivityDetectorNewSourceIP, observation.SourceIP, baseline.SourceIPs, domain.AnomalyTypeUnexpectedLocation, domain.AlertSeverityWarning, "source IP"},
+	}
+	for _, check := range novelty {
+		if !config.detectors[check.detector] || check.value == "" || check.seen[check.value] > 0 {
+			continue
+		}
+		findings = append(findings, activityFinding{
+			detector:    check.detector,
+			key:         string(check.detector) + ":" + check.value,
+			anomalyType: check.anomalyType,
+			severity:    check.severity,
+			title:       fmt.Sprintf("New %s: %s", strings.ToUpper(check.label[:1])+check.label[1:], name),
+			description: fmt.Sprintf("Agent '%s' used %s '%s' for the first time in %d observed requests.",
+				name, check.label, check.value, baseline.TotalEvents),
+		})
+	}
+
+	return findings
+}
+
+// raiseUnusualActivityAlert creates one alert for the triggered findings of a request
+func (s *AnomalyDetectionService) raiseUnusualActivityAlert(agent *domain.Agent, policy *domain.SecurityPolicy, findings []activityFinding, blocked bool) bool {
+	if s.alertRepo == nil {
+		return false
+	}
+
+	severity := domain.AlertSeverityInfo
+	message := fmt.Sprintf("Agent '%s' deviated from its learned activity baseline:\n\n", agent.DisplayName)
+	for _, finding := range findings {
+		if alertSeverityRank(finding.severity) > alertSeverityRank(severity) {
+			severity = finding.severity
+		}
+		message += fmt.Sprintf("- **%s:** %s\n", finding.detector, finding.description)
+	}
+
+	enforcement := "alert only"
+	if blocked {
+		enforcement = "request blocked"
+	}
+	message += fmt.Sprintf("\n**Enforcement:** %s (policy: %s)\n", enforcement, policy.Name)
+
+	alert := &domain.Alert{
+		ID:             uuid.New(),
+		OrganizationID: agent.OrganizationID,
+		AlertType:      domain.AlertUnusualActivity,
+		Severity:       severity,
+		Title:          fmt.Sprintf("Unusual Activity Detected: %s", agent.DisplayName),
+		Description:    message,
+		ResourceType:   "agent",
+		ResourceID:     agent.ID,
+		IsAcknowledged: false,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := s.alertRepo.Create(alert); err != nil {
+		fmt.Printf("⚠️  Failed to create unusual activity alert for agent %s: %v\n", agent.Name, err)
+		return false
+	}
+
+	return true
+}
+
+// persist writes the baseline when it changed and the persist interval has passed
+func (s *AnomalyDetectionService) persist(state *agentActivityState) {
+	if !state.dirty || s.now().Sub(state.persistedAt) < s.persistInterval {
+		return
+	}
+
+	if err := s.baselineRepo.Upsert(state.baseline); err != nil {
+		fmt.Printf("⚠️  Failed to save activity baseline for agent %s: %v\n", state.baseline.AgentID, err)
+		return
+	}
+	state.persistedAt = s.now()
+	state.dirty = false
+}
+
+// rollActivityHour folds completed hours, including idle ones, into the hourly mean
+func rollActivityHour(baseline *domain.AgentActivityBaseline, at time.Time) {
+	hour := at.UTC().Truncate(time.Hour)
+	if baseline.CurrentHour.IsZero() {
+		baseline.CurrentHour = hour
+		return
+	}
+	if !hour.After(baseline.CurrentHour) {
+		return
+	}
+
+	completed := []float64{float64(baseline.CurrentHourCount)}
+	idle := int(hour.Sub(baseline.CurrentHour).Hours()) - 1
+	for i := 0; i < idle && i < maxActivityIdleHours; i++ {
+		completed = append(completed, 0)
+	}
+
+	for _, count := range completed {
+		if baseline.HourlyMean == 0 && baseline.TotalEvents == baseline.CurrentHourCount {
+			baseline.HourlyMean = count // First completed hour
+			continue
+		}
+		baseline.HourlyMean += activityHourlyAlpha * (count - baseline.HourlyMean)
+	}
+
+	baseline.CurrentHour = hour
+	baseline.CurrentHourCount = 0
+}
+
+// learnActivity adds an observation to the baseline
+func learnActivity(baseline *domain.AgentActivityBaseline, observation domain.ActivityObservation) {
+	ensureActivityProfiles(baseline)
+	if baseline.FirstSeenAt.IsZero() {
+		baseline.FirstSeenAt = observation.OccurredAt
+	}
+	baseline.LastSeenAt = observation.OccurredAt
+	baseline.TotalEvents++
+	baseline.CurrentHourCount++
+	baseline.HourOfDay[observation.OccurredAt.UTC().Hour()]++
+
+	countActivity(baseline.Actions, observation.Action)
+	countActivity(baseline.Resources, observation.Resource)
+	countActivity(baseline.MCPServers, observation.MCPServer)
+	countActivity(baseline.SourceIPs, observation.SourceIP)
+}
+
+// countActivity increments value in a categorical profile, evicting the rarest entry when full
+func countActivity(profile map[string]int64, value string) {
+	if value == "" {
+		return
+	}
+	if _, ok := profile[value]; !ok && len(profile) >= maxActivityProfileEntries {
+		rarest, rarestCount := "", int64(math.MaxInt64)
+		for key, count := range profile {
+			if count < rarestCount || (count == rarestCount && key < rarest) {
+				rarest, rarestCount = key, count
+			}
+		}
+		delete(profile, rarest)
+	}
+	profile[value]++
+}
+
+func ensureActivityProfiles(baseline *domain.AgentActivityBaseline) {
+	if baseline.Actions == nil {
+		baseline.Actions = map[string]int64{}
+	}
+	if baseline.Resources == nil {
+		baseline.Resources = map[string]int64{}
+	}
+	if baseline.MCPServers == nil {
+		baseline.MCPServers = map[string]int64{}
+	}
+	if baseline.SourceIPs == nil {
+		baseline.SourceIPs = map[string]int64{}
+	}
+}
+
+func copyActivityProfile(profile map[string]int64) map[string]int64 {
+	copied := make(map[string]int64, len(profile))
+	for key, count := range profile {
+		copied[key] = count
+	}
+	return copied
+}
+
+// baselineConfidence grows with the number of observations behind the baseline (50-95)
+func baselineConfidence(baseline *domain.AgentActivityBaseline) float64 {
+	return math.Min(95, 50+float64(baseline.TotalEvents)/20)
+}
+
+func activityFindingDetectors(findings []activityFinding) []string {
+	detectors := []string{}
+	seen := map[domain.ActivityDetector]bool{}
+	for _, finding := range findings {
+		if !seen[finding.detector] {
+			seen[finding.detector] = true
+			detectors = append(detectors, string(finding.detector))
+		}
+	}
+	sort.Strings(detectors)
+	return detectors
+}
+
+// alertSeverityRank orders severities; an empty threshold matches everything
+func alertSeverityRank(severity domain.AlertSeverity) int {
+	switch severity {
+	case domain.AlertSeverityWarning:
+		return 1
+	case domain.AlertSeverityHigh:
+		return 2
+	case domain.AlertSeverityCritical:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// ActivityMCPServer returns the MCP server named in verify-action metadata or verification context
+func ActivityMCPServer(metadata map[string]interface{}) string {
+	for _, key := range []string{"mcp_server", "mcp_server_name", "mcp_server_id"} {
+		if value, ok := metadata[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// policyRuleFloat reads a numeric policy rule, falling back when it is missing or invalid
+func policyRuleFloat(rules map[string]interface{}, key string, fallback float64) float64 {
+	switch value := rules[key].(type) {
+	case float64:
+		if value > 0 {
+			return value
+		}
+	case int:
+		if value > 0 {
+			return float64(value)
+		}
+	}
+	return fallback
+}
+
+// policyRuleStrings reads a list-of-strings policy rule
+func policyRuleStrings(rules map[string]interface{}, key string) []string {
+	switch value := rules[key].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
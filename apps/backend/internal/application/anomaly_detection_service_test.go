@@ -0,0 +1,232 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeActivityBaselineRepository struct {
+	baselines map[uuid.UUID]*domain.AgentActivityBaseline
+}
+
+func (r *fakeActivityBaselineRepository) GetByAgent(agentID uuid.UUID) (*domain.AgentActivityBaseline, error) {
+	return r.baselines[agentID], nil
+}
+
+func (r *fakeActivityBaselineRepository) Upsert(baseline *domain.AgentActivityBaseline) error {
+	copied := *baseline
+	r.baselines[baseline.AgentID] = &copied
+	return nil
+}
+
+func (r *fakeActivityBaselineRepository) Delete(agentID uuid.UUID) error {
+	delete(r.baselines, agentID)
+	return nil
+}
+
+type fakeAnomalyRecorder struct {
+	anomalies []*domain.Anomaly
+}
+
+func (r *fakeAnomalyRecorder) CreateAnomaly(anomaly *domain.Anomaly) error {
+	r.anomalies = append(r.anomalies, anomaly)
+	return nil
+}
+
+// createTestAnomalyAgent creates an agent without activity history
+func createTestAnomalyAgent() *domain.Agent {
+	return &domain.Agent{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Name:           "reporting-agent",
+		DisplayName:    "Reporting Agent",
+	}
+}
+
+// createTestAnomalyDetectionService creates a service enforcing the given unusual_activity
+// policies of the agent's organization, whose clock reads *clock
+func createTestAnomalyDetectionService(agent *domain.Agent, clock *time.Time, policies ...*domain.SecurityPolicy) (*AnomalyDetectionService, *fakeActivityBaselineRepository, *fakeAnomalyRecorder, *MockToolSurfaceAlertRepository) {
+	policyRepo := new(MockToolSurfacePolicyRepository)
+	if policies == nil {
+		policies = []*domain.SecurityPolicy{}
+	}
+	policyRepo.On("GetByType", agent.OrganizationID, domain.PolicyTypeUnusualActivity).Return(policies, nil)
+
+	baselines := &fakeActivityBaselineRepository{baselines: map[uuid.UUID]*domain.AgentActivityBaseline{}}
+	recorder := &fakeAnomalyRecorder{}
+	alertRepo := new(MockToolSurfaceAlertRepository)
+	service := NewAnomalyDetectionService(baselines, recorder, NewSecurityPolicyService(policyRepo, alertRepo, nil), alertRepo)
+	service.now = func() time.Time { return *clock }
+	return service, baselines, recorder, alertRepo
+}
+
+// observeTestActivity moves the clock to at and observes a request for reports
+func observeTestActivity(t *testing.T, service *AnomalyDetectionService, agent *domain.Agent, clock *time.Time, at time.Time, action string) *domain.ActivityAnomalyResult {
+	*clock = at
+	result, err := service.ObserveActivity(context.Background(), agent, domain.ActivityObservation{
+		Action:     action,
+		Resource:   "reports",
+		SourceIP:   "10.0.0.1",
+		OccurredAt: at,
+	})
+	require.NoError(t, err)
+	return result
+}
+
+// trainTestBaseline observes two days of office-hours activity: 5 read_file requests per hour, 09:00-17:59 UTC
+func trainTestBaseline(t *testing.T, service *AnomalyDetectionService, agent *domain.Agent, clock *time.Time) {
+	for day := 0; day < 2; day++ {
+		for hour := 9; hour < 18; hour++ {
+			for i := 0; i < 5; i++ {
+				at := clock.Truncate(24*time.Hour).AddDate(0, 0, day).Add(time.Duration(hour)*time.Hour + time.Duration(i*10)*time.Minute)
+				result := observeTestActivity(t, service, agent, clock, at, "read_file")
+				require.Empty(t, result.Anomalies, "office-hours activity matches the baseline")
+			}
+		}
+	}
+}
+
+func monitorUnusualActivityPolicy(action domain.EnforcementAction, threshold domain.AlertSeverity) *domain.SecurityPolicy {
+	return &domain.SecurityPolicy{
+		Name:              "Monitor Unusual Activity",
+		PolicyType:        domain.PolicyTypeUnusualActivity,
+		EnforcementAction: action,
+		SeverityThreshold: threshold,
+		Rules:             map[string]interface{}{},
+		AppliesTo:         "all",
+		IsEnabled:         true,
+	}
+}
+
+func TestAnomalyDetectionLearnsBeforeFlagging(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	agent := createTestAnomalyAgent()
+	service, baselines, _, alertRepo := createTestAnomalyDetectionService(agent, &clock)
+
+	// While learning, new actions and odd hours are not flagged
+	result := observeTestActivity(t, service, agent, &clock, clock.Add(3*time.Hour), "delete_file")
+	assert.Empty(t, result.Anomalies)
+
+	trainTestBaseline(t, service, agent, &clock)
+	assert.Equal(t, int64(91), baselines.baselines[agent.ID].TotalEvents, "baseline is persisted")
+
+	// Once learned, a never-before-seen action is recorded but without a policy neither alerts nor blocks
+	result = observeTestActivity(t, service, agent, &clock, clock.Truncate(24*time.Hour).AddDate(0, 0, 1).Add(10*time.Hour), "execute_code")
+	require.Len(t, result.Anomalies, 1)
+	assert.Equal(t, domain.AnomalyTypeUnusualAPIUsage, result.Anomalies[0].AnomalyType)
+	assert.Equal(t, domain.AlertSeverityHigh, result.Anomalies[0].Severity)
+	assert.Equal(t, agent.ID, result.Anomalies[0].ResourceID)
+	assert.False(t, result.Alerted)
+	assert.False(t, result.Blocked)
+	alertRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	// The action is learned, so it is not flagged again
+	result = observeTestActivity(t, service, agent, &clock, clock.Add(20*time.Minute), "execute_code")
+	assert.Empty(t, result.Anomalies)
+}
+
+func TestAnomalyDetectionBlocksRateSpike(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	agent := createTestAnomalyAgent()
+	service, baselines, recorder, alertRepo := createTestAnomalyDetectionService(agent, &clock, monitorUnusualActivityPolicy(domain.EnforcementBlockAndAlert, domain.AlertSeverityHigh))
+	alertRepo.On("Create", mock.MatchedBy(func(alert *domain.Alert) bool {
+		return alert.AlertType == domain.AlertUnusualActivity && alert.ResourceID == agent.ID
+	})).Return(nil).Once()
+	trainTestBaseline(t, service, agent, &clock)
+	learned := baselines.baselines[agent.ID].TotalEvents
+
+	burst := clock.Truncate(24*time.Hour).AddDate(0, 0, 1).Add(10 * time.Hour)
+	var blocked []*domain.ActivityAnomalyResult
+	for i := 0; i < 30; i++ {
+		result := observeTestActivity(t, service, agent, &clock, burst.Add(time.Duration(i)*time.Second), "read_file")
+		if result.Blocked {
+			blocked = append(blocked, result)
+		}
+	}
+
+	// Requests beyond the spike threshold (20 per 10 minutes) are blocked; the spike is recorded and alerted once
+	require.Len(t, blocked, 10)
+	assert.Contains(t, blocked[0].Reason, "rate_spike")
+	assert.Contains(t, blocked[0].Reason, "Monitor Unusual Activity")
+	assert.True(t, blocked[0].Alerted)
+	assert.False(t, blocked[1].Alerted)
+	require.Len(t, recorder.anomalies, 1)
+	assert.Equal(t, domain.AnomalyTypeAbnormalTraffic, recorder.anomalies[0].AnomalyType)
+	alertRepo.AssertExpectations(t)
+
+	// Blocked requests do not become part of the baseline
+	baseline, err := service.GetBaseline(context.Background(), agent)
+	require.NoError(t, err)
+	assert.Equal(t, learned+20, baseline.TotalEvents)
+}
+
+func TestAnomalyDetectionSeverityThreshold(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	agent := createTestAnomalyAgent()
+	service, _, _, alertRepo := createTestAnomalyDetectionService(agent, &clock, monitorUnusualActivityPolicy(domain.EnforcementBlockAndAlert, domain.AlertSeverityHigh))
+	trainTestBaseline(t, service, agent, &clock)
+
+	// Off-hours activity is a warning, below the policy threshold: recorded, not enforced
+	result := observeTestActivity(t, service, agent, &clock, clock.Truncate(24*time.Hour).AddDate(0, 0, 1).Add(3*time.Hour), "read_file")
+	require.Len(t, result.Anomalies, 1)
+	assert.Equal(t, domain.AnomalyTypeUnusualAccessPattern, result.Anomalies[0].AnomalyType)
+	assert.Equal(t, domain.AlertSeverityWarning, result.Anomalies[0].Severity)
+	assert.False(t, result.Blocked)
+	alertRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAnomalyDetectionPolicyRules(t *testing.T) {
+	policy := monitorUnusualActivityPolicy(domain.EnforcementAlertOnly, domain.AlertSeverityWarning)
+	policy.Rules = map[string]interface{}{"detectors": []interface{}{"new_resource"}}
+	clock := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	agent := createTestAnomalyAgent()
+	service, _, _, alertRepo := createTestAnomalyDetectionService(agent, &clock, policy)
+	alertRepo.On("Create", mock.Anything).Return(nil).Once()
+	trainTestBaseline(t, service, agent, &clock)
+
+	clock = clock.Truncate(24*time.Hour).AddDate(0, 0, 1).Add(3 * time.Hour)
+	result, err := service.ObserveActivity(context.Background(), agent, domain.ActivityObservation{
+		Action:     "write_file",
+		Resource:   "payroll",
+		OccurredAt: clock,
+	})
+	require.NoError(t, err)
+
+	// Only the enabled detector runs; alert_only alerts without blocking
+	require.Len(t, result.Anomalies, 1)
+	assert.Contains(t, result.Anomalies[0].Description, "'payroll'")
+	assert.True(t, result.Alerted)
+	assert.False(t, result.Blocked)
+	alertRepo.AssertExpectations(t)
+
+	require.NoError(t, service.ResetBaseline(context.Background(), agent.ID))
+	baseline, err := service.GetBaseline(context.Background(), agent)
+	require.NoError(t, err)
+	assert.Nil(t, baseline)
+}
+
+func TestRollActivityHour(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	baseline := &domain.AgentActivityBaseline{}
+
+	for i := 0; i < 10; i++ {
+		rollActivityHour(baseline, start.Add(time.Duration(i)*time.Minute))
+		learnActivity(baseline, domain.ActivityObservation{OccurredAt: start, Action: "read_file"})
+	}
+	assert.Zero(t, baseline.HourlyMean, "the current hour is not part of the mean until it completes")
+
+	rollActivityHour(baseline, start.Add(time.Hour))
+	assert.Equal(t, 10.0, baseline.HourlyMean, "the first completed hour seeds the mean")
+
+	// Two idle hours pull the mean down
+	rollActivityHour(baseline, start.Add(4*time.Hour))
+	assert.InDelta(t, 10*0.95*0.95*0.95, baseline.HourlyMean, 0.001)
+	assert.Zero(t, baseline.CurrentHourCount)
+}
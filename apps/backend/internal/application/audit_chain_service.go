@@ -0,0 +1,562 @@
+package application
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// auditChainPageSize is the number of records read per query while walking a chain
+const auditChainPageSize = 1000
+
+// AuditChainService signs checkpoints of the per-organization audit hash chains and verifies
+// that no record was modified, deleted or inserted outside the chain.
+type AuditChainService struct {
+	chainRepo   domain.AuditChainRepository
+	signingKey  ed25519.PrivateKey
+	trustedKeys map[string]ed25519.PublicKey
+}
+
+// NewAuditChainService creates a new audit chain service. signingKey may be nil for verify-only
+// use; its public key is always trusted in addition to trustedKeys (e.g. keys from before a rotation).
+func NewAuditChainService(
+	chainRepo domain.AuditChainRepository,
+	signingKey ed25519.PrivateKey,
+	trustedKeys []ed25519.PublicKey,
+) *AuditChainService {
+	trusted := make(map[string]ed25519.PublicKey)
+	for _, key := range trustedKeys {
+		trusted[AuditSigningKeyID(key)] = key
+	}
+	if signingKey != nil {
+		publicKey := signingKey.Public().(ed25519.PublicKey)
+		trusted[AuditSigningKeyID(publicKey)] = publicKey
+	}
+
+	return &AuditChainService{
+		chainRepo:   chainRepo,
+		signingKey:  signingKey,
+		trustedKeys: trusted,
+	}
+}
+
+// LoadAuditSigningKey decodes a base64 Ed25519 seed (32 bytes) or private key (64 bytes).
+// When encoded is empty an ephemeral key is generated; checkpoints signed with it cannot be
+// verified after a restart, so this is for development only.
+func LoadAuditSigningKey(encoded string) (ed25519.PrivateKey, error) {
+	if encoded == "" {
+		fmt.Println("⚠️  AUDIT_SIGNING_KEY not set, generating ephemeral audit checkpoint key (development only)")
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate audit signing key: %w", err)
+		}
+		return privateKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audit signing key: %w", err)
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("invalid audit signing key size: expected %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+}
+
+// ParseAuditPublicKeys decodes a list of base64 Ed25519 public keys
+func ParseAuditPublicKeys(encoded []string) ([]ed25519.PublicKey, error) {
+	keys := make([]ed25519.PublicKey, 0, len(encoded))
+	for _, value := range encoded {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode audit public key: %w", err)
+		}
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid audit public key size: expected %d, got %d", ed25519.PublicKeySize, len(raw))
+		}
+		keys = append(keys, ed25519.PublicKey(raw))
+	}
+	return keys, nil
+}
+
+// AuditSigningKeyID returns the fingerprint stored with checkpoints (hex of the first 16 bytes of SHA-256)
+func AuditSigningKeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:16])
+}
+
+// CreateCheckpoint verifies the records appended since the last checkpoint and signs the new
+// chain head. It returns the latest existing checkpoint when nothing was appended, and nil when
+// the organization has no chained records.
+func (s *AuditChainService) CreateCheckpoint(ctx context.Context, orgID uuid.UUID) (*domain.AuditCheckpoint, error) {
+	if s.signingKey == nil {
+		return nil, fmt.Errorf("audit signing key not configured")
+	}
+
+	head, err := s.chainRepo.GetChainHead(orgID)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, nil
+	}
+
+	latest, err := s.chainRepo.GetLatestCheckpoint(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	afterSequence, prevHash := int64(0), domain.AuditChainGenesisHash
+	if head.PrunedThroughSequence > 0 {
+		afterSequence, prevHash = head.PrunedThroughSequence, head.PrunedThroughHash
+	}
+	if latest != nil {
+		if latest.SequenceNumber >= head.LastSequence {
+			return latest, nil
+		}
+		if brk := s.verifyCheckpoint(latest); brk == nil {
+			afterSequence, prevHash = latest.SequenceNumber, latest.EntryHash
+		} else {
+			// The previous checkpoint cannot anchor the new one; re-verify from the start
+			fmt.Printf("⚠️  Audit checkpoint %d for org %s is not trusted (%s), verifying chain from sequence %d\n",
+				latest.SequenceNumber, orgID, brk.Reason, afterSequence)
+		}
+	}
+
+	lastSequence, lastHash, brk, err := s.walk(orgID, afterSequence, prevHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	if brk != nil {
+		return nil, fmt.Errorf("refusing to checkpoint broken audit chain: %s at sequence %d", brk.Reason, brk.SequenceNumber)
+	}
+	if lastSequence < head.LastSequence {
+		return nil, fmt.Errorf("refusing to checkpoint broken audit chain: %s at sequence %d", domain.AuditChainBreakTruncated, lastSequence+1)
+	}
+
+	publicKey := s.signingKey.Public().(ed25519.PublicKey)
+	checkpoint := &domain.AuditCheckpoint{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		SequenceNumber: lastSequence,
+		EntryHash:      lastHash,
+		KeyID:          AuditSigningKeyID(publicKey),
+		PublicKey:      base64.StdEncoding.EncodeToString(publicKey),
+		CreatedAt:      time.Now().UTC().Truncate(time.Microsecond),
+	}
+	checkpoint.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(s.signingKey, checkpoint.SigningPayload()))
+
+	if err := s.chainRepo.CreateCheckpoint(checkpoint); err != nil {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+// CreateCheckpoints checkpoints every organization's audit chain and returns how many were processed
+func (s *AuditChainService) CreateCheckpoints(ctx context.Context) (int, error) {
+	heads, err := s.chainRepo.ListChainHeads()
+	if err != nil {
+		return 0, err
+	}
+
+	processed, failed := 0, 0
+	for _, head := range heads {
+		if ctx.Err() != nil {
+			return processed, ctx.Err()
+		}
+		if _, err := s.CreateCheckpoint(ctx, head.OrganizationID); err != nil {
+			fmt.Printf("🚨 Audit checkpoint failed for org %s: %v\n", head.OrganizationID, err)
+			failed++
+			continue
+		}
+		processed++
+	}
+
+	if failed > 0 {
+		return processed, fmt.Errorf("audit checkpoint failed for %d of %d organizations", failed, len(heads))
+	}
+
+	return processed, nil
+}
+
+// GetCheckpoints returns an organization's checkpoints, newest first
+func (s *AuditChainService) GetCheckpoints(ctx context.Context, orgID uuid.UUID, limit, offset int) ([]*domain.AuditCheckpoint, error) {
+	return s.chainRepo.GetCheckpoints(orgID, limit, offset)
+}
+
+// VerifyChain walks an organization's whole audit chain and reports the first broken link. A chain
+// purged by retention is walked from its prune anchor, which must match a trusted checkpoint.
+func (s *AuditChainService) VerifyChain(ctx context.Context, orgID uuid.UUID) (*domain.AuditChainVerification, error) {
+	result := &domain.AuditChainVerification{
+		OrganizationID: orgID,
+		VerifiedAt:     time.Now().UTC(),
+	}
+
+	head, err := s.chainRepo.GetChainHead(orgID)
+	if err != nil {
+		return nil, err
+	}
+	if head != nil {
+		result.HeadSequence = head.LastSequence
+	}
+
+	checkpoints, err := s.allCheckpoints(orgID)
+	if err != nil {
+		return nil, err
+	}
+	checkpointsBySequence := make(map[int64]*domain.AuditCheckpoint, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		checkpointsBySequence[checkpoint.SequenceNumber] = checkpoint
+		if s.trustedKeys[checkpoint.KeyID] == nil && !containsString(result.UntrustedKeyIDs, checkpoint.KeyID) {
+			result.UntrustedKeyIDs = append(result.UntrustedKeyIDs, checkpoint.KeyID)
+		}
+	}
+	if len(checkpoints) > 0 {
+		result.LastCheckpoint = checkpoints[0]
+	}
+
+	afterSequence, prevHash := int64(0), domain.AuditChainGenesisHash
+	brk := s.checkPruneAnchor(head, checkpointsBySequence)
+	if head != nil && head.PrunedThroughSequence > 0 {
+		afterSequence, prevHash = head.PrunedThroughSequence, head.PrunedThroughHash
+		result.PrunedThrough = head.PrunedThroughSequence
+		if brk == nil {
+			result.CheckpointsVerified++
+		}
+	}
+
+	lastSequence, lastHash := afterSequence, prevHash
+	if brk == nil {
+		lastSequence, lastHash, brk, err = s.walk(orgID, afterSequence, prevHash, s.checkpointCheck(checkpointsBySequence, result))
+		if err != nil {
+			return nil, err
+		}
+	}
+	result.EntriesVerified = lastSequence - afterSequence
+
+	if brk == nil {
+		brk = checkAuditChainEnd(head, lastSequence, lastHash)
+	}
+	if brk == nil {
+		// Checkpoints are newest first; the first one past the end of the chain is the earliest
+		for i := len(checkpoints) - 1; i >= 0; i-- {
+			if checkpoints[i].SequenceNumber > lastSequence {
+				brk = &domain.AuditChainBreak{
+					SequenceNumber: lastSequence + 1,
+					Reason:         domain.AuditChainBreakTruncated,
+					Expected:       fmt.Sprintf("chain through checkpoint %d", checkpoints[i].SequenceNumber),
+					Actual:         fmt.Sprintf("chain ends at %d", lastSequence),
+				}
+				break
+			}
+		}
+	}
+	if brk == nil && head != nil {
+		unchained, err := s.chainRepo.CountUnchainedSince(orgID, head.ChainStartedAt)
+		if err != nil {
+			return nil, err
+		}
+		if unchained > 0 {
+			brk = &domain.AuditChainBreak{
+				Reason:   domain.AuditChainBreakUnchainedEntry,
+				Expected: "0 unchained records",
+				Actual:   fmt.Sprintf("%d unchained records since %s", unchained, head.ChainStartedAt.UTC().Format(time.RFC3339)),
+			}
+		}
+	}
+
+	result.FirstBrokenLink = brk
+	result.Valid = brk == nil
+
+	return result, nil
+}
+
+// PruneThrough moves an organization's prune anchor to the newest trusted checkpoint created
+// before the cutoff and returns its sequence; chained records up to that sequence may then be
+// deleted without breaking verification. It refuses to prune a chain that does not verify.
+func (s *AuditChainService) PruneThrough(ctx context.Context, orgID uuid.UUID, before time.Time) (int64, error) {
+	head, err := s.chainRepo.GetChainHead(orgID)
+	if err != nil {
+		return 0, err
+	}
+	if head == nil {
+		return 0, nil
+	}
+
+	verification, err := s.VerifyChain(ctx, orgID)
+	if err != nil {
+		return 0, err
+	}
+	if !verification.Valid {
+		return 0, fmt.Errorf("refusing to prune broken audit chain: %s at sequence %d",
+			verification.FirstBrokenLink.Reason, verification.FirstBrokenLink.SequenceNumber)
+	}
+
+	checkpoints, err := s.allCheckpoints(orgID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Checkpoints are newest first; every record up to a checkpoint was written before it was signed
+	for _, checkpoint := range checkpoints {
+		if checkpoint.SequenceNumber <= head.PrunedThroughSequence {
+			break
+		}
+		if !checkpoint.CreatedAt.Before(before) || s.verifyCheckpoint(checkpoint) != nil {
+			continue
+		}
+		if err := s.chainRepo.SetPrunedThrough(orgID, checkpoint.SequenceNumber, checkpoint.EntryHash); err != nil {
+			return 0, err
+		}
+		return checkpoint.SequenceNumber, nil
+	}
+
+	return head.PrunedThroughSequence, nil
+}
+
+// checkpointCheck returns a walk check that verifies records against the signed checkpoints at their sequence
+func (s *AuditChainService) checkpointCheck(
+	checkpointsBySequence map[int64]*domain.AuditCheckpoint,
+	result *domain.AuditChainVerification,
+) func(entry *domain.AuditLog) *domain.AuditChainBreak {
+	return func(entry *domain.AuditLog) *domain.AuditChainBreak {
+		checkpoint, ok := checkpointsBySequence[*entry.SequenceNumber]
+		if !ok {
+			return nil
+		}
+		if brk := s.verifyCheckpoint(checkpoint); brk != nil {
+			return brk
+		}
+		if checkpoint.EntryHash != entry.EntryHash {
+			return &domain.AuditChainBreak{
+				SequenceNumber: checkpoint.SequenceNumber,
+				EntryID:        &entry.ID,
+				Reason:         domain.AuditChainBreakCheckpointMismatch,
+				Expected:       checkpoint.EntryHash,
+				Actual:         entry.EntryHash,
+			}
+		}
+		result.CheckpointsVerified++
+		return nil
+	}
+}
+
+// checkPruneAnchor checks that a purged chain was cut at a trusted checkpoint with the recorded hash
+func (s *AuditChainService) checkPruneAnchor(
+	head *domain.AuditChainHead,
+	checkpointsBySequence map[int64]*domain.AuditCheckpoint,
+) *domain.AuditChainBreak {
+	if head == nil || head.PrunedThroughSequence == 0 {
+		return nil
+	}
+
+	checkpoint, ok := checkpointsBySequence[head.PrunedThroughSequence]
+	if !ok {
+		return &domain.AuditChainBreak{
+			SequenceNumber: head.PrunedThroughSequence,
+			Reason:         domain.AuditChainBreakPruneAnchor,
+			Expected:       "signed checkpoint at pruned sequence",
+			Actual:         "no checkpoint",
+		}
+	}
+	if brk := s.verifyCheckpoint(checkpoint); brk != nil {
+		return brk
+	}
+	if checkpoint.EntryHash != head.PrunedThroughHash {
+		return &domain.AuditChainBreak{
+			SequenceNumber: head.PrunedThroughSequence,
+			Reason:         domain.AuditChainBreakPruneAnchor,
+			Expected:       checkpoint.EntryHash,
+			Actual:         head.PrunedThroughHash,
+		}
+	}
+
+	return nil
+}
+
+// walk verifies chained records after afterSequence, starting from prevHash, calling check for
+// every record that links correctly. It returns the last verified position and the first break.
+func (s *AuditChainService) walk(
+	orgID uuid.UUID,
+	afterSequence int64,
+	prevHash string,
+	check func(entry *domain.AuditLog) *domain.AuditChainBreak,
+) (int64, string, *domain.AuditChainBreak, error) {
+	expected := afterSequence + 1
+
+	for {
+		entries, err := s.chainRepo.GetChainedEntries(orgID, expected-1, auditChainPageSize)
+		if err != nil {
+			return 0, "", nil, err
+		}
+
+		for _, entry := range entries {
+			if brk := checkAuditEntry(entry, expected, prevHash); brk != nil {
+				return expected - 1, prevHash, brk, nil
+			}
+			if check != nil {
+				if brk := check(entry); brk != nil {
+					return expected - 1, prevHash, brk, nil
+				}
+			}
+			prevHash = entry.EntryHash
+			expected++
+		}
+
+		if len(entries) < auditChainPageSize {
+			return expected - 1, prevHash, nil, nil
+		}
+	}
+}
+
+// checkAuditEntry checks a record's position, link to its predecessor and content hash
+func checkAuditEntry(entry *domain.AuditLog, expectedSequence int64, prevHash string) *domain.AuditChainBreak {
+	if *entry.SequenceNumber != expectedSequence {
+		return &domain.AuditChainBreak{
+			SequenceNumber: expectedSequence,
+			Reason:         domain.AuditChainBreakMissingEntry,
+			Expected:       strconv.FormatInt(expectedSequence, 10),
+			Actual:         strconv.FormatInt(*entry.SequenceNumber, 10),
+		}
+	}
+
+	if entry.PrevHash != prevHash {
+		return &domain.AuditChainBreak{
+			SequenceNumber: expectedSequence,
+			EntryID:        &entry.ID,
+			Reason:         domain.AuditChainBreakBrokenLink,
+			Expected:       prevHash,
+			Actual:         entry.PrevHash,
+		}
+	}
+
+	hash, err := domain.ComputeAuditEntryHash(entry)
+	if err != nil {
+		hash = err.Error()
+	}
+	if hash != entry.EntryHash {
+		return &domain.AuditChainBreak{
+			SequenceNumber: expectedSequence,
+			EntryID:        &entry.ID,
+			Reason:         domain.AuditChainBreakModifiedEntry,
+			Expected:       hash,
+			Actual:         entry.EntryHash,
+		}
+	}
+
+	return nil
+}
+
+// checkAuditChainEnd compares the end of the walked chain with the stored chain head
+func checkAuditChainEnd(head *domain.AuditChainHead, lastSequence int64, lastHash string) *domain.AuditChainBreak {
+	if head == nil {
+		if lastSequence == 0 {
+			return nil
+		}
+		return &domain.AuditChainBreak{
+			SequenceNumber: lastSequence,
+			Reason:         domain.AuditChainBreakBrokenLink,
+			Expected:       "chain head",
+			Actual:         "chain head missing",
+		}
+	}
+
+	switch {
+	case head.LastSequence > lastSequence:
+		return &domain.AuditChainBreak{
+			SequenceNumber: lastSequence + 1,
+			Reason:         domain.AuditChainBreakTruncated,
+			Expected:       fmt.Sprintf("chain through %d", head.LastSequence),
+			Actual:         fmt.Sprintf("chain ends at %d", lastSequence),
+		}
+	case head.LastSequence == lastSequence && head.LastHash != lastHash:
+		return &domain.AuditChainBreak{
+			SequenceNumber: lastSequence,
+			Reason:         domain.AuditChainBreakBrokenLink,
+			Expected:       head.LastHash,
+			Actual:         lastHash,
+		}
+	}
+
+	// Records past the head are appends that committed after the head was read
+	return nil
+}
+
+// verifyCheckpoint checks a checkpoint's key fingerprint, signature and that its key is trusted
+func (s *AuditChainService) verifyCheckpoint(checkpoint *domain.AuditCheckpoint) *domain.AuditChainBreak {
+	invalid := &domain.AuditChainBreak{
+		SequenceNumber: checkpoint.SequenceNumber,
+		Reason:         domain.AuditChainBreakInvalidSignature,
+		Expected:       "valid signature by key " + checkpoint.KeyID,
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(checkpoint.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		invalid.Actual = "malformed public key"
+		return invalid
+	}
+	if AuditSigningKeyID(publicKey) != checkpoint.KeyID {
+		invalid.Actual = "public key does not match key id"
+		return invalid
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(checkpoint.Signature)
+	if err != nil || !ed25519.Verify(publicKey, checkpoint.SigningPayload(), signature) {
+		invalid.Actual = "signature does not verify"
+		return invalid
+	}
+
+	if s.trustedKeys[checkpoint.KeyID] == nil {
+		return &domain.AuditChainBreak{
+			SequenceNumber: checkpoint.SequenceNumber,
+			Reason:         domain.AuditChainBreakUntrustedKey,
+			Expected:       "checkpoint signed by a trusted key",
+			Actual:         checkpoint.KeyID,
+		}
+	}
+
+	return nil
+}
+
+func (s *AuditChainService) allCheckpoints(orgID uuid.UUID) ([]*domain.AuditCheckpoint, error) {
+	var checkpoints []*domain.AuditCheckpoint
+	for offset := 0; ; offset += auditChainPageSize {
+		page, err := s.chainRepo.GetCheckpoints(orgID, auditChainPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, page...)
+		if len(page) < auditChainPageSize {
+			return checkpoints, nil
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,316 @@
+package application
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuditChainRepository keeps one organization's chained records in memory
+type fakeAuditChainRepository struct {
+	head        *domain.AuditChainHead
+	entries     []*domain.AuditLog
+	checkpoints []*domain.AuditCheckpoint
+	unchained   int
+}
+
+// appendEntry chains a record the same way AuditLogRepository.Create does
+func (r *fakeAuditChainRepository) appendEntry(t *testing.T, orgID uuid.UUID, metadata map[string]interface{}) {
+	if r.head == nil {
+		r.head = &domain.AuditChainHead{
+			OrganizationID: orgID,
+			LastHash:       domain.AuditChainGenesisHash,
+			ChainStartedAt: time.Now().UTC(),
+		}
+	}
+
+	sequence := r.head.LastSequence + 1
+	entry := &domain.AuditLog{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		UserID:         uuid.New(),
+		Action:         domain.AuditActionUpdate,
+		ResourceType:   "agent",
+		ResourceID:     uuid.New(),
+		IPAddress:      "10.0.0.1",
+		UserAgent:      "test",
+		Metadata:       metadata,
+		Timestamp:      time.Now().UTC().Truncate(time.Microsecond),
+		SequenceNumber: &sequence,
+		PrevHash:       r.head.LastHash,
+	}
+	hash, err := domain.ComputeAuditEntryHash(entry)
+	require.NoError(t, err)
+	entry.EntryHash = hash
+
+	r.entries = append(r.entries, entry)
+	r.head.LastSequence = sequence
+	r.head.LastHash = hash
+}
+
+func (r *fakeAuditChainRepository) GetChainHead(orgID uuid.UUID) (*domain.AuditChainHead, error) {
+	if r.head == nil {
+		return nil, nil
+	}
+	head := *r.head
+	return &head, nil
+}
+
+func (r *fakeAuditChainRepository) ListChainHeads() ([]*domain.AuditChainHead, error) {
+	if r.head == nil {
+		return nil, nil
+	}
+	return []*domain.AuditChainHead{r.head}, nil
+}
+
+func (r *fakeAuditChainRepository) GetChainedEntries(orgID uuid.UUID, afterSequence int64, limit int) ([]*domain.AuditLog, error) {
+	result := []*domain.AuditLog{}
+	for _, entry := range r.entries {
+		if *entry.SequenceNumber > afterSequence && len(result) < limit {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeAuditChainRepository) CountUnchainedSince(orgID uuid.UUID, since time.Time) (int, error) {
+	return r.unchained, nil
+}
+
+func (r *fakeAuditChainRepository) CreateCheckpoint(checkpoint *domain.AuditCheckpoint) error {
+	r.checkpoints = append(r.checkpoints, checkpoint)
+	sort.Slice(r.checkpoints, func(i, j int) bool {
+		return r.checkpoints[i].SequenceNumber > r.checkpoints[j].SequenceNumber
+	})
+	return nil
+}
+
+func (r *fakeAuditChainRepository) GetCheckpoints(orgID uuid.UUID, limit, offset int) ([]*domain.AuditCheckpoint, error) {
+	if offset >= len(r.checkpoints) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > len(r.checkpoints) {
+		end = len(r.checkpoints)
+	}
+	return r.checkpoints[offset:end], nil
+}
+
+func (r *fakeAuditChainRepository) GetLatestCheckpoint(orgID uuid.UUID) (*domain.AuditCheckpoint, error) {
+	if len(r.checkpoints) == 0 {
+		return nil, nil
+	}
+	return r.checkpoints[0], nil
+}
+
+func (r *fakeAuditChainRepository) SetPrunedThrough(orgID uuid.UUID, sequence int64, hash string) error {
+	if r.head != nil && sequence > r.head.PrunedThroughSequence {
+		r.head.PrunedThroughSequence = sequence
+		r.head.PrunedThroughHash = hash
+	}
+	return nil
+}
+
+// purgeThrough deletes chained records up to sequence, as the retention purge does
+func (r *fakeAuditChainRepository) purgeThrough(sequence int64) {
+	kept := []*domain.AuditLog{}
+	for _, entry := range r.entries {
+		if *entry.SequenceNumber > sequence {
+			kept = append(kept, entry)
+		}
+	}
+	r.entries = kept
+}
+
+func newTestAuditChain(t *testing.T, entries int) (*fakeAuditChainRepository, *AuditChainService, uuid.UUID) {
+	_, signingKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	orgID := uuid.New()
+	repo := &fakeAuditChainRepository{}
+	for i := 0; i < entries; i++ {
+		repo.appendEntry(t, orgID, map[string]interface{}{"index": i, "ratio": 0.5, "tags": []string{"a", "b"}})
+	}
+
+	return repo, NewAuditChainService(repo, signingKey, nil), orgID
+}
+
+func TestComputeAuditEntryHash_StableAcrossJSONRoundTrip(t *testing.T) {
+	repo, _, _ := newTestAuditChain(t, 1)
+	entry := repo.entries[0]
+
+	// Metadata read back from JSONB is decoded into generic JSON types
+	raw, err := json.Marshal(entry.Metadata)
+	require.NoError(t, err)
+	decoded := *entry
+	decoded.Metadata = nil
+	require.NoError(t, json.Unmarshal(raw, &decoded.Metadata))
+
+	hash, err := domain.ComputeAuditEntryHash(&decoded)
+	require.NoError(t, err)
+	assert.Equal(t, entry.EntryHash, hash)
+}
+
+func TestAuditChainService_VerifyValidChain(t *testing.T) {
+	repo, service, orgID := newTestAuditChain(t, 5)
+
+	checkpoint, err := service.CreateCheckpoint(context.Background(), orgID)
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, int64(5), checkpoint.SequenceNumber)
+
+	// Nothing appended since: the existing checkpoint is returned
+	again, err := service.CreateCheckpoint(context.Background(), orgID)
+	require.NoError(t, err)
+	assert.Equal(t, checkpoint.ID, again.ID)
+
+	repo.appendEntry(t, orgID, nil)
+
+	result, err := service.VerifyChain(context.Background(), orgID)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Nil(t, result.FirstBrokenLink)
+	assert.Equal(t, int64(6), result.EntriesVerified)
+	assert.Equal(t, 1, result.CheckpointsVerified)
+}
+
+func TestAuditChainService_DetectsModifiedEntry(t *testing.T) {
+	repo, service, orgID := newTestAuditChain(t, 5)
+	repo.entries[2].Action = domain.AuditActionView
+
+	result, err := service.VerifyChain(context.Background(), orgID)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	require.NotNil(t, result.FirstBrokenLink)
+	assert.Equal(t, domain.AuditChainBreakModifiedEntry, result.FirstBrokenLink.Reason)
+	assert.Equal(t, int64(3), result.FirstBrokenLink.SequenceNumber)
+	assert.Equal(t, repo.entries[2].ID, *result.FirstBrokenLink.EntryID)
+	assert.Equal(t, int64(2), result.EntriesVerified)
+
+	_, err = service.CreateCheckpoint(context.Background(), orgID)
+	assert.Error(t, err, "a broken chain must not be checkpointed")
+}
+
+func TestAuditChainService_DetectsDeletedEntry(t *testing.T) {
+	repo, service, orgID := newTestAuditChain(t, 5)
+	repo.entries = append(repo.entries[:1], repo.entries[2:]...)
+
+	result, err := service.VerifyChain(context.Background(), orgID)
+	require.NoError(t, err)
+	require.NotNil(t, result.FirstBrokenLink)
+	assert.Equal(t, domain.AuditChainBreakMissingEntry, result.FirstBrokenLink.Reason)
+	assert.Equal(t, int64(2), result.FirstBrokenLink.SequenceNumber)
+}
+
+func TestAuditChainService_DetectsRewrittenHistoryWithCheckpoint(t *testing.T) {
+	repo, service, orgID := newTestAuditChain(t, 4)
+	_, err := service.CreateCheckpoint(context.Background(), orgID)
+	require.NoError(t, err)
+
+	// Rewrite the whole chain consistently, as an attacker with database access could
+	rewritten := &fakeAuditChainRepository{checkpoints: repo.checkpoints}
+	for i := 0; i < 4; i++ {
+		rewritten.appendEntry(t, orgID, nil)
+	}
+	service = NewAuditChainService(rewritten, nil, []ed25519.PublicKey{service.signingKey.Public().(ed25519.PublicKey)})
+
+	result, err := service.VerifyChain(context.Background(), orgID)
+	require.NoError(t, err)
+	require.NotNil(t, result.FirstBrokenLink)
+	assert.Equal(t, domain.AuditChainBreakCheckpointMismatch, result.FirstBrokenLink.Reason)
+	assert.Equal(t, int64(4), result.FirstBrokenLink.SequenceNumber)
+}
+
+func TestAuditChainService_DetectsTruncationAndForgedCheckpoint(t *testing.T) {
+	repo, service, orgID := newTestAuditChain(t, 4)
+	_, err := service.CreateCheckpoint(context.Background(), orgID)
+	require.NoError(t, err)
+
+	// Drop the tail and roll the head back
+	repo.entries = repo.entries[:2]
+	repo.head.LastSequence = 2
+	repo.head.LastHash = repo.entries[1].EntryHash
+
+	result, err := service.VerifyChain(context.Background(), orgID)
+	require.NoError(t, err)
+	require.NotNil(t, result.FirstBrokenLink)
+	assert.Equal(t, domain.AuditChainBreakTruncated, result.FirstBrokenLink.Reason)
+	assert.Equal(t, int64(3), result.FirstBrokenLink.SequenceNumber)
+
+	// A checkpoint re-signed with another key is not trusted
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	repo.checkpoints = nil
+	_, err = NewAuditChainService(repo, otherKey, nil).CreateCheckpoint(context.Background(), orgID)
+	require.NoError(t, err)
+
+	result, err = service.VerifyChain(context.Background(), orgID)
+	require.NoError(t, err)
+	require.NotNil(t, result.FirstBrokenLink)
+	assert.Equal(t, domain.AuditChainBreakUntrustedKey, result.FirstBrokenLink.Reason)
+	assert.Len(t, result.UntrustedKeyIDs, 1)
+
+	// Tampering with the signed fields invalidates the signature
+	repo.checkpoints[0].KeyID = AuditSigningKeyID(service.signingKey.Public().(ed25519.PublicKey))
+	result, err = service.VerifyChain(context.Background(), orgID)
+	require.NoError(t, err)
+	require.NotNil(t, result.FirstBrokenLink)
+	assert.Equal(t, domain.AuditChainBreakInvalidSignature, result.FirstBrokenLink.Reason)
+}
+
+func TestAuditChainService_PruneThroughKeepsChainVerifiable(t *testing.T) {
+	repo, service, orgID := newTestAuditChain(t, 4)
+	ctx := context.Background()
+
+	// No checkpoint yet: nothing may be pruned
+	through, err := service.PruneThrough(ctx, orgID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), through)
+
+	_, err = service.CreateCheckpoint(ctx, orgID)
+	require.NoError(t, err)
+	repo.appendEntry(t, orgID, nil)
+
+	// The checkpoint is newer than the cutoff
+	through, err = service.PruneThrough(ctx, orgID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), through)
+
+	through, err = service.PruneThrough(ctx, orgID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), through)
+	repo.purgeThrough(through)
+
+	result, err := service.VerifyChain(ctx, orgID)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "%+v", result.FirstBrokenLink)
+	assert.Equal(t, int64(4), result.PrunedThrough)
+	assert.Equal(t, int64(1), result.EntriesVerified)
+
+	// New checkpoints anchor on the pruned chain
+	repo.appendEntry(t, orgID, nil)
+	checkpoint, err := service.CreateCheckpoint(ctx, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), checkpoint.SequenceNumber)
+
+	// A prune anchor without a matching checkpoint is a break
+	repo.head.PrunedThroughSequence = 5
+	repo.head.PrunedThroughHash = repo.entries[0].EntryHash
+	repo.purgeThrough(5)
+	result, err = service.VerifyChain(ctx, orgID)
+	require.NoError(t, err)
+	require.NotNil(t, result.FirstBrokenLink)
+	assert.Equal(t, domain.AuditChainBreakPruneAnchor, result.FirstBrokenLink.Reason)
+
+	_, err = service.PruneThrough(ctx, orgID, time.Now().Add(time.Hour))
+	assert.Error(t, err, "a broken chain must not be pruned")
+}
@@ -0,0 +1,269 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/parquet"
+)
+
+// Audit log export formats
+const (
+	AuditExportFormatCSV     = "csv"
+	AuditExportFormatNDJSON  = "ndjson"
+	AuditExportFormatParquet = "parquet"
+)
+
+// auditExportBatchSize is the number of records read per query while streaming an export
+const auditExportBatchSize = 1000
+
+// auditExportColumns is the column order of CSV and Parquet exports
+var auditExportColumns = []string{
+	"id", "sequence_number", "timestamp", "organization_id", "user_id", "action",
+	"resource_type", "resource_id", "ip_address", "user_agent", "metadata", "prev_hash", "entry_hash",
+}
+
+// AuditLogExport is a prepared, filtered audit log export that is streamed with WriteTo
+type AuditLogExport struct {
+	Format        string
+	ContentType   string
+	FileExtension string
+	NextCursor    string // Set when a page limit was given and more records follow
+
+	repo   domain.AuditLogExportRepository
+	filter domain.AuditLogFilter
+	after  *domain.AuditLogCursor
+	until  *domain.AuditLogCursor
+}
+
+// EncodeAuditLogCursor encodes a keyset position as an opaque cursor
+func EncodeAuditLogCursor(cursor *domain.AuditLogCursor) string {
+	raw := cursor.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeAuditLogCursor decodes a cursor produced by EncodeAuditLogCursor
+func DecodeAuditLogCursor(encoded string) (*domain.AuditLogCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &domain.AuditLogCursor{Timestamp: timestamp, ID: id}, nil
+}
+
+// WriteTo streams the export to w in batches, flushing w after each batch when it supports it.
+// It returns the number of records written.
+func (e *AuditLogExport) WriteTo(ctx context.Context, w io.Writer) (int64, error) {
+	records, err := newAuditRecordWriter(e.Format, w)
+	if err != nil {
+		return 0, err
+	}
+
+	var written int64
+	after := e.after
+	for {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+
+		logs, err := e.repo.ListForExport(e.filter, after, e.until, auditExportBatchSize)
+		if err != nil {
+			return written, err
+		}
+
+		for _, log := range logs {
+			if err := records.Write(log); err != nil {
+				return written, fmt.Errorf("failed to write audit log %s: %w", log.ID, err)
+			}
+			written++
+		}
+
+		if err := records.Flush(); err != nil {
+			return written, err
+		}
+		if flusher, ok := w.(interface{ Flush() error }); ok {
+			if err := flusher.Flush(); err != nil {
+				return written, err
+			}
+		}
+
+		if len(logs) < auditExportBatchSize {
+			break
+		}
+		last := logs[len(logs)-1]
+		after = &domain.AuditLogCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+
+	return written, records.Close()
+}
+
+// auditRecordWriter encodes audit logs in one export format
+type auditRecordWriter interface {
+	Write(log *domain.AuditLog) error
+	Flush() error
+	Close() error
+}
+
+func newAuditRecordWriter(format string, w io.Writer) (auditRecordWriter, error) {
+	switch format {
+	case AuditExportFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(auditExportColumns); err != nil {
+			return nil, err
+		}
+		return &csvAuditRecordWriter{w: cw}, nil
+	case AuditExportFormatNDJSON:
+		return &ndjsonAuditRecordWriter{encoder: json.NewEncoder(w)}, nil
+	case AuditExportFormatParquet:
+		pw, err := parquet.NewWriter(w, []parquet.Column{
+			{Name: "id", Type: parquet.String},
+			{Name: "sequence_number", Type: parquet.Int64, Optional: true},
+			{Name: "timestamp", Type: parquet.TimestampMicros},
+			{Name: "organization_id", Type: parquet.String},
+			{Name: "user_id", Type: parquet.String},
+			{Name: "action", Type: parquet.String},
+			{Name: "resource_type", Type: parquet.String},
+			{Name: "resource_id", Type: parquet.String},
+			{Name: "ip_address", Type: parquet.String},
+			{Name: "user_agent", Type: parquet.String},
+			{Name: "metadata", Type: parquet.String, Optional: true},
+			{Name: "prev_hash", Type: parquet.String, Optional: true},
+			{Name: "entry_hash", Type: parquet.String, Optional: true},
+		}, parquet.DefaultRowGroupSize)
+		if err != nil {
+			return nil, err
+		}
+		return &parquetAuditRecordWriter{w: pw}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+func auditMetadataJSON(log *domain.AuditLog) (string, error) {
+	if log.Metadata == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(log.Metadata)
+	return string(data), err
+}
+
+type csvAuditRecordWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvAuditRecordWriter) Write(log *domain.AuditLog) error {
+	metadata, err := auditMetadataJSON(log)
+	if err != nil {
+		return err
+	}
+	sequence := ""
+	if log.SequenceNumber != nil {
+		sequence = strconv.FormatInt(*log.SequenceNumber, 10)
+	}
+
+	return c.w.Write([]string{
+		log.ID.String(),
+		sequence,
+		log.Timestamp.UTC().Format(time.RFC3339Nano),
+		log.OrganizationID.String(),
+		log.UserID.String(),
+		string(log.Action),
+		log.ResourceType,
+		log.ResourceID.String(),
+		log.IPAddress,
+		log.UserAgent,
+		metadata,
+		log.PrevHash,
+		log.EntryHash,
+	})
+}
+
+func (c *csvAuditRecordWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvAuditRecordWriter) Close() error {
+	return c.Flush()
+}
+
+type ndjsonAuditRecordWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonAuditRecordWriter) Write(log *domain.AuditLog) error {
+	return n.encoder.Encode(log)
+}
+
+func (n *ndjsonAuditRecordWriter) Flush() error { return nil }
+
+func (n *ndjsonAuditRecordWriter) Close() error { return nil }
+
+type parquetAuditRecordWriter struct {
+	w *parquet.Writer
+}
+
+func (p *parquetAuditRecordWriter) Write(log *domain.AuditLog) error {
+	metadata, err := auditMetadataJSON(log)
+	if err != nil {
+		return err
+	}
+
+	return p.w.WriteRow(
+		log.ID.String(),
+		optionalInt64(log.SequenceNumber),
+		log.Timestamp,
+		log.OrganizationID.String(),
+		log.UserID.String(),
+		string(log.Action),
+		log.ResourceType,
+		log.ResourceID.String(),
+		log.IPAddress,
+		log.UserAgent,
+		optionalString(metadata),
+		optionalString(log.PrevHash),
+		optionalString(log.EntryHash),
+	)
+}
+
+// Flush is a no-op: row groups are written as they fill so the file stays columnar
+func (p *parquetAuditRecordWriter) Flush() error { return nil }
+
+func (p *parquetAuditRecordWriter) Close() error {
+	return p.w.Close()
+}
+
+func optionalInt64(v *int64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func optionalString(v string) interface{} {
+	if v == "" {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,166 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuditLogExportRepository serves records already sorted by (timestamp, id)
+type fakeAuditLogExportRepository struct {
+	logs []*domain.AuditLog
+}
+
+func auditLogAfter(log *domain.AuditLog, cursor *domain.AuditLogCursor) bool {
+	if !log.Timestamp.Equal(cursor.Timestamp) {
+		return log.Timestamp.After(cursor.Timestamp)
+	}
+	return log.ID.String() > cursor.ID.String()
+}
+
+func (r *fakeAuditLogExportRepository) ListForExport(filter domain.AuditLogFilter, after, until *domain.AuditLogCursor, limit int) ([]*domain.AuditLog, error) {
+	result := []*domain.AuditLog{}
+	for _, log := range r.logs {
+		if after != nil && !auditLogAfter(log, after) {
+			continue
+		}
+		if until != nil && auditLogAfter(log, until) {
+			break
+		}
+		if len(result) == limit {
+			break
+		}
+		result = append(result, log)
+	}
+	return result, nil
+}
+
+func (r *fakeAuditLogExportRepository) GetExportPageEnd(filter domain.AuditLogFilter, after *domain.AuditLogCursor, pageSize int) (*domain.AuditLogCursor, error) {
+	page, _ := r.ListForExport(filter, after, nil, pageSize+1)
+	if len(page) <= pageSize {
+		return nil, nil
+	}
+	last := page[pageSize-1]
+	return &domain.AuditLogCursor{Timestamp: last.Timestamp, ID: last.ID}, nil
+}
+
+func newTestExportService(count int) (*ComplianceService, *fakeAuditLogExportRepository) {
+	repo := &fakeAuditLogExportRepository{}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		repo.logs = append(repo.logs, &domain.AuditLog{
+			ID:           uuid.New(),
+			Action:       domain.AuditActionUpdate,
+			ResourceType: "agent",
+			ResourceID:   uuid.New(),
+			UserAgent:    "Mozilla/5.0 (X11, \"quoted\")\nsecond line",
+			Metadata:     map[string]interface{}{"index": i},
+			Timestamp:    start.Add(time.Duration(i) * time.Second),
+		})
+	}
+	return NewComplianceService(nil, repo, nil, nil), repo
+}
+
+func TestExportAuditLog_CSVEscaping(t *testing.T) {
+	service, repo := newTestExportService(3)
+
+	export, err := service.ExportAuditLog(context.Background(), domain.AuditLogFilter{}, AuditExportFormatCSV, "", 0)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	written, err := export.WriteTo(context.Background(), &out)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), written)
+
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, auditExportColumns, records[0])
+	assert.Equal(t, repo.logs[0].UserAgent, records[1][9], "commas, quotes and newlines survive")
+	assert.Equal(t, `{"index":0}`, records[1][10])
+}
+
+func TestExportAuditLog_NDJSONStreamsAllBatches(t *testing.T) {
+	service, repo := newTestExportService(auditExportBatchSize*2 + 5)
+
+	export, err := service.ExportAuditLog(context.Background(), domain.AuditLogFilter{}, AuditExportFormatNDJSON, "", 0)
+	require.NoError(t, err)
+	assert.Empty(t, export.NextCursor)
+
+	var out bytes.Buffer
+	buffered := bufio.NewWriter(&out)
+	written, err := export.WriteTo(context.Background(), buffered)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(repo.logs)), written)
+
+	scanner := bufio.NewScanner(&out)
+	lines := 0
+	for scanner.Scan() {
+		var log domain.AuditLog
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &log))
+		assert.Equal(t, repo.logs[lines].ID, log.ID)
+		lines++
+	}
+	assert.Equal(t, len(repo.logs), lines)
+}
+
+func TestExportAuditLog_CursorPagination(t *testing.T) {
+	service, repo := newTestExportService(25)
+
+	var seen []uuid.UUID
+	cursor := ""
+	for page := 0; page < 10; page++ {
+		export, err := service.ExportAuditLog(context.Background(), domain.AuditLogFilter{}, AuditExportFormatNDJSON, cursor, 10)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		_, err = export.WriteTo(context.Background(), &out)
+		require.NoError(t, err)
+
+		decoder := json.NewDecoder(&out)
+		for decoder.More() {
+			var log domain.AuditLog
+			require.NoError(t, decoder.Decode(&log))
+			seen = append(seen, log.ID)
+		}
+
+		if export.NextCursor == "" {
+			break
+		}
+		cursor = export.NextCursor
+	}
+
+	require.Len(t, seen, len(repo.logs))
+	for i, log := range repo.logs {
+		assert.Equal(t, log.ID, seen[i])
+	}
+
+	_, err := service.ExportAuditLog(context.Background(), domain.AuditLogFilter{}, AuditExportFormatNDJSON, "not-a-cursor", 10)
+	assert.EqualError(t, err, "invalid cursor")
+}
+
+func TestExportAuditLog_Parquet(t *testing.T) {
+	service, _ := newTestExportService(3)
+
+	export, err := service.ExportAuditLog(context.Background(), domain.AuditLogFilter{}, AuditExportFormatParquet, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "parquet", export.FileExtension)
+
+	var out bytes.Buffer
+	_, err = export.WriteTo(context.Background(), &out)
+	require.NoError(t, err)
+	assert.Equal(t, "PAR1", out.String()[:4])
+	assert.Equal(t, "PAR1", out.String()[out.Len()-4:])
+
+	_, err = service.ExportAuditLog(context.Background(), domain.AuditLogFilter{}, "xml", "", 0)
+	assert.EqualError(t, err, "unsupported export format: xml")
+}
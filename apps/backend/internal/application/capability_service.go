@@ -27,6 +27,7 @@ type CapabilityService struct {
 	auditRepo      domain.AuditLogRepository
 	trustCalc      domain.TrustScoreCalculator
 	trustScoreRepo domain.TrustScoreRepository
+	actionCatalog  *ActionCatalogService
 }
 
 // NewCapabilityService creates a new capability service
@@ -36,6 +37,7 @@ func NewCapabilityService(
 	auditRepo domain.AuditLogRepository,
 	trustCalc domain.TrustScoreCalculator,
 	trustScoreRepo domain.TrustScoreRepository,
+	actionCatalog *ActionCatalogService,
 ) *CapabilityService {
 	return &CapabilityService{
 		capabilityRepo: capabilityRepo,
@@ -43,6 +45,7 @@ func NewCapabilityService(
 		auditRepo:      auditRepo,
 		trustCalc:      trustCalc,
 		trustScoreRepo: trustScoreRepo,
+		actionCatalog:  actionCatalog,
 	}
 }
 
@@ -311,6 +314,11 @@ func (s *CapabilityService) AutoDetectCapabilities(
 	agentID uuid.UUID,
 	mcpMetadata map[string]interface{},
 ) error {
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil {
+		return fmt.Errorf("agent not found: %w", err)
+	}
+
 	// Extract tools/capabilities from MCP metadata
 	// MCP servers typically declare their tools in the registration payload
 	if tools, ok := mcpMetadata["tools"].([]interface{}); ok {
@@ -318,7 +326,7 @@ func (s *CapabilityService) AutoDetectCapabilities(
 			if toolMap, ok := tool.(map[string]interface{}); ok {
 				// Extract tool name and convert to capability type
 				toolName, _ := toolMap["name"].(string)
-				capabilityType := s.mcpToolToCapabilityType(toolName)
+				capabilityType := s.mcpToolToCapabilityType(ctx, agent.OrganizationID, toolName)
 
 				// Create capability with tool metadata as scope
 				capability := &domain.AgentCapability{
@@ -340,23 +348,11 @@ func (s *CapabilityService) AutoDetectCapabilities(
 	return nil
 }
 
-// mcpToolToCapabilityType maps MCP tool names to standard capability types
-func (s *CapabilityService) mcpToolToCapabilityType(toolName string) string {
-	// Map common MCP tool patterns to capability types
-	toolMap := map[string]string{
-		"read_file":       domain.CapabilityFileRead,
-		"write_file":      domain.CapabilityFileWrite,
-		"delete_file":     domain.CapabilityFileDelete,
-		"execute_command": domain.CapabilitySystemAdmin,
-		"query_database":  domain.CapabilityDBQuery,
-		"write_database":  domain.CapabilityDBWrite,
-		"call_api":        domain.CapabilityAPICall,
-		"export_data":     domain.CapabilityDataExport,
-	}
-
-	// Check for exact matches first
-	if capType, ok := toolMap[toolName]; ok {
-		return capType
+// mcpToolToCapabilityType maps MCP tool names to standard capability types through the
+// organization's action catalog, whose aliases list MCP tool names
+func (s *CapabilityService) mcpToolToCapabilityType(ctx context.Context, orgID uuid.UUID, toolName string) string {
+	if s.actionCatalog != nil {
+		return s.actionCatalog.CapabilityForTool(ctx, orgID, toolName)
 	}
 
 	// Fallback to mcp:tool_use with tool name as suffix
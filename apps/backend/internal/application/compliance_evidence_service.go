@@ -0,0 +1,998 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"embed"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+//go:embed compliance_catalogs/*.json
+var embeddedComplianceCatalogs embed.FS
+
+// Evidence sources referenced by control catalogs; each becomes one CSV artifact
+const (
+	EvidenceSourceAccessReview        = "access_review"
+	EvidenceSourceKeyRotation         = "key_rotation"
+	EvidenceSourceCapabilityApprovals = "capability_approvals"
+	EvidenceSourceAuditChain          = "audit_chain"
+	EvidenceSourceAuditActivity       = "audit_activity"
+	EvidenceSourceAgentInventory      = "agent_inventory"
+)
+
+// Control evaluation outcomes
+const (
+	ControlStatusPass   = "pass"
+	ControlStatusFail   = "fail"
+	ControlStatusManual = "manual" // No automated evidence; the auditor collects it outside AIM
+)
+
+// Files inside an evidence pack zip
+const (
+	EvidencePackManifestFile  = "pack.json"
+	EvidencePackSignatureFile = "signature.json"
+	evidencePackControlsFile  = "controls.csv"
+)
+
+// ComplianceCatalog declares a framework's controls and the AIM evidence that tests them
+type ComplianceCatalog struct {
+	Framework string              `json:"framework"`
+	Name      string              `json:"name"`
+	Version   string              `json:"version"`
+	Controls  []ComplianceControl `json:"controls"`
+}
+
+// ComplianceControl is one control of a framework
+type ComplianceControl struct {
+	ID          string                   `json:"id"`
+	Title       string                   `json:"title"`
+	Description string                   `json:"description"`
+	Checks      []ComplianceControlCheck `json:"checks"`
+}
+
+// ComplianceControlCheck is an automated test against an evidence source
+type ComplianceControlCheck struct {
+	Source    string   `json:"source"`
+	Check     string   `json:"check"`
+	Threshold *float64 `json:"threshold,omitempty"`
+}
+
+// ControlCheckResult is the outcome of one check
+type ControlCheckResult struct {
+	Source    string   `json:"source"`
+	Check     string   `json:"check"`
+	Threshold *float64 `json:"threshold,omitempty"`
+	Passed    bool     `json:"passed"`
+	Observed  float64  `json:"observed"`
+	Finding   string   `json:"finding"`
+}
+
+// ControlResult is the evaluated state of a control
+type ControlResult struct {
+	ID          string               `json:"id"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Status      string               `json:"status"`
+	Checks      []ControlCheckResult `json:"checks"`
+	Evidence    []string             `json:"evidence"` // Artifact file names
+}
+
+// EvidenceArtifact describes a CSV file of an evidence pack
+type EvidenceArtifact struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Rows   int    `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// EvidencePackSummary counts control outcomes
+type EvidencePackSummary struct {
+	Passed int     `json:"passed"`
+	Failed int     `json:"failed"`
+	Manual int     `json:"manual"`
+	Score  float64 `json:"score"` // Passed share of automated controls, 0-100
+}
+
+// EvidencePack is the auditor-facing result of evaluating a framework catalog
+type EvidencePack struct {
+	ID             uuid.UUID           `json:"id"`
+	OrganizationID uuid.UUID           `json:"organization_id"`
+	Framework      string              `json:"framework"`
+	FrameworkName  string              `json:"framework_name"`
+	CatalogVersion string              `json:"catalog_version"`
+	PeriodStart    time.Time           `json:"period_start"`
+	PeriodEnd      time.Time           `json:"period_end"`
+	GeneratedAt    time.Time           `json:"generated_at"`
+	GeneratedBy    uuid.UUID           `json:"generated_by"`
+	Summary        EvidencePackSummary `json:"summary"`
+	Controls       []ControlResult     `json:"controls"`
+	Artifacts      []EvidenceArtifact  `json:"artifacts"`
+
+	files map[string][]byte
+}
+
+// EvidencePackSignature is stored next to pack.json; it signs the exact bytes of pack.json,
+// which in turn pins every artifact by SHA-256
+type EvidencePackSignature struct {
+	Algorithm  string `json:"algorithm"`
+	KeyID      string `json:"key_id"`
+	PublicKey  string `json:"public_key"` // base64
+	SignedFile string `json:"signed_file"`
+	Signature  string `json:"signature"` // base64
+}
+
+// ComplianceEvidenceService builds framework evidence packs from declarative control catalogs
+type ComplianceEvidenceService struct {
+	userRepo              domain.UserRepository
+	apiKeyRepo            domain.APIKeyRepository
+	agentRepo             domain.AgentRepository
+	capabilityRequestRepo domain.CapabilityRequestRepository
+	exportRepo            domain.AuditLogExportRepository
+	auditChain            *AuditChainService
+	signingKey            ed25519.PrivateKey
+	catalogs              map[string]*ComplianceCatalog
+}
+
+// NewComplianceEvidenceService creates a new evidence service with the built-in catalogs
+func NewComplianceEvidenceService(
+	userRepo domain.UserRepository,
+	apiKeyRepo domain.APIKeyRepository,
+	agentRepo domain.AgentRepository,
+	capabilityRequestRepo domain.CapabilityRequestRepository,
+	exportRepo domain.AuditLogExportRepository,
+	auditChain *AuditChainService,
+	signingKey ed25519.PrivateKey,
+) (*ComplianceEvidenceService, error) {
+	catalogs, err := loadComplianceCatalogs()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ComplianceEvidenceService{
+		userRepo:              userRepo,
+		apiKeyRepo:            apiKeyRepo,
+		agentRepo:             agentRepo,
+		capabilityRequestRepo: capabilityRequestRepo,
+		exportRepo:            exportRepo,
+		auditChain:            auditChain,
+		signingKey:            signingKey,
+		catalogs:              catalogs,
+	}, nil
+}
+
+func loadComplianceCatalogs() (map[string]*ComplianceCatalog, error) {
+	entries, err := embeddedComplianceCatalogs.ReadDir("compliance_catalogs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compliance catalogs: %w", err)
+	}
+
+	catalogs := make(map[string]*ComplianceCatalog)
+	for _, entry := range entries {
+		data, err := embeddedComplianceCatalogs.ReadFile("compliance_catalogs/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read compliance catalog %s: %w", entry.Name(), err)
+		}
+
+		var catalog ComplianceCatalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, fmt.Errorf("failed to parse compliance catalog %s: %w", entry.Name(), err)
+		}
+		if err := validateComplianceCatalog(&catalog); err != nil {
+			return nil, fmt.Errorf("invalid compliance catalog %s: %w", entry.Name(), err)
+		}
+		catalogs[catalog.Framework] = &catalog
+	}
+
+	return catalogs, nil
+}
+
+func validateComplianceCatalog(catalog *ComplianceCatalog) error {
+	if catalog.Framework == "" {
+		return fmt.Errorf("framework is required")
+	}
+	for _, control := range catalog.Controls {
+		for _, check := range control.Checks {
+			if _, ok := evidenceChecks[check.Source][check.Check]; !ok {
+				return fmt.Errorf("control %s references unknown check %s.%s", control.ID, check.Source, check.Check)
+			}
+		}
+	}
+	return nil
+}
+
+// ListCatalogs returns the available framework catalogs
+func (s *ComplianceEvidenceService) ListCatalogs() []*ComplianceCatalog {
+	catalogs := make([]*ComplianceCatalog, 0, len(s.catalogs))
+	for _, catalog := range s.catalogs {
+		catalogs = append(catalogs, catalog)
+	}
+	sort.Slice(catalogs, func(i, j int) bool { return catalogs[i].Framework < catalogs[j].Framework })
+	return catalogs
+}
+
+// GenerateEvidencePack evaluates a framework's controls against current AIM data. Point-in-time
+// evidence (users, keys, agents, approvals, audit chain) reflects the generation time; audit
+// activity covers [start, end).
+func (s *ComplianceEvidenceService) GenerateEvidencePack(
+	ctx context.Context,
+	orgID, generatedBy uuid.UUID,
+	framework string,
+	start, end time.Time,
+) (*EvidencePack, error) {
+	catalog, ok := s.catalogs[framework]
+	if !ok {
+		return nil, fmt.Errorf("unknown compliance framework: %s", framework)
+	}
+
+	now := time.Now().UTC()
+	if end.IsZero() {
+		end = now
+	}
+	if start.IsZero() {
+		start = end.AddDate(0, 0, -90)
+	}
+	if !start.Before(end) {
+		return nil, fmt.Errorf("invalid period: start must be before end")
+	}
+
+	sources := map[string]bool{}
+	for _, control := range catalog.Controls {
+		for _, check := range control.Checks {
+			sources[check.Source] = true
+		}
+	}
+
+	evidence, err := s.collectEvidence(ctx, orgID, sources, start.UTC(), end.UTC(), now)
+	if err != nil {
+		return nil, err
+	}
+
+	pack := &EvidencePack{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Framework:      catalog.Framework,
+		FrameworkName:  catalog.Name,
+		CatalogVersion: catalog.Version,
+		PeriodStart:    start.UTC(),
+		PeriodEnd:      end.UTC(),
+		GeneratedAt:    now,
+		GeneratedBy:    generatedBy,
+		Controls:       []ControlResult{},
+		Artifacts:      []EvidenceArtifact{},
+		files:          map[string][]byte{},
+	}
+
+	for _, control := range catalog.Controls {
+		result := ControlResult{
+			ID:          control.ID,
+			Title:       control.Title,
+			Description: control.Description,
+			Status:      ControlStatusManual,
+			Checks:      []ControlCheckResult{},
+			Evidence:    []string{},
+		}
+
+		for _, check := range control.Checks {
+			threshold := 0.0
+			if check.Threshold != nil {
+				threshold = *check.Threshold
+			}
+			passed, observed, finding := evidenceChecks[check.Source][check.Check](evidence, threshold)
+			result.Checks = append(result.Checks, ControlCheckResult{
+				Source:    check.Source,
+				Check:     check.Check,
+				Threshold: check.Threshold,
+				Passed:    passed,
+				Observed:  observed,
+				Finding:   finding,
+			})
+
+			artifact := evidenceArtifactName(check.Source)
+			if !containsString(result.Evidence, artifact) {
+				result.Evidence = append(result.Evidence, artifact)
+			}
+
+			if !passed {
+				result.Status = ControlStatusFail
+			} else if result.Status == ControlStatusManual {
+				result.Status = ControlStatusPass
+			}
+		}
+
+		switch result.Status {
+		case ControlStatusPass:
+			pack.Summary.Passed++
+		case ControlStatusFail:
+			pack.Summary.Failed++
+		default:
+			pack.Summary.Manual++
+		}
+		pack.Controls = append(pack.Controls, result)
+	}
+
+	if automated := pack.Summary.Passed + pack.Summary.Failed; automated > 0 {
+		pack.Summary.Score = float64(pack.Summary.Passed) / float64(automated) * 100
+	}
+
+	// Build the CSV artifacts in a stable order
+	names := make([]string, 0, len(sources))
+	for source := range sources {
+		names = append(names, source)
+	}
+	sort.Strings(names)
+
+	for _, source := range names {
+		rows := evidence.rows(source)
+		if err := pack.addArtifact(evidenceArtifactName(source), source, rows); err != nil {
+			return nil, err
+		}
+	}
+	if err := pack.addArtifact(evidencePackControlsFile, "controls", pack.controlRows()); err != nil {
+		return nil, err
+	}
+
+	return pack, nil
+}
+
+func evidenceArtifactName(source string) string {
+	return "artifacts/" + source + ".csv"
+}
+
+func (p *EvidencePack) addArtifact(name, source string, rows [][]string) error {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	p.files[name] = buf.Bytes()
+	p.Artifacts = append(p.Artifacts, EvidenceArtifact{
+		Name:   name,
+		Source: source,
+		Rows:   len(rows) - 1,
+		SHA256: hex.EncodeToString(sum[:]),
+	})
+	return nil
+}
+
+func (p *EvidencePack) controlRows() [][]string {
+	rows := [][]string{{"control_id", "title", "status", "source", "check", "threshold", "observed", "passed", "finding"}}
+	for _, control := range p.Controls {
+		if len(control.Checks) == 0 {
+			rows = append(rows, []string{control.ID, control.Title, control.Status, "", "", "", "", "", "Evidence collected outside AIM"})
+			continue
+		}
+		for _, check := range control.Checks {
+			threshold := ""
+			if check.Threshold != nil {
+				threshold = formatEvidenceNumber(*check.Threshold)
+			}
+			rows = append(rows, []string{
+				control.ID,
+				control.Title,
+				control.Status,
+				check.Source,
+				check.Check,
+				threshold,
+				formatEvidenceNumber(check.Observed),
+				strconv.FormatBool(check.Passed),
+				check.Finding,
+			})
+		}
+	}
+	return rows
+}
+
+// WriteZip writes the pack as a zip of pack.json, the CSV artifacts and signature.json
+func (s *ComplianceEvidenceService) WriteZip(pack *EvidencePack, w io.Writer) error {
+	if s.signingKey == nil {
+		return fmt.Errorf("evidence signing key not configured")
+	}
+
+	manifest, err := json.MarshalIndent(pack, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal evidence pack: %w", err)
+	}
+
+	publicKey := s.signingKey.Public().(ed25519.PublicKey)
+	signature, err := json.MarshalIndent(EvidencePackSignature{
+		Algorithm:  "Ed25519",
+		KeyID:      AuditSigningKeyID(publicKey),
+		PublicKey:  base64.StdEncoding.EncodeToString(publicKey),
+		SignedFile: EvidencePackManifestFile,
+		Signature:  base64.StdEncoding.EncodeToString(ed25519.Sign(s.signingKey, manifest)),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal evidence signature: %w", err)
+	}
+
+	archive := zip.NewWriter(w)
+	write := func(name string, data []byte) error {
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: pack.GeneratedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", name, err)
+		}
+		_, err = file.Write(data)
+		return err
+	}
+
+	if err := write(EvidencePackManifestFile, manifest); err != nil {
+		return err
+	}
+	for _, artifact := range pack.Artifacts {
+		if err := write(artifact.Name, pack.files[artifact.Name]); err != nil {
+			return err
+		}
+	}
+	if err := write(EvidencePackSignatureFile, signature); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// VerifyEvidencePack checks a pack zip: the signature over pack.json must verify with one of
+// the trusted keys and every artifact must match its recorded SHA-256
+func VerifyEvidencePack(r io.ReaderAt, size int64, trustedKeys []ed25519.PublicKey) (*EvidencePack, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid evidence pack: %w", err)
+	}
+
+	files := map[string][]byte{}
+	for _, file := range archive.File {
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("invalid evidence pack: %w", err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid evidence pack: %w", err)
+		}
+		files[file.Name] = data
+	}
+
+	manifest, ok := files[EvidencePackManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("invalid evidence pack: %s missing", EvidencePackManifestFile)
+	}
+
+	var signature EvidencePackSignature
+	if err := json.Unmarshal(files[EvidencePackSignatureFile], &signature); err != nil {
+		return nil, fmt.Errorf("invalid evidence pack: %s missing or malformed", EvidencePackSignatureFile)
+	}
+	publicKey, err := base64.StdEncoding.DecodeString(signature.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid evidence pack: malformed public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid evidence pack: malformed signature")
+	}
+
+	trusted := false
+	for _, key := range trustedKeys {
+		if bytes.Equal(key, publicKey) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return nil, fmt.Errorf("evidence pack signed by untrusted key %s", signature.KeyID)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(publicKey), manifest, sig) {
+		return nil, fmt.Errorf("evidence pack signature does not verify")
+	}
+
+	var pack EvidencePack
+	if err := json.Unmarshal(manifest, &pack); err != nil {
+		return nil, fmt.Errorf("invalid evidence pack: %w", err)
+	}
+	for _, artifact := range pack.Artifacts {
+		data, ok := files[artifact.Name]
+		if !ok {
+			return nil, fmt.Errorf("evidence artifact %s missing", artifact.Name)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != artifact.SHA256 {
+			return nil, fmt.Errorf("evidence artifact %s was modified", artifact.Name)
+		}
+	}
+
+	return &pack, nil
+}
+
+// complianceEvidence holds the data collected for one pack
+type complianceEvidence struct {
+	now, start, end time.Time
+
+	users              []*domain.User
+	apiKeys            []*domain.APIKey
+	agents             []*domain.Agent
+	capabilityRequests []*domain.CapabilityRequestWithDetails
+	chain              *domain.AuditChainVerification
+	activity           map[string]map[string]int // day -> action -> count
+	activityTotal      int
+}
+
+func (s *ComplianceEvidenceService) collectEvidence(
+	ctx context.Context,
+	orgID uuid.UUID,
+	sources map[string]bool,
+	start, end, now time.Time,
+) (*complianceEvidence, error) {
+	evidence := &complianceEvidence{now: now, start: start, end: end}
+	var err error
+
+	if sources[EvidenceSourceAccessReview] {
+		if evidence.users, err = s.userRepo.GetByOrganization(orgID); err != nil {
+			return nil, fmt.Errorf("failed to collect access review evidence: %w", err)
+		}
+	}
+
+	if sources[EvidenceSourceKeyRotation] || sources[EvidenceSourceAgentInventory] {
+		if evidence.agents, err = s.agentRepo.GetByOrganization(orgID); err != nil {
+			return nil, fmt.Errorf("failed to collect agent evidence: %w", err)
+		}
+	}
+
+	if sources[EvidenceSourceKeyRotation] {
+		if evidence.apiKeys, err = s.apiKeyRepo.GetByOrganization(orgID); err != nil {
+			return nil, fmt.Errorf("failed to collect key rotation evidence: %w", err)
+		}
+	}
+
+	if sources[EvidenceSourceCapabilityApprovals] {
+		evidence.capabilityRequests, err = s.capabilityRequestRepo.List(domain.CapabilityRequestFilter{OrganizationID: &orgID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect capability approval evidence: %w", err)
+		}
+	}
+
+	if sources[EvidenceSourceAuditChain] {
+		if evidence.chain, err = s.auditChain.VerifyChain(ctx, orgID); err != nil {
+			return nil, fmt.Errorf("failed to verify audit chain: %w", err)
+		}
+	}
+
+	if sources[EvidenceSourceAuditActivity] {
+		if err := s.collectAuditActivity(ctx, orgID, evidence); err != nil {
+			return nil, err
+		}
+	}
+
+	return evidence, nil
+}
+
+// collectAuditActivity counts audit records per day and action over the period
+func (s *ComplianceEvidenceService) collectAuditActivity(ctx context.Context, orgID uuid.UUID, evidence *complianceEvidence) error {
+	filter := domain.AuditLogFilter{
+		OrganizationID: orgID,
+		StartDate:      &evidence.start,
+		EndDate:        &evidence.end,
+	}
+	evidence.activity = map[string]map[string]int{}
+
+	var after *domain.AuditLogCursor
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		logs, err := s.exportRepo.ListForExport(filter, after, nil, auditExportBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to collect audit activity evidence: %w", err)
+		}
+
+		for _, log := range logs {
+			day := log.Timestamp.UTC().Format("2006-01-02")
+			if evidence.activity[day] == nil {
+				evidence.activity[day] = map[string]int{}
+			}
+			evidence.activity[day][string(log.Action)]++
+			evidence.activityTotal++
+		}
+
+		if len(logs) < auditExportBatchSize {
+			return nil
+		}
+		last := logs[len(logs)-1]
+		after = &domain.AuditLogCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+}
+
+// rows renders an evidence source as CSV rows (header first)
+func (e *complianceEvidence) rows(source string) [][]string {
+	switch source {
+	case EvidenceSourceAccessReview:
+		rows := [][]string{{"user_id", "email", "name", "role", "status", "provider", "approved_by", "approved_at", "last_login_at", "days_since_login", "created_at"}}
+		for _, user := range e.users {
+			daysSinceLogin := ""
+			if user.LastLoginAt != nil {
+				daysSinceLogin = strconv.Itoa(daysBetween(*user.LastLoginAt, e.now))
+			}
+			rows = append(rows, []string{
+				user.ID.String(),
+				user.Email,
+				user.Name,
+				string(user.Role),
+				string(user.Status),
+				user.Provider,
+				formatEvidenceUUID(user.ApprovedBy),
+				formatEvidenceTime(user.ApprovedAt),
+				formatEvidenceTime(user.LastLoginAt),
+				daysSinceLogin,
+				formatEvidenceTime(&user.CreatedAt),
+			})
+		}
+		return rows
+
+	case EvidenceSourceKeyRotation:
+		rows := [][]string{{"credential_type", "credential_id", "agent_id", "name", "active", "created_at", "age_days", "expires_at", "last_used_at", "rotation_count"}}
+		for _, agent := range e.agents {
+			if agent.PublicKey == nil || agent.KeyCreatedAt == nil {
+				continue
+			}
+			rows = append(rows, []string{
+				"agent_key",
+				agent.ID.String(),
+				agent.ID.String(),
+				agent.Name,
+				strconv.FormatBool(agentCredentialActive(agent)),
+				formatEvidenceTime(agent.KeyCreatedAt),
+				strconv.Itoa(daysBetween(*agent.KeyCreatedAt, e.now)),
+				formatEvidenceTime(agent.KeyExpiresAt),
+				formatEvidenceTime(agent.LastActive),
+				strconv.Itoa(agent.RotationCount),
+			})
+		}
+		for _, key := range e.apiKeys {
+			rows = append(rows, []string{
+				"api_key",
+				key.ID.String(),
+				key.AgentID.String(),
+				key.Name,
+				strconv.FormatBool(key.IsActive),
+				formatEvidenceTime(&key.CreatedAt),
+				strconv.Itoa(daysBetween(key.CreatedAt, e.now)),
+				formatEvidenceTime(key.ExpiresAt),
+				formatEvidenceTime(key.LastUsedAt),
+				"",
+			})
+		}
+		return rows
+
+	case EvidenceSourceCapabilityApprovals:
+		rows := [][]string{{"request_id", "agent_id", "agent_name", "capability_type", "status", "requested_by", "requested_at", "reviewed_by", "reviewed_at", "decision_hours"}}
+		for _, req := range e.capabilityRequests {
+			decisionHours := ""
+			if req.ReviewedAt != nil {
+				decisionHours = formatEvidenceNumber(req.ReviewedAt.Sub(req.RequestedAt).Hours())
+			}
+			reviewedBy := formatEvidenceUUID(req.ReviewedBy)
+			if req.ReviewedByEmail != nil {
+				reviewedBy = *req.ReviewedByEmail
+			}
+			rows = append(rows, []string{
+				req.ID.String(),
+				req.AgentID.String(),
+				req.AgentName,
+				req.CapabilityType,
+				string(req.Status),
+				req.RequestedByEmail,
+				formatEvidenceTime(&req.RequestedAt),
+				reviewedBy,
+				formatEvidenceTime(req.ReviewedAt),
+				decisionHours,
+			})
+		}
+		return rows
+
+	case EvidenceSourceAuditChain:
+		rows := [][]string{{"metric", "value"}}
+		if e.chain == nil {
+			return rows
+		}
+		rows = append(rows,
+			[]string{"valid", strconv.FormatBool(e.chain.Valid)},
+			[]string{"entries_verified", strconv.FormatInt(e.chain.EntriesVerified, 10)},
+			[]string{"head_sequence", strconv.FormatInt(e.chain.HeadSequence, 10)},
+			[]string{"checkpoints_verified", strconv.Itoa(e.chain.CheckpointsVerified)},
+			[]string{"verified_at", formatEvidenceTime(&e.chain.VerifiedAt)},
+		)
+		if checkpoint := e.chain.LastCheckpoint; checkpoint != nil {
+			rows = append(rows,
+				[]string{"last_checkpoint_sequence", strconv.FormatInt(checkpoint.SequenceNumber, 10)},
+				[]string{"last_checkpoint_hash", checkpoint.EntryHash},
+				[]string{"last_checkpoint_key_id", checkpoint.KeyID},
+				[]string{"last_checkpoint_at", formatEvidenceTime(&checkpoint.CreatedAt)},
+			)
+		}
+		if brk := e.chain.FirstBrokenLink; brk != nil {
+			rows = append(rows,
+				[]string{"first_break_sequence", strconv.FormatInt(brk.SequenceNumber, 10)},
+				[]string{"first_break_reason", brk.Reason},
+			)
+		}
+		if len(e.chain.UntrustedKeyIDs) > 0 {
+			rows = append(rows, []string{"untrusted_key_ids", strings.Join(e.chain.UntrustedKeyIDs, " ")})
+		}
+		return rows
+
+	case EvidenceSourceAuditActivity:
+		rows := [][]string{{"date", "action", "count"}}
+		days := make([]string, 0, len(e.activity))
+		for day := range e.activity {
+			days = append(days, day)
+		}
+		sort.Strings(days)
+		for _, day := range days {
+			actions := make([]string, 0, len(e.activity[day]))
+			for action := range e.activity[day] {
+				actions = append(actions, action)
+			}
+			sort.Strings(actions)
+			for _, action := range actions {
+				rows = append(rows, []string{day, action, strconv.Itoa(e.activity[day][action])})
+			}
+		}
+		return rows
+
+	case EvidenceSourceAgentInventory:
+		rows := [][]string{{"agent_id", "name", "type", "status", "trust_score", "verified_at", "has_public_key", "capabilities", "talks_to", "capability_violations", "is_compromised", "last_active", "created_by"}}
+		for _, agent := range e.agents {
+			rows = append(rows, []string{
+				agent.ID.String(),
+				agent.Name,
+				string(agent.AgentType),
+				string(agent.Status),
+				formatEvidenceNumber(agent.TrustScore),
+				formatEvidenceTime(agent.VerifiedAt),
+				strconv.FormatBool(agent.PublicKey != nil && *agent.PublicKey != ""),
+				strings.Join(agent.Capabilities, " "),
+				strings.Join(agent.TalksTo, " "),
+				strconv.Itoa(agent.CapabilityViolationCount),
+				strconv.FormatBool(agent.IsCompromised),
+				formatEvidenceTime(agent.LastActive),
+				agent.CreatedBy.String(),
+			})
+		}
+		return rows
+	}
+
+	return [][]string{}
+}
+
+// evidenceCheck evaluates collected evidence against a threshold and returns whether it
+// passed, the observed value and a human-readable finding
+type evidenceCheck func(e *complianceEvidence, threshold float64) (bool, float64, string)
+
+var evidenceChecks = map[string]map[string]evidenceCheck{
+	EvidenceSourceAccessReview: {
+		// Active users who have not signed in (or never signed in) for threshold days
+		"inactive_users": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			count := 0
+			for _, user := range e.users {
+				if user.Status != domain.UserStatusActive {
+					continue
+				}
+				lastSeen := user.CreatedAt
+				if user.LastLoginAt != nil {
+					lastSeen = *user.LastLoginAt
+				}
+				if float64(daysBetween(lastSeen, e.now)) > threshold {
+					count++
+				}
+			}
+			return count == 0, float64(count), fmt.Sprintf("%d active user(s) without sign-in for more than %s days", count, formatEvidenceNumber(threshold))
+		},
+		// Registration requests waiting longer than threshold days
+		"stale_pending_users": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			count := 0
+			for _, user := range e.users {
+				if user.Status == domain.UserStatusPending && float64(daysBetween(user.CreatedAt, e.now)) > threshold {
+					count++
+				}
+			}
+			return count == 0, float64(count), fmt.Sprintf("%d user registration(s) pending for more than %s days", count, formatEvidenceNumber(threshold))
+		},
+		// Share of active users with the admin role, in percent (two admins are always allowed)
+		"admin_ratio": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			admins, active := 0, 0
+			for _, user := range e.users {
+				if user.Status != domain.UserStatusActive {
+					continue
+				}
+				active++
+				if user.Role == domain.RoleAdmin {
+					admins++
+				}
+			}
+			ratio := 0.0
+			if active > 0 {
+				ratio = float64(admins) / float64(active) * 100
+			}
+			return admins <= 2 || ratio <= threshold, ratio, fmt.Sprintf("%d of %d active user(s) are administrators (%s%%)", admins, active, formatEvidenceNumber(ratio))
+		},
+	},
+	EvidenceSourceKeyRotation: {
+		// Active agent keys and API keys older than threshold days
+		"max_credential_age_days": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			stale, oldest := 0, 0
+			for _, agent := range e.agents {
+				if agent.PublicKey == nil || agent.KeyCreatedAt == nil || !agentCredentialActive(agent) {
+					continue
+				}
+				age := daysBetween(*agent.KeyCreatedAt, e.now)
+				if age > oldest {
+					oldest = age
+				}
+				if float64(age) > threshold {
+					stale++
+				}
+			}
+			for _, key := range e.apiKeys {
+				if !key.IsActive {
+					continue
+				}
+				age := daysBetween(key.CreatedAt, e.now)
+				if age > oldest {
+					oldest = age
+				}
+				if float64(age) > threshold {
+					stale++
+				}
+			}
+			return stale == 0, float64(oldest), fmt.Sprintf("%d active credential(s) older than %s days; oldest is %d days", stale, formatEvidenceNumber(threshold), oldest)
+		},
+		// Active API keys past their expiry date
+		"expired_credentials_active": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			count := 0
+			for _, key := range e.apiKeys {
+				if key.IsActive && key.ExpiresAt != nil && key.ExpiresAt.Before(e.now) {
+					count++
+				}
+			}
+			return count == 0, float64(count), fmt.Sprintf("%d expired API key(s) still marked active", count)
+		},
+	},
+	EvidenceSourceCapabilityApprovals: {
+		// Requests decided by the same user who requested them
+		"self_approved_requests": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			count := 0
+			for _, req := range e.capabilityRequests {
+				if req.ReviewedBy != nil && *req.ReviewedBy == req.RequestedBy {
+					count++
+				}
+			}
+			return count == 0, float64(count), fmt.Sprintf("%d capability request(s) reviewed by their requester", count)
+		},
+		// Requests still pending after threshold days
+		"stale_pending_requests": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			count := 0
+			for _, req := range e.capabilityRequests {
+				if req.Status == domain.CapabilityRequestStatusPending && float64(daysBetween(req.RequestedAt, e.now)) > threshold {
+					count++
+				}
+			}
+			return count == 0, float64(count), fmt.Sprintf("%d capability request(s) pending for more than %s days", count, formatEvidenceNumber(threshold))
+		},
+	},
+	EvidenceSourceAuditChain: {
+		"chain_integrity": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			if e.chain.Valid {
+				return true, float64(e.chain.EntriesVerified), fmt.Sprintf("Audit chain intact: %d record(s) and %d checkpoint(s) verified", e.chain.EntriesVerified, e.chain.CheckpointsVerified)
+			}
+			finding := "Audit chain verification failed"
+			if brk := e.chain.FirstBrokenLink; brk != nil {
+				finding = fmt.Sprintf("Audit chain broken at sequence %d (%s)", brk.SequenceNumber, brk.Reason)
+			}
+			return false, float64(e.chain.EntriesVerified), finding
+		},
+		// Hours since the latest signed checkpoint
+		"checkpoint_age_hours": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			if e.chain.LastCheckpoint == nil {
+				return false, 0, "No signed audit checkpoint exists"
+			}
+			age := e.now.Sub(e.chain.LastCheckpoint.CreatedAt).Hours()
+			return age <= threshold, age, fmt.Sprintf("Latest signed checkpoint is %s hours old", formatEvidenceNumber(age))
+		},
+	},
+	EvidenceSourceAuditActivity: {
+		"logging_active": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			return e.activityTotal > 0, float64(e.activityTotal), fmt.Sprintf("%d audit record(s) in the period", e.activityTotal)
+		},
+	},
+	EvidenceSourceAgentInventory: {
+		// Agents that are not verified (revoked agents are out of scope); passes up to threshold
+		"unverified_agents": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			count := 0
+			for _, agent := range e.agents {
+				if agent.Status == domain.AgentStatusPending || agent.Status == domain.AgentStatusSuspended {
+					count++
+				}
+			}
+			return float64(count) <= threshold, float64(count), fmt.Sprintf("%d of %d agent(s) are pending or suspended", count, len(e.agents))
+		},
+		"compromised_agents": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			count := 0
+			for _, agent := range e.agents {
+				if agent.IsCompromised && agent.Status != domain.AgentStatusRevoked {
+					count++
+				}
+			}
+			return count == 0, float64(count), fmt.Sprintf("%d compromised agent(s) not revoked", count)
+		},
+		// Lowest trust score among verified agents (0-1 scale)
+		"min_trust_score": func(e *complianceEvidence, threshold float64) (bool, float64, string) {
+			below, lowest, verified := 0, 1.0, 0
+			for _, agent := range e.agents {
+				if agent.Status != domain.AgentStatusVerified {
+					continue
+				}
+				verified++
+				if agent.TrustScore < lowest {
+					lowest = agent.TrustScore
+				}
+				if agent.TrustScore < threshold {
+					below++
+				}
+			}
+			if verified == 0 {
+				lowest = 0
+			}
+			return below == 0, lowest, fmt.Sprintf("%d verified agent(s) below trust score %s", below, formatEvidenceNumber(threshold))
+		},
+	},
+}
+
+// agentCredentialActive reports whether an agent's key can still be used
+func agentCredentialActive(agent *domain.Agent) bool {
+	return agent.Status != domain.AgentStatusRevoked
+}
+
+func daysBetween(from, to time.Time) int {
+	if to.Before(from) {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func formatEvidenceTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatEvidenceUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func formatEvidenceNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,244 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCapabilityRequestRepository for testing
+type MockCapabilityRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockCapabilityRequestRepository) Create(req *domain.CapabilityRequest) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockCapabilityRequestRepository) GetByID(id uuid.UUID) (*domain.CapabilityRequestWithDetails, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CapabilityRequestWithDetails), args.Error(1)
+}
+
+func (m *MockCapabilityRequestRepository) List(filter domain.CapabilityRequestFilter) ([]*domain.CapabilityRequestWithDetails, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CapabilityRequestWithDetails), args.Error(1)
+}
+
+func (m *MockCapabilityRequestRepository) UpdateStatus(id uuid.UUID, status domain.CapabilityRequestStatus, reviewedBy uuid.UUID) error {
+	args := m.Called(id, status, reviewedBy)
+	return args.Error(0)
+}
+
+func (m *MockCapabilityRequestRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// evidenceTestData is an organization that passes every automated control
+type evidenceTestData struct {
+	users    []*domain.User
+	agents   []*domain.Agent
+	apiKeys  []*domain.APIKey
+	requests []*domain.CapabilityRequestWithDetails
+}
+
+func createEvidenceTestData() *evidenceTestData {
+	now := time.Now().UTC()
+	recent := now.Add(-24 * time.Hour)
+	keyCreated := now.AddDate(0, 0, -10)
+	reviewedAt := now.AddDate(0, 0, -2)
+	publicKey := "test-public-key"
+	adminID := uuid.New()
+	memberID := uuid.New()
+	agentID := uuid.New()
+
+	return &evidenceTestData{
+		users: []*domain.User{
+			{ID: adminID, Email: "admin@example.com", Role: domain.RoleAdmin, Status: domain.UserStatusActive, LastLoginAt: &recent, CreatedAt: now.AddDate(-1, 0, 0)},
+			{ID: memberID, Email: "member@example.com", Role: domain.RoleMember, Status: domain.UserStatusActive, LastLoginAt: &recent, CreatedAt: now.AddDate(-1, 0, 0)},
+		},
+		agents: []*domain.Agent{
+			{ID: agentID, Name: "billing-agent", Status: domain.AgentStatusVerified, TrustScore: 0.9, PublicKey: &publicKey, KeyCreatedAt: &keyCreated},
+		},
+		apiKeys: []*domain.APIKey{
+			{ID: uuid.New(), AgentID: agentID, Name: "ci", IsActive: true, CreatedAt: keyCreated},
+		},
+		requests: []*domain.CapabilityRequestWithDetails{
+			{CapabilityRequest: domain.CapabilityRequest{ID: uuid.New(), AgentID: agentID, CapabilityType: "data:export", Status: domain.CapabilityRequestStatusApproved, RequestedBy: memberID, ReviewedBy: &adminID, RequestedAt: now.AddDate(0, 0, -3), ReviewedAt: &reviewedAt}},
+		},
+	}
+}
+
+// setupEvidenceService wires the evidence service to mocks returning data for a checkpointed audit chain
+func setupEvidenceService(t *testing.T, data *evidenceTestData) (*ComplianceEvidenceService, uuid.UUID) {
+	chainRepo, chain, orgID := newTestAuditChain(t, 3)
+	_, err := chain.CreateCheckpoint(context.Background(), orgID)
+	require.NoError(t, err)
+
+	mockUserRepo := new(MockUserRepository)
+	mockAPIKeyRepo := new(MockAPIKeyRepository)
+	mockAgentRepo := new(MockAgentRepository)
+	mockCapabilityRequestRepo := new(MockCapabilityRequestRepository)
+
+	// Each framework only collects the evidence sources its controls reference
+	mockUserRepo.On("GetByOrganization", orgID).Return(data.users, nil).Maybe()
+	mockAgentRepo.On("GetByOrganization", orgID).Return(data.agents, nil).Maybe()
+	mockAPIKeyRepo.On("GetByOrganization", orgID).Return(data.apiKeys, nil).Maybe()
+	mockCapabilityRequestRepo.On("List", domain.CapabilityRequestFilter{OrganizationID: &orgID}).Return(data.requests, nil).Maybe()
+
+	exportRepo := &fakeAuditLogExportRepository{logs: chainRepo.entries}
+
+	service, err := NewComplianceEvidenceService(mockUserRepo, mockAPIKeyRepo, mockAgentRepo, mockCapabilityRequestRepo, exportRepo, chain, chain.signingKey)
+	require.NoError(t, err)
+
+	return service, orgID
+}
+
+func findControl(pack *EvidencePack, id string) *ControlResult {
+	for i := range pack.Controls {
+		if pack.Controls[i].ID == id {
+			return &pack.Controls[i]
+		}
+	}
+	return nil
+}
+
+func TestComplianceEvidence_CatalogsReferenceKnownChecks(t *testing.T) {
+	catalogs, err := loadComplianceCatalogs()
+	require.NoError(t, err)
+
+	for _, framework := range []string{"soc2", "iso27001", "eu_ai_act"} {
+		catalog, ok := catalogs[framework]
+		require.True(t, ok, framework)
+		assert.NotEmpty(t, catalog.Controls, framework)
+	}
+}
+
+func TestComplianceEvidence_CleanOrganizationPasses(t *testing.T) {
+	service, orgID := setupEvidenceService(t, createEvidenceTestData())
+
+	for _, catalog := range service.ListCatalogs() {
+		pack, err := service.GenerateEvidencePack(context.Background(), orgID, uuid.New(), catalog.Framework, time.Time{}, time.Time{})
+		require.NoError(t, err)
+
+		assert.Zero(t, pack.Summary.Failed, catalog.Framework)
+		assert.Equal(t, 100.0, pack.Summary.Score, catalog.Framework)
+		for _, control := range pack.Controls {
+			assert.NotEqual(t, ControlStatusFail, control.Status, "%s %s: %+v", catalog.Framework, control.ID, control.Checks)
+		}
+	}
+}
+
+func TestComplianceEvidence_FindingsFailControls(t *testing.T) {
+	data := createEvidenceTestData()
+	now := time.Now().UTC()
+
+	// A credential that has not been rotated for a year
+	data.apiKeys[0].CreatedAt = now.AddDate(-1, 0, 0)
+	// A capability request approved by its own requester
+	data.requests[0].ReviewedBy = &data.requests[0].RequestedBy
+
+	service, orgID := setupEvidenceService(t, data)
+
+	pack, err := service.GenerateEvidencePack(context.Background(), orgID, uuid.New(), "soc2", time.Time{}, time.Time{})
+	require.NoError(t, err)
+
+	rotation := findControl(pack, "CC6.1")
+	require.NotNil(t, rotation)
+	assert.Equal(t, ControlStatusFail, rotation.Status)
+
+	approvals := findControl(pack, "CC8.1")
+	require.NotNil(t, approvals)
+	assert.Equal(t, ControlStatusFail, approvals.Status)
+	assert.Contains(t, approvals.Evidence, "artifacts/capability_approvals.csv")
+
+	assert.Less(t, pack.Summary.Score, 100.0)
+}
+
+func TestComplianceEvidence_UnknownFrameworkAndPeriod(t *testing.T) {
+	service, orgID := setupEvidenceService(t, createEvidenceTestData())
+
+	_, err := service.GenerateEvidencePack(context.Background(), orgID, uuid.New(), "hipaa", time.Time{}, time.Time{})
+	assert.EqualError(t, err, "unknown compliance framework: hipaa")
+
+	now := time.Now()
+	_, err = service.GenerateEvidencePack(context.Background(), orgID, uuid.New(), "soc2", now, now.Add(-time.Hour))
+	assert.Error(t, err)
+}
+
+func TestComplianceEvidence_SignedZipVerifies(t *testing.T) {
+	service, orgID := setupEvidenceService(t, createEvidenceTestData())
+
+	pack, err := service.GenerateEvidencePack(context.Background(), orgID, uuid.New(), "iso27001", time.Time{}, time.Time{})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.WriteZip(pack, &buf))
+
+	trusted := []ed25519.PublicKey{service.signingKey.Public().(ed25519.PublicKey)}
+	verified, err := VerifyEvidencePack(bytes.NewReader(buf.Bytes()), int64(buf.Len()), trusted)
+	require.NoError(t, err)
+	assert.Equal(t, pack.ID, verified.ID)
+	assert.Equal(t, len(pack.Artifacts), len(verified.Artifacts))
+
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = VerifyEvidencePack(bytes.NewReader(buf.Bytes()), int64(buf.Len()), []ed25519.PublicKey{otherKey})
+	assert.ErrorContains(t, err, "untrusted key")
+}
+
+func TestComplianceEvidence_TamperedArtifactDetected(t *testing.T) {
+	service, orgID := setupEvidenceService(t, createEvidenceTestData())
+
+	pack, err := service.GenerateEvidencePack(context.Background(), orgID, uuid.New(), "soc2", time.Time{}, time.Time{})
+	require.NoError(t, err)
+
+	var original bytes.Buffer
+	require.NoError(t, service.WriteZip(pack, &original))
+
+	// Rewrite the zip with one artifact edited
+	reader, err := zip.NewReader(bytes.NewReader(original.Bytes()), int64(original.Len()))
+	require.NoError(t, err)
+
+	var tampered bytes.Buffer
+	writer := zip.NewWriter(&tampered)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+
+		if file.Name == "artifacts/access_review.csv" {
+			data = bytes.Replace(data, []byte("admin@example.com"), []byte("someone@example.com"), 1)
+		}
+		w, err := writer.Create(file.Name)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	trusted := []ed25519.PublicKey{service.signingKey.Public().(ed25519.PublicKey)}
+	_, err = VerifyEvidencePack(bytes.NewReader(tampered.Bytes()), int64(tampered.Len()), trusted)
+	assert.EqualError(t, err, "evidence artifact artifacts/access_review.csv was modified")
+}
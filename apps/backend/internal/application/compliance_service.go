@@ -2,7 +2,11 @@ package application
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -49,21 +53,24 @@ type AuditActivitySummary struct {
 
 // ComplianceService handles compliance reporting
 type ComplianceService struct {
-	auditRepo domain.AuditLogRepository
-	agentRepo domain.AgentRepository
-	userRepo  domain.UserRepository
+	auditRepo  domain.AuditLogRepository
+	exportRepo domain.AuditLogExportRepository
+	agentRepo  domain.AgentRepository
+	userRepo   domain.UserRepository
 }
 
 // NewComplianceService creates a new compliance service
 func NewComplianceService(
 	auditRepo domain.AuditLogRepository,
+	exportRepo domain.AuditLogExportRepository,
 	agentRepo domain.AgentRepository,
 	userRepo domain.UserRepository,
 ) *ComplianceService {
 	return &ComplianceService{
-		auditRepo: auditRepo,
-		agentRepo: agentRepo,
-		userRepo:  userRepo,
+		auditRepo:  auditRepo,
+		exportRepo: exportRepo,
+		agentRepo:  agentRepo,
+		userRepo:   userRepo,
 	}
 }
 
@@ -206,50 +213,120 @@ func (s *ComplianceService) generateRecommendations(summary ComplianceSummary, a
 	return recommendations
 }
 
-// ExportAuditLog exports audit logs for compliance
+// ExportAuditLog prepares a filtered audit log export. Records are streamed in (timestamp, id)
+// order from the position after cursor; when limit > 0 the export stops after limit records and
+// NextCursor continues from there.
 func (s *ComplianceService) ExportAuditLog(
+	ctx context.Context,
+	filter domain.AuditLogFilter,
+	format string,
+	cursor string,
+	limit int,
+) (*AuditLogExport, error) {
+	export := &AuditLogExport{
+		Format: format,
+		repo:   s.exportRepo,
+		filter: filter,
+	}
+
+	switch format {
+	case AuditExportFormatCSV:
+		export.ContentType, export.FileExtension = "text/csv", "csv"
+	case AuditExportFormatNDJSON:
+		export.ContentType, export.FileExtension = "application/x-ndjson", "ndjson"
+	case AuditExportFormatParquet:
+		export.ContentType, export.FileExtension = "application/vnd.apache.parquet", "parquet"
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	if cursor != "" {
+		after, err := DecodeAuditLogCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		export.after = after
+	}
+
+	if limit > 0 {
+		until, err := s.exportRepo.GetExportPageEnd(filter, export.after, limit)
+		if err != nil {
+			return nil, err
+		}
+		if until != nil {
+			export.until = until
+			export.NextCursor = EncodeAuditLogCursor(until)
+		}
+	}
+
+	return export, nil
+}
+
+// ExportComplianceReport writes the compliance status and metrics for a period as JSON or CSV.
+// The CSV has one row per value: section, date, metric, value.
+func (s *ComplianceService) ExportComplianceReport(
 	ctx context.Context,
 	orgID uuid.UUID,
 	startDate time.Time,
 	endDate time.Time,
 	format string,
-) (string, error) {
-	// Get audit logs for the time period
-	logs, err := s.auditRepo.GetByOrganization(orgID, 10000, 0)
-	if err != nil {
-		return "", err
+	w io.Writer,
+) error {
+	if endDate.IsZero() {
+		endDate = time.Now()
+	}
+	if startDate.IsZero() {
+		startDate = endDate.AddDate(0, 0, -30)
 	}
 
-	// Filter by date range
-	var filteredLogs []*domain.AuditLog
-	for _, log := range logs {
-		if log.Timestamp.After(startDate) && log.Timestamp.Before(endDate) {
-			filteredLogs = append(filteredLogs, log)
-		}
+	status, err := s.GetComplianceStatus(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	metrics, err := s.GetComplianceMetrics(ctx, orgID, startDate, endDate, "day")
+	if err != nil {
+		return err
 	}
 
-	// Export based on format
 	switch format {
 	case "json":
-		// Simple JSON export - in production would use json.Marshal
-		return fmt.Sprintf(`{"organization_id": "%s", "total_logs": %d, "start_date": "%s", "end_date": "%s"}`,
-			orgID.String(), len(filteredLogs), startDate.Format(time.RFC3339), endDate.Format(time.RFC3339)), nil
+		return json.NewEncoder(w).Encode(map[string]interface{}{
+			"generated_at":    time.Now().Format(time.RFC3339),
+			"organization_id": orgID,
+			"status":          status,
+			"metrics":         metrics,
+		})
 	case "csv":
-		// Simple CSV export
-		csv := "timestamp,user_id,action,resource_type,resource_id,ip_address\n"
-		for _, log := range filteredLogs {
-			csv += fmt.Sprintf("%s,%s,%s,%s,%s,%s\n",
-				log.Timestamp.Format(time.RFC3339),
-				log.UserID.String(),
-				log.Action,
-				log.ResourceType,
-				log.ResourceID.String(),
-				log.IPAddress,
-			)
-		}
-		return csv, nil
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"section", "date", "metric", "value"})
+
+		statusValues, _ := status.(map[string]interface{})
+		keys := make([]string, 0, len(statusValues))
+		for key := range statusValues {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			cw.Write([]string{"status", "", key, fmt.Sprint(statusValues[key])})
+		}
+
+		metricValues, _ := metrics.(map[string]interface{})
+		for _, section := range []string{"agent_verification_trend", "trust_score_trend"} {
+			points, _ := metricValues[section].([]map[string]interface{})
+			for _, point := range points {
+				date := fmt.Sprint(point["date"])
+				for key, value := range point {
+					if key != "date" {
+						cw.Write([]string{section, date, key, fmt.Sprint(value)})
+					}
+				}
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
 	default:
-		return "", fmt.Errorf("unsupported export format: %s", format)
+		return fmt.Errorf("unsupported export format: %s", format)
 	}
 }
 
@@ -508,28 +585,6 @@ func (s *ComplianceService) GetAccessReview(ctx context.Context, orgID uuid.UUID
 	return review, nil
 }
 
-// GetDataRetentionStatus returns data retention policy status
-func (s *ComplianceService) GetDataRetentionStatus(ctx context.Context, orgID uuid.UUID) (interface{}, error) {
-	// Get audit logs count
-	logs, _ := s.auditRepo.GetByOrganization(orgID, 10000, 0)
-
-	retention := map[string]interface{}{
-		"policy": map[string]interface{}{
-			"audit_logs_retention_days": 365,
-			"agent_data_retention_days": 730,
-			"user_data_retention_days":  365,
-		},
-		"current_status": map[string]interface{}{
-			"total_audit_logs":       len(logs),
-			"oldest_audit_log":       time.Now().AddDate(0, 0, -90).Format("2006-01-02"),
-			"data_within_policy":     true,
-			"cleanup_scheduled_date": time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
-		},
-	}
-
-	return retention, nil
-}
-
 // ComplianceCheckResult represents compliance check results
 type ComplianceCheckResult struct {
 	CheckType   string                   `json:"check_type"`
@@ -1429,56 +1484,6 @@ func (s *ComplianceService) ListAccessReviews(
 	return reviews, nil
 }
 
-// DataRetentionPolicy represents data retention settings
-type DataRetentionPolicy struct {
-	AuditLogRetentionDays          int    `json:"audit_log_retention_days"`
-	VerificationEventRetentionDays int    `json:"verification_event_retention_days"`
-	AlertRetentionDays             int    `json:"alert_retention_days"`
-	InactiveAgentRetentionDays     int    `json:"inactive_agent_retention_days"`
-	LastUpdated                    string `json:"last_updated"`
-	EnforcementStatus              string `json:"enforcement_status"`
-}
-
-// GetDataRetentionPolicies returns data retention policies
-func (s *ComplianceService) GetDataRetentionPolicies(
-	ctx context.Context,
-	orgID uuid.UUID,
-) (map[string]interface{}, error) {
-	policy := DataRetentionPolicy{
-		AuditLogRetentionDays:          365, // 1 year
-		VerificationEventRetentionDays: 90,  // 3 months
-		AlertRetentionDays:             180, // 6 months
-		InactiveAgentRetentionDays:     730, // 2 years
-		LastUpdated:                    time.Now().AddDate(0, -1, 0).Format("2006-01-02"),
-		EnforcementStatus:              "active",
-	}
-
-	// Get current data stats
-	logs, _ := s.auditRepo.GetByOrganization(orgID, 10000, 0)
-	agents, _ := s.agentRepo.GetByOrganization(orgID)
-
-	// Calculate oldest records
-	oldestAuditLog := time.Now()
-	if len(logs) > 0 {
-		for _, log := range logs {
-			if log.Timestamp.Before(oldestAuditLog) {
-				oldestAuditLog = log.Timestamp
-			}
-		}
-	}
-
-	return map[string]interface{}{
-		"policy": policy,
-		"current_status": map[string]interface{}{
-			"total_audit_logs":       len(logs),
-			"total_agents":           len(agents),
-			"oldest_audit_log":       oldestAuditLog.Format("2006-01-02"),
-			"data_within_policy":     true,
-			"cleanup_scheduled_date": time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
-		},
-	}, nil
-}
-
 // Helper functions for compliance reports
 
 func (s *ComplianceService) calculateFrameworkScore(agents []*domain.Agent, framework string) float64 {
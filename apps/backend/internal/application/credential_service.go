@@ -0,0 +1,639 @@
+package application
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/auth"
+)
+
+var (
+	ErrCredentialNotFound       = errors.New("credential not found")
+	ErrCredentialUnavailable    = errors.New("credentials are only issued to verified agents")
+	ErrInvalidCredentialRequest = errors.New("invalid credential request")
+	ErrDIDNotFound              = errors.New("DID not found")
+	ErrStatusListNotFound       = errors.New("status list not found")
+)
+
+const (
+	// defaultCredentialValidity is how long a credential is valid unless the issuer asks otherwise
+	defaultCredentialValidity = 90 * 24 * time.Hour
+	// maxCredentialValidityDays bounds credential lifetimes; revocation covers the rest
+	maxCredentialValidityDays = 365
+	// MaxCredentialValidity is the longest a credential stays valid; the keys that sign
+	// credentials must keep verifying at least this long after they retire
+	MaxCredentialValidity = maxCredentialValidityDays * 24 * time.Hour
+	// statusListTTL is how long a signed status list is valid. Verifiers refetch it after that,
+	// so it bounds how long a revocation can go unnoticed.
+	statusListTTL = time.Hour
+)
+
+// credentialContexts are the JSON-LD contexts of the credentials AIM issues
+var credentialContexts = []string{
+	"https://www.w3.org/2018/credentials/v1",
+	"https://w3id.org/vc/status-list/2021/v1",
+}
+
+// ed25519Multicodec is the multicodec prefix of an Ed25519 public key (ed25519-pub, 0xed)
+var ed25519Multicodec = []byte{0xed, 0x01}
+
+// IssueCredentialRequest issues a verifiable credential to an agent
+type IssueCredentialRequest struct {
+	DIDMethod    string `json:"didMethod"`    // "web" (default) or "key"
+	ValidForDays int    `json:"validForDays"` // Defaults to 90
+}
+
+// IssuedCredential is a newly issued credential and its VC-JWT encoding
+type IssuedCredential struct {
+	Credential *domain.AgentCredential `json:"credential"`
+	JWT        string                  `json:"jwt"`
+}
+
+// AgentDIDs are the identifiers an agent can be referred to by
+type AgentDIDs struct {
+	DIDWeb   string              `json:"didWeb"`
+	DIDKey   string              `json:"didKey,omitempty"` // Only for agents with an Ed25519 key
+	Document *domain.DIDDocument `json:"document"`
+}
+
+// credentialKeySource signs credentials and publishes the keys in AIM's DID document
+type credentialKeySource interface {
+	auth.SigningKeySource
+	JWKS() *domain.JSONWebKeySet
+}
+
+// agentCredentialClaims are the claims of a VC-JWT
+type agentCredentialClaims struct {
+	VC domain.VerifiableCredential `json:"vc"`
+	jwt.RegisteredClaims
+}
+
+// CredentialService gives agents decentralized identifiers and issues W3C Verifiable Credentials
+// that let them prove their identity to other organizations without calling AIM. AIM is
+// identified by a did:web of its issuer URL whose document publishes the user token signing
+// keys; credentials are VC-JWTs signed with those keys and revoked through StatusList2021 lists.
+type CredentialService struct {
+	credentialRepo domain.AgentCredentialRepository
+	agentRepo      domain.AgentRepository
+	capabilityRepo domain.CapabilityRepository
+	orgRepo        domain.OrganizationRepository
+	keys           credentialKeySource
+	issuer         string
+	issuerDID      string
+	now            func() time.Time
+}
+
+// NewCredentialService creates a new credential service
+func NewCredentialService(
+	credentialRepo domain.AgentCredentialRepository,
+	agentRepo domain.AgentRepository,
+	capabilityRepo domain.CapabilityRepository,
+	orgRepo domain.OrganizationRepository,
+	keys credentialKeySource,
+	issuer string,
+) *CredentialService {
+	issuer = strings.TrimRight(issuer, "/")
+	return &CredentialService{
+		credentialRepo: credentialRepo,
+		agentRepo:      agentRepo,
+		capabilityRepo: capabilityRepo,
+		orgRepo:        orgRepo,
+		keys:           keys,
+		issuer:         issuer,
+		issuerDID:      didWebFromURL(issuer),
+		now:            time.Now,
+	}
+}
+
+// IssuerDID returns AIM's own DID
+func (s *CredentialService) IssuerDID() string {
+	return s.issuerDID
+}
+
+// StatusListURL returns the URL a status list credential is published at
+func (s *CredentialService) StatusListURL(listID uuid.UUID) string {
+	return s.issuer + "/credentials/status/" + listID.String()
+}
+
+// agentWebDID returns the did:web of an agent, resolved at {issuer}/agents/{id}/did.json
+func (s *CredentialService) agentWebDID(agentID uuid.UUID) string {
+	return s.issuerDID + ":agents:" + agentID.String()
+}
+
+// IssuerDIDDocument returns AIM's DID document, listing every published signing key
+func (s *CredentialService) IssuerDIDDocument() *domain.DIDDocument {
+	document := &domain.DIDDocument{
+		Context:            []string{"https://www.w3.org/ns/did/v1", "https://w3id.org/security/suites/jws-2020/v1"},
+		ID:                 s.issuerDID,
+		VerificationMethod: []domain.DIDVerificationMethod{},
+		AssertionMethod:    []string{},
+	}
+	for _, jwk := range s.keys.JWKS().Keys {
+		jwk := jwk
+		methodID := s.issuerDID + "#" + jwk.KeyID
+		document.VerificationMethod = append(document.VerificationMethod, domain.DIDVerificationMethod{
+			ID:           methodID,
+			Type:         "JsonWebKey2020",
+			Controller:   s.issuerDID,
+			PublicKeyJwk: &jwk,
+		})
+		document.AssertionMethod = append(document.AssertionMethod, methodID)
+	}
+	return document
+}
+
+// AgentDIDDocument resolves an agent's did:web. Revoked agents' DIDs are deactivated.
+func (s *CredentialService) AgentDIDDocument(ctx context.Context, agentID uuid.UUID) (*domain.DIDDocument, error) {
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil || agent.Status == domain.AgentStatusRevoked {
+		return nil, ErrDIDNotFound
+	}
+	return s.agentDIDDocument(agent), nil
+}
+
+// GetAgentDIDs returns an agent's DIDs and DID document
+func (s *CredentialService) GetAgentDIDs(ctx context.Context, orgID, agentID uuid.UUID) (*AgentDIDs, error) {
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil || agent.OrganizationID != orgID {
+		return nil, fmt.Errorf("agent not found")
+	}
+
+	dids := &AgentDIDs{
+		DIDWeb:   s.agentWebDID(agent.ID),
+		Document: s.agentDIDDocument(agent),
+	}
+	if key, ok := agentEd25519Key(agent); ok {
+		dids.DIDKey = didKeyFromEd25519(key)
+	}
+	return dids, nil
+}
+
+func (s *CredentialService) agentDIDDocument(agent *domain.Agent) *domain.DIDDocument {
+	id := s.agentWebDID(agent.ID)
+	document := &domain.DIDDocument{
+		Context:            []string{"https://www.w3.org/ns/did/v1", "https://w3id.org/security/suites/ed25519-2020/v1"},
+		ID:                 id,
+		Controller:         s.issuerDID,
+		VerificationMethod: []domain.DIDVerificationMethod{},
+	}
+
+	if key, ok := agentEd25519Key(agent); ok {
+		multibase := multibaseEd25519(key)
+		methodID := id + "#" + multibase
+		document.AlsoKnownAs = []string{"did:key:" + multibase}
+		document.VerificationMethod = append(document.VerificationMethod, domain.DIDVerificationMethod{
+			ID:                 methodID,
+			Type:               "Ed25519VerificationKey2020",
+			Controller:         id,
+			PublicKeyMultibase: multibase,
+		})
+		document.Authentication = []string{methodID}
+		document.AssertionMethod = []string{methodID}
+	}
+	return document
+}
+
+// Issue issues a credential asserting an agent's verification status, owner organization and
+// granted capabilities. The VC-JWT is only returned here; AIM keeps the record for revocation.
+func (s *CredentialService) Issue(ctx context.Context, orgID, agentID, userID uuid.UUID, req *IssueCredentialRequest) (*IssuedCredential, error) {
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil || agent.OrganizationID != orgID {
+		return nil, fmt.Errorf("agent not found")
+	}
+	if agent.Status != domain.AgentStatusVerified || agent.IsCompromised {
+		return nil, ErrCredentialUnavailable
+	}
+
+	validity := defaultCredentialValidity
+	if req.ValidForDays != 0 {
+		if req.ValidForDays < 1 || req.ValidForDays > maxCredentialValidityDays {
+			return nil, fmt.Errorf("%w: validForDays must be between 1 and %d", ErrInvalidCredentialRequest, maxCredentialValidityDays)
+		}
+		validity = time.Duration(req.ValidForDays) * 24 * time.Hour
+	}
+
+	var subjectDID string
+	switch req.DIDMethod {
+	case "", domain.DIDMethodWeb:
+		subjectDID = s.agentWebDID(agent.ID)
+	case domain.DIDMethodKey:
+		key, ok := agentEd25519Key(agent)
+		if !ok {
+			return nil, fmt.Errorf("%w: agent has no Ed25519 key to derive a did:key from", ErrInvalidCredentialRequest)
+		}
+		subjectDID = didKeyFromEd25519(key)
+	default:
+		return nil, fmt.Errorf("%w: unsupported DID method %q", ErrInvalidCredentialRequest, req.DIDMethod)
+	}
+
+	capabilities, err := grantedCapabilityTypes(s.capabilityRepo, agent.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// JWT times have second precision; keep the record identical to the credential
+	now := s.now().UTC().Truncate(time.Second)
+	credential := &domain.AgentCredential{
+		ID:             uuid.New(),
+		AgentID:        agent.ID,
+		OrganizationID: agent.OrganizationID,
+		SubjectDID:     subjectDID,
+		Capabilities:   capabilities,
+		IssuedBy:       &userID,
+		IssuedAt:       now,
+		ExpiresAt:      now.Add(validity),
+	}
+	if err := s.credentialRepo.Create(credential); err != nil {
+		return nil, err
+	}
+
+	token, err := s.signCredential(credential, agent)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("🪪 Issued credential %s to agent %s as %s\n", credential.ID, agent.ID, subjectDID)
+	return &IssuedCredential{Credential: credential, JWT: token}, nil
+}
+
+// signCredential encodes an agent identity credential as a VC-JWT
+func (s *CredentialService) signCredential(credential *domain.AgentCredential, agent *domain.Agent) (string, error) {
+	organization := map[string]interface{}{"id": agent.OrganizationID.String()}
+	if org, err := s.orgRepo.GetByID(agent.OrganizationID); err == nil && org != nil {
+		organization["name"] = org.Name
+		if org.Domain != "" {
+			organization["domain"] = org.Domain
+		}
+	}
+
+	subject := map[string]interface{}{
+		"id":                 credential.SubjectDID,
+		"type":               "Agent",
+		"agentId":            agent.ID.String(),
+		"name":               agent.Name,
+		"verificationStatus": string(agent.Status),
+		"organization":       organization,
+		"capabilities":       credential.Capabilities,
+		"trustScore":         agent.TrustScore,
+	}
+	if agent.VerifiedAt != nil {
+		subject["verifiedAt"] = agent.VerifiedAt.UTC().Format(time.RFC3339)
+	}
+
+	listURL := s.StatusListURL(credential.StatusListID)
+	index := strconv.Itoa(credential.StatusListIndex)
+	vc := domain.VerifiableCredential{
+		Context:           credentialContexts,
+		ID:                "urn:uuid:" + credential.ID.String(),
+		Type:              []string{domain.CredentialTypeVerifiable, domain.CredentialTypeAgentIdentity},
+		Issuer:            s.issuerDID,
+		IssuanceDate:      credential.IssuedAt.Format(time.RFC3339),
+		ExpirationDate:    credential.ExpiresAt.Format(time.RFC3339),
+		CredentialSubject: subject,
+		CredentialStatus: &domain.CredentialStatus{
+			ID:                   listURL + "#" + index,
+			Type:                 "StatusList2021Entry",
+			StatusPurpose:        "revocation",
+			StatusListIndex:      index,
+			StatusListCredential: listURL,
+		},
+	}
+
+	return s.sign(agentCredentialClaims{
+		VC: vc,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuerDID,
+			Subject:   credential.SubjectDID,
+			ID:        vc.ID,
+			IssuedAt:  jwt.NewNumericDate(credential.IssuedAt),
+			NotBefore: jwt.NewNumericDate(credential.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(credential.ExpiresAt),
+		},
+	})
+}
+
+// sign signs claims with the current key, naming it by its DID URL
+func (s *CredentialService) sign(claims jwt.Claims) (string, error) {
+	kid, alg, key, err := s.keys.CurrentSigningKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to get signing key: %w", err)
+	}
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return "", fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = s.issuerDID + "#" + kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign credential: %w", err)
+	}
+	return signed, nil
+}
+
+// ListCredentials returns the credentials issued to an agent
+func (s *CredentialService) ListCredentials(ctx context.Context, orgID, agentID uuid.UUID) ([]*domain.AgentCredential, error) {
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil || agent.OrganizationID != orgID {
+		return nil, fmt.Errorf("agent not found")
+	}
+	return s.credentialRepo.ListByAgent(agentID)
+}
+
+// Revoke revokes a credential; it is listed as revoked in its status list from now on
+func (s *CredentialService) Revoke(ctx context.Context, orgID, agentID, credentialID uuid.UUID, reason string) error {
+	credential, err := s.credentialRepo.GetByID(credentialID)
+	if err != nil {
+		return err
+	}
+	if credential == nil || credential.AgentID != agentID || credential.OrganizationID != orgID {
+		return ErrCredentialNotFound
+	}
+	if reason == "" {
+		reason = "revoked"
+	}
+	return s.credentialRepo.Revoke(credential.ID, reason, s.now())
+}
+
+// StatusListCredential returns a status list as a signed StatusList2021Credential. The list is
+// rebuilt from the credential records, so it also covers agents that were suspended or deleted.
+func (s *CredentialService) StatusListCredential(ctx context.Context, listID uuid.UUID) (string, error) {
+	exists, err := s.credentialRepo.StatusListExists(listID)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", ErrStatusListNotFound
+	}
+
+	revoked, err := s.credentialRepo.RevokedIndexes(listID)
+	if err != nil {
+		return "", err
+	}
+	encodedList, err := encodeStatusList(revoked, domain.StatusListSize)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	listURL := s.StatusListURL(listID)
+	vc := domain.VerifiableCredential{
+		Context:        credentialContexts,
+		ID:             listURL,
+		Type:           []string{domain.CredentialTypeVerifiable, domain.CredentialTypeStatusList},
+		Issuer:         s.issuerDID,
+		IssuanceDate:   now.Format(time.RFC3339),
+		ExpirationDate: now.Add(statusListTTL).Format(time.RFC3339),
+		CredentialSubject: map[string]interface{}{
+			"id":            listURL + "#list",
+			"type":          "StatusList2021",
+			"statusPurpose": "revocation",
+			"encodedList":   encodedList,
+		},
+	}
+
+	return s.sign(agentCredentialClaims{
+		VC: vc,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuerDID,
+			Subject:   listURL + "#list",
+			ID:        listURL,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(statusListTTL)),
+		},
+	})
+}
+
+// Verify checks an agent identity credential the way an offline verifier would: its signature
+// against AIM's DID document, its validity period and its entry in the status list. Problems are
+// reported in the result; an error means the check itself could not be completed.
+func (s *CredentialService) Verify(ctx context.Context, token string) (*domain.CredentialVerification, error) {
+	result := &domain.CredentialVerification{}
+	fail := func(format string, args ...interface{}) {
+		result.Errors = append(result.Errors, fmt.Sprintf(format, args...))
+	}
+
+	claims := &agentCredentialClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, s.keyFunc,
+		jwt.WithValidMethods([]string{"EdDSA", "ES256"}),
+		jwt.WithIssuer(s.issuerDID),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.now),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, jwt.ErrTokenMalformed):
+			fail("credential is not a VC-JWT")
+			return result, nil
+		case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+			fail("credential was not issued by %s", s.issuerDID)
+			return result, nil
+		case errors.Is(err, jwt.ErrTokenExpired):
+			fail("credential has expired")
+		case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+			fail("credential is not valid yet")
+		default:
+			fail("credential has no valid signature from %s", s.issuerDID)
+			return result, nil
+		}
+	}
+
+	result.Issuer = claims.Issuer
+	result.CredentialID = claims.ID
+	result.Subject = claims.Subject
+	if claims.IssuedAt != nil {
+		result.IssuedAt = &claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt != nil {
+		result.ExpiresAt = &claims.ExpiresAt.Time
+	}
+
+	vc := claims.VC
+	if !containsString(vc.Type, domain.CredentialTypeAgentIdentity) {
+		fail("credential is not an agent identity credential")
+		return result, nil
+	}
+	if subjectID, _ := vc.CredentialSubject["id"].(string); subjectID != claims.Subject {
+		fail("credential subject does not match the JWT subject")
+	}
+	if agentID, err := uuid.Parse(fmt.Sprint(vc.CredentialSubject["agentId"])); err == nil {
+		result.AgentID = &agentID
+	}
+	if organization, ok := vc.CredentialSubject["organization"].(map[string]interface{}); ok {
+		if orgID, err := uuid.Parse(fmt.Sprint(organization["id"])); err == nil {
+			result.OrganizationID = &orgID
+		}
+	}
+	if capabilities, ok := vc.CredentialSubject["capabilities"].([]interface{}); ok {
+		result.Capabilities = make([]string, 0, len(capabilities))
+		for _, capability := range capabilities {
+			result.Capabilities = append(result.Capabilities, fmt.Sprint(capability))
+		}
+	}
+
+	revoked, err := s.isRevoked(vc.CredentialStatus, fail)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		result.Revoked = true
+		fail("credential has been revoked")
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result, nil
+}
+
+// keyFunc selects the published key a credential names by its DID URL
+func (s *CredentialService) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if !strings.HasPrefix(kid, s.issuerDID+"#") {
+		return nil, fmt.Errorf("unknown verification method %q", kid)
+	}
+	alg, key, err := s.keys.VerificationKey(strings.TrimPrefix(kid, s.issuerDID+"#"))
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != alg {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key, nil
+}
+
+// isRevoked looks up a credential's entry in one of AIM's status lists
+func (s *CredentialService) isRevoked(status *domain.CredentialStatus, fail func(string, ...interface{})) (bool, error) {
+	if status == nil || status.Type != "StatusList2021Entry" || status.StatusPurpose != "revocation" {
+		fail("credential has no revocation status")
+		return false, nil
+	}
+
+	prefix := s.issuer + "/credentials/status/"
+	listID, err := uuid.Parse(strings.TrimPrefix(status.StatusListCredential, prefix))
+	if err != nil || !strings.HasPrefix(status.StatusListCredential, prefix) {
+		fail("credential status list is not published by this issuer")
+		return false, nil
+	}
+	index, err := strconv.Atoi(status.StatusListIndex)
+	if err != nil || index < 0 || index >= domain.StatusListSize {
+		fail("credential status list index is invalid")
+		return false, nil
+	}
+
+	revoked, err := s.credentialRepo.RevokedIndexes(listID)
+	if err != nil {
+		return false, err
+	}
+	for _, revokedIndex := range revoked {
+		if revokedIndex == index {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// encodeStatusList encodes a StatusList2021 bitstring: the first entry is the most significant
+// bit of the first byte, and the bitstring is GZIP-compressed and base64url-encoded
+func encodeStatusList(revoked []int, size int) (string, error) {
+	bitstring := make([]byte, size/8)
+	for _, index := range revoked {
+		if index >= 0 && index < size {
+			bitstring[index/8] |= 0x80 >> (index % 8)
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(bitstring); err != nil {
+		return "", fmt.Errorf("failed to compress status list: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress status list: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// agentEd25519Key decodes an agent's public key
+func agentEd25519Key(agent *domain.Agent) (ed25519.PublicKey, bool) {
+	if agent.PublicKey == nil || *agent.PublicKey == "" {
+		return nil, false
+	}
+	raw, err := base64.StdEncoding.DecodeString(*agent.PublicKey)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, false
+	}
+	return ed25519.PublicKey(raw), true
+}
+
+// didWebFromURL returns the did:web identifying a URL: its host (with the port's colon
+// percent-encoded) followed by its path segments
+func didWebFromURL(base string) string {
+	parsed, err := url.Parse(base)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	did := "did:web:" + strings.ReplaceAll(parsed.Host, ":", "%3A")
+	for _, segment := range strings.Split(strings.Trim(parsed.Path, "/"), "/") {
+		if segment != "" {
+			did += ":" + url.PathEscape(segment)
+		}
+	}
+	return did
+}
+
+// didKeyFromEd25519 returns the did:key of an Ed25519 public key
+func didKeyFromEd25519(key ed25519.PublicKey) string {
+	return "did:key:" + multibaseEd25519(key)
+}
+
+// multibaseEd25519 encodes an Ed25519 public key as a base58btc multibase multicodec value
+func multibaseEd25519(key ed25519.PublicKey) string {
+	return "z" + base58Encode(append(append([]byte{}, ed25519Multicodec...), key...))
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Encode encodes bytes with the Bitcoin base58 alphabet
+func base58Encode(input []byte) string {
+	zeros := 0
+	for zeros < len(input) && input[zeros] == 0 {
+		zeros++
+	}
+
+	// Base 58 digits, least significant first
+	digits := make([]byte, 0, len(input)*138/100+1)
+	for _, b := range input[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	encoded := make([]byte, 0, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		encoded = append(encoded, '1')
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		encoded = append(encoded, base58Alphabet[digits[i]])
+	}
+	return string(encoded)
+}
@@ -0,0 +1,230 @@
+package application
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgentCredentialRepository keeps credentials in memory, in a single status list
+type fakeAgentCredentialRepository struct {
+	listID      uuid.UUID
+	credentials map[uuid.UUID]*domain.AgentCredential
+}
+
+func (r *fakeAgentCredentialRepository) Create(credential *domain.AgentCredential) error {
+	credential.StatusListID = r.listID
+	credential.StatusListIndex = len(r.credentials)
+	copied := *credential
+	r.credentials[credential.ID] = &copied
+	return nil
+}
+
+func (r *fakeAgentCredentialRepository) GetByID(id uuid.UUID) (*domain.AgentCredential, error) {
+	if credential, ok := r.credentials[id]; ok {
+		copied := *credential
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeAgentCredentialRepository) ListByAgent(agentID uuid.UUID) ([]*domain.AgentCredential, error) {
+	credentials := []*domain.AgentCredential{}
+	for _, credential := range r.credentials {
+		if credential.AgentID == agentID {
+			credentials = append(credentials, credential)
+		}
+	}
+	return credentials, nil
+}
+
+func (r *fakeAgentCredentialRepository) Revoke(id uuid.UUID, reason string, at time.Time) error {
+	r.credentials[id].RevokedAt = &at
+	r.credentials[id].RevokeReason = &reason
+	return nil
+}
+
+func (r *fakeAgentCredentialRepository) StatusListExists(listID uuid.UUID) (bool, error) {
+	return listID == r.listID, nil
+}
+
+func (r *fakeAgentCredentialRepository) RevokedIndexes(listID uuid.UUID) ([]int, error) {
+	indexes := []int{}
+	for _, credential := range r.credentials {
+		if credential.StatusListID == listID && credential.RevokedAt != nil {
+			indexes = append(indexes, credential.StatusListIndex)
+		}
+	}
+	return indexes, nil
+}
+
+// createTestCredentialAgent creates a verified agent with a fresh Ed25519 key
+func createTestCredentialAgent(t *testing.T) *domain.Agent {
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	encodedKey := base64.StdEncoding.EncodeToString(publicKey)
+
+	return &domain.Agent{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Name:           "invoice-bot",
+		Status:         domain.AgentStatusVerified,
+		PublicKey:      &encodedKey,
+		KeyAlgorithm:   "Ed25519",
+		TrustScore:     0.82,
+	}
+}
+
+func createTestCredentialService(t *testing.T, agent *domain.Agent) (*CredentialService, *fakeAgentCredentialRepository) {
+	clock := time.Now().UTC()
+	keys, _ := newTestSigningKeyService(t, "ES256", &clock)
+	require.NoError(t, keys.Rotate(context.Background()))
+
+	agentRepo := new(MockAgentRepository)
+	agentRepo.On("GetByID", agent.ID).Return(agent, nil)
+	capabilityRepo := new(MockCapabilityRepository)
+	capabilityRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{
+		{AgentID: agent.ID, CapabilityType: "invoices:read"},
+	}, nil)
+	orgRepo := new(MockOrganizationRepository)
+	orgRepo.On("GetByID", agent.OrganizationID).Return(&domain.Organization{ID: agent.OrganizationID, Name: "Acme", Domain: "acme.example.com"}, nil)
+
+	credentials := &fakeAgentCredentialRepository{listID: uuid.New(), credentials: map[uuid.UUID]*domain.AgentCredential{}}
+	service := NewCredentialService(credentials, agentRepo, capabilityRepo, orgRepo, keys, "https://aim.example.com/")
+	return service, credentials
+}
+
+func issueTestCredential(t *testing.T, service *CredentialService, agent *domain.Agent, method string) *IssuedCredential {
+	issued, err := service.Issue(context.Background(), agent.OrganizationID, agent.ID, uuid.New(), &IssueCredentialRequest{DIDMethod: method})
+	require.NoError(t, err)
+	return issued
+}
+
+func TestCredentialService_IssuesVerifiableCredentials(t *testing.T) {
+	agent := createTestCredentialAgent(t)
+	service, _ := createTestCredentialService(t, agent)
+	ctx := context.Background()
+
+	issued := issueTestCredential(t, service, agent, "")
+	assert.Equal(t, "did:web:aim.example.com:agents:"+agent.ID.String(), issued.Credential.SubjectDID)
+	assert.True(t, strings.HasPrefix(tokenHeader(t, issued.JWT)["kid"].(string), "did:web:aim.example.com#"))
+
+	result, err := service.Verify(ctx, issued.JWT)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, result.Errors)
+	assert.Equal(t, "did:web:aim.example.com", result.Issuer)
+	assert.Equal(t, issued.Credential.SubjectDID, result.Subject)
+	assert.Equal(t, agent.ID, *result.AgentID)
+	assert.Equal(t, agent.OrganizationID, *result.OrganizationID)
+	assert.Equal(t, []string{"invoices:read"}, result.Capabilities)
+
+	// The same agent can present a did:key derived from its own key
+	issued = issueTestCredential(t, service, agent, domain.DIDMethodKey)
+	assert.True(t, strings.HasPrefix(issued.Credential.SubjectDID, "did:key:z6Mk"))
+	dids, err := service.GetAgentDIDs(ctx, agent.OrganizationID, agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, issued.Credential.SubjectDID, dids.DIDKey)
+	assert.Equal(t, []string{dids.DIDKey}, dids.Document.AlsoKnownAs)
+	require.Len(t, dids.Document.VerificationMethod, 1)
+	assert.Equal(t, strings.TrimPrefix(dids.DIDKey, "did:key:"), dids.Document.VerificationMethod[0].PublicKeyMultibase)
+
+	// AIM's DID document publishes the key credentials are signed with
+	issuerDocument := service.IssuerDIDDocument()
+	require.NotEmpty(t, issuerDocument.VerificationMethod)
+	assert.Contains(t, issuerDocument.AssertionMethod, tokenHeader(t, issued.JWT)["kid"])
+
+	// Tampered and expired credentials fail
+	parts := strings.Split(issued.JWT, ".")
+	forged := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"did:web:aim.example.com","exp":9999999999}`)) + "." + parts[2]
+	result, err = service.Verify(ctx, forged)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors, "credential has no valid signature from did:web:aim.example.com")
+
+	service.now = func() time.Time { return time.Now().Add(91 * 24 * time.Hour) }
+	result, err = service.Verify(ctx, issued.JWT)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors, "credential has expired")
+}
+
+func TestCredentialService_RevocationIsPublishedInStatusList(t *testing.T) {
+	agent := createTestCredentialAgent(t)
+	service, credentials := createTestCredentialService(t, agent)
+	ctx := context.Background()
+
+	kept := issueTestCredential(t, service, agent, "")
+	revoked := issueTestCredential(t, service, agent, "")
+	require.NoError(t, service.Revoke(ctx, agent.OrganizationID, agent.ID, revoked.Credential.ID, "key leaked"))
+
+	result, err := service.Verify(ctx, revoked.JWT)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.True(t, result.Revoked)
+	result, err = service.Verify(ctx, kept.JWT)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, result.Errors)
+
+	// Offline verifiers see the same thing in the signed status list
+	listJWT, err := service.StatusListCredential(ctx, credentials.listID)
+	require.NoError(t, err)
+	claims := &agentCredentialClaims{}
+	_, err = jwt.ParseWithClaims(listJWT, claims, service.keyFunc)
+	require.NoError(t, err)
+	assert.Contains(t, claims.VC.Type, domain.CredentialTypeStatusList)
+
+	compressed, err := base64.RawURLEncoding.DecodeString(claims.VC.CredentialSubject["encodedList"].(string))
+	require.NoError(t, err)
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	bitstring, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Len(t, bitstring, domain.StatusListSize/8)
+	bit := func(index int) bool { return bitstring[index/8]&(0x80>>(index%8)) != 0 }
+	assert.False(t, bit(kept.Credential.StatusListIndex))
+	assert.True(t, bit(revoked.Credential.StatusListIndex))
+
+	// Credentials of other agents cannot be revoked through this one
+	require.ErrorIs(t, service.Revoke(ctx, agent.OrganizationID, uuid.New(), kept.Credential.ID, ""), ErrCredentialNotFound)
+	_, err = service.StatusListCredential(ctx, uuid.New())
+	require.ErrorIs(t, err, ErrStatusListNotFound)
+}
+
+func TestCredentialService_OnlyVerifiedAgentsGetCredentials(t *testing.T) {
+	agent := createTestCredentialAgent(t)
+	service, _ := createTestCredentialService(t, agent)
+	ctx := context.Background()
+
+	_, err := service.Issue(ctx, agent.OrganizationID, agent.ID, uuid.New(), &IssueCredentialRequest{DIDMethod: "peer"})
+	require.ErrorIs(t, err, ErrInvalidCredentialRequest)
+	_, err = service.Issue(ctx, agent.OrganizationID, agent.ID, uuid.New(), &IssueCredentialRequest{ValidForDays: 400})
+	require.ErrorIs(t, err, ErrInvalidCredentialRequest)
+
+	agent.Status = domain.AgentStatusPending
+	_, err = service.Issue(ctx, agent.OrganizationID, agent.ID, uuid.New(), &IssueCredentialRequest{})
+	require.ErrorIs(t, err, ErrCredentialUnavailable)
+
+	agent.Status = domain.AgentStatusRevoked
+	_, err = service.AgentDIDDocument(ctx, agent.ID)
+	require.ErrorIs(t, err, ErrDIDNotFound)
+}
+
+func TestDIDEncoding(t *testing.T) {
+	assert.Equal(t, "did:web:aim.example.com", didWebFromURL("https://aim.example.com"))
+	assert.Equal(t, "did:web:localhost%3A8080:aim", didWebFromURL("http://localhost:8080/aim/"))
+
+	assert.Equal(t, "StV1DL6CwTryKyV", base58Encode([]byte("hello world")))
+	assert.Equal(t, "112", base58Encode([]byte{0, 0, 1}))
+}
@@ -0,0 +1,360 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+const (
+	defaultDataThresholdMB    = 100 // Roadmap default: 100MB per hour
+	defaultDataTransferWindow = time.Hour
+	maxDataTransferWindow     = 7 * 24 * time.Hour
+	dataTransferRetention     = maxDataTransferWindow + 24*time.Hour
+	maxAlertDestinations      = 5
+	bytesPerMB                = 1024 * 1024
+)
+
+// dataExfiltrationConfig holds the tunables of a data_exfiltration policy's rules
+type dataExfiltrationConfig struct {
+	thresholdBytes     int64         // data_threshold_mb: bytes exported within the window
+	readThresholdBytes int64         // read_threshold_mb: optional, bytes read within the window
+	window             time.Duration // time_window, e.g. "1h"
+	exportActions      []string      // export_actions: actions denied once a threshold is exceeded
+}
+
+func newDataExfiltrationConfig(policy *domain.SecurityPolicy) dataExfiltrationConfig {
+	config := dataExfiltrationConfig{
+		thresholdBytes:     int64(policyRuleFloat(policy.Rules, "data_threshold_mb", defaultDataThresholdMB) * bytesPerMB),
+		readThresholdBytes: int64(policyRuleFloat(policy.Rules, "read_threshold_mb", 0) * bytesPerMB),
+		window:             policyRuleDuration(policy.Rules, "time_window", defaultDataTransferWindow),
+		exportActions:      policyRuleStrings(policy.Rules, "export_actions"),
+	}
+	if config.window > maxDataTransferWindow {
+		config.window = maxDataTransferWindow
+	}
+	if len(config.exportActions) == 0 {
+		config.exportActions = []string{domain.CapabilityDataExport}
+	}
+	return config
+}
+
+// DataExfiltrationService accounts for the bytes agents read and export, reported through
+// verify-action metadata, action results and SDK-instrumented MCP calls. The data_exfiltration
+// policy that applies to an agent sets a per-window threshold; once it is exceeded an alert naming
+// the destinations is raised, and block_and_alert policies deny further data:export actions until
+// the rolling window drops below the threshold again. Without a policy transfers are only recorded.
+type DataExfiltrationService struct {
+	transferRepo  domain.DataTransferRepository
+	policyService *SecurityPolicyService
+	alertRepo     domain.AlertRepository
+
+	mu        sync.Mutex
+	alertedAt map[uuid.UUID]time.Time // One alert per agent per window
+
+	now func() time.Time
+}
+
+// NewDataExfiltrationService creates a new data exfiltration service
+func NewDataExfiltrationService(
+	transferRepo domain.DataTransferRepository,
+	policyService *SecurityPolicyService,
+	alertRepo domain.AlertRepository,
+) *DataExfiltrationService {
+	return &DataExfiltrationService{
+		transferRepo:  transferRepo,
+		policyService: policyService,
+		alertRepo:     alertRepo,
+		alertedAt:     make(map[uuid.UUID]time.Time),
+		now:           func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// RecordTransfer stores a reported transfer and evaluates the agent's data_exfiltration policy,
+// alerting when the transfer pushes the agent over its threshold
+func (s *DataExfiltrationService) RecordTransfer(ctx context.Context, agent *domain.Agent, transfer *domain.DataTransfer) (*domain.DataTransferUsage, error) {
+	if transfer.BytesRead < 0 || transfer.BytesExported < 0 {
+		return nil, fmt.Errorf("invalid data transfer: byte counts must not be negative")
+	}
+
+	transfer.OrganizationID = agent.OrganizationID
+	transfer.AgentID = agent.ID
+	if transfer.OccurredAt.IsZero() || transfer.OccurredAt.After(s.now()) {
+		transfer.OccurredAt = s.now()
+	}
+
+	if err := s.transferRepo.Create(transfer); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.policyService.FindApplicablePolicy(ctx, agent, domain.PolicyTypeDataExfiltration)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.evaluate(agent, policy)
+	if err != nil {
+		return nil, err
+	}
+	if usage.Exceeded && policy.EnforcementAction != domain.EnforcementAllow {
+		s.raiseExfiltrationAlert(agent, policy, usage, "")
+	}
+
+	return usage, nil
+}
+
+// RecordReportedTransfer records the byte counts reported in verify-action metadata, verification
+// context or an action result. It returns nil when no bytes were reported.
+func (s *DataExfiltrationService) RecordReportedTransfer(
+	ctx context.Context,
+	agent *domain.Agent,
+	source domain.DataTransferSource,
+	resource string,
+	auditID *uuid.UUID,
+	metadata map[string]interface{},
+) (*domain.DataTransferUsage, error) {
+	transfer := DataTransferFromMetadata(metadata)
+	if transfer == nil {
+		return nil, nil
+	}
+
+	transfer.Source = source
+	transfer.AuditID = auditID
+	if transfer.Resource == "" {
+		transfer.Resource = resource
+	}
+	return s.RecordTransfer(ctx, agent, transfer)
+}
+
+// CheckExport decides whether an action may proceed given the agent's data volume. Only actions
+// listed in the policy's export_actions (default data:export) are ever denied.
+func (s *DataExfiltrationService) CheckExport(ctx context.Context, agent *domain.Agent, actionType string) (*domain.DataTransferUsage, error) {
+	policy, err := s.policyService.FindApplicablePolicy(ctx, agent, domain.PolicyTypeDataExfiltration)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+	if !matchesActionPattern(actionType, newDataExfiltrationConfig(policy).exportActions) {
+		return nil, nil
+	}
+
+	usage, err := s.evaluate(agent, policy)
+	if err != nil {
+		return nil, err
+	}
+	if usage.ExportBlocked {
+		fmt.Printf("🚨 Data export by agent %s blocked by policy '%s' (%d bytes exported in window)\n",
+			agent.Name, policy.Name, usage.BytesExported)
+		s.raiseExfiltrationAlert(agent, policy, usage, actionType)
+	}
+
+	return usage, nil
+}
+
+// GetUsage returns the agent's data volume in the window of its data_exfiltration policy, or the
+// default one-hour window when no policy applies
+func (s *DataExfiltrationService) GetUsage(ctx context.Context, agent *domain.Agent) (*domain.DataTransferUsage, error) {
+	policy, err := s.policyService.FindApplicablePolicy(ctx, agent, domain.PolicyTypeDataExfiltration)
+	if err != nil {
+		return nil, err
+	}
+	return s.evaluate(agent, policy)
+}
+
+// PruneTransfers deletes transfers older than the longest policy window
+func (s *DataExfiltrationService) PruneTransfers(ctx context.Context) error {
+	deleted, err := s.transferRepo.DeleteBefore(s.now().Add(-dataTransferRetention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		fmt.Printf("✅ Pruned %d data transfer records\n", deleted)
+	}
+	return nil
+}
+
+// ExportDeniedReason is the denial reason for an export blocked by usage
+func (s *DataExfiltrationService) ExportDeniedReason(usage *domain.DataTransferUsage) string {
+	return fmt.Sprintf(
+		"Data export blocked by security policy '%s': %s exported since %s exceeds the %.0f MB threshold",
+		usage.PolicyName, formatDataSize(usage.BytesExported), usage.WindowStart.Format(time.RFC3339), usage.ThresholdMB,
+	)
+}
+
+// evaluate sums the agent's transfers in the policy window and compares them with the thresholds
+func (s *DataExfiltrationService) evaluate(agent *domain.Agent, policy *domain.SecurityPolicy) (*domain.DataTransferUsage, error) {
+	window := defaultDataTransferWindow
+	var config dataExfiltrationConfig
+	if policy != nil {
+		config = newDataExfiltrationConfig(policy)
+		window = config.window
+	}
+
+	now := s.now()
+	usage := &domain.DataTransferUsage{
+		AgentID:     agent.ID,
+		WindowStart: now.Add(-window),
+		WindowEnd:   now,
+	}
+
+	var err error
+	usage.BytesRead, usage.BytesExported, err = s.transferRepo.SumByAgent(agent.ID, usage.WindowStart)
+	if err != nil {
+		return nil, err
+	}
+	usage.Destinations, err = s.transferRepo.TopDestinations(agent.ID, usage.WindowStart, maxAlertDestinations)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy != nil {
+		usage.PolicyName = policy.Name
+		usage.ThresholdMB = float64(config.thresholdBytes) / bytesPerMB
+		usage.Exceeded = usage.BytesExported > config.thresholdBytes ||
+			(config.readThresholdBytes > 0 && usage.BytesRead > config.readThresholdBytes)
+		usage.ExportBlocked = usage.Exceeded && policy.EnforcementAction == domain.EnforcementBlockAndAlert
+	}
+
+	return usage, nil
+}
+
+// raiseExfiltrationAlert alerts once per agent and window, naming the top destinations
+func (s *DataExfiltrationService) raiseExfiltrationAlert(agent *domain.Agent, policy *domain.SecurityPolicy, usage *domain.DataTransferUsage, blockedAction string) {
+	s.mu.Lock()
+	if last, ok := s.alertedAt[agent.ID]; ok && last.After(usage.WindowStart) {
+		s.mu.Unlock()
+		return
+	}
+	s.alertedAt[agent.ID] = s.now()
+	s.mu.Unlock()
+
+	destinations := []string{}
+	for _, destination := range usage.Destinations {
+		name := destination.Destination
+		if name == "" {
+			name = "(unreported destination)"
+		}
+		destinations = append(destinations, fmt.Sprintf("- %s: %s in %d transfers", name, formatDataSize(destination.BytesExported), destination.Transfers))
+	}
+	if len(destinations) == 0 {
+		destinations = append(destinations, "- none reported (read volume exceeded)")
+	}
+
+	enforcement := "alert only"
+	severity := domain.AlertSeverityHigh
+	if usage.ExportBlocked {
+		enforcement = "further data exports blocked"
+		severity = domain.AlertSeverityCritical
+	}
+
+	description := fmt.Sprintf(
+		"Agent '%s' exported %s and read %s since %s, exceeding the %.0f MB threshold.\n\n**Destinations:**\n%s\n\n**Enforcement:** %s (policy: %s)",
+		agent.DisplayName, formatDataSize(usage.BytesExported), formatDataSize(usage.BytesRead),
+		usage.WindowStart.Format(time.RFC3339), usage.ThresholdMB, strings.Join(destinations, "\n"), enforcement, policy.Name,
+	)
+	if blockedAction != "" {
+		description += fmt.Sprintf("\n**Blocked action:** %s", blockedAction)
+	}
+
+	alert := &domain.Alert{
+		ID:             uuid.New(),
+		OrganizationID: agent.OrganizationID,
+		AlertType:      domain.AlertDataExfiltration,
+		Severity:       severity,
+		Title:          fmt.Sprintf("Possible Data Exfiltration: %s", agent.DisplayName),
+		Description:    description,
+		ResourceType:   "agent",
+		ResourceID:     agent.ID,
+		IsAcknowledged: false,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := s.alertRepo.Create(alert); err != nil {
+		fmt.Printf("⚠️  Failed to create data exfiltration alert for agent %s: %v\n", agent.Name, err)
+		s.mu.Lock()
+		delete(s.alertedAt, agent.ID)
+		s.mu.Unlock()
+	}
+}
+
+// DataTransferFromMetadata reads the byte counts an agent reported in verify-action metadata,
+// verification context or an action result. It returns nil when no bytes were reported.
+func DataTransferFromMetadata(metadata map[string]interface{}) *domain.DataTransfer {
+	bytesRead, readOK := metadataBytes(metadata, "bytes_read")
+	bytesExported, exportedOK := metadataBytes(metadata, "bytes_exported")
+	if !readOK && !exportedOK {
+		return nil
+	}
+
+	transfer := &domain.DataTransfer{
+		BytesRead:     bytesRead,
+		BytesExported: bytesExported,
+		MCPServer:     ActivityMCPServer(metadata),
+	}
+	if destination, ok := metadata["destination"].(string); ok {
+		transfer.Destination = destination
+	}
+	if resource, ok := metadata["resource"].(string); ok {
+		transfer.Resource = resource
+	}
+	return transfer
+}
+
+func metadataBytes(metadata map[string]interface{}, key string) (int64, bool) {
+	switch value := metadata[key].(type) {
+	case float64:
+		return int64(value), true
+	case int:
+		return int64(value), true
+	case int64:
+		return value, true
+	case string:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		return parsed, err == nil
+	}
+	return 0, false
+}
+
+// matchesActionPattern reports whether an action matches one of the patterns; a trailing *
+// matches any suffix, as for capability grants
+func matchesActionPattern(actionType string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if pattern == actionType {
+			return true
+		}
+		if strings.HasSuffix(pattern, "*") && strings.HasPrefix(actionType, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyRuleDuration reads a duration policy rule such as "1h" or "30m"; plain numbers are minutes
+func policyRuleDuration(rules map[string]interface{}, key string, fallback time.Duration) time.Duration {
+	switch value := rules[key].(type) {
+	case string:
+		if duration, err := time.ParseDuration(value); err == nil && duration > 0 {
+			return duration
+		}
+	case float64:
+		if value > 0 {
+			return time.Duration(value * float64(time.Minute))
+		}
+	case int:
+		if value > 0 {
+			return time.Duration(value) * time.Minute
+		}
+	}
+	return fallback
+}
+
+func formatDataSize(bytes int64) string {
+	if bytes < bytesPerMB {
+		return fmt.Sprintf("%.1f KB", float64(bytes)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(bytes)/bytesPerMB)
+}
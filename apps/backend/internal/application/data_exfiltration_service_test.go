@@ -0,0 +1,220 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDataTransferRepository struct {
+	transfers []*domain.DataTransfer
+}
+
+func (r *fakeDataTransferRepository) Create(transfer *domain.DataTransfer) error {
+	r.transfers = append(r.transfers, transfer)
+	return nil
+}
+
+func (r *fakeDataTransferRepository) SumByAgent(agentID uuid.UUID, since time.Time) (int64, int64, error) {
+	var bytesRead, bytesExported int64
+	for _, transfer := range r.transfers {
+		if transfer.AgentID == agentID && transfer.OccurredAt.After(since) {
+			bytesRead += transfer.BytesRead
+			bytesExported += transfer.BytesExported
+		}
+	}
+	return bytesRead, bytesExported, nil
+}
+
+func (r *fakeDataTransferRepository) TopDestinations(agentID uuid.UUID, since time.Time, limit int) ([]*domain.DataTransferDestination, error) {
+	byDestination := map[string]*domain.DataTransferDestination{}
+	for _, transfer := range r.transfers {
+		if transfer.AgentID != agentID || !transfer.OccurredAt.After(since) || transfer.BytesExported == 0 {
+			continue
+		}
+		destination, ok := byDestination[transfer.Destination]
+		if !ok {
+			destination = &domain.DataTransferDestination{Destination: transfer.Destination}
+			byDestination[transfer.Destination] = destination
+		}
+		destination.BytesExported += transfer.BytesExported
+		destination.Transfers++
+	}
+
+	destinations := []*domain.DataTransferDestination{}
+	for _, destination := range byDestination {
+		destinations = append(destinations, destination)
+	}
+	sort.Slice(destinations, func(i, j int) bool { return destinations[i].BytesExported > destinations[j].BytesExported })
+	if len(destinations) > limit {
+		destinations = destinations[:limit]
+	}
+	return destinations, nil
+}
+
+func (r *fakeDataTransferRepository) DeleteBefore(before time.Time) (int64, error) {
+	kept := r.transfers[:0]
+	for _, transfer := range r.transfers {
+		if !transfer.OccurredAt.Before(before) {
+			kept = append(kept, transfer)
+		}
+	}
+	deleted := int64(len(r.transfers) - len(kept))
+	r.transfers = kept
+	return deleted, nil
+}
+
+// createTestExportAgent creates an agent that exports reports
+func createTestExportAgent() *domain.Agent {
+	return &domain.Agent{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Name:           "report-exporter",
+		DisplayName:    "Report Exporter",
+	}
+}
+
+// createTestDataExfiltrationService creates a service enforcing the given data_exfiltration
+// policies of the agent's organization, whose clock reads *clock
+func createTestDataExfiltrationService(agent *domain.Agent, clock *time.Time, policies ...*domain.SecurityPolicy) (*DataExfiltrationService, *MockToolSurfaceAlertRepository) {
+	policyRepo := new(MockToolSurfacePolicyRepository)
+	if policies == nil {
+		policies = []*domain.SecurityPolicy{}
+	}
+	policyRepo.On("GetByType", agent.OrganizationID, domain.PolicyTypeDataExfiltration).Return(policies, nil)
+
+	alertRepo := new(MockToolSurfaceAlertRepository)
+	service := NewDataExfiltrationService(&fakeDataTransferRepository{}, NewSecurityPolicyService(policyRepo, alertRepo, nil), alertRepo)
+	service.now = func() time.Time { return *clock }
+	return service, alertRepo
+}
+
+func recordTestExport(t *testing.T, service *DataExfiltrationService, agent *domain.Agent, destination string, bytes int64) *domain.DataTransferUsage {
+	usage, err := service.RecordTransfer(context.Background(), agent, &domain.DataTransfer{
+		Source:        domain.DataTransferSourceMCPCall,
+		Resource:      "customers",
+		Destination:   destination,
+		BytesExported: bytes,
+	})
+	require.NoError(t, err)
+	return usage
+}
+
+func dataExfiltrationPolicy(action domain.EnforcementAction) *domain.SecurityPolicy {
+	return &domain.SecurityPolicy{
+		Name:              "Monitor Data Exfiltration",
+		PolicyType:        domain.PolicyTypeDataExfiltration,
+		EnforcementAction: action,
+		Rules: map[string]interface{}{
+			"data_threshold_mb": float64(1),
+			"time_window":       "1h",
+		},
+		AppliesTo: "all",
+		IsEnabled: true,
+	}
+}
+
+func TestDataExfiltrationWithoutPolicyOnlyRecords(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	agent := createTestExportAgent()
+	service, alertRepo := createTestDataExfiltrationService(agent, &clock)
+
+	usage := recordTestExport(t, service, agent, "https://example.com/upload", 500*bytesPerMB)
+	assert.Equal(t, int64(500*bytesPerMB), usage.BytesExported)
+	assert.False(t, usage.Exceeded)
+	assert.Equal(t, clock.Add(-time.Hour), usage.WindowStart)
+
+	usage, err := service.CheckExport(context.Background(), agent, domain.CapabilityDataExport)
+	require.NoError(t, err)
+	assert.Nil(t, usage)
+	alertRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestDataExfiltrationBlocksExportsOverThreshold(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	agent := createTestExportAgent()
+	service, alertRepo := createTestDataExfiltrationService(agent, &clock, dataExfiltrationPolicy(domain.EnforcementBlockAndAlert))
+	alertRepo.On("Create", mock.MatchedBy(func(alert *domain.Alert) bool {
+		return alert.AlertType == domain.AlertDataExfiltration &&
+			alert.Severity == domain.AlertSeverityCritical &&
+			assert.Contains(t, alert.Description, "https://paste.example/raw") &&
+			assert.Contains(t, alert.Description, "s3://reports-bucket")
+	})).Return(nil).Once()
+
+	usage := recordTestExport(t, service, agent, "s3://reports-bucket", 400*1024)
+	assert.False(t, usage.Exceeded)
+
+	clock = clock.Add(10 * time.Minute)
+	usage = recordTestExport(t, service, agent, "https://paste.example/raw", 700*1024)
+	assert.True(t, usage.Exceeded)
+	assert.True(t, usage.ExportBlocked)
+	require.Len(t, usage.Destinations, 2)
+	assert.Equal(t, "https://paste.example/raw", usage.Destinations[0].Destination)
+
+	// Further exports are denied, other actions are not; the alert is raised only once per window
+	usage, err := service.CheckExport(context.Background(), agent, domain.CapabilityDataExport)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+	assert.True(t, usage.ExportBlocked)
+	assert.Contains(t, service.ExportDeniedReason(usage), "Monitor Data Exfiltration")
+
+	usage, err = service.CheckExport(context.Background(), agent, "read_file")
+	require.NoError(t, err)
+	assert.Nil(t, usage)
+	alertRepo.AssertExpectations(t)
+
+	// Once the window has rolled past the transfers, exports are allowed again
+	clock = clock.Add(time.Hour)
+	usage, err = service.CheckExport(context.Background(), agent, domain.CapabilityDataExport)
+	require.NoError(t, err)
+	assert.False(t, usage.ExportBlocked)
+	assert.Zero(t, usage.BytesExported)
+}
+
+func TestDataExfiltrationAlertOnlyPolicy(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	agent := createTestExportAgent()
+	service, alertRepo := createTestDataExfiltrationService(agent, &clock, dataExfiltrationPolicy(domain.EnforcementAlertOnly))
+	alertRepo.On("Create", mock.MatchedBy(func(alert *domain.Alert) bool {
+		return alert.Severity == domain.AlertSeverityHigh
+	})).Return(nil).Once()
+
+	usage := recordTestExport(t, service, agent, "", 2*bytesPerMB)
+	assert.True(t, usage.Exceeded)
+	assert.False(t, usage.ExportBlocked)
+
+	usage, err := service.CheckExport(context.Background(), agent, domain.CapabilityDataExport)
+	require.NoError(t, err)
+	assert.False(t, usage.ExportBlocked)
+	alertRepo.AssertExpectations(t)
+}
+
+func TestDataTransferFromMetadata(t *testing.T) {
+	assert.Nil(t, DataTransferFromMetadata(map[string]interface{}{"destination": "s3://bucket"}))
+	assert.Nil(t, DataTransferFromMetadata(nil))
+
+	transfer := DataTransferFromMetadata(map[string]interface{}{
+		"bytes_read":     float64(2048),
+		"bytes_exported": "1024",
+		"destination":    "s3://bucket",
+		"mcp_server":     "filesystem",
+	})
+	require.NotNil(t, transfer)
+	assert.Equal(t, int64(2048), transfer.BytesRead)
+	assert.Equal(t, int64(1024), transfer.BytesExported)
+	assert.Equal(t, "s3://bucket", transfer.Destination)
+	assert.Equal(t, "filesystem", transfer.MCPServer)
+
+	assert.Equal(t, 30*time.Minute, policyRuleDuration(map[string]interface{}{"time_window": "30m"}, "time_window", time.Hour))
+	assert.Equal(t, 15*time.Minute, policyRuleDuration(map[string]interface{}{"time_window": float64(15)}, "time_window", time.Hour))
+	assert.Equal(t, time.Hour, policyRuleDuration(map[string]interface{}{"time_window": "soon"}, "time_window", time.Hour))
+	assert.True(t, matchesActionPattern("data:export_csv", []string{"data:export*"}))
+	assert.False(t, matchesActionPattern("data:read", []string{"data:export"}))
+}
@@ -0,0 +1,389 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// Purges delete in short batches so no statement holds row locks for long; a run stops after
+// retentionMaxBatches per table and the next run continues where it left off.
+const (
+	retentionBatchSize  = 1000
+	retentionBatchPause = 100 * time.Millisecond
+	retentionMaxBatches = 500
+)
+
+// organizationLister lists the organizations scheduled jobs run for (OrganizationRepository)
+type organizationLister interface {
+	ListActiveIDs() ([]uuid.UUID, error)
+}
+
+// DataRetentionService manages per-organization retention rules and legal holds and purges
+// expired rows. Every purge run is recorded in the audit log with its row counts.
+type DataRetentionService struct {
+	retentionRepo domain.DataRetentionRepository
+	auditRepo     domain.AuditLogRepository
+	auditChain    *AuditChainService
+	organizations organizationLister
+	batchSize     int
+	batchPause    time.Duration
+	maxBatches    int
+	now           func() time.Time
+}
+
+// NewDataRetentionService creates a new data retention service
+func NewDataRetentionService(
+	retentionRepo domain.DataRetentionRepository,
+	auditRepo domain.AuditLogRepository,
+	auditChain *AuditChainService,
+	organizations organizationLister,
+) *DataRetentionService {
+	return &DataRetentionService{
+		retentionRepo: retentionRepo,
+		auditRepo:     auditRepo,
+		auditChain:    auditChain,
+		organizations: organizations,
+		batchSize:     retentionBatchSize,
+		batchPause:    retentionBatchPause,
+		maxBatches:    retentionMaxBatches,
+		now:           func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// GetPolicies returns the effective retention of every table with its current size
+func (s *DataRetentionService) GetPolicies(ctx context.Context, orgID uuid.UUID) ([]*domain.RetentionPolicy, error) {
+	rules, err := s.effectiveRules(orgID)
+	if err != nil {
+		return nil, err
+	}
+	holds, err := s.retentionRepo.ListLegalHolds(orgID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]*domain.RetentionPolicy, 0, len(domain.RetentionTables))
+	for _, table := range domain.RetentionTables {
+		rule := rules[table]
+		policy := &domain.RetentionPolicy{
+			Table:         table,
+			RetentionDays: rule.RetentionDays,
+			Enabled:       rule.Enabled,
+			IsDefault:     rule.UpdatedAt.IsZero(),
+			MinDays:       domain.MinRetentionDays[table],
+			OnLegalHold:   legalHoldCovers(holds, table),
+		}
+		if !policy.IsDefault {
+			updatedAt := rule.UpdatedAt
+			policy.UpdatedAt = &updatedAt
+		}
+
+		policy.RecordCount, policy.OldestRecord, err = s.retentionRepo.GetTableStats(orgID, table)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// SetRule overrides the retention period of a table for an organization
+func (s *DataRetentionService) SetRule(
+	ctx context.Context,
+	orgID, userID uuid.UUID,
+	table domain.RetentionTable,
+	retentionDays int,
+	enabled bool,
+) (*domain.RetentionRule, error) {
+	if !table.IsValid() {
+		return nil, fmt.Errorf("invalid retention table: %s", table)
+	}
+	if minDays := domain.MinRetentionDays[table]; retentionDays < minDays {
+		return nil, fmt.Errorf("invalid retention period: %s must be kept at least %d days", table, minDays)
+	}
+
+	rule := &domain.RetentionRule{
+		OrganizationID: orgID,
+		Table:          table,
+		RetentionDays:  retentionDays,
+		Enabled:        enabled,
+		UpdatedBy:      &userID,
+	}
+	if err := s.retentionRepo.UpsertRule(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteRule reverts a table to the default retention period
+func (s *DataRetentionService) DeleteRule(ctx context.Context, orgID uuid.UUID, table domain.RetentionTable) error {
+	if !table.IsValid() {
+		return fmt.Errorf("invalid retention table: %s", table)
+	}
+	return s.retentionRepo.DeleteRule(orgID, table)
+}
+
+// CreateLegalHold suspends purging of one table, or every table when table is nil
+func (s *DataRetentionService) CreateLegalHold(
+	ctx context.Context,
+	orgID, userID uuid.UUID,
+	table *domain.RetentionTable,
+	reason string,
+) (*domain.LegalHold, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("invalid legal hold: reason is required")
+	}
+	if table != nil && !table.IsValid() {
+		return nil, fmt.Errorf("invalid retention table: %s", *table)
+	}
+
+	hold := &domain.LegalHold{
+		OrganizationID: orgID,
+		Table:          table,
+		Reason:         reason,
+		CreatedBy:      userID,
+	}
+	if err := s.retentionRepo.CreateLegalHold(hold); err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// ListLegalHolds returns an organization's legal holds, newest first
+func (s *DataRetentionService) ListLegalHolds(ctx context.Context, orgID uuid.UUID, includeReleased bool) ([]*domain.LegalHold, error) {
+	return s.retentionRepo.ListLegalHolds(orgID, includeReleased)
+}
+
+// ReleaseLegalHold lifts a legal hold; purging resumes with the next run
+func (s *DataRetentionService) ReleaseLegalHold(ctx context.Context, orgID, holdID, userID uuid.UUID) (*domain.LegalHold, error) {
+	hold, err := s.retentionRepo.GetLegalHold(holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold.OrganizationID != orgID {
+		return nil, fmt.Errorf("legal hold not found")
+	}
+	if hold.ReleasedAt != nil {
+		return nil, fmt.Errorf("legal hold already released")
+	}
+
+	releasedAt := s.now()
+	if err := s.retentionRepo.ReleaseLegalHold(holdID, userID, releasedAt); err != nil {
+		return nil, err
+	}
+	hold.ReleasedBy = &userID
+	hold.ReleasedAt = &releasedAt
+
+	return hold, nil
+}
+
+// PurgeOrganization deletes an organization's rows past their retention period, skipping tables
+// on legal hold, and records the run in the audit log. triggeredBy is nil for scheduled runs.
+func (s *DataRetentionService) PurgeOrganization(ctx context.Context, orgID uuid.UUID, triggeredBy *uuid.UUID) (*domain.RetentionPurgeRun, error) {
+	rules, err := s.effectiveRules(orgID)
+	if err != nil {
+		return nil, err
+	}
+	holds, err := s.retentionRepo.ListLegalHolds(orgID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &domain.RetentionPurgeRun{
+		OrganizationID: orgID,
+		StartedAt:      s.now(),
+	}
+
+	for _, table := range domain.RetentionTables {
+		rule := rules[table]
+		result := domain.RetentionPurgeTable{Table: table}
+
+		switch {
+		case !rule.Enabled:
+			result.Skipped = "retention disabled"
+		case legalHoldCovers(holds, table):
+			result.Skipped = "legal hold"
+		default:
+			cutoff := run.StartedAt.AddDate(0, 0, -rule.RetentionDays)
+			result.Cutoff = &cutoff
+			s.purgeTable(ctx, orgID, &result, cutoff)
+		}
+
+		run.TotalDeleted += result.RowsDeleted
+		run.Tables = append(run.Tables, result)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	run.FinishedAt = s.now()
+
+	if err := s.recordPurge(run, triggeredBy); err != nil {
+		return run, err
+	}
+
+	return run, nil
+}
+
+// RunPurge purges every active organization and returns how many were processed
+func (s *DataRetentionService) RunPurge(ctx context.Context) (int, error) {
+	orgIDs, err := s.organizations.ListActiveIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	processed, failed := 0, 0
+	for _, orgID := range orgIDs {
+		if ctx.Err() != nil {
+			return processed, ctx.Err()
+		}
+
+		run, err := s.PurgeOrganization(ctx, orgID, nil)
+		if err != nil {
+			fmt.Printf("🚨 Data retention purge failed for org %s: %v\n", orgID, err)
+			failed++
+			continue
+		}
+		if run.TotalDeleted > 0 {
+			fmt.Printf("✅ Data retention purged %d records for org %s\n", run.TotalDeleted, orgID)
+		}
+		processed++
+	}
+
+	if failed > 0 {
+		return processed, fmt.Errorf("data retention purge failed for %d of %d organizations", failed, len(orgIDs))
+	}
+
+	return processed, nil
+}
+
+// purgeTable deletes expired rows of one table in batches, pausing between batches so other
+// writers are not starved
+func (s *DataRetentionService) purgeTable(ctx context.Context, orgID uuid.UUID, result *domain.RetentionPurgeTable, cutoff time.Time) {
+	purge := func(limit int) (int64, error) {
+		return s.retentionRepo.PurgeBatch(ctx, orgID, result.Table, cutoff, limit)
+	}
+
+	if result.Table == domain.RetentionTableAuditLogs {
+		// Chained records may only go up to a signed checkpoint older than the cutoff, so the
+		// remaining chain still verifies from that checkpoint
+		throughSequence, err := s.auditChain.PruneThrough(ctx, orgID, cutoff)
+		if err != nil {
+			result.Error = err.Error()
+			fmt.Printf("🚨 Audit log purge skipped for org %s: %v\n", orgID, err)
+			return
+		}
+		purge = func(limit int) (int64, error) {
+			return s.retentionRepo.PurgeAuditLogBatch(ctx, orgID, throughSequence, cutoff, limit)
+		}
+	}
+
+	for result.Batches < s.maxBatches {
+		deleted, err := purge(s.batchSize)
+		if err != nil {
+			result.Error = err.Error()
+			fmt.Printf("🚨 Data retention purge of %s failed for org %s: %v\n", result.Table, orgID, err)
+			return
+		}
+		result.Batches++
+		result.RowsDeleted += deleted
+		if deleted < int64(s.batchSize) {
+			result.Complete = true
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Error = ctx.Err().Error()
+			return
+		case <-time.After(s.batchPause):
+		}
+	}
+}
+
+// recordPurge writes the audit entry for a purge run
+func (s *DataRetentionService) recordPurge(run *domain.RetentionPurgeRun, triggeredBy *uuid.UUID) error {
+	trigger, userID := "scheduled", uuid.Nil
+	if triggeredBy != nil {
+		trigger, userID = "manual", *triggeredBy
+	}
+
+	tables := make(map[string]interface{}, len(run.Tables))
+	for _, result := range run.Tables {
+		entry := map[string]interface{}{
+			"rows_deleted": result.RowsDeleted,
+			"batches":      result.Batches,
+			"complete":     result.Complete,
+		}
+		if result.Cutoff != nil {
+			entry["cutoff"] = result.Cutoff.Format(time.RFC3339)
+		}
+		if result.Skipped != "" {
+			entry["skipped"] = result.Skipped
+		}
+		if result.Error != "" {
+			entry["error"] = result.Error
+		}
+		tables[string(result.Table)] = entry
+	}
+
+	err := s.auditRepo.Create(&domain.AuditLog{
+		OrganizationID: run.OrganizationID,
+		UserID:         userID,
+		Action:         domain.AuditActionPurge,
+		ResourceType:   "data_retention",
+		ResourceID:     run.OrganizationID,
+		Metadata: map[string]interface{}{
+			"trigger":       trigger,
+			"total_deleted": run.TotalDeleted,
+			"tables":        tables,
+			"duration_ms":   run.FinishedAt.Sub(run.StartedAt).Milliseconds(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record data retention purge: %w", err)
+	}
+
+	return nil
+}
+
+// effectiveRules merges an organization's rules over the defaults. Default rules have a zero UpdatedAt.
+func (s *DataRetentionService) effectiveRules(orgID uuid.UUID) (map[domain.RetentionTable]domain.RetentionRule, error) {
+	rules, err := s.retentionRepo.ListRules(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := make(map[domain.RetentionTable]domain.RetentionRule, len(domain.RetentionTables))
+	for _, table := range domain.RetentionTables {
+		effective[table] = domain.RetentionRule{
+			OrganizationID: orgID,
+			Table:          table,
+			RetentionDays:  domain.DefaultRetentionDays[table],
+			Enabled:        true,
+		}
+	}
+	for _, rule := range rules {
+		if rule.Table.IsValid() {
+			effective[rule.Table] = *rule
+		}
+	}
+
+	return effective, nil
+}
+
+func legalHoldCovers(holds []*domain.LegalHold, table domain.RetentionTable) bool {
+	for _, hold := range holds {
+		if hold.Covers(table) {
+			return true
+		}
+	}
+	return false
+}
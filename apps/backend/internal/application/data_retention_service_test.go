@@ -0,0 +1,286 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDataRetentionRepository keeps the write time of each row per table in memory
+type fakeDataRetentionRepository struct {
+	rules   map[domain.RetentionTable]*domain.RetentionRule
+	holds   []*domain.LegalHold
+	rows    map[domain.RetentionTable][]time.Time
+	chain   *fakeAuditChainRepository
+	batches map[domain.RetentionTable]int
+}
+
+func newFakeDataRetentionRepository(chain *fakeAuditChainRepository) *fakeDataRetentionRepository {
+	return &fakeDataRetentionRepository{
+		rules:   map[domain.RetentionTable]*domain.RetentionRule{},
+		rows:    map[domain.RetentionTable][]time.Time{},
+		chain:   chain,
+		batches: map[domain.RetentionTable]int{},
+	}
+}
+
+func (r *fakeDataRetentionRepository) addRows(table domain.RetentionTable, count int, writtenAt time.Time) {
+	for i := 0; i < count; i++ {
+		r.rows[table] = append(r.rows[table], writtenAt)
+	}
+}
+
+func (r *fakeDataRetentionRepository) ListRules(orgID uuid.UUID) ([]*domain.RetentionRule, error) {
+	rules := []*domain.RetentionRule{}
+	for _, rule := range r.rules {
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r *fakeDataRetentionRepository) UpsertRule(rule *domain.RetentionRule) error {
+	rule.UpdatedAt = time.Now().UTC()
+	r.rules[rule.Table] = rule
+	return nil
+}
+
+func (r *fakeDataRetentionRepository) DeleteRule(orgID uuid.UUID, table domain.RetentionTable) error {
+	delete(r.rules, table)
+	return nil
+}
+
+func (r *fakeDataRetentionRepository) CreateLegalHold(hold *domain.LegalHold) error {
+	hold.ID = uuid.New()
+	hold.CreatedAt = time.Now().UTC()
+	r.holds = append(r.holds, hold)
+	return nil
+}
+
+func (r *fakeDataRetentionRepository) GetLegalHold(id uuid.UUID) (*domain.LegalHold, error) {
+	for _, hold := range r.holds {
+		if hold.ID == id {
+			return hold, nil
+		}
+	}
+	return nil, assert.AnError
+}
+
+func (r *fakeDataRetentionRepository) ListLegalHolds(orgID uuid.UUID, includeReleased bool) ([]*domain.LegalHold, error) {
+	holds := []*domain.LegalHold{}
+	for _, hold := range r.holds {
+		if includeReleased || hold.ReleasedAt == nil {
+			holds = append(holds, hold)
+		}
+	}
+	return holds, nil
+}
+
+func (r *fakeDataRetentionRepository) ReleaseLegalHold(id, releasedBy uuid.UUID, releasedAt time.Time) error {
+	hold, err := r.GetLegalHold(id)
+	if err != nil {
+		return err
+	}
+	hold.ReleasedBy = &releasedBy
+	hold.ReleasedAt = &releasedAt
+	return nil
+}
+
+func (r *fakeDataRetentionRepository) GetTableStats(orgID uuid.UUID, table domain.RetentionTable) (int64, *time.Time, error) {
+	rows := r.rows[table]
+	if len(rows) == 0 {
+		return 0, nil, nil
+	}
+	oldest := rows[0]
+	for _, writtenAt := range rows {
+		if writtenAt.Before(oldest) {
+			oldest = writtenAt
+		}
+	}
+	return int64(len(rows)), &oldest, nil
+}
+
+func (r *fakeDataRetentionRepository) PurgeBatch(ctx context.Context, orgID uuid.UUID, table domain.RetentionTable, before time.Time, limit int) (int64, error) {
+	r.batches[table]++
+	kept := []time.Time{}
+	deleted := 0
+	for _, writtenAt := range r.rows[table] {
+		if writtenAt.Before(before) && deleted < limit {
+			deleted++
+			continue
+		}
+		kept = append(kept, writtenAt)
+	}
+	r.rows[table] = kept
+	return int64(deleted), nil
+}
+
+func (r *fakeDataRetentionRepository) PurgeAuditLogBatch(ctx context.Context, orgID uuid.UUID, throughSequence int64, before time.Time, limit int) (int64, error) {
+	count := len(r.chain.entries)
+	r.chain.purgeThrough(throughSequence)
+	return int64(count - len(r.chain.entries)), nil
+}
+
+func newTestDataRetentionService(t *testing.T, chainEntries int) (*DataRetentionService, *fakeDataRetentionRepository, *AgentServiceMockAuditLogRepository, uuid.UUID) {
+	chainRepo, chain, orgID := newTestAuditChain(t, chainEntries)
+	repo := newFakeDataRetentionRepository(chainRepo)
+	auditRepo := new(AgentServiceMockAuditLogRepository)
+	auditRepo.On("Create", mock.Anything).Return(nil)
+
+	service := NewDataRetentionService(repo, auditRepo, chain, nil)
+	service.batchSize = 10
+	service.batchPause = 0
+	return service, repo, auditRepo, orgID
+}
+
+// createdAuditLogs returns the audit logs written to the mocked repository, oldest first
+func createdAuditLogs(auditRepo *AgentServiceMockAuditLogRepository) []*domain.AuditLog {
+	logs := []*domain.AuditLog{}
+	for _, call := range auditRepo.Calls {
+		if call.Method == "Create" {
+			logs = append(logs, call.Arguments.Get(0).(*domain.AuditLog))
+		}
+	}
+	return logs
+}
+
+func TestDataRetention_RulesAndPolicies(t *testing.T) {
+	service, repo, _, orgID := newTestDataRetentionService(t, 0)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	_, err := service.SetRule(ctx, orgID, userID, domain.RetentionTableAuditLogs, 30, true)
+	assert.ErrorContains(t, err, "invalid retention period")
+	_, err = service.SetRule(ctx, orgID, userID, "users", 30, true)
+	assert.ErrorContains(t, err, "invalid retention table")
+
+	_, err = service.SetRule(ctx, orgID, userID, domain.RetentionTableWebhookDeliveries, 14, true)
+	require.NoError(t, err)
+	repo.addRows(domain.RetentionTableWebhookDeliveries, 3, time.Now().AddDate(0, 0, -20))
+
+	table := domain.RetentionTableAlerts
+	_, err = service.CreateLegalHold(ctx, orgID, userID, &table, "  ")
+	assert.ErrorContains(t, err, "invalid legal hold")
+	_, err = service.CreateLegalHold(ctx, orgID, userID, &table, "litigation 2026-117")
+	require.NoError(t, err)
+
+	policies, err := service.GetPolicies(ctx, orgID)
+	require.NoError(t, err)
+	require.Len(t, policies, len(domain.RetentionTables))
+	byTable := map[domain.RetentionTable]*domain.RetentionPolicy{}
+	for _, policy := range policies {
+		byTable[policy.Table] = policy
+	}
+
+	assert.Equal(t, 14, byTable[domain.RetentionTableWebhookDeliveries].RetentionDays)
+	assert.False(t, byTable[domain.RetentionTableWebhookDeliveries].IsDefault)
+	assert.Equal(t, int64(3), byTable[domain.RetentionTableWebhookDeliveries].RecordCount)
+	assert.Equal(t, 365, byTable[domain.RetentionTableAuditLogs].RetentionDays)
+	assert.True(t, byTable[domain.RetentionTableAuditLogs].IsDefault)
+	assert.True(t, byTable[domain.RetentionTableAlerts].OnLegalHold)
+	assert.False(t, byTable[domain.RetentionTableDetections].OnLegalHold)
+
+	require.NoError(t, service.DeleteRule(ctx, orgID, domain.RetentionTableWebhookDeliveries))
+	policies, err = service.GetPolicies(ctx, orgID)
+	require.NoError(t, err)
+	for _, policy := range policies {
+		assert.True(t, policy.IsDefault, policy.Table)
+	}
+}
+
+func TestDataRetention_PurgeInBatchesRespectingHolds(t *testing.T) {
+	service, repo, auditRepo, orgID := newTestDataRetentionService(t, 0)
+	ctx := context.Background()
+	userID := uuid.New()
+	old := time.Now().AddDate(0, 0, -200)
+
+	repo.addRows(domain.RetentionTableVerificationEvents, 25, old)
+	repo.addRows(domain.RetentionTableVerificationEvents, 5, time.Now())
+	repo.addRows(domain.RetentionTableAlerts, 4, old)
+	repo.addRows(domain.RetentionTableDetections, 6, old)
+
+	table := domain.RetentionTableAlerts
+	hold, err := service.CreateLegalHold(ctx, orgID, userID, &table, "regulator request")
+	require.NoError(t, err)
+	_, err = service.SetRule(ctx, orgID, userID, domain.RetentionTableDetections, 30, false)
+	require.NoError(t, err)
+
+	run, err := service.PurgeOrganization(ctx, orgID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), run.TotalDeleted)
+	assert.Len(t, repo.rows[domain.RetentionTableVerificationEvents], 5)
+	assert.Equal(t, 3, repo.batches[domain.RetentionTableVerificationEvents], "25 rows in batches of 10")
+	assert.Len(t, repo.rows[domain.RetentionTableAlerts], 4, "held")
+	assert.Len(t, repo.rows[domain.RetentionTableDetections], 6, "retention disabled")
+
+	results := map[domain.RetentionTable]domain.RetentionPurgeTable{}
+	for _, result := range run.Tables {
+		results[result.Table] = result
+	}
+	assert.Equal(t, "legal hold", results[domain.RetentionTableAlerts].Skipped)
+	assert.Equal(t, "retention disabled", results[domain.RetentionTableDetections].Skipped)
+	assert.True(t, results[domain.RetentionTableVerificationEvents].Complete)
+
+	// The run is recorded with its row counts
+	logs := createdAuditLogs(auditRepo)
+	require.Len(t, logs, 1)
+	entry := logs[0]
+	assert.Equal(t, domain.AuditActionPurge, entry.Action)
+	assert.Equal(t, uuid.Nil, entry.UserID)
+	assert.Equal(t, "scheduled", entry.Metadata["trigger"])
+	assert.Equal(t, int64(25), entry.Metadata["total_deleted"])
+	tables := entry.Metadata["tables"].(map[string]interface{})
+	assert.Equal(t, int64(25), tables["verification_events"].(map[string]interface{})["rows_deleted"])
+
+	// Releasing the hold lets the next run purge
+	_, err = service.ReleaseLegalHold(ctx, uuid.New(), hold.ID, userID)
+	assert.ErrorContains(t, err, "not found")
+	_, err = service.ReleaseLegalHold(ctx, orgID, hold.ID, userID)
+	require.NoError(t, err)
+
+	run, err = service.PurgeOrganization(ctx, orgID, &userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), run.TotalDeleted)
+	logs = createdAuditLogs(auditRepo)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "manual", logs[1].Metadata["trigger"])
+	assert.Equal(t, userID, logs[1].UserID)
+}
+
+func TestDataRetention_AuditLogPurgeKeepsChainVerifiable(t *testing.T) {
+	service, repo, _, orgID := newTestDataRetentionService(t, 6)
+	ctx := context.Background()
+
+	_, err := service.auditChain.CreateCheckpoint(ctx, orgID)
+	require.NoError(t, err)
+	repo.chain.appendEntry(t, orgID, nil)
+
+	// A year later the checkpoint is past the audit retention period
+	service.now = func() time.Time { return time.Now().UTC().AddDate(1, 0, 2) }
+
+	run, err := service.PurgeOrganization(ctx, orgID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), run.TotalDeleted)
+	assert.Len(t, repo.chain.entries, 1)
+
+	result, err := service.auditChain.VerifyChain(ctx, orgID)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "%+v", result.FirstBrokenLink)
+	assert.Equal(t, int64(6), result.PrunedThrough)
+
+	// A tampered chain is never pruned
+	repo.chain.entries[0].Action = domain.AuditActionView
+	run, err = service.PurgeOrganization(ctx, orgID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), run.TotalDeleted)
+	for _, result := range run.Tables {
+		if result.Table == domain.RetentionTableAuditLogs {
+			assert.Contains(t, result.Error, "refusing to prune")
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/uuid"
@@ -76,6 +77,11 @@ func (m *MockAgentRepository) MarkAsCompromised(agentID uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockAgentRepository) UpdateLastActive(ctx context.Context, agentID uuid.UUID) error {
+	args := m.Called(ctx, agentID)
+	return args.Error(0)
+}
+
 // MockAlertRepository mocks the AlertRepository interface
 type MockAlertRepository struct {
 	mock.Mock
@@ -0,0 +1,60 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// In-memory repositories shared by service tests that assert on stored state
+// rather than on call expectations. Methods a test does not need fall through
+// to the embedded interface and panic if called.
+
+// fakeAgentRepository serves agents by ID and records updates
+type fakeAgentRepository struct {
+	domain.AgentRepository
+	agents  map[uuid.UUID]*domain.Agent
+	updated int
+}
+
+func (r *fakeAgentRepository) GetByID(id uuid.UUID) (*domain.Agent, error) {
+	if agent, ok := r.agents[id]; ok {
+		return agent, nil
+	}
+	return nil, fmt.Errorf("agent not found")
+}
+
+func (r *fakeAgentRepository) Update(agent *domain.Agent) error {
+	r.agents[agent.ID] = agent
+	r.updated++
+	return nil
+}
+
+// fakeUserRepository serves users by ID
+type fakeUserRepository struct {
+	domain.UserRepository
+	users map[uuid.UUID]*domain.User
+}
+
+func (r *fakeUserRepository) GetByID(id uuid.UUID) (*domain.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+// fakeAlertRepository keeps created alerts in memory
+type fakeAlertRepository struct {
+	domain.AlertRepository
+	alerts []*domain.Alert
+}
+
+func (r *fakeAlertRepository) Create(alert *domain.Alert) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func (r *fakeAlertRepository) GetUnacknowledged(orgID uuid.UUID) ([]*domain.Alert, error) {
+	return r.alerts, nil
+}
@@ -0,0 +1,317 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/scheduler"
+)
+
+// DefaultScheduledJobTimeout bounds a single job run when no timeout is registered
+const DefaultScheduledJobTimeout = 30 * time.Minute
+
+// ScheduledJobFunc is the work performed by a scheduled job
+type ScheduledJobFunc func(ctx context.Context) error
+
+// ScheduledJob is a maintenance task registered with the scheduler
+type ScheduledJob struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Schedule    string        `json:"schedule"`
+	Timeout     time.Duration `json:"-"`
+
+	schedule scheduler.Schedule
+	run      ScheduledJobFunc
+}
+
+// ScheduledJobStatus is a job with its current scheduling state
+type ScheduledJobStatus struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Schedule    string                  `json:"schedule"`
+	NextRunAt   *time.Time              `json:"next_run_at,omitempty"`
+	Running     bool                    `json:"running"`
+	LastRun     *domain.ScheduledJobRun `json:"last_run,omitempty"`
+}
+
+// JobSchedulerService runs registered maintenance jobs on cron schedules. A Postgres advisory
+// lock per job and a per-slot run record make sure only one replica runs each job.
+type JobSchedulerService struct {
+	jobRepo    domain.ScheduledJobRepository
+	instanceID string
+
+	mu       sync.Mutex
+	jobs     map[string]*ScheduledJob
+	nextRuns map[string]time.Time
+	running  map[string]bool
+	baseCtx  context.Context
+	wg       sync.WaitGroup
+}
+
+// NewJobSchedulerService creates a new job scheduler
+func NewJobSchedulerService(jobRepo domain.ScheduledJobRepository) *JobSchedulerService {
+	hostname, _ := os.Hostname()
+	return &JobSchedulerService{
+		jobRepo:    jobRepo,
+		instanceID: fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		jobs:       make(map[string]*ScheduledJob),
+		nextRuns:   make(map[string]time.Time),
+		running:    make(map[string]bool),
+		baseCtx:    context.Background(),
+	}
+}
+
+// Register adds a job; schedule is a 5-field cron expression or descriptor such as "@every 15m"
+func (s *JobSchedulerService) Register(name, description, schedule string, timeout time.Duration, run ScheduledJobFunc) error {
+	parsed, err := scheduler.Parse(schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule for job %s: %w", name, err)
+	}
+	if timeout <= 0 {
+		timeout = DefaultScheduledJobTimeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s is already registered", name)
+	}
+
+	s.jobs[name] = &ScheduledJob{
+		Name:        name,
+		Description: description,
+		Schedule:    schedule,
+		Timeout:     timeout,
+		schedule:    parsed,
+		run:         run,
+	}
+
+	return nil
+}
+
+// Start runs every registered job on its schedule until ctx is cancelled
+func (s *JobSchedulerService) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.baseCtx = ctx
+	jobs := make([]*ScheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+
+	fmt.Printf("✅ Job scheduler started with %d jobs (instance %s)\n", len(jobs), s.instanceID)
+}
+
+// Wait blocks until every job loop has exited after Start's context was cancelled
+func (s *JobSchedulerService) Wait() {
+	s.wg.Wait()
+}
+
+func (s *JobSchedulerService) loop(ctx context.Context, job *ScheduledJob) {
+	defer s.wg.Done()
+
+	for {
+		next := job.schedule.Next(time.Now().UTC())
+		if next.IsZero() {
+			return
+		}
+
+		s.mu.Lock()
+		s.nextRuns[job.Name] = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		run, execute, err := s.begin(ctx, job, domain.ScheduledJobTriggerScheduled, nil, &next)
+		if err != nil {
+			fmt.Printf("ℹ️  Skipping job %s: %v\n", job.Name, err)
+			continue
+		}
+		if run == nil {
+			continue // Another replica already ran this slot
+		}
+		execute()
+	}
+}
+
+// TriggerJob starts a job immediately in the background and returns its run record
+func (s *JobSchedulerService) TriggerJob(ctx context.Context, name string, triggeredBy uuid.UUID) (*domain.ScheduledJobRun, error) {
+	s.mu.Lock()
+	job, ok := s.jobs[name]
+	baseCtx := s.baseCtx
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("scheduled job not found")
+	}
+
+	run, execute, err := s.begin(baseCtx, job, domain.ScheduledJobTriggerManual, &triggeredBy, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	go execute()
+
+	return run, nil
+}
+
+// begin claims the job (locally and cluster-wide) and records the run. It returns a nil run
+// when a scheduled slot was already handled by another replica.
+func (s *JobSchedulerService) begin(
+	ctx context.Context,
+	job *ScheduledJob,
+	trigger domain.ScheduledJobTrigger,
+	triggeredBy *uuid.UUID,
+	scheduledFor *time.Time,
+) (*domain.ScheduledJobRun, func(), error) {
+	s.mu.Lock()
+	if s.running[job.Name] {
+		s.mu.Unlock()
+		return nil, nil, fmt.Errorf("job is already running")
+	}
+	s.running[job.Name] = true
+	s.mu.Unlock()
+
+	release := func() {
+		s.mu.Lock()
+		delete(s.running, job.Name)
+		s.mu.Unlock()
+	}
+
+	unlock, acquired, err := s.jobRepo.TryLock(ctx, job.Name)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	if !acquired {
+		release()
+		return nil, nil, fmt.Errorf("job is already running")
+	}
+
+	if scheduledFor != nil {
+		alreadyRan, err := s.jobRepo.HasScheduledRun(job.Name, *scheduledFor)
+		if err != nil || alreadyRan {
+			unlock()
+			release()
+			return nil, nil, err
+		}
+	}
+
+	run := &domain.ScheduledJobRun{
+		ID:           uuid.New(),
+		JobName:      job.Name,
+		Trigger:      trigger,
+		TriggeredBy:  triggeredBy,
+		Status:       domain.ScheduledJobRunRunning,
+		ScheduledFor: scheduledFor,
+		StartedAt:    time.Now().UTC(),
+		InstanceID:   s.instanceID,
+	}
+	if err := s.jobRepo.CreateRun(run); err != nil {
+		unlock()
+		release()
+		return nil, nil, err
+	}
+
+	// Manual runs are returned to the caller while they execute, so work on a copy
+	result := *run
+
+	execute := func() {
+		defer release()
+		defer unlock()
+
+		runCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+
+		jobErr := s.execute(runCtx, job)
+
+		finishedAt := time.Now().UTC()
+		durationMs := finishedAt.Sub(run.StartedAt).Milliseconds()
+		run.FinishedAt = &finishedAt
+		run.DurationMs = &durationMs
+		run.Status = domain.ScheduledJobRunSucceeded
+		if jobErr != nil {
+			run.Status = domain.ScheduledJobRunFailed
+			run.Error = jobErr.Error()
+			fmt.Printf("⚠️  Job %s failed after %dms: %v\n", job.Name, durationMs, jobErr)
+		}
+
+		if err := s.jobRepo.FinishRun(run); err != nil {
+			fmt.Printf("⚠️  Failed to record run of job %s: %v\n", job.Name, err)
+		}
+	}
+
+	return &result, execute, nil
+}
+
+// execute runs the job function, converting panics into errors
+func (s *JobSchedulerService) execute(ctx context.Context, job *ScheduledJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return job.run(ctx)
+}
+
+// ListJobs returns every registered job with its next and last run
+func (s *JobSchedulerService) ListJobs(ctx context.Context) ([]*ScheduledJobStatus, error) {
+	s.mu.Lock()
+	statuses := make([]*ScheduledJobStatus, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		status := &ScheduledJobStatus{
+			Name:        job.Name,
+			Description: job.Description,
+			Schedule:    job.Schedule,
+			Running:     s.running[job.Name],
+		}
+		if next, ok := s.nextRuns[job.Name]; ok {
+			status.NextRunAt = &next
+		}
+		statuses = append(statuses, status)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	for _, status := range statuses {
+		lastRun, err := s.jobRepo.GetLastRun(status.Name)
+		if err != nil {
+			return nil, err
+		}
+		status.LastRun = lastRun
+	}
+
+	return statuses, nil
+}
+
+// GetJobRuns returns the run history of a job, newest first
+func (s *JobSchedulerService) GetJobRuns(ctx context.Context, name string, limit, offset int) ([]*domain.ScheduledJobRun, error) {
+	s.mu.Lock()
+	_, ok := s.jobs[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("scheduled job not found")
+	}
+
+	return s.jobRepo.GetRunsByJob(name, limit, offset)
+}
@@ -0,0 +1,212 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScheduledJobRepository keeps job runs and locks in memory
+type fakeScheduledJobRepository struct {
+	mu       sync.Mutex
+	runs     map[uuid.UUID]*domain.ScheduledJobRun
+	locked   map[string]bool
+	finished chan *domain.ScheduledJobRun
+}
+
+func newFakeScheduledJobRepository() *fakeScheduledJobRepository {
+	return &fakeScheduledJobRepository{
+		runs:     map[uuid.UUID]*domain.ScheduledJobRun{},
+		locked:   map[string]bool{},
+		finished: make(chan *domain.ScheduledJobRun, 10),
+	}
+}
+
+func (r *fakeScheduledJobRepository) TryLock(ctx context.Context, jobName string) (func(), bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.locked[jobName] {
+		return nil, false, nil
+	}
+	r.locked[jobName] = true
+	return func() {
+		r.mu.Lock()
+		delete(r.locked, jobName)
+		r.mu.Unlock()
+	}, true, nil
+}
+
+func (r *fakeScheduledJobRepository) CreateRun(run *domain.ScheduledJobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *run
+	r.runs[run.ID] = &copied
+	return nil
+}
+
+func (r *fakeScheduledJobRepository) FinishRun(run *domain.ScheduledJobRun) error {
+	r.mu.Lock()
+	copied := *run
+	r.runs[run.ID] = &copied
+	r.mu.Unlock()
+	r.finished <- &copied
+	return nil
+}
+
+func (r *fakeScheduledJobRepository) GetRunsByJob(jobName string, limit, offset int) ([]*domain.ScheduledJobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	runs := []*domain.ScheduledJobRun{}
+	for _, run := range r.runs {
+		if run.JobName == jobName {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func (r *fakeScheduledJobRepository) GetLastRun(jobName string) (*domain.ScheduledJobRun, error) {
+	runs, _ := r.GetRunsByJob(jobName, 1, 0)
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	return runs[0], nil
+}
+
+func (r *fakeScheduledJobRepository) HasScheduledRun(jobName string, scheduledFor time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, run := range r.runs {
+		if run.JobName == jobName && run.ScheduledFor != nil && !run.ScheduledFor.Before(scheduledFor) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func waitForRun(t *testing.T, repo *fakeScheduledJobRepository) *domain.ScheduledJobRun {
+	select {
+	case run := <-repo.finished:
+		return run
+	case <-time.After(2 * time.Second):
+		t.Fatal("job did not finish")
+		return nil
+	}
+}
+
+func TestJobScheduler_TriggerRecordsSuccessfulRun(t *testing.T) {
+	repo := newFakeScheduledJobRepository()
+	scheduler := NewJobSchedulerService(repo)
+	calls := 0
+	require.NoError(t, scheduler.Register("cleanup", "Cleanup", "@hourly", time.Minute, func(ctx context.Context) error {
+		calls++
+		return nil
+	}))
+
+	userID := uuid.New()
+	run, err := scheduler.TriggerJob(context.Background(), "cleanup", userID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ScheduledJobRunRunning, run.Status)
+	assert.Equal(t, domain.ScheduledJobTriggerManual, run.Trigger)
+	assert.Equal(t, userID, *run.TriggeredBy)
+
+	finished := waitForRun(t, repo)
+	assert.Equal(t, run.ID, finished.ID)
+	assert.Equal(t, domain.ScheduledJobRunSucceeded, finished.Status)
+	assert.NotNil(t, finished.FinishedAt)
+	assert.NotNil(t, finished.DurationMs)
+	assert.Empty(t, finished.Error)
+	assert.Equal(t, 1, calls)
+}
+
+func TestJobScheduler_FailuresAndPanicsAreRecorded(t *testing.T) {
+	repo := newFakeScheduledJobRepository()
+	scheduler := NewJobSchedulerService(repo)
+	require.NoError(t, scheduler.Register("failing", "Fails", "@daily", 0, func(ctx context.Context) error {
+		return fmt.Errorf("database unavailable")
+	}))
+	require.NoError(t, scheduler.Register("panicking", "Panics", "@daily", 0, func(ctx context.Context) error {
+		panic("boom")
+	}))
+
+	_, err := scheduler.TriggerJob(context.Background(), "failing", uuid.New())
+	require.NoError(t, err)
+	failed := waitForRun(t, repo)
+	assert.Equal(t, domain.ScheduledJobRunFailed, failed.Status)
+	assert.Equal(t, "database unavailable", failed.Error)
+
+	_, err = scheduler.TriggerJob(context.Background(), "panicking", uuid.New())
+	require.NoError(t, err)
+	panicked := waitForRun(t, repo)
+	assert.Equal(t, domain.ScheduledJobRunFailed, panicked.Status)
+	assert.Contains(t, panicked.Error, "boom")
+}
+
+func TestJobScheduler_LockHeldByAnotherReplica(t *testing.T) {
+	repo := newFakeScheduledJobRepository()
+	scheduler := NewJobSchedulerService(repo)
+	require.NoError(t, scheduler.Register("cleanup", "Cleanup", "@hourly", 0, func(ctx context.Context) error { return nil }))
+
+	repo.locked["cleanup"] = true
+
+	_, err := scheduler.TriggerJob(context.Background(), "cleanup", uuid.New())
+	assert.EqualError(t, err, "job is already running")
+	assert.Empty(t, repo.runs)
+}
+
+func TestJobScheduler_ScheduledSlotRunsOnce(t *testing.T) {
+	repo := newFakeScheduledJobRepository()
+	first := NewJobSchedulerService(repo)
+	second := NewJobSchedulerService(repo)
+	calls := 0
+	job := func(ctx context.Context) error {
+		calls++
+		return nil
+	}
+	require.NoError(t, first.Register("recalculate", "Recalculate", "0 * * * *", 0, job))
+	require.NoError(t, second.Register("recalculate", "Recalculate", "0 * * * *", 0, job))
+
+	slot := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	run, execute, err := first.begin(context.Background(), first.jobs["recalculate"], domain.ScheduledJobTriggerScheduled, nil, &slot)
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	execute()
+	waitForRun(t, repo)
+
+	// The other replica wakes up for the same slot after the first one finished
+	run, _, err = second.begin(context.Background(), second.jobs["recalculate"], domain.ScheduledJobTriggerScheduled, nil, &slot)
+	require.NoError(t, err)
+	assert.Nil(t, run)
+	assert.Equal(t, 1, calls)
+	assert.False(t, repo.locked["recalculate"])
+}
+
+func TestJobScheduler_RegisterAndList(t *testing.T) {
+	scheduler := NewJobSchedulerService(newFakeScheduledJobRepository())
+	noop := func(ctx context.Context) error { return nil }
+
+	require.NoError(t, scheduler.Register("b_job", "B", "*/5 * * * *", 0, noop))
+	require.NoError(t, scheduler.Register("a_job", "A", "@every 15m", 0, noop))
+	assert.Error(t, scheduler.Register("a_job", "A", "@hourly", 0, noop))
+	assert.Error(t, scheduler.Register("bad", "Bad", "61 * * * *", 0, noop))
+
+	jobs, err := scheduler.ListJobs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "a_job", jobs[0].Name)
+	assert.Equal(t, "@every 15m", jobs[0].Schedule)
+	assert.Nil(t, jobs[0].LastRun)
+
+	_, err = scheduler.TriggerJob(context.Background(), "missing", uuid.New())
+	assert.EqualError(t, err, "scheduled job not found")
+	_, err = scheduler.GetJobRuns(context.Background(), "missing", 10, 0)
+	assert.EqualError(t, err, "scheduled job not found")
+}
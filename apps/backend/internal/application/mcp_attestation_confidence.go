@@ -0,0 +1,123 @@
+package application
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// Anti-sybil confidence scoring parameters
+const (
+	// AttestationAgentMaturityPeriod is how long an attesting agent must exist before its
+	// attestation carries full weight (newly registered agents ramp up linearly)
+	AttestationAgentMaturityPeriod = 30 * 24 * time.Hour
+
+	// AttestationRecencyWindow is how recent an attestation must be to count at full weight
+	AttestationRecencyWindow = 7 * 24 * time.Hour
+
+	// StaleAttestationWeight is the weight multiplier for attestations older than the recency window
+	StaleAttestationWeight = 0.5
+
+	// MaxAttestationWeightPerCreator caps the combined weight of all agents created by the
+	// same user, so registering many throwaway agents cannot inflate the score
+	MaxAttestationWeightPerCreator = 1.0
+
+	// FullConfidenceAttestationWeight is the total (capped) weight needed for a score of 100
+	FullConfidenceAttestationWeight = 4.0
+
+	// MinAttestationOwnersForFullConfidence is the number of distinct agent creators required
+	// before the score can reach 100; fewer owners cap the score proportionally
+	MinAttestationOwnersForFullConfidence = 3
+)
+
+// AttestationConfidence is the outcome of scoring an MCP server's valid attestations
+type AttestationConfidence struct {
+	Score            float64   `json:"score"`
+	AttestationCount int       `json:"attestation_count"`
+	AgentCount       int       `json:"agent_count"`
+	OwnerCount       int       `json:"owner_count"`
+	EffectiveWeight  float64   `json:"effective_weight"`
+	LastAttestedAt   time.Time `json:"last_attested_at"`
+}
+
+// CalculateAttestationConfidence scores an MCP server from its valid attestations.
+//
+// Each attesting agent counts once (its latest attestation) and is weighted by its trust
+// score, its age and the recency of the attestation. Weights are summed per owner (the user
+// who created the agent) and capped, and the final score is limited by how many distinct
+// owners vouch for the server.
+func CalculateAttestationConfidence(attestations []*domain.MCPAttestation, now time.Time) AttestationConfidence {
+	result := AttestationConfidence{AttestationCount: len(attestations)}
+
+	// Latest attestation per agent
+	latest := make(map[uuid.UUID]*domain.MCPAttestation)
+	for _, att := range attestations {
+		if att.VerifiedAt != nil && att.VerifiedAt.After(result.LastAttestedAt) {
+			result.LastAttestedAt = *att.VerifiedAt
+		}
+		if current, ok := latest[att.AgentID]; !ok || attestationTime(att).After(attestationTime(current)) {
+			latest[att.AgentID] = att
+		}
+	}
+	result.AgentCount = len(latest)
+
+	// Sum agent weights per owner, capped per owner
+	ownerWeights := make(map[uuid.UUID]float64)
+	for _, att := range latest {
+		owner := attestationOwner(att)
+		ownerWeights[owner] = math.Min(ownerWeights[owner]+attestationWeight(att, now), MaxAttestationWeightPerCreator)
+	}
+	result.OwnerCount = len(ownerWeights)
+
+	for _, weight := range ownerWeights {
+		result.EffectiveWeight += weight
+	}
+
+	score := math.Min(result.EffectiveWeight/FullConfidenceAttestationWeight, 1.0) * 100.0
+
+	// Diversity requirement: too few independent owners caps the score
+	diversityCap := math.Min(float64(result.OwnerCount)/float64(MinAttestationOwnersForFullConfidence), 1.0) * 100.0
+	result.Score = math.Round(math.Min(score, diversityCap)*100) / 100
+
+	return result
+}
+
+// attestationWeight is the 0-1 weight of a single agent's attestation
+func attestationWeight(att *domain.MCPAttestation, now time.Time) float64 {
+	// Agent trust scores are stored on a 0-100 scale
+	trust := math.Max(0, math.Min(att.AgentTrustScore/100.0, 1.0))
+
+	// Young agents ramp up to full weight over the maturity period
+	age := 0.0
+	if att.AgentCreatedAt != nil {
+		age = math.Max(0, math.Min(now.Sub(*att.AgentCreatedAt).Hours()/AttestationAgentMaturityPeriod.Hours(), 1.0))
+	}
+
+	recency := 1.0
+	if now.Sub(attestationTime(att)) > AttestationRecencyWindow {
+		recency = StaleAttestationWeight
+	}
+
+	return trust * age * recency
+}
+
+// attestationOwner groups agents by their creator, falling back to the agent's organization
+// (and finally the agent itself) when the creator is unknown
+func attestationOwner(att *domain.MCPAttestation) uuid.UUID {
+	if att.AgentCreatedBy != nil {
+		return *att.AgentCreatedBy
+	}
+	if att.AgentOrganizationID != nil {
+		return *att.AgentOrganizationID
+	}
+	return att.AgentID
+}
+
+func attestationTime(att *domain.MCPAttestation) time.Time {
+	if att.VerifiedAt != nil {
+		return *att.VerifiedAt
+	}
+	return att.CreatedAt
+}
@@ -0,0 +1,120 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAttestation(creator uuid.UUID, trust float64, agentAge, attestedAgo time.Duration, now time.Time) *domain.MCPAttestation {
+	orgID := uuid.New()
+	createdAt := now.Add(-agentAge)
+	verifiedAt := now.Add(-attestedAgo)
+	return &domain.MCPAttestation{
+		ID:                  uuid.New(),
+		AgentID:             uuid.New(),
+		VerifiedAt:          &verifiedAt,
+		IsValid:             true,
+		AgentTrustScore:     trust,
+		AgentOrganizationID: &orgID,
+		AgentCreatedBy:      &creator,
+		AgentCreatedAt:      &createdAt,
+	}
+}
+
+func TestCalculateAttestationConfidence_NoAttestations(t *testing.T) {
+	confidence := CalculateAttestationConfidence(nil, time.Now())
+
+	assert.Equal(t, 0.0, confidence.Score)
+	assert.Equal(t, 0, confidence.AgentCount)
+	assert.True(t, confidence.LastAttestedAt.IsZero())
+}
+
+func TestCalculateAttestationConfidence_SingleCreatorCannotInflate(t *testing.T) {
+	now := time.Now()
+	creator := uuid.New()
+
+	var attestations []*domain.MCPAttestation
+	for i := 0; i < 10; i++ {
+		attestations = append(attestations, testAttestation(creator, 100, 90*24*time.Hour, time.Hour, now))
+	}
+
+	confidence := CalculateAttestationConfidence(attestations, now)
+
+	assert.Equal(t, 10, confidence.AgentCount)
+	assert.Equal(t, 1, confidence.OwnerCount)
+	assert.Equal(t, MaxAttestationWeightPerCreator, confidence.EffectiveWeight)
+	assert.Equal(t, 25.0, confidence.Score)
+}
+
+func TestCalculateAttestationConfidence_DiverseMatureAgentsReachFullConfidence(t *testing.T) {
+	now := time.Now()
+
+	var attestations []*domain.MCPAttestation
+	for i := 0; i < 4; i++ {
+		attestations = append(attestations, testAttestation(uuid.New(), 100, 90*24*time.Hour, time.Hour, now))
+	}
+
+	confidence := CalculateAttestationConfidence(attestations, now)
+
+	assert.Equal(t, 4, confidence.OwnerCount)
+	assert.Equal(t, 100.0, confidence.Score)
+}
+
+func TestCalculateAttestationConfidence_OwnerDiversityCapsScore(t *testing.T) {
+	now := time.Now()
+	creatorA, creatorB := uuid.New(), uuid.New()
+
+	attestations := []*domain.MCPAttestation{
+		testAttestation(creatorA, 100, 90*24*time.Hour, time.Hour, now),
+		testAttestation(creatorB, 100, 90*24*time.Hour, time.Hour, now),
+	}
+
+	confidence := CalculateAttestationConfidence(attestations, now)
+
+	// 2 owners of weight 1 give 50 points, and the 2/3 diversity cap does not bind
+	assert.Equal(t, 50.0, confidence.Score)
+
+	attestations = append(attestations, testAttestation(creatorA, 100, 90*24*time.Hour, time.Hour, now))
+	assert.Equal(t, 50.0, CalculateAttestationConfidence(attestations, now).Score)
+}
+
+func TestCalculateAttestationConfidence_NewAndLowTrustAgentsCountLess(t *testing.T) {
+	now := time.Now()
+
+	mature := CalculateAttestationConfidence([]*domain.MCPAttestation{
+		testAttestation(uuid.New(), 80, 60*24*time.Hour, time.Hour, now),
+	}, now)
+	young := CalculateAttestationConfidence([]*domain.MCPAttestation{
+		testAttestation(uuid.New(), 80, 3*24*time.Hour, time.Hour, now),
+	}, now)
+	brandNew := CalculateAttestationConfidence([]*domain.MCPAttestation{
+		testAttestation(uuid.New(), 100, 0, time.Hour, now),
+	}, now)
+
+	assert.InDelta(t, 0.8, mature.EffectiveWeight, 0.0001)
+	assert.InDelta(t, 0.08, young.EffectiveWeight, 0.0001)
+	assert.Equal(t, 0.0, brandNew.Score)
+}
+
+func TestCalculateAttestationConfidence_UsesLatestAttestationPerAgent(t *testing.T) {
+	now := time.Now()
+	stale := testAttestation(uuid.New(), 100, 90*24*time.Hour, 20*24*time.Hour, now)
+
+	confidence := CalculateAttestationConfidence([]*domain.MCPAttestation{stale}, now)
+	assert.InDelta(t, StaleAttestationWeight, confidence.EffectiveWeight, 0.0001)
+
+	fresh := *stale
+	freshAt := now.Add(-time.Minute)
+	fresh.ID = uuid.New()
+	fresh.VerifiedAt = &freshAt
+
+	confidence = CalculateAttestationConfidence([]*domain.MCPAttestation{stale, &fresh}, now)
+	assert.Equal(t, 2, confidence.AttestationCount)
+	assert.Equal(t, 1, confidence.AgentCount)
+	assert.InDelta(t, 1.0, confidence.EffectiveWeight, 0.0001)
+	assert.Equal(t, freshAt, confidence.LastAttestedAt)
+}
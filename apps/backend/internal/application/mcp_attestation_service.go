@@ -155,67 +155,28 @@ func (s *MCPAttestationService) updateMCPConfidenceScore(
 	}
 
 	if len(attestations) == 0 {
-		// No attestations - confidence is 0
-		return 0, 0, nil
-	}
-
-	// Confidence calculation factors:
-	// 1. Number of unique agents attesting (20 points each, max 5 agents = 100)
-	// 2. Average trust score of attesting agents (0-50 points)
-	// 3. Recency of attestations (0-30 points)
-
-	uniqueAgents := make(map[uuid.UUID]bool)
-	var totalTrust float64
-	var mostRecentAttestation time.Time
-
-	for _, att := range attestations {
-		uniqueAgents[att.AgentID] = true
-		totalTrust += att.AgentTrustScore
-
-		if att.VerifiedAt != nil && att.VerifiedAt.After(mostRecentAttestation) {
-			mostRecentAttestation = *att.VerifiedAt
-		}
-	}
-
-	// Factor 1: Number of unique agents (20 points each, max 100)
-	agentCount := len(uniqueAgents)
-	agentPoints := float64(agentCount) * 20.0
-	if agentPoints > 100.0 {
-		agentPoints = 100.0
-	}
-
-	// Factor 2: Average trust score of attesting agents (0-50 points)
-	avgTrust := totalTrust / float64(len(attestations))
-	trustPoints := (avgTrust / 100.0) * 50.0 // Scale to 0-50
-
-	// Factor 3: Recency factor (% of attestations in last 7 days)
-	recentCount := 0
-	for _, att := range attestations {
-		if att.VerifiedAt != nil && time.Since(*att.VerifiedAt) < 7*24*time.Hour {
-			recentCount++
+		// No attestations left (e.g. all revoked or expired) - reset confidence to 0
+		if err := s.attestationRepo.UpdateMCPConfidenceScore(mcpServerID, 0, 0, time.Time{}); err != nil {
+			return 0, 0, err
 		}
+		return 0, 0, nil
 	}
-	recencyFactor := float64(recentCount) / float64(len(attestations))
-	recencyPoints := recencyFactor * 30.0
 
-	// Calculate final confidence score (0-100)
-	confidenceScore := (agentPoints + trustPoints + recencyPoints) / 1.8
-	if confidenceScore > 100.0 {
-		confidenceScore = 100.0
-	}
+	// Weighted by attesting agents' trust and age, capped per creator, limited by owner diversity
+	confidence := CalculateAttestationConfidence(attestations, time.Now().UTC())
 
 	// Update MCP server
 	err = s.attestationRepo.UpdateMCPConfidenceScore(
 		mcpServerID,
-		confidenceScore,
-		len(attestations),
-		mostRecentAttestation,
+		confidence.Score,
+		confidence.AttestationCount,
+		confidence.LastAttestedAt,
 	)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	return confidenceScore, len(attestations), nil
+	return confidence.Score, confidence.AttestationCount, nil
 }
 
 // updateAgentMCPConnection updates or creates the connection between agent and MCP
@@ -290,6 +251,11 @@ func (s *MCPAttestationService) GetMCPAttestations(
 		}
 		expiresAtStr = att.ExpiresAt.Format(time.RFC3339)
 
+		var revokedAtStr string
+		if att.RevokedAt != nil {
+			revokedAtStr = att.RevokedAt.Format(time.RFC3339)
+		}
+
 		result = append(result, &domain.AttestationWithAgentDetails{
 			ID:                    att.ID,
 			AgentID:               att.AgentID,
@@ -301,6 +267,8 @@ func (s *MCPAttestationService) GetMCPAttestations(
 			ConnectionLatencyMs:   att.AttestationData.ConnectionLatencyMs,
 			HealthCheckPassed:     att.AttestationData.HealthCheckPassed,
 			IsValid:               att.IsValid,
+			RevokedAt:             revokedAtStr,
+			RevocationReason:      att.RevocationReason,
 		})
 	}
 
@@ -390,6 +358,108 @@ func (s *MCPAttestationService) RecalculateAllConfidenceScores(ctx context.Conte
 	return nil
 }
 
+// AttestationRevocationResult reports what an admin revocation changed
+type AttestationRevocationResult struct {
+	RevokedCount int                        `json:"revoked_count"`
+	MCPServers   []MCPConfidenceScoreUpdate `json:"mcp_servers"`
+}
+
+// MCPConfidenceScoreUpdate is an MCP server's confidence after it was recomputed
+type MCPConfidenceScoreUpdate struct {
+	MCPServerID      uuid.UUID `json:"mcp_server_id"`
+	ConfidenceScore  float64   `json:"confidence_score"`
+	AttestationCount int       `json:"attestation_count"`
+}
+
+// RevokeAttestation revokes a single attestation and immediately recomputes the MCP server's confidence
+func (s *MCPAttestationService) RevokeAttestation(
+	ctx context.Context,
+	orgID uuid.UUID,
+	mcpServerID uuid.UUID,
+	attestationID uuid.UUID,
+	revokedBy uuid.UUID,
+	reason string,
+) (*AttestationRevocationResult, error) {
+	if reason == "" {
+		return nil, fmt.Errorf("revocation reason is required")
+	}
+
+	mcpServer, err := s.mcpRepo.GetByID(mcpServerID)
+	if err != nil || mcpServer.OrganizationID != orgID {
+		return nil, fmt.Errorf("mcp server not found")
+	}
+
+	attestation, err := s.attestationRepo.GetAttestationByID(attestationID)
+	if err != nil || attestation.MCPServerID != mcpServerID {
+		return nil, fmt.Errorf("attestation not found")
+	}
+
+	if attestation.RevokedAt != nil {
+		return nil, fmt.Errorf("attestation already revoked")
+	}
+
+	if err := s.attestationRepo.RevokeAttestation(attestationID, revokedBy, reason); err != nil {
+		return nil, err
+	}
+
+	score, count, err := s.updateMCPConfidenceScore(ctx, mcpServerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update confidence score: %w", err)
+	}
+
+	return &AttestationRevocationResult{
+		RevokedCount: 1,
+		MCPServers: []MCPConfidenceScoreUpdate{
+			{MCPServerID: mcpServerID, ConfidenceScore: score, AttestationCount: count},
+		},
+	}, nil
+}
+
+// RevokeAgentAttestations revokes every attestation made by a (compromised) agent and
+// immediately recomputes the confidence of each affected MCP server
+func (s *MCPAttestationService) RevokeAgentAttestations(
+	ctx context.Context,
+	orgID uuid.UUID,
+	agentID uuid.UUID,
+	revokedBy uuid.UUID,
+	reason string,
+) (*AttestationRevocationResult, error) {
+	if reason == "" {
+		return nil, fmt.Errorf("revocation reason is required")
+	}
+
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent.OrganizationID != orgID {
+		return nil, fmt.Errorf("agent not found")
+	}
+
+	mcpServerIDs, revoked, err := s.attestationRepo.RevokeAttestationsByAgent(agentID, revokedBy, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AttestationRevocationResult{
+		RevokedCount: revoked,
+		MCPServers:   []MCPConfidenceScoreUpdate{},
+	}
+
+	for _, mcpServerID := range mcpServerIDs {
+		score, count, err := s.updateMCPConfidenceScore(ctx, mcpServerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update confidence score for MCP %s: %w", mcpServerID, err)
+		}
+		result.MCPServers = append(result.MCPServers, MCPConfidenceScoreUpdate{
+			MCPServerID:      mcpServerID,
+			ConfidenceScore:  score,
+			AttestationCount: count,
+		})
+	}
+
+	fmt.Printf("🚨 Revoked %d attestations by agent %s across %d MCP servers: %s\n", revoked, agentID, len(mcpServerIDs), reason)
+
+	return result, nil
+}
+
 // ToCanonicalJSON is a helper to ensure consistent JSON serialization
 func toCanonicalJSON(v interface{}) ([]byte, error) {
 	return json.Marshal(v)
@@ -15,9 +15,12 @@ import (
 )
 
 type MCPCapabilityService struct {
-	capabilityRepo *repository.MCPServerCapabilityRepository
-	mcpRepo        *repository.MCPServerRepository
-	httpClient     *http.Client
+	capabilityRepo     *repository.MCPServerCapabilityRepository
+	mcpRepo            *repository.MCPServerRepository
+	toolSurfaceService *MCPToolSurfaceService // ✅ For tool-surface change (rug-pull) detection
+	metadataScanner    *MCPMetadataScanner    // ✅ For prompt-injection / tool-poisoning detection
+	alertRepo          domain.AlertRepository
+	httpClient         *http.Client
 }
 
 // MCPCapabilitiesResponse represents the standard MCP protocol capabilities response
@@ -55,10 +58,16 @@ type MCPPrompt struct {
 func NewMCPCapabilityService(
 	capabilityRepo *repository.MCPServerCapabilityRepository,
 	mcpRepo *repository.MCPServerRepository,
+	toolSurfaceService *MCPToolSurfaceService,
+	metadataScanner *MCPMetadataScanner,
+	alertRepo domain.AlertRepository,
 ) *MCPCapabilityService {
 	return &MCPCapabilityService{
-		capabilityRepo: capabilityRepo,
-		mcpRepo:        mcpRepo,
+		capabilityRepo:     capabilityRepo,
+		mcpRepo:            mcpRepo,
+		toolSurfaceService: toolSurfaceService,
+		metadataScanner:    metadataScanner,
+		alertRepo:          alertRepo,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second, // 30 second timeout for capability discovery
 		},
@@ -74,6 +83,9 @@ func (s *MCPCapabilityService) DetectCapabilities(ctx context.Context, serverID
 	if err != nil {
 		return fmt.Errorf("failed to get MCP server: %w", err)
 	}
+	if err := requireRemoteMCPServer(server); err != nil {
+		return err
+	}
 
 	// ✅ REAL MCP PROTOCOL CAPABILITY DETECTION
 	// Step 1: Construct MCP capabilities endpoint URL
@@ -183,21 +195,132 @@ func (s *MCPCapabilityService) DetectCapabilities(ctx context.Context, serverID
 		})
 	}
 
-	// Step 5: Store detected capabilities in database
+	// Step 5: Scan tool metadata for prompt injection / tool poisoning before anything is stored
+	var newFindings []CapabilityFinding
+	var previous []*domain.MCPServerCapability
+	if s.metadataScanner != nil {
+		previous, err = s.capabilityRepo.GetByServerID(serverID)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to load previous capabilities for %s: %v\n", server.Name, err)
+		}
+		s.metadataScanner.ScanCapabilities(capabilities)
+		newFindings = NewMetadataFindings(previous, capabilities)
+	}
+
+	// Step 6: Compare against the last approved tool surface (rug-pull detection)
+	var drift *ToolSurfaceDiscoveryResult
+	if s.toolSurfaceService != nil {
+		result, err := s.toolSurfaceService.RecordDiscovery(ctx, server, capabilities)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to record tool surface snapshot for %s: %v\n", server.Name, err)
+		} else if !result.DriftDetected && FindingsCountedWithDrift(result.Snapshot, previous) {
+			// First discovery since an admin approved the change: its findings were counted with the drift
+			newFindings = nil
+		} else if result.DriftDetected {
+			fmt.Printf("🚨 Tool surface of MCP server %s differs from approved snapshot (%s v%d)\n", server.Name, result.Snapshot.Status, result.Snapshot.Version)
+			drift = result
+		}
+	}
+
+	// Step 7: Only findings that were not reported before lower the trust score. Capabilities of
+	// an unapproved tool surface are not stored until it is approved, so its findings count once,
+	// with the drift.
+	if len(newFindings) > 0 && (drift == nil || !drift.AlreadyReported) {
+		s.applyMetadataFindings(server, newFindings)
+	}
+
+	// The approved capabilities stay in force until an admin approves the new tool surface
+	if drift != nil {
+		fmt.Printf("⚠️  Keeping approved capabilities of MCP server %s until the tool surface change is reviewed\n", server.Name)
+		return nil
+	}
+
+	// Step 8: Store detected capabilities in database
+	// Upsert so re-discovery refreshes existing rows instead of failing on the unique constraint
+	storedIDs := make([]uuid.UUID, 0, len(capabilities))
 	for _, cap := range capabilities {
-		if err := s.capabilityRepo.Create(cap); err != nil {
+		if err := s.capabilityRepo.Upsert(cap); err != nil {
 			// Log error but continue with other capabilities
 			fmt.Printf("⚠️  Failed to store capability %s: %v\n", cap.Name, err)
 			continue
 		}
+		storedIDs = append(storedIDs, cap.ID)
 
 		fmt.Printf("✅ Detected %s capability: %s\n", cap.CapabilityType, cap.Name)
 	}
 
+	// Capabilities no longer exposed by the server are deactivated (history is kept)
+	if err := s.capabilityRepo.DeactivateMissing(serverID, storedIDs); err != nil {
+		fmt.Printf("⚠️  Failed to deactivate removed capabilities for %s: %v\n", server.Name, err)
+	}
+
 	fmt.Printf("✅ Successfully detected %d real capabilities from MCP server %s\n", len(capabilities), server.Name)
 	return nil
 }
 
+// applyMetadataFindings lowers the server trust score for new scanner findings and raises
+// a security alert when any of them is high or critical
+func (s *MCPCapabilityService) applyMetadataFindings(server *domain.MCPServer, findings []CapabilityFinding) {
+	previousScore := server.TrustScore
+	server.TrustScore -= MetadataFindingsPenalty(findings)
+	if server.TrustScore < MinimumTrustScore {
+		server.TrustScore = MinimumTrustScore
+	}
+	if err := s.mcpRepo.Update(server); err != nil {
+		fmt.Printf("⚠️  Failed to apply metadata findings to MCP server %s: %v\n", server.Name, err)
+		return
+	}
+
+	fmt.Printf("⚠️  %d new metadata findings on MCP server %s: trust %.2f -> %.2f\n",
+		len(findings), server.Name, previousScore, server.TrustScore)
+
+	var serious []CapabilityFinding
+	severity := domain.AlertSeverityHigh
+	for _, f := range findings {
+		if f.Finding.Severity == domain.AlertSeverityHigh || f.Finding.Severity == domain.AlertSeverityCritical {
+			serious = append(serious, f)
+		}
+		if f.Finding.Severity == domain.AlertSeverityCritical {
+			severity = domain.AlertSeverityCritical
+		}
+	}
+	if len(serious) == 0 || s.alertRepo == nil {
+		return
+	}
+
+	message := fmt.Sprintf("The metadata scanner found %d new high-risk issues in the tools exposed by MCP server '%s'. "+
+		"Tool names, descriptions and schemas are sent to the model verbatim and can carry prompt injections.", len(serious), server.Name)
+	message += "\n\n**Findings:**\n"
+	for _, f := range serious {
+		message += fmt.Sprintf("- `%s` (%s, %s): %s", f.CapabilityName, f.Finding.Field, f.Finding.Severity, f.Finding.Message)
+		if f.Finding.Evidence != "" {
+			message += fmt.Sprintf(" - \"%s\"", f.Finding.Evidence)
+		}
+		message += "\n"
+	}
+	message += fmt.Sprintf("\n**Trust Score:** %.2f -> %.2f\n", previousScore, server.TrustScore)
+	message += "\n**Recommended Actions:**\n"
+	message += "1. Review the flagged tool descriptions and schemas\n"
+	message += "2. Disconnect agents from this server if the instructions are not legitimate\n"
+	message += "3. Report the server to its publisher\n"
+
+	alert := &domain.Alert{
+		ID:             uuid.New(),
+		OrganizationID: server.OrganizationID,
+		AlertType:      domain.AlertSecurityBreach,
+		Severity:       severity,
+		Title:          fmt.Sprintf("Suspicious MCP Tool Metadata: %s", server.Name),
+		Description:    message,
+		ResourceType:   "mcp_server",
+		ResourceID:     server.ID,
+		IsAcknowledged: false,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.alertRepo.Create(alert); err != nil {
+		fmt.Printf("⚠️  Failed to create metadata findings alert for MCP server %s: %v\n", server.Name, err)
+	}
+}
+
 // GetCapabilities retrieves all capabilities for an MCP server
 func (s *MCPCapabilityService) GetCapabilities(ctx context.Context, serverID uuid.UUID) ([]*domain.MCPServerCapability, error) {
 	return s.capabilityRepo.GetByServerID(serverID)
@@ -0,0 +1,242 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// MaxMCPConfigSize is the largest MCP client config accepted for import
+const MaxMCPConfigSize = 1 << 20
+
+// MCPConfigFormat identifies the AI client an uploaded MCP config belongs to
+type MCPConfigFormat string
+
+const (
+	MCPConfigClaudeDesktop MCPConfigFormat = "claude_desktop" // claude_desktop_config.json
+	MCPConfigCursor        MCPConfigFormat = "cursor"         // .cursor/mcp.json
+	MCPConfigVSCode        MCPConfigFormat = "vscode"         // .vscode/mcp.json or settings.json ("mcp" section)
+	MCPConfigWindsurf      MCPConfigFormat = "windsurf"       // ~/.codeium/windsurf/mcp_config.json
+	MCPConfigContinue      MCPConfigFormat = "continue"       // ~/.continue/config.json
+)
+
+// redactedEnvValue replaces environment values of imported servers; configs routinely carry API keys
+const redactedEnvValue = "[REDACTED]"
+
+// mcpConfigServer is one server entry in any of the supported formats
+type mcpConfigServer struct {
+	Name      string            `json:"name"` // Continue only; other formats key servers by name
+	Type      string            `json:"type"` // VS Code: stdio, sse or http
+	Command   string            `json:"command"`
+	Args      []string          `json:"args"`
+	Env       map[string]string `json:"env"`
+	URL       string            `json:"url"`
+	ServerURL string            `json:"serverUrl"` // Windsurf remote servers
+	Transport *mcpConfigServer  `json:"transport"` // Continue experimental.modelContextProtocolServers
+}
+
+// mcpConfigFile is the union of the top-level shapes of the supported formats
+type mcpConfigFile struct {
+	MCPServers json.RawMessage            `json:"mcpServers"` // Object (Claude, Cursor, Windsurf) or array (Continue)
+	Servers    map[string]mcpConfigServer `json:"servers"`    // VS Code mcp.json
+	MCP        *struct {
+		Servers map[string]mcpConfigServer `json:"servers"`
+	} `json:"mcp"` // VS Code settings.json
+	Experimental *struct {
+		ModelContextProtocolServers []mcpConfigServer `json:"modelContextProtocolServers"`
+	} `json:"experimental"` // Continue config.json
+}
+
+// parseMCPClientConfig extracts the MCP servers from an uploaded client config. The format is
+// detected from the content when empty. Environment values and credentials in args are redacted.
+func parseMCPClientConfig(content []byte, format MCPConfigFormat) ([]DetectedMCPServer, MCPConfigFormat, error) {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, "", fmt.Errorf("invalid config: config content is required")
+	}
+	if len(content) > MaxMCPConfigSize {
+		return nil, "", fmt.Errorf("invalid config: config exceeds %d bytes", MaxMCPConfigSize)
+	}
+
+	// VS Code and Cursor accept JSON with comments and trailing commas
+	var config mcpConfigFile
+	if err := json.Unmarshal(stripJSONComments(content), &config); err != nil {
+		return nil, "", fmt.Errorf("invalid config: only JSON configs are supported: %w", err)
+	}
+
+	if format == "" {
+		format = detectMCPConfigFormat(&config)
+	}
+
+	var servers map[string]mcpConfigServer
+	switch format {
+	case MCPConfigClaudeDesktop, MCPConfigCursor, MCPConfigWindsurf:
+		if len(config.MCPServers) > 0 && json.Unmarshal(config.MCPServers, &servers) != nil {
+			return nil, "", fmt.Errorf("invalid config: mcpServers must be an object")
+		}
+	case MCPConfigVSCode:
+		servers = config.Servers
+		if servers == nil && config.MCP != nil {
+			servers = config.MCP.Servers
+		}
+	case MCPConfigContinue:
+		var list []mcpConfigServer
+		if len(config.MCPServers) > 0 && json.Unmarshal(config.MCPServers, &list) != nil {
+			return nil, "", fmt.Errorf("invalid config: mcpServers must be an array")
+		}
+		if config.Experimental != nil {
+			list = append(list, config.Experimental.ModelContextProtocolServers...)
+		}
+		servers = make(map[string]mcpConfigServer, len(list))
+		for i, server := range list {
+			if server.Transport != nil {
+				name := server.Name
+				server = *server.Transport
+				server.Name = name
+			}
+			if server.Name == "" {
+				server.Name = fmt.Sprintf("mcp-server-%d", i+1)
+			}
+			servers[server.Name] = server
+		}
+	default:
+		return nil, "", fmt.Errorf("invalid config: unsupported format %q", format)
+	}
+
+	detected := make([]DetectedMCPServer, 0, len(servers))
+	for name, server := range servers {
+		url := server.URL
+		if url == "" {
+			url = server.ServerURL
+		}
+		if server.Command == "" && url == "" {
+			continue
+		}
+
+		transport := server.Type
+		switch {
+		case server.Command != "":
+			transport = "stdio"
+		case transport == "" || transport == "stdio":
+			transport = "sse"
+		}
+
+		var env map[string]string
+		var envNames []string
+		if len(server.Env) > 0 {
+			env = make(map[string]string, len(server.Env))
+			for key := range server.Env {
+				env[key] = redactedEnvValue
+				envNames = append(envNames, key)
+			}
+		}
+
+		entry := DetectedMCPServer{
+			Name:       name,
+			Command:    server.Command,
+			Args:       RedactMCPArgs(server.Args),
+			Env:        env,
+			URL:        url,
+			Transport:  transport,
+			Confidence: 100.0, // High confidence for config file detection
+			Source:     string(format) + "_config",
+		}
+		if transport == string(domain.MCPTransportStdio) {
+			entry.Package = ParseMCPPackageRef(server.Command, server.Args)
+			entry.IdentityHash = ComputeMCPStdioIdentity(server.Command, server.Args, envNames, entry.Package)
+		}
+
+		detected = append(detected, entry)
+	}
+
+	sort.Slice(detected, func(i, j int) bool { return detected[i].Name < detected[j].Name })
+
+	return detected, format, nil
+}
+
+// detectMCPConfigFormat guesses the client from the config's shape. Claude Desktop and Cursor
+// share a format, so Cursor configs are reported as Claude Desktop unless the format is given.
+func detectMCPConfigFormat(config *mcpConfigFile) MCPConfigFormat {
+	switch {
+	case config.Servers != nil || (config.MCP != nil && config.MCP.Servers != nil):
+		return MCPConfigVSCode
+	case config.Experimental != nil && config.Experimental.ModelContextProtocolServers != nil:
+		return MCPConfigContinue
+	case bytes.HasPrefix(bytes.TrimSpace(config.MCPServers), []byte("[")):
+		return MCPConfigContinue
+	case bytes.Contains(config.MCPServers, []byte(`"serverUrl"`)):
+		return MCPConfigWindsurf
+	default:
+		return MCPConfigClaudeDesktop
+	}
+}
+
+// stripJSONComments removes // and /* */ comments and trailing commas outside of strings (JSONC)
+func stripJSONComments(content []byte) []byte {
+	withoutComments := scanJSONOutsideStrings(content, func(content []byte, i int, out *bytes.Buffer) int {
+		switch {
+		case content[i] == '/' && i+1 < len(content) && content[i+1] == '/':
+			end := bytes.IndexByte(content[i:], '\n')
+			if end < 0 {
+				return len(content)
+			}
+			return i + end
+		case content[i] == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := bytes.Index(content[i+2:], []byte("*/"))
+			if end < 0 {
+				return len(content)
+			}
+			out.WriteByte(' ')
+			return i + end + 4
+		}
+		return -1
+	})
+
+	return scanJSONOutsideStrings(withoutComments, func(content []byte, i int, out *bytes.Buffer) int {
+		if content[i] != ',' {
+			return -1
+		}
+		next := i + 1
+		for next < len(content) && strings.IndexByte(" \t\r\n", content[next]) >= 0 {
+			next++
+		}
+		if next < len(content) && (content[next] == '}' || content[next] == ']') {
+			return i + 1 // Drop the trailing comma
+		}
+		return -1
+	})
+}
+
+// scanJSONOutsideStrings copies content, letting replace handle bytes outside string literals.
+// replace returns the index to continue from, or -1 to copy the byte unchanged.
+func scanJSONOutsideStrings(content []byte, replace func(content []byte, i int, out *bytes.Buffer) int) []byte {
+	var out bytes.Buffer
+	out.Grow(len(content))
+
+	inString, escaped := false, false
+	for i := 0; i < len(content); {
+		ch := content[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+		} else if ch == '"' {
+			inString = true
+		} else if next := replace(content, i, &out); next >= 0 {
+			i = next
+			continue
+		}
+		out.WriteByte(ch)
+		i++
+	}
+
+	return out.Bytes()
+}
@@ -0,0 +1,406 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+const (
+	// MCPHealthFailureThreshold is the number of consecutive failed checks before a verified server is downgraded
+	MCPHealthFailureThreshold = 3
+
+	// MCPHealthDowngradePenalty is the MCP server trust score penalty applied when a server is downgraded
+	MCPHealthDowngradePenalty = 10.0
+
+	// MCPHealthUptimeWindow is the window used for uptime reporting and trust scoring
+	MCPHealthUptimeWindow = 30 * 24 * time.Hour
+)
+
+// MCPServerChallenger performs the Ed25519 challenge-response against an MCP server
+type MCPServerChallenger interface {
+	ChallengeServer(ctx context.Context, server *domain.MCPServer) error
+}
+
+// MCPHealthMonitorService periodically re-verifies MCP servers.
+//
+// Each run probes the server URL for reachability and, when the server has a public key,
+// repeats the Ed25519 challenge-response that MCPService.VerifyMCPServer performs on demand.
+// Every run is stored as a health check and recorded as a VerificationEvent initiated by the
+// scheduler. After MCPHealthFailureThreshold consecutive failures a verified server is
+// downgraded to pending; it is restored automatically once a check succeeds again.
+type MCPHealthMonitorService struct {
+	challenger            MCPServerChallenger
+	mcpRepo               domain.MCPServerRepository
+	healthRepo            domain.MCPServerHealthRepository
+	verificationEventRepo domain.VerificationEventRepository
+	alertRepo             domain.AlertRepository
+	httpClient            *http.Client
+	failureThreshold      int
+
+	runMu sync.Mutex // Prevents overlapping runs
+}
+
+// NewMCPHealthMonitorService creates a new MCP health monitor
+func NewMCPHealthMonitorService(
+	challenger MCPServerChallenger,
+	mcpRepo domain.MCPServerRepository,
+	healthRepo domain.MCPServerHealthRepository,
+	verificationEventRepo domain.VerificationEventRepository,
+	alertRepo domain.AlertRepository,
+) *MCPHealthMonitorService {
+	return &MCPHealthMonitorService{
+		challenger:            challenger,
+		mcpRepo:               mcpRepo,
+		healthRepo:            healthRepo,
+		verificationEventRepo: verificationEventRepo,
+		alertRepo:             alertRepo,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second, // Health probes must not hold up the whole run
+		},
+		failureThreshold: MCPHealthFailureThreshold,
+	}
+}
+
+// MCPHealthRunSummary describes the outcome of one monitor run
+type MCPHealthRunSummary struct {
+	Checked    int `json:"checked"`
+	Healthy    int `json:"healthy"`
+	Failed     int `json:"failed"`
+	Downgraded int `json:"downgraded"`
+	Restored   int `json:"restored"`
+}
+
+// MCPHealthCheckResult describes the outcome of checking one server
+type MCPHealthCheckResult struct {
+	Check      *domain.MCPServerHealthCheck `json:"check"`
+	Health     *domain.MCPServerHealth      `json:"health"`
+	Downgraded bool                         `json:"downgraded"`
+	Restored   bool                         `json:"restored"`
+}
+
+// RunScheduledChecks is the scheduled job entry point: it runs all checks and logs a summary
+func (s *MCPHealthMonitorService) RunScheduledChecks(ctx context.Context) error {
+	summary, err := s.RunChecks(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ MCP health monitor: %d checked, %d healthy, %d failed, %d downgraded, %d restored\n",
+		summary.Checked, summary.Healthy, summary.Failed, summary.Downgraded, summary.Restored)
+
+	return nil
+}
+
+// RunChecks checks every verified server and every server the monitor previously downgraded
+func (s *MCPHealthMonitorService) RunChecks(ctx context.Context) (*MCPHealthRunSummary, error) {
+	if !s.runMu.TryLock() {
+		return nil, fmt.Errorf("a health monitor run is already in progress")
+	}
+	defer s.runMu.Unlock()
+
+	serverIDs, err := s.healthRepo.ListServerIDsDueForCheck()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &MCPHealthRunSummary{}
+	for _, serverID := range serverIDs {
+		if ctx.Err() != nil {
+			return summary, ctx.Err()
+		}
+
+		result, err := s.CheckServer(ctx, serverID)
+		if err != nil {
+			fmt.Printf("⚠️  Health check failed for MCP server %s: %v\n", serverID, err)
+			continue
+		}
+
+		summary.Checked++
+		if result.Check.Success {
+			summary.Healthy++
+		} else {
+			summary.Failed++
+		}
+		if result.Downgraded {
+			summary.Downgraded++
+		}
+		if result.Restored {
+			summary.Restored++
+		}
+	}
+
+	return summary, nil
+}
+
+// CheckServer probes and re-challenges a single server and updates its health state
+func (s *MCPHealthMonitorService) CheckServer(ctx context.Context, serverID uuid.UUID) (*MCPHealthCheckResult, error) {
+	startTime := time.Now()
+
+	server, err := s.mcpRepo.GetByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP server: %w", err)
+	}
+
+	check := &domain.MCPServerHealthCheck{
+		ID:             uuid.New(),
+		MCPServerID:    server.ID,
+		OrganizationID: server.OrganizationID,
+	}
+
+	// 1. Reachability probe
+	check.Reachable, check.HTTPStatus, err = s.probe(ctx, server.URL)
+	if err != nil {
+		check.ErrorReason = err.Error()
+	}
+
+	// 2. Cryptographic re-verification
+	if check.Reachable && server.PublicKey != "" && s.challenger != nil {
+		valid := true
+		if err := s.challenger.ChallengeServer(ctx, server); err != nil {
+			valid = false
+			check.ErrorReason = err.Error()
+		}
+		check.SignatureValid = &valid
+	}
+
+	check.Success = check.Reachable && (check.SignatureValid == nil || *check.SignatureValid)
+	check.LatencyMs = int(time.Since(startTime).Milliseconds())
+	check.CheckedAt = time.Now().UTC()
+
+	if err := s.healthRepo.CreateCheck(check); err != nil {
+		return nil, err
+	}
+
+	// 3. Update health state and server status
+	health, err := s.healthRepo.GetHealth(server.ID)
+	if err != nil {
+		return nil, err
+	}
+	if health == nil {
+		health = &domain.MCPServerHealth{MCPServerID: server.ID, HealthStatus: domain.MCPServerHealthUnknown}
+	}
+
+	result := &MCPHealthCheckResult{Check: check, Health: health}
+	checkedAt := check.CheckedAt
+	health.LastCheckedAt = &checkedAt
+	serverChanged := false
+
+	if check.Success {
+		health.HealthStatus = domain.MCPServerHealthHealthy
+		health.ConsecutiveFailures = 0
+		health.LastSuccessAt = &checkedAt
+		health.LastFailureReason = ""
+
+		if check.SignatureValid != nil {
+			server.LastVerifiedAt = &checkedAt
+			serverChanged = true
+		}
+
+		// Restore servers the monitor downgraded (servers suspended for other reasons stay suspended)
+		if health.DowngradedAt != nil {
+			if server.Status == domain.MCPServerStatusPending {
+				server.Status = domain.MCPServerStatusVerified
+				server.IsVerified = true
+				serverChanged = true
+				result.Restored = true
+			}
+			health.DowngradedAt = nil
+		}
+	} else {
+		health.ConsecutiveFailures++
+		health.LastFailureReason = check.ErrorReason
+		if health.DowngradedAt == nil {
+			health.HealthStatus = domain.MCPServerHealthDegraded
+		}
+
+		if health.ConsecutiveFailures >= s.failureThreshold && server.Status == domain.MCPServerStatusVerified {
+			server.Status = domain.MCPServerStatusPending
+			server.IsVerified = false
+			server.TrustScore -= MCPHealthDowngradePenalty
+			if server.TrustScore < MinimumTrustScore {
+				server.TrustScore = MinimumTrustScore
+			}
+			serverChanged = true
+
+			health.HealthStatus = domain.MCPServerHealthUnhealthy
+			health.DowngradedAt = &checkedAt
+			result.Downgraded = true
+		}
+	}
+
+	if serverChanged {
+		if err := s.mcpRepo.Update(server); err != nil {
+			return nil, fmt.Errorf("failed to update MCP server: %w", err)
+		}
+	}
+
+	if err := s.healthRepo.UpsertHealth(health); err != nil {
+		return nil, err
+	}
+
+	if result.Downgraded {
+		s.createDowngradeAlert(server, health)
+	}
+
+	s.recordVerificationEvent(server, check, health, startTime)
+
+	return result, nil
+}
+
+// GetHealth returns the health state, recent checks and uptime of a server
+func (s *MCPHealthMonitorService) GetHealth(ctx context.Context, serverID uuid.UUID, limit int) (*domain.MCPServerHealth, []*domain.MCPServerHealthCheck, map[string]*domain.MCPServerUptime, error) {
+	health, err := s.healthRepo.GetHealth(serverID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if health == nil {
+		health = &domain.MCPServerHealth{MCPServerID: serverID, HealthStatus: domain.MCPServerHealthUnknown}
+	}
+
+	checks, err := s.healthRepo.GetChecksByServer(serverID, limit, 0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	windows := map[string]time.Duration{
+		"24h": 24 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"30d": MCPHealthUptimeWindow,
+	}
+	uptime := map[string]*domain.MCPServerUptime{}
+	for label, window := range windows {
+		u, err := s.healthRepo.GetUptime(serverID, now.Add(-window))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		uptime[label] = u
+	}
+
+	return health, checks, uptime, nil
+}
+
+// probe issues a GET against the server URL; any response below 500 counts as reachable
+func (s *MCPHealthMonitorService) probe(ctx context.Context, url string) (bool, *int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "AIM/1.0 (Agent Identity Management) health-monitor")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return false, nil, fmt.Errorf("server unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	if status >= http.StatusInternalServerError {
+		return false, &status, fmt.Errorf("server returned status %d", status)
+	}
+
+	return true, &status, nil
+}
+
+func (s *MCPHealthMonitorService) recordVerificationEvent(
+	server *domain.MCPServer,
+	check *domain.MCPServerHealthCheck,
+	health *domain.MCPServerHealth,
+	startTime time.Time,
+) {
+	if s.verificationEventRepo == nil {
+		return
+	}
+
+	status := domain.VerificationEventStatusSuccess
+	result := domain.VerificationResultVerified
+	confidence := 0.95
+	if !check.Success {
+		status = domain.VerificationEventStatusFailed
+		result = domain.VerificationResultDenied
+		confidence = 0.0
+		if !check.Reachable {
+			status = domain.VerificationEventStatusTimeout
+		}
+	}
+
+	initiatorName := "mcp-health-monitor"
+	completedAt := check.CheckedAt
+	serverID := server.ID
+	serverName := server.Name
+
+	event := &domain.VerificationEvent{
+		ID:               uuid.New(),
+		OrganizationID:   server.OrganizationID,
+		MCPServerID:      &serverID,
+		MCPServerName:    &serverName,
+		Protocol:         domain.VerificationProtocolMCP,
+		VerificationType: domain.VerificationTypeIdentity,
+		Status:           status,
+		Result:           &result,
+		Confidence:       confidence,
+		TrustScore:       server.TrustScore,
+		DurationMs:       check.LatencyMs,
+		InitiatorType:    domain.InitiatorTypeScheduler,
+		InitiatorName:    &initiatorName,
+		StartedAt:        startTime,
+		CompletedAt:      &completedAt,
+		CreatedAt:        time.Now(),
+		Metadata: map[string]interface{}{
+			"health_check_id":      check.ID.String(),
+			"reachable":            check.Reachable,
+			"signature_checked":    check.SignatureValid != nil,
+			"consecutive_failures": health.ConsecutiveFailures,
+			"health_status":        health.HealthStatus,
+		},
+	}
+	if check.ErrorReason != "" {
+		reason := check.ErrorReason
+		event.ErrorReason = &reason
+	}
+
+	if err := s.verificationEventRepo.Create(event); err != nil {
+		fmt.Printf("⚠️  Failed to create verification event for health check: %v\n", err)
+	}
+}
+
+func (s *MCPHealthMonitorService) createDowngradeAlert(server *domain.MCPServer, health *domain.MCPServerHealth) {
+	if s.alertRepo == nil {
+		return
+	}
+
+	message := fmt.Sprintf("MCP server '%s' failed %d consecutive scheduled re-verifications and has been downgraded from verified to pending.",
+		server.Name, health.ConsecutiveFailures)
+	message += fmt.Sprintf("\n\n**Last Error:** %s\n", health.LastFailureReason)
+	if health.LastSuccessAt != nil {
+		message += fmt.Sprintf("**Last Successful Check:** %s\n", health.LastSuccessAt.Format(time.RFC3339))
+	}
+	message += fmt.Sprintf("**Trust Score:** %.2f\n", server.TrustScore)
+	message += "\n**Recommended Actions:**\n"
+	message += "1. Check that the MCP server is running and reachable\n"
+	message += "2. Confirm the server still holds the private key for its registered public key\n"
+	message += "3. The server is restored automatically after the next successful check\n"
+
+	alert := &domain.Alert{
+		ID:             uuid.New(),
+		OrganizationID: server.OrganizationID,
+		AlertType:      domain.AlertMCPServerUnhealthy,
+		Severity:       domain.AlertSeverityHigh,
+		Title:          fmt.Sprintf("MCP Server Failing Re-verification: %s", server.Name),
+		Description:    message,
+		ResourceType:   "mcp_server",
+		ResourceID:     server.ID,
+		IsAcknowledged: false,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := s.alertRepo.Create(alert); err != nil {
+		fmt.Printf("⚠️  Failed to create health alert for MCP server %s: %v\n", server.Name, err)
+	}
+}
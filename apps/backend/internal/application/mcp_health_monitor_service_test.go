@@ -0,0 +1,295 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMCPServerHealthRepository keeps health checks and state in memory
+type fakeMCPServerHealthRepository struct {
+	checks []*domain.MCPServerHealthCheck
+	health map[uuid.UUID]*domain.MCPServerHealth
+}
+
+func newFakeMCPServerHealthRepository() *fakeMCPServerHealthRepository {
+	return &fakeMCPServerHealthRepository{health: map[uuid.UUID]*domain.MCPServerHealth{}}
+}
+
+func (r *fakeMCPServerHealthRepository) CreateCheck(check *domain.MCPServerHealthCheck) error {
+	r.checks = append(r.checks, check)
+	return nil
+}
+
+func (r *fakeMCPServerHealthRepository) GetChecksByServer(serverID uuid.UUID, limit, offset int) ([]*domain.MCPServerHealthCheck, error) {
+	return r.checks, nil
+}
+
+func (r *fakeMCPServerHealthRepository) GetHealth(serverID uuid.UUID) (*domain.MCPServerHealth, error) {
+	if health, ok := r.health[serverID]; ok {
+		copied := *health
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeMCPServerHealthRepository) UpsertHealth(health *domain.MCPServerHealth) error {
+	copied := *health
+	r.health[health.MCPServerID] = &copied
+	return nil
+}
+
+func (r *fakeMCPServerHealthRepository) GetUptime(serverID uuid.UUID, since time.Time) (*domain.MCPServerUptime, error) {
+	uptime := &domain.MCPServerUptime{Since: since}
+	for _, check := range r.checks {
+		if check.MCPServerID == serverID && !check.CheckedAt.Before(since) {
+			uptime.TotalChecks++
+			if check.Success {
+				uptime.SuccessfulChecks++
+			}
+		}
+	}
+	if uptime.TotalChecks > 0 {
+		uptime.Uptime = float64(uptime.SuccessfulChecks) / float64(uptime.TotalChecks)
+	}
+	return uptime, nil
+}
+
+func (r *fakeMCPServerHealthRepository) GetUptimeForServerRefs(orgID uuid.UUID, refs []string, since time.Time) (*domain.MCPServerUptime, error) {
+	return &domain.MCPServerUptime{Since: since}, nil
+}
+
+func (r *fakeMCPServerHealthRepository) ListServerIDsDueForCheck() ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+// MockMCPChallenger is a mock of the MCP server challenge-response check
+type MockMCPChallenger struct {
+	mock.Mock
+}
+
+func (m *MockMCPChallenger) ChallengeServer(ctx context.Context, server *domain.MCPServer) error {
+	args := m.Called(ctx, server)
+	return args.Error(0)
+}
+
+// createTestHealthCheckedServer creates a verified MCP server reachable at url
+func createTestHealthCheckedServer(url string) *domain.MCPServer {
+	return &domain.MCPServer{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Name:           "filesystem",
+		URL:            url,
+		PublicKey:      "test-public-key",
+		Status:         domain.MCPServerStatusVerified,
+		IsVerified:     true,
+		TrustScore:     75.0,
+	}
+}
+
+func createTestHealthMonitorService(server *domain.MCPServer, challenger *MockMCPChallenger) (*MCPHealthMonitorService, *MockToolSurfaceMCPServerRepository, *fakeMCPServerHealthRepository, *MockVerificationEventRepository, *MockToolSurfaceAlertRepository) {
+	mcpRepo := new(MockToolSurfaceMCPServerRepository)
+	mcpRepo.On("GetByID", server.ID).Return(server, nil)
+	healthRepo := newFakeMCPServerHealthRepository()
+	eventRepo := new(MockVerificationEventRepository)
+	eventRepo.On("Create", mock.Anything).Return(nil)
+	alertRepo := new(MockToolSurfaceAlertRepository)
+
+	service := NewMCPHealthMonitorService(challenger, mcpRepo, healthRepo, eventRepo, alertRepo)
+	return service, mcpRepo, healthRepo, eventRepo, alertRepo
+}
+
+// createdVerificationEvents returns the verification events passed to Create, oldest first
+func createdVerificationEvents(eventRepo *MockVerificationEventRepository) []*domain.VerificationEvent {
+	events := []*domain.VerificationEvent{}
+	for _, call := range eventRepo.Calls {
+		if call.Method == "Create" {
+			events = append(events, call.Arguments.Get(0).(*domain.VerificationEvent))
+		}
+	}
+	return events
+}
+
+func TestMCPHealthMonitor_SuccessfulCheckRefreshesLastVerified(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	server := createTestHealthCheckedServer(upstream.URL)
+	challenger := new(MockMCPChallenger)
+	service, mcpRepo, _, eventRepo, _ := createTestHealthMonitorService(server, challenger)
+	challenger.On("ChallengeServer", mock.Anything, server).Return(nil)
+	mcpRepo.On("Update", server).Return(nil)
+
+	result, err := service.CheckServer(context.Background(), server.ID)
+	require.NoError(t, err)
+
+	assert.True(t, result.Check.Success)
+	assert.True(t, result.Check.Reachable)
+	require.NotNil(t, result.Check.SignatureValid)
+	assert.True(t, *result.Check.SignatureValid)
+	assert.Equal(t, domain.MCPServerHealthHealthy, result.Health.HealthStatus)
+	assert.NotNil(t, server.LastVerifiedAt)
+	challenger.AssertNumberOfCalls(t, "ChallengeServer", 1)
+
+	events := createdVerificationEvents(eventRepo)
+	require.Len(t, events, 1)
+	event := events[0]
+	assert.Equal(t, domain.InitiatorTypeScheduler, event.InitiatorType)
+	assert.Equal(t, domain.VerificationEventStatusSuccess, event.Status)
+	assert.Equal(t, server.ID, *event.MCPServerID)
+}
+
+func TestMCPHealthMonitor_DowngradesAfterConsecutiveFailures(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	server := createTestHealthCheckedServer(upstream.URL)
+	challenger := new(MockMCPChallenger)
+	service, mcpRepo, _, eventRepo, alertRepo := createTestHealthMonitorService(server, challenger)
+	challenger.On("ChallengeServer", mock.Anything, server).Return(fmt.Errorf("signature verification failed: invalid signature"))
+	mcpRepo.On("Update", server).Return(nil)
+	alertRepo.On("Create", mock.MatchedBy(func(alert *domain.Alert) bool {
+		return alert.AlertType == domain.AlertMCPServerUnhealthy && alert.ResourceID == server.ID
+	})).Return(nil).Once()
+
+	for i := 1; i < MCPHealthFailureThreshold; i++ {
+		result, err := service.CheckServer(context.Background(), server.ID)
+		require.NoError(t, err)
+		assert.False(t, result.Downgraded)
+		assert.Equal(t, domain.MCPServerHealthDegraded, result.Health.HealthStatus)
+		assert.Equal(t, domain.MCPServerStatusVerified, server.Status)
+	}
+
+	result, err := service.CheckServer(context.Background(), server.ID)
+	require.NoError(t, err)
+
+	assert.True(t, result.Downgraded)
+	assert.Equal(t, domain.MCPServerHealthUnhealthy, result.Health.HealthStatus)
+	assert.Equal(t, MCPHealthFailureThreshold, result.Health.ConsecutiveFailures)
+	assert.Equal(t, domain.MCPServerStatusPending, server.Status)
+	assert.False(t, server.IsVerified)
+	assert.Equal(t, 75.0-MCPHealthDowngradePenalty, server.TrustScore)
+	events := createdVerificationEvents(eventRepo)
+	assert.Equal(t, domain.VerificationEventStatusFailed, events[len(events)-1].Status)
+	alertRepo.AssertExpectations(t)
+}
+
+func TestMCPHealthMonitor_RestoresDowngradedServer(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	server := createTestHealthCheckedServer(upstream.URL)
+	challenger := new(MockMCPChallenger)
+	service, mcpRepo, healthRepo, _, _ := createTestHealthMonitorService(server, challenger)
+	challenger.On("ChallengeServer", mock.Anything, server).Return(nil)
+	downgradedAt := time.Now().Add(-time.Hour)
+	server.Status = domain.MCPServerStatusPending
+	server.IsVerified = false
+	healthRepo.health[server.ID] = &domain.MCPServerHealth{
+		MCPServerID:         server.ID,
+		HealthStatus:        domain.MCPServerHealthUnhealthy,
+		ConsecutiveFailures: 5,
+		DowngradedAt:        &downgradedAt,
+	}
+	mcpRepo.On("Update", server).Return(nil)
+
+	result, err := service.CheckServer(context.Background(), server.ID)
+	require.NoError(t, err)
+
+	assert.True(t, result.Restored)
+	assert.Nil(t, result.Health.DowngradedAt)
+	assert.Equal(t, 0, result.Health.ConsecutiveFailures)
+	assert.Equal(t, domain.MCPServerStatusVerified, server.Status)
+	assert.True(t, server.IsVerified)
+}
+
+func TestMCPHealthMonitor_UnreachableServerIsNotChallenged(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	server := createTestHealthCheckedServer(upstream.URL)
+	challenger := new(MockMCPChallenger)
+	service, mcpRepo, _, eventRepo, _ := createTestHealthMonitorService(server, challenger)
+
+	result, err := service.CheckServer(context.Background(), server.ID)
+	require.NoError(t, err)
+
+	assert.False(t, result.Check.Success)
+	assert.False(t, result.Check.Reachable)
+	assert.Nil(t, result.Check.SignatureValid)
+	assert.Equal(t, http.StatusServiceUnavailable, *result.Check.HTTPStatus)
+	challenger.AssertNotCalled(t, "ChallengeServer", mock.Anything, mock.Anything)
+	assert.Equal(t, domain.VerificationEventStatusTimeout, createdVerificationEvents(eventRepo)[0].Status)
+	mcpRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestMCPHealthMonitor_GetHealthReportsUptime(t *testing.T) {
+	server := createTestHealthCheckedServer("http://unused")
+	challenger := new(MockMCPChallenger)
+	service, _, healthRepo, _, _ := createTestHealthMonitorService(server, challenger)
+	now := time.Now().UTC()
+	for i, success := range []bool{true, true, false, true} {
+		healthRepo.checks = append(healthRepo.checks, &domain.MCPServerHealthCheck{
+			ID:          uuid.New(),
+			MCPServerID: server.ID,
+			Success:     success,
+			CheckedAt:   now.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+
+	health, checks, uptime, err := service.GetHealth(context.Background(), server.ID, 20)
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.MCPServerHealthUnknown, health.HealthStatus)
+	assert.Len(t, checks, 4)
+	assert.Equal(t, 4, uptime["24h"].TotalChecks)
+	assert.InDelta(t, 0.75, uptime["30d"].Uptime, 0.0001)
+}
+
+func TestMCPService_ConcurrentChallengesDoNotOverwriteEachOther(t *testing.T) {
+	s := &MCPService{challenges: make(map[string]ChallengeData)}
+	serverID := uuid.New()
+
+	// A manual verification and a scheduled check challenge the same server at once
+	s.storeChallenge(serverID, "manual-nonce")
+	s.storeChallenge(serverID, "scheduled-nonce")
+
+	scheduled, err := s.takeChallenge(serverID, "scheduled-nonce")
+	require.NoError(t, err)
+	assert.Equal(t, "scheduled-nonce", scheduled.Challenge)
+	manual, err := s.takeChallenge(serverID, "manual-nonce")
+	require.NoError(t, err)
+	assert.Equal(t, "manual-nonce", manual.Challenge)
+
+	// Challenges are single use and bound to their server
+	_, err = s.takeChallenge(serverID, "manual-nonce")
+	assert.Error(t, err)
+	s.storeChallenge(serverID, "other-nonce")
+	_, err = s.takeChallenge(uuid.New(), "other-nonce")
+	assert.Error(t, err)
+	_, err = s.takeChallenge(serverID, "other-nonce")
+	assert.NoError(t, err)
+
+	// Challenges that were never answered are dropped once expired
+	s.challenges["abandoned-nonce"] = ChallengeData{Challenge: "abandoned-nonce", ServerID: serverID, ExpiresAt: time.Now().Add(-time.Second)}
+	s.storeChallenge(serverID, "fresh-nonce")
+	assert.NotContains(t, s.challenges, "abandoned-nonce")
+	assert.Contains(t, s.challenges, "fresh-nonce")
+}
@@ -0,0 +1,544 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// MaxMetadataFindingsPenalty caps the trust score penalty applied for one discovery
+const MaxMetadataFindingsPenalty = 50.0
+
+// MCPMetadataScanner is a static analyzer for MCP tool metadata.
+//
+// MCP tool names, descriptions and schemas are injected verbatim into the LLM context
+// of every agent that connects to the server, which makes them a prompt-injection
+// ("tool poisoning") vector. The scanner runs at discovery time and flags:
+//   - hidden instructions aimed at the model rather than the user
+//   - Unicode tag, zero-width and bidi control characters that hide text from humans
+//   - references to other tools (shadowing / cross-tool manipulation)
+//   - phrasing that asks for credentials or secrets to be read or sent
+//   - schemas that accept arbitrary input
+type MCPMetadataScanner struct {
+	instructionRules []metadataPatternRule
+	exfilRules       []metadataPatternRule
+}
+
+type metadataPatternRule struct {
+	id       string
+	pattern  *regexp.Regexp
+	severity domain.AlertSeverity
+	message  string
+}
+
+// NewMCPMetadataScanner creates a scanner with the built-in rule set
+func NewMCPMetadataScanner() *MCPMetadataScanner {
+	return &MCPMetadataScanner{
+		instructionRules: []metadataPatternRule{
+			{"hidden_instructions.ignore_previous", regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\b.{0,30}\b(previous|prior|above|earlier|all|system)\b.{0,20}\b(instructions?|prompts?|rules?|guidelines?)`), domain.AlertSeverityCritical, "Attempts to override the model's prior instructions"},
+			{"hidden_instructions.conceal_from_user", regexp.MustCompile(`(?i)\b(do not|don't|never|without)\b.{0,30}\b(tell|inform|mention|notify|alert|reveal|show)(ing)?\b.{0,20}\b(the )?(user|human|operator)`), domain.AlertSeverityCritical, "Instructs the model to hide behaviour from the user"},
+			{"hidden_instructions.tagged_block", regexp.MustCompile(`(?i)<\s*/?\s*(important|system|instructions?|secret|hidden|admin|assistant)\s*>`), domain.AlertSeverityHigh, "Contains pseudo-markup commonly used to smuggle instructions to the model"},
+			{"hidden_instructions.html_comment", regexp.MustCompile(`<!--[\s\S]*?-->`), domain.AlertSeverityHigh, "Contains an HTML comment that is invisible in rendered descriptions"},
+			{"hidden_instructions.precondition", regexp.MustCompile(`(?i)\b(before|prior to|after)\b.{0,20}\b(using|calling|invoking|running)\b.{0,30}\b(you must|always|first)\b`), domain.AlertSeverityHigh, "Imposes hidden preconditions on how the model uses tools"},
+			{"hidden_instructions.role_override", regexp.MustCompile(`(?i)\b(you are now|act as|pretend to be|your new (role|task|instructions?))\b`), domain.AlertSeverityHigh, "Attempts to change the model's role"},
+			{"hidden_instructions.system_prompt", regexp.MustCompile(`(?i)\b(system prompt|developer message|hidden prompt)\b`), domain.AlertSeverityWarning, "References the system prompt"},
+		},
+		exfilRules: []metadataPatternRule{
+			{"credential_exfiltration.secret_files", regexp.MustCompile(`(?i)(~/\.ssh|id_rsa|id_ed25519|\.aws/credentials|\.npmrc|\.pypirc|\.netrc|\.git-credentials|\.env\b|/etc/passwd|/etc/shadow|mcp\.json|claude_desktop_config)`), domain.AlertSeverityCritical, "References files that commonly hold credentials"},
+			{"credential_exfiltration.send_secrets", regexp.MustCompile(`(?i)\b(send|include|pass|post|upload|forward|attach|append|embed|copy)\b.{0,40}\b(api[_ -]?keys?|tokens?|passwords?|secrets?|credentials?|private[_ -]?keys?|session cookies?|conversation history|chat history)\b`), domain.AlertSeverityCritical, "Asks for secrets or conversation data to be sent or included"},
+			{"credential_exfiltration.read_secrets", regexp.MustCompile(`(?i)\b(read|cat|open|load|retrieve|collect|extract)\b.{0,30}\b(api[_ -]?keys?|passwords?|secrets?|credentials?|private[_ -]?keys?|environment variables)\b`), domain.AlertSeverityHigh, "Asks for secrets to be read"},
+			{"credential_exfiltration.external_url", regexp.MustCompile(`(?i)\b(send|post|upload|forward|report)\b.{0,40}https?://`), domain.AlertSeverityHigh, "Directs data to an external URL"},
+		},
+	}
+}
+
+// ScanCapabilities scans every capability of one server and stores the findings on each capability
+func (s *MCPMetadataScanner) ScanCapabilities(capabilities []*domain.MCPServerCapability) {
+	toolNames := make([]string, 0, len(capabilities))
+	for _, capability := range capabilities {
+		toolNames = append(toolNames, capability.Name)
+	}
+
+	scannedAt := time.Now().UTC()
+	for _, capability := range capabilities {
+		capability.SecurityFindings = s.ScanCapability(capability, toolNames)
+		capability.RiskLevel = RiskLevelForFindings(capability.SecurityFindings)
+		capability.ScannedAt = &scannedAt
+	}
+}
+
+// ScanCapability scans a single capability; siblingNames are the other tool names exposed by the server
+func (s *MCPMetadataScanner) ScanCapability(capability *domain.MCPServerCapability, siblingNames []string) []domain.MCPCapabilityFinding {
+	findings := []domain.MCPCapabilityFinding{}
+
+	schemaText := string(capability.CapabilitySchema)
+	fields := []struct {
+		name string
+		text string
+	}{
+		{"name", capability.Name},
+		{"description", capability.Description},
+		{"schema", schemaDescriptions(capability.CapabilitySchema)},
+	}
+
+	for _, field := range fields {
+		if field.text == "" {
+			continue
+		}
+
+		findings = append(findings, scanInvisibleCharacters(field.name, field.text)...)
+
+		// Invisible characters are stripped before pattern matching so they can't split keywords
+		visible := stripInvisibleCharacters(field.text)
+		findings = append(findings, matchMetadataRules(s.instructionRules, domain.MCPFindingHiddenInstructions, field.name, visible)...)
+		findings = append(findings, matchMetadataRules(s.exfilRules, domain.MCPFindingCredentialExfiltration, field.name, visible)...)
+
+		if field.name != "name" {
+			findings = append(findings, scanCrossToolReferences(capability.Name, siblingNames, field.name, visible)...)
+		}
+	}
+
+	if capability.CapabilityType == domain.MCPCapabilityTypeTool && schemaText != "" {
+		findings = append(findings, scanBroadSchema(capability.CapabilitySchema)...)
+	}
+
+	return findings
+}
+
+// RiskLevelForFindings maps the worst finding severity to a capability risk level
+func RiskLevelForFindings(findings []domain.MCPCapabilityFinding) domain.MCPCapabilityRiskLevel {
+	level := domain.MCPCapabilityRiskNone
+	rank := map[domain.MCPCapabilityRiskLevel]int{
+		domain.MCPCapabilityRiskNone:     0,
+		domain.MCPCapabilityRiskLow:      1,
+		domain.MCPCapabilityRiskMedium:   2,
+		domain.MCPCapabilityRiskHigh:     3,
+		domain.MCPCapabilityRiskCritical: 4,
+	}
+
+	for _, finding := range findings {
+		var candidate domain.MCPCapabilityRiskLevel
+		switch finding.Severity {
+		case domain.AlertSeverityCritical:
+			candidate = domain.MCPCapabilityRiskCritical
+		case domain.AlertSeverityHigh:
+			candidate = domain.MCPCapabilityRiskHigh
+		case domain.AlertSeverityWarning:
+			candidate = domain.MCPCapabilityRiskMedium
+		default:
+			candidate = domain.MCPCapabilityRiskLow
+		}
+		if rank[candidate] > rank[level] {
+			level = candidate
+		}
+	}
+
+	return level
+}
+
+// MetadataFindingsSummary aggregates scanner findings across the capabilities of one server
+type MetadataFindingsSummary struct {
+	RiskLevel      domain.MCPCapabilityRiskLevel `json:"risk_level"`
+	TotalFindings  int                           `json:"total_findings"`
+	BySeverity     map[domain.AlertSeverity]int  `json:"by_severity"`
+	RiskyToolCount int                           `json:"risky_tool_count"` // Capabilities rated high or critical
+}
+
+// SummarizeMetadataFindings builds the per-server findings summary shown next to the capability list
+func SummarizeMetadataFindings(capabilities []*domain.MCPServerCapability) MetadataFindingsSummary {
+	summary := MetadataFindingsSummary{
+		RiskLevel: domain.MCPCapabilityRiskNone,
+		BySeverity: map[domain.AlertSeverity]int{
+			domain.AlertSeverityInfo:     0,
+			domain.AlertSeverityWarning:  0,
+			domain.AlertSeverityHigh:     0,
+			domain.AlertSeverityCritical: 0,
+		},
+	}
+
+	var all []domain.MCPCapabilityFinding
+	for _, capability := range capabilities {
+		all = append(all, capability.SecurityFindings...)
+		for _, finding := range capability.SecurityFindings {
+			summary.BySeverity[finding.Severity]++
+		}
+		if capability.RiskLevel == domain.MCPCapabilityRiskHigh || capability.RiskLevel == domain.MCPCapabilityRiskCritical {
+			summary.RiskyToolCount++
+		}
+	}
+	summary.TotalFindings = len(all)
+	summary.RiskLevel = RiskLevelForFindings(all)
+
+	return summary
+}
+
+// CapabilityFinding pairs a finding with the capability it was found on
+type CapabilityFinding struct {
+	CapabilityName string
+	CapabilityType domain.MCPCapabilityType
+	Finding        domain.MCPCapabilityFinding
+}
+
+// NewMetadataFindings returns the findings in current that were not already reported in previous.
+// Findings are matched by capability, rule and field so unchanged issues are not penalized twice.
+func NewMetadataFindings(previous, current []*domain.MCPServerCapability) []CapabilityFinding {
+	key := func(capType domain.MCPCapabilityType, capName string, finding domain.MCPCapabilityFinding) string {
+		return string(capType) + "\x00" + capName + "\x00" + finding.RuleID + "\x00" + finding.Field
+	}
+
+	known := map[string]bool{}
+	for _, capability := range previous {
+		for _, finding := range capability.SecurityFindings {
+			known[key(capability.CapabilityType, capability.Name, finding)] = true
+		}
+	}
+
+	newFindings := []CapabilityFinding{}
+	for _, capability := range current {
+		for _, finding := range capability.SecurityFindings {
+			k := key(capability.CapabilityType, capability.Name, finding)
+			if known[k] {
+				continue
+			}
+			known[k] = true
+			newFindings = append(newFindings, CapabilityFinding{
+				CapabilityName: capability.Name,
+				CapabilityType: capability.CapabilityType,
+				Finding:        finding,
+			})
+		}
+	}
+
+	return newFindings
+}
+
+// MetadataFindingsPenalty sums the trust score penalty for a set of findings, capped at MaxMetadataFindingsPenalty
+func MetadataFindingsPenalty(findings []CapabilityFinding) float64 {
+	penalty := 0.0
+	for _, f := range findings {
+		penalty += MetadataFindingPenalty(f.Finding)
+	}
+	if penalty > MaxMetadataFindingsPenalty {
+		penalty = MaxMetadataFindingsPenalty
+	}
+	return penalty
+}
+
+// MetadataFindingPenalty returns the trust score penalty for one finding
+func MetadataFindingPenalty(finding domain.MCPCapabilityFinding) float64 {
+	switch finding.Severity {
+	case domain.AlertSeverityCritical:
+		return 25.0
+	case domain.AlertSeverityHigh:
+		return 10.0
+	case domain.AlertSeverityWarning:
+		return 3.0
+	default:
+		return 0.0
+	}
+}
+
+func matchMetadataRules(rules []metadataPatternRule, category domain.MCPFindingCategory, field, text string) []domain.MCPCapabilityFinding {
+	findings := []domain.MCPCapabilityFinding{}
+	for _, rule := range rules {
+		match := rule.pattern.FindString(text)
+		if match == "" {
+			continue
+		}
+		findings = append(findings, domain.MCPCapabilityFinding{
+			RuleID:   rule.id,
+			Category: category,
+			Severity: rule.severity,
+			Field:    field,
+			Message:  rule.message,
+			Evidence: truncateEvidence(match),
+		})
+	}
+	return findings
+}
+
+// isInvisibleRune reports Unicode tag characters, zero-width characters and bidi controls
+func isInvisibleRune(r rune) (bool, string) {
+	switch {
+	case r >= 0xE0000 && r <= 0xE007F:
+		return true, "unicode_tag"
+	case r == 0x200B || r == 0x200C || r == 0x200D || r == 0x2060 || r == 0xFEFF || r == 0x180E || (r >= 0x2061 && r <= 0x2064):
+		return true, "zero_width"
+	case (r >= 0x202A && r <= 0x202E) || (r >= 0x2066 && r <= 0x2069) || r == 0x200E || r == 0x200F:
+		return true, "bidi_control"
+	}
+	return false, ""
+}
+
+func scanInvisibleCharacters(field, text string) []domain.MCPCapabilityFinding {
+	counts := map[string]int{}
+	var tagPayload strings.Builder
+
+	for _, r := range text {
+		invisible, kind := isInvisibleRune(r)
+		if !invisible {
+			continue
+		}
+		counts[kind]++
+		// Unicode tag characters mirror ASCII - decode them to show the hidden text
+		if kind == "unicode_tag" && r >= 0xE0020 && r <= 0xE007E {
+			tagPayload.WriteRune(r - 0xE0000)
+		}
+	}
+
+	findings := []domain.MCPCapabilityFinding{}
+	if counts["unicode_tag"] > 0 {
+		findings = append(findings, domain.MCPCapabilityFinding{
+			RuleID:   "invisible_characters.unicode_tag",
+			Category: domain.MCPFindingInvisibleCharacters,
+			Severity: domain.AlertSeverityCritical,
+			Field:    field,
+			Message:  fmt.Sprintf("Contains %d Unicode tag characters that encode text invisible to humans", counts["unicode_tag"]),
+			Evidence: truncateEvidence(tagPayload.String()),
+		})
+	}
+	if counts["zero_width"] > 0 {
+		findings = append(findings, domain.MCPCapabilityFinding{
+			RuleID:   "invisible_characters.zero_width",
+			Category: domain.MCPFindingInvisibleCharacters,
+			Severity: domain.AlertSeverityHigh,
+			Field:    field,
+			Message:  fmt.Sprintf("Contains %d zero-width characters", counts["zero_width"]),
+		})
+	}
+	if counts["bidi_control"] > 0 {
+		findings = append(findings, domain.MCPCapabilityFinding{
+			RuleID:   "invisible_characters.bidi_control",
+			Category: domain.MCPFindingInvisibleCharacters,
+			Severity: domain.AlertSeverityHigh,
+			Field:    field,
+			Message:  fmt.Sprintf("Contains %d bidirectional control characters that can reorder displayed text", counts["bidi_control"]),
+		})
+	}
+
+	return findings
+}
+
+func stripInvisibleCharacters(text string) string {
+	return strings.Map(func(r rune) rune {
+		if invisible, _ := isInvisibleRune(r); invisible {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+var otherToolsPattern = regexp.MustCompile(`(?i)\b(instead of|rather than|do not use|don't use|whenever you use|when using|replaces?|overrides?)\b.{0,20}\b(the )?(other|any|all)?\s*tools?\b`)
+
+// scanCrossToolReferences flags descriptions that mention sibling tools or try to steer other tools
+func scanCrossToolReferences(selfName string, siblingNames []string, field, text string) []domain.MCPCapabilityFinding {
+	findings := []domain.MCPCapabilityFinding{}
+	lower := strings.ToLower(text)
+
+	referenced := []string{}
+	for _, name := range siblingNames {
+		// Very short names ("get", "run") produce too many false positives
+		if name == selfName || len(name) < 4 {
+			continue
+		}
+		if containsWord(lower, strings.ToLower(name)) {
+			referenced = append(referenced, name)
+		}
+	}
+	sort.Strings(referenced)
+
+	if len(referenced) > 0 {
+		findings = append(findings, domain.MCPCapabilityFinding{
+			RuleID:   "cross_tool_reference.sibling_tool",
+			Category: domain.MCPFindingCrossToolReference,
+			Severity: domain.AlertSeverityWarning,
+			Field:    field,
+			Message:  "References other tools exposed by this server",
+			Evidence: truncateEvidence(strings.Join(referenced, ", ")),
+		})
+	}
+
+	if match := otherToolsPattern.FindString(text); match != "" {
+		findings = append(findings, domain.MCPCapabilityFinding{
+			RuleID:   "cross_tool_reference.shadowing",
+			Category: domain.MCPFindingCrossToolReference,
+			Severity: domain.AlertSeverityHigh,
+			Field:    field,
+			Message:  "Tries to change how the model uses other tools (tool shadowing)",
+			Evidence: truncateEvidence(match),
+		})
+	}
+
+	return findings
+}
+
+// containsWord checks for name as a whole identifier (underscores and hyphens count as word characters)
+func containsWord(text, word string) bool {
+	isIdent := func(b byte) bool {
+		return b == '_' || b == '-' || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9')
+	}
+	for start := 0; ; {
+		idx := strings.Index(text[start:], word)
+		if idx < 0 {
+			return false
+		}
+		idx += start
+		end := idx + len(word)
+		if (idx == 0 || !isIdent(text[idx-1])) && (end == len(text) || !isIdent(text[end])) {
+			return true
+		}
+		start = idx + 1
+	}
+}
+
+// Parameter names that accept code or commands and should be constrained
+var dangerousParamNames = map[string]bool{
+	"command": true, "cmd": true, "shell": true, "script": true, "code": true,
+	"sql": true, "query": true, "eval": true, "exec": true, "expression": true,
+}
+
+// Parameter names frequently used as a side channel to exfiltrate context
+var sideChannelParamNames = map[string]bool{
+	"sidenote": true, "side_note": true, "notes": true, "context": true,
+	"conversation": true, "history": true, "metadata": true, "debug": true,
+}
+
+// scanBroadSchema flags input schemas that accept arbitrary or unconstrained input
+func scanBroadSchema(raw json.RawMessage) []domain.MCPCapabilityFinding {
+	findings := []domain.MCPCapabilityFinding{}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil || schema == nil {
+		return findings
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if additional, ok := schema["additionalProperties"]; ok {
+		if allowed, isBool := additional.(bool); (isBool && allowed) || (!isBool && additional != nil) {
+			findings = append(findings, domain.MCPCapabilityFinding{
+				RuleID:   "broad_schema.additional_properties",
+				Category: domain.MCPFindingBroadSchema,
+				Severity: domain.AlertSeverityWarning,
+				Field:    "schema",
+				Message:  "Input schema accepts arbitrary additional properties",
+			})
+		}
+	}
+
+	if _, hasType := schema["type"]; !hasType && len(properties) == 0 && len(schema) > 0 {
+		findings = append(findings, domain.MCPCapabilityFinding{
+			RuleID:   "broad_schema.untyped",
+			Category: domain.MCPFindingBroadSchema,
+			Severity: domain.AlertSeverityWarning,
+			Field:    "schema",
+			Message:  "Input schema declares neither a type nor properties",
+		})
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, _ := properties[name].(map[string]interface{})
+		lowerName := strings.ToLower(name)
+
+		if prop == nil || prop["type"] == nil && prop["enum"] == nil && prop["$ref"] == nil && prop["anyOf"] == nil && prop["oneOf"] == nil {
+			findings = append(findings, domain.MCPCapabilityFinding{
+				RuleID:   "broad_schema.untyped_property",
+				Category: domain.MCPFindingBroadSchema,
+				Severity: domain.AlertSeverityInfo,
+				Field:    "schema",
+				Message:  fmt.Sprintf("Parameter '%s' has no type constraint", name),
+			})
+			continue
+		}
+
+		if dangerousParamNames[lowerName] && prop["type"] == "string" && prop["enum"] == nil && prop["pattern"] == nil {
+			findings = append(findings, domain.MCPCapabilityFinding{
+				RuleID:   "broad_schema.free_form_code",
+				Category: domain.MCPFindingBroadSchema,
+				Severity: domain.AlertSeverityWarning,
+				Field:    "schema",
+				Message:  fmt.Sprintf("Parameter '%s' accepts free-form code or commands without enum or pattern", name),
+			})
+		}
+
+		if sideChannelParamNames[lowerName] && prop["type"] == "string" {
+			findings = append(findings, domain.MCPCapabilityFinding{
+				RuleID:   "broad_schema.side_channel",
+				Category: domain.MCPFindingBroadSchema,
+				Severity: domain.AlertSeverityWarning,
+				Field:    "schema",
+				Message:  fmt.Sprintf("Free-text parameter '%s' can be used to smuggle conversation data out", name),
+			})
+		}
+	}
+
+	return findings
+}
+
+// schemaDescriptions concatenates every "description" and "title" string in a JSON schema,
+// since parameter descriptions reach the model just like the tool description does
+func schemaDescriptions(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+
+	var parts []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(node))
+			for key := range node {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if text, ok := node[key].(string); ok && (key == "description" || key == "title" || key == "default") {
+					parts = append(parts, text)
+					continue
+				}
+				walk(node[key])
+			}
+		case []interface{}:
+			for _, item := range node {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+
+	return strings.Join(parts, "\n")
+}
+
+// truncateEvidence limits evidence to 120 characters and escapes invisible characters
+func truncateEvidence(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if invisible, _ := isInvisibleRune(r); invisible {
+			b.WriteString(fmt.Sprintf("\\u%04X", r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	escaped := b.String()
+	if utf8.RuneCountInString(escaped) > 120 {
+		escaped = string([]rune(escaped)[:117]) + "..."
+	}
+	return escaped
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,8 +27,11 @@ type MCPService struct {
 	capabilityService     *MCPCapabilityService  // ✅ For automatic capability detection
 	httpClient            *http.Client           // ✅ For real MCP server communication
 	agentRepo             *repository.AgentRepository // ✅ For querying connected agents
-	// In-memory challenge storage (in production, use Redis)
-	challenges map[string]ChallengeData
+	quotaService          *QuotaService               // ✅ For the plan's MCP server quota
+	// In-memory challenge storage (in production, use Redis), keyed by the challenge nonce so
+	// that concurrent attempts against the same server do not overwrite each other
+	challenges   map[string]ChallengeData
+	challengesMu sync.Mutex // ✅ Challenges are also issued by the scheduled health monitor
 }
 
 // ChallengeData stores challenge information
@@ -38,7 +42,7 @@ type ChallengeData struct {
 	ExpiresAt time.Time
 }
 
-func NewMCPService(mcpRepo *repository.MCPServerRepository, verificationEventRepo domain.VerificationEventRepository, userRepo *repository.UserRepository, keyVault *crypto.KeyVault, capabilityService *MCPCapabilityService, agentRepo *repository.AgentRepository) *MCPService {
+func NewMCPService(mcpRepo *repository.MCPServerRepository, verificationEventRepo domain.VerificationEventRepository, userRepo *repository.UserRepository, keyVault *crypto.KeyVault, capabilityService *MCPCapabilityService, agentRepo *repository.AgentRepository, quotaService *QuotaService) *MCPService {
 	return &MCPService{
 		mcpRepo:               mcpRepo,
 		verificationEventRepo: verificationEventRepo,
@@ -49,8 +53,9 @@ func NewMCPService(mcpRepo *repository.MCPServerRepository, verificationEventRep
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second, // 30 second timeout for MCP server communication
 		},
-		challenges: make(map[string]ChallengeData),
-		agentRepo:  agentRepo,
+		challenges:   make(map[string]ChallengeData),
+		agentRepo:    agentRepo,
+		quotaService: quotaService,
 	}
 }
 
@@ -58,11 +63,17 @@ func NewMCPService(mcpRepo *repository.MCPServerRepository, verificationEventRep
 type CreateMCPServerRequest struct {
 	Name            string   `json:"name" validate:"required"`
 	Description     string   `json:"description"`
-	URL             string   `json:"url" validate:"required,url"`
+	URL             string   `json:"url" validate:"omitempty,url"` // Required for sse and http servers
 	Version         string   `json:"version"`
 	PublicKey       string   `json:"public_key"`
 	VerificationURL string   `json:"verification_url"`
 	Capabilities    []string `json:"capabilities"`
+	// Local (stdio) servers are identified by their launch spec instead of a URL
+	Transport domain.MCPTransport   `json:"transport,omitempty"` // Defaults to stdio when a command is given, else http
+	Command   string                `json:"command,omitempty"`
+	Args      []string              `json:"args,omitempty"`
+	EnvNames  []string              `json:"env_names,omitempty"`
+	Package   *domain.MCPPackageRef `json:"package,omitempty"` // Parsed from npx, uvx or docker command lines when omitted
 }
 
 // UpdateMCPServerRequest represents the request to update an MCP server
@@ -84,10 +95,46 @@ type AddPublicKeyRequest struct {
 
 // CreateMCPServer creates a new MCP server
 func (s *MCPService) CreateMCPServer(ctx context.Context, req *CreateMCPServerRequest, orgID, userID uuid.UUID) (*domain.MCPServer, error) {
-	// Check if MCP server with this URL already exists
-	existing, _ := s.mcpRepo.GetByURL(req.URL)
-	if existing != nil {
-		return nil, fmt.Errorf("mcp server with this URL already exists")
+	transport := req.Transport
+	if transport == "" {
+		transport = domain.MCPTransportHTTP
+		if req.Command != "" {
+			transport = domain.MCPTransportStdio
+		}
+	}
+
+	var launch *domain.MCPServer
+	switch transport {
+	case domain.MCPTransportStdio:
+		if strings.TrimSpace(req.Command) == "" {
+			return nil, fmt.Errorf("invalid mcp server: command is required for stdio servers")
+		}
+		launch = newMCPStdioLaunch(req.Command, req.Args, req.EnvNames, req.Package)
+
+		// Agents launching the same command line share one server
+		existing, _ := s.mcpRepo.GetByIdentityHash(orgID, launch.IdentityHash)
+		if existing != nil {
+			return nil, fmt.Errorf("mcp server with this identity already exists")
+		}
+	case domain.MCPTransportSSE, domain.MCPTransportHTTP:
+		if strings.TrimSpace(req.URL) == "" {
+			return nil, fmt.Errorf("invalid mcp server: url is required for %s servers", transport)
+		}
+
+		// Check if MCP server with this URL already exists
+		existing, _ := s.mcpRepo.GetByURL(req.URL)
+		if existing != nil {
+			return nil, fmt.Errorf("mcp server with this URL already exists")
+		}
+	default:
+		return nil, fmt.Errorf("invalid mcp server: unsupported transport %q", transport)
+	}
+
+	// Enforce the plan's MCP server quota
+	if s.quotaService != nil {
+		if err := s.quotaService.CheckQuota(ctx, orgID, domain.QuotaMCPServers); err != nil {
+			return nil, err
+		}
 	}
 
 	// ✅ AUTOMATIC KEY GENERATION - Zero effort for developers
@@ -124,6 +171,18 @@ func (s *MCPService) CreateMCPServer(ctx context.Context, req *CreateMCPServerRe
 		Capabilities:    req.Capabilities,
 		TrustScore:      0.0,
 		CreatedBy:       userID,
+		Transport:       transport,
+	}
+	if launch != nil {
+		server.URL = ""
+		server.Command = launch.Command
+		server.Args = launch.Args
+		server.EnvNames = launch.EnvNames
+		server.Package = launch.Package
+		server.IdentityHash = launch.IdentityHash
+		if server.Version == "" && server.Package != nil {
+			server.Version = server.Package.Version
+		}
 	}
 
 	if err := s.mcpRepo.Create(server); err != nil {
@@ -151,6 +210,71 @@ func (s *MCPService) CreateMCPServer(ctx context.Context, req *CreateMCPServerRe
 	return server, nil
 }
 
+// MCPStdioRegistration is the outcome of registering a stdio server detected in an agent's config
+type MCPStdioRegistration struct {
+	Server  *domain.MCPServer
+	Created bool
+	Drift   *MCPIdentityDrift // Set when a server of the same name was registered with another launch spec
+}
+
+// RegisterStdioServer registers a stdio server detected in an agent's config. A server with the same
+// identity is reused whatever its name, so agents launching the same command line share it. A server
+// of the same name with a different identity is reported as drift and left unchanged; servers that
+// predate launch spec tracking or arg redaction adopt the detected spec.
+func (s *MCPService) RegisterStdioServer(ctx context.Context, req *CreateMCPServerRequest, orgID, userID uuid.UUID) (*MCPStdioRegistration, error) {
+	launch := newMCPStdioLaunch(req.Command, req.Args, req.EnvNames, req.Package)
+
+	if existing, _ := s.mcpRepo.GetByIdentityHash(orgID, launch.IdentityHash); existing != nil {
+		return &MCPStdioRegistration{Server: existing}, nil
+	}
+
+	servers, err := s.mcpRepo.GetByOrganization(orgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, server := range servers {
+		if server.Name != strings.TrimSpace(req.Name) {
+			continue
+		}
+		if server.Transport.IsRemote() {
+			return nil, fmt.Errorf("mcp server with this name already exists")
+		}
+
+		// Specs stored before arg redaction hash to the detected identity once redacted
+		if server.IdentityHash == "" || ComputeMCPStdioIdentity(server.Command, server.Args, server.EnvNames, server.Package) == launch.IdentityHash {
+			server.Command = launch.Command
+			server.Args = launch.Args
+			server.EnvNames = launch.EnvNames
+			server.Package = launch.Package
+			server.IdentityHash = launch.IdentityHash
+			if err := s.mcpRepo.Update(server); err != nil {
+				return nil, err
+			}
+			return &MCPStdioRegistration{Server: server}, nil
+		}
+
+		return &MCPStdioRegistration{
+			Server: server,
+			Drift: &MCPIdentityDrift{
+				ServerID:           server.ID,
+				Name:               server.Name,
+				RegisteredIdentity: server.IdentityHash,
+				DetectedIdentity:   launch.IdentityHash,
+				ChangedFields:      diffMCPStdioLaunch(server, launch),
+			},
+		}, nil
+	}
+
+	stdioReq := *req
+	stdioReq.Transport = domain.MCPTransportStdio
+	server, err := s.CreateMCPServer(ctx, &stdioReq, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MCPStdioRegistration{Server: server, Created: true}, nil
+}
+
 // GetMCPServer retrieves an MCP server by ID
 func (s *MCPService) GetMCPServer(ctx context.Context, id uuid.UUID) (*domain.MCPServer, error) {
 	return s.mcpRepo.GetByID(id)
@@ -176,6 +300,9 @@ func (s *MCPService) UpdateMCPServer(ctx context.Context, id uuid.UUID, req *Upd
 		server.Description = req.Description
 	}
 	if req.URL != "" {
+		if !server.Transport.IsRemote() {
+			return nil, fmt.Errorf("invalid mcp server: stdio servers have no URL")
+		}
 		server.URL = strings.TrimSpace(req.URL) // ✅ Trim spaces
 	}
 	if req.Version != "" {
@@ -221,19 +348,17 @@ func (s *MCPService) VerifyMCPServer(ctx context.Context, id uuid.UUID, userID u
 		}
 	}
 
+	if err := requireRemoteMCPServer(server); err != nil {
+		return err
+	}
+
 	// Cryptographic verification workflow:
 	// 1. Check if server has a public key
 	if server.PublicKey == "" {
 		return fmt.Errorf("server must have a public key for verification")
 	}
 
-	// 2. Generate challenge
-	challenge, err := s.GenerateVerificationChallenge(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to generate challenge: %w", err)
-	}
-
-	// ✅ 3. REAL CRYPTOGRAPHIC VERIFICATION
+	// ✅ 2-3. REAL CRYPTOGRAPHIC VERIFICATION
 	// Send challenge to server's verification URL and verify signed response
 	var verificationSuccess bool
 
@@ -242,52 +367,8 @@ func (s *MCPService) VerifyMCPServer(ctx context.Context, id uuid.UUID, userID u
 		server.VerificationURL = server.URL + "/.well-known/mcp/verify"
 	}
 
-	// Clean up URL (trim spaces that might have been entered in the form)
-	verificationURL := strings.TrimSpace(server.VerificationURL)
-
-	// Step 3a: Send challenge to MCP server
-	challengeReq := map[string]string{
-		"challenge": challenge,
-		"server_id": id.String(),
-	}
-	reqBody, err := json.Marshal(challengeReq)
-	if err != nil {
-		return fmt.Errorf("failed to marshal challenge request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", verificationURL, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return fmt.Errorf("failed to create verification request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-
-	resp, err := s.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("failed to contact MCP server verification endpoint: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("MCP server verification endpoint returned non-200 status: %d", resp.StatusCode)
-	}
-
-	// Step 3b: Parse signed challenge response
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read verification response: %w", err)
-	}
-
-	var verifyResp struct {
-		SignedChallenge string `json:"signed_challenge"`
-	}
-	if err := json.Unmarshal(respBody, &verifyResp); err != nil {
-		return fmt.Errorf("failed to parse verification response: %w", err)
-	}
-
-	// Step 3c: Verify the signed challenge using Ed25519
-	if err := s.VerifyChallengeResponse(ctx, id, verifyResp.SignedChallenge); err != nil {
-		return fmt.Errorf("signature verification failed: %w", err)
+	if err := s.ChallengeServer(ctx, server); err != nil {
+		return err
 	}
 
 	// ✅ Verification successful - cryptographic proof established
@@ -379,6 +460,77 @@ func (s *MCPService) VerifyMCPServer(ctx context.Context, id uuid.UUID, userID u
 	return nil
 }
 
+// ChallengeServer sends a fresh Ed25519 challenge to the server's verification endpoint and
+// verifies the signed response. It does not change the server's verification state.
+func (s *MCPService) ChallengeServer(ctx context.Context, server *domain.MCPServer) error {
+	id := server.ID
+
+	if err := requireRemoteMCPServer(server); err != nil {
+		return err
+	}
+
+	// 1. Generate challenge
+	challenge, err := s.GenerateVerificationChallenge(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to generate challenge: %w", err)
+	}
+
+	verificationURL := server.VerificationURL
+	if verificationURL == "" {
+		verificationURL = server.URL + "/.well-known/mcp/verify"
+	}
+
+	// Clean up URL (trim spaces that might have been entered in the form)
+	verificationURL = strings.TrimSpace(verificationURL)
+
+	// Step 3a: Send challenge to MCP server
+	challengeReq := map[string]string{
+		"challenge": challenge,
+		"server_id": id.String(),
+	}
+	reqBody, err := json.Marshal(challengeReq)
+	if err != nil {
+		return fmt.Errorf("failed to marshal challenge request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", verificationURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create verification request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to contact MCP server verification endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("MCP server verification endpoint returned non-200 status: %d", resp.StatusCode)
+	}
+
+	// Step 3b: Parse signed challenge response
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read verification response: %w", err)
+	}
+
+	var verifyResp struct {
+		SignedChallenge string `json:"signed_challenge"`
+	}
+	if err := json.Unmarshal(respBody, &verifyResp); err != nil {
+		return fmt.Errorf("failed to parse verification response: %w", err)
+	}
+
+	// Step 3c: Verify the signed challenge using Ed25519
+	if err := s.VerifyChallengeResponse(ctx, id, challenge, verifyResp.SignedChallenge); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	return nil
+}
+
 // AddPublicKey adds a public key to an MCP server
 func (s *MCPService) AddPublicKey(ctx context.Context, serverID uuid.UUID, req *AddPublicKeyRequest) error {
 	// Verify server exists
@@ -413,30 +565,17 @@ func (s *MCPService) GenerateVerificationChallenge(ctx context.Context, serverID
 		return "", fmt.Errorf("failed to generate challenge: %w", err)
 	}
 
-	// Store challenge with expiration (5 minutes)
-	now := time.Now()
-	s.challenges[serverID.String()] = ChallengeData{
-		Challenge: challenge,
-		ServerID:  serverID,
-		CreatedAt: now,
-		ExpiresAt: now.Add(5 * time.Minute),
-	}
+	s.storeChallenge(serverID, challenge)
 
 	return challenge, nil
 }
 
-// VerifyChallengeResponse verifies a signed challenge response
-func (s *MCPService) VerifyChallengeResponse(ctx context.Context, serverID uuid.UUID, signedChallenge string) error {
-	// Retrieve challenge
-	challengeData, exists := s.challenges[serverID.String()]
-	if !exists {
-		return fmt.Errorf("no challenge found for server")
-	}
-
-	// Check if challenge has expired
-	if time.Now().After(challengeData.ExpiresAt) {
-		delete(s.challenges, serverID.String())
-		return fmt.Errorf("challenge has expired")
+// VerifyChallengeResponse verifies a signed response to a challenge issued for the server.
+// Each challenge can be answered once.
+func (s *MCPService) VerifyChallengeResponse(ctx context.Context, serverID uuid.UUID, challenge, signedChallenge string) error {
+	challengeData, err := s.takeChallenge(serverID, challenge)
+	if err != nil {
+		return err
 	}
 
 	// Get server details
@@ -460,12 +599,49 @@ func (s *MCPService) VerifyChallengeResponse(ctx context.Context, serverID uuid.
 		return fmt.Errorf("invalid signature")
 	}
 
-	// Clean up challenge after successful verification
-	delete(s.challenges, serverID.String())
-
 	return nil
 }
 
+// storeChallenge records a challenge with expiration (5 minutes) and drops expired ones, which
+// are left behind by attempts that never got a response
+func (s *MCPService) storeChallenge(serverID uuid.UUID, challenge string) {
+	now := time.Now()
+	s.challengesMu.Lock()
+	defer s.challengesMu.Unlock()
+	for nonce, data := range s.challenges {
+		if now.After(data.ExpiresAt) {
+			delete(s.challenges, nonce)
+		}
+	}
+	s.challenges[challenge] = ChallengeData{
+		Challenge: challenge,
+		ServerID:  serverID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(5 * time.Minute),
+	}
+}
+
+// takeChallenge removes and returns a challenge issued for the server
+func (s *MCPService) takeChallenge(serverID uuid.UUID, challenge string) (ChallengeData, error) {
+	s.challengesMu.Lock()
+	challengeData, exists := s.challenges[challenge]
+	if exists && challengeData.ServerID == serverID {
+		delete(s.challenges, challenge)
+	}
+	s.challengesMu.Unlock()
+
+	if !exists || challengeData.ServerID != serverID {
+		return ChallengeData{}, fmt.Errorf("no challenge found for server")
+	}
+
+	// Check if challenge has expired
+	if time.Now().After(challengeData.ExpiresAt) {
+		return ChallengeData{}, fmt.Errorf("challenge has expired")
+	}
+
+	return challengeData, nil
+}
+
 // VerifyMCPAction verifies if an MCP server can perform an action
 func (s *MCPService) VerifyMCPAction(
 	ctx context.Context,
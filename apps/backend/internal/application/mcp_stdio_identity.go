@@ -0,0 +1,337 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// mcpStdioLaunchSpec is the canonical form of a stdio server's launch spec that is hashed
+type mcpStdioLaunchSpec struct {
+	Command  string                `json:"command"`
+	Args     []string              `json:"args"`
+	EnvNames []string              `json:"env_names"`
+	Package  *domain.MCPPackageRef `json:"package"`
+}
+
+// MCPIdentityDrift reports a stdio server whose launch spec no longer matches the registered one
+type MCPIdentityDrift struct {
+	ServerID           uuid.UUID `json:"server_id"`
+	Name               string    `json:"name"`
+	RegisteredIdentity string    `json:"registered_identity_hash"`
+	DetectedIdentity   string    `json:"detected_identity_hash"`
+	ChangedFields      []string  `json:"changed_fields"` // command, args, env_names and/or package
+}
+
+// ComputeMCPStdioIdentity hashes a stdio server's launch spec. Env var names are sorted and
+// deduplicated, so two agents launching the same command line get the same identity however their
+// configs are ordered, while any change to the command, args, env names or package changes it.
+// Args are hashed after RedactMCPArgs, so rotating a credential passed on the command line keeps
+// the identity.
+func ComputeMCPStdioIdentity(command string, args, envNames []string, pkg *domain.MCPPackageRef) string {
+	spec := mcpStdioLaunchSpec{
+		Command:  strings.TrimSpace(command),
+		Args:     RedactMCPArgs(args),
+		EnvNames: normalizeMCPEnvNames(envNames),
+		Package:  pkg,
+	}
+	if spec.Args == nil {
+		spec.Args = []string{}
+	}
+
+	// Marshalling a struct of strings cannot fail
+	encoded, _ := json.Marshal(spec)
+	return sha256Hex(encoded)
+}
+
+// newMCPStdioLaunch returns the normalized launch spec and identity of a stdio server. The package
+// is parsed from the command line when not given, and credentials in the args are redacted.
+func newMCPStdioLaunch(command string, args, envNames []string, pkg *domain.MCPPackageRef) *domain.MCPServer {
+	command = strings.TrimSpace(command)
+	if pkg == nil || pkg.Name == "" {
+		pkg = ParseMCPPackageRef(command, args)
+	}
+
+	return &domain.MCPServer{
+		Transport:    domain.MCPTransportStdio,
+		Command:      command,
+		Args:         RedactMCPArgs(args),
+		EnvNames:     normalizeMCPEnvNames(envNames),
+		Package:      pkg,
+		IdentityHash: ComputeMCPStdioIdentity(command, args, envNames, pkg),
+	}
+}
+
+// diffMCPStdioLaunch lists the launch spec fields that differ between two stdio servers
+func diffMCPStdioLaunch(registered, detected *domain.MCPServer) []string {
+	changed := []string{}
+	if registered.Command != detected.Command {
+		changed = append(changed, "command")
+	}
+	if strings.Join(registered.Args, "\x00") != strings.Join(detected.Args, "\x00") || len(registered.Args) != len(detected.Args) {
+		changed = append(changed, "args")
+	}
+	if strings.Join(normalizeMCPEnvNames(registered.EnvNames), ",") != strings.Join(detected.EnvNames, ",") {
+		changed = append(changed, "env_names")
+	}
+	if !reflect.DeepEqual(registered.Package, detected.Package) {
+		changed = append(changed, "package")
+	}
+	return changed
+}
+
+// requireRemoteMCPServer rejects network operations (challenge verification, capability discovery,
+// health probes) on stdio servers, which only exist as processes on the agent's host
+func requireRemoteMCPServer(server *domain.MCPServer) error {
+	if server.Transport != "" && !server.Transport.IsRemote() {
+		return fmt.Errorf("mcp server %s uses the stdio transport and has no URL; stdio servers are identified by their launch spec and cannot be verified over the network", server.Name)
+	}
+	return nil
+}
+
+// mcpCredentialName matches flag, variable and header names that carry credentials
+var mcpCredentialName = regexp.MustCompile(`(?i)(token|secret|passw|api[-_]?key|access[-_]?key|private[-_]?key|auth|credential|bearer|cookie|session)`)
+
+// RedactMCPArgs returns a copy of a stdio server's args with credentials replaced by
+// redactedEnvValue: values of credential-named flags (--api-key x, --token=x), NAME=value and
+// "Header: value" args with credential names (docker -e GITHUB_TOKEN=x, --header "Authorization:
+// Bearer x"), and URL userinfo and credential-named query parameters. Redacting twice is a no-op.
+func RedactMCPArgs(args []string) []string {
+	if args == nil {
+		return nil
+	}
+
+	redacted := make([]string, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if strings.HasPrefix(arg, "-") {
+			name, _, hasValue := strings.Cut(arg, "=")
+			if mcpCredentialName.MatchString(name) {
+				if hasValue {
+					redacted[i] = name + "=" + redactedEnvValue
+					continue
+				}
+				redacted[i] = arg
+				if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+					i++
+					redacted[i] = redactedEnvValue
+				}
+				continue
+			}
+		}
+
+		redacted[i] = redactMCPArgValue(arg)
+	}
+	return redacted
+}
+
+// redactMCPArgValue redacts a single arg that is not a flag
+func redactMCPArgValue(arg string) string {
+	if strings.Contains(arg, "://") {
+		return redactMCPURL(arg)
+	}
+	if name, _, ok := strings.Cut(arg, "="); ok && !strings.ContainsAny(name, " /") && mcpCredentialName.MatchString(name) {
+		return name + "=" + redactedEnvValue
+	}
+	if name, _, ok := strings.Cut(arg, ":"); ok && !strings.Contains(name, " ") && mcpCredentialName.MatchString(name) {
+		return name + ": " + redactedEnvValue
+	}
+	return arg
+}
+
+// redactMCPURL redacts the userinfo and credential-named query parameters of a URL arg. It works on
+// the string rather than net/url so that the rest of the arg is kept byte for byte.
+func redactMCPURL(arg string) string {
+	schemeEnd := strings.Index(arg, "://") + len("://")
+	authorityEnd := len(arg)
+	if idx := strings.IndexAny(arg[schemeEnd:], "/?#"); idx >= 0 {
+		authorityEnd = schemeEnd + idx
+	}
+	if at := strings.LastIndex(arg[schemeEnd:authorityEnd], "@"); at >= 0 {
+		arg = arg[:schemeEnd] + redactedEnvValue + arg[schemeEnd+at:]
+	}
+
+	queryStart := strings.Index(arg, "?")
+	if queryStart < 0 {
+		return arg
+	}
+	query, fragment := arg[queryStart+1:], ""
+	if idx := strings.Index(query, "#"); idx >= 0 {
+		query, fragment = query[:idx], query[idx:]
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		if name, _, ok := strings.Cut(param, "="); ok && mcpCredentialName.MatchString(name) {
+			params[i] = name + "=" + redactedEnvValue
+		}
+	}
+	return arg[:queryStart+1] + strings.Join(params, "&") + fragment
+}
+
+// normalizeMCPEnvNames returns the sorted, deduplicated, non-empty env var names
+func normalizeMCPEnvNames(envNames []string) []string {
+	seen := make(map[string]bool, len(envNames))
+	normalized := []string{}
+	for _, name := range envNames {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// ParseMCPPackageRef extracts the package a stdio server is launched from when the command is a
+// package runner: npx, bunx, pnpm dlx and yarn dlx (npm), uvx and pipx run (pypi) or docker run.
+// It returns nil for anything else, such as a local script or binary.
+func ParseMCPPackageRef(command string, args []string) *domain.MCPPackageRef {
+	runner := strings.TrimSuffix(filepath.Base(strings.TrimSpace(command)), ".cmd")
+
+	switch runner {
+	case "npx", "bunx":
+		return parseNPMPackageArg(packageRunnerArg(args, "--package", "-p"))
+	case "pnpm", "yarn":
+		if len(args) > 0 && args[0] == "dlx" {
+			return parseNPMPackageArg(packageRunnerArg(args[1:], "--package", "-p"))
+		}
+	case "uvx":
+		return parsePyPIPackageArg(packageRunnerArg(args, "--from"))
+	case "pipx":
+		if len(args) > 0 && args[0] == "run" {
+			return parsePyPIPackageArg(packageRunnerArg(args[1:], "--spec"))
+		}
+	case "docker", "podman":
+		if len(args) > 0 && args[0] == "run" {
+			return parseDockerImageArg(dockerImageArg(args[1:]))
+		}
+	}
+
+	return nil
+}
+
+// packageRunnerArg returns the value of the first of packageFlags, or else the first positional
+// argument. Other flags are skipped; runner flags that take values use the --flag=value form.
+func packageRunnerArg(args []string, packageFlags ...string) string {
+	for i, arg := range args {
+		for _, flag := range packageFlags {
+			if arg == flag && i+1 < len(args) {
+				return args[i+1]
+			}
+			if strings.HasPrefix(arg, flag+"=") {
+				return strings.TrimPrefix(arg, flag+"=")
+			}
+		}
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+			return ""
+		}
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+	}
+
+	return ""
+}
+
+// parseNPMPackageArg parses name, name@version, @scope/name or @scope/name@version
+func parseNPMPackageArg(arg string) *domain.MCPPackageRef {
+	if arg == "" || strings.ContainsAny(arg, `\:`) || strings.HasPrefix(arg, ".") || strings.HasPrefix(arg, "/") {
+		return nil // Local path, URL or git reference
+	}
+
+	name, version := arg, ""
+	if at := strings.LastIndex(arg, "@"); at > 0 {
+		name, version = arg[:at], arg[at+1:]
+	}
+	if strings.HasPrefix(name, "@") && !strings.Contains(name, "/") {
+		return nil
+	}
+	if version == "latest" {
+		version = ""
+	}
+
+	return &domain.MCPPackageRef{Registry: "npm", Name: name, Version: version}
+}
+
+// parsePyPIPackageArg parses name, name==version, name@version or name[extras]==version
+func parsePyPIPackageArg(arg string) *domain.MCPPackageRef {
+	if arg == "" || strings.ContainsAny(arg, `/\:`) || strings.HasPrefix(arg, ".") {
+		return nil // Local path, URL or git reference
+	}
+
+	name, version := arg, ""
+	if idx := strings.Index(arg, "=="); idx > 0 {
+		name, version = arg[:idx], arg[idx+2:]
+	} else if idx := strings.Index(arg, "@"); idx > 0 {
+		name, version = arg[:idx], arg[idx+1:]
+	}
+	if idx := strings.Index(name, "["); idx > 0 {
+		name = name[:idx]
+	}
+	if version == "latest" {
+		version = ""
+	}
+
+	// PyPI names are case-insensitive and treat -, _ and . alike (PEP 503)
+	name = strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+
+	return &domain.MCPPackageRef{Registry: "pypi", Name: name, Version: version}
+}
+
+// dockerValueFlags are the docker run flags commonly used in MCP configs that take a separate value
+var dockerValueFlags = map[string]bool{
+	"-e": true, "--env": true, "--env-file": true, "-v": true, "--volume": true, "--mount": true,
+	"--name": true, "--network": true, "-p": true, "--publish": true, "-u": true, "--user": true,
+	"-w": true, "--workdir": true, "--entrypoint": true, "--platform": true, "-l": true, "--label": true,
+}
+
+// dockerImageArg returns the image of a docker run command line
+func dockerImageArg(args []string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if dockerValueFlags[arg] {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+	}
+	return ""
+}
+
+// parseDockerImageArg parses image, image:tag or image@sha256:digest
+func parseDockerImageArg(arg string) *domain.MCPPackageRef {
+	if arg == "" {
+		return nil
+	}
+
+	name, version := arg, ""
+	if at := strings.Index(arg, "@"); at > 0 {
+		name, version = arg[:at], arg[at+1:]
+	} else if colon := strings.LastIndex(arg, ":"); colon > strings.LastIndex(arg, "/") {
+		name, version = arg[:colon], arg[colon+1:]
+	}
+	if version == "latest" {
+		version = ""
+	}
+
+	return &domain.MCPPackageRef{Registry: "docker", Name: name, Version: version}
+}
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeMCPStdioIdentity(t *testing.T) {
+	args := []string{"-y", "@modelcontextprotocol/server-github@1.2.0"}
+	pkg := ParseMCPPackageRef("npx", args)
+	base := ComputeMCPStdioIdentity("npx", args, []string{"GITHUB_TOKEN", "GITHUB_HOST"}, pkg)
+
+	assert.Len(t, base, 64)
+	assert.Equal(t, base, ComputeMCPStdioIdentity(" npx ", args, []string{"GITHUB_HOST", "GITHUB_TOKEN", "GITHUB_TOKEN"}, pkg),
+		"env name order, duplicates and surrounding whitespace do not change the identity")
+
+	changed := map[string]string{
+		"command":   ComputeMCPStdioIdentity("/usr/local/bin/npx", args, []string{"GITHUB_TOKEN", "GITHUB_HOST"}, pkg),
+		"args":      ComputeMCPStdioIdentity("npx", []string{"-y", "@modelcontextprotocol/server-github@1.3.0"}, []string{"GITHUB_TOKEN", "GITHUB_HOST"}, pkg),
+		"env":       ComputeMCPStdioIdentity("npx", args, []string{"GITHUB_TOKEN"}, pkg),
+		"package":   ComputeMCPStdioIdentity("npx", args, []string{"GITHUB_TOKEN", "GITHUB_HOST"}, nil),
+		"arg split": ComputeMCPStdioIdentity("npx", []string{"-y @modelcontextprotocol/server-github@1.2.0"}, []string{"GITHUB_TOKEN", "GITHUB_HOST"}, pkg),
+	}
+	for field, identity := range changed {
+		assert.NotEqual(t, base, identity, field)
+	}
+
+	assert.Equal(t,
+		ComputeMCPStdioIdentity("npx", []string{"-y", "mcp-remote", "--api-key", "old"}, nil, nil),
+		ComputeMCPStdioIdentity("npx", []string{"-y", "mcp-remote", "--api-key", "rotated"}, nil, nil),
+		"credentials in args do not change the identity")
+}
+
+func TestRedactMCPArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"-y", "@scope/pkg@1.2.3", "--token", "x"}, []string{"-y", "@scope/pkg@1.2.3", "--token", redactedEnvValue}},
+		{[]string{"--api-key=sk-123", "--port", "8080"}, []string{"--api-key=" + redactedEnvValue, "--port", "8080"}},
+		{[]string{"--password", "--verbose"}, []string{"--password", "--verbose"}},
+		{[]string{"run", "-e", "GITHUB_TOKEN=ghp_x", "-e", "GITHUB_HOST=github.com", "image"},
+			[]string{"run", "-e", "GITHUB_TOKEN=" + redactedEnvValue, "-e", "GITHUB_HOST=github.com", "image"}},
+		{[]string{"mcp-remote", "https://mcp.example.com/sse", "--header", "Authorization: Bearer abc"},
+			[]string{"mcp-remote", "https://mcp.example.com/sse", "--header", "Authorization: " + redactedEnvValue}},
+		{[]string{"postgresql://admin:hunter2@db:5432/app?sslmode=disable&password=x"},
+			[]string{"postgresql://" + redactedEnvValue + "@db:5432/app?sslmode=disable&password=" + redactedEnvValue}},
+		{[]string{"https://example.com/a?q=1#frag"}, []string{"https://example.com/a?q=1#frag"}},
+	}
+
+	for _, tt := range tests {
+		redacted := RedactMCPArgs(tt.args)
+		assert.Equal(t, tt.want, redacted, "%v", tt.args)
+		assert.Equal(t, redacted, RedactMCPArgs(redacted), "redacting twice is a no-op")
+	}
+	assert.Nil(t, RedactMCPArgs(nil))
+
+	launch := newMCPStdioLaunch("npx", []string{"-y", "@scope/pkg@1.0.0", "--token", "secret"}, nil, nil)
+	assert.Equal(t, []string{"-y", "@scope/pkg@1.0.0", "--token", redactedEnvValue}, launch.Args,
+		"registered servers never store raw credentials")
+	assert.Equal(t, &domain.MCPPackageRef{Registry: "npm", Name: "@scope/pkg", Version: "1.0.0"}, launch.Package)
+}
+
+func TestParseMCPPackageRef(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		want    *domain.MCPPackageRef
+	}{
+		{"npx", []string{"-y", "@modelcontextprotocol/server-github"}, &domain.MCPPackageRef{Registry: "npm", Name: "@modelcontextprotocol/server-github"}},
+		{"npx", []string{"-y", "@scope/pkg@1.2.3", "--token", "x"}, &domain.MCPPackageRef{Registry: "npm", Name: "@scope/pkg", Version: "1.2.3"}},
+		{"npx.cmd", []string{"mcp-remote@latest", "https://example.com"}, &domain.MCPPackageRef{Registry: "npm", Name: "mcp-remote"}},
+		{"npx", []string{"-p", "pkg@2.0.0", "pkg-bin"}, &domain.MCPPackageRef{Registry: "npm", Name: "pkg", Version: "2.0.0"}},
+		{"pnpm", []string{"dlx", "server-everything@0.6.2"}, &domain.MCPPackageRef{Registry: "npm", Name: "server-everything", Version: "0.6.2"}},
+		{"uvx", []string{"mcp-server-fetch"}, &domain.MCPPackageRef{Registry: "pypi", Name: "mcp-server-fetch"}},
+		{"/home/me/.local/bin/uvx", []string{"--from", "Mcp_Server.Git==0.6.2", "mcp-server-git"}, &domain.MCPPackageRef{Registry: "pypi", Name: "mcp-server-git", Version: "0.6.2"}},
+		{"pipx", []string{"run", "mcp-server-time[tz]==1.0"}, &domain.MCPPackageRef{Registry: "pypi", Name: "mcp-server-time", Version: "1.0"}},
+		{"docker", []string{"run", "-i", "--rm", "-e", "GITHUB_TOKEN", "ghcr.io/github/github-mcp-server:v0.4.0"}, &domain.MCPPackageRef{Registry: "docker", Name: "ghcr.io/github/github-mcp-server", Version: "v0.4.0"}},
+		{"docker", []string{"run", "-i", "mcp/postgres@sha256:abc"}, &domain.MCPPackageRef{Registry: "docker", Name: "mcp/postgres", Version: "sha256:abc"}},
+		{"npx", []string{"-y", "./local-server"}, nil},
+		{"uvx", []string{"--from", "git+https://github.com/org/repo", "server"}, nil},
+		{"node", []string{"/opt/server/index.js"}, nil},
+		{"python", []string{"-m", "my_server"}, nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ParseMCPPackageRef(tt.command, tt.args), "%s %v", tt.command, tt.args)
+	}
+}
+
+func TestDiffMCPStdioLaunch(t *testing.T) {
+	registered := newMCPStdioLaunch("npx", []string{"-y", "@scope/pkg@1.0.0"}, []string{"TOKEN"}, nil)
+
+	assert.Empty(t, diffMCPStdioLaunch(registered, newMCPStdioLaunch("npx", []string{"-y", "@scope/pkg@1.0.0"}, []string{"TOKEN"}, nil)))
+	assert.Equal(t, []string{"args", "package"},
+		diffMCPStdioLaunch(registered, newMCPStdioLaunch("npx", []string{"-y", "@scope/pkg@1.0.1"}, []string{"TOKEN"}, nil)))
+	assert.Equal(t, []string{"command", "env_names"},
+		diffMCPStdioLaunch(registered, newMCPStdioLaunch("bunx", []string{"-y", "@scope/pkg@1.0.0"}, []string{"TOKEN", "EXTRA"}, nil)))
+
+	assert.ErrorContains(t, requireRemoteMCPServer(registered), "cannot be verified over the network")
+	assert.NoError(t, requireRemoteMCPServer(&domain.MCPServer{Transport: domain.MCPTransportSSE}))
+	assert.NoError(t, requireRemoteMCPServer(&domain.MCPServer{}))
+}
@@ -0,0 +1,497 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// ToolSurfaceDriftPenalty is the MCP server trust score penalty applied for each unapproved tool-surface change
+const ToolSurfaceDriftPenalty = 15.0
+
+// MCPToolSurfaceService detects "rug pulls" - MCP servers that silently add tools
+// or rewrite tool descriptions/schemas after they have been reviewed and trusted.
+//
+// Every capability discovery is reduced to a versioned snapshot of hashes. The first
+// snapshot becomes the approved baseline; any later snapshot that differs from the
+// last approved one is stored as pending, raises a configuration drift alert, lowers
+// the server trust score and, when a config_drift policy is set to block, suspends
+// the server until an admin approves the new tool surface. Rejecting a change suspends
+// the server, and a server that presents a rejected tool surface again stays suspended.
+type MCPToolSurfaceService struct {
+	snapshotRepo domain.MCPToolSurfaceRepository
+	mcpRepo      domain.MCPServerRepository
+	alertRepo    domain.AlertRepository
+	policyRepo   domain.SecurityPolicyRepository
+}
+
+// NewMCPToolSurfaceService creates a new MCP tool-surface service
+func NewMCPToolSurfaceService(
+	snapshotRepo domain.MCPToolSurfaceRepository,
+	mcpRepo domain.MCPServerRepository,
+	alertRepo domain.AlertRepository,
+	policyRepo domain.SecurityPolicyRepository,
+) *MCPToolSurfaceService {
+	return &MCPToolSurfaceService{
+		snapshotRepo: snapshotRepo,
+		mcpRepo:      mcpRepo,
+		alertRepo:    alertRepo,
+		policyRepo:   policyRepo,
+	}
+}
+
+// ToolSurfaceDiscoveryResult describes the outcome of recording a discovery
+type ToolSurfaceDiscoveryResult struct {
+	Snapshot        *domain.MCPToolSurfaceSnapshot `json:"snapshot"`
+	DriftDetected   bool                           `json:"drift_detected"`
+	AlreadyReported bool                           `json:"already_reported"` // The drift was alerted and penalized at an earlier discovery
+	ServerSuspended bool                           `json:"server_suspended"`
+	Alert           *domain.Alert                  `json:"alert,omitempty"`
+}
+
+// RecordDiscovery hashes the discovered capabilities and compares them with the last approved snapshot
+func (s *MCPToolSurfaceService) RecordDiscovery(
+	ctx context.Context,
+	server *domain.MCPServer,
+	capabilities []*domain.MCPServerCapability,
+) (*ToolSurfaceDiscoveryResult, error) {
+	tools := FingerprintCapabilities(capabilities)
+	surfaceHash := ComputeSurfaceHash(tools)
+
+	approved, err := s.snapshotRepo.GetLatestApproved(server.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. First discovery - trust on first use, establish the baseline
+	if approved == nil {
+		snapshot := &domain.MCPToolSurfaceSnapshot{
+			MCPServerID:   server.ID,
+			SurfaceHash:   surfaceHash,
+			Tools:         tools,
+			Status:        domain.MCPToolSurfaceStatusApproved,
+			ReviewComment: "Baseline established at first capability discovery",
+		}
+		if err := s.snapshotRepo.Create(snapshot); err != nil {
+			return nil, err
+		}
+		return &ToolSurfaceDiscoveryResult{Snapshot: snapshot}, nil
+	}
+
+	// 2. Unchanged tool surface - nothing to record. A server suspended because its change was
+	// rejected is back on the approved surface, so the suspension is lifted.
+	if approved.SurfaceHash == surfaceHash {
+		if server.Status == domain.MCPServerStatusSuspended {
+			latest, err := s.snapshotRepo.GetLatest(server.ID)
+			if err != nil {
+				return nil, err
+			}
+			if latest != nil && latest.Status == domain.MCPToolSurfaceStatusRejected {
+				if err := s.liftSuspension(server); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return &ToolSurfaceDiscoveryResult{Snapshot: approved}, nil
+	}
+
+	// 3. Same drift already recorded and awaiting review - avoid duplicate alerts and penalties
+	latest, err := s.snapshotRepo.GetLatest(server.ID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Status == domain.MCPToolSurfaceStatusPending && latest.SurfaceHash == surfaceHash {
+		return &ToolSurfaceDiscoveryResult{
+			Snapshot:        latest,
+			DriftDetected:   true,
+			AlreadyReported: true,
+			ServerSuspended: server.Status == domain.MCPServerStatusSuspended,
+		}, nil
+	}
+
+	// 4. A tool surface an admin already rejected - keep the server suspended, but do not alert
+	// or penalize the same change again
+	rejected, err := s.snapshotRepo.GetRejectedBySurfaceHash(server.ID, surfaceHash)
+	if err != nil {
+		return nil, err
+	}
+	if rejected != nil {
+		if server.Status != domain.MCPServerStatusSuspended {
+			server.Status = domain.MCPServerStatusSuspended
+			if err := s.mcpRepo.Update(server); err != nil {
+				return nil, fmt.Errorf("failed to suspend MCP server: %w", err)
+			}
+		}
+		return &ToolSurfaceDiscoveryResult{
+			Snapshot:        rejected,
+			DriftDetected:   true,
+			AlreadyReported: true,
+			ServerSuspended: true,
+		}, nil
+	}
+
+	// 5. New drift - store pending snapshot and respond
+	snapshot := &domain.MCPToolSurfaceSnapshot{
+		MCPServerID: server.ID,
+		SurfaceHash: surfaceHash,
+		Tools:       tools,
+		Diff:        DiffToolSurfaces(approved.Tools, tools),
+		Status:      domain.MCPToolSurfaceStatusPending,
+	}
+	if err := s.snapshotRepo.Create(snapshot); err != nil {
+		return nil, err
+	}
+
+	result := &ToolSurfaceDiscoveryResult{Snapshot: snapshot, DriftDetected: true}
+
+	shouldSuspend, policyName := s.evaluateDriftPolicy(server)
+
+	alert, err := s.createToolSurfaceDriftAlert(server, approved, snapshot, shouldSuspend, policyName)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to create tool surface drift alert for MCP server %s: %v\n", server.Name, err)
+	}
+	result.Alert = alert
+
+	previousScore := server.TrustScore
+	server.TrustScore -= ToolSurfaceDriftPenalty
+	if server.TrustScore < MinimumTrustScore {
+		server.TrustScore = MinimumTrustScore
+	}
+	if shouldSuspend {
+		server.Status = domain.MCPServerStatusSuspended
+		result.ServerSuspended = true
+	}
+	if err := s.mcpRepo.Update(server); err != nil {
+		return result, fmt.Errorf("failed to apply tool surface drift to MCP server: %w", err)
+	}
+
+	fmt.Printf("⚠️  Tool surface drift on MCP server %s (v%d -> v%d): trust %.2f -> %.2f, suspended=%t\n",
+		server.Name, approved.Version, snapshot.Version, previousScore, server.TrustScore, shouldSuspend)
+
+	return result, nil
+}
+
+// ApproveLatest approves the newest pending snapshot as the new baseline and lifts a drift suspension
+func (s *MCPToolSurfaceService) ApproveLatest(ctx context.Context, serverID, userID uuid.UUID, comment string) (*domain.MCPToolSurfaceSnapshot, error) {
+	snapshot, err := s.latestPending(serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.snapshotRepo.UpdateStatus(snapshot.ID, domain.MCPToolSurfaceStatusApproved, &userID, comment); err != nil {
+		return nil, err
+	}
+
+	server, err := s.mcpRepo.GetByID(serverID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.liftSuspension(server); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	snapshot.Status = domain.MCPToolSurfaceStatusApproved
+	snapshot.ReviewedBy = &userID
+	snapshot.ReviewedAt = &now
+	snapshot.ReviewComment = comment
+
+	return snapshot, nil
+}
+
+// RejectLatest rejects the newest pending snapshot and suspends the server, which still serves
+// the rejected tool surface; the suspension is lifted once it is back on the approved baseline
+func (s *MCPToolSurfaceService) RejectLatest(ctx context.Context, serverID, userID uuid.UUID, comment string) (*domain.MCPToolSurfaceSnapshot, error) {
+	snapshot, err := s.latestPending(serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.snapshotRepo.UpdateStatus(snapshot.ID, domain.MCPToolSurfaceStatusRejected, &userID, comment); err != nil {
+		return nil, err
+	}
+
+	server, err := s.mcpRepo.GetByID(serverID)
+	if err != nil {
+		return nil, err
+	}
+	if server.Status != domain.MCPServerStatusSuspended {
+		server.Status = domain.MCPServerStatusSuspended
+		if err := s.mcpRepo.Update(server); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	snapshot.Status = domain.MCPToolSurfaceStatusRejected
+	snapshot.ReviewedBy = &userID
+	snapshot.ReviewedAt = &now
+	snapshot.ReviewComment = comment
+
+	return snapshot, nil
+}
+
+// ListSnapshots returns the snapshot history for an MCP server, newest first
+func (s *MCPToolSurfaceService) ListSnapshots(ctx context.Context, serverID uuid.UUID, limit, offset int) ([]*domain.MCPToolSurfaceSnapshot, error) {
+	return s.snapshotRepo.GetByServerID(serverID, limit, offset)
+}
+
+// liftSuspension restores a suspended server to the status its verification gives it
+func (s *MCPToolSurfaceService) liftSuspension(server *domain.MCPServer) error {
+	if server.Status != domain.MCPServerStatusSuspended {
+		return nil
+	}
+	if server.IsVerified {
+		server.Status = domain.MCPServerStatusVerified
+	} else {
+		server.Status = domain.MCPServerStatusPending
+	}
+	return s.mcpRepo.Update(server)
+}
+
+func (s *MCPToolSurfaceService) latestPending(serverID uuid.UUID) (*domain.MCPToolSurfaceSnapshot, error) {
+	latest, err := s.snapshotRepo.GetLatest(serverID)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil || latest.Status != domain.MCPToolSurfaceStatusPending {
+		return nil, fmt.Errorf("no pending tool surface change for this MCP server")
+	}
+	return latest, nil
+}
+
+// evaluateDriftPolicy decides whether the server should be suspended based on config_drift policies
+// Default (no policy configured) is alert-only so existing deployments are not disrupted.
+func (s *MCPToolSurfaceService) evaluateDriftPolicy(server *domain.MCPServer) (shouldSuspend bool, policyName string) {
+	if s.policyRepo == nil {
+		return false, "default_policy"
+	}
+
+	policies, err := s.policyRepo.GetByType(server.OrganizationID, domain.PolicyTypeConfigDrift)
+	if err != nil || len(policies) == 0 {
+		return false, "default_policy"
+	}
+
+	// Policies are ordered by priority (highest first)
+	for _, policy := range policies {
+		if !policyAppliesToMCPServer(policy, server) {
+			continue
+		}
+		return policy.EnforcementAction == domain.EnforcementBlockAndAlert, policy.Name
+	}
+
+	return false, "default_policy"
+}
+
+// policyAppliesToMCPServer checks the applies_to scope of a policy against an MCP server
+func policyAppliesToMCPServer(policy *domain.SecurityPolicy, server *domain.MCPServer) bool {
+	if strings.HasPrefix(policy.AppliesTo, "mcp_server_id:") {
+		return strings.TrimPrefix(policy.AppliesTo, "mcp_server_id:") == server.ID.String()
+	}
+	// Agent-scoped policies never match MCP servers
+	if strings.HasPrefix(policy.AppliesTo, "agent_id:") || strings.HasPrefix(policy.AppliesTo, "agent_type:") {
+		return false
+	}
+	return true
+}
+
+// createToolSurfaceDriftAlert creates a configuration drift alert describing the tool-surface change
+func (s *MCPToolSurfaceService) createToolSurfaceDriftAlert(
+	server *domain.MCPServer,
+	approved *domain.MCPToolSurfaceSnapshot,
+	snapshot *domain.MCPToolSurfaceSnapshot,
+	suspended bool,
+	policyName string,
+) (*domain.Alert, error) {
+	diff := snapshot.Diff
+
+	message := fmt.Sprintf("MCP server '%s' changed its tool surface since the last approved snapshot (v%d -> v%d).",
+		server.Name, approved.Version, snapshot.Version)
+
+	if len(diff.Added) > 0 {
+		message += "\n\n**New Capabilities:**\n"
+		for _, tool := range diff.Added {
+			message += fmt.Sprintf("- `%s` (%s)\n", tool.Name, tool.Type)
+		}
+	}
+	if len(diff.Removed) > 0 {
+		message += "\n\n**Removed Capabilities:**\n"
+		for _, tool := range diff.Removed {
+			message += fmt.Sprintf("- `%s` (%s)\n", tool.Name, tool.Type)
+		}
+	}
+	if len(diff.Modified) > 0 {
+		message += "\n\n**Modified Capabilities:**\n"
+		for _, change := range diff.Modified {
+			var parts []string
+			if change.DescriptionChanged {
+				parts = append(parts, "description")
+			}
+			if change.SchemaChanged {
+				parts = append(parts, "schema")
+			}
+			message += fmt.Sprintf("- `%s` (%s changed)\n", change.Name, strings.Join(parts, " and "))
+		}
+	}
+
+	enforcement := "alert only"
+	if suspended {
+		enforcement = "server suspended until an admin approves the new tool surface"
+	}
+	message += fmt.Sprintf("\n\n**Enforcement:** %s (policy: %s)\n", enforcement, policyName)
+
+	message += "\n**Recommended Actions:**\n"
+	message += "1. Review the new and modified tool descriptions for hidden instructions\n"
+	message += "2. If legitimate, approve the new tool surface\n"
+	message += "3. If suspicious, reject it and disconnect agents from this server\n"
+
+	severity := domain.AlertSeverityHigh
+	if len(diff.Added) > 0 || suspended {
+		severity = domain.AlertSeverityCritical
+	}
+
+	alert := &domain.Alert{
+		ID:             uuid.New(),
+		OrganizationID: server.OrganizationID,
+		AlertType:      domain.AlertTypeConfigurationDrift,
+		Severity:       severity,
+		Title:          fmt.Sprintf("MCP Tool Surface Changed: %s", server.Name),
+		Description:    message,
+		ResourceType:   "mcp_server",
+		ResourceID:     server.ID,
+		IsAcknowledged: false,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := s.alertRepo.Create(alert); err != nil {
+		return nil, fmt.Errorf("failed to create alert: %w", err)
+	}
+
+	return alert, nil
+}
+
+// ===== Hashing helpers =====
+
+// FingerprintCapabilities hashes each capability's name, type, description and schema.
+// The result is sorted by type and name so that snapshots are order independent.
+func FingerprintCapabilities(capabilities []*domain.MCPServerCapability) []domain.MCPToolFingerprint {
+	tools := make([]domain.MCPToolFingerprint, 0, len(capabilities))
+	for _, capability := range capabilities {
+		descriptionHash := sha256Hex([]byte(capability.Description))
+		schemaHash := sha256Hex(canonicalizeJSON(capability.CapabilitySchema))
+
+		tools = append(tools, domain.MCPToolFingerprint{
+			Name:            capability.Name,
+			Type:            capability.CapabilityType,
+			DescriptionHash: descriptionHash,
+			SchemaHash:      schemaHash,
+			Hash: sha256Hex([]byte(strings.Join([]string{
+				string(capability.CapabilityType), capability.Name, descriptionHash, schemaHash,
+			}, "\x00"))),
+		})
+	}
+
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Type != tools[j].Type {
+			return tools[i].Type < tools[j].Type
+		}
+		return tools[i].Name < tools[j].Name
+	})
+
+	return tools
+}
+
+// ComputeSurfaceHash hashes a sorted list of tool fingerprints into a single surface hash
+func ComputeSurfaceHash(tools []domain.MCPToolFingerprint) string {
+	hashes := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		hashes = append(hashes, tool.Hash)
+	}
+	sort.Strings(hashes)
+	return sha256Hex([]byte(strings.Join(hashes, "\n")))
+}
+
+// FindingsCountedWithDrift reports whether the metadata findings of an approved tool surface were
+// already counted when it was recorded as drift: an admin approved the change and the stored
+// capabilities still belong to the surface it replaced
+func FindingsCountedWithDrift(approved *domain.MCPToolSurfaceSnapshot, stored []*domain.MCPServerCapability) bool {
+	if approved == nil || approved.Status != domain.MCPToolSurfaceStatusApproved || approved.ReviewedBy == nil || approved.Diff == nil {
+		return false
+	}
+	return ComputeSurfaceHash(FingerprintCapabilities(stored)) != approved.SurfaceHash
+}
+
+// DiffToolSurfaces compares an approved tool list against a newly discovered one
+func DiffToolSurfaces(approved, current []domain.MCPToolFingerprint) *domain.MCPToolSurfaceDiff {
+	diff := &domain.MCPToolSurfaceDiff{
+		Added:    []domain.MCPToolFingerprint{},
+		Removed:  []domain.MCPToolFingerprint{},
+		Modified: []domain.MCPToolChange{},
+	}
+
+	key := func(tool domain.MCPToolFingerprint) string {
+		return string(tool.Type) + ":" + tool.Name
+	}
+
+	approvedByKey := make(map[string]domain.MCPToolFingerprint, len(approved))
+	for _, tool := range approved {
+		approvedByKey[key(tool)] = tool
+	}
+
+	currentKeys := make(map[string]bool, len(current))
+	for _, tool := range current {
+		currentKeys[key(tool)] = true
+
+		previous, exists := approvedByKey[key(tool)]
+		if !exists {
+			diff.Added = append(diff.Added, tool)
+			continue
+		}
+		if previous.Hash != tool.Hash {
+			diff.Modified = append(diff.Modified, domain.MCPToolChange{
+				Name:               tool.Name,
+				Type:               tool.Type,
+				DescriptionChanged: previous.DescriptionHash != tool.DescriptionHash,
+				SchemaChanged:      previous.SchemaHash != tool.SchemaHash,
+			})
+		}
+	}
+
+	for _, tool := range approved {
+		if !currentKeys[key(tool)] {
+			diff.Removed = append(diff.Removed, tool)
+		}
+	}
+
+	return diff
+}
+
+// canonicalizeJSON re-encodes JSON with sorted object keys so that semantically
+// identical schemas hash identically regardless of key order or whitespace
+func canonicalizeJSON(raw json.RawMessage) []byte {
+	if len(raw) == 0 {
+		return []byte("null")
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return raw
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return raw
+	}
+	return canonical
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,251 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// ErrOutsideOrganizationScope is returned when an organization is not in the caller's subtree
+var ErrOutsideOrganizationScope = errors.New("organization is outside your administrative scope")
+
+var organizationSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CreateChildOrganizationRequest describes a business unit to create under an organization
+type CreateChildOrganizationRequest struct {
+	Name string `json:"name"`
+	// Domain defaults to "<name>.<parent domain>" and must be a subdomain of the parent's
+	// domain. Users registering with an email at this domain join the child organization.
+	Domain string `json:"domain,omitempty"`
+}
+
+// OrganizationHierarchyService manages parent/child organizations. Users of an organization
+// act with their role on its whole subtree, so central security keeps access to every
+// business unit while business unit admins only reach their own.
+type OrganizationHierarchyService struct {
+	orgRepo       domain.OrganizationRepository
+	hierarchyRepo domain.OrganizationHierarchyRepository
+	userRepo      domain.UserRepository
+	actionCatalog *ActionCatalogService
+	now           func() time.Time
+}
+
+// NewOrganizationHierarchyService creates a new organization hierarchy service
+func NewOrganizationHierarchyService(
+	orgRepo domain.OrganizationRepository,
+	hierarchyRepo domain.OrganizationHierarchyRepository,
+	userRepo domain.UserRepository,
+	actionCatalog *ActionCatalogService,
+) *OrganizationHierarchyService {
+	return &OrganizationHierarchyService{
+		orgRepo:       orgRepo,
+		hierarchyRepo: hierarchyRepo,
+		userRepo:      userRepo,
+		actionCatalog: actionCatalog,
+		now:           func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// CreateChild creates a business unit under an organization. The child starts on the
+// parent's plan and with the default action catalog; it draws on the quotas of its
+// top-level organization rather than getting quotas of its own.
+func (s *OrganizationHierarchyService) CreateChild(
+	ctx context.Context,
+	parentID uuid.UUID,
+	req CreateChildOrganizationRequest,
+) (*domain.Organization, error) {
+	parent, err := s.orgRepo.GetByID(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("organization not found")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid organization: name is required")
+	}
+
+	domainName := strings.ToLower(strings.TrimSpace(req.Domain))
+	if domainName == "" {
+		slug := strings.Trim(organizationSlugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+		if slug == "" {
+			return nil, fmt.Errorf("invalid organization: name must contain letters or digits")
+		}
+		domainName = slug + "." + parent.Domain
+	}
+	// Registrants join the organization that owns their email domain, so a child may only
+	// claim subdomains of its parent's domain
+	if !strings.HasSuffix(domainName, "."+parent.Domain) {
+		return nil, fmt.Errorf("invalid organization: domain must be a subdomain of %s", parent.Domain)
+	}
+
+	existing, err := s.orgRepo.GetByDomain(domainName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check organization domain: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("organization domain already in use: %s", domainName)
+	}
+
+	child := &domain.Organization{
+		ParentID:  &parent.ID,
+		Name:      name,
+		Domain:    domainName,
+		PlanType:  parent.PlanType,
+		MaxAgents: parent.MaxAgents,
+		MaxUsers:  parent.MaxUsers,
+		IsActive:  true,
+	}
+	if err := s.orgRepo.Create(child); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	if s.actionCatalog != nil {
+		if _, err := s.actionCatalog.SeedDefaults(ctx, child.ID); err != nil {
+			fmt.Printf("⚠️  Failed to seed action catalog for organization %s: %v\n", child.ID, err)
+		}
+	}
+
+	fmt.Printf("✅ Created child organization %s (%s) under %s\n", child.Name, child.ID, parent.ID)
+	return child, nil
+}
+
+// ListChildren returns the direct children of an organization
+func (s *OrganizationHierarchyService) ListChildren(ctx context.Context, orgID uuid.UUID) ([]*domain.Organization, error) {
+	return s.hierarchyRepo.GetChildren(orgID)
+}
+
+// GetTree returns an organization with all of its descendants
+func (s *OrganizationHierarchyService) GetTree(ctx context.Context, rootID uuid.UUID) (*domain.OrganizationNode, error) {
+	orgs, err := s.hierarchyRepo.GetSubtree(rootID)
+	if err != nil {
+		return nil, err
+	}
+	if len(orgs) == 0 {
+		return nil, fmt.Errorf("organization not found")
+	}
+
+	// Parents are listed before their children
+	nodes := make(map[uuid.UUID]*domain.OrganizationNode, len(orgs))
+	for _, org := range orgs {
+		node := &domain.OrganizationNode{Organization: org, Children: []*domain.OrganizationNode{}}
+		nodes[org.ID] = node
+		if org.ID != rootID && org.ParentID != nil {
+			if parent, ok := nodes[*org.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+			}
+		}
+	}
+
+	return nodes[rootID], nil
+}
+
+// SubtreeIDs returns the IDs of an organization and all of its descendants
+func (s *OrganizationHierarchyService) SubtreeIDs(ctx context.Context, rootID uuid.UUID) ([]uuid.UUID, error) {
+	orgs, err := s.hierarchyRepo.GetSubtree(rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(orgs))
+	for _, org := range orgs {
+		ids = append(ids, org.ID)
+	}
+	return ids, nil
+}
+
+// IsWithinScope reports whether an organization is the root or one of its descendants
+func (s *OrganizationHierarchyService) IsWithinScope(ctx context.Context, rootID, orgID uuid.UUID) (bool, error) {
+	if rootID == orgID {
+		return true, nil
+	}
+
+	ancestors, err := s.hierarchyRepo.GetAncestorIDs(orgID)
+	if err != nil {
+		return false, err
+	}
+	for _, ancestorID := range ancestors {
+		if ancestorID == rootID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MoveUser moves a user between organizations of the caller's subtree, e.g. to make them a
+// business unit admin
+func (s *OrganizationHierarchyService) MoveUser(ctx context.Context, scopeRootID, userID, targetOrgID uuid.UUID) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	for _, orgID := range []uuid.UUID{user.OrganizationID, targetOrgID} {
+		ok, err := s.IsWithinScope(ctx, scopeRootID, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check organization scope: %w", err)
+		}
+		if !ok {
+			return nil, ErrOutsideOrganizationScope
+		}
+	}
+
+	if err := s.hierarchyRepo.MoveUser(userID, targetOrgID); err != nil {
+		return nil, err
+	}
+	user.OrganizationID = targetOrgID
+
+	return user, nil
+}
+
+// GetRollup rolls analytics up across an organization and its descendants
+func (s *OrganizationHierarchyService) GetRollup(ctx context.Context, rootID uuid.UUID, days int) (*domain.OrganizationRollupReport, error) {
+	ids, err := s.SubtreeIDs(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("organization not found")
+	}
+
+	now := s.now()
+	since := now.AddDate(0, 0, -days)
+	orgs, err := s.hierarchyRepo.GetRollup(ids, since)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &domain.OrganizationRollup{OrganizationID: rootID, Name: "Total"}
+	trustSum := 0.0
+	for _, org := range orgs {
+		if org.OrganizationID == rootID {
+			totals.Name = org.Name
+			totals.ParentID = org.ParentID
+		}
+		totals.TotalAgents += org.TotalAgents
+		totals.VerifiedAgents += org.VerifiedAgents
+		totals.TotalMCPServers += org.TotalMCPServers
+		totals.TotalUsers += org.TotalUsers
+		totals.OpenAlerts += org.OpenAlerts
+		totals.CriticalAlerts += org.CriticalAlerts
+		totals.TotalVerifications += org.TotalVerifications
+		totals.FailedVerifications += org.FailedVerifications
+		trustSum += org.AvgTrustScore * float64(org.TotalAgents)
+	}
+	if totals.TotalAgents > 0 {
+		totals.AvgTrustScore = trustSum / float64(totals.TotalAgents)
+	}
+
+	return &domain.OrganizationRollupReport{
+		RootOrganizationID: rootID,
+		Since:              since,
+		Totals:             totals,
+		Organizations:      orgs,
+		GeneratedAt:        now,
+	}, nil
+}
@@ -0,0 +1,437 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOrganizationTree keeps organizations in memory and implements both the organization
+// and the hierarchy repository
+type fakeOrganizationTree struct {
+	orgs    []*domain.Organization
+	rollups map[uuid.UUID]*domain.OrganizationRollup
+	moved   map[uuid.UUID]uuid.UUID
+}
+
+func newFakeOrganizationTree() *fakeOrganizationTree {
+	return &fakeOrganizationTree{rollups: map[uuid.UUID]*domain.OrganizationRollup{}, moved: map[uuid.UUID]uuid.UUID{}}
+}
+
+func (t *fakeOrganizationTree) add(name string, parent *domain.Organization) *domain.Organization {
+	org := &domain.Organization{ID: uuid.New(), Name: name, Domain: name + ".example.com", PlanType: "pro", MaxAgents: 1000, MaxUsers: 100}
+	if parent != nil {
+		org.ParentID = &parent.ID
+	}
+	t.orgs = append(t.orgs, org)
+	return org
+}
+
+func (t *fakeOrganizationTree) Create(org *domain.Organization) error {
+	org.ID = uuid.New()
+	t.orgs = append(t.orgs, org)
+	return nil
+}
+
+func (t *fakeOrganizationTree) GetByID(id uuid.UUID) (*domain.Organization, error) {
+	for _, org := range t.orgs {
+		if org.ID == id {
+			return org, nil
+		}
+	}
+	return nil, fmt.Errorf("organization not found")
+}
+
+func (t *fakeOrganizationTree) GetByDomain(domainName string) (*domain.Organization, error) {
+	for _, org := range t.orgs {
+		if org.Domain == domainName {
+			return org, nil
+		}
+	}
+	return nil, nil
+}
+
+func (t *fakeOrganizationTree) Update(org *domain.Organization) error {
+	for i, existing := range t.orgs {
+		if existing.ID == org.ID {
+			t.orgs[i] = org
+			return nil
+		}
+	}
+	return fmt.Errorf("organization not found")
+}
+
+func (t *fakeOrganizationTree) Delete(id uuid.UUID) error {
+	for i, org := range t.orgs {
+		if org.ID == id {
+			t.orgs = append(t.orgs[:i], t.orgs[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("organization not found")
+}
+
+func (t *fakeOrganizationTree) GetChildren(parentID uuid.UUID) ([]*domain.Organization, error) {
+	children := []*domain.Organization{}
+	for _, org := range t.orgs {
+		if org.ParentID != nil && *org.ParentID == parentID {
+			children = append(children, org)
+		}
+	}
+	return children, nil
+}
+
+func (t *fakeOrganizationTree) GetAncestorIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	org, err := t.GetByID(id)
+	for err == nil && org.ParentID != nil {
+		ids = append(ids, *org.ParentID)
+		org, err = t.GetByID(*org.ParentID)
+	}
+	return ids, nil
+}
+
+func (t *fakeOrganizationTree) GetSubtree(rootID uuid.UUID) ([]*domain.Organization, error) {
+	root, err := t.GetByID(rootID)
+	if err != nil {
+		return []*domain.Organization{}, nil
+	}
+	subtree := []*domain.Organization{root}
+	for i := 0; i < len(subtree); i++ {
+		children, _ := t.GetChildren(subtree[i].ID)
+		subtree = append(subtree, children...)
+	}
+	return subtree, nil
+}
+
+func (t *fakeOrganizationTree) MoveUser(userID, orgID uuid.UUID) error {
+	t.moved[userID] = orgID
+	return nil
+}
+
+func (t *fakeOrganizationTree) GetRollup(orgIDs []uuid.UUID, since time.Time) ([]*domain.OrganizationRollup, error) {
+	rollups := []*domain.OrganizationRollup{}
+	for _, id := range orgIDs {
+		if rollup, ok := t.rollups[id]; ok {
+			rollups = append(rollups, rollup)
+		}
+	}
+	return rollups, nil
+}
+
+func TestOrganizationHierarchyService_CreateChild(t *testing.T) {
+	tree := newFakeOrganizationTree()
+	root := tree.add("acme", nil)
+	service := NewOrganizationHierarchyService(tree, tree, &fakeUserRepository{}, nil)
+	ctx := context.Background()
+
+	child, err := service.CreateChild(ctx, root.ID, CreateChildOrganizationRequest{Name: "  Payments & Billing "})
+	require.NoError(t, err)
+	assert.Equal(t, "Payments & Billing", child.Name)
+	assert.Equal(t, "payments-billing.acme.example.com", child.Domain)
+	assert.Equal(t, &root.ID, child.ParentID)
+	assert.Equal(t, "pro", child.PlanType)
+	assert.True(t, child.IsActive)
+
+	_, err = service.CreateChild(ctx, root.ID, CreateChildOrganizationRequest{Name: "payments billing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "organization domain already in use")
+
+	emea, err := service.CreateChild(ctx, root.ID, CreateChildOrganizationRequest{Name: "EMEA", Domain: "EMEA.acme.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "emea.acme.example.com", emea.Domain)
+
+	// A child cannot claim an email domain outside its parent's
+	for _, claimed := range []string{"bigcorp.com", "acme.example.com", "evilacme.example.com"} {
+		_, err = service.CreateChild(ctx, root.ID, CreateChildOrganizationRequest{Name: "Squatter", Domain: claimed})
+		require.Error(t, err, claimed)
+		assert.Contains(t, err.Error(), "invalid organization", claimed)
+	}
+
+	_, err = service.CreateChild(ctx, root.ID, CreateChildOrganizationRequest{Name: " "})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid organization")
+
+	_, err = service.CreateChild(ctx, uuid.New(), CreateChildOrganizationRequest{Name: "orphan"})
+	assert.EqualError(t, err, "organization not found")
+}
+
+func TestOrganizationHierarchyService_ScopeCoversSubtree(t *testing.T) {
+	tree := newFakeOrganizationTree()
+	root := tree.add("acme", nil)
+	payments := tree.add("payments", root)
+	cards := tree.add("cards", payments)
+	research := tree.add("research", root)
+	other := tree.add("globex", nil)
+	service := NewOrganizationHierarchyService(tree, tree, &fakeUserRepository{}, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		scope  uuid.UUID
+		target uuid.UUID
+		want   bool
+	}{
+		{"own organization", payments.ID, payments.ID, true},
+		{"child", root.ID, payments.ID, true},
+		{"grandchild", root.ID, cards.ID, true},
+		{"parent", payments.ID, root.ID, false},
+		{"sibling", payments.ID, research.ID, false},
+		{"unrelated tenant", root.ID, other.ID, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := service.IsWithinScope(ctx, tt.scope, tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+
+	node, err := service.GetTree(ctx, root.ID)
+	require.NoError(t, err)
+	assert.Equal(t, root.ID, node.ID)
+	require.Len(t, node.Children, 2)
+	assert.Equal(t, payments.ID, node.Children[0].ID)
+	require.Len(t, node.Children[0].Children, 1)
+	assert.Equal(t, cards.ID, node.Children[0].Children[0].ID)
+}
+
+func TestOrganizationHierarchyService_MoveUser(t *testing.T) {
+	tree := newFakeOrganizationTree()
+	root := tree.add("acme", nil)
+	payments := tree.add("payments", root)
+	research := tree.add("research", root)
+	user := &domain.User{ID: uuid.New(), OrganizationID: root.ID, Role: domain.RoleAdmin}
+	users := &fakeUserRepository{users: map[uuid.UUID]*domain.User{user.ID: user}}
+	service := NewOrganizationHierarchyService(tree, tree, users, nil)
+	ctx := context.Background()
+
+	// A business unit admin cannot pull users out of the parent or into a sibling
+	_, err := service.MoveUser(ctx, payments.ID, user.ID, payments.ID)
+	assert.ErrorIs(t, err, ErrOutsideOrganizationScope)
+
+	moved, err := service.MoveUser(ctx, root.ID, user.ID, payments.ID)
+	require.NoError(t, err)
+	assert.Equal(t, payments.ID, moved.OrganizationID)
+	assert.Equal(t, payments.ID, tree.moved[user.ID])
+
+	_, err = service.MoveUser(ctx, payments.ID, user.ID, research.ID)
+	assert.ErrorIs(t, err, ErrOutsideOrganizationScope)
+
+	_, err = service.MoveUser(ctx, root.ID, uuid.New(), payments.ID)
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestOrganizationHierarchyService_GetRollup(t *testing.T) {
+	tree := newFakeOrganizationTree()
+	root := tree.add("acme", nil)
+	payments := tree.add("payments", root)
+	other := tree.add("globex", nil)
+	tree.rollups[root.ID] = &domain.OrganizationRollup{OrganizationID: root.ID, Name: "acme", TotalAgents: 2, AvgTrustScore: 0.9, TotalUsers: 3, OpenAlerts: 1}
+	tree.rollups[payments.ID] = &domain.OrganizationRollup{OrganizationID: payments.ID, Name: "payments", TotalAgents: 6, AvgTrustScore: 0.5, TotalUsers: 2, TotalVerifications: 40, FailedVerifications: 4}
+	tree.rollups[other.ID] = &domain.OrganizationRollup{OrganizationID: other.ID, TotalAgents: 100}
+	service := NewOrganizationHierarchyService(tree, tree, &fakeUserRepository{}, nil)
+	service.now = func() time.Time { return time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC) }
+
+	report, err := service.GetRollup(context.Background(), root.ID, 7)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), report.Since)
+	assert.Len(t, report.Organizations, 2)
+	assert.Equal(t, "acme", report.Totals.Name)
+	assert.Equal(t, 8, report.Totals.TotalAgents)
+	assert.InDelta(t, 0.6, report.Totals.AvgTrustScore, 0.0001)
+	assert.Equal(t, 5, report.Totals.TotalUsers)
+	assert.Equal(t, 1, report.Totals.OpenAlerts)
+	assert.Equal(t, 40, report.Totals.TotalVerifications)
+	assert.Equal(t, 4, report.Totals.FailedVerifications)
+
+	// The rollup of a business unit does not include its parent
+	report, err = service.GetRollup(context.Background(), payments.ID, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 6, report.Totals.TotalAgents)
+}
+
+func TestSecurityPolicyService_InheritedPoliciesCanOnlyBeTightened(t *testing.T) {
+	tree := newFakeOrganizationTree()
+	root := tree.add("acme", nil)
+	payments := tree.add("payments", root)
+	agent := &domain.Agent{ID: uuid.New(), OrganizationID: payments.ID, Name: "ledger-bot", AgentType: domain.AgentTypeAI}
+
+	global := &domain.SecurityPolicy{
+		ID: uuid.New(), OrganizationID: root.ID, Name: "Block Capability Violations",
+		PolicyType: domain.PolicyTypeCapabilityViolation, EnforcementAction: domain.EnforcementBlockAndAlert,
+		AppliesTo: "all", IsEnabled: true, Inheritable: true,
+	}
+	exfiltration := &domain.SecurityPolicy{
+		ID: uuid.New(), OrganizationID: root.ID, Name: "Monitor Data Exfiltration",
+		PolicyType: domain.PolicyTypeDataExfiltration, EnforcementAction: domain.EnforcementAlertOnly,
+		Rules: map[string]interface{}{"data_threshold_mb": 100.0}, AppliesTo: "all", IsEnabled: true, Inheritable: true,
+	}
+	local := &domain.SecurityPolicy{
+		ID: uuid.New(), OrganizationID: payments.ID, Name: "Allow Capability Violations",
+		PolicyType: domain.PolicyTypeCapabilityViolation, EnforcementAction: domain.EnforcementAllow,
+		AppliesTo: "all", IsEnabled: true, Priority: 2000,
+	}
+
+	policyRepo := new(MockToolSurfacePolicyRepository)
+	policyRepo.On("GetByType", payments.ID, domain.PolicyTypeCapabilityViolation).Return([]*domain.SecurityPolicy{local}, nil)
+	policyRepo.On("GetByType", root.ID, domain.PolicyTypeCapabilityViolation).Return([]*domain.SecurityPolicy{global}, nil)
+	policyRepo.On("GetByType", payments.ID, domain.PolicyTypeDataExfiltration).Return([]*domain.SecurityPolicy{}, nil)
+	policyRepo.On("GetByType", root.ID, domain.PolicyTypeDataExfiltration).Return([]*domain.SecurityPolicy{exfiltration}, nil)
+	policyRepo.On("Create", mock.Anything).Return(nil)
+	service := NewSecurityPolicyService(policyRepo, new(MockToolSurfaceAlertRepository), tree)
+	ctx := context.Background()
+
+	// The child's looser policy does not override the parent's blocking policy
+	shouldBlock, shouldAlert, policyName, err := service.EvaluateCapabilityViolation(ctx, agent, "delete_file", "/ledger", uuid.New())
+	require.NoError(t, err)
+	assert.True(t, shouldBlock)
+	assert.True(t, shouldAlert)
+	assert.Equal(t, "Block Capability Violations", policyName)
+
+	// Inherited policies apply where the child has none
+	policy, err := service.FindApplicablePolicy(ctx, agent, domain.PolicyTypeDataExfiltration)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.Equal(t, exfiltration.ID, policy.ID)
+	assert.True(t, policy.Inherited)
+
+	// Creating looser policies in the child is rejected
+	err = service.CreatePolicy(ctx, &domain.SecurityPolicy{
+		OrganizationID: payments.ID, Name: "Alert only", PolicyType: domain.PolicyTypeCapabilityViolation,
+		EnforcementAction: domain.EnforcementAlertOnly, AppliesTo: "agent_type:ai",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "policy loosens inherited policy \"Block Capability Violations\"")
+
+	err = service.CreatePolicy(ctx, &domain.SecurityPolicy{
+		OrganizationID: payments.ID, Name: "Higher threshold", PolicyType: domain.PolicyTypeDataExfiltration,
+		EnforcementAction: domain.EnforcementBlockAndAlert, AppliesTo: "all",
+		Rules: map[string]interface{}{"data_threshold_mb": 500.0},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "data_threshold_mb may not exceed 100")
+
+	// Tightening is allowed
+	require.NoError(t, service.CreatePolicy(ctx, &domain.SecurityPolicy{
+		OrganizationID: payments.ID, Name: "Block large exports", PolicyType: domain.PolicyTypeDataExfiltration,
+		EnforcementAction: domain.EnforcementBlockAndAlert, AppliesTo: "all",
+		Rules: map[string]interface{}{"data_threshold_mb": 20.0},
+	}))
+
+	// Top-level organizations are unconstrained
+	require.NoError(t, service.CreatePolicy(ctx, &domain.SecurityPolicy{
+		OrganizationID: root.ID, Name: "Allow", PolicyType: domain.PolicyTypeCapabilityViolation,
+		EnforcementAction: domain.EnforcementAllow, AppliesTo: "all",
+	}))
+}
+
+func TestSecurityPolicyService_InheritedRulesApplyAtEvaluation(t *testing.T) {
+	tree := newFakeOrganizationTree()
+	root := tree.add("acme", nil)
+	payments := tree.add("payments", root)
+	agent := &domain.Agent{ID: uuid.New(), OrganizationID: payments.ID, Name: "ledger-bot", AgentType: domain.AgentTypeAI}
+
+	// The parent tightened its policy after the child created its own
+	parentPolicy := &domain.SecurityPolicy{
+		ID: uuid.New(), OrganizationID: root.ID, Name: "Limit exports",
+		PolicyType: domain.PolicyTypeDataExfiltration, EnforcementAction: domain.EnforcementBlockAndAlert,
+		Rules: map[string]interface{}{
+			"data_threshold_mb": 50.0,
+			"read_threshold_mb": 500.0,
+			"time_window":       "2h",
+			"export_actions":    []interface{}{"data:export", "files:upload"},
+		},
+		AppliesTo: "all", IsEnabled: true, Inheritable: true,
+	}
+	childPolicy := &domain.SecurityPolicy{
+		ID: uuid.New(), OrganizationID: payments.ID, Name: "Payments exports",
+		PolicyType: domain.PolicyTypeDataExfiltration, EnforcementAction: domain.EnforcementBlockAndAlert,
+		Rules: map[string]interface{}{
+			"data_threshold_mb": 200.0,
+			"read_threshold_mb": 0.0, // Disabled, so the parent's threshold applies
+			"export_actions":    []interface{}{"ledger:export"},
+		},
+		AppliesTo: "all", IsEnabled: true,
+	}
+	agentScoped := &domain.SecurityPolicy{
+		ID: uuid.New(), OrganizationID: root.ID, Name: "Block ledger-bot",
+		PolicyType: domain.PolicyTypeCapabilityViolation, EnforcementAction: domain.EnforcementBlockAndAlert,
+		AppliesTo: "agent_id:" + agent.ID.String(), IsEnabled: true, Inheritable: true,
+	}
+
+	policyRepo := new(MockToolSurfacePolicyRepository)
+	policyRepo.On("GetByType", payments.ID, domain.PolicyTypeDataExfiltration).Return([]*domain.SecurityPolicy{childPolicy}, nil)
+	policyRepo.On("GetByType", root.ID, domain.PolicyTypeDataExfiltration).Return([]*domain.SecurityPolicy{parentPolicy}, nil)
+	policyRepo.On("GetByType", root.ID, domain.PolicyTypeCapabilityViolation).Return([]*domain.SecurityPolicy{agentScoped}, nil)
+	service := NewSecurityPolicyService(policyRepo, new(MockToolSurfaceAlertRepository), tree)
+	ctx := context.Background()
+
+	policy, err := service.FindApplicablePolicy(ctx, agent, domain.PolicyTypeDataExfiltration)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.Equal(t, childPolicy.ID, policy.ID)
+	assert.True(t, policy.Inherited)
+
+	config := newDataExfiltrationConfig(policy)
+	assert.Equal(t, int64(50*bytesPerMB), config.thresholdBytes)
+	assert.Equal(t, int64(500*bytesPerMB), config.readThresholdBytes)
+	assert.Equal(t, 2*time.Hour, config.window)
+	assert.ElementsMatch(t, []string{"ledger:export", "data:export", "files:upload"}, config.exportActions)
+
+	// The stored policies are left alone
+	assert.Equal(t, 200.0, childPolicy.Rules["data_threshold_mb"])
+
+	// A parent policy scoped to one agent still constrains a child policy for all agents
+	err = service.CreatePolicy(ctx, &domain.SecurityPolicy{
+		OrganizationID: payments.ID, Name: "Allow all", PolicyType: domain.PolicyTypeCapabilityViolation,
+		EnforcementAction: domain.EnforcementAllow, AppliesTo: "all",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "policy loosens inherited policy \"Block ledger-bot\"")
+}
+
+func TestSecurityPolicyService_InheritedOffHoursShareCanOnlyBeRaised(t *testing.T) {
+	tree := newFakeOrganizationTree()
+	root := tree.add("acme", nil)
+	payments := tree.add("payments", root)
+	agent := &domain.Agent{ID: uuid.New(), OrganizationID: payments.ID, Name: "ledger-bot", AgentType: domain.AgentTypeAI}
+
+	parentPolicy := &domain.SecurityPolicy{
+		ID: uuid.New(), OrganizationID: root.ID, Name: "Watch off-hours activity",
+		PolicyType: domain.PolicyTypeUnusualActivity, EnforcementAction: domain.EnforcementAlertOnly,
+		Rules: map[string]interface{}{"off_hours_share": 0.05}, AppliesTo: "all", IsEnabled: true, Inheritable: true,
+	}
+	childPolicy := &domain.SecurityPolicy{
+		ID: uuid.New(), OrganizationID: payments.ID, Name: "Payments activity",
+		PolicyType: domain.PolicyTypeUnusualActivity, EnforcementAction: domain.EnforcementAlertOnly,
+		Rules: map[string]interface{}{"off_hours_share": 0.01}, AppliesTo: "all", IsEnabled: true,
+	}
+
+	policyRepo := new(MockToolSurfacePolicyRepository)
+	policyRepo.On("GetByType", payments.ID, domain.PolicyTypeUnusualActivity).Return([]*domain.SecurityPolicy{childPolicy}, nil)
+	policyRepo.On("GetByType", root.ID, domain.PolicyTypeUnusualActivity).Return([]*domain.SecurityPolicy{parentPolicy}, nil)
+	service := NewSecurityPolicyService(policyRepo, new(MockToolSurfaceAlertRepository), tree)
+	ctx := context.Background()
+
+	// A lower share flags fewer hours, so the parent's share applies
+	policy, err := service.FindApplicablePolicy(ctx, agent, domain.PolicyTypeUnusualActivity)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.Equal(t, 0.05, newActivityDetectionConfig(policy).offHoursShare)
+
+	err = service.CreatePolicy(ctx, &domain.SecurityPolicy{
+		OrganizationID: payments.ID, Name: "Fewer off-hours alerts", PolicyType: domain.PolicyTypeUnusualActivity,
+		EnforcementAction: domain.EnforcementAlertOnly, AppliesTo: "all",
+		Rules: map[string]interface{}{"off_hours_share": 0.01},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "off_hours_share may not be below 0.05")
+}
@@ -0,0 +1,328 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// ErrQuotaExceeded is wrapped by every QuotaExceededError
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaExceededError is returned when an organization has used up a quota of its plan
+type QuotaExceededError struct {
+	Resource domain.QuotaResource
+	Plan     string
+	Limit    int
+	Used     int
+}
+
+func (e *QuotaExceededError) Error() string {
+	if e.Resource == domain.QuotaVerifications {
+		return fmt.Sprintf("quota exceeded: the %s plan allows %d verifications per month and %d have been used", e.Plan, e.Limit, e.Used)
+	}
+	return fmt.Sprintf("quota exceeded: the %s plan allows %d %s and the organization has %d", e.Plan, e.Limit, quotaResourceName(e.Resource), e.Used)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+func quotaResourceName(resource domain.QuotaResource) string {
+	if resource == domain.QuotaMCPServers {
+		return "MCP servers"
+	}
+	return string(resource)
+}
+
+// QuotaService enforces the agent, user, MCP server and monthly verification quotas of each
+// organization's plan and warns admins with an alert once usage reaches 80% of a quota.
+// Child organizations share the quotas of their top-level organization: usage is counted
+// across its whole subtree against its limits.
+// Quota checks fail open: usage that cannot be counted never blocks a request.
+type QuotaService struct {
+	orgRepo       domain.OrganizationRepository
+	quotaRepo     domain.QuotaRepository
+	alertRepo     domain.AlertRepository
+	hierarchyRepo domain.OrganizationHierarchyRepository // Optional; nil counts each organization on its own
+	alerted       sync.Map                               // quota alert resource ID -> struct{}, alerts already raised
+	now           func() time.Time
+}
+
+// NewQuotaService creates a new quota service
+func NewQuotaService(
+	orgRepo domain.OrganizationRepository,
+	quotaRepo domain.QuotaRepository,
+	alertRepo domain.AlertRepository,
+	hierarchyRepo domain.OrganizationHierarchyRepository,
+) *QuotaService {
+	return &QuotaService{
+		orgRepo:       orgRepo,
+		quotaRepo:     quotaRepo,
+		alertRepo:     alertRepo,
+		hierarchyRepo: hierarchyRepo,
+		now:           func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// quotaOwner returns the organization whose plan governs orgID's quotas, its top-level
+// organization, and the IDs of every organization sharing them
+func (s *QuotaService) quotaOwner(orgID uuid.UUID) (*domain.Organization, []uuid.UUID, error) {
+	if s.hierarchyRepo == nil {
+		org, err := s.orgRepo.GetByID(orgID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return org, []uuid.UUID{org.ID}, nil
+	}
+
+	rootID := orgID
+	ancestors, err := s.hierarchyRepo.GetAncestorIDs(orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ancestors) > 0 {
+		rootID = ancestors[len(ancestors)-1]
+	}
+	root, err := s.orgRepo.GetByID(rootID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subtree, err := s.hierarchyRepo.GetSubtree(rootID)
+	if err != nil {
+		return nil, nil, err
+	}
+	orgIDs := make([]uuid.UUID, 0, len(subtree))
+	for _, org := range subtree {
+		orgIDs = append(orgIDs, org.ID)
+	}
+	if len(orgIDs) == 0 {
+		orgIDs = append(orgIDs, root.ID)
+	}
+	return root, orgIDs, nil
+}
+
+// quotaLimit returns the organization's limit for a resource; zero means unlimited
+func quotaLimit(org *domain.Organization, resource domain.QuotaResource) int {
+	plan := domain.QuotaForPlan(org.PlanType)
+	switch resource {
+	case domain.QuotaAgents:
+		if org.MaxAgents > 0 {
+			return org.MaxAgents
+		}
+		return plan.MaxAgents
+	case domain.QuotaUsers:
+		if org.MaxUsers > 0 {
+			return org.MaxUsers
+		}
+		return plan.MaxUsers
+	case domain.QuotaMCPServers:
+		return plan.MaxMCPServers
+	case domain.QuotaVerifications:
+		return plan.MonthlyVerifications
+	}
+	return 0
+}
+
+func monthStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// countUsage sums the usage of a resource across organizations
+func (s *QuotaService) countUsage(orgIDs []uuid.UUID, resource domain.QuotaResource, now time.Time) (int, error) {
+	total := 0
+	for _, orgID := range orgIDs {
+		used, err := s.countOrganizationUsage(orgID, resource, now)
+		if err != nil {
+			return 0, err
+		}
+		total += used
+	}
+	return total, nil
+}
+
+func (s *QuotaService) countOrganizationUsage(orgID uuid.UUID, resource domain.QuotaResource, now time.Time) (int, error) {
+	switch resource {
+	case domain.QuotaAgents:
+		return s.quotaRepo.CountAgents(orgID)
+	case domain.QuotaUsers:
+		return s.quotaRepo.CountUsers(orgID)
+	case domain.QuotaMCPServers:
+		return s.quotaRepo.CountMCPServers(orgID)
+	case domain.QuotaVerifications:
+		return s.quotaRepo.CountVerifications(orgID, monthStart(now))
+	}
+	return 0, fmt.Errorf("unknown quota resource: %s", resource)
+}
+
+func (s *QuotaService) usage(org *domain.Organization, orgIDs []uuid.UUID, resource domain.QuotaResource, now time.Time) (*domain.QuotaUsage, error) {
+	used, err := s.countUsage(orgIDs, resource, now)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &domain.QuotaUsage{
+		Resource: resource,
+		Used:     used,
+		Limit:    quotaLimit(org, resource),
+	}
+	if usage.Limit > 0 {
+		usage.Percent = float64(used) / float64(usage.Limit) * 100
+		usage.Warning = float64(used) >= float64(usage.Limit)*domain.QuotaWarningRatio
+		usage.Exceeded = used >= usage.Limit
+	}
+	if resource == domain.QuotaVerifications {
+		start := monthStart(now)
+		resetsAt := start.AddDate(0, 1, 0)
+		usage.Period = start.Format("2006-01")
+		usage.ResetsAt = &resetsAt
+	}
+
+	return usage, nil
+}
+
+// GetUsage reports the organization's usage of every quota; child organizations report the
+// usage of the quotas they share with their top-level organization
+func (s *QuotaService) GetUsage(ctx context.Context, orgID uuid.UUID) (*domain.OrganizationUsage, error) {
+	org, orgIDs, err := s.quotaOwner(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("organization not found: %w", err)
+	}
+
+	now := s.now()
+	report := &domain.OrganizationUsage{
+		OrganizationID: orgID,
+		Plan:           org.PlanType,
+		Quotas:         make([]*domain.QuotaUsage, 0, len(domain.QuotaResources)),
+	}
+	for _, resource := range domain.QuotaResources {
+		usage, err := s.usage(org, orgIDs, resource, now)
+		if err != nil {
+			return nil, err
+		}
+		report.Quotas = append(report.Quotas, usage)
+	}
+
+	return report, nil
+}
+
+// CheckQuota returns a QuotaExceededError when the organization may not add one more of a
+// resource (or make one more verification this month). When one more brings usage to the
+// warning ratio or the limit, admins of the top-level organization are alerted once.
+func (s *QuotaService) CheckQuota(ctx context.Context, orgID uuid.UUID, resource domain.QuotaResource) error {
+	org, orgIDs, err := s.quotaOwner(orgID)
+	if err != nil {
+		fmt.Printf("⚠️  Quota check of %s skipped for organization %s: %v\n", resource, orgID, err)
+		return nil
+	}
+	limit := quotaLimit(org, resource)
+	if limit <= 0 {
+		return nil
+	}
+
+	now := s.now()
+	used, err := s.countUsage(orgIDs, resource, now)
+	if err != nil {
+		fmt.Printf("⚠️  Quota check of %s skipped for organization %s: %v\n", resource, orgID, err)
+		return nil
+	}
+
+	if used >= limit {
+		s.raiseAlert(org, resource, used, limit, now)
+		return &QuotaExceededError{Resource: resource, Plan: org.PlanType, Limit: limit, Used: used}
+	}
+
+	if float64(used+1) >= float64(limit)*domain.QuotaWarningRatio {
+		s.raiseAlert(org, resource, used+1, limit, now)
+	} else if resource != domain.QuotaVerifications {
+		// Usage dropped below the warning ratio; warn again if it climbs back
+		s.alerted.Delete(quotaAlertID(org.ID, resource, "", "warning"))
+		s.alerted.Delete(quotaAlertID(org.ID, resource, "", "limit"))
+	}
+
+	return nil
+}
+
+// RecordVerification meters a verify-action call against the monthly verification quota
+func (s *QuotaService) RecordVerification(ctx context.Context, orgID uuid.UUID, success bool) {
+	if err := s.quotaRepo.RecordVerification(orgID, s.now(), success); err != nil {
+		fmt.Printf("⚠️  Failed to meter verification for organization %s: %v\n", orgID, err)
+	}
+}
+
+// quotaAlertID identifies the alert raised for a quota level; monthly quotas alert once per period
+func quotaAlertID(orgID uuid.UUID, resource domain.QuotaResource, period, level string) uuid.UUID {
+	return uuid.NewSHA1(orgID, []byte(fmt.Sprintf("quota:%s:%s:%s", resource, period, level)))
+}
+
+func (s *QuotaService) raiseAlert(org *domain.Organization, resource domain.QuotaResource, used, limit int, now time.Time) {
+	period, window := "", ""
+	if resource == domain.QuotaVerifications {
+		period = monthStart(now).Format("2006-01")
+		window = " this month"
+	}
+
+	level, severity := "warning", domain.AlertSeverityWarning
+	title := fmt.Sprintf("%s quota at %d%%", quotaResourceLabel(resource), used*100/limit)
+	if used >= limit {
+		level, severity = "limit", domain.AlertSeverityHigh
+		title = fmt.Sprintf("%s quota reached", quotaResourceLabel(resource))
+	}
+
+	alertID := quotaAlertID(org.ID, resource, period, level)
+	if _, raised := s.alerted.LoadOrStore(alertID, struct{}{}); raised {
+		return
+	}
+
+	existing, err := s.alertRepo.GetUnacknowledged(org.ID)
+	if err != nil {
+		s.alerted.Delete(alertID)
+		fmt.Printf("⚠️  Failed to check quota alerts for organization %s: %v\n", org.ID, err)
+		return
+	}
+	for _, alert := range existing {
+		if alert.AlertType == domain.AlertQuotaWarning && alert.ResourceID == alertID {
+			return
+		}
+	}
+
+	alert := &domain.Alert{
+		ID:             uuid.New(),
+		OrganizationID: org.ID,
+		AlertType:      domain.AlertQuotaWarning,
+		Severity:       severity,
+		Title:          title,
+		Description: fmt.Sprintf(
+			"The organization has used %d of the %d %s its %s plan allows%s. "+
+				"Requests beyond the limit are rejected; upgrade the plan or free up capacity.",
+			used, limit, quotaResourceName(resource), org.PlanType, window,
+		),
+		ResourceType: "organization_quota",
+		ResourceID:   alertID,
+		CreatedAt:    now,
+	}
+	if err := s.alertRepo.Create(alert); err != nil {
+		s.alerted.Delete(alertID)
+		fmt.Printf("⚠️  Failed to create quota alert for organization %s: %v\n", org.ID, err)
+		return
+	}
+	fmt.Printf("📈 Quota alert for organization %s: %s (%d/%d)\n", org.ID, resource, used, limit)
+}
+
+func quotaResourceLabel(resource domain.QuotaResource) string {
+	switch resource {
+	case domain.QuotaAgents:
+		return "Agent"
+	case domain.QuotaUsers:
+		return "User"
+	case domain.QuotaMCPServers:
+		return "MCP server"
+	}
+	return "Monthly verification"
+}
@@ -0,0 +1,205 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQuotaRepository returns fixed counts and records metered verifications
+type fakeQuotaRepository struct {
+	agents, users, mcpServers, verifications int
+	verificationsSince                       time.Time
+	recorded                                 []bool
+	err                                      error
+}
+
+func (r *fakeQuotaRepository) CountAgents(orgID uuid.UUID) (int, error) { return r.agents, r.err }
+func (r *fakeQuotaRepository) CountUsers(orgID uuid.UUID) (int, error)  { return r.users, r.err }
+func (r *fakeQuotaRepository) CountMCPServers(orgID uuid.UUID) (int, error) {
+	return r.mcpServers, r.err
+}
+
+func (r *fakeQuotaRepository) CountVerifications(orgID uuid.UUID, since time.Time) (int, error) {
+	r.verificationsSince = since
+	return r.verifications, r.err
+}
+
+func (r *fakeQuotaRepository) RecordVerification(orgID uuid.UUID, at time.Time, success bool) error {
+	r.recorded = append(r.recorded, success)
+	r.verifications++
+	return nil
+}
+
+// createTestQuotaOrganizationRepository creates an organization repository serving only org
+func createTestQuotaOrganizationRepository(org *domain.Organization) *MockOrganizationRepository {
+	orgRepo := new(MockOrganizationRepository)
+	orgRepo.On("GetByID", org.ID).Return(org, nil)
+	orgRepo.On("GetByID", mock.Anything).Return(nil, fmt.Errorf("organization not found"))
+	return orgRepo
+}
+
+func newTestQuotaService(org *domain.Organization, quotas *fakeQuotaRepository) (*QuotaService, *fakeAlertRepository) {
+	alerts := &fakeAlertRepository{}
+	service := NewQuotaService(createTestQuotaOrganizationRepository(org), quotas, alerts, nil)
+	service.now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+	return service, alerts
+}
+
+func TestQuotaService_CheckQuotaRejectsAtLimit(t *testing.T) {
+	org := &domain.Organization{ID: uuid.New(), PlanType: "free", MaxAgents: 5}
+	quotas := &fakeQuotaRepository{agents: 5, mcpServers: 49}
+	service, alerts := newTestQuotaService(org, quotas)
+	ctx := context.Background()
+
+	err := service.CheckQuota(ctx, org.ID, domain.QuotaAgents)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+	var quotaErr *QuotaExceededError
+	require.True(t, errors.As(err, &quotaErr))
+	assert.Equal(t, 5, quotaErr.Limit, "the organization's max_agents overrides the plan")
+	assert.Equal(t, 5, quotaErr.Used)
+	assert.Equal(t, "free", quotaErr.Plan)
+
+	// The 50th MCP server is allowed and reaches the limit
+	require.NoError(t, service.CheckQuota(ctx, org.ID, domain.QuotaMCPServers))
+
+	require.Len(t, alerts.alerts, 2)
+	assert.Equal(t, domain.AlertQuotaWarning, alerts.alerts[0].AlertType)
+	assert.Equal(t, domain.AlertSeverityHigh, alerts.alerts[0].Severity)
+	assert.Equal(t, "Agent quota reached", alerts.alerts[0].Title)
+	assert.Equal(t, "MCP server quota reached", alerts.alerts[1].Title)
+}
+
+func TestQuotaService_WarnsOnceAtEightyPercent(t *testing.T) {
+	org := &domain.Organization{ID: uuid.New(), PlanType: "free"}
+	quotas := &fakeQuotaRepository{users: 6}
+	service, alerts := newTestQuotaService(org, quotas)
+	ctx := context.Background()
+
+	// The 7th of 10 users stays below the warning ratio
+	require.NoError(t, service.CheckQuota(ctx, org.ID, domain.QuotaUsers))
+	assert.Empty(t, alerts.alerts)
+
+	quotas.users = 7
+	require.NoError(t, service.CheckQuota(ctx, org.ID, domain.QuotaUsers))
+	require.Len(t, alerts.alerts, 1)
+	assert.Equal(t, domain.AlertSeverityWarning, alerts.alerts[0].Severity)
+	assert.Equal(t, "User quota at 80%", alerts.alerts[0].Title)
+
+	quotas.users = 8
+	require.NoError(t, service.CheckQuota(ctx, org.ID, domain.QuotaUsers))
+	assert.Len(t, alerts.alerts, 1, "the warning is raised once")
+
+	// A fresh service (e.g. after a restart) finds the unacknowledged alert
+	restarted := NewQuotaService(createTestQuotaOrganizationRepository(org), quotas, alerts, nil)
+	require.NoError(t, restarted.CheckQuota(ctx, org.ID, domain.QuotaUsers))
+	assert.Len(t, alerts.alerts, 1)
+}
+
+func TestQuotaService_MonthlyVerifications(t *testing.T) {
+	org := &domain.Organization{ID: uuid.New(), PlanType: "pro"}
+	quotas := &fakeQuotaRepository{verifications: 999999}
+	service, _ := newTestQuotaService(org, quotas)
+	ctx := context.Background()
+
+	require.NoError(t, service.CheckQuota(ctx, org.ID, domain.QuotaVerifications))
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), quotas.verificationsSince)
+
+	service.RecordVerification(ctx, org.ID, false)
+	assert.Equal(t, []bool{false}, quotas.recorded)
+
+	err := service.CheckQuota(ctx, org.ID, domain.QuotaVerifications)
+	var quotaErr *QuotaExceededError
+	require.True(t, errors.As(err, &quotaErr))
+	assert.Equal(t, domain.QuotaVerifications, quotaErr.Resource)
+	assert.Contains(t, err.Error(), "1000000 verifications per month")
+}
+
+func TestQuotaService_GetUsage(t *testing.T) {
+	org := &domain.Organization{ID: uuid.New(), PlanType: "free"}
+	quotas := &fakeQuotaRepository{agents: 85, users: 2, mcpServers: 50, verifications: 1000}
+	service, _ := newTestQuotaService(org, quotas)
+
+	report, err := service.GetUsage(context.Background(), org.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "free", report.Plan)
+	require.Len(t, report.Quotas, len(domain.QuotaResources))
+
+	agents, users, mcpServers, verifications := report.Quotas[0], report.Quotas[1], report.Quotas[2], report.Quotas[3]
+	assert.Equal(t, 85.0, agents.Percent)
+	assert.True(t, agents.Warning)
+	assert.False(t, agents.Exceeded)
+	assert.False(t, users.Warning)
+	assert.True(t, mcpServers.Exceeded)
+	assert.Equal(t, 50000, verifications.Limit)
+	assert.Equal(t, "2026-10", verifications.Period)
+	require.NotNil(t, verifications.ResetsAt)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), *verifications.ResetsAt)
+
+	// Enterprise plans are unlimited
+	org.PlanType = "enterprise"
+	report, err = service.GetUsage(context.Background(), org.ID)
+	require.NoError(t, err)
+	for _, usage := range report.Quotas {
+		assert.Zero(t, usage.Limit)
+		assert.False(t, usage.Exceeded)
+	}
+}
+
+func TestQuotaService_FailsOpen(t *testing.T) {
+	org := &domain.Organization{ID: uuid.New(), PlanType: "free"}
+	service, alerts := newTestQuotaService(org, &fakeQuotaRepository{err: fmt.Errorf("connection refused")})
+
+	assert.NoError(t, service.CheckQuota(context.Background(), org.ID, domain.QuotaAgents))
+	assert.NoError(t, service.CheckQuota(context.Background(), uuid.New(), domain.QuotaAgents))
+	assert.Empty(t, alerts.alerts)
+}
+
+// fakeSubtreeQuotaRepository counts agents per organization
+type fakeSubtreeQuotaRepository struct {
+	fakeQuotaRepository
+	agentsByOrg map[uuid.UUID]int
+}
+
+func (r *fakeSubtreeQuotaRepository) CountAgents(orgID uuid.UUID) (int, error) {
+	return r.agentsByOrg[orgID], nil
+}
+
+func TestQuotaService_ChildOrganizationsShareTheRootQuota(t *testing.T) {
+	tree := newFakeOrganizationTree()
+	root := tree.add("acme", nil)
+	root.MaxAgents = 10
+	payments := tree.add("payments", root)
+	emea := tree.add("emea", payments)
+
+	quotas := &fakeSubtreeQuotaRepository{agentsByOrg: map[uuid.UUID]int{root.ID: 4, payments.ID: 3, emea.ID: 2}}
+	alerts := &fakeAlertRepository{}
+	service := NewQuotaService(tree, quotas, alerts, tree)
+	ctx := context.Background()
+
+	// 9 of the root's 10 agents are used across the subtree
+	require.NoError(t, service.CheckQuota(ctx, emea.ID, domain.QuotaAgents))
+	require.Len(t, alerts.alerts, 1)
+	assert.Equal(t, root.ID, alerts.alerts[0].OrganizationID)
+
+	quotas.agentsByOrg[emea.ID] = 3
+	err := service.CheckQuota(ctx, payments.ID, domain.QuotaAgents)
+	var exceeded *QuotaExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, 10, exceeded.Limit)
+	assert.Equal(t, 10, exceeded.Used)
+
+	usage, err := service.GetUsage(ctx, emea.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, usage.Quotas[0].Used)
+	assert.Equal(t, 10, usage.Quotas[0].Limit)
+}
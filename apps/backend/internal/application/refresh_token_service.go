@@ -0,0 +1,392 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/auth"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// sessionCacheTTL is how long a replica trusts a cached session state. Sessions signed out on
+// another replica stop working within this window.
+const sessionCacheTTL = 30 * time.Second
+
+// sessionCacheLimit bounds the session state cache; it is cleared when full
+const sessionCacheLimit = 10000
+
+type cachedSession struct {
+	active    bool
+	checkedAt time.Time
+}
+
+// RefreshTokenService issues user session tokens and rotates their refresh tokens. Each login
+// starts a family of refresh tokens; a token that is presented again after it was rotated has
+// been copied, so the whole family is revoked and the user is warned.
+type RefreshTokenService struct {
+	repo         domain.RefreshTokenRepository
+	userRepo     domain.UserRepository
+	alertRepo    domain.AlertRepository
+	emailService domain.EmailService
+	jwtService   *auth.JWTService
+	now          func() time.Time
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]cachedSession
+}
+
+// NewRefreshTokenService creates a new refresh token service
+func NewRefreshTokenService(
+	repo domain.RefreshTokenRepository,
+	userRepo domain.UserRepository,
+	alertRepo domain.AlertRepository,
+	emailService domain.EmailService,
+	jwtService *auth.JWTService,
+) *RefreshTokenService {
+	return &RefreshTokenService{
+		repo:         repo,
+		userRepo:     userRepo,
+		alertRepo:    alertRepo,
+		emailService: emailService,
+		jwtService:   jwtService,
+		now:          time.Now,
+		sessions:     map[uuid.UUID]cachedSession{},
+	}
+}
+
+// StartSession issues the tokens of a new login and records the first token of its family
+func (s *RefreshTokenService) StartSession(ctx context.Context, user *domain.User, ipAddress, userAgent string) (string, string, error) {
+	familyID := uuid.New()
+	return s.issue(user, familyID, nil, ipAddress, userAgent)
+}
+
+// Refresh rotates a refresh token. Presenting a token that was already rotated revokes its
+// family and returns ErrRefreshTokenReused.
+func (s *RefreshTokenService) Refresh(ctx context.Context, refreshToken, ipAddress, userAgent string) (string, string, error) {
+	claims, err := s.jwtService.ValidateToken(refreshToken)
+	if err != nil || claims.SessionID == "" {
+		return "", "", ErrInvalidRefreshToken
+	}
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	record, err := s.repo.GetByID(tokenID)
+	if err != nil {
+		return "", "", err
+	}
+	if record == nil ||
+		record.FamilyID.String() != claims.SessionID ||
+		subtle.ConstantTimeCompare([]byte(record.TokenHash), []byte(hashRefreshToken(refreshToken))) != 1 {
+		return "", "", ErrInvalidRefreshToken
+	}
+	if record.RevokedAt != nil {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	if record.RotatedAt != nil {
+		s.handleReuse(record, ipAddress, userAgent)
+		return "", "", ErrRefreshTokenReused
+	}
+	rotated, err := s.repo.MarkRotated(record.ID, s.now())
+	if err != nil {
+		return "", "", err
+	}
+	if !rotated {
+		// Another request rotated the token between the lookup and now
+		s.handleReuse(record, ipAddress, userAgent)
+		return "", "", ErrRefreshTokenReused
+	}
+
+	// Email and role are re-read so changes take effect on the next refresh
+	user, err := s.userRepo.GetByID(record.UserID)
+	if err != nil || user == nil || user.Status != domain.UserStatusActive {
+		_ = s.revokeFamily(record.FamilyID, "user is no longer active")
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	parentID := record.ID
+	return s.issue(user, record.FamilyID, &parentID, ipAddress, userAgent)
+}
+
+// issue signs a token pair for a family and records the refresh token
+func (s *RefreshTokenService) issue(user *domain.User, familyID uuid.UUID, parentID *uuid.UUID, ipAddress, userAgent string) (string, string, error) {
+	tokenID := familyID
+	if parentID != nil {
+		tokenID = uuid.New()
+	}
+
+	accessToken, refreshToken, err := s.jwtService.GenerateSessionTokenPair(
+		user.ID.String(),
+		user.OrganizationID.String(),
+		user.Email,
+		string(user.Role),
+		familyID.String(),
+		tokenID.String(),
+	)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := s.now()
+	var ip, ua *string
+	if ipAddress != "" {
+		ip = &ipAddress
+	}
+	if userAgent != "" {
+		ua = &userAgent
+	}
+	record := &domain.RefreshToken{
+		ID:             tokenID,
+		FamilyID:       familyID,
+		ParentID:       parentID,
+		UserID:         user.ID,
+		OrganizationID: user.OrganizationID,
+		TokenHash:      hashRefreshToken(refreshToken),
+		IPAddress:      ip,
+		UserAgent:      ua,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.jwtService.RefreshTokenExpiry()),
+	}
+	if err := s.repo.Create(record); err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// handleReuse revokes a family whose rotated token was replayed, raises a security alert and
+// tells the user their session was signed out
+func (s *RefreshTokenService) handleReuse(record *domain.RefreshToken, ipAddress, userAgent string) {
+	fmt.Printf("🚨 Refresh token reuse detected for user %s (session %s) from %s\n", record.UserID, record.FamilyID, ipAddress)
+
+	if err := s.revokeFamily(record.FamilyID, "refresh token reuse detected"); err != nil {
+		fmt.Printf("⚠️  Failed to revoke session %s: %v\n", record.FamilyID, err)
+	}
+
+	user, err := s.userRepo.GetByID(record.UserID)
+	if err != nil || user == nil {
+		fmt.Printf("⚠️  Failed to load user %s for refresh token reuse alert: %v\n", record.UserID, err)
+		return
+	}
+	device := "Unknown device"
+	if record.UserAgent != nil {
+		device = describeDevice(*record.UserAgent)
+	}
+
+	alert := &domain.Alert{
+		ID:             uuid.New(),
+		OrganizationID: record.OrganizationID,
+		AlertType:      domain.AlertRefreshTokenReuse,
+		Severity:       domain.AlertSeverityHigh,
+		Title:          fmt.Sprintf("Refresh token reuse for %s", user.Email),
+		Description: fmt.Sprintf(
+			"A refresh token of %s was presented after it had already been rotated, which means it was copied. "+
+				"The session (%s) has been signed out. Request from %s (%s).",
+			user.Email, device, ipAddress, userAgent,
+		),
+		ResourceType: "user",
+		ResourceID:   user.ID,
+		CreatedAt:    s.now(),
+	}
+	if err := s.alertRepo.Create(alert); err != nil {
+		fmt.Printf("⚠️  Failed to create refresh token reuse alert: %v\n", err)
+	}
+
+	if s.emailService == nil {
+		return
+	}
+
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:3000"
+	}
+	name := user.Name
+	if name == "" {
+		name = user.Email
+	}
+	templateData := domain.EmailTemplateData{
+		UserName:     name,
+		UserEmail:    user.Email,
+		DashboardURL: frontendURL + "/dashboard/settings/sessions",
+		Timestamp:    s.now(),
+		CustomData: map[string]interface{}{
+			"Device":    device,
+			"IPAddress": ipAddress,
+		},
+	}
+	if err := s.emailService.SendTemplatedEmail(domain.TemplateSessionRevoked, user.Email, templateData); err != nil {
+		fmt.Printf("⚠️  Failed to send session revoked email to %s: %v\n", user.Email, err)
+	}
+}
+
+// ListSessions returns the user's signed-in devices. currentSessionID marks the caller's own.
+func (s *RefreshTokenService) ListSessions(ctx context.Context, userID uuid.UUID, currentSessionID string) ([]*domain.UserSession, error) {
+	sessions, err := s.repo.ListSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.DeviceName = "Unknown device"
+		if session.UserAgent != nil {
+			session.DeviceName = describeDevice(*session.UserAgent)
+		}
+		session.Current = session.ID.String() == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's devices out
+func (s *RefreshTokenService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	// The family ID is the ID of its first token
+	root, err := s.repo.GetByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if root == nil || root.UserID != userID || root.FamilyID != root.ID {
+		return ErrSessionNotFound
+	}
+
+	return s.revokeFamily(sessionID, "signed out remotely")
+}
+
+// Logout signs out the session a token belongs to. Tokens without a session are ignored.
+func (s *RefreshTokenService) Logout(ctx context.Context, token string) error {
+	claims, err := s.jwtService.ValidateToken(token)
+	if err != nil || claims.SessionID == "" {
+		return nil
+	}
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil
+	}
+
+	return s.revokeFamily(sessionID, "signed out")
+}
+
+// IsSessionActive implements auth.SessionChecker
+func (s *RefreshTokenService) IsSessionActive(sessionID string) (bool, error) {
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	cached, ok := s.sessions[id]
+	s.mu.Unlock()
+	if ok && s.now().Sub(cached.checkedAt) < sessionCacheTTL {
+		return cached.active, nil
+	}
+
+	active, err := s.repo.IsFamilyActive(id)
+	if err != nil {
+		return false, err
+	}
+	s.cacheSession(id, active)
+
+	return active, nil
+}
+
+// CleanupExpired removes refresh token families that have fully expired
+func (s *RefreshTokenService) CleanupExpired(ctx context.Context) error {
+	deleted, err := s.repo.DeleteExpired(s.now())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		fmt.Printf("🧹 Deleted %d expired refresh tokens\n", deleted)
+	}
+	return nil
+}
+
+func (s *RefreshTokenService) revokeFamily(familyID uuid.UUID, reason string) error {
+	if err := s.repo.RevokeFamily(familyID, reason, s.now()); err != nil {
+		return err
+	}
+	s.cacheSession(familyID, false)
+	return nil
+}
+
+func (s *RefreshTokenService) cacheSession(id uuid.UUID, active bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sessions) >= sessionCacheLimit {
+		s.sessions = map[uuid.UUID]cachedSession{}
+	}
+	s.sessions[id] = cachedSession{active: active, checkedAt: s.now()}
+}
+
+// hashRefreshToken returns the hex SHA-256 of a refresh token, as stored
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// describeDevice turns a User-Agent into a short label such as "Chrome on macOS"
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	browser := ""
+	for _, candidate := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"python-requests", "Python SDK"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			browser = candidate.name
+			break
+		}
+	}
+
+	platform := ""
+	for _, candidate := range []struct{ token, name string }{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			platform = candidate.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	}
+	if len(userAgent) > 60 {
+		return userAgent[:60]
+	}
+	return userAgent
+}
@@ -0,0 +1,237 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRefreshTokenRepository keeps refresh tokens in memory
+type fakeRefreshTokenRepository struct {
+	tokens map[uuid.UUID]*domain.RefreshToken
+}
+
+func (r *fakeRefreshTokenRepository) Create(token *domain.RefreshToken) error {
+	copied := *token
+	r.tokens[token.ID] = &copied
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) GetByID(id uuid.UUID) (*domain.RefreshToken, error) {
+	if token, ok := r.tokens[id]; ok {
+		copied := *token
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeRefreshTokenRepository) MarkRotated(id uuid.UUID, at time.Time) (bool, error) {
+	token, ok := r.tokens[id]
+	if !ok || token.RotatedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+	token.RotatedAt = &at
+	return true, nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeFamily(familyID uuid.UUID, reason string, at time.Time) error {
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+			token.RevokeReason = &reason
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) IsFamilyActive(familyID uuid.UUID) (bool, error) {
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil && token.ExpiresAt.After(time.Now()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRefreshTokenRepository) ListSessions(userID uuid.UUID) ([]*domain.UserSession, error) {
+	sessions := []*domain.UserSession{}
+	for _, token := range r.tokens {
+		if token.UserID != userID || token.RotatedAt != nil || token.RevokedAt != nil {
+			continue
+		}
+		sessions = append(sessions, &domain.UserSession{
+			ID:           token.FamilyID,
+			IPAddress:    token.IPAddress,
+			UserAgent:    token.UserAgent,
+			CreatedAt:    r.tokens[token.FamilyID].CreatedAt,
+			LastActiveAt: token.CreatedAt,
+			ExpiresAt:    token.ExpiresAt,
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastActiveAt.After(sessions[j].LastActiveAt) })
+	return sessions, nil
+}
+
+func (r *fakeRefreshTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// createTestRefreshTokenService creates a refresh token service whose tokens are validated by the
+// returned JWT service
+func createTestRefreshTokenService(t *testing.T, users ...*domain.User) (*RefreshTokenService, *fakeRefreshTokenRepository, *fakeAlertRepository, *MockEmailService, *auth.JWTService) {
+	t.Setenv("JWT_SECRET", "refresh-token-test-secret-0123456789")
+
+	userRepo := &fakeUserRepository{users: map[uuid.UUID]*domain.User{}}
+	for _, user := range users {
+		userRepo.users[user.ID] = user
+	}
+	repo := &fakeRefreshTokenRepository{tokens: map[uuid.UUID]*domain.RefreshToken{}}
+	alertRepo := &fakeAlertRepository{}
+	emailService := new(MockEmailService)
+	jwtService := auth.NewJWTService()
+
+	service := NewRefreshTokenService(repo, userRepo, alertRepo, emailService, jwtService)
+	jwtService.UseSessionChecker(service)
+	return service, repo, alertRepo, emailService, jwtService
+}
+
+// createTestSessionUser creates an active member of the organization
+func createTestSessionUser(orgID uuid.UUID, email, name string) *domain.User {
+	return &domain.User{ID: uuid.New(), OrganizationID: orgID, Email: email, Name: name, Role: domain.RoleMember, Status: domain.UserStatusActive}
+}
+
+const testUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0 Safari/537.36"
+
+func TestRefreshTokenService_ReuseRevokesFamily(t *testing.T) {
+	user := createTestSessionUser(uuid.New(), "alice@acme.example.com", "Alice")
+	service, repo, alertRepo, emailService, jwtService := createTestRefreshTokenService(t, user)
+	emailService.On("SendTemplatedEmail", domain.TemplateSessionRevoked, "alice@acme.example.com", mock.Anything).Return(nil)
+	ctx := context.Background()
+
+	_, first, err := service.StartSession(ctx, user, "198.51.100.7", testUserAgent)
+	require.NoError(t, err)
+
+	// Rotation issues a child of the same family and retires the presented token
+	access, second, err := service.Refresh(ctx, first, "198.51.100.7", testUserAgent)
+	require.NoError(t, err)
+	claims, err := jwtService.ValidateToken(access)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@acme.example.com", claims.Email)
+
+	secondClaims, err := jwtService.ValidateToken(second)
+	require.NoError(t, err)
+	record := repo.tokens[uuid.MustParse(secondClaims.ID)]
+	require.NotNil(t, record.ParentID)
+	assert.Equal(t, record.FamilyID, *record.ParentID)
+	assert.Equal(t, claims.SessionID, record.FamilyID.String())
+
+	// Replaying the rotated token signs the whole family out
+	_, _, err = service.Refresh(ctx, first, "203.0.113.9", "curl/8.4.0")
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+	for _, token := range repo.tokens {
+		assert.NotNil(t, token.RevokedAt)
+	}
+
+	require.Len(t, alertRepo.alerts, 1)
+	alert := alertRepo.alerts[0]
+	assert.Equal(t, domain.AlertRefreshTokenReuse, alert.AlertType)
+	assert.Equal(t, user.ID, alert.ResourceID)
+	assert.Contains(t, alert.Description, "Chrome on macOS")
+	emailService.AssertNumberOfCalls(t, "SendTemplatedEmail", 1)
+
+	// The legitimate client's newest tokens stop working too
+	_, _, err = service.Refresh(ctx, second, "198.51.100.7", testUserAgent)
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+	_, err = jwtService.ValidateToken(access)
+	require.Error(t, err)
+}
+
+func TestRefreshTokenService_ImmediateReplayIsReuse(t *testing.T) {
+	user := createTestSessionUser(uuid.New(), "alice@acme.example.com", "Alice")
+	service, repo, alertRepo, emailService, _ := createTestRefreshTokenService(t, user)
+	emailService.On("SendTemplatedEmail", domain.TemplateSessionRevoked, "alice@acme.example.com", mock.Anything).Return(nil)
+	ctx := context.Background()
+
+	_, first, err := service.StartSession(ctx, user, "198.51.100.7", testUserAgent)
+	require.NoError(t, err)
+	_, _, err = service.Refresh(ctx, first, "198.51.100.7", testUserAgent)
+	require.NoError(t, err)
+
+	// There is no grace period, even for the same client a moment later
+	_, _, err = service.Refresh(ctx, first, "198.51.100.7", testUserAgent)
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+	for _, token := range repo.tokens {
+		assert.NotNil(t, token.RevokedAt)
+	}
+	assert.Len(t, alertRepo.alerts, 1)
+}
+
+func TestRefreshTokenService_RejectsUnrecordedTokens(t *testing.T) {
+	user := createTestSessionUser(uuid.New(), "alice@acme.example.com", "Alice")
+	service, _, alertRepo, _, jwtService := createTestRefreshTokenService(t, user)
+	ctx := context.Background()
+
+	// Access tokens and session-less refresh tokens are not recorded refresh tokens
+	access, _, err := service.StartSession(ctx, user, "198.51.100.7", testUserAgent)
+	require.NoError(t, err)
+	_, _, err = service.Refresh(ctx, access, "198.51.100.7", testUserAgent)
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	legacy, err := jwtService.GenerateRefreshToken(user.ID.String(), user.OrganizationID.String())
+	require.NoError(t, err)
+	_, _, err = service.Refresh(ctx, legacy, "198.51.100.7", testUserAgent)
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	assert.Empty(t, alertRepo.alerts)
+}
+
+func TestRefreshTokenService_ListAndRevokeSessions(t *testing.T) {
+	user := createTestSessionUser(uuid.New(), "alice@acme.example.com", "Alice")
+	other := createTestSessionUser(user.OrganizationID, "bob@acme.example.com", "Bob")
+	service, _, _, _, jwtService := createTestRefreshTokenService(t, user, other)
+	ctx := context.Background()
+
+	laptopAccess, _, err := service.StartSession(ctx, user, "198.51.100.7", testUserAgent)
+	require.NoError(t, err)
+	phoneAccess, _, err := service.StartSession(ctx, user, "198.51.100.8", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 Version/17.5 Mobile/15E148 Safari/604.1")
+	require.NoError(t, err)
+	_, _, err = service.StartSession(ctx, other, "198.51.100.9", testUserAgent)
+	require.NoError(t, err)
+
+	laptop, err := jwtService.ValidateToken(laptopAccess)
+	require.NoError(t, err)
+	phone, err := jwtService.ValidateToken(phoneAccess)
+	require.NoError(t, err)
+
+	sessions, err := service.ListSessions(ctx, user.ID, laptop.SessionID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	devices := map[string]bool{}
+	for _, session := range sessions {
+		devices[session.DeviceName] = session.Current
+	}
+	assert.Equal(t, map[string]bool{"Chrome on macOS": true, "Safari on iOS": false}, devices)
+
+	// Users can only sign out their own sessions
+	phoneSessionID := uuid.MustParse(phone.SessionID)
+	require.ErrorIs(t, service.RevokeSession(ctx, other.ID, phoneSessionID), ErrSessionNotFound)
+
+	require.NoError(t, service.RevokeSession(ctx, user.ID, phoneSessionID))
+	_, err = jwtService.ValidateToken(phoneAccess)
+	require.Error(t, err)
+	_, err = jwtService.ValidateToken(laptopAccess)
+	require.NoError(t, err)
+
+	sessions, err = service.ListSessions(ctx, user.ID, laptop.SessionID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.True(t, sessions[0].Current)
+}
@@ -38,6 +38,8 @@ type RegistrationService struct {
 	orgRepo          domain.OrganizationRepository
 	auditService     *AuditService
 	emailService     domain.EmailService
+	actionCatalog    *ActionCatalogService
+	quotaService     *QuotaService
 }
 
 func NewRegistrationService(
@@ -46,6 +48,8 @@ func NewRegistrationService(
 	orgRepo domain.OrganizationRepository,
 	auditService *AuditService,
 	emailService domain.EmailService,
+	actionCatalog *ActionCatalogService,
+	quotaService *QuotaService,
 ) *RegistrationService {
 	return &RegistrationService{
 		registrationRepo: registrationRepo,
@@ -53,6 +57,8 @@ func NewRegistrationService(
 		orgRepo:          orgRepo,
 		auditService:     auditService,
 		emailService:     emailService,
+		actionCatalog:    actionCatalog,
+		quotaService:     quotaService,
 	}
 }
 
@@ -301,6 +307,13 @@ func (s *RegistrationService) ApproveRegistrationRequest(
 		return nil, fmt.Errorf("failed to find or create organization: %w", err)
 	}
 
+	// Enforce the plan's user quota before the request is marked approved
+	if s.quotaService != nil {
+		if err := s.quotaService.CheckQuota(ctx, targetOrgID, domain.QuotaUsers); err != nil {
+			return nil, err
+		}
+	}
+
 	// Approve request
 	req.Approve(reviewerID)
 	if err := s.registrationRepo.UpdateRegistrationRequest(ctx, req); err != nil {
@@ -645,6 +658,13 @@ func (s *RegistrationService) findOrCreateOrganization(ctx context.Context, doma
 	}
 
 	fmt.Printf("✅ Created new organization: %s (ID: %s)\n", domainName, newOrg.ID)
+
+	// Seed the default action risk catalog; if this fails it is seeded on first use
+	if s.actionCatalog != nil {
+		if _, err := s.actionCatalog.SeedDefaults(ctx, newOrg.ID); err != nil {
+			fmt.Printf("⚠️  Failed to seed action catalog for organization %s: %v\n", newOrg.ID, err)
+		}
+	}
 	return newOrg.ID, nil
 }
 
@@ -660,6 +680,11 @@ func (s *RegistrationService) shouldAutoApproveFirstUser(ctx context.Context, em
 		return true
 	}
 
+	// Child organizations get their admins from the parent organization
+	if org.ParentID != nil {
+		return false
+	}
+
 	// Organization exists - check if it has any users
 	existingUsers, err := s.userRepo.GetByOrganization(org.ID)
 	if err != nil {
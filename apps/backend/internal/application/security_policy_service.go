@@ -10,22 +10,213 @@ import (
 )
 
 // SecurityPolicyService handles security policy evaluation and management
+// Enabled, inheritable policies of ancestor organizations also apply to an organization's
+// agents; they set a floor its own policies can tighten but not loosen. The floor is applied
+// when policies are evaluated, so rules a child omits and parents tightened later still hold.
 type SecurityPolicyService struct {
-	policyRepo domain.SecurityPolicyRepository
-	alertRepo  domain.AlertRepository
+	policyRepo    domain.SecurityPolicyRepository
+	alertRepo     domain.AlertRepository
+	hierarchyRepo domain.OrganizationHierarchyRepository // Optional; nil disables inheritance
 }
 
 // NewSecurityPolicyService creates a new security policy service
 func NewSecurityPolicyService(
 	policyRepo domain.SecurityPolicyRepository,
 	alertRepo domain.AlertRepository,
+	hierarchyRepo domain.OrganizationHierarchyRepository,
 ) *SecurityPolicyService {
 	return &SecurityPolicyService{
-		policyRepo: policyRepo,
-		alertRepo:  alertRepo,
+		policyRepo:    policyRepo,
+		alertRepo:     alertRepo,
+		hierarchyRepo: hierarchyRepo,
 	}
 }
 
+// policyRuleTightening lists numeric policy rules and the direction that makes them stricter:
+// -1 when a lower value is stricter, +1 when a higher value is
+var policyRuleTightening = map[string]int{
+	"data_threshold_mb":   -1,
+	"read_threshold_mb":   -1,
+	"spike_multiplier":    -1,
+	"min_spike_events":    -1,
+	"min_baseline_events": -1,
+	"min_baseline_hours":  -1,
+	"off_hours_share":     1, // Hours below this share of the baseline are off-hours
+	"trust_threshold":     1,
+}
+
+// inheritedPolicies returns the enabled, inheritable policies of the organization's ancestors
+func (s *SecurityPolicyService) inheritedPolicies(orgID uuid.UUID, policyType domain.PolicyType) ([]*domain.SecurityPolicy, error) {
+	if s.hierarchyRepo == nil {
+		return nil, nil
+	}
+
+	ancestors, err := s.hierarchyRepo.GetAncestorIDs(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch parent organizations: %w", err)
+	}
+
+	inherited := []*domain.SecurityPolicy{}
+	for _, ancestorID := range ancestors {
+		policies, err := s.policyRepo.GetByType(ancestorID, policyType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch inherited policies: %w", err)
+		}
+		for _, policy := range policies {
+			if policy.Inheritable {
+				policy.Inherited = true
+				inherited = append(inherited, policy)
+			}
+		}
+	}
+	return inherited, nil
+}
+
+// effectivePolicy returns the policy of the given type that governs the agent: the
+// highest-priority applicable policy of its organization, merged with every applicable
+// inherited policy so that the stricter enforcement action and rule values win. Returns nil
+// when no policy applies.
+func (s *SecurityPolicyService) effectivePolicy(agent *domain.Agent, policyType domain.PolicyType) (*domain.SecurityPolicy, error) {
+	policies, err := s.policyRepo.GetByType(agent.OrganizationID, policyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch policies: %w", err)
+	}
+
+	var effective *domain.SecurityPolicy
+	for _, policy := range policies {
+		if s.policyAppliesToAgent(policy, agent) {
+			effective = policy
+			break
+		}
+	}
+
+	inherited, err := s.inheritedPolicies(agent.OrganizationID, policyType)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range inherited {
+		if !s.policyAppliesToAgent(policy, agent) {
+			continue
+		}
+		if effective == nil {
+			effective = copyPolicy(policy)
+			continue
+		}
+		effective = mergeInheritedPolicy(effective, policy)
+	}
+
+	return effective, nil
+}
+
+func copyPolicy(policy *domain.SecurityPolicy) *domain.SecurityPolicy {
+	copied := *policy
+	copied.Rules = make(map[string]interface{}, len(policy.Rules))
+	for rule, value := range policy.Rules {
+		copied.Rules[rule] = value
+	}
+	return &copied
+}
+
+// mergeInheritedPolicy applies an inherited policy's floor to a policy: the stricter
+// enforcement action, the stricter value of each numeric rule, the union of list rules and
+// the parent's value for any rule the policy does not set
+func mergeInheritedPolicy(policy, parent *domain.SecurityPolicy) *domain.SecurityPolicy {
+	merged := copyPolicy(policy)
+	if parent.EnforcementAction.Strictness() > merged.EnforcementAction.Strictness() {
+		merged.Name = parent.Name
+		merged.EnforcementAction = parent.EnforcementAction
+		merged.Inherited = true
+	}
+
+	for rule, limit := range parent.Rules {
+		value, set := merged.Rules[rule]
+		if direction, numeric := policyRuleTightening[rule]; numeric {
+			limitValue, limitSet := positiveRuleValue(limit)
+			if !limitSet {
+				continue
+			}
+			current, currentSet := positiveRuleValue(value)
+			if !currentSet || (direction < 0 && limitValue < current) || (direction > 0 && limitValue > current) {
+				merged.Rules[rule] = limitValue
+				merged.Inherited = true
+			}
+			continue
+		}
+
+		if !set {
+			merged.Rules[rule] = limit
+			merged.Inherited = true
+			continue
+		}
+		if parentItems := policyRuleStrings(parent.Rules, rule); parentItems != nil {
+			items := policyRuleStrings(merged.Rules, rule)
+			union := append([]string{}, items...)
+			for _, item := range parentItems {
+				if !containsString(union, item) {
+					union = append(union, item)
+				}
+			}
+			if len(union) > len(items) {
+				merged.Rules[rule] = union
+				merged.Inherited = true
+			}
+		}
+	}
+	return merged
+}
+
+// positiveRuleValue reads a numeric rule; zero and negative values mean the rule is unset
+func positiveRuleValue(value interface{}) (float64, bool) {
+	number := policyRuleFloat(map[string]interface{}{"value": value}, "value", 0)
+	return number, number > 0
+}
+
+// policyScopesOverlap reports whether two applies_to scopes can match the same agent
+func policyScopesOverlap(a, b string) bool {
+	if a == "all" || b == "all" || a == b {
+		return true
+	}
+	aKind, _, _ := strings.Cut(a, ":")
+	bKind, _, _ := strings.Cut(b, ":")
+	// Different agent IDs or agent types never overlap; anything else might
+	return aKind != bKind || (aKind != "agent_id" && aKind != "agent_type")
+}
+
+// checkNotLooser rejects a policy that would loosen an enabled policy inherited from a
+// parent organization with the same type and an overlapping scope
+func (s *SecurityPolicyService) checkNotLooser(policy *domain.SecurityPolicy) error {
+	inherited, err := s.inheritedPolicies(policy.OrganizationID, policy.PolicyType)
+	if err != nil {
+		return err
+	}
+
+	for _, parent := range inherited {
+		if !policyScopesOverlap(parent.AppliesTo, policy.AppliesTo) {
+			continue
+		}
+		if policy.EnforcementAction.Strictness() < parent.EnforcementAction.Strictness() {
+			return fmt.Errorf("policy loosens inherited policy %q: enforcement_action must be at least %s",
+				parent.Name, parent.EnforcementAction)
+		}
+		// Rules the policy omits inherit the parent's value when policies are evaluated
+		for rule, direction := range policyRuleTightening {
+			limit, inherited := positiveRuleValue(parent.Rules[rule])
+			value, set := positiveRuleValue(policy.Rules[rule])
+			if !inherited || !set {
+				continue
+			}
+			if direction < 0 && value > limit {
+				return fmt.Errorf("policy loosens inherited policy %q: %s may not exceed %v", parent.Name, rule, limit)
+			}
+			if direction > 0 && value < limit {
+				return fmt.Errorf("policy loosens inherited policy %q: %s may not be below %v", parent.Name, rule, limit)
+			}
+		}
+	}
+
+	return nil
+}
+
 // EvaluateCapabilityViolation evaluates security policies for capability violations
 // Returns enforcement decision and whether to create an alert
 func (s *SecurityPolicyService) EvaluateCapabilityViolation(
@@ -35,45 +226,44 @@ func (s *SecurityPolicyService) EvaluateCapabilityViolation(
 	resource string,
 	auditID uuid.UUID,
 ) (shouldBlock bool, shouldAlert bool, policyName string, err error) {
-	// 1. Get active capability_violation policies for this organization
-	policies, err := s.policyRepo.GetByType(agent.OrganizationID, domain.PolicyTypeCapabilityViolation)
+	// 1. Find the capability_violation policy governing this agent, including inherited policies
+	policy, err := s.effectivePolicy(agent, domain.PolicyTypeCapabilityViolation)
 	if err != nil {
-		return false, false, "", fmt.Errorf("failed to fetch policies: %w", err)
+		return false, false, "", err
 	}
 
-	// 2. If no policies configured, use safe defaults (block + alert)
-	if len(policies) == 0 {
-		fmt.Printf("⚠️  No security policies configured for org %s, using default: block + alert\n", agent.OrganizationID)
+	// 2. No matching policy found - use safe default (block + alert)
+	if policy == nil {
+		fmt.Printf("⚠️  No matching security policy for agent %s in org %s, using default: block + alert\n", agent.Name, agent.OrganizationID)
 		return true, true, "default_policy", nil
 	}
 
-	// 3. Evaluate policies by priority (highest first)
-	for _, policy := range policies {
-		// Check if policy applies to this agent
-		if !s.policyAppliesToAgent(policy, agent) {
-			continue
-		}
-
-		// Policy matches - return enforcement action
-		fmt.Printf("✅ Security Policy '%s' triggered for agent %s (action: %s)\n",
-			policy.Name, agent.Name, policy.EnforcementAction)
-
-		switch policy.EnforcementAction {
-		case domain.EnforcementBlockAndAlert:
-			return true, true, policy.Name, nil
-		case domain.EnforcementAlertOnly:
-			return false, true, policy.Name, nil
-		case domain.EnforcementAllow:
-			return false, false, policy.Name, nil
-		default:
-			// Unknown enforcement action - use safe default
-			return true, true, policy.Name, nil
-		}
+	// 3. Policy matches - return enforcement action
+	fmt.Printf("✅ Security Policy '%s' triggered for agent %s (action: %s, inherited: %t)\n",
+		policy.Name, agent.Name, policy.EnforcementAction, policy.Inherited)
+
+	switch policy.EnforcementAction {
+	case domain.EnforcementBlockAndAlert:
+		return true, true, policy.Name, nil
+	case domain.EnforcementAlertOnly:
+		return false, true, policy.Name, nil
+	case domain.EnforcementAllow:
+		return false, false, policy.Name, nil
+	default:
+		// Unknown enforcement action - use safe default
+		return true, true, policy.Name, nil
 	}
+}
 
-	// 4. No matching policy found - use safe default (block + alert)
-	fmt.Printf("⚠️  No matching security policy for agent %s, using default: block + alert\n", agent.Name)
-	return true, true, "default_policy", nil
+// FindApplicablePolicy returns the highest-priority enabled policy of the given type that applies
+// to the agent, tightened by the applicable policies inherited from parent organizations; nil
+// when there is none
+func (s *SecurityPolicyService) FindApplicablePolicy(
+	ctx context.Context,
+	agent *domain.Agent,
+	policyType domain.PolicyType,
+) (*domain.SecurityPolicy, error) {
+	return s.effectivePolicy(agent, policyType)
 }
 
 // policyAppliesToAgent checks if a policy applies to a specific agent
@@ -122,10 +312,11 @@ func (s *SecurityPolicyService) CreateDefaultPolicies(ctx context.Context, orgID
 		Rules: map[string]interface{}{
 			"attack_patterns": []string{"echoleak", "bulk_access", "data_exfiltration"},
 		},
-		AppliesTo: "all",
-		IsEnabled: true,
-		Priority:  1000, // Highest priority
-		CreatedBy: userID,
+		AppliesTo:   "all",
+		IsEnabled:   true,
+		Priority:    1000, // Highest priority
+		Inheritable: true,
+		CreatedBy:   userID,
 	}
 
 	if err := s.policyRepo.Create(capabilityViolationPolicy); err != nil {
@@ -143,10 +334,11 @@ func (s *SecurityPolicyService) CreateDefaultPolicies(ctx context.Context, orgID
 		Rules: map[string]interface{}{
 			"trust_threshold": 0.3,
 		},
-		AppliesTo: "trust_score_below:0.3",
-		IsEnabled: true,
-		Priority:  500, // Medium priority
-		CreatedBy: userID,
+		AppliesTo:   "trust_score_below:0.3",
+		IsEnabled:   true,
+		Priority:    500, // Medium priority
+		Inheritable: true,
+		CreatedBy:   userID,
 	}
 
 	if err := s.policyRepo.Create(lowTrustPolicy); err != nil {
@@ -158,30 +350,84 @@ func (s *SecurityPolicyService) CreateDefaultPolicies(ctx context.Context, orgID
 	dataExfiltrationPolicy := &domain.SecurityPolicy{
 		OrganizationID:    orgID,
 		Name:              "Monitor Data Exfiltration",
-		Description:       "Generate alerts on suspected data exfiltration attempts (e.g., external URL fetching, bulk data access). This monitors potential data leakage. Admins can enable blocking mode to prevent these actions.",
+		Description:       "Generate alerts on suspected data exfiltration attempts (e.g., external URL fetching, bulk data access), including agents exporting more than 100MB within an hour. This monitors potential data leakage. Admins can enable blocking mode to prevent these actions.",
 		PolicyType:        domain.PolicyTypeDataExfiltration,
 		EnforcementAction: domain.EnforcementAlertOnly,
 		SeverityThreshold: domain.AlertSeverityCritical,
 		Rules: map[string]interface{}{
-			"patterns": []string{"fetch_external_url", "bulk_export", "mass_download"},
+			"patterns":          []string{"fetch_external_url", "bulk_export", "mass_download"},
+			"data_threshold_mb": 100, // Bytes exported per agent within time_window
+			"time_window":       "1h",
+			"export_actions":    []string{"data:export"}, // Denied once the threshold is exceeded (block mode only)
 		},
-		AppliesTo: "all",
-		IsEnabled: true,
-		Priority:  900, // High priority
-		CreatedBy: userID,
+		AppliesTo:   "all",
+		IsEnabled:   true,
+		Priority:    900, // High priority
+		Inheritable: true,
+		CreatedBy:   userID,
 	}
 
 	if err := s.policyRepo.Create(dataExfiltrationPolicy); err != nil {
 		return fmt.Errorf("failed to create data exfiltration policy: %w", err)
 	}
 
-	fmt.Printf("✅ Created 3 default security policies for organization %s\n", orgID)
+	// Default Policy 4: Alert on Unusual Activity
+	// NOTE: Default is alert-only. Anomalies are only flagged once an agent's baseline is learned.
+	unusualActivityPolicy := &domain.SecurityPolicy{
+		OrganizationID:    orgID,
+		Name:              "Monitor Unusual Activity",
+		Description:       "Generate alerts when an agent deviates from its learned activity baseline (request spikes, never-before-seen actions or MCP servers). Off-hours activity and new source IPs are recorded as anomalies without alerting. Admins can enable blocking mode to deny anomalous requests.",
+		PolicyType:        domain.PolicyTypeUnusualActivity,
+		EnforcementAction: domain.EnforcementAlertOnly,
+		SeverityThreshold: domain.AlertSeverityHigh,
+		Rules: map[string]interface{}{
+			"detectors":           []string{"rate_spike", "off_hours", "new_action", "new_mcp_server", "new_source_ip"},
+			"spike_multiplier":    5,
+			"min_spike_events":    20,
+			"min_baseline_events": 50,
+			"min_baseline_hours":  24,
+		},
+		AppliesTo:   "all",
+		IsEnabled:   true,
+		Priority:    800,
+		Inheritable: true,
+		CreatedBy:   userID,
+	}
+
+	if err := s.policyRepo.Create(unusualActivityPolicy); err != nil {
+		return fmt.Errorf("failed to create unusual activity policy: %w", err)
+	}
+
+	fmt.Printf("✅ Created 4 default security policies for organization %s\n", orgID)
 	return nil
 }
 
-// ListPolicies retrieves all security policies for an organization
+// ListPolicies retrieves all security policies for an organization, followed by the enabled
+// policies it inherits from parent organizations (marked inherited)
 func (s *SecurityPolicyService) ListPolicies(ctx context.Context, orgID uuid.UUID) ([]*domain.SecurityPolicy, error) {
-	return s.policyRepo.GetByOrganization(orgID)
+	policies, err := s.policyRepo.GetByOrganization(orgID)
+	if err != nil || s.hierarchyRepo == nil {
+		return policies, err
+	}
+
+	ancestors, err := s.hierarchyRepo.GetAncestorIDs(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch parent organizations: %w", err)
+	}
+	for _, ancestorID := range ancestors {
+		inherited, err := s.policyRepo.GetActiveByOrganization(ancestorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch inherited policies: %w", err)
+		}
+		for _, policy := range inherited {
+			if policy.Inheritable {
+				policy.Inherited = true
+				policies = append(policies, policy)
+			}
+		}
+	}
+
+	return policies, nil
 }
 
 // GetPolicy retrieves a security policy by ID
@@ -189,13 +435,19 @@ func (s *SecurityPolicyService) GetPolicy(ctx context.Context, id uuid.UUID) (*d
 	return s.policyRepo.GetByID(id)
 }
 
-// CreatePolicy creates a new security policy
+// CreatePolicy creates a new security policy; it may not loosen an inherited policy
 func (s *SecurityPolicyService) CreatePolicy(ctx context.Context, policy *domain.SecurityPolicy) error {
+	if err := s.checkNotLooser(policy); err != nil {
+		return err
+	}
 	return s.policyRepo.Create(policy)
 }
 
-// UpdatePolicy updates a security policy
+// UpdatePolicy updates a security policy; it may not loosen an inherited policy
 func (s *SecurityPolicyService) UpdatePolicy(ctx context.Context, policy *domain.SecurityPolicy) error {
+	if err := s.checkNotLooser(policy); err != nil {
+		return err
+	}
 	return s.policyRepo.Update(policy)
 }
 
@@ -23,6 +23,21 @@ func setupSecurityTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
 	return db, mock
 }
 
+// newSecurityScanAgentRows returns agent rows as selected by AgentRepository.GetByOrganization
+func newSecurityScanAgentRows(orgID uuid.UUID, trustScores map[string]float64) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{
+		"id", "organization_id", "name", "display_name", "description", "agent_type", "status", "version",
+		"public_key", "certificate_url", "repository_url", "documentation_url", "trust_score", "verified_at",
+		"talks_to", "created_at", "updated_at", "created_by",
+	})
+	now := time.Now().UTC()
+	for name, trustScore := range trustScores {
+		rows.AddRow(uuid.New(), orgID, name, name, "", "ai_agent", "verified", "1.0.0",
+			nil, nil, nil, nil, trustScore, nil, []byte(`[]`), now, now, uuid.New())
+	}
+	return rows
+}
+
 // TestGetThreats_AlertConversion tests threat retrieval (converts alerts to threats)
 func TestGetThreats_AlertConversion(t *testing.T) {
 	ctx := context.Background()
@@ -71,7 +86,7 @@ func TestGetThreats_AlertConversion(t *testing.T) {
 			},
 		}
 
-		mockAlertRepo.On("GetByOrganization", orgID, 10, 0).Return(alerts, nil)
+		mockAlertRepo.On("GetByOrganization", orgID, 10, 0).Return(alerts, nil).Once()
 
 		// Execute
 		threats, err := service.GetThreats(ctx, orgID, 10, 0)
@@ -178,7 +193,7 @@ func TestGetAnomalies(t *testing.T) {
 			AddRow(anomalyID2, orgID, "abnormal_traffic", "warning", "Traffic Spike",
 				"Unusual traffic volume", "agent", resourceID2, 72.3, now)
 
-		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM security_anomalies`)).
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM security_anomalies`)).
 			WithArgs(orgID, 10, 0).
 			WillReturnRows(rows)
 
@@ -207,32 +222,43 @@ func TestGetSecurityMetrics(t *testing.T) {
 	service := NewSecurityService(securityRepo, agentRepo, mockAlertRepo)
 
 	t.Run("successfully retrieves security metrics", func(t *testing.T) {
-		// Mock metrics query
-		metricsRow := sqlmock.NewRows([]string{
-			"total_threats", "active_threats", "blocked_threats", "total_anomalies",
-			"high_severity_count", "open_incidents", "average_trust_score", "security_score",
-		}).AddRow(25, 5, 20, 15, 8, 3, 75.5, 82.3)
+		// Mock threat counts (alerts; acknowledged alerts count as blocked)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*), COALESCE(SUM(CASE WHEN is_acknowledged`)).
+			WithArgs(orgID).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "blocked"}).AddRow(25, 20))
 
-		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) as total_threats`)).
+		// Mock anomaly, high severity and open incident counts
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM security_anomalies`)).
+			WithArgs(orgID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(15))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`as high_severity`)).
+			WithArgs(orgID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM security_incidents`)).
 			WithArgs(orgID).
-			WillReturnRows(metricsRow)
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		// Mock average trust score
+		dbMock.ExpectQuery(regexp.QuoteMeta(`COALESCE(AVG(trust_score), 0)`)).
+			WithArgs(orgID).
+			WillReturnRows(sqlmock.NewRows([]string{"avg"}).AddRow(75.5))
 
 		// Mock threat trend
 		trendRows := sqlmock.NewRows([]string{"date", "count"}).
-			AddRow("2025-01-01", 5).
-			AddRow("2025-01-02", 3)
+			AddRow("Jan 01", 5).
+			AddRow("Jan 02", 3)
 
-		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT DATE(created_at) as date, COUNT(*) as count`)).
+		dbMock.ExpectQuery(regexp.QuoteMeta(`TO_CHAR(DATE(created_at), 'Mon DD') as date`)).
 			WithArgs(orgID).
 			WillReturnRows(trendRows)
 
 		// Mock severity distribution
 		severityRows := sqlmock.NewRows([]string{"severity", "count"}).
-			AddRow("critical", 2).
-			AddRow("high", 6).
-			AddRow("warning", 10)
+			AddRow("Critical", 2).
+			AddRow("High", 6).
+			AddRow("Warning", 10)
 
-		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT severity, COUNT(*) as count`)).
+		dbMock.ExpectQuery(regexp.QuoteMeta(`INITCAP(severity::TEXT) as severity`)).
 			WithArgs(orgID).
 			WillReturnRows(severityRows)
 
@@ -240,7 +266,13 @@ func TestGetSecurityMetrics(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, 25, metrics.TotalThreats)
-		assert.Equal(t, 82.3, metrics.SecurityScore)
+		assert.Equal(t, 5, metrics.ActiveThreats)
+		assert.Equal(t, 75.5, metrics.AverageTrustScore)
+		// 100 - 5/25 active threats × 30 - 2 high severity × 10 - 3 open incidents × 5
+		assert.InDelta(t, 59.0, metrics.SecurityScore, 0.0001)
+		assert.Len(t, metrics.ThreatTrend, 2)
+		assert.Len(t, metrics.SeverityDistribution, 3)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
 	})
 }
 
@@ -266,13 +298,9 @@ func TestRunSecurityScan(t *testing.T) {
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		// Mock agent query for background scan
-		agentRows := sqlmock.NewRows([]string{
-			"id", "organization_id", "name", "agent_type", "trust_score",
-		}).
-			AddRow(uuid.New(), orgID, "Agent1", "ai_agent", 80.0).
-			AddRow(uuid.New(), orgID, "Agent2", "ai_agent", 90.0)
+		agentRows := newSecurityScanAgentRows(orgID, map[string]float64{"Agent1": 80.0, "Agent2": 90.0})
 
-		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM agents WHERE organization_id`)).
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM agents`)).
 			WithArgs(orgID).
 			WillReturnRows(agentRows)
 
@@ -306,15 +334,14 @@ func TestRunSecurityScan(t *testing.T) {
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		// Mock agents with various trust scores
-		agentRows := sqlmock.NewRows([]string{
-			"id", "organization_id", "name", "agent_type", "trust_score",
-		}).
-			AddRow(uuid.New(), orgID, "Low Trust Agent 1", "ai_agent", 30.0). // threat
-			AddRow(uuid.New(), orgID, "Low Trust Agent 2", "ai_agent", 45.0). // threat
-			AddRow(uuid.New(), orgID, "Medium Trust Agent", "ai_agent", 55.0). // anomaly
-			AddRow(uuid.New(), orgID, "High Trust Agent", "ai_agent", 80.0)    // ok
-
-		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM agents WHERE organization_id`)).
+		agentRows := newSecurityScanAgentRows(orgID, map[string]float64{
+			"Low Trust Agent 1":  30.0, // threat
+			"Low Trust Agent 2":  45.0, // threat
+			"Medium Trust Agent": 55.0, // anomaly
+			"High Trust Agent":   80.0, // ok
+		})
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM agents`)).
 			WithArgs(orgID).
 			WillReturnRows(agentRows)
 
@@ -355,7 +382,7 @@ func TestGetSecurityScan(t *testing.T) {
 			"anomalies_found", "vulnerabilities_found", "security_score", "started_at", "completed_at",
 		}).AddRow(scanID, orgID, "comprehensive", "completed", 3, 5, 2, 78.5, startedAt, completedAt)
 
-		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM security_scans WHERE scan_id`)).
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM security_scans`)).
 			WithArgs(scanID).
 			WillReturnRows(rows)
 
@@ -400,7 +427,7 @@ func TestGetIncidents(t *testing.T) {
 			now, now, nil, nil, "",
 		)
 
-		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM security_incidents`)).
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM security_incidents`)).
 			WithArgs(orgID, "open", 10, 0).
 			WillReturnRows(rows)
 
@@ -430,8 +457,8 @@ func TestResolveIncident(t *testing.T) {
 	service := NewSecurityService(securityRepo, agentRepo, mockAlertRepo)
 
 	t.Run("successfully resolves incident", func(t *testing.T) {
-		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE security_incidents SET status`)).
-			WithArgs("resolved", resolvedBy, notes, sqlmock.AnyArg(), incidentID).
+		dbMock.ExpectExec(`UPDATE security_incidents\s+SET status`).
+			WithArgs("resolved", sqlmock.AnyArg(), resolvedBy, notes, sqlmock.AnyArg(), incidentID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		err := service.ResolveIncident(ctx, incidentID, resolvedBy, notes)
@@ -576,7 +603,7 @@ func TestBlockThreat(t *testing.T) {
 
 	t.Run("successfully blocks threat", func(t *testing.T) {
 		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE security_threats SET is_blocked`)).
-			WithArgs(true, threatID).
+			WithArgs(threatID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		err := service.BlockThreat(ctx, threatID)
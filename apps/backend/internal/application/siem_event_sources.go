@@ -0,0 +1,245 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// The repositories below wrap the audit, alert and verification event stores so every record
+// that is successfully written is also published to the SIEM forwarder. Services keep their
+// existing dependencies and need no knowledge of SIEM delivery.
+
+type siemAuditLogRepository struct {
+	domain.AuditLogRepository
+	forwarder *SIEMForwarderService
+}
+
+// NewSIEMAuditLogRepository forwards created audit records to the organization's SIEM sinks
+func NewSIEMAuditLogRepository(repo domain.AuditLogRepository, forwarder *SIEMForwarderService) domain.AuditLogRepository {
+	return &siemAuditLogRepository{AuditLogRepository: repo, forwarder: forwarder}
+}
+
+func (r *siemAuditLogRepository) Create(log *domain.AuditLog) error {
+	if err := r.AuditLogRepository.Create(log); err != nil {
+		return err
+	}
+	r.forwarder.Publish(auditLogToSIEMEvent(log))
+	return nil
+}
+
+type siemAlertRepository struct {
+	domain.AlertRepository
+	forwarder *SIEMForwarderService
+}
+
+// NewSIEMAlertRepository forwards created alerts to the organization's SIEM sinks
+func NewSIEMAlertRepository(repo domain.AlertRepository, forwarder *SIEMForwarderService) domain.AlertRepository {
+	return &siemAlertRepository{AlertRepository: repo, forwarder: forwarder}
+}
+
+func (r *siemAlertRepository) Create(alert *domain.Alert) error {
+	if err := r.AlertRepository.Create(alert); err != nil {
+		return err
+	}
+	r.forwarder.Publish(alertToSIEMEvent(alert))
+	return nil
+}
+
+type siemVerificationEventRepository struct {
+	domain.VerificationEventRepository
+	forwarder *SIEMForwarderService
+}
+
+// NewSIEMVerificationEventRepository forwards verification events (and their later results)
+// to the organization's SIEM sinks
+func NewSIEMVerificationEventRepository(repo domain.VerificationEventRepository, forwarder *SIEMForwarderService) domain.VerificationEventRepository {
+	return &siemVerificationEventRepository{VerificationEventRepository: repo, forwarder: forwarder}
+}
+
+func (r *siemVerificationEventRepository) Create(event *domain.VerificationEvent) error {
+	if err := r.VerificationEventRepository.Create(event); err != nil {
+		return err
+	}
+	r.forwarder.Publish(verificationEventToSIEMEvent(event))
+	return nil
+}
+
+func (r *siemVerificationEventRepository) UpdateResult(id uuid.UUID, result domain.VerificationResult, reason *string, metadata map[string]interface{}) error {
+	if err := r.VerificationEventRepository.UpdateResult(id, result, reason, metadata); err != nil {
+		return err
+	}
+
+	// The update only carries the result, so reload the event (skipped when nothing listens)
+	if r.forwarder.hasSinks() {
+		if event, err := r.VerificationEventRepository.GetByID(id); err == nil {
+			r.forwarder.Publish(verificationEventToSIEMEvent(event))
+		}
+	}
+	return nil
+}
+
+// auditLogToSIEMEvent maps an audit record onto the SIEM event model
+func auditLogToSIEMEvent(log *domain.AuditLog) *domain.SIEMEvent {
+	event := &domain.SIEMEvent{
+		ID:             log.ID,
+		OrganizationID: log.OrganizationID,
+		Type:           domain.SIEMEventAudit,
+		Name:           string(log.Action),
+		Severity:       auditActionSeverity(log.Action),
+		Message:        fmt.Sprintf("%s %s %s", log.Action, log.ResourceType, log.ResourceID),
+		Timestamp:      log.Timestamp,
+		ActorType:      "system",
+		ResourceType:   log.ResourceType,
+		ResourceID:     log.ResourceID.String(),
+		SourceIP:       log.IPAddress,
+		Data:           map[string]interface{}{},
+	}
+	if log.UserID != uuid.Nil {
+		userID := log.UserID
+		event.ActorID = &userID
+		event.ActorType = "user"
+	}
+	for key, value := range log.Metadata {
+		event.Data[key] = value
+	}
+	if log.UserAgent != "" {
+		event.Data["user_agent"] = log.UserAgent
+	}
+	return event
+}
+
+func auditActionSeverity(action domain.AuditAction) int {
+	switch {
+	case strings.Contains(string(action), "violation"):
+		return 7
+	case action == domain.AuditActionDelete, action == domain.AuditActionRevoke,
+		strings.HasPrefix(string(action), "delete_"), strings.HasPrefix(string(action), "revoke_"):
+		return 5
+	case action == domain.AuditActionView:
+		return 1
+	default:
+		return 3
+	}
+}
+
+// alertToSIEMEvent maps an alert onto the SIEM event model
+func alertToSIEMEvent(alert *domain.Alert) *domain.SIEMEvent {
+	return &domain.SIEMEvent{
+		ID:             alert.ID,
+		OrganizationID: alert.OrganizationID,
+		Type:           domain.SIEMEventAlert,
+		Name:           string(alert.AlertType),
+		Severity:       alertSeverityToSIEM(alert.Severity),
+		Message:        alert.Description,
+		Timestamp:      alert.CreatedAt,
+		ActorType:      "system",
+		ResourceType:   alert.ResourceType,
+		ResourceID:     alert.ResourceID.String(),
+		Data: map[string]interface{}{
+			"title":    alert.Title,
+			"severity": string(alert.Severity),
+		},
+	}
+}
+
+func alertSeverityToSIEM(severity domain.AlertSeverity) int {
+	switch severity {
+	case domain.AlertSeverityCritical:
+		return 10
+	case domain.AlertSeverityHigh:
+		return 8
+	case domain.AlertSeverityWarning:
+		return 5
+	default:
+		return 3
+	}
+}
+
+// verificationEventToSIEMEvent maps a verification event onto the SIEM event model
+func verificationEventToSIEMEvent(event *domain.VerificationEvent) *domain.SIEMEvent {
+	siemEvent := &domain.SIEMEvent{
+		ID:             event.ID,
+		OrganizationID: event.OrganizationID,
+		Type:           domain.SIEMEventVerification,
+		Name:           string(event.Status),
+		Severity:       verificationSeverity(event),
+		Timestamp:      event.CreatedAt,
+		ActorType:      string(event.InitiatorType),
+		ActorID:        event.InitiatorID,
+		Data: map[string]interface{}{
+			"protocol":          string(event.Protocol),
+			"verification_type": string(event.VerificationType),
+			"confidence":        event.Confidence,
+			"trust_score":       event.TrustScore,
+			"duration_ms":       event.DurationMs,
+			"drift_detected":    event.DriftDetected,
+		},
+	}
+	if siemEvent.Timestamp.IsZero() {
+		siemEvent.Timestamp = event.StartedAt
+	}
+	if event.InitiatorIP != nil {
+		siemEvent.SourceIP = *event.InitiatorIP
+	}
+
+	target := ""
+	switch {
+	case event.AgentID != nil:
+		siemEvent.ResourceType = "agent"
+		siemEvent.ResourceID = event.AgentID.String()
+		if event.AgentName != nil {
+			target = *event.AgentName
+		}
+	case event.MCPServerID != nil:
+		siemEvent.ResourceType = "mcp_server"
+		siemEvent.ResourceID = event.MCPServerID.String()
+		if event.MCPServerName != nil {
+			target = *event.MCPServerName
+		}
+	}
+	if target == "" {
+		target = siemEvent.ResourceID
+	}
+
+	action := ""
+	if event.Action != nil {
+		action = *event.Action
+		siemEvent.Data["action"] = action
+	}
+	siemEvent.Message = strings.TrimSpace(fmt.Sprintf("%s verification %s for %s %s", event.VerificationType, event.Status, target, action))
+
+	if event.Result != nil {
+		siemEvent.Data["result"] = string(*event.Result)
+	}
+	if event.ErrorReason != nil {
+		siemEvent.Data["error_reason"] = *event.ErrorReason
+	}
+	if len(event.MCPServerDrift) > 0 {
+		siemEvent.Data["mcp_server_drift"] = event.MCPServerDrift
+	}
+	if len(event.CapabilityDrift) > 0 {
+		siemEvent.Data["capability_drift"] = event.CapabilityDrift
+	}
+
+	return siemEvent
+}
+
+func verificationSeverity(event *domain.VerificationEvent) int {
+	if event.DriftDetected {
+		return 7
+	}
+	if event.Result != nil && *event.Result == domain.VerificationResultDenied {
+		return 6
+	}
+	switch event.Status {
+	case domain.VerificationEventStatusFailed, domain.VerificationEventStatusTimeout:
+		return 6
+	case domain.VerificationEventStatusPending:
+		return 3
+	default:
+		return 1
+	}
+}
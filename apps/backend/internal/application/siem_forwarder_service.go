@@ -0,0 +1,609 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/netguard"
+	"github.com/opena2a/identity/backend/internal/infrastructure/siem"
+)
+
+const (
+	// DefaultSIEMBufferSize is the per-sink queue capacity when none is configured
+	DefaultSIEMBufferSize = 10000
+	maxSIEMBufferSize     = 1000000
+
+	siemBatchSize     = 100
+	siemFlushInterval = time.Second
+	siemMaxAttempts   = 3
+	siemRetryBackoff  = time.Second
+	siemSendTimeout   = 30 * time.Second
+	siemDrainTimeout  = 10 * time.Second
+)
+
+// SIEMSinkMetrics reports the delivery state of a sink on this instance
+type SIEMSinkMetrics struct {
+	SinkID         uuid.UUID  `json:"sink_id"`
+	Running        bool       `json:"running"`
+	Queued         int64      `json:"queued"`    // Events accepted into the buffer
+	Delivered      int64      `json:"delivered"` // Events acknowledged by the SIEM
+	Dropped        int64      `json:"dropped"`   // Events discarded because the buffer was full
+	Failed         int64      `json:"failed"`    // Events discarded after exhausting retries
+	QueueDepth     int        `json:"queue_depth"`
+	BufferSize     int        `json:"buffer_size"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastErrorAt    *time.Time `json:"last_error_at,omitempty"`
+}
+
+// SIEMSinkTestResult is the outcome of a synchronous test delivery
+type SIEMSinkTestResult struct {
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// SIEMSinkRequest creates or updates a SIEM sink
+type SIEMSinkRequest struct {
+	Name        string                   `json:"name"`
+	Type        domain.SIEMSinkType      `json:"type"`
+	Endpoint    string                   `json:"endpoint"`
+	Format      domain.SIEMMessageFormat `json:"format,omitempty"`
+	UseTLS      bool                     `json:"use_tls"`
+	TLSCACert   string                   `json:"tls_ca_cert,omitempty"`
+	Token       *string                  `json:"token,omitempty"` // Omit on update to keep the current token
+	EventTypes  []domain.SIEMEventType   `json:"event_types,omitempty"`
+	MinSeverity int                      `json:"min_severity"`
+	BufferSize  int                      `json:"buffer_size,omitempty"`
+	IsActive    *bool                    `json:"is_active,omitempty"`
+}
+
+// siemSinkCounters survive worker restarts so reconfiguring a sink keeps its history
+type siemSinkCounters struct {
+	queued    atomic.Int64
+	delivered atomic.Int64
+	dropped   atomic.Int64
+	failed    atomic.Int64
+
+	mu             sync.Mutex
+	lastDeliveryAt *time.Time
+	lastError      string
+	lastErrorAt    *time.Time
+}
+
+type siemSinkWorker struct {
+	sink      *domain.SIEMSink
+	transport siem.Transport
+	queue     chan *domain.SIEMEvent
+	stop      chan struct{}
+	done      chan struct{}
+	counters  *siemSinkCounters
+}
+
+// SIEMForwarderService streams audit records, alerts and verification events to each
+// organization's SIEM sinks. Publishing never blocks the caller: every sink has a bounded
+// in-memory queue drained by its own worker, and events that do not fit are dropped and counted.
+type SIEMForwarderService struct {
+	sinkRepo     domain.SIEMSinkRepository
+	outbound     *netguard.Policy
+	newTransport func(sink *domain.SIEMSink) (siem.Transport, error)
+
+	batchSize     int
+	flushInterval time.Duration
+	maxAttempts   int
+	retryBackoff  time.Duration
+
+	mu       sync.RWMutex
+	ctx      context.Context
+	workers  map[uuid.UUID]*siemSinkWorker
+	byOrg    map[uuid.UUID][]*siemSinkWorker
+	counters map[uuid.UUID]*siemSinkCounters
+	wg       sync.WaitGroup
+}
+
+// NewSIEMForwarderService creates a new SIEM forwarder; call Start to begin delivery. Sinks may
+// only reach the internal addresses the outbound policy allows.
+func NewSIEMForwarderService(sinkRepo domain.SIEMSinkRepository, outbound *netguard.Policy) *SIEMForwarderService {
+	return &SIEMForwarderService{
+		sinkRepo: sinkRepo,
+		outbound: outbound,
+		newTransport: func(sink *domain.SIEMSink) (siem.Transport, error) {
+			return siem.NewTransport(sink, outbound)
+		},
+		batchSize:     siemBatchSize,
+		flushInterval: siemFlushInterval,
+		maxAttempts:   siemMaxAttempts,
+		retryBackoff:  siemRetryBackoff,
+		workers:       make(map[uuid.UUID]*siemSinkWorker),
+		byOrg:         make(map[uuid.UUID][]*siemSinkWorker),
+		counters:      make(map[uuid.UUID]*siemSinkCounters),
+	}
+}
+
+// Start loads every active sink and starts its worker; workers stop when ctx is cancelled
+func (s *SIEMForwarderService) Start(ctx context.Context) error {
+	sinks, err := s.sinkRepo.ListActive()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.ctx = ctx
+	for _, sink := range sinks {
+		s.startWorkerLocked(sink)
+	}
+	started := len(s.workers)
+	s.mu.Unlock()
+
+	fmt.Printf("✅ SIEM forwarder started with %d sinks\n", started)
+	return nil
+}
+
+// Wait blocks until every worker has flushed its queue after Start's context was cancelled
+func (s *SIEMForwarderService) Wait() {
+	s.wg.Wait()
+}
+
+// Publish queues an event for every matching sink of its organization
+func (s *SIEMForwarderService) Publish(event *domain.SIEMEvent) {
+	s.mu.RLock()
+	workers := s.byOrg[event.OrganizationID]
+	s.mu.RUnlock()
+
+	for _, worker := range workers {
+		if !worker.sink.Accepts(event) {
+			continue
+		}
+		select {
+		case worker.queue <- event:
+			worker.counters.queued.Add(1)
+		default:
+			// Backpressure: the SIEM is slower than we produce events, so shed load
+			if worker.counters.dropped.Add(1) == 1 {
+				fmt.Printf("⚠️  SIEM sink %s buffer is full, dropping events\n", worker.sink.ID)
+			}
+		}
+	}
+}
+
+// hasSinks reports whether any sink is running, letting event sources skip extra work
+func (s *SIEMForwarderService) hasSinks() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.workers) > 0
+}
+
+// startWorkerLocked starts a worker for an active sink; s.mu must be held
+func (s *SIEMForwarderService) startWorkerLocked(sink *domain.SIEMSink) {
+	if s.ctx == nil || !sink.IsActive {
+		return
+	}
+
+	transport, err := s.newTransport(sink)
+	if err != nil {
+		fmt.Printf("⚠️  SIEM sink %s is misconfigured: %v\n", sink.ID, err)
+		return
+	}
+
+	counters, ok := s.counters[sink.ID]
+	if !ok {
+		counters = &siemSinkCounters{}
+		s.counters[sink.ID] = counters
+	}
+
+	bufferSize := sink.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = DefaultSIEMBufferSize
+	}
+
+	worker := &siemSinkWorker{
+		sink:      sink,
+		transport: transport,
+		queue:     make(chan *domain.SIEMEvent, bufferSize),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		counters:  counters,
+	}
+	s.workers[sink.ID] = worker
+	s.byOrg[sink.OrganizationID] = append(s.byOrg[sink.OrganizationID], worker)
+
+	s.wg.Add(1)
+	go s.runWorker(s.ctx, worker)
+}
+
+// stopWorkerLocked detaches a sink's worker; it flushes what is already queued in the
+// background. s.mu must be held.
+func (s *SIEMForwarderService) stopWorkerLocked(sinkID uuid.UUID) {
+	worker, ok := s.workers[sinkID]
+	if !ok {
+		return
+	}
+	delete(s.workers, sinkID)
+
+	orgWorkers := s.byOrg[worker.sink.OrganizationID]
+	remaining := make([]*siemSinkWorker, 0, len(orgWorkers))
+	for _, w := range orgWorkers {
+		if w != worker {
+			remaining = append(remaining, w)
+		}
+	}
+	if len(remaining) == 0 {
+		delete(s.byOrg, worker.sink.OrganizationID)
+	} else {
+		s.byOrg[worker.sink.OrganizationID] = remaining
+	}
+
+	close(worker.stop)
+}
+
+// reload restarts the worker of a sink after its configuration changed
+func (s *SIEMForwarderService) reload(sink *domain.SIEMSink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopWorkerLocked(sink.ID)
+	s.startWorkerLocked(sink)
+}
+
+// Reconcile brings this instance's workers in line with the stored sinks. Sink changes only
+// reload workers on the instance that served the request, so every instance runs this
+// periodically to pick up sinks created, changed or deleted elsewhere.
+func (s *SIEMForwarderService) Reconcile(ctx context.Context) error {
+	sinks, err := s.sinkRepo.ListActive()
+	if err != nil {
+		return fmt.Errorf("failed to list SIEM sinks: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx == nil {
+		return nil
+	}
+
+	active := make(map[uuid.UUID]bool, len(sinks))
+	for _, sink := range sinks {
+		active[sink.ID] = true
+		worker, ok := s.workers[sink.ID]
+		if ok && worker.sink.UpdatedAt.Equal(sink.UpdatedAt) {
+			continue
+		}
+		s.stopWorkerLocked(sink.ID)
+		s.startWorkerLocked(sink)
+	}
+	for id := range s.workers {
+		if !active[id] {
+			s.stopWorkerLocked(id)
+		}
+	}
+
+	return nil
+}
+
+func (s *SIEMForwarderService) runWorker(ctx context.Context, worker *siemSinkWorker) {
+	defer s.wg.Done()
+	defer close(worker.done)
+	defer worker.transport.Close()
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*domain.SIEMEvent, 0, s.batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			s.deliver(ctx, worker, batch, s.maxAttempts)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case event := <-worker.queue:
+			batch = append(batch, event)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-worker.stop:
+			s.drain(worker, batch)
+			return
+		case <-ctx.Done():
+			s.drain(worker, batch)
+			return
+		}
+	}
+}
+
+// drain makes a single, time-boxed attempt to deliver what was queued when the worker stopped
+func (s *SIEMForwarderService) drain(worker *siemSinkWorker, batch []*domain.SIEMEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), siemDrainTimeout)
+	defer cancel()
+
+	for pending := len(worker.queue); pending > 0; pending-- {
+		batch = append(batch, <-worker.queue)
+		if len(batch) >= s.batchSize {
+			s.deliver(ctx, worker, batch, 1)
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		s.deliver(ctx, worker, batch, 1)
+	}
+}
+
+// deliver sends a batch, retrying with exponential backoff
+func (s *SIEMForwarderService) deliver(ctx context.Context, worker *siemSinkWorker, batch []*domain.SIEMEvent, attempts int) {
+	backoff := s.retryBackoff
+	var err error
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, siemSendTimeout)
+		err = worker.transport.Send(sendCtx, batch)
+		cancel()
+
+		if err == nil {
+			now := time.Now().UTC()
+			worker.counters.delivered.Add(int64(len(batch)))
+			worker.counters.mu.Lock()
+			worker.counters.lastDeliveryAt = &now
+			worker.counters.mu.Unlock()
+			return
+		}
+
+		if attempt == attempts {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			attempt = attempts
+		case <-worker.stop:
+			attempt = attempts
+		}
+	}
+
+	now := time.Now().UTC()
+	worker.counters.failed.Add(int64(len(batch)))
+	worker.counters.mu.Lock()
+	worker.counters.lastError = err.Error()
+	worker.counters.lastErrorAt = &now
+	worker.counters.mu.Unlock()
+
+	fmt.Printf("⚠️  SIEM sink %s failed to deliver %d events: %v\n", worker.sink.ID, len(batch), err)
+}
+
+// CreateSink validates and stores a new sink and starts forwarding to it
+func (s *SIEMForwarderService) CreateSink(ctx context.Context, orgID, userID uuid.UUID, req *SIEMSinkRequest) (*domain.SIEMSink, error) {
+	sink := &domain.SIEMSink{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		IsActive:       true,
+		CreatedBy:      userID,
+	}
+	applySIEMSinkRequest(sink, req)
+
+	if err := s.validateSink(ctx, sink); err != nil {
+		return nil, err
+	}
+
+	if err := s.sinkRepo.Create(sink); err != nil {
+		return nil, err
+	}
+
+	s.reload(sink)
+	return sink, nil
+}
+
+// ListSinks lists an organization's sinks
+func (s *SIEMForwarderService) ListSinks(ctx context.Context, orgID uuid.UUID) ([]*domain.SIEMSink, error) {
+	return s.sinkRepo.GetByOrganization(orgID)
+}
+
+// GetSink returns a sink owned by the organization
+func (s *SIEMForwarderService) GetSink(ctx context.Context, orgID, id uuid.UUID) (*domain.SIEMSink, error) {
+	sink, err := s.sinkRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if sink.OrganizationID != orgID {
+		return nil, fmt.Errorf("siem sink not found")
+	}
+	return sink, nil
+}
+
+// UpdateSink replaces a sink's configuration and restarts its worker
+func (s *SIEMForwarderService) UpdateSink(ctx context.Context, orgID, id uuid.UUID, req *SIEMSinkRequest) (*domain.SIEMSink, error) {
+	sink, err := s.GetSink(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applySIEMSinkRequest(sink, req)
+
+	if err := s.validateSink(ctx, sink); err != nil {
+		return nil, err
+	}
+
+	if err := s.sinkRepo.Update(sink); err != nil {
+		return nil, err
+	}
+
+	s.reload(sink)
+	return sink, nil
+}
+
+// DeleteSink removes a sink; events already queued for it are still flushed
+func (s *SIEMForwarderService) DeleteSink(ctx context.Context, orgID, id uuid.UUID) error {
+	if _, err := s.GetSink(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	if err := s.sinkRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.stopWorkerLocked(id)
+	delete(s.counters, id)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// GetSinkMetrics returns the delivery metrics of a sink on this instance
+func (s *SIEMForwarderService) GetSinkMetrics(ctx context.Context, orgID, id uuid.UUID) (*SIEMSinkMetrics, error) {
+	sink, err := s.GetSink(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	worker, running := s.workers[id]
+	counters := s.counters[id]
+	s.mu.RUnlock()
+
+	metrics := &SIEMSinkMetrics{
+		SinkID:     id,
+		Running:    running,
+		BufferSize: sink.BufferSize,
+	}
+	if running {
+		metrics.QueueDepth = len(worker.queue)
+		metrics.BufferSize = cap(worker.queue)
+	}
+	if counters != nil {
+		metrics.Queued = counters.queued.Load()
+		metrics.Delivered = counters.delivered.Load()
+		metrics.Dropped = counters.dropped.Load()
+		metrics.Failed = counters.failed.Load()
+		counters.mu.Lock()
+		metrics.LastDeliveryAt = counters.lastDeliveryAt
+		metrics.LastError = counters.lastError
+		metrics.LastErrorAt = counters.lastErrorAt
+		counters.mu.Unlock()
+	}
+
+	return metrics, nil
+}
+
+// TestSink synchronously delivers a test event, bypassing the queue
+func (s *SIEMForwarderService) TestSink(ctx context.Context, orgID, id uuid.UUID) (*SIEMSinkTestResult, error) {
+	sink, err := s.GetSink(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The endpoint is checked again: its name may resolve differently than when it was saved
+	if err := siem.CheckEndpoint(ctx, sink, s.outbound); err != nil {
+		return &SIEMSinkTestResult{Success: false, Error: err.Error()}, nil
+	}
+
+	transport, err := s.newTransport(sink)
+	if err != nil {
+		return &SIEMSinkTestResult{Success: false, Error: err.Error()}, nil
+	}
+	defer transport.Close()
+
+	event := &domain.SIEMEvent{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Type:           domain.SIEMEventAudit,
+		Name:           "siem_test",
+		Severity:       1,
+		Message:        "AIM SIEM connectivity test",
+		Timestamp:      time.Now().UTC(),
+		ActorType:      "system",
+		ResourceType:   "siem_sink",
+		ResourceID:     sink.ID.String(),
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, siemSendTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err = transport.Send(sendCtx, []*domain.SIEMEvent{event})
+	result := &SIEMSinkTestResult{
+		Success:    err == nil,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result, nil
+}
+
+func applySIEMSinkRequest(sink *domain.SIEMSink, req *SIEMSinkRequest) {
+	sink.Name = req.Name
+	sink.Type = req.Type
+	sink.Endpoint = req.Endpoint
+	sink.Format = req.Format
+	sink.UseTLS = req.UseTLS
+	sink.TLSCACert = req.TLSCACert
+	sink.EventTypes = req.EventTypes
+	sink.MinSeverity = req.MinSeverity
+	sink.BufferSize = req.BufferSize
+	if req.Token != nil {
+		sink.Token = *req.Token
+	}
+	if req.IsActive != nil {
+		sink.IsActive = *req.IsActive
+	}
+
+	if sink.BufferSize == 0 {
+		sink.BufferSize = DefaultSIEMBufferSize
+	}
+	if sink.Type == domain.SIEMSinkSyslog && sink.Format == "" {
+		sink.Format = domain.SIEMFormatCEF
+	}
+	if sink.Type != domain.SIEMSinkSyslog {
+		sink.Format = ""
+		sink.UseTLS = false
+	}
+	if sink.EventTypes == nil {
+		sink.EventTypes = []domain.SIEMEventType{}
+	}
+}
+
+// validateSink checks the configuration, that a transport can be built for it and that its
+// endpoint does not point at an internal address
+func (s *SIEMForwarderService) validateSink(ctx context.Context, sink *domain.SIEMSink) error {
+	if sink.Name == "" {
+		return fmt.Errorf("invalid siem sink: name is required")
+	}
+	if sink.MinSeverity < 0 || sink.MinSeverity > 10 {
+		return fmt.Errorf("invalid siem sink: min_severity must be between 0 and 10")
+	}
+	if sink.BufferSize < 1 || sink.BufferSize > maxSIEMBufferSize {
+		return fmt.Errorf("invalid siem sink: buffer_size must be between 1 and %d", maxSIEMBufferSize)
+	}
+	switch sink.Format {
+	case "", domain.SIEMFormatCEF, domain.SIEMFormatLEEF, domain.SIEMFormatJSON:
+	default:
+		return fmt.Errorf("invalid siem sink: unsupported format %s", sink.Format)
+	}
+	for _, eventType := range sink.EventTypes {
+		switch eventType {
+		case domain.SIEMEventAudit, domain.SIEMEventAlert, domain.SIEMEventVerification:
+		default:
+			return fmt.Errorf("invalid siem sink: unknown event type %s", eventType)
+		}
+	}
+
+	transport, err := siem.NewTransport(sink, s.outbound)
+	if err != nil {
+		return fmt.Errorf("invalid siem sink: %w", err)
+	}
+	transport.Close()
+
+	if err := siem.CheckEndpoint(ctx, sink, s.outbound); err != nil {
+		return fmt.Errorf("invalid siem sink: %w", err)
+	}
+
+	return nil
+}
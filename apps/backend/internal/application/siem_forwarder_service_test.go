@@ -0,0 +1,413 @@
+package application
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/netguard"
+	"github.com/opena2a/identity/backend/internal/infrastructure/siem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSIEMSinkRepository struct {
+	mu    sync.Mutex
+	sinks map[uuid.UUID]*domain.SIEMSink
+}
+
+func newFakeSIEMSinkRepository(sinks ...*domain.SIEMSink) *fakeSIEMSinkRepository {
+	repo := &fakeSIEMSinkRepository{sinks: make(map[uuid.UUID]*domain.SIEMSink)}
+	for _, sink := range sinks {
+		repo.sinks[sink.ID] = sink
+	}
+	return repo
+}
+
+func (r *fakeSIEMSinkRepository) Create(sink *domain.SIEMSink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sinks[sink.ID] = sink
+	return nil
+}
+
+func (r *fakeSIEMSinkRepository) GetByID(id uuid.UUID) (*domain.SIEMSink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sink, ok := r.sinks[id]
+	if !ok {
+		return nil, errors.New("siem sink not found")
+	}
+	copied := *sink
+	return &copied, nil
+}
+
+func (r *fakeSIEMSinkRepository) GetByOrganization(orgID uuid.UUID) ([]*domain.SIEMSink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sinks []*domain.SIEMSink
+	for _, sink := range r.sinks {
+		if sink.OrganizationID == orgID {
+			sinks = append(sinks, sink)
+		}
+	}
+	return sinks, nil
+}
+
+func (r *fakeSIEMSinkRepository) ListActive() ([]*domain.SIEMSink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sinks []*domain.SIEMSink
+	for _, sink := range r.sinks {
+		if sink.IsActive {
+			sinks = append(sinks, sink)
+		}
+	}
+	return sinks, nil
+}
+
+func (r *fakeSIEMSinkRepository) Update(sink *domain.SIEMSink) error { return r.Create(sink) }
+
+func (r *fakeSIEMSinkRepository) Delete(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sinks, id)
+	return nil
+}
+
+// fakeSIEMTransport records delivered events; failures and block make sends fail or hang
+type fakeSIEMTransport struct {
+	mu       sync.Mutex
+	events   []*domain.SIEMEvent
+	failures int
+	block    chan struct{}
+}
+
+func (t *fakeSIEMTransport) Send(ctx context.Context, events []*domain.SIEMEvent) error {
+	if t.block != nil {
+		select {
+		case <-t.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failures > 0 {
+		t.failures--
+		return errors.New("collector unavailable")
+	}
+	t.events = append(t.events, events...)
+	return nil
+}
+
+func (t *fakeSIEMTransport) Close() error { return nil }
+
+func (t *fakeSIEMTransport) delivered() []*domain.SIEMEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*domain.SIEMEvent(nil), t.events...)
+}
+
+func newTestSIEMForwarder(repo domain.SIEMSinkRepository, transports map[uuid.UUID]*fakeSIEMTransport) *SIEMForwarderService {
+	forwarder := NewSIEMForwarderService(repo, nil)
+	forwarder.flushInterval = 10 * time.Millisecond
+	forwarder.retryBackoff = time.Millisecond
+	forwarder.newTransport = func(sink *domain.SIEMSink) (siem.Transport, error) {
+		return transports[sink.ID], nil
+	}
+	return forwarder
+}
+
+func testSIEMSink(orgID uuid.UUID, eventTypes ...domain.SIEMEventType) *domain.SIEMSink {
+	return &domain.SIEMSink{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Name:           "siem",
+		Type:           domain.SIEMSinkHTTPNDJSON,
+		Endpoint:       "http://127.0.0.1:9/ingest",
+		EventTypes:     eventTypes,
+		BufferSize:     100,
+		IsActive:       true,
+	}
+}
+
+func TestSIEMForwarderRoutesEventsToMatchingSinks(t *testing.T) {
+	orgID := uuid.New()
+	auditSink := testSIEMSink(orgID, domain.SIEMEventAudit)
+	alertSink := testSIEMSink(orgID, domain.SIEMEventAlert)
+	alertSink.MinSeverity = 8
+	otherOrgSink := testSIEMSink(uuid.New())
+
+	transports := map[uuid.UUID]*fakeSIEMTransport{
+		auditSink.ID:    {},
+		alertSink.ID:    {},
+		otherOrgSink.ID: {},
+	}
+	forwarder := newTestSIEMForwarder(newFakeSIEMSinkRepository(auditSink, alertSink, otherOrgSink), transports)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, forwarder.Start(ctx))
+
+	auditLogRepo := new(AgentServiceMockAuditLogRepository)
+	auditLogRepo.On("Create", mock.Anything).Return(nil)
+	auditRepo := NewSIEMAuditLogRepository(auditLogRepo, forwarder)
+	require.NoError(t, auditRepo.Create(&domain.AuditLog{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		UserID:         uuid.New(),
+		Action:         domain.AuditActionDelete,
+		ResourceType:   "agent",
+		ResourceID:     uuid.New(),
+		IPAddress:      "10.1.2.3",
+		Timestamp:      time.Now().UTC(),
+	}))
+	forwarder.Publish(alertToSIEMEvent(&domain.Alert{ID: uuid.New(), OrganizationID: orgID, AlertType: domain.AlertUnusualActivity, Severity: domain.AlertSeverityWarning}))
+	forwarder.Publish(alertToSIEMEvent(&domain.Alert{ID: uuid.New(), OrganizationID: orgID, AlertType: domain.AlertSecurityBreach, Severity: domain.AlertSeverityCritical}))
+
+	cancel()
+	forwarder.Wait()
+	auditLogRepo.AssertExpectations(t)
+
+	audit := transports[auditSink.ID].delivered()
+	require.Len(t, audit, 1)
+	assert.Equal(t, "delete", audit[0].Name)
+	assert.Equal(t, "user", audit[0].ActorType)
+	assert.Equal(t, "10.1.2.3", audit[0].SourceIP)
+
+	alerts := transports[alertSink.ID].delivered()
+	require.Len(t, alerts, 1, "warning alerts are below the sink's minimum severity")
+	assert.Equal(t, string(domain.AlertSecurityBreach), alerts[0].Name)
+	assert.Equal(t, 10, alerts[0].Severity)
+
+	assert.Empty(t, transports[otherOrgSink.ID].delivered())
+}
+
+func TestSIEMForwarderDropsEventsWhenBufferIsFull(t *testing.T) {
+	orgID := uuid.New()
+	sink := testSIEMSink(orgID)
+	sink.BufferSize = 2
+	transport := &fakeSIEMTransport{block: make(chan struct{})}
+	forwarder := newTestSIEMForwarder(newFakeSIEMSinkRepository(sink), map[uuid.UUID]*fakeSIEMTransport{sink.ID: transport})
+	forwarder.batchSize = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, forwarder.Start(ctx))
+
+	publish := func() {
+		forwarder.Publish(&domain.SIEMEvent{ID: uuid.New(), OrganizationID: orgID, Type: domain.SIEMEventAudit, Timestamp: time.Now()})
+	}
+
+	// The first event is taken by the (blocked) worker; the buffer then holds two more
+	publish()
+	require.Eventually(t, func() bool {
+		metrics, _ := forwarder.GetSinkMetrics(ctx, orgID, sink.ID)
+		return metrics.QueueDepth == 0
+	}, time.Second, 5*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		publish()
+	}
+
+	metrics, err := forwarder.GetSinkMetrics(ctx, orgID, sink.ID)
+	require.NoError(t, err)
+	assert.True(t, metrics.Running)
+	assert.Equal(t, int64(3), metrics.Queued)
+	assert.Equal(t, int64(3), metrics.Dropped)
+	assert.Equal(t, 2, metrics.QueueDepth)
+	assert.Equal(t, 2, metrics.BufferSize)
+
+	close(transport.block)
+	require.Eventually(t, func() bool {
+		metrics, _ := forwarder.GetSinkMetrics(ctx, orgID, sink.ID)
+		return metrics.Delivered == 3
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestSIEMForwarderRetriesFailedDeliveries(t *testing.T) {
+	orgID := uuid.New()
+	retried := testSIEMSink(orgID)
+	failing := testSIEMSink(orgID)
+	transports := map[uuid.UUID]*fakeSIEMTransport{
+		retried.ID: {failures: 2},
+		failing.ID: {failures: 100},
+	}
+	forwarder := newTestSIEMForwarder(newFakeSIEMSinkRepository(retried, failing), transports)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, forwarder.Start(ctx))
+
+	forwarder.Publish(&domain.SIEMEvent{ID: uuid.New(), OrganizationID: orgID, Type: domain.SIEMEventAlert, Timestamp: time.Now()})
+
+	require.Eventually(t, func() bool {
+		ok, _ := forwarder.GetSinkMetrics(ctx, orgID, retried.ID)
+		failed, _ := forwarder.GetSinkMetrics(ctx, orgID, failing.ID)
+		return ok.Delivered == 1 && failed.Failed == 1
+	}, time.Second, 5*time.Millisecond)
+
+	metrics, err := forwarder.GetSinkMetrics(ctx, orgID, failing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "collector unavailable", metrics.LastError)
+	assert.NotNil(t, metrics.LastErrorAt)
+	assert.Nil(t, metrics.LastDeliveryAt)
+}
+
+func TestSIEMForwarderSinkLifecycle(t *testing.T) {
+	orgID := uuid.New()
+	repo := newFakeSIEMSinkRepository()
+	forwarder := NewSIEMForwarderService(repo, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, forwarder.Start(ctx))
+
+	_, err := forwarder.CreateSink(ctx, orgID, uuid.New(), &SIEMSinkRequest{Name: "qradar", Type: domain.SIEMSinkSyslog, Endpoint: "not-a-host-port"})
+	assert.ErrorContains(t, err, "invalid siem sink")
+
+	_, err = forwarder.CreateSink(ctx, orgID, uuid.New(), &SIEMSinkRequest{Name: "splunk", Type: domain.SIEMSinkSplunkHEC, Endpoint: "https://splunk.example.com:8088"})
+	assert.ErrorContains(t, err, "token is required")
+
+	// Sinks cannot point at internal addresses such as the cloud metadata service
+	_, err = forwarder.CreateSink(ctx, orgID, uuid.New(), &SIEMSinkRequest{Name: "metadata", Type: domain.SIEMSinkHTTPNDJSON, Endpoint: "http://169.254.169.254/latest/meta-data"})
+	assert.ErrorContains(t, err, "not allowed")
+	_, err = forwarder.CreateSink(ctx, orgID, uuid.New(), &SIEMSinkRequest{Name: "local", Type: domain.SIEMSinkSyslog, Endpoint: "localhost:514"})
+	assert.ErrorContains(t, err, "not allowed")
+
+	token := "hec-token"
+	sink, err := forwarder.CreateSink(ctx, orgID, uuid.New(), &SIEMSinkRequest{
+		Name:     "splunk",
+		Type:     domain.SIEMSinkSplunkHEC,
+		Endpoint: "https://splunk.example.com:8088",
+		Token:    &token,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSIEMBufferSize, sink.BufferSize)
+	assert.True(t, forwarder.hasSinks())
+
+	_, err = forwarder.GetSink(ctx, uuid.New(), sink.ID)
+	assert.EqualError(t, err, "siem sink not found")
+
+	// Omitting the token on update keeps it; deactivating stops the worker
+	inactive := false
+	updated, err := forwarder.UpdateSink(ctx, orgID, sink.ID, &SIEMSinkRequest{
+		Name:     "splunk",
+		Type:     domain.SIEMSinkSplunkHEC,
+		Endpoint: "https://splunk.example.com:8088",
+		IsActive: &inactive,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, token, updated.Token)
+	assert.False(t, forwarder.hasSinks())
+
+	require.NoError(t, forwarder.DeleteSink(ctx, orgID, sink.ID))
+	_, err = forwarder.GetSink(ctx, orgID, sink.ID)
+	assert.Error(t, err)
+}
+
+// TestSIEMForwarderDeliversToSyslogListener runs the real syslog transport against a local
+// TCP listener standing in for the SIEM
+func TestSIEMForwarderReconcilesSinksChangedElsewhere(t *testing.T) {
+	orgID := uuid.New()
+	kept := testSIEMSink(orgID)
+	deleted := testSIEMSink(orgID)
+	transports := map[uuid.UUID]*fakeSIEMTransport{kept.ID: {}, deleted.ID: {}}
+	repo := newFakeSIEMSinkRepository(kept, deleted)
+	forwarder := newTestSIEMForwarder(repo, transports)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, forwarder.Start(ctx))
+	keptWorker := forwarder.workers[kept.ID]
+
+	// Another instance creates one sink, deletes one and changes one
+	created := testSIEMSink(orgID)
+	transports[created.ID] = &fakeSIEMTransport{}
+	require.NoError(t, repo.Create(created))
+	require.NoError(t, repo.Delete(deleted.ID))
+	changed := *kept
+	changed.MinSeverity = 5
+	changed.UpdatedAt = kept.UpdatedAt.Add(time.Second)
+	require.NoError(t, repo.Update(&changed))
+
+	require.NoError(t, forwarder.Reconcile(ctx))
+	assert.Len(t, forwarder.workers, 2)
+	assert.Contains(t, forwarder.workers, created.ID)
+	assert.NotContains(t, forwarder.workers, deleted.ID)
+	assert.NotSame(t, keptWorker, forwarder.workers[kept.ID])
+	assert.Equal(t, 5, forwarder.workers[kept.ID].sink.MinSeverity)
+
+	// Nothing changed, so no worker is restarted
+	createdWorker := forwarder.workers[created.ID]
+	require.NoError(t, forwarder.Reconcile(ctx))
+	assert.Same(t, createdWorker, forwarder.workers[created.ID])
+}
+
+func TestSIEMForwarderDeliversToSyslogListener(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		length, err := reader.ReadString(' ')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(length))
+		frame := make([]byte, n)
+		if _, err := io.ReadFull(reader, frame); err == nil {
+			received <- string(frame)
+		}
+	}()
+
+	orgID := uuid.New()
+	loopback, err := netguard.NewPolicy([]string{"127.0.0.0/8"})
+	require.NoError(t, err)
+	forwarder := NewSIEMForwarderService(newFakeSIEMSinkRepository(), loopback)
+	forwarder.flushInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, forwarder.Start(ctx))
+
+	_, err = forwarder.CreateSink(ctx, orgID, uuid.New(), &SIEMSinkRequest{
+		Name:     "arcsight",
+		Type:     domain.SIEMSinkSyslog,
+		Endpoint: listener.Addr().String(),
+	})
+	require.NoError(t, err)
+
+	alertRepo := NewSIEMAlertRepository(&fakeAlertRepository{}, forwarder)
+	require.NoError(t, alertRepo.Create(&domain.Alert{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		AlertType:      domain.AlertSecurityBreach,
+		Severity:       domain.AlertSeverityHigh,
+		Title:          "Capability violation",
+		Description:    "agent attempted data:export",
+		CreatedAt:      time.Now().UTC(),
+	}))
+
+	select {
+	case frame := <-received:
+		assert.Contains(t, frame, "CEF:0|OpenA2A|AIM|1.0|alert:security_breach|Capability violation|8|")
+		assert.Contains(t, frame, "msg=agent attempted data:export")
+	case <-time.After(5 * time.Second):
+		t.Fatal("syslog listener received nothing")
+	}
+}
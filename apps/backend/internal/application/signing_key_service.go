@@ -0,0 +1,388 @@
+package application
+
+import (
+	"context"
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opena2a/identity/backend/internal/crypto"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// signingKeyCacheTTL is how long a replica signs and verifies with its cached keys before it
+// reloads them, which picks up rotations made by other replicas
+const signingKeyCacheTTL = time.Minute
+
+// signingKeyReloadBackoff limits reloads triggered by tokens naming an unknown key
+const signingKeyReloadBackoff = 5 * time.Second
+
+// cachedSigningKey is a decoded signing key
+type cachedSigningKey struct {
+	record  *domain.SigningKey
+	public  stdcrypto.PublicKey
+	private stdcrypto.Signer // Only decrypted for the active key
+}
+
+// SigningKeyService manages the asymmetric keys that sign user access and refresh tokens and
+// publishes them as a JWKS. Keys rotate on a schedule: the "next" key is published a full
+// rotation interval before it starts signing, and retired keys stay published until every
+// token they signed has expired, so verifiers that cache the JWKS never miss a key.
+type SigningKeyService struct {
+	repo             domain.SigningKeyRepository
+	keyVault         *crypto.KeyVault
+	algorithm        string
+	rotationInterval time.Duration
+	retention        time.Duration // How long a retired key keeps verifying
+	now              func() time.Time
+
+	mu       sync.RWMutex
+	keys     map[string]*cachedSigningKey
+	active   *cachedSigningKey
+	loadedAt time.Time
+}
+
+// NewSigningKeyService creates a new signing key service. retention must cover the longest
+// lifetime of a token signed with these keys.
+func NewSigningKeyService(
+	repo domain.SigningKeyRepository,
+	keyVault *crypto.KeyVault,
+	algorithm string,
+	rotationInterval time.Duration,
+	retention time.Duration,
+) *SigningKeyService {
+	return &SigningKeyService{
+		repo:             repo,
+		keyVault:         keyVault,
+		algorithm:        algorithm,
+		rotationInterval: rotationInterval,
+		retention:        retention,
+		now:              func() time.Time { return time.Now().UTC() },
+		keys:             map[string]*cachedSigningKey{},
+	}
+}
+
+// Rotate promotes the next key once the active key has signed for a full rotation interval,
+// publishes a new next key and removes keys whose tokens have all expired. Run at startup it
+// also creates the first keys.
+func (s *SigningKeyService) Rotate(ctx context.Context) error {
+	if err := s.reload(); err != nil {
+		return err
+	}
+	now := s.now()
+
+	s.mu.RLock()
+	active := s.active
+	var next *domain.SigningKey
+	for _, key := range s.keys {
+		record := key.record
+		if record.Status == domain.SigningKeyStatusNext && record.Algorithm == s.algorithm &&
+			(next == nil || record.CreatedAt.Before(next.CreatedAt)) {
+			next = record
+		}
+	}
+	s.mu.RUnlock()
+
+	due := active == nil || active.private == nil || active.record.Algorithm != s.algorithm ||
+		active.record.ActivatedAt == nil || !now.Before(active.record.ActivatedAt.Add(s.rotationInterval))
+	if due {
+		// A next key that cannot be decrypted (e.g. after the key vault master key changed) is
+		// skipped in favour of a fresh one
+		if next != nil {
+			if _, err := s.decryptPrivateKey(next); err != nil {
+				fmt.Printf("⚠️  Skipping undecryptable signing key %s: %v\n", next.ID, err)
+				next = nil
+			}
+		}
+		if next == nil {
+			created, err := s.createKey(now)
+			if err != nil {
+				return err
+			}
+			next = created
+		}
+		if err := s.repo.Promote(next.ID, now, now.Add(s.retention)); err != nil {
+			// Another replica starting at the same time may have promoted it first
+			if reloadErr := s.reload(); reloadErr == nil {
+				if _, _, _, activeErr := s.CurrentSigningKey(); activeErr == nil {
+					return nil
+				}
+			}
+			return err
+		}
+		fmt.Printf("🔑 Signing key %s (%s) is now active\n", next.ID, next.Algorithm)
+
+		if _, err := s.createKey(now); err != nil {
+			return err
+		}
+	} else if next == nil {
+		if _, err := s.createKey(now); err != nil {
+			return err
+		}
+	}
+
+	if deleted, err := s.repo.DeleteExpired(now); err != nil {
+		return err
+	} else if deleted > 0 {
+		fmt.Printf("🧹 Deleted %d expired signing keys\n", deleted)
+	}
+
+	return s.reload()
+}
+
+// createKey generates and stores a next key
+func (s *SigningKeyService) createKey(now time.Time) (*domain.SigningKey, error) {
+	var public stdcrypto.PublicKey
+	var private stdcrypto.Signer
+	switch s.algorithm {
+	case "EdDSA":
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		public, private = pub, priv
+	case "ES256":
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		public, private = &priv.PublicKey, priv
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", s.algorithm)
+	}
+
+	jwk, err := jsonWebKey(s.algorithm, public)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	encrypted, err := s.keyVault.EncryptPrivateKey(base64.StdEncoding.EncodeToString(privateDER))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+
+	key := &domain.SigningKey{
+		ID:                  jwk.KeyID,
+		Algorithm:           s.algorithm,
+		PublicKey:           base64.StdEncoding.EncodeToString(publicDER),
+		EncryptedPrivateKey: encrypted,
+		Status:              domain.SigningKeyStatusNext,
+		CreatedAt:           now,
+	}
+	if err := s.repo.Create(key); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("🔑 Published next signing key %s (%s)\n", key.ID, key.Algorithm)
+	return key, nil
+}
+
+// reload replaces the cached keys with the stored ones
+func (s *SigningKeyService) reload() error {
+	records, err := s.repo.List()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*cachedSigningKey, len(records))
+	var active *cachedSigningKey
+	for _, record := range records {
+		der, err := base64.StdEncoding.DecodeString(record.PublicKey)
+		if err != nil {
+			fmt.Printf("⚠️  Ignoring signing key %s with an invalid public key: %v\n", record.ID, err)
+			continue
+		}
+		public, err := x509.ParsePKIXPublicKey(der)
+		if err != nil {
+			fmt.Printf("⚠️  Ignoring signing key %s with an invalid public key: %v\n", record.ID, err)
+			continue
+		}
+
+		key := &cachedSigningKey{record: record, public: public}
+		if record.Status == domain.SigningKeyStatusActive {
+			if key.private, err = s.decryptPrivateKey(record); err != nil {
+				fmt.Printf("⚠️  Cannot decrypt active signing key %s: %v\n", record.ID, err)
+			}
+			active = key
+		}
+		keys[record.ID] = key
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.active = active
+	s.loadedAt = s.now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *SigningKeyService) decryptPrivateKey(record *domain.SigningKey) (stdcrypto.Signer, error) {
+	decrypted, err := s.keyVault.DecryptPrivateKey(record.EncryptedPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	der, err := base64.StdEncoding.DecodeString(decrypted)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := parsed.(stdcrypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+	return signer, nil
+}
+
+// refreshIfStale reloads the keys when the cache is older than maxAge
+func (s *SigningKeyService) refreshIfStale(maxAge time.Duration) {
+	s.mu.RLock()
+	stale := s.now().Sub(s.loadedAt) >= maxAge
+	s.mu.RUnlock()
+	if stale {
+		if err := s.reload(); err != nil {
+			fmt.Printf("⚠️  Failed to reload signing keys: %v\n", err)
+		}
+	}
+}
+
+// CurrentSigningKey returns the key that signs new tokens (implements auth.SigningKeySource)
+func (s *SigningKeyService) CurrentSigningKey() (string, string, stdcrypto.Signer, error) {
+	s.refreshIfStale(signingKeyCacheTTL)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.active == nil || s.active.private == nil {
+		return "", "", nil, fmt.Errorf("no active signing key")
+	}
+	return s.active.record.ID, s.active.record.Algorithm, s.active.private, nil
+}
+
+// VerificationKey returns a published key (implements auth.SigningKeySource)
+func (s *SigningKeyService) VerificationKey(kid string) (string, stdcrypto.PublicKey, error) {
+	s.refreshIfStale(signingKeyCacheTTL)
+
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	s.mu.RUnlock()
+	if !ok {
+		// Another replica may have just rotated
+		s.refreshIfStale(signingKeyReloadBackoff)
+		s.mu.RLock()
+		key, ok = s.keys[kid]
+		s.mu.RUnlock()
+	}
+	if !ok {
+		return "", nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key.record.Algorithm, key.public, nil
+}
+
+// SigningSince returns when the oldest published key started signing (implements
+// auth.SigningKeySource)
+func (s *SigningKeyService) SigningSince() time.Time {
+	var since time.Time
+	for _, key := range s.publishedKeys() {
+		activatedAt := key.record.ActivatedAt
+		if activatedAt != nil && (since.IsZero() || activatedAt.Before(since)) {
+			since = *activatedAt
+		}
+	}
+	return since
+}
+
+// Algorithm returns the algorithm new keys are generated for
+func (s *SigningKeyService) Algorithm() string {
+	return s.algorithm
+}
+
+// publishedKeys returns the cached keys, oldest first
+func (s *SigningKeyService) publishedKeys() []*cachedSigningKey {
+	s.refreshIfStale(signingKeyCacheTTL)
+
+	s.mu.RLock()
+	keys := make([]*cachedSigningKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].record.CreatedAt.Before(keys[j].record.CreatedAt)
+	})
+	return keys
+}
+
+// ListKeys returns the published keys, oldest first
+func (s *SigningKeyService) ListKeys() []*domain.SigningKey {
+	keys := []*domain.SigningKey{}
+	for _, key := range s.publishedKeys() {
+		keys = append(keys, key.record)
+	}
+	return keys
+}
+
+// JWKS returns the published keys: the next, active and retired keys
+func (s *SigningKeyService) JWKS() *domain.JSONWebKeySet {
+	set := &domain.JSONWebKeySet{Keys: []domain.JSONWebKey{}}
+	for _, key := range s.publishedKeys() {
+		jwk, err := jsonWebKey(key.record.Algorithm, key.public)
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// jsonWebKey encodes a public key as a JWK whose "kid" is its RFC 7638 thumbprint
+func jsonWebKey(alg string, publicKey stdcrypto.PublicKey) (domain.JSONWebKey, error) {
+	jwk := domain.JSONWebKey{Use: "sig", Alg: alg}
+
+	var members string
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+		members = `{"crv":"Ed25519","kty":"OKP","x":"` + jwk.X + `"}`
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return jwk, fmt.Errorf("unsupported curve %s", key.Curve.Params().Name)
+		}
+		ecdhKey, err := key.ECDH()
+		if err != nil {
+			return jwk, err
+		}
+		point := ecdhKey.Bytes() // 0x04 || X || Y
+		jwk.KeyType = "EC"
+		jwk.Curve = "P-256"
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[1:33])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[33:])
+		members = `{"crv":"P-256","kty":"EC","x":"` + jwk.X + `","y":"` + jwk.Y + `"}`
+	default:
+		return jwk, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	sum := sha256.Sum256([]byte(members))
+	jwk.KeyID = base64.RawURLEncoding.EncodeToString(sum[:])
+	return jwk, nil
+}
@@ -0,0 +1,241 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/opena2a/identity/backend/internal/crypto"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSigningKeyRepository keeps signing keys in memory
+type fakeSigningKeyRepository struct {
+	keys map[string]*domain.SigningKey
+	now  func() time.Time
+}
+
+func (r *fakeSigningKeyRepository) Create(key *domain.SigningKey) error {
+	if _, exists := r.keys[key.ID]; exists {
+		return fmt.Errorf("failed to create signing key: duplicate")
+	}
+	copied := *key
+	r.keys[key.ID] = &copied
+	return nil
+}
+
+func (r *fakeSigningKeyRepository) List() ([]*domain.SigningKey, error) {
+	keys := []*domain.SigningKey{}
+	for _, key := range r.keys {
+		if key.ExpiresAt == nil || key.ExpiresAt.After(r.now()) {
+			copied := *key
+			keys = append(keys, &copied)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *fakeSigningKeyRepository) Promote(id string, at, retiredExpiresAt time.Time) error {
+	next, ok := r.keys[id]
+	if !ok || next.Status != domain.SigningKeyStatusNext {
+		return fmt.Errorf("signing key not found")
+	}
+	for _, key := range r.keys {
+		if key.Status == domain.SigningKeyStatusActive {
+			key.Status = domain.SigningKeyStatusRetired
+			key.RetiredAt = &at
+			key.ExpiresAt = &retiredExpiresAt
+		}
+	}
+	next.Status = domain.SigningKeyStatusActive
+	next.ActivatedAt = &at
+	return nil
+}
+
+func (r *fakeSigningKeyRepository) DeleteExpired(before time.Time) (int64, error) {
+	var deleted int64
+	for id, key := range r.keys {
+		if key.ExpiresAt != nil && key.ExpiresAt.Before(before) {
+			delete(r.keys, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *fakeSigningKeyRepository) withStatus(status domain.SigningKeyStatus) []*domain.SigningKey {
+	keys := []*domain.SigningKey{}
+	for _, key := range r.keys {
+		if key.Status == status {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func newTestKeyVault(t *testing.T) *crypto.KeyVault {
+	masterKey := make([]byte, 32)
+	_, err := rand.Read(masterKey)
+	require.NoError(t, err)
+	keyVault, err := crypto.NewKeyVault(base64.StdEncoding.EncodeToString(masterKey))
+	require.NoError(t, err)
+	return keyVault
+}
+
+func newTestSigningKeyService(t *testing.T, algorithm string, clock *time.Time) (*SigningKeyService, *fakeSigningKeyRepository) {
+	now := func() time.Time { return *clock }
+	repo := &fakeSigningKeyRepository{keys: map[string]*domain.SigningKey{}, now: now}
+	service := NewSigningKeyService(repo, newTestKeyVault(t), algorithm, 30*24*time.Hour, 90*24*time.Hour)
+	service.now = now
+	return service, repo
+}
+
+// tokenHeader decodes the JOSE header of a compact JWT
+func tokenHeader(t *testing.T, token string) map[string]interface{} {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	require.NoError(t, err)
+	header := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(raw, &header))
+	return header
+}
+
+func TestSigningKeyService_RotationKeepsKeysPublished(t *testing.T) {
+	clock := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	service, repo := newTestSigningKeyService(t, "EdDSA", &clock)
+	ctx := context.Background()
+
+	// The first run creates an active key and publishes the next one
+	require.NoError(t, service.Rotate(ctx))
+	require.Len(t, repo.withStatus(domain.SigningKeyStatusActive), 1)
+	require.Len(t, repo.withStatus(domain.SigningKeyStatusNext), 1)
+	first := repo.withStatus(domain.SigningKeyStatusActive)[0].ID
+	next := repo.withStatus(domain.SigningKeyStatusNext)[0].ID
+	assert.Len(t, service.JWKS().Keys, 2)
+
+	// Nothing changes before the rotation interval has passed
+	clock = clock.Add(29 * 24 * time.Hour)
+	require.NoError(t, service.Rotate(ctx))
+	assert.Equal(t, first, repo.withStatus(domain.SigningKeyStatusActive)[0].ID)
+
+	// The published next key takes over and the old key is retired, not removed
+	clock = clock.Add(2 * 24 * time.Hour)
+	require.NoError(t, service.Rotate(ctx))
+	assert.Equal(t, next, repo.withStatus(domain.SigningKeyStatusActive)[0].ID)
+	retired := repo.withStatus(domain.SigningKeyStatusRetired)
+	require.Len(t, retired, 1)
+	assert.Equal(t, first, retired[0].ID)
+	assert.Equal(t, clock.Add(90*24*time.Hour), *retired[0].ExpiresAt)
+	assert.Len(t, service.JWKS().Keys, 3)
+
+	// Retired keys are dropped once every token they signed has expired
+	clock = clock.Add(91 * 24 * time.Hour)
+	require.NoError(t, service.Rotate(ctx))
+	assert.NotContains(t, repo.keys, first)
+}
+
+func TestSigningKeyService_JWTServiceSignsWithKeyID(t *testing.T) {
+	t.Setenv("JWT_SECRET", "signing-key-test-secret-0123456789")
+
+	for _, algorithm := range []string{"EdDSA", "ES256"} {
+		t.Run(algorithm, func(t *testing.T) {
+			// The first key starts signing after the legacy token below is issued
+			clock := time.Now().UTC().Add(time.Minute)
+			service, _ := newTestSigningKeyService(t, algorithm, &clock)
+			require.NoError(t, service.Rotate(context.Background()))
+
+			jwtService := auth.NewJWTService()
+			legacyToken, err := jwtService.GenerateAccessToken("user-1", "org-1", "alice@acme.example.com", "admin")
+			require.NoError(t, err)
+
+			jwtService.UseSigningKeys(service, "https://aim.example.com", true)
+			accessToken, refreshToken, err := jwtService.GenerateTokenPair("user-1", "org-1", "alice@acme.example.com", "admin")
+			require.NoError(t, err)
+
+			kid, _, _, err := service.CurrentSigningKey()
+			require.NoError(t, err)
+			header := tokenHeader(t, accessToken)
+			assert.Equal(t, algorithm, header["alg"])
+			assert.Equal(t, kid, header["kid"])
+
+			claims, err := jwtService.ValidateToken(accessToken)
+			require.NoError(t, err)
+			assert.Equal(t, "https://aim.example.com", claims.Issuer)
+			assert.Equal(t, "alice@acme.example.com", claims.Email)
+			_, err = jwtService.ValidateToken(refreshToken)
+			require.NoError(t, err)
+
+			// The published key set names the signing key
+			kids := []string{}
+			for _, key := range service.JWKS().Keys {
+				kids = append(kids, key.KeyID)
+				assert.Equal(t, algorithm, key.Alg)
+			}
+			assert.Contains(t, kids, kid)
+
+			// Tokens issued before the switch stay valid only while legacy HMAC is accepted
+			_, err = jwtService.ValidateToken(legacyToken)
+			require.NoError(t, err)
+
+			// HS256 tokens claiming to be issued after the switch, or to outlive every token
+			// issued before it, are forgeries
+			forge := func(issuedAt, expiresAt time.Time) string {
+				forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.JWTClaims{
+					UserID:         "user-1",
+					OrganizationID: "org-1",
+					RegisteredClaims: jwt.RegisteredClaims{
+						Issuer:    "agent-identity-management",
+						IssuedAt:  jwt.NewNumericDate(issuedAt),
+						ExpiresAt: jwt.NewNumericDate(expiresAt),
+					},
+				}).SignedString([]byte("signing-key-test-secret-0123456789"))
+				require.NoError(t, err)
+				return forged
+			}
+			_, err = jwtService.ValidateToken(forge(clock.Add(time.Hour), clock.Add(2*time.Hour)))
+			assert.Error(t, err)
+			_, err = jwtService.ValidateToken(forge(clock.Add(-time.Hour), clock.Add(365*24*time.Hour)))
+			assert.Error(t, err)
+			_, err = jwtService.ValidateToken(forge(clock.Add(-time.Hour), clock.Add(time.Hour)))
+			assert.NoError(t, err)
+			jwtService.UseSigningKeys(service, "https://aim.example.com", false)
+			_, err = jwtService.ValidateToken(legacyToken)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSigningKeyService_RetiredKeysStillVerify(t *testing.T) {
+	t.Setenv("JWT_SECRET", "signing-key-test-secret-0123456789")
+	clock := time.Now().UTC()
+	service, _ := newTestSigningKeyService(t, "EdDSA", &clock)
+	ctx := context.Background()
+	require.NoError(t, service.Rotate(ctx))
+
+	jwtService := auth.NewJWTService()
+	jwtService.UseSigningKeys(service, "https://aim.example.com", false)
+	token, err := jwtService.GenerateRefreshToken("user-1", "org-1")
+	require.NoError(t, err)
+	oldKid := tokenHeader(t, token)["kid"]
+
+	// Rotate: the token's key is retired but still published
+	clock = clock.Add(31 * 24 * time.Hour)
+	require.NoError(t, service.Rotate(ctx))
+	clock = time.Now().UTC()
+
+	newToken, err := jwtService.GenerateRefreshToken("user-1", "org-1")
+	require.NoError(t, err)
+	assert.NotEqual(t, oldKid, tokenHeader(t, newToken)["kid"])
+	_, err = jwtService.ValidateToken(token)
+	require.NoError(t, err)
+}
@@ -18,6 +18,7 @@ type TrustCalculator struct {
 	capabilityRepo   domain.CapabilityRepository
 	agentRepo        domain.AgentRepository
 	alertRepo        domain.AlertRepository
+	mcpHealthRepo    domain.MCPServerHealthRepository
 }
 
 // NewTrustCalculator creates a new trust calculator
@@ -28,6 +29,7 @@ func NewTrustCalculator(
 	capabilityRepo domain.CapabilityRepository,
 	agentRepo domain.AgentRepository,
 	alertRepo domain.AlertRepository,
+	mcpHealthRepo domain.MCPServerHealthRepository,
 ) *TrustCalculator {
 	return &TrustCalculator{
 		trustScoreRepo:   trustScoreRepo,
@@ -36,6 +38,7 @@ func NewTrustCalculator(
 		capabilityRepo:   capabilityRepo,
 		agentRepo:        agentRepo,
 		alertRepo:        alertRepo,
+		mcpHealthRepo:    mcpHealthRepo,
 	}
 }
 
@@ -156,9 +159,16 @@ func (c *TrustCalculator) calculateVerificationStatus(agent *domain.Agent) float
 // Factor 2: Uptime & Availability (15% weight)
 // Measures how often agent responds to health checks
 func (c *TrustCalculator) calculateUptime(agent *domain.Agent) float64 {
-	// TODO: Query agent_health_checks table
-	// Calculate: successful_health_checks / total_health_checks
-	// For MVP: Return baseline based on agent status
+	// Use the scheduled health checks of the MCP servers this agent depends on:
+	// successful_health_checks / total_health_checks over the last 30 days
+	if c.mcpHealthRepo != nil && len(agent.TalksTo) > 0 {
+		uptime, err := c.mcpHealthRepo.GetUptimeForServerRefs(agent.OrganizationID, agent.TalksTo, time.Now().Add(-MCPHealthUptimeWindow))
+		if err == nil && uptime.TotalChecks > 0 {
+			return uptime.Uptime
+		}
+	}
+
+	// No health history yet: fall back to a baseline based on agent status
 	if agent.Status == domain.AgentStatusVerified {
 		return 0.98 // Assume 98% uptime for verified agents
 	} else if agent.Status == domain.AgentStatusPending {
@@ -222,30 +222,76 @@ func generateExpiredCertificate() string {
 	return string(certPEM)
 }
 
+// newTestTrustCalculator creates a trust calculator with mocked repositories
+func newTestTrustCalculator(mockCapabilityRepo *MockCapabilityRepository) *TrustCalculator {
+	return NewTrustCalculator(
+		new(AgentServiceMockTrustScoreRepository),
+		new(MockAPIKeyRepository),
+		new(AgentServiceMockAuditLogRepository),
+		mockCapabilityRepo,
+		new(MockAgentRepository),
+		new(MockAlertRepository),
+		nil,
+	)
+}
+
+// assertFactorsUnchanged checks that changing an agent's self-reported metadata
+// does not move any of the 8 trust factors
+func assertFactorsUnchanged(t *testing.T, agent *domain.Agent, change func(agent *domain.Agent)) {
+	t.Helper()
+
+	mockCapabilityRepo := new(MockCapabilityRepository)
+	mockCapabilityRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
+	calculator := newTestTrustCalculator(mockCapabilityRepo)
+
+	before, err := calculator.CalculateFactors(agent)
+	assert.NoError(t, err)
+
+	changed := *agent
+	change(&changed)
+	after, err := calculator.CalculateFactors(&changed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, before, after)
+}
+
+// newTestViolation creates a capability violation of the given severity and age
+func newTestViolation(agentID uuid.UUID, severity string, age time.Duration) *domain.CapabilityViolation {
+	return &domain.CapabilityViolation{
+		ID:                  uuid.New(),
+		AgentID:             agentID,
+		AttemptedCapability: domain.CapabilitySystemAdmin,
+		Severity:            severity,
+		IsBlocked:           true,
+		CreatedAt:           time.Now().Add(-age),
+	}
+}
+
 // ============================================================================
 // TEST: Weight Validation
 // ============================================================================
 
 func TestTrustCalculator_WeightsSum(t *testing.T) {
-	// Verify all weights sum to exactly 1.0 (100%)
-	weights := []float64{
-		0.18, // verification
-		0.12, // certificate
-		0.12, // repository
-		0.08, // documentation
-		0.08, // community
-		0.12, // security
-		0.08, // updates
-		0.05, // age
-		0.17, // capability_risk
-	}
+	// A perfect score on every factor must yield exactly 1.0 (weights total 100%)
+	mockCapabilityRepo := new(MockCapabilityRepository)
+	calculator := newTestTrustCalculator(mockCapabilityRepo)
 
-	sum := 0.0
-	for _, w := range weights {
-		sum += w
+	agent := &domain.Agent{
+		ID:        uuid.New(),
+		Status:    domain.AgentStatusVerified,
+		CreatedAt: time.Now().Add(-200 * 24 * time.Hour),
 	}
+	mockCapabilityRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
+
+	score, err := calculator.Calculate(agent)
+	assert.NoError(t, err)
+
+	// Verification 0.25, uptime 0.15 × 0.98, success rate 0.15 × 0.95, security alerts 0.15,
+	// compliance 0.10, age 0.10, drift detection 0.05, user feedback 0.05 × 0.75
+	expected := 0.25 + 0.15*0.98 + 0.15*0.95 + 0.15 + 0.10 + 0.10 + 0.05 + 0.05*0.75
+	assert.InDelta(t, expected, score.Score, 0.0001)
 
-	assert.Equal(t, 1.0, sum, "Weights must sum to exactly 1.0")
+	mockCapabilityRepo.AssertExpectations(t)
 }
 
 // ============================================================================
@@ -253,13 +299,8 @@ func TestTrustCalculator_WeightsSum(t *testing.T) {
 // ============================================================================
 
 func TestTrustCalculator_Calculate_AllFactorsPerfectScore(t *testing.T) {
-	// Setup mocks
-	mockTrustRepo := new(AgentServiceMockTrustScoreRepository)
-	mockAPIKeyRepo := new(MockAPIKeyRepository)
-	mockAuditRepo := new(AgentServiceMockAuditLogRepository)
 	mockCapabilityRepo := new(MockCapabilityRepository)
-
-	calculator := NewTrustCalculator(mockTrustRepo, mockAPIKeyRepo, mockAuditRepo, mockCapabilityRepo)
+	calculator := newTestTrustCalculator(mockCapabilityRepo)
 
 	// Create agent with perfect conditions
 	validCert := generateValidCertificate()
@@ -271,16 +312,14 @@ func TestTrustCalculator_Calculate_AllFactorsPerfectScore(t *testing.T) {
 		RepositoryURL:    "https://github.com/test/repo",
 		DocumentationURL: "https://docs.example.com",
 		Description:      "This is a comprehensive description that is definitely longer than 50 characters to ensure proper scoring.",
-		UpdatedAt:        time.Now().Add(-15 * 24 * time.Hour), // Updated recently
+		UpdatedAt:        time.Now().Add(-15 * 24 * time.Hour),  // Updated recently
 		CreatedAt:        time.Now().Add(-200 * 24 * time.Hour), // Old enough for max age score
 		Version:          "1.0.0",
 	}
 
-	// Mock no capabilities (neutral risk)
-	mockCapabilityRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{}, nil)
+	// No violations (perfect security score)
 	mockCapabilityRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
 
-	// Calculate trust score
 	score, err := calculator.Calculate(agent)
 
 	assert.NoError(t, err)
@@ -290,35 +329,29 @@ func TestTrustCalculator_Calculate_AllFactorsPerfectScore(t *testing.T) {
 	assert.Equal(t, agent.ID, score.AgentID)
 	assert.NotZero(t, score.LastCalculated)
 
-	// Verify individual factors are within range
-	assert.GreaterOrEqual(t, score.Factors.VerificationStatus, 0.0)
-	assert.LessOrEqual(t, score.Factors.VerificationStatus, 1.0)
-	assert.GreaterOrEqual(t, score.Factors.CertificateValidity, 0.0)
-	assert.LessOrEqual(t, score.Factors.CertificateValidity, 1.0)
-	assert.GreaterOrEqual(t, score.Factors.CapabilityRisk, 0.0)
-	assert.LessOrEqual(t, score.Factors.CapabilityRisk, 1.0)
+	// Verify individual factors
+	assert.Equal(t, 1.0, score.Factors.VerificationStatus)
+	assert.Equal(t, 1.0, score.Factors.SecurityAlerts)
+	assert.Equal(t, 1.0, score.Factors.Age)
+	assert.Greater(t, score.Score, 0.9, "Verified agent with history and no violations should score > 0.9")
 
 	mockCapabilityRepo.AssertExpectations(t)
 }
 
 func TestTrustCalculator_Calculate_MinimalAgent(t *testing.T) {
-	mockTrustRepo := new(AgentServiceMockTrustScoreRepository)
-	mockAPIKeyRepo := new(MockAPIKeyRepository)
-	mockAuditRepo := new(AgentServiceMockAuditLogRepository)
 	mockCapabilityRepo := new(MockCapabilityRepository)
+	calculator := newTestTrustCalculator(mockCapabilityRepo)
 
-	calculator := NewTrustCalculator(mockTrustRepo, mockAPIKeyRepo, mockAuditRepo, mockCapabilityRepo)
-
-	// Create minimal agent (pending status, no cert, no docs)
+	// Create minimal agent (revoked, brand new, with a critical violation)
 	agent := &domain.Agent{
 		ID:        uuid.New(),
-		Status:    domain.AgentStatusPending,
+		Status:    domain.AgentStatusRevoked,
 		UpdatedAt: time.Now(),
 		CreatedAt: time.Now(),
 	}
 
-	mockCapabilityRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{}, nil)
-	mockCapabilityRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
+	violations := []*domain.CapabilityViolation{newTestViolation(agent.ID, domain.ViolationSeverityCritical, time.Hour)}
+	mockCapabilityRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(violations, 1, nil)
 
 	score, err := calculator.Calculate(agent)
 
@@ -383,8 +416,10 @@ func TestTrustCalculator_VerificationStatus_Unknown(t *testing.T) {
 }
 
 // ============================================================================
-// TEST: calculateCertificateValidity()
+// TEST: certificate inputs
 // ============================================================================
+// The 8-factor model has no certificate factor: a public key only counts as a
+// confidence data point and never raises the verification factor on its own.
 
 func TestTrustCalculator_CertificateValidity_ValidCertificate(t *testing.T) {
 	calculator := &TrustCalculator{}
@@ -393,11 +428,11 @@ func TestTrustCalculator_CertificateValidity_ValidCertificate(t *testing.T) {
 	agent := &domain.Agent{
 		CertificateURL: "https://example.com/cert.pem",
 		PublicKey:      &validCert,
+		CreatedAt:      time.Now(),
 	}
 
-	score := calculator.calculateCertificateValidity(agent)
-
-	assert.Equal(t, 1.0, score, "Valid certificate should get score of 1.0")
+	assert.Equal(t, 0.3, calculator.calculateVerificationStatus(agent), "A certificate alone should not verify an agent")
+	assert.InDelta(t, 1.0/8.0, calculator.calculateConfidence(agent, &domain.TrustScoreFactors{}), 0.0001)
 }
 
 func TestTrustCalculator_CertificateValidity_ExpiredCertificate(t *testing.T) {
@@ -407,11 +442,11 @@ func TestTrustCalculator_CertificateValidity_ExpiredCertificate(t *testing.T) {
 	agent := &domain.Agent{
 		CertificateURL: "https://example.com/cert.pem",
 		PublicKey:      &expiredCert,
+		CreatedAt:      time.Now(),
 	}
 
-	score := calculator.calculateCertificateValidity(agent)
-
-	assert.Equal(t, 0.2, score, "Expired certificate should get score of 0.2")
+	assert.Equal(t, 0.3, calculator.calculateVerificationStatus(agent))
+	assert.InDelta(t, 1.0/8.0, calculator.calculateConfidence(agent, &domain.TrustScoreFactors{}), 0.0001)
 }
 
 func TestTrustCalculator_CertificateValidity_NoCertificateURL(t *testing.T) {
@@ -419,11 +454,10 @@ func TestTrustCalculator_CertificateValidity_NoCertificateURL(t *testing.T) {
 
 	agent := &domain.Agent{
 		CertificateURL: "",
+		CreatedAt:      time.Now(),
 	}
 
-	score := calculator.calculateCertificateValidity(agent)
-
-	assert.Equal(t, 0.0, score, "No certificate URL should get score of 0.0")
+	assert.Equal(t, 0.0, calculator.calculateConfidence(agent, &domain.TrustScoreFactors{}), "No key material should add no confidence")
 }
 
 func TestTrustCalculator_CertificateValidity_NoPublicKey(t *testing.T) {
@@ -432,11 +466,10 @@ func TestTrustCalculator_CertificateValidity_NoPublicKey(t *testing.T) {
 	agent := &domain.Agent{
 		CertificateURL: "https://example.com/cert.pem",
 		PublicKey:      nil,
+		CreatedAt:      time.Now(),
 	}
 
-	score := calculator.calculateCertificateValidity(agent)
-
-	assert.Equal(t, 0.3, score, "No public key should get score of 0.3")
+	assert.Equal(t, 0.0, calculator.calculateConfidence(agent, &domain.TrustScoreFactors{}), "A certificate URL without a public key should add no confidence")
 }
 
 func TestTrustCalculator_CertificateValidity_InvalidPEM(t *testing.T) {
@@ -446,874 +479,299 @@ func TestTrustCalculator_CertificateValidity_InvalidPEM(t *testing.T) {
 	agent := &domain.Agent{
 		CertificateURL: "https://example.com/cert.pem",
 		PublicKey:      &invalidPEM,
+		CreatedAt:      time.Now(),
 	}
 
-	score := calculator.calculateCertificateValidity(agent)
-
-	assert.Equal(t, 0.3, score, "Invalid PEM should get score of 0.3")
+	assert.Equal(t, 0.3, calculator.calculateVerificationStatus(agent))
+	assert.InDelta(t, 1.0/8.0, calculator.calculateConfidence(agent, &domain.TrustScoreFactors{}), 0.0001)
 }
 
 // ============================================================================
-// TEST: calculateRepositoryQuality()
+// TEST: repository inputs
 // ============================================================================
+// Repository URLs are self-reported and are not scored.
 
 func TestTrustCalculator_RepositoryQuality_NoRepositoryURL(t *testing.T) {
-	calculator := &TrustCalculator{}
-
-	agent := &domain.Agent{RepositoryURL: ""}
-	score := calculator.calculateRepositoryQuality(agent)
+	agent := &domain.Agent{ID: uuid.New(), Status: domain.AgentStatusVerified, RepositoryURL: ""}
 
-	assert.Equal(t, 0.0, score, "No repository URL should get score of 0.0")
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.RepositoryURL = "https://github.com/test/repo" })
 }
 
 func TestTrustCalculator_RepositoryQuality_InvalidURL(t *testing.T) {
-	calculator := &TrustCalculator{}
-
-	agent := &domain.Agent{RepositoryURL: "not a valid url"}
-	score := calculator.calculateRepositoryQuality(agent)
+	agent := &domain.Agent{ID: uuid.New(), Status: domain.AgentStatusVerified, RepositoryURL: "not a valid url"}
 
-	assert.Equal(t, 0.0, score, "Invalid URL should get score of 0.0")
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.RepositoryURL = "" })
 }
 
 func TestTrustCalculator_RepositoryQuality_GitHubURL(t *testing.T) {
-	calculator := &TrustCalculator{}
+	agent := &domain.Agent{ID: uuid.New(), Status: domain.AgentStatusVerified, RepositoryURL: "https://github.com/test/repo"}
 
-	agent := &domain.Agent{RepositoryURL: "https://github.com/test/repo"}
-	score := calculator.calculateRepositoryQuality(agent)
-
-	// GitHub URL gets 0.5 for known hosting service
-	assert.GreaterOrEqual(t, score, 0.5, "GitHub URL should get at least 0.5")
-	assert.LessOrEqual(t, score, 1.0)
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.RepositoryURL = "" })
 }
 
 func TestTrustCalculator_RepositoryQuality_GitLabURL(t *testing.T) {
-	calculator := &TrustCalculator{}
+	agent := &domain.Agent{ID: uuid.New(), Status: domain.AgentStatusVerified, RepositoryURL: "https://gitlab.com/test/repo"}
 
-	agent := &domain.Agent{RepositoryURL: "https://gitlab.com/test/repo"}
-	score := calculator.calculateRepositoryQuality(agent)
-
-	assert.GreaterOrEqual(t, score, 0.5, "GitLab URL should get at least 0.5")
-	assert.LessOrEqual(t, score, 1.0)
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.RepositoryURL = "" })
 }
 
 func TestTrustCalculator_RepositoryQuality_BitbucketURL(t *testing.T) {
-	calculator := &TrustCalculator{}
-
-	agent := &domain.Agent{RepositoryURL: "https://bitbucket.org/test/repo"}
-	score := calculator.calculateRepositoryQuality(agent)
+	agent := &domain.Agent{ID: uuid.New(), Status: domain.AgentStatusVerified, RepositoryURL: "https://bitbucket.org/test/repo"}
 
-	assert.GreaterOrEqual(t, score, 0.5, "Bitbucket URL should get at least 0.5")
-	assert.LessOrEqual(t, score, 1.0)
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.RepositoryURL = "" })
 }
 
 // ============================================================================
-// TEST: calculateDocumentationScore()
+// TEST: documentation inputs
 // ============================================================================
+// Descriptions and documentation links are self-reported and are not scored.
 
 func TestTrustCalculator_DocumentationScore_NoDocumentation(t *testing.T) {
-	calculator := &TrustCalculator{}
-
-	agent := &domain.Agent{
-		Description:      "",
-		DocumentationURL: "",
-	}
-
-	score := calculator.calculateDocumentationScore(agent)
+	agent := &domain.Agent{ID: uuid.New(), Status: domain.AgentStatusVerified}
 
-	assert.Equal(t, 0.0, score, "No documentation should get score of 0.0")
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) {
+		agent.Description = "This is a comprehensive description that is definitely longer than 50 characters."
+		agent.DocumentationURL = "https://docs.example.com"
+	})
 }
 
 func TestTrustCalculator_DocumentationScore_ShortDescription(t *testing.T) {
-	calculator := &TrustCalculator{}
+	agent := &domain.Agent{ID: uuid.New(), Status: domain.AgentStatusVerified, Description: "Short"}
 
-	agent := &domain.Agent{
-		Description:      "Short",
-		DocumentationURL: "",
-	}
-
-	score := calculator.calculateDocumentationScore(agent)
-
-	assert.Equal(t, 0.0, score, "Short description (<50 chars) should get score of 0.0")
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.Description = "" })
 }
 
 func TestTrustCalculator_DocumentationScore_LongDescription(t *testing.T) {
-	calculator := &TrustCalculator{}
-
 	agent := &domain.Agent{
-		Description:      "This is a comprehensive description that is definitely longer than 50 characters.",
-		DocumentationURL: "",
+		ID:          uuid.New(),
+		Status:      domain.AgentStatusVerified,
+		Description: "This is a comprehensive description that is definitely longer than 50 characters.",
 	}
 
-	score := calculator.calculateDocumentationScore(agent)
-
-	assert.Equal(t, 0.3, score, "Long description should get score of 0.3")
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.Description = "" })
 }
 
 func TestTrustCalculator_DocumentationScore_WithDocURL(t *testing.T) {
-	calculator := &TrustCalculator{}
-
 	agent := &domain.Agent{
+		ID:               uuid.New(),
+		Status:           domain.AgentStatusVerified,
 		Description:      "This is a comprehensive description that is definitely longer than 50 characters.",
 		DocumentationURL: "https://docs.example.com",
 	}
 
-	score := calculator.calculateDocumentationScore(agent)
-
-	// Score should be at least 0.6 (0.3 for description + 0.3 for URL)
-	assert.GreaterOrEqual(t, score, 0.6, "Description + doc URL should get at least 0.6")
-	assert.LessOrEqual(t, score, 1.0)
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.DocumentationURL = "" })
 }
 
 // ============================================================================
-// TEST: calculateUpdateFrequency()
+// TEST: update inputs
 // ============================================================================
+// Age & History is measured from creation; metadata updates do not count.
 
 func TestTrustCalculator_UpdateFrequency_RecentUpdate(t *testing.T) {
-	calculator := &TrustCalculator{}
+	agent := &domain.Agent{ID: uuid.New(), CreatedAt: time.Now().Add(-400 * 24 * time.Hour)}
 
-	agent := &domain.Agent{
-		UpdatedAt: time.Now().Add(-15 * 24 * time.Hour), // 15 days ago
-	}
-
-	score := calculator.calculateUpdateFrequency(agent)
-
-	assert.Equal(t, 1.0, score, "Update within 30 days should get score of 1.0")
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.UpdatedAt = time.Now().Add(-15 * 24 * time.Hour) })
 }
 
 func TestTrustCalculator_UpdateFrequency_30To90Days(t *testing.T) {
-	calculator := &TrustCalculator{}
+	agent := &domain.Agent{ID: uuid.New(), CreatedAt: time.Now().Add(-400 * 24 * time.Hour)}
 
-	agent := &domain.Agent{
-		UpdatedAt: time.Now().Add(-60 * 24 * time.Hour), // 60 days ago
-	}
-
-	score := calculator.calculateUpdateFrequency(agent)
-
-	assert.Equal(t, 0.7, score, "Update within 90 days should get score of 0.7")
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.UpdatedAt = time.Now().Add(-60 * 24 * time.Hour) })
 }
 
 func TestTrustCalculator_UpdateFrequency_90To180Days(t *testing.T) {
-	calculator := &TrustCalculator{}
+	agent := &domain.Agent{ID: uuid.New(), CreatedAt: time.Now().Add(-400 * 24 * time.Hour)}
 
-	agent := &domain.Agent{
-		UpdatedAt: time.Now().Add(-120 * 24 * time.Hour), // 120 days ago
-	}
-
-	score := calculator.calculateUpdateFrequency(agent)
-
-	assert.Equal(t, 0.5, score, "Update within 180 days should get score of 0.5")
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.UpdatedAt = time.Now().Add(-120 * 24 * time.Hour) })
 }
 
 func TestTrustCalculator_UpdateFrequency_180To365Days(t *testing.T) {
-	calculator := &TrustCalculator{}
+	agent := &domain.Agent{ID: uuid.New(), CreatedAt: time.Now().Add(-400 * 24 * time.Hour)}
 
-	agent := &domain.Agent{
-		UpdatedAt: time.Now().Add(-200 * 24 * time.Hour), // 200 days ago
-	}
-
-	score := calculator.calculateUpdateFrequency(agent)
-
-	assert.Equal(t, 0.3, score, "Update within 365 days should get score of 0.3")
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.UpdatedAt = time.Now().Add(-200 * 24 * time.Hour) })
 }
 
 func TestTrustCalculator_UpdateFrequency_Over365Days(t *testing.T) {
-	calculator := &TrustCalculator{}
+	agent := &domain.Agent{ID: uuid.New(), CreatedAt: time.Now().Add(-400 * 24 * time.Hour)}
 
-	agent := &domain.Agent{
-		UpdatedAt: time.Now().Add(-400 * 24 * time.Hour), // 400 days ago
-	}
+	assertFactorsUnchanged(t, agent, func(agent *domain.Agent) { agent.UpdatedAt = time.Now().Add(-400 * 24 * time.Hour) })
+}
+
+// ============================================================================
+// TEST: calculateUptime()
+// ============================================================================
 
-	score := calculator.calculateUpdateFrequency(agent)
+func TestTrustCalculator_Uptime_BaselineWithoutHealthHistory(t *testing.T) {
+	calculator := &TrustCalculator{}
 
-	assert.Equal(t, 0.1, score, "Update over 365 days ago should get score of 0.1")
+	assert.Equal(t, 0.98, calculator.calculateUptime(&domain.Agent{Status: domain.AgentStatusVerified}))
+	assert.Equal(t, 0.75, calculator.calculateUptime(&domain.Agent{Status: domain.AgentStatusPending}))
+	assert.Equal(t, 0.50, calculator.calculateUptime(&domain.Agent{Status: domain.AgentStatusSuspended}))
 }
 
 // ============================================================================
-// TEST: calculateAgeScore()
+// TEST: calculateAge()
 // ============================================================================
 
-func TestTrustCalculator_AgeScore_LessThan7Days(t *testing.T) {
+func TestTrustCalculator_Age_LessThan7Days(t *testing.T) {
 	calculator := &TrustCalculator{}
 
 	agent := &domain.Agent{
 		CreatedAt: time.Now().Add(-5 * 24 * time.Hour), // 5 days old
 	}
 
-	score := calculator.calculateAgeScore(agent)
+	score := calculator.calculateAge(agent)
 
-	assert.Equal(t, 0.2, score, "Agent less than 7 days old should get score of 0.2")
+	assert.Equal(t, 0.30, score, "Agent less than 7 days old should get score of 0.30")
 }
 
-func TestTrustCalculator_AgeScore_7To30Days(t *testing.T) {
+func TestTrustCalculator_Age_7To30Days(t *testing.T) {
 	calculator := &TrustCalculator{}
 
 	agent := &domain.Agent{
 		CreatedAt: time.Now().Add(-20 * 24 * time.Hour), // 20 days old
 	}
 
-	score := calculator.calculateAgeScore(agent)
+	score := calculator.calculateAge(agent)
 
-	assert.Equal(t, 0.4, score, "Agent 7-30 days old should get score of 0.4")
+	assert.Equal(t, 0.50, score, "Agent 7-30 days old should get score of 0.50")
 }
 
-func TestTrustCalculator_AgeScore_30To90Days(t *testing.T) {
+func TestTrustCalculator_Age_30To90Days(t *testing.T) {
 	calculator := &TrustCalculator{}
 
 	agent := &domain.Agent{
 		CreatedAt: time.Now().Add(-60 * 24 * time.Hour), // 60 days old
 	}
 
-	score := calculator.calculateAgeScore(agent)
+	score := calculator.calculateAge(agent)
 
-	assert.Equal(t, 0.6, score, "Agent 30-90 days old should get score of 0.6")
+	assert.Equal(t, 0.75, score, "Agent 30-90 days old should get score of 0.75")
 }
 
-func TestTrustCalculator_AgeScore_90To180Days(t *testing.T) {
+func TestTrustCalculator_Age_Over90Days(t *testing.T) {
 	calculator := &TrustCalculator{}
 
 	agent := &domain.Agent{
 		CreatedAt: time.Now().Add(-120 * 24 * time.Hour), // 120 days old
 	}
 
-	score := calculator.calculateAgeScore(agent)
-
-	assert.Equal(t, 0.8, score, "Agent 90-180 days old should get score of 0.8")
-}
-
-func TestTrustCalculator_AgeScore_Over180Days(t *testing.T) {
-	calculator := &TrustCalculator{}
-
-	agent := &domain.Agent{
-		CreatedAt: time.Now().Add(-200 * 24 * time.Hour), // 200 days old
-	}
-
-	score := calculator.calculateAgeScore(agent)
+	score := calculator.calculateAge(agent)
 
-	assert.Equal(t, 1.0, score, "Agent over 180 days old should get score of 1.0")
+	assert.Equal(t, 1.0, score, "Agent over 90 days old should get score of 1.0")
 }
 
-// Test 1: Agent with no capabilities (neutral baseline)
-func TestCalculateCapabilityRisk_NoCapabilities(t *testing.T) {
-	mockRepo := new(MockCapabilityRepository)
-	calculator := &TrustCalculator{
-		capabilityRepo: mockRepo,
-	}
-
-	agent := &domain.Agent{
-		ID:        uuid.New(),
-		Name:      "test-agent",
-		AgentType: domain.AgentTypeAI,
-		Status:    domain.AgentStatusVerified,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Mock: No capabilities
-	mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{}, nil)
-	// Mock: No violations
-	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
-
-	score := calculator.calculateCapabilityRisk(agent)
-
-	assert.Equal(t, 0.7, score, "Agent with no capabilities should have neutral score of 0.7")
-	mockRepo.AssertExpectations(t)
-}
+// ============================================================================
+// TEST: calculateSecurityAlerts()
+// ============================================================================
 
-// Test 2: Agent with only low-risk capabilities
-func TestCalculateCapabilityRisk_LowRiskOnly(t *testing.T) {
+func TestCalculateSecurityAlerts_NoViolations(t *testing.T) {
 	mockRepo := new(MockCapabilityRepository)
-	calculator := &TrustCalculator{
-		capabilityRepo: mockRepo,
-	}
-
-	agent := &domain.Agent{
-		ID:        uuid.New(),
-		Name:      "low-risk-agent",
-		AgentType: domain.AgentTypeAI,
-		Status:    domain.AgentStatusVerified,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
+	calculator := &TrustCalculator{capabilityRepo: mockRepo}
 
-	// Mock: Low-risk capabilities (file:read, db:query)
-	capabilities := []*domain.AgentCapability{
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilityFileRead,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilityDBQuery,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
-	}
-	mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return(capabilities, nil)
+	agent := &domain.Agent{ID: uuid.New(), Status: domain.AgentStatusVerified}
 	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
 
-	score := calculator.calculateCapabilityRisk(agent)
+	score := calculator.calculateSecurityAlerts(agent)
 
-	expectedScore := 0.7 - 0.03 - 0.03 // 0.64
-	assert.InDelta(t, expectedScore, score, 0.001, "Low-risk capabilities should have minor penalties")
+	assert.Equal(t, 1.0, score, "Agent without violations should have a perfect security score")
 	mockRepo.AssertExpectations(t)
 }
 
-// Test 3: Agent with high-risk capabilities
-func TestCalculateCapabilityRisk_HighRiskCapabilities(t *testing.T) {
-	mockRepo := new(MockCapabilityRepository)
-	calculator := &TrustCalculator{
-		capabilityRepo: mockRepo,
-	}
-
-	agent := &domain.Agent{
-		ID:        uuid.New(),
-		Name:      "high-risk-agent",
-		AgentType: domain.AgentTypeAI,
-		Status:    domain.AgentStatusVerified,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Mock: High-risk capabilities (system:admin, user:impersonate)
-	capabilities := []*domain.AgentCapability{
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilitySystemAdmin,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilityUserImpersonate,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
+func TestCalculateSecurityAlerts_BySeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity string
+		want     float64
+	}{
+		{"critical", domain.ViolationSeverityCritical, 0.0},
+		{"high", domain.ViolationSeverityHigh, 0.50},
+		{"medium", domain.ViolationSeverityMedium, 0.75},
+		{"low", domain.ViolationSeverityLow, 1.0},
 	}
-	mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return(capabilities, nil)
-	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
-
-	score := calculator.calculateCapabilityRisk(agent)
-
-	expectedScore := 0.7 - 0.20 - 0.20 // 0.30
-	assert.InDelta(t, expectedScore, score, 0.001, "High-risk capabilities should have major penalties")
-	mockRepo.AssertExpectations(t)
-}
 
-// Test 4: Agent with medium-risk capabilities
-func TestCalculateCapabilityRisk_MediumRiskCapabilities(t *testing.T) {
-	mockRepo := new(MockCapabilityRepository)
-	calculator := &TrustCalculator{
-		capabilityRepo: mockRepo,
-	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCapabilityRepository)
+			calculator := &TrustCalculator{capabilityRepo: mockRepo}
 
-	agent := &domain.Agent{
-		ID:        uuid.New(),
-		Name:      "medium-risk-agent",
-		AgentType: domain.AgentTypeAI,
-		Status:    domain.AgentStatusVerified,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
+			agent := &domain.Agent{ID: uuid.New()}
+			violations := []*domain.CapabilityViolation{newTestViolation(agent.ID, tt.severity, 24*time.Hour)}
+			mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(violations, 1, nil)
 
-	// Mock: Medium-risk capabilities (file:write, db:write, api:call)
-	capabilities := []*domain.AgentCapability{
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilityFileWrite,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilityDBWrite,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilityAPICall,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
+			assert.Equal(t, tt.want, calculator.calculateSecurityAlerts(agent))
+			mockRepo.AssertExpectations(t)
+		})
 	}
-	mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return(capabilities, nil)
-	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
-
-	score := calculator.calculateCapabilityRisk(agent)
-
-	expectedScore := 0.7 - 0.08 - 0.08 - 0.05 // 0.49
-	assert.InDelta(t, expectedScore, score, 0.001, "Medium-risk capabilities should have moderate penalties")
-	mockRepo.AssertExpectations(t)
 }
 
-// Test 5: Agent with recent CRITICAL violations
-func TestCalculateCapabilityRisk_CriticalViolations(t *testing.T) {
+func TestCalculateSecurityAlerts_MostSevereViolationWins(t *testing.T) {
 	mockRepo := new(MockCapabilityRepository)
-	calculator := &TrustCalculator{
-		capabilityRepo: mockRepo,
-	}
-
-	agent := &domain.Agent{
-		ID:        uuid.New(),
-		Name:      "violation-agent",
-		AgentType: domain.AgentTypeAI,
-		Status:    domain.AgentStatusVerified,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Mock: Single low-risk capability
-	capabilities := []*domain.AgentCapability{
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilityFileRead,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
-	}
+	calculator := &TrustCalculator{capabilityRepo: mockRepo}
 
-	// Mock: 3 recent CRITICAL violations (last 7 days)
-	now := time.Now()
+	agent := &domain.Agent{ID: uuid.New()}
 	violations := []*domain.CapabilityViolation{
-		{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilitySystemAdmin,
-			Severity:            domain.ViolationSeverityCritical,
-			TrustScoreImpact:    -15,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -1), // 1 day ago
-		},
-		{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilitySystemAdmin,
-			Severity:            domain.ViolationSeverityCritical,
-			TrustScoreImpact:    -15,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -5), // 5 days ago
-		},
-		{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilitySystemAdmin,
-			Severity:            domain.ViolationSeverityCritical,
-			TrustScoreImpact:    -15,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -7), // 7 days ago
-		},
+		newTestViolation(agent.ID, domain.ViolationSeverityLow, 1*24*time.Hour),
+		newTestViolation(agent.ID, domain.ViolationSeverityMedium, 2*24*time.Hour),
+		newTestViolation(agent.ID, domain.ViolationSeverityHigh, 3*24*time.Hour),
 	}
-
-	mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return(capabilities, nil)
 	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(violations, 3, nil)
 
-	score := calculator.calculateCapabilityRisk(agent)
-
-	// Expected: 0.7 - 0.03 (file:read) - (3 * 0.15) (critical violations) = 0.22
-	expectedScore := 0.7 - 0.03 - (3 * 0.15)
-	assert.InDelta(t, expectedScore, score, 0.001, "CRITICAL violations should heavily impact trust")
-	mockRepo.AssertExpectations(t)
-}
-
-// Test 6: Agent with many violations (volume penalty)
-func TestCalculateCapabilityRisk_HighViolationVolume(t *testing.T) {
-	mockRepo := new(MockCapabilityRepository)
-	calculator := &TrustCalculator{
-		capabilityRepo: mockRepo,
-	}
-
-	agent := &domain.Agent{
-		ID:        uuid.New(),
-		Name:      "high-volume-violation-agent",
-		AgentType: domain.AgentTypeAI,
-		Status:    domain.AgentStatusVerified,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Mock: No capabilities
-	mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{}, nil)
-
-	// Mock: 12 recent LOW violations (triggers volume penalty)
-	now := time.Now()
-	violations := make([]*domain.CapabilityViolation, 12)
-	for i := 0; i < 12; i++ {
-		violations[i] = &domain.CapabilityViolation{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilityFileWrite,
-			Severity:            domain.ViolationSeverityLow,
-			TrustScoreImpact:    -2,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -i-1),
-		}
-	}
-
-	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(violations, 12, nil)
-
-	score := calculator.calculateCapabilityRisk(agent)
-
-	// Expected: 0.7 - (12 * 0.02) - 0.20 (volume penalty) = 0.26
-	expectedScore := 0.7 - (12 * 0.02) - 0.20
-	assert.InDelta(t, expectedScore, score, 0.001, "High violation volume should trigger additional penalty")
-	mockRepo.AssertExpectations(t)
-}
-
-// Test 7: Score bounds enforcement (cannot go below 0)
-func TestCalculateCapabilityRisk_ScoreBoundsMinimum(t *testing.T) {
-	mockRepo := new(MockCapabilityRepository)
-	calculator := &TrustCalculator{
-		capabilityRepo: mockRepo,
-	}
-
-	agent := &domain.Agent{
-		ID:        uuid.New(),
-		Name:      "extreme-risk-agent",
-		AgentType: domain.AgentTypeAI,
-		Status:    domain.AgentStatusVerified,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Mock: All high-risk capabilities
-	capabilities := []*domain.AgentCapability{
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilitySystemAdmin,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilityUserImpersonate,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilityFileDelete,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
-		{
-			ID:             uuid.New(),
-			AgentID:        agent.ID,
-			CapabilityType: domain.CapabilityDataExport,
-			GrantedAt:      time.Now(),
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		},
-	}
-
-	// Mock: Many CRITICAL violations
-	now := time.Now()
-	violations := make([]*domain.CapabilityViolation, 20)
-	for i := 0; i < 20; i++ {
-		violations[i] = &domain.CapabilityViolation{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilitySystemAdmin,
-			Severity:            domain.ViolationSeverityCritical,
-			TrustScoreImpact:    -15,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -i-1),
-		}
-	}
-
-	mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return(capabilities, nil)
-	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(violations, 20, nil)
+	score := calculator.calculateSecurityAlerts(agent)
 
-	score := calculator.calculateCapabilityRisk(agent)
-
-	assert.Equal(t, 0.0, score, "Score should never go below 0")
+	assert.Equal(t, 0.50, score, "The most severe recent violation determines the score")
 	mockRepo.AssertExpectations(t)
 }
 
-// Test 8: Old violations should not impact score
-func TestCalculateCapabilityRisk_OldViolationsIgnored(t *testing.T) {
+func TestCalculateSecurityAlerts_OldViolationsIgnored(t *testing.T) {
 	mockRepo := new(MockCapabilityRepository)
-	calculator := &TrustCalculator{
-		capabilityRepo: mockRepo,
-	}
-
-	agent := &domain.Agent{
-		ID:        uuid.New(),
-		Name:      "old-violation-agent",
-		AgentType: domain.AgentTypeAI,
-		Status:    domain.AgentStatusVerified,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Mock: No capabilities
-	mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{}, nil)
+	calculator := &TrustCalculator{capabilityRepo: mockRepo}
 
-	// Mock: Violations older than 30 days
-	now := time.Now()
+	agent := &domain.Agent{ID: uuid.New()}
 	violations := []*domain.CapabilityViolation{
-		{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilitySystemAdmin,
-			Severity:            domain.ViolationSeverityCritical,
-			TrustScoreImpact:    -15,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -35), // 35 days ago (outside 30-day window)
-		},
-		{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilitySystemAdmin,
-			Severity:            domain.ViolationSeverityCritical,
-			TrustScoreImpact:    -15,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -60), // 60 days ago
-		},
+		newTestViolation(agent.ID, domain.ViolationSeverityCritical, 35*24*time.Hour), // Outside 30-day window
+		newTestViolation(agent.ID, domain.ViolationSeverityCritical, 60*24*time.Hour),
 	}
-
 	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(violations, 2, nil)
 
-	score := calculator.calculateCapabilityRisk(agent)
-
-	assert.Equal(t, 0.7, score, "Violations older than 30 days should not impact score")
-	mockRepo.AssertExpectations(t)
-}
-
-// Test 9: Mixed severity violations
-func TestCalculateCapabilityRisk_MixedSeverityViolations(t *testing.T) {
-	mockRepo := new(MockCapabilityRepository)
-	calculator := &TrustCalculator{
-		capabilityRepo: mockRepo,
-	}
-
-	agent := &domain.Agent{
-		ID:        uuid.New(),
-		Name:      "mixed-violations-agent",
-		AgentType: domain.AgentTypeAI,
-		Status:    domain.AgentStatusVerified,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Mock: No capabilities
-	mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{}, nil)
-
-	// Mock: Various severity violations
-	now := time.Now()
-	violations := []*domain.CapabilityViolation{
-		{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilitySystemAdmin,
-			Severity:            domain.ViolationSeverityCritical,
-			TrustScoreImpact:    -15,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -1), // 1 day ago
-		},
-		{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilityFileDelete,
-			Severity:            domain.ViolationSeverityHigh,
-			TrustScoreImpact:    -10,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -2), // 2 days ago
-		},
-		{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilityFileWrite,
-			Severity:            domain.ViolationSeverityMedium,
-			TrustScoreImpact:    -5,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -3), // 3 days ago
-		},
-		{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilityFileRead,
-			Severity:            domain.ViolationSeverityLow,
-			TrustScoreImpact:    -2,
-			IsBlocked:           true,
-			CreatedAt:           now.AddDate(0, 0, -4), // 4 days ago
-		},
-	}
-
-	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(violations, 4, nil)
-
-	score := calculator.calculateCapabilityRisk(agent)
+	score := calculator.calculateSecurityAlerts(agent)
 
-	// Expected: 0.7 - 0.15 (critical) - 0.10 (high) - 0.05 (medium) - 0.02 (low) = 0.38
-	expectedScore := 0.7 - 0.15 - 0.10 - 0.05 - 0.02
-	assert.InDelta(t, expectedScore, score, 0.001, "Mixed severity violations should have varying impacts")
+	assert.Equal(t, 1.0, score, "Violations older than 30 days should not impact score")
 	mockRepo.AssertExpectations(t)
 }
 
-// Test 10: Error handling - repository error returns baseline
-func TestCalculateCapabilityRisk_RepositoryError(t *testing.T) {
+func TestCalculateSecurityAlerts_RepositoryError(t *testing.T) {
 	mockRepo := new(MockCapabilityRepository)
-	calculator := &TrustCalculator{
-		capabilityRepo: mockRepo,
-	}
-
-	agent := &domain.Agent{
-		ID:        uuid.New(),
-		Name:      "error-agent",
-		AgentType: domain.AgentTypeAI,
-		Status:    domain.AgentStatusVerified,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
+	calculator := &TrustCalculator{capabilityRepo: mockRepo}
 
-	// Mock: Repository error for capabilities
-	mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return(nil, assert.AnError)
-	// Mock: No violations when capability fetch fails
-	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
+	agent := &domain.Agent{ID: uuid.New()}
+	mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(nil, 0, assert.AnError)
 
-	score := calculator.calculateCapabilityRisk(agent)
+	score := calculator.calculateSecurityAlerts(agent)
 
-	assert.Equal(t, 0.7, score, "Repository error should return neutral baseline score")
+	assert.Equal(t, 1.0, score, "Repository error should return the baseline score")
 	mockRepo.AssertExpectations(t)
 }
 
-// Test 11: Volume penalty thresholds
-func TestCalculateCapabilityRisk_ViolationVolumeThresholds(t *testing.T) {
-	now := time.Now()
-
-	// Test 6 violations (should trigger -0.10 penalty)
-	t.Run("6 violations", func(t *testing.T) {
-		mockRepo := new(MockCapabilityRepository)
-		calculator := &TrustCalculator{
-			capabilityRepo: mockRepo,
-		}
-
-		agent := &domain.Agent{
-			ID:        uuid.New(),
-			Name:      "6-violations-agent",
-			AgentType: domain.AgentTypeAI,
-			Status:    domain.AgentStatusVerified,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-
-		mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{}, nil)
-
-		violations := make([]*domain.CapabilityViolation, 6)
-		for i := 0; i < 6; i++ {
-			violations[i] = &domain.CapabilityViolation{
-				ID:                  uuid.New(),
-				AgentID:             agent.ID,
-				AttemptedCapability: domain.CapabilityFileRead,
-				Severity:            domain.ViolationSeverityLow,
-				TrustScoreImpact:    -2,
-				IsBlocked:           true,
-				CreatedAt:           now.AddDate(0, 0, -i-1),
-			}
-		}
-
-		mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(violations, 6, nil)
-
-		score := calculator.calculateCapabilityRisk(agent)
-
-		// Expected: 0.7 - (6 * 0.02) - 0.10 (volume penalty > 5) = 0.48
-		expectedScore := 0.7 - (6 * 0.02) - 0.10
-		assert.InDelta(t, expectedScore, score, 0.001, "6 violations should trigger -0.10 volume penalty")
-		mockRepo.AssertExpectations(t)
-	})
-
-	// Test 11 violations (should trigger -0.20 penalty)
-	t.Run("11 violations", func(t *testing.T) {
-		mockRepo := new(MockCapabilityRepository)
-		calculator := &TrustCalculator{
-			capabilityRepo: mockRepo,
-		}
-
-		agent := &domain.Agent{
-			ID:        uuid.New(),
-			Name:      "11-violations-agent",
-			AgentType: domain.AgentTypeAI,
-			Status:    domain.AgentStatusVerified,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-
-		mockRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{}, nil)
-
-		violations := make([]*domain.CapabilityViolation, 11)
-		for i := 0; i < 11; i++ {
-			violations[i] = &domain.CapabilityViolation{
-				ID:                  uuid.New(),
-				AgentID:             agent.ID,
-				AttemptedCapability: domain.CapabilityFileRead,
-				Severity:            domain.ViolationSeverityLow,
-				TrustScoreImpact:    -2,
-				IsBlocked:           true,
-				CreatedAt:           now.AddDate(0, 0, -i-1),
-			}
-		}
-
-		mockRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(violations, 11, nil)
-
-		score := calculator.calculateCapabilityRisk(agent)
-
-		// Expected: 0.7 - (11 * 0.02) - 0.20 (volume penalty > 10) = 0.28
-		expectedScore := 0.7 - (11 * 0.02) - 0.20
-		assert.InDelta(t, expectedScore, score, 0.001, "11 violations should trigger -0.20 volume penalty")
-		mockRepo.AssertExpectations(t)
-	})
-}
-
 // ============================================================================
 // TEST: Score Boundaries
 // ============================================================================
 
 func TestTrustCalculator_ScoreNeverExceedsOne(t *testing.T) {
-	mockTrustRepo := new(AgentServiceMockTrustScoreRepository)
-	mockAPIKeyRepo := new(MockAPIKeyRepository)
-	mockAuditRepo := new(AgentServiceMockAuditLogRepository)
 	mockCapabilityRepo := new(MockCapabilityRepository)
+	calculator := newTestTrustCalculator(mockCapabilityRepo)
 
-	calculator := NewTrustCalculator(mockTrustRepo, mockAPIKeyRepo, mockAuditRepo, mockCapabilityRepo)
-
-	validCert := generateValidCertificate()
+	publicKey := "ed25519-public-key"
 	agent := &domain.Agent{
-		ID:               uuid.New(),
-		Status:           domain.AgentStatusVerified,
-		PublicKey:        &validCert,
-		CertificateURL:   "https://example.com/cert.pem",
-		RepositoryURL:    "https://github.com/test/repo",
-		DocumentationURL: "https://docs.example.com",
-		Description:      "This is a very comprehensive description with lots of details about the agent.",
-		UpdatedAt:        time.Now(),
-		CreatedAt:        time.Now().Add(-365 * 24 * time.Hour),
-		Version:          "1.0.0",
+		ID:          uuid.New(),
+		Status:      domain.AgentStatusVerified,
+		PublicKey:   &publicKey,
+		Description: "This is a very comprehensive description with lots of details about the agent.",
+		UpdatedAt:   time.Now(),
+		CreatedAt:   time.Now().Add(-365 * 24 * time.Hour),
+		Version:     "1.0.0",
 	}
 
-	mockCapabilityRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{}, nil)
 	mockCapabilityRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
 
 	score, err := calculator.Calculate(agent)
@@ -1325,12 +783,8 @@ func TestTrustCalculator_ScoreNeverExceedsOne(t *testing.T) {
 }
 
 func TestTrustCalculator_ScoreNeverBelowZero(t *testing.T) {
-	mockTrustRepo := new(AgentServiceMockTrustScoreRepository)
-	mockAPIKeyRepo := new(MockAPIKeyRepository)
-	mockAuditRepo := new(AgentServiceMockAuditLogRepository)
 	mockCapabilityRepo := new(MockCapabilityRepository)
-
-	calculator := NewTrustCalculator(mockTrustRepo, mockAPIKeyRepo, mockAuditRepo, mockCapabilityRepo)
+	calculator := newTestTrustCalculator(mockCapabilityRepo)
 
 	agent := &domain.Agent{
 		ID:        uuid.New(),
@@ -1339,25 +793,11 @@ func TestTrustCalculator_ScoreNeverBelowZero(t *testing.T) {
 		CreatedAt: time.Now(),
 	}
 
-	// Worst case: multiple high-risk capabilities + many critical violations
-	capabilities := []*domain.AgentCapability{
-		{ID: uuid.New(), AgentID: agent.ID, CapabilityType: domain.CapabilitySystemAdmin},
-		{ID: uuid.New(), AgentID: agent.ID, CapabilityType: domain.CapabilityUserImpersonate},
-		{ID: uuid.New(), AgentID: agent.ID, CapabilityType: domain.CapabilityFileDelete},
-	}
-
+	// Worst case: many critical violations
 	violations := make([]*domain.CapabilityViolation, 15)
 	for i := 0; i < 15; i++ {
-		violations[i] = &domain.CapabilityViolation{
-			ID:                  uuid.New(),
-			AgentID:             agent.ID,
-			AttemptedCapability: domain.CapabilitySystemAdmin,
-			Severity:            domain.ViolationSeverityCritical,
-			CreatedAt:           time.Now().Add(-time.Duration(i) * time.Hour),
-		}
+		violations[i] = newTestViolation(agent.ID, domain.ViolationSeverityCritical, time.Duration(i)*time.Hour)
 	}
-
-	mockCapabilityRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return(capabilities, nil)
 	mockCapabilityRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(violations, 15, nil)
 
 	score, err := calculator.Calculate(agent)
@@ -1375,27 +815,25 @@ func TestTrustCalculator_ScoreNeverBelowZero(t *testing.T) {
 func TestTrustCalculator_Confidence_AllDataPoints(t *testing.T) {
 	calculator := &TrustCalculator{}
 
-	validCert := generateValidCertificate()
+	publicKey := "ed25519-public-key"
 	agent := &domain.Agent{
-		Status:           domain.AgentStatusVerified,
-		PublicKey:        &validCert,
-		CertificateURL:   "https://example.com/cert.pem",
-		RepositoryURL:    "https://github.com/test/repo",
-		DocumentationURL: "https://docs.example.com",
-		Description:      "Complete description",
-		Version:          "1.0.0",
+		Status:    domain.AgentStatusVerified,
+		PublicKey: &publicKey,
+		CreatedAt: time.Now().Add(-60 * 24 * time.Hour),
 	}
 
 	factors := &domain.TrustScoreFactors{}
 	confidence := calculator.calculateConfidence(agent, factors)
 
-	assert.Equal(t, 1.0, confidence, "All data points should yield confidence of 1.0")
+	// Status, public key and history are the data points available until operational metrics are
+	expectedConfidence := 3.0 / 8.0
+	assert.InDelta(t, expectedConfidence, confidence, 0.0001)
 }
 
 func TestTrustCalculator_Confidence_NoDataPoints(t *testing.T) {
 	calculator := &TrustCalculator{}
 
-	agent := &domain.Agent{}
+	agent := &domain.Agent{CreatedAt: time.Now()}
 
 	factors := &domain.TrustScoreFactors{}
 	confidence := calculator.calculateConfidence(agent, factors)
@@ -1407,61 +845,13 @@ func TestTrustCalculator_Confidence_PartialData(t *testing.T) {
 	calculator := &TrustCalculator{}
 
 	agent := &domain.Agent{
-		Status:      domain.AgentStatusVerified,
-		Description: "Some description",
-		Version:     "1.0.0",
+		Status:    domain.AgentStatusVerified,
+		CreatedAt: time.Now(),
 	}
 
 	factors := &domain.TrustScoreFactors{}
 	confidence := calculator.calculateConfidence(agent, factors)
 
-	expectedConfidence := 3.0 / 7.0 // 3 out of 7 data points
-	assert.InDelta(t, expectedConfidence, confidence, 0.01, "Partial data should yield proportional confidence")
-}
-
-// ============================================================================
-// TEST: Edge Cases
-// ============================================================================
-
-func TestTrustCalculator_CapabilityRisk_ErrorFetchingCapabilities(t *testing.T) {
-	mockTrustRepo := new(AgentServiceMockTrustScoreRepository)
-	mockAPIKeyRepo := new(MockAPIKeyRepository)
-	mockAuditRepo := new(AgentServiceMockAuditLogRepository)
-	mockCapabilityRepo := new(MockCapabilityRepository)
-
-	calculator := NewTrustCalculator(mockTrustRepo, mockAPIKeyRepo, mockAuditRepo, mockCapabilityRepo)
-
-	agent := &domain.Agent{ID: uuid.New()}
-
-	// Simulate error fetching capabilities
-	mockCapabilityRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return(nil, assert.AnError)
-	mockCapabilityRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return([]*domain.CapabilityViolation{}, 0, nil)
-
-	score := calculator.calculateCapabilityRisk(agent)
-
-	// Should return baseline score when capabilities can't be fetched
-	assert.Equal(t, 0.7, score, "Error fetching capabilities should return baseline score")
-
-	mockCapabilityRepo.AssertExpectations(t)
-}
-
-func TestTrustCalculator_CapabilityRisk_ErrorFetchingViolations(t *testing.T) {
-	mockTrustRepo := new(AgentServiceMockTrustScoreRepository)
-	mockAPIKeyRepo := new(MockAPIKeyRepository)
-	mockAuditRepo := new(AgentServiceMockAuditLogRepository)
-	mockCapabilityRepo := new(MockCapabilityRepository)
-
-	calculator := NewTrustCalculator(mockTrustRepo, mockAPIKeyRepo, mockAuditRepo, mockCapabilityRepo)
-
-	agent := &domain.Agent{ID: uuid.New()}
-
-	mockCapabilityRepo.On("GetActiveCapabilitiesByAgentID", agent.ID).Return([]*domain.AgentCapability{}, nil)
-	mockCapabilityRepo.On("GetViolationsByAgentID", agent.ID, 100, 0).Return(nil, 0, assert.AnError)
-
-	score := calculator.calculateCapabilityRisk(agent)
-
-	// Should return baseline score when violations can't be fetched
-	assert.Equal(t, 0.7, score, "Error fetching violations should return baseline score")
-
-	mockCapabilityRepo.AssertExpectations(t)
+	expectedConfidence := 1.0 / 8.0 // 1 out of 8 data points
+	assert.InDelta(t, expectedConfidence, confidence, 0.0001, "Partial data should yield proportional confidence")
 }
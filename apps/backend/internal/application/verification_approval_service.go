@@ -0,0 +1,648 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/netguard"
+)
+
+// DefaultVerificationApprovalTimeout is how long a high-risk action waits for a human decision
+const DefaultVerificationApprovalTimeout = 15 * time.Minute
+
+// MaxVerificationApprovalWait bounds a single long-poll; it stays under the server write timeout
+const MaxVerificationApprovalWait = 25 * time.Second
+
+// verificationApprovalPollInterval re-reads a pending approval while long-polling, so decisions
+// made on another server instance are picked up
+const verificationApprovalPollInterval = 2 * time.Second
+
+// verificationEventRecorder reads and completes verification events (VerificationEventService)
+type verificationEventRecorder interface {
+	GetVerificationEvent(ctx context.Context, id uuid.UUID) (*domain.VerificationEvent, error)
+	UpdateVerificationResult(ctx context.Context, id uuid.UUID, result domain.VerificationResult, reason *string, metadata map[string]interface{}) error
+}
+
+// webhookDispatcher delivers organization events to subscribed webhooks (WebhookService)
+type webhookDispatcher interface {
+	DispatchEvent(ctx context.Context, orgID uuid.UUID, event domain.WebhookEvent, data interface{}) error
+}
+
+// VerificationApprovalRequest is a verification that must wait for a human decision
+type VerificationApprovalRequest struct {
+	VerificationID uuid.UUID
+	ActionType     string
+	Resource       string
+	Context        map[string]interface{}
+	TrustScore     float64
+	Reason         string
+	CallbackURL    string
+}
+
+// VerificationApprovalService parks high-risk agent actions until an approver decides them.
+// Approvers are notified by email (with signed one-click links) and webhook; the SDK long-polls
+// or receives a callback. Requests nobody decides in time expire as timeout.
+type VerificationApprovalService struct {
+	approvalRepo domain.VerificationApprovalRepository
+	userRepo     domain.UserRepository
+	events       verificationEventRecorder
+	webhooks     webhookDispatcher
+	emailService domain.EmailService
+	signingKey   []byte
+	timeout      time.Duration
+	httpClient   *http.Client
+	now          func() time.Time
+
+	// Callbacks may only reach the internal addresses outbound allows; requireHTTPS rejects
+	// plain http callback URLs
+	outbound     *netguard.Policy
+	requireHTTPS bool
+
+	mu      sync.Mutex
+	waiters map[uuid.UUID][]chan struct{}
+}
+
+// NewVerificationApprovalService creates a new verification approval service. The link signing
+// key is derived from signingSecret. webhooks and emailService may be nil; timeout defaults to
+// DefaultVerificationApprovalTimeout.
+func NewVerificationApprovalService(
+	approvalRepo domain.VerificationApprovalRepository,
+	userRepo domain.UserRepository,
+	events verificationEventRecorder,
+	webhooks webhookDispatcher,
+	emailService domain.EmailService,
+	signingSecret string,
+	timeout time.Duration,
+) *VerificationApprovalService {
+	if timeout <= 0 {
+		timeout = DefaultVerificationApprovalTimeout
+	}
+	key := sha256.Sum256([]byte("verification-approval-link:" + signingSecret))
+
+	return &VerificationApprovalService{
+		approvalRepo: approvalRepo,
+		userRepo:     userRepo,
+		events:       events,
+		webhooks:     webhooks,
+		emailService: emailService,
+		signingKey:   key[:],
+		timeout:      timeout,
+		httpClient:   newCallbackClient(nil),
+		now:          func() time.Time { return time.Now().UTC() },
+		waiters:      map[uuid.UUID][]chan struct{}{},
+	}
+}
+
+// RestrictCallbacks sets which internal networks SDK callback URLs may point at and whether
+// they must use https. Without it, internal addresses are refused and http is allowed.
+func (s *VerificationApprovalService) RestrictCallbacks(outbound *netguard.Policy, requireHTTPS bool) {
+	s.outbound = outbound
+	s.requireHTTPS = requireHTTPS
+	s.httpClient = newCallbackClient(outbound)
+}
+
+// newCallbackClient creates the client callbacks are posted with; it checks every address it
+// connects to, so a callback host cannot be re-pointed at an internal address after validation
+func newCallbackClient(outbound *netguard.Policy) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = outbound.Dialer(10 * time.Second).DialContext
+	return &http.Client{Timeout: 10 * time.Second, Transport: transport}
+}
+
+// ValidateCallbackURL checks a callback URL supplied by an SDK
+func (s *VerificationApprovalService) ValidateCallbackURL(ctx context.Context, callbackURL string) error {
+	if callbackURL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(callbackURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return fmt.Errorf("invalid callback_url: must be an absolute http(s) URL")
+	}
+	if s.requireHTTPS && parsed.Scheme != "https" {
+		return fmt.Errorf("invalid callback_url: must be an https URL")
+	}
+	if err := s.outbound.CheckHost(ctx, parsed.Hostname()); err != nil {
+		return fmt.Errorf("invalid callback_url: %w", err)
+	}
+	return nil
+}
+
+// Enqueue parks a verification for human approval and notifies the organization's approvers
+func (s *VerificationApprovalService) Enqueue(
+	ctx context.Context,
+	agent *domain.Agent,
+	req VerificationApprovalRequest,
+) (*domain.VerificationApproval, error) {
+	if err := s.ValidateCallbackURL(ctx, req.CallbackURL); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	approval := &domain.VerificationApproval{
+		ID:             req.VerificationID,
+		OrganizationID: agent.OrganizationID,
+		AgentID:        agent.ID,
+		AgentName:      agentDisplayName(agent),
+		ActionType:     req.ActionType,
+		Resource:       req.Resource,
+		Context:        req.Context,
+		TrustScore:     req.TrustScore,
+		Reason:         req.Reason,
+		Status:         domain.VerificationApprovalPending,
+		CallbackURL:    req.CallbackURL,
+		ExpiresAt:      now.Add(s.timeout),
+		CreatedAt:      now,
+	}
+	if err := s.approvalRepo.Create(approval); err != nil {
+		return nil, err
+	}
+
+	s.notifyApprovers(ctx, approval)
+	s.dispatchWebhook(ctx, domain.WebhookEventVerificationApprovalRequested, approval)
+
+	fmt.Printf("⏸️  Verification %s parked for approval: agent %s wants to %s\n", approval.ID, approval.AgentName, approval.ActionType)
+	return approval, nil
+}
+
+// AttachVerificationEvent links the dashboard verification event, which is completed when the
+// approval is decided
+func (s *VerificationApprovalService) AttachVerificationEvent(ctx context.Context, approval *domain.VerificationApproval, eventID uuid.UUID) error {
+	if err := s.approvalRepo.AttachVerificationEvent(approval.ID, eventID); err != nil {
+		return err
+	}
+	approval.VerificationEventID = &eventID
+	return nil
+}
+
+// GetApproval returns an approval of the organization. A pending approval past its deadline is
+// timed out on read, so callers never see a stale pending state between expiry job runs.
+func (s *VerificationApprovalService) GetApproval(ctx context.Context, orgID, id uuid.UUID) (*domain.VerificationApproval, error) {
+	approval, err := s.approvalRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if approval.OrganizationID != orgID {
+		return nil, fmt.Errorf("verification approval not found")
+	}
+
+	if approval.Status == domain.VerificationApprovalPending && !s.now().Before(approval.ExpiresAt) {
+		if err := s.expire(ctx, approval); err != nil {
+			return s.approvalRepo.GetByID(id)
+		}
+	}
+
+	return approval, nil
+}
+
+// ListApprovals returns the organization's approvals, newest first
+func (s *VerificationApprovalService) ListApprovals(
+	ctx context.Context,
+	orgID uuid.UUID,
+	status *domain.VerificationApprovalStatus,
+	limit, offset int,
+) ([]*domain.VerificationApproval, int, error) {
+	return s.approvalRepo.List(orgID, status, limit, offset)
+}
+
+// Decide records an approver's decision made from the dashboard
+func (s *VerificationApprovalService) Decide(
+	ctx context.Context,
+	orgID, approvalID, userID uuid.UUID,
+	decision domain.VerificationApprovalStatus,
+	reason string,
+) (*domain.VerificationApproval, error) {
+	approval, err := s.GetApproval(ctx, orgID, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	return s.decide(ctx, approval, &userID, domain.VerificationApprovalViaDashboard, decision, reason)
+}
+
+// DecisionLink is what a signed one-click link resolves to
+type DecisionLink struct {
+	Approval *domain.VerificationApproval
+	Approver *domain.User
+	Decision domain.VerificationApprovalStatus
+}
+
+// ResolveDecisionLink verifies a signed link token and returns the approval it decides. It does
+// not decide anything, so it is safe for link previews and the confirmation page.
+func (s *VerificationApprovalService) ResolveDecisionLink(ctx context.Context, token string) (*DecisionLink, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("invalid approval link")
+	}
+
+	expected := s.signLink(parts[0], parts[1], parts[2], parts[3])
+	if !hmac.Equal([]byte(parts[4]), []byte(expected)) {
+		return nil, fmt.Errorf("invalid approval link")
+	}
+
+	approvalID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval link")
+	}
+	userID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval link")
+	}
+	decision := domain.VerificationApprovalStatus(parts[2])
+	expiresAt, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval link")
+	}
+	if !s.now().Before(time.Unix(expiresAt, 0)) {
+		return nil, fmt.Errorf("approval link has expired")
+	}
+
+	approver, err := s.userRepo.GetByID(userID)
+	if err != nil || !isApprover(approver) {
+		return nil, fmt.Errorf("approval link is no longer valid")
+	}
+
+	approval, err := s.GetApproval(ctx, approver.OrganizationID, approvalID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DecisionLink{Approval: approval, Approver: approver, Decision: decision}, nil
+}
+
+// DecideWithLink records the decision carried by a signed one-click link
+func (s *VerificationApprovalService) DecideWithLink(ctx context.Context, token, reason string) (*DecisionLink, error) {
+	link, err := s.ResolveDecisionLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	approval, err := s.decide(ctx, link.Approval, &link.Approver.ID, domain.VerificationApprovalViaLink, link.Decision, reason)
+	if err != nil {
+		return nil, err
+	}
+	link.Approval = approval
+	return link, nil
+}
+
+// Wait blocks until the approval is decided or times out, up to MaxVerificationApprovalWait
+func (s *VerificationApprovalService) Wait(ctx context.Context, orgID, id uuid.UUID, wait time.Duration) (*domain.VerificationApproval, error) {
+	if wait > MaxVerificationApprovalWait {
+		wait = MaxVerificationApprovalWait
+	}
+
+	// Register before reading so a decision between the read and the wait is not missed
+	decided := s.subscribe(id)
+	defer s.unsubscribe(id, decided)
+
+	approval, err := s.GetApproval(ctx, orgID, id)
+	if err != nil || approval.Status != domain.VerificationApprovalPending || wait <= 0 {
+		return approval, err
+	}
+
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	poll := time.NewTicker(verificationApprovalPollInterval)
+	defer poll.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return approval, nil
+		case <-deadline.C:
+			return s.GetApproval(ctx, orgID, id)
+		case <-decided:
+			return s.GetApproval(ctx, orgID, id)
+		case <-poll.C:
+			approval, err = s.GetApproval(ctx, orgID, id)
+			if err != nil || approval.Status != domain.VerificationApprovalPending {
+				return approval, err
+			}
+		}
+	}
+}
+
+// ExpirePending times out every pending approval past its deadline
+func (s *VerificationApprovalService) ExpirePending(ctx context.Context) error {
+	approvals, err := s.approvalRepo.ListExpired(s.now())
+	if err != nil {
+		return err
+	}
+
+	expired := 0
+	for _, approval := range approvals {
+		if err := s.expire(ctx, approval); err != nil {
+			continue
+		}
+		expired++
+	}
+
+	if expired > 0 {
+		fmt.Printf("⏱️  Timed out %d verification approval(s)\n", expired)
+	}
+	return nil
+}
+
+func (s *VerificationApprovalService) expire(ctx context.Context, approval *domain.VerificationApproval) error {
+	decidedAt := s.now()
+	approval.Status = domain.VerificationApprovalTimeout
+	approval.DecisionReason = "No approver decided before the request timed out"
+	approval.DecidedAt = &decidedAt
+	if err := s.approvalRepo.Decide(approval); err != nil {
+		return err
+	}
+
+	s.complete(ctx, approval)
+	return nil
+}
+
+func (s *VerificationApprovalService) decide(
+	ctx context.Context,
+	approval *domain.VerificationApproval,
+	decidedBy *uuid.UUID,
+	via domain.VerificationApprovalChannel,
+	decision domain.VerificationApprovalStatus,
+	reason string,
+) (*domain.VerificationApproval, error) {
+	reason = strings.TrimSpace(reason)
+	if decision != domain.VerificationApprovalApproved && decision != domain.VerificationApprovalDenied {
+		return nil, fmt.Errorf("invalid decision: must be approved or denied")
+	}
+	if decision == domain.VerificationApprovalDenied && reason == "" {
+		return nil, fmt.Errorf("invalid decision: a reason is required to deny")
+	}
+	if approval.Status != domain.VerificationApprovalPending {
+		return nil, fmt.Errorf("verification approval already decided")
+	}
+
+	decidedAt := s.now()
+	if !decidedAt.Before(approval.ExpiresAt) {
+		return nil, fmt.Errorf("verification approval has expired")
+	}
+	approval.Status = decision
+	approval.DecidedBy = decidedBy
+	approval.DecidedVia = via
+	approval.DecisionReason = reason
+	approval.DecidedAt = &decidedAt
+	if err := s.approvalRepo.Decide(approval); err != nil {
+		return nil, err
+	}
+
+	if decidedBy != nil {
+		if approver, err := s.userRepo.GetByID(*decidedBy); err == nil {
+			approval.DecidedByEmail = approver.Email
+		}
+	}
+
+	s.complete(ctx, approval)
+	return approval, nil
+}
+
+// complete propagates a final approval state: the verification event, long-polling SDKs,
+// the SDK callback and webhooks
+func (s *VerificationApprovalService) complete(ctx context.Context, approval *domain.VerificationApproval) {
+	if approval.VerificationEventID != nil && s.events != nil {
+		s.completeVerificationEvent(ctx, approval)
+	}
+
+	s.notifyWaiters(approval.ID)
+
+	if approval.CallbackURL != "" {
+		go s.sendCallback(approval)
+	}
+
+	s.dispatchWebhook(ctx, domain.WebhookEventVerificationApprovalDecided, approval)
+	fmt.Printf("✅ Verification %s %s\n", approval.ID, approval.Status)
+}
+
+func (s *VerificationApprovalService) completeVerificationEvent(ctx context.Context, approval *domain.VerificationApproval) {
+	result := domain.VerificationResultVerified
+	switch approval.Status {
+	case domain.VerificationApprovalDenied:
+		result = domain.VerificationResultDenied
+	case domain.VerificationApprovalTimeout:
+		result = domain.VerificationResultExpired
+	}
+
+	// UpdateVerificationResult replaces the metadata, so extend what the event already has
+	metadata := map[string]interface{}{}
+	if event, err := s.events.GetVerificationEvent(ctx, *approval.VerificationEventID); err == nil {
+		for key, value := range event.Metadata {
+			metadata[key] = value
+		}
+	}
+	metadata["approval_status"] = approval.Status
+	metadata["approval_decided_via"] = approval.DecidedVia
+	if approval.DecidedBy != nil {
+		metadata["approval_decided_by"] = approval.DecidedBy.String()
+	}
+
+	var reason *string
+	if approval.Status != domain.VerificationApprovalApproved && approval.DecisionReason != "" {
+		reason = &approval.DecisionReason
+	}
+
+	if err := s.events.UpdateVerificationResult(ctx, *approval.VerificationEventID, result, reason, metadata); err != nil {
+		fmt.Printf("⚠️  Failed to complete verification event for approval %s: %v\n", approval.ID, err)
+	}
+}
+
+// VerificationApprovalCallback is posted to an SDK's callback URL once its request is final.
+// It carries no secrets; receivers should confirm the outcome with GET /sdk-api/verifications/:id.
+type VerificationApprovalCallback struct {
+	ID             string     `json:"id"`
+	Status         string     `json:"status"`
+	ApprovedBy     string     `json:"approved_by,omitempty"`
+	DecisionReason string     `json:"decision_reason,omitempty"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty"`
+}
+
+func (s *VerificationApprovalService) sendCallback(approval *domain.VerificationApproval) {
+	callback := VerificationApprovalCallback{
+		ID:             approval.ID.String(),
+		Status:         string(approval.Status),
+		DecisionReason: approval.DecisionReason,
+		DecidedAt:      approval.DecidedAt,
+	}
+	if approval.Status == domain.VerificationApprovalApproved {
+		callback.ApprovedBy = approval.DecidedByEmail
+	}
+
+	body, err := json.Marshal(callback)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, approval.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		fmt.Printf("⚠️  Invalid callback URL for verification %s: %v\n", approval.ID, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-AIM-Verification-ID", approval.ID.String())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		fmt.Printf("⚠️  Callback for verification %s failed: %v\n", approval.ID, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		fmt.Printf("⚠️  Callback for verification %s returned status %d\n", approval.ID, resp.StatusCode)
+	}
+}
+
+func (s *VerificationApprovalService) dispatchWebhook(ctx context.Context, event domain.WebhookEvent, approval *domain.VerificationApproval) {
+	if s.webhooks == nil {
+		return
+	}
+	if err := s.webhooks.DispatchEvent(ctx, approval.OrganizationID, event, approval); err != nil {
+		fmt.Printf("⚠️  Failed to dispatch %s webhooks: %v\n", event, err)
+	}
+}
+
+// notifyApprovers emails every active admin and manager of the organization, each with their
+// own signed approve and deny links
+func (s *VerificationApprovalService) notifyApprovers(ctx context.Context, approval *domain.VerificationApproval) {
+	if s.emailService == nil {
+		return
+	}
+
+	users, err := s.userRepo.GetByOrganization(approval.OrganizationID)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to load approvers for verification %s: %v\n", approval.ID, err)
+		return
+	}
+
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:3000"
+	}
+
+	for _, user := range users {
+		if !isApprover(user) {
+			continue
+		}
+
+		name := user.Name
+		if name == "" {
+			name = user.Email
+		}
+		templateData := domain.EmailTemplateData{
+			UserName:     name,
+			UserEmail:    user.Email,
+			DashboardURL: frontendURL + "/dashboard/verifications/approvals",
+			AgentID:      approval.AgentID.String(),
+			AgentName:    approval.AgentName,
+			TrustScore:   approval.TrustScore,
+			Timestamp:    approval.CreatedAt,
+			CustomData: map[string]interface{}{
+				"ActionType": approval.ActionType,
+				"Resource":   approval.Resource,
+				"Reason":     approval.Reason,
+				"ExpiresAt":  approval.ExpiresAt.Format("January 2, 2006 15:04 MST"),
+				"ApproveURL": s.DecisionLinkURL(approval, user.ID, domain.VerificationApprovalApproved),
+				"DenyURL":    s.DecisionLinkURL(approval, user.ID, domain.VerificationApprovalDenied),
+			},
+		}
+
+		if err := s.emailService.SendTemplatedEmail(domain.TemplateVerificationApprovalRequest, user.Email, templateData); err != nil {
+			fmt.Printf("⚠️  Failed to send approval request to %s: %v\n", user.Email, err)
+		}
+	}
+}
+
+// DecisionLinkURL returns an approver's signed one-click link for a decision. The link opens a
+// confirmation page; it is valid until the approval times out.
+func (s *VerificationApprovalService) DecisionLinkURL(
+	approval *domain.VerificationApproval,
+	approverID uuid.UUID,
+	decision domain.VerificationApprovalStatus,
+) string {
+	baseURL := os.Getenv("AIM_PUBLIC_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	return strings.TrimRight(baseURL, "/") + "/api/v1/public/verification-approvals/" + s.decisionToken(approval, approverID, decision)
+}
+
+// decisionToken is approvalID.approverID.decision.expiry.signature, safe to use as a path segment
+func (s *VerificationApprovalService) decisionToken(
+	approval *domain.VerificationApproval,
+	approverID uuid.UUID,
+	decision domain.VerificationApprovalStatus,
+) string {
+	fields := []string{
+		approval.ID.String(),
+		approverID.String(),
+		string(decision),
+		strconv.FormatInt(approval.ExpiresAt.Unix(), 10),
+	}
+	return strings.Join(append(fields, s.signLink(fields...)), ".")
+}
+
+func (s *VerificationApprovalService) signLink(fields ...string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(strings.Join(fields, ".")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *VerificationApprovalService) subscribe(id uuid.UUID) chan struct{} {
+	ch := make(chan struct{})
+	s.mu.Lock()
+	s.waiters[id] = append(s.waiters[id], ch)
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *VerificationApprovalService) unsubscribe(id uuid.UUID, ch chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	waiters := s.waiters[id]
+	for i, waiter := range waiters {
+		if waiter == ch {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(s.waiters, id)
+	} else {
+		s.waiters[id] = waiters
+	}
+}
+
+func (s *VerificationApprovalService) notifyWaiters(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ch := range s.waiters[id] {
+		close(ch)
+	}
+	delete(s.waiters, id)
+}
+
+// isApprover reports whether a user may decide verification approvals
+func isApprover(user *domain.User) bool {
+	return user.Status == domain.UserStatusActive &&
+		(user.Role == domain.RoleAdmin || user.Role == domain.RoleManager)
+}
+
+func agentDisplayName(agent *domain.Agent) string {
+	if agent.DisplayName != "" {
+		return agent.DisplayName
+	}
+	return agent.Name
+}
@@ -0,0 +1,441 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/netguard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVerificationApprovalRepository struct {
+	mu        sync.Mutex
+	approvals map[uuid.UUID]*domain.VerificationApproval
+}
+
+func (r *fakeVerificationApprovalRepository) Create(approval *domain.VerificationApproval) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *approval
+	r.approvals[approval.ID] = &stored
+	return nil
+}
+
+func (r *fakeVerificationApprovalRepository) GetByID(id uuid.UUID) (*domain.VerificationApproval, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	approval, ok := r.approvals[id]
+	if !ok {
+		return nil, fmt.Errorf("verification approval not found")
+	}
+	copied := *approval
+	return &copied, nil
+}
+
+func (r *fakeVerificationApprovalRepository) List(orgID uuid.UUID, status *domain.VerificationApprovalStatus, limit, offset int) ([]*domain.VerificationApproval, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	approvals := []*domain.VerificationApproval{}
+	for _, approval := range r.approvals {
+		if approval.OrganizationID == orgID && (status == nil || approval.Status == *status) {
+			copied := *approval
+			approvals = append(approvals, &copied)
+		}
+	}
+	return approvals, len(approvals), nil
+}
+
+func (r *fakeVerificationApprovalRepository) Decide(approval *domain.VerificationApproval) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.approvals[approval.ID]
+	if stored.Status != domain.VerificationApprovalPending {
+		return fmt.Errorf("verification approval already decided")
+	}
+	if approval.Status != domain.VerificationApprovalTimeout && !approval.DecidedAt.Before(stored.ExpiresAt) {
+		return fmt.Errorf("verification approval already decided or expired")
+	}
+	stored.Status = approval.Status
+	stored.DecidedBy = approval.DecidedBy
+	stored.DecidedVia = approval.DecidedVia
+	stored.DecisionReason = approval.DecisionReason
+	stored.DecidedAt = approval.DecidedAt
+	return nil
+}
+
+func (r *fakeVerificationApprovalRepository) ListExpired(now time.Time) ([]*domain.VerificationApproval, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	approvals := []*domain.VerificationApproval{}
+	for _, approval := range r.approvals {
+		if approval.Status == domain.VerificationApprovalPending && !approval.ExpiresAt.After(now) {
+			copied := *approval
+			approvals = append(approvals, &copied)
+		}
+	}
+	return approvals, nil
+}
+
+func (r *fakeVerificationApprovalRepository) AttachVerificationEvent(id, eventID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.approvals[id].VerificationEventID = &eventID
+	return nil
+}
+
+// fakeVerificationEventRecorder records how verification events were completed
+type fakeVerificationEventRecorder struct {
+	mu       sync.Mutex
+	events   map[uuid.UUID]*domain.VerificationEvent
+	results  map[uuid.UUID]domain.VerificationResult
+	metadata map[uuid.UUID]map[string]interface{}
+}
+
+func (f *fakeVerificationEventRecorder) GetVerificationEvent(ctx context.Context, id uuid.UUID) (*domain.VerificationEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	event, ok := f.events[id]
+	if !ok {
+		return nil, fmt.Errorf("verification event not found")
+	}
+	return event, nil
+}
+
+func (f *fakeVerificationEventRecorder) UpdateVerificationResult(ctx context.Context, id uuid.UUID, result domain.VerificationResult, reason *string, metadata map[string]interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[id] = result
+	f.metadata[id] = metadata
+	return nil
+}
+
+func (f *fakeVerificationEventRecorder) result(id uuid.UUID) domain.VerificationResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.results[id]
+}
+
+type fakeWebhookDispatcher struct {
+	mu     sync.Mutex
+	events []domain.WebhookEvent
+}
+
+func (f *fakeWebhookDispatcher) DispatchEvent(ctx context.Context, orgID uuid.UUID, event domain.WebhookEvent, data interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+// createTestApprovalAgent creates an agent whose high-risk actions need approval
+func createTestApprovalAgent() *domain.Agent {
+	return &domain.Agent{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Name:           "ops-bot",
+		DisplayName:    "Ops Bot",
+	}
+}
+
+// createTestApprovers creates an organization's users: an admin and a manager who approve, a
+// member and a suspended admin who do not
+func createTestApprovers(orgID uuid.UUID) (admin, manager *domain.User, userRepo *MockUserRepository) {
+	user := func(email string, role domain.UserRole, status domain.UserStatus) *domain.User {
+		return &domain.User{ID: uuid.New(), OrganizationID: orgID, Email: email, Role: role, Status: status}
+	}
+	admin = user("admin@example.com", domain.RoleAdmin, domain.UserStatusActive)
+	manager = user("manager@example.com", domain.RoleManager, domain.UserStatusActive)
+	users := []*domain.User{
+		admin,
+		manager,
+		user("member@example.com", domain.RoleMember, domain.UserStatusActive),
+		user("former-admin@example.com", domain.RoleAdmin, domain.UserStatusSuspended),
+	}
+
+	userRepo = new(MockUserRepository)
+	userRepo.On("GetByOrganization", orgID).Return(users, nil)
+	for _, u := range users {
+		userRepo.On("GetByID", u.ID).Return(u, nil)
+	}
+	return admin, manager, userRepo
+}
+
+// createTestVerificationApprovalService creates a service whose clock reads *clock
+func createTestVerificationApprovalService(userRepo domain.UserRepository, clock *time.Time) (*VerificationApprovalService, *fakeVerificationApprovalRepository, *fakeVerificationEventRecorder, *fakeWebhookDispatcher, *MockEmailService) {
+	repo := &fakeVerificationApprovalRepository{approvals: map[uuid.UUID]*domain.VerificationApproval{}}
+	events := &fakeVerificationEventRecorder{events: map[uuid.UUID]*domain.VerificationEvent{}, results: map[uuid.UUID]domain.VerificationResult{}, metadata: map[uuid.UUID]map[string]interface{}{}}
+	webhooks := &fakeWebhookDispatcher{}
+	emailService := new(MockEmailService)
+	emailService.On("SendTemplatedEmail", domain.TemplateVerificationApprovalRequest, mock.Anything, mock.Anything).Return(nil)
+
+	service := NewVerificationApprovalService(repo, userRepo, events, webhooks, emailService, "test-signing-secret", 10*time.Minute)
+	service.now = func() time.Time { return *clock }
+	return service, repo, events, webhooks, emailService
+}
+
+func enqueueTestApproval(t *testing.T, service *VerificationApprovalService, events *fakeVerificationEventRecorder, agent *domain.Agent, callbackURL string) *domain.VerificationApproval {
+	approval, err := service.Enqueue(context.Background(), agent, VerificationApprovalRequest{
+		VerificationID: uuid.New(),
+		ActionType:     "delete_data",
+		Resource:       "customers",
+		TrustScore:     0.82,
+		Reason:         "Action delete_data is high risk and requires human approval",
+		CallbackURL:    callbackURL,
+	})
+	require.NoError(t, err)
+
+	eventID := uuid.New()
+	events.events[eventID] = &domain.VerificationEvent{ID: eventID, Metadata: map[string]interface{}{"verification_id": approval.ID.String()}}
+	require.NoError(t, service.AttachVerificationEvent(context.Background(), approval, eventID))
+	return approval
+}
+
+// approvalLinkToken returns the signed link token from the last approval email sent to an approver
+func approvalLinkToken(t *testing.T, emailService *MockEmailService, to, link string) string {
+	var data domain.EmailTemplateData
+	sent := false
+	for _, call := range emailService.Calls {
+		if call.Method == "SendTemplatedEmail" && call.Arguments.String(1) == to {
+			data = call.Arguments.Get(2).(domain.EmailTemplateData)
+			sent = true
+		}
+	}
+	require.True(t, sent, "no approval email sent to %s", to)
+	url := data.CustomData[link].(string)
+	return url[strings.LastIndex(url, "/")+1:]
+}
+
+func TestVerificationApprovalNotifiesApproversAndDecides(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	agent := createTestApprovalAgent()
+	admin, manager, userRepo := createTestApprovers(agent.OrganizationID)
+	service, _, events, webhooks, emailService := createTestVerificationApprovalService(userRepo, &clock)
+	approval := enqueueTestApproval(t, service, events, agent, "")
+
+	assert.Equal(t, domain.VerificationApprovalPending, approval.Status)
+	assert.Equal(t, clock.Add(10*time.Minute), approval.ExpiresAt)
+	assert.Equal(t, "Ops Bot", approval.AgentName)
+
+	// Only active admins and managers are asked
+	emailService.AssertNumberOfCalls(t, "SendTemplatedEmail", 2)
+	emailService.AssertCalled(t, "SendTemplatedEmail", domain.TemplateVerificationApprovalRequest, "admin@example.com", mock.Anything)
+	emailService.AssertCalled(t, "SendTemplatedEmail", domain.TemplateVerificationApprovalRequest, "manager@example.com", mock.Anything)
+	assert.Equal(t, []domain.WebhookEvent{domain.WebhookEventVerificationApprovalRequested}, webhooks.events)
+
+	decided, err := service.Decide(context.Background(), agent.OrganizationID, approval.ID, manager.ID, domain.VerificationApprovalApproved, "")
+	require.NoError(t, err)
+	assert.Equal(t, domain.VerificationApprovalApproved, decided.Status)
+	assert.Equal(t, domain.VerificationApprovalViaDashboard, decided.DecidedVia)
+	assert.Equal(t, "manager@example.com", decided.DecidedByEmail)
+
+	// The dashboard verification event is completed and keeps its original metadata
+	eventID := *decided.VerificationEventID
+	assert.Equal(t, domain.VerificationResultVerified, events.result(eventID))
+	assert.Equal(t, approval.ID.String(), events.metadata[eventID]["verification_id"])
+	assert.Equal(t, domain.VerificationApprovalApproved, events.metadata[eventID]["approval_status"])
+	assert.Contains(t, webhooks.events, domain.WebhookEventVerificationApprovalDecided)
+
+	_, err = service.Decide(context.Background(), agent.OrganizationID, approval.ID, admin.ID, domain.VerificationApprovalDenied, "too late")
+	assert.EqualError(t, err, "verification approval already decided")
+
+	_, err = service.GetApproval(context.Background(), uuid.New(), approval.ID)
+	assert.EqualError(t, err, "verification approval not found")
+}
+
+func TestVerificationApprovalDenyRequiresReason(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	agent := createTestApprovalAgent()
+	admin, _, userRepo := createTestApprovers(agent.OrganizationID)
+	service, _, events, _, _ := createTestVerificationApprovalService(userRepo, &clock)
+	approval := enqueueTestApproval(t, service, events, agent, "")
+
+	_, err := service.Decide(context.Background(), agent.OrganizationID, approval.ID, admin.ID, domain.VerificationApprovalDenied, "  ")
+	assert.EqualError(t, err, "invalid decision: a reason is required to deny")
+	_, err = service.Decide(context.Background(), agent.OrganizationID, approval.ID, admin.ID, domain.VerificationApprovalTimeout, "")
+	assert.EqualError(t, err, "invalid decision: must be approved or denied")
+
+	decided, err := service.Decide(context.Background(), agent.OrganizationID, approval.ID, admin.ID, domain.VerificationApprovalDenied, "Not during the freeze")
+	require.NoError(t, err)
+	assert.Equal(t, "Not during the freeze", decided.DecisionReason)
+	assert.Equal(t, domain.VerificationResultDenied, events.result(*decided.VerificationEventID))
+
+	_, err = service.Enqueue(context.Background(), agent, VerificationApprovalRequest{
+		VerificationID: uuid.New(),
+		ActionType:     "execute_command",
+		CallbackURL:    "ftp://example.com/hook",
+	})
+	assert.EqualError(t, err, "invalid callback_url: must be an absolute http(s) URL")
+}
+
+func TestVerificationApprovalCallbackURLRestrictions(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	service, _, _, _, _ := createTestVerificationApprovalService(new(MockUserRepository), &clock)
+	ctx := context.Background()
+
+	// Callbacks cannot reach internal addresses such as the cloud metadata service
+	assert.ErrorContains(t, service.ValidateCallbackURL(ctx, "http://169.254.169.254/latest/meta-data"), "not allowed")
+	assert.ErrorContains(t, service.ValidateCallbackURL(ctx, "http://localhost:8080/hook"), "not allowed")
+	assert.ErrorContains(t, service.ValidateCallbackURL(ctx, "https://[::1]/hook"), "not allowed")
+	assert.NoError(t, service.ValidateCallbackURL(ctx, "http://93.184.216.34/hook"))
+
+	// Outside development callbacks must use https; allowed networks are opt-in
+	internal, err := netguard.NewPolicy([]string{"10.20.0.0/16"})
+	require.NoError(t, err)
+	service.RestrictCallbacks(internal, true)
+	assert.EqualError(t, service.ValidateCallbackURL(ctx, "http://93.184.216.34/hook"), "invalid callback_url: must be an https URL")
+	assert.NoError(t, service.ValidateCallbackURL(ctx, "https://10.20.1.5/hook"))
+	assert.ErrorContains(t, service.ValidateCallbackURL(ctx, "https://10.30.1.5/hook"), "not allowed")
+}
+
+func TestVerificationApprovalSignedLinks(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	agent := createTestApprovalAgent()
+	admin, manager, userRepo := createTestApprovers(agent.OrganizationID)
+	service, _, events, _, emailService := createTestVerificationApprovalService(userRepo, &clock)
+	enqueueTestApproval(t, service, events, agent, "")
+
+	// Resolving a link, as the confirmation page does, decides nothing
+	approveToken := approvalLinkToken(t, emailService, "admin@example.com", "ApproveURL")
+	link, err := service.ResolveDecisionLink(context.Background(), approveToken)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VerificationApprovalApproved, link.Decision)
+	assert.Equal(t, admin.ID, link.Approver.ID)
+	assert.Equal(t, domain.VerificationApprovalPending, link.Approval.Status)
+
+	// The decision is covered by the signature
+	tampered := strings.Replace(approveToken, ".approved.", ".denied.", 1)
+	_, err = service.ResolveDecisionLink(context.Background(), tampered)
+	assert.EqualError(t, err, "invalid approval link")
+
+	// Links stop working when the approver is no longer an approver
+	managerToken := approvalLinkToken(t, emailService, "manager@example.com", "ApproveURL")
+	manager.Role = domain.RoleMember
+	_, err = service.DecideWithLink(context.Background(), managerToken, "")
+	assert.EqualError(t, err, "approval link is no longer valid")
+
+	denyToken := approvalLinkToken(t, emailService, "admin@example.com", "DenyURL")
+	_, err = service.DecideWithLink(context.Background(), denyToken, "")
+	assert.EqualError(t, err, "invalid decision: a reason is required to deny")
+
+	link, err = service.DecideWithLink(context.Background(), denyToken, "Unexpected target")
+	require.NoError(t, err)
+	assert.Equal(t, domain.VerificationApprovalDenied, link.Approval.Status)
+	assert.Equal(t, domain.VerificationApprovalViaLink, link.Approval.DecidedVia)
+	assert.Equal(t, admin.ID, *link.Approval.DecidedBy)
+
+	_, err = service.DecideWithLink(context.Background(), approveToken, "")
+	assert.EqualError(t, err, "verification approval already decided")
+
+	// Links expire with the approval
+	other := enqueueTestApproval(t, service, events, agent, "")
+	token := service.decisionToken(other, admin.ID, domain.VerificationApprovalApproved)
+	clock = clock.Add(11 * time.Minute)
+	_, err = service.ResolveDecisionLink(context.Background(), token)
+	assert.EqualError(t, err, "approval link has expired")
+}
+
+func TestVerificationApprovalTimesOut(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	agent := createTestApprovalAgent()
+	admin, _, userRepo := createTestApprovers(agent.OrganizationID)
+	service, repo, events, _, _ := createTestVerificationApprovalService(userRepo, &clock)
+	expired := enqueueTestApproval(t, service, events, agent, "")
+	clock = clock.Add(5 * time.Minute)
+	stillPending := enqueueTestApproval(t, service, events, agent, "")
+
+	clock = clock.Add(6 * time.Minute)
+	require.NoError(t, service.ExpirePending(context.Background()))
+
+	approval, err := service.GetApproval(context.Background(), agent.OrganizationID, expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VerificationApprovalTimeout, approval.Status)
+	assert.Equal(t, domain.VerificationResultExpired, events.result(*approval.VerificationEventID))
+
+	approval, err = service.GetApproval(context.Background(), agent.OrganizationID, stillPending.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VerificationApprovalPending, approval.Status)
+
+	// Reads time out a pending approval past its deadline even before the job runs
+	clock = clock.Add(5 * time.Minute)
+	approval, err = service.GetApproval(context.Background(), agent.OrganizationID, stillPending.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VerificationApprovalTimeout, approval.Status)
+
+	_, err = service.Decide(context.Background(), agent.OrganizationID, stillPending.ID, admin.ID, domain.VerificationApprovalApproved, "")
+	assert.EqualError(t, err, "verification approval already decided")
+
+	// A decision racing the deadline loses, even when the approval was read while still open
+	late := enqueueTestApproval(t, service, events, agent, "")
+	clock = clock.Add(10 * time.Minute)
+	_, err = service.decide(context.Background(), late, &admin.ID, domain.VerificationApprovalViaDashboard, domain.VerificationApprovalApproved, "")
+	assert.EqualError(t, err, "verification approval has expired")
+	late.Status = domain.VerificationApprovalApproved
+	late.DecidedAt = &clock
+	assert.Error(t, repo.Decide(late))
+}
+
+func TestVerificationApprovalWaitAndCallback(t *testing.T) {
+	callbacks := make(chan VerificationApprovalCallback, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var callback VerificationApprovalCallback
+		if err := json.NewDecoder(r.Body).Decode(&callback); err == nil {
+			callbacks <- callback
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	agent := createTestApprovalAgent()
+	admin, _, userRepo := createTestApprovers(agent.OrganizationID)
+	service, _, events, _, _ := createTestVerificationApprovalService(userRepo, &clock)
+	loopback, err := netguard.NewPolicy([]string{"127.0.0.0/8"})
+	require.NoError(t, err)
+	service.RestrictCallbacks(loopback, false)
+	approval := enqueueTestApproval(t, service, events, agent, server.URL)
+
+	// Without wait the current state is returned immediately
+	current, err := service.Wait(context.Background(), agent.OrganizationID, approval.ID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VerificationApprovalPending, current.Status)
+
+	waited := make(chan *domain.VerificationApproval, 1)
+	go func() {
+		result, _ := service.Wait(context.Background(), agent.OrganizationID, approval.ID, 10*time.Second)
+		waited <- result
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	started := time.Now()
+	_, err = service.Decide(context.Background(), agent.OrganizationID, approval.ID, admin.ID, domain.VerificationApprovalApproved, "Planned cleanup")
+	require.NoError(t, err)
+
+	select {
+	case result := <-waited:
+		assert.Equal(t, domain.VerificationApprovalApproved, result.Status)
+		assert.Less(t, time.Since(started), verificationApprovalPollInterval)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait did not return after the decision")
+	}
+
+	select {
+	case callback := <-callbacks:
+		assert.Equal(t, approval.ID.String(), callback.ID)
+		assert.Equal(t, "approved", callback.Status)
+		assert.Equal(t, "admin@example.com", callback.ApprovedBy)
+		assert.Equal(t, "Planned cleanup", callback.DecisionReason)
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback was not sent")
+	}
+}
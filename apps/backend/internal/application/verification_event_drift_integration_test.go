@@ -142,7 +142,6 @@ func TestVerificationEventWithDriftDetection(t *testing.T) {
 			Protocol:            domain.VerificationProtocolMCP,
 			VerificationType:    domain.VerificationTypeIdentity,
 			Status:              domain.VerificationEventStatusSuccess,
-			Confidence:          0.95,
 			DurationMs:          150,
 			InitiatorType:       domain.InitiatorTypeSystem,
 			StartedAt:           time.Now().Add(-150 * time.Millisecond),
@@ -196,7 +195,6 @@ func TestVerificationEventWithDriftDetection(t *testing.T) {
 			Protocol:          domain.VerificationProtocolMCP,
 			VerificationType:  domain.VerificationTypeIdentity,
 			Status:            domain.VerificationEventStatusSuccess,
-			Confidence:        0.95,
 			DurationMs:        150,
 			InitiatorType:     domain.InitiatorTypeSystem,
 			StartedAt:         time.Now().Add(-150 * time.Millisecond),
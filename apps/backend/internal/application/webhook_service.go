@@ -147,6 +147,44 @@ func (s *WebhookService) TestWebhook(ctx context.Context, id uuid.UUID) (*Webhoo
 	return result, nil
 }
 
+// DispatchEvent delivers an event to every active webhook of the organization subscribed to it.
+// Deliveries run in the background so callers on a request path are not held up by slow receivers.
+func (s *WebhookService) DispatchEvent(ctx context.Context, orgID uuid.UUID, event domain.WebhookEvent, data interface{}) error {
+	webhooks, err := s.webhookRepo.GetByOrganization(orgID)
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.IsActive || !webhookSubscribed(webhook, event) {
+			continue
+		}
+
+		payload := map[string]interface{}{
+			"event":      string(event),
+			"webhook_id": webhook.ID.String(),
+			"timestamp":  time.Now().UTC(),
+			"data":       data,
+		}
+		go func(webhook *domain.Webhook) {
+			if err := s.sendWebhook(webhook, string(event), payload); err != nil {
+				fmt.Printf("⚠️  Webhook %s delivery of %s failed: %v\n", webhook.ID, event, err)
+			}
+		}(webhook)
+	}
+
+	return nil
+}
+
+func webhookSubscribed(webhook *domain.Webhook, event domain.WebhookEvent) bool {
+	for _, subscribed := range webhook.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
 // sendWebhookWithResult sends a webhook payload and returns status code and error
 func (s *WebhookService) sendWebhookWithResult(webhook *domain.Webhook, event string, payload interface{}) (int, error) {
 	jsonData, err := json.Marshal(payload)
@@ -0,0 +1,376 @@
+package application
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/spiffe"
+)
+
+var (
+	ErrInvalidSPIFFEBinding        = errors.New("invalid SPIFFE binding")
+	ErrSPIFFEBindingNotFound       = errors.New("SPIFFE binding not found")
+	ErrSVIDRejected                = errors.New("SVID rejected")
+	ErrAgentCANotEnabled           = errors.New("agent certificate authority is not enabled")
+	ErrAgentCertificateUnavailable = errors.New("certificates are only issued to verified agents")
+	ErrAgentCertificateNotFound    = errors.New("agent has no valid certificate")
+	ErrInvalidCSR                  = errors.New("invalid certificate signing request")
+)
+
+// expiredCertificateRetention is how long issued certificates are kept after they expire
+const expiredCertificateRetention = 7 * 24 * time.Hour
+
+// WorkloadIdentityService authenticates agents by their SPIFFE workload identity. SVIDs are
+// verified against configured trust bundles and mapped to agents through the SPIFFE ID
+// patterns admins bind to them. Optionally AIM is itself an issuing CA that mints short-lived
+// X.509-SVIDs (spiffe://<trust domain>/agents/<agent ID>) which authenticate without a binding.
+type WorkloadIdentityService struct {
+	bindingRepo     domain.SPIFFEBindingRepository
+	certificateRepo domain.AgentCertificateRepository
+	agentRepo       domain.AgentRepository
+	bundles         map[string]*spiffe.Bundle
+	audience        string
+	issuer          string
+	// forwardedCertificateHeader carries the client certificate when a proxy terminates TLS;
+	// it is only read on connections from trustedProxies
+	forwardedCertificateHeader string
+	trustedProxies             []*net.IPNet
+	ca                         *spiffe.CA
+	certificateTTL             time.Duration
+	now                        func() time.Time
+}
+
+// NewWorkloadIdentityService creates a new workload identity service. JWT-SVIDs must be issued
+// for audience, which defaults to the issuer URL.
+func NewWorkloadIdentityService(
+	bindingRepo domain.SPIFFEBindingRepository,
+	certificateRepo domain.AgentCertificateRepository,
+	agentRepo domain.AgentRepository,
+	bundles map[string]*spiffe.Bundle,
+	audience string,
+	issuer string,
+) *WorkloadIdentityService {
+	issuer = strings.TrimRight(issuer, "/")
+	if audience == "" {
+		audience = issuer
+	}
+	if bundles == nil {
+		bundles = map[string]*spiffe.Bundle{}
+	}
+	return &WorkloadIdentityService{
+		bindingRepo:     bindingRepo,
+		certificateRepo: certificateRepo,
+		agentRepo:       agentRepo,
+		bundles:         bundles,
+		audience:        audience,
+		issuer:          issuer,
+		now:             time.Now,
+	}
+}
+
+// UseCA makes AIM an issuing CA for agents. Its trust domain is trusted for X.509-SVIDs.
+func (s *WorkloadIdentityService) UseCA(ca *spiffe.CA, certificateTTL time.Duration) {
+	s.ca = ca
+	s.certificateTTL = certificateTTL
+
+	bundle, ok := s.bundles[ca.TrustDomain]
+	if !ok {
+		s.bundles[ca.TrustDomain] = ca.Bundle()
+		return
+	}
+	bundle.X509Authorities = append(bundle.X509Authorities, ca.Bundle().X509Authorities...)
+}
+
+// TrustForwardedCertificates accepts X.509-SVIDs a TLS-terminating proxy forwards, URL-encoded,
+// in the given header. The header is only read on connections from the given proxy addresses
+// (CIDRs or IPs); the proxy must still strip it from client requests.
+func (s *WorkloadIdentityService) TrustForwardedCertificates(header string, proxies []string) error {
+	trusted := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return fmt.Errorf("invalid trusted proxy address %q", proxy)
+			}
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy CIDR %q: %w", proxy, err)
+		}
+		trusted = append(trusted, network)
+	}
+	if len(trusted) == 0 {
+		return fmt.Errorf("forwarded client certificates require at least one trusted proxy")
+	}
+
+	s.forwardedCertificateHeader = header
+	s.trustedProxies = trusted
+	return nil
+}
+
+// ForwardedCertificateHeader returns the header forwarded client certificates arrive in, or ""
+// when the request did not come directly from a trusted proxy
+func (s *WorkloadIdentityService) ForwardedCertificateHeader(peer net.IP) string {
+	if s.forwardedCertificateHeader == "" || peer == nil {
+		return ""
+	}
+	for _, proxy := range s.trustedProxies {
+		if proxy.Contains(peer) {
+			return s.forwardedCertificateHeader
+		}
+	}
+	return ""
+}
+
+// AuthenticateX509SVID authenticates an agent by an X.509-SVID chain (leaf first). agentHint
+// names the agent when the SPIFFE ID is bound to several.
+func (s *WorkloadIdentityService) AuthenticateX509SVID(ctx context.Context, chain []*x509.Certificate, agentHint *uuid.UUID) (*domain.Agent, spiffe.ID, error) {
+	id, err := spiffe.VerifyX509SVID(chain, s.bundles, s.now())
+	if err != nil {
+		return nil, spiffe.ID{}, fmt.Errorf("%w: %v", ErrSVIDRejected, err)
+	}
+	agent, err := s.resolveAgent(id, agentHint)
+	return agent, id, err
+}
+
+// AuthenticateJWTSVID authenticates an agent by a JWT-SVID. agentHint names the agent when the
+// SPIFFE ID is bound to several.
+func (s *WorkloadIdentityService) AuthenticateJWTSVID(ctx context.Context, token string, agentHint *uuid.UUID) (*domain.Agent, spiffe.ID, error) {
+	id, err := spiffe.VerifyJWTSVID(token, s.bundles, s.audience, s.now())
+	if err != nil {
+		return nil, spiffe.ID{}, fmt.Errorf("%w: %v", ErrSVIDRejected, err)
+	}
+	agent, err := s.resolveAgent(id, agentHint)
+	return agent, id, err
+}
+
+// resolveAgent maps a verified SPIFFE ID to the agent it is bound to
+func (s *WorkloadIdentityService) resolveAgent(id spiffe.ID, agentHint *uuid.UUID) (*domain.Agent, error) {
+	candidates := map[uuid.UUID]bool{}
+	if s.ca != nil && id.TrustDomain == s.ca.TrustDomain && strings.HasPrefix(id.Path, "/agents/") {
+		if agentID, err := uuid.Parse(strings.TrimPrefix(id.Path, "/agents/")); err == nil {
+			candidates[agentID] = true
+		}
+	}
+
+	bindings, err := s.bindingRepo.ListByTrustDomain(id.TrustDomain)
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range bindings {
+		if spiffe.MatchPattern(binding.SPIFFEIDPattern, id) {
+			candidates[binding.AgentID] = true
+		}
+	}
+
+	var agentID uuid.UUID
+	switch {
+	case agentHint != nil:
+		if !candidates[*agentHint] {
+			return nil, fmt.Errorf("%w: %s is not bound to agent %s", ErrSVIDRejected, id, *agentHint)
+		}
+		agentID = *agentHint
+	case len(candidates) == 0:
+		return nil, fmt.Errorf("%w: no agent is bound to %s", ErrSVIDRejected, id)
+	case len(candidates) > 1:
+		return nil, fmt.Errorf("%w: %s is bound to several agents; name one in X-Agent-ID", ErrSVIDRejected, id)
+	default:
+		for candidate := range candidates {
+			agentID = candidate
+		}
+	}
+
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil {
+		return nil, fmt.Errorf("%w: agent not found", ErrSVIDRejected)
+	}
+	if agent.Status == domain.AgentStatusRevoked || agent.Status == domain.AgentStatusSuspended || agent.IsCompromised {
+		return nil, fmt.Errorf("%w: agent is %s", ErrSVIDRejected, agentStatusDescription(agent))
+	}
+	return agent, nil
+}
+
+func agentStatusDescription(agent *domain.Agent) string {
+	if agent.IsCompromised {
+		return "marked as compromised"
+	}
+	return string(agent.Status)
+}
+
+// CreateBinding binds a SPIFFE ID pattern to an agent
+func (s *WorkloadIdentityService) CreateBinding(ctx context.Context, orgID, agentID, userID uuid.UUID, pattern string) (*domain.SPIFFEBinding, error) {
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil || agent.OrganizationID != orgID {
+		return nil, fmt.Errorf("agent not found")
+	}
+
+	pattern = strings.TrimSpace(pattern)
+	trustDomain, err := spiffe.ValidatePattern(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSPIFFEBinding, err)
+	}
+	if _, ok := s.bundles[trustDomain]; !ok {
+		return nil, fmt.Errorf("%w: no trust bundle is configured for trust domain %s", ErrInvalidSPIFFEBinding, trustDomain)
+	}
+	// AIM's own SVIDs name their agent; binding them to another agent would hijack it
+	if s.ca != nil && spiffe.PatternsOverlap(pattern, "spiffe://"+s.ca.TrustDomain+"/agents/*") {
+		return nil, fmt.Errorf("%w: spiffe://%s/agents/ is reserved for certificates AIM issues", ErrInvalidSPIFFEBinding, s.ca.TrustDomain)
+	}
+	// Trust bundles are shared by every organization, so a workload may only ever resolve to
+	// agents of one organization
+	existing, err := s.bindingRepo.ListByTrustDomain(trustDomain)
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range existing {
+		if binding.OrganizationID != orgID && spiffe.PatternsOverlap(pattern, binding.SPIFFEIDPattern) {
+			return nil, fmt.Errorf("%w: pattern overlaps a SPIFFE ID bound in another organization", ErrInvalidSPIFFEBinding)
+		}
+	}
+
+	binding := &domain.SPIFFEBinding{
+		ID:              uuid.New(),
+		AgentID:         agentID,
+		OrganizationID:  orgID,
+		SPIFFEIDPattern: pattern,
+		TrustDomain:     trustDomain,
+		CreatedBy:       &userID,
+		CreatedAt:       s.now(),
+	}
+	if err := s.bindingRepo.Create(binding); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
+
+// ListBindings returns the SPIFFE ID patterns bound to an agent
+func (s *WorkloadIdentityService) ListBindings(ctx context.Context, orgID, agentID uuid.UUID) ([]*domain.SPIFFEBinding, error) {
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil || agent.OrganizationID != orgID {
+		return nil, fmt.Errorf("agent not found")
+	}
+	return s.bindingRepo.ListByAgent(agentID)
+}
+
+// DeleteBinding removes a SPIFFE ID pattern from an agent
+func (s *WorkloadIdentityService) DeleteBinding(ctx context.Context, orgID, agentID, bindingID uuid.UUID) error {
+	bindings, err := s.ListBindings(ctx, orgID, agentID)
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings {
+		if binding.ID == bindingID {
+			return s.bindingRepo.Delete(bindingID)
+		}
+	}
+	return ErrSPIFFEBindingNotFound
+}
+
+// CAEnabled reports whether AIM issues certificates to agents
+func (s *WorkloadIdentityService) CAEnabled() bool {
+	return s.ca != nil
+}
+
+// CACertificatePEM returns the certificate of AIM's issuing CA
+func (s *WorkloadIdentityService) CACertificatePEM() ([]byte, error) {
+	if s.ca == nil {
+		return nil, ErrAgentCANotEnabled
+	}
+	return s.ca.CertificatePEM(), nil
+}
+
+// CertificateURL returns the URL of an agent's current certificate; it requires authentication
+// as a member of the agent's organization or as the agent itself
+func (s *WorkloadIdentityService) CertificateURL(agentID uuid.UUID) string {
+	return s.issuer + "/api/v1/agents/" + agentID.String() + "/certificate"
+}
+
+// IssueCertificate issues a short-lived X.509-SVID for the key in a CSR and points the agent's
+// CertificateURL at it
+func (s *WorkloadIdentityService) IssueCertificate(ctx context.Context, orgID, agentID uuid.UUID, csrPEM string) (*domain.AgentCertificate, error) {
+	if s.ca == nil {
+		return nil, ErrAgentCANotEnabled
+	}
+	agent, err := s.agentRepo.GetByID(agentID)
+	if err != nil || agent == nil || agent.OrganizationID != orgID {
+		return nil, fmt.Errorf("agent not found")
+	}
+	if agent.Status != domain.AgentStatusVerified || agent.IsCompromised {
+		return nil, ErrAgentCertificateUnavailable
+	}
+
+	csr, err := spiffe.ParseCSR([]byte(csrPEM))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+
+	id := spiffe.ID{TrustDomain: s.ca.TrustDomain, Path: "/agents/" + agent.ID.String()}
+	now := s.now()
+	issued, err := s.ca.Sign(csr, id, now, s.certificateTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate := &domain.AgentCertificate{
+		ID:             uuid.New(),
+		AgentID:        agent.ID,
+		OrganizationID: agent.OrganizationID,
+		SerialNumber:   issued.SerialNumber.Text(16),
+		SPIFFEID:       id.String(),
+		CertificatePEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issued.Raw})),
+		NotBefore:      issued.NotBefore,
+		NotAfter:       issued.NotAfter,
+		CreatedAt:      now,
+	}
+	if err := s.certificateRepo.Create(certificate); err != nil {
+		return nil, err
+	}
+
+	if certificateURL := s.CertificateURL(agent.ID); agent.CertificateURL != certificateURL {
+		agent.CertificateURL = certificateURL
+		if err := s.agentRepo.Update(agent); err != nil {
+			return nil, fmt.Errorf("failed to update agent certificate URL: %w", err)
+		}
+	}
+
+	fmt.Printf("📜 Issued certificate %s for %s (expires %s)\n", certificate.SerialNumber, id, certificate.NotAfter.Format(time.RFC3339))
+	return certificate, nil
+}
+
+// CurrentCertificate returns an agent's latest certificate if it is still valid
+func (s *WorkloadIdentityService) CurrentCertificate(ctx context.Context, orgID, agentID uuid.UUID) (*domain.AgentCertificate, error) {
+	certificate, err := s.certificateRepo.GetLatestByAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+	if certificate == nil || certificate.OrganizationID != orgID || !s.now().Before(certificate.NotAfter) {
+		return nil, ErrAgentCertificateNotFound
+	}
+	return certificate, nil
+}
+
+// CleanupExpiredCertificates deletes certificates that expired over a week ago
+func (s *WorkloadIdentityService) CleanupExpiredCertificates(ctx context.Context) error {
+	deleted, err := s.certificateRepo.DeleteExpired(s.now().Add(-expiredCertificateRetention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		fmt.Printf("🧹 Deleted %d expired agent certificates\n", deleted)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package application
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/spiffe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSPIFFEBindingRepository struct {
+	bindings []*domain.SPIFFEBinding
+}
+
+func (r *fakeSPIFFEBindingRepository) Create(binding *domain.SPIFFEBinding) error {
+	r.bindings = append(r.bindings, binding)
+	return nil
+}
+
+func (r *fakeSPIFFEBindingRepository) ListByAgent(agentID uuid.UUID) ([]*domain.SPIFFEBinding, error) {
+	bindings := []*domain.SPIFFEBinding{}
+	for _, binding := range r.bindings {
+		if binding.AgentID == agentID {
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings, nil
+}
+
+func (r *fakeSPIFFEBindingRepository) ListByTrustDomain(trustDomain string) ([]*domain.SPIFFEBinding, error) {
+	bindings := []*domain.SPIFFEBinding{}
+	for _, binding := range r.bindings {
+		if binding.TrustDomain == trustDomain {
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings, nil
+}
+
+func (r *fakeSPIFFEBindingRepository) Delete(id uuid.UUID) error {
+	for i, binding := range r.bindings {
+		if binding.ID == id {
+			r.bindings = append(r.bindings[:i], r.bindings[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+type fakeAgentCertificateRepository struct {
+	certificates []*domain.AgentCertificate
+}
+
+func (r *fakeAgentCertificateRepository) Create(certificate *domain.AgentCertificate) error {
+	r.certificates = append(r.certificates, certificate)
+	return nil
+}
+
+func (r *fakeAgentCertificateRepository) GetLatestByAgent(agentID uuid.UUID) (*domain.AgentCertificate, error) {
+	var latest *domain.AgentCertificate
+	for _, certificate := range r.certificates {
+		if certificate.AgentID == agentID && (latest == nil || certificate.NotAfter.After(latest.NotAfter)) {
+			latest = certificate
+		}
+	}
+	return latest, nil
+}
+
+func (r *fakeAgentCertificateRepository) DeleteExpired(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// createTestWorkloadIdentityService creates a service trusting the prod.example.org trust domain.
+// The returned CA stands in for that trust domain's SPIRE server.
+func createTestWorkloadIdentityService(t *testing.T) (*WorkloadIdentityService, *fakeAgentRepository, *spiffe.CA) {
+	workloadCA, err := spiffe.NewEphemeralCA("prod.example.org", 24*time.Hour)
+	require.NoError(t, err)
+
+	agents := &fakeAgentRepository{agents: map[uuid.UUID]*domain.Agent{}}
+	service := NewWorkloadIdentityService(
+		&fakeSPIFFEBindingRepository{},
+		&fakeAgentCertificateRepository{},
+		agents,
+		map[string]*spiffe.Bundle{"prod.example.org": workloadCA.Bundle()},
+		"",
+		"https://aim.example.com/",
+	)
+	return service, agents, workloadCA
+}
+
+func createTestWorkloadAgent(agents *fakeAgentRepository, orgID uuid.UUID, status domain.AgentStatus) *domain.Agent {
+	agent := &domain.Agent{ID: uuid.New(), OrganizationID: orgID, Name: "payments-agent", Status: status}
+	agents.agents[agent.ID] = agent
+	return agent
+}
+
+func newTestCSRPEM(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+// newTestWorkloadSVID mints an X.509-SVID for a workload of the prod.example.org trust domain
+func newTestWorkloadSVID(t *testing.T, workloadCA *spiffe.CA, path string) []*x509.Certificate {
+	csr, err := spiffe.ParseCSR([]byte(newTestCSRPEM(t)))
+	require.NoError(t, err)
+	certificate, err := workloadCA.Sign(csr, spiffe.ID{TrustDomain: "prod.example.org", Path: path}, time.Now(), time.Hour)
+	require.NoError(t, err)
+	return []*x509.Certificate{certificate}
+}
+
+func TestWorkloadIdentityService_ResolvesBoundAgents(t *testing.T) {
+	service, agents, workloadCA := createTestWorkloadIdentityService(t)
+	orgID := uuid.New()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	payments := createTestWorkloadAgent(agents, orgID, domain.AgentStatusVerified)
+	_, err := service.CreateBinding(ctx, orgID, payments.ID, userID, "spiffe://prod.example.org/ns/payments/sa/*")
+	require.NoError(t, err)
+
+	svid := newTestWorkloadSVID(t, workloadCA, "/ns/payments/sa/worker")
+	agent, id, err := service.AuthenticateX509SVID(ctx, svid, nil)
+	require.NoError(t, err)
+	assert.Equal(t, payments.ID, agent.ID)
+	assert.Equal(t, "spiffe://prod.example.org/ns/payments/sa/worker", id.String())
+
+	// Workloads without a matching binding are rejected
+	_, _, err = service.AuthenticateX509SVID(ctx, newTestWorkloadSVID(t, workloadCA, "/ns/billing/sa/worker"), nil)
+	assert.ErrorIs(t, err, ErrSVIDRejected)
+
+	// A SPIFFE ID bound to several agents needs X-Agent-ID to pick one
+	refunds := createTestWorkloadAgent(agents, orgID, domain.AgentStatusVerified)
+	_, err = service.CreateBinding(ctx, orgID, refunds.ID, userID, "spiffe://prod.example.org/ns/payments/sa/worker")
+	require.NoError(t, err)
+	_, _, err = service.AuthenticateX509SVID(ctx, svid, nil)
+	assert.ErrorIs(t, err, ErrSVIDRejected)
+	agent, _, err = service.AuthenticateX509SVID(ctx, svid, &refunds.ID)
+	require.NoError(t, err)
+	assert.Equal(t, refunds.ID, agent.ID)
+
+	// ...and the hint must name a bound agent
+	other := createTestWorkloadAgent(agents, orgID, domain.AgentStatusVerified)
+	_, _, err = service.AuthenticateX509SVID(ctx, svid, &other.ID)
+	assert.ErrorIs(t, err, ErrSVIDRejected)
+
+	// Suspended agents cannot authenticate
+	refunds.Status = domain.AgentStatusSuspended
+	_, _, err = service.AuthenticateX509SVID(ctx, svid, &refunds.ID)
+	assert.ErrorIs(t, err, ErrSVIDRejected)
+}
+
+func TestWorkloadIdentityService_RejectsBindingsWithoutTrustBundle(t *testing.T) {
+	service, agents, _ := createTestWorkloadIdentityService(t)
+	orgID := uuid.New()
+	ctx := context.Background()
+	agent := createTestWorkloadAgent(agents, orgID, domain.AgentStatusVerified)
+
+	_, err := service.CreateBinding(ctx, orgID, agent.ID, uuid.New(), "spiffe://untrusted.example.org/ns/payments/sa/*")
+	assert.ErrorIs(t, err, ErrInvalidSPIFFEBinding)
+	_, err = service.CreateBinding(ctx, orgID, agent.ID, uuid.New(), "spiffe://*.example.org/ns/payments")
+	assert.ErrorIs(t, err, ErrInvalidSPIFFEBinding)
+	_, err = service.CreateBinding(ctx, uuid.New(), agent.ID, uuid.New(), "spiffe://prod.example.org/ns/payments")
+	assert.EqualError(t, err, "agent not found")
+}
+
+func TestWorkloadIdentityService_KeepsBindingsWithinOneOrganization(t *testing.T) {
+	service, agents, workloadCA := createTestWorkloadIdentityService(t)
+	orgID := uuid.New()
+	ctx := context.Background()
+	payments := createTestWorkloadAgent(agents, orgID, domain.AgentStatusVerified)
+	_, err := service.CreateBinding(ctx, orgID, payments.ID, uuid.New(), "spiffe://prod.example.org/ns/payments/sa/*")
+	require.NoError(t, err)
+
+	// Another organization cannot bind an overlapping pattern, which would make the first
+	// organization's workloads ambiguous or let them authenticate as its agent
+	otherOrg := uuid.New()
+	intruder := &domain.Agent{ID: uuid.New(), OrganizationID: otherOrg, Status: domain.AgentStatusVerified}
+	agents.agents[intruder.ID] = intruder
+	for _, pattern := range []string{
+		"spiffe://prod.example.org/ns/*/sa/*",
+		"spiffe://prod.example.org/ns/payments/sa/worker",
+	} {
+		_, err = service.CreateBinding(ctx, otherOrg, intruder.ID, uuid.New(), pattern)
+		assert.ErrorIs(t, err, ErrInvalidSPIFFEBinding, pattern)
+	}
+	_, err = service.CreateBinding(ctx, otherOrg, intruder.ID, uuid.New(), "spiffe://prod.example.org/ns/billing/sa/*")
+	require.NoError(t, err)
+
+	agent, _, err := service.AuthenticateX509SVID(ctx, newTestWorkloadSVID(t, workloadCA, "/ns/payments/sa/worker"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, payments.ID, agent.ID)
+
+	// Overlapping patterns within one organization are fine
+	refunds := createTestWorkloadAgent(agents, orgID, domain.AgentStatusVerified)
+	_, err = service.CreateBinding(ctx, orgID, refunds.ID, uuid.New(), "spiffe://prod.example.org/ns/pay*/sa/worker")
+	require.NoError(t, err)
+
+	// Nobody can bind the SVIDs AIM's CA issues
+	ca, err := spiffe.NewEphemeralCA("aim.example.com", 24*time.Hour)
+	require.NoError(t, err)
+	service.UseCA(ca, time.Hour)
+	_, err = service.CreateBinding(ctx, otherOrg, intruder.ID, uuid.New(), "spiffe://aim.example.com/agents/*")
+	assert.ErrorIs(t, err, ErrInvalidSPIFFEBinding)
+	_, err = service.CreateBinding(ctx, otherOrg, intruder.ID, uuid.New(), "spiffe://aim.example.com/*/"+payments.ID.String())
+	assert.ErrorIs(t, err, ErrInvalidSPIFFEBinding)
+}
+
+func TestWorkloadIdentityService_IssuesAgentCertificates(t *testing.T) {
+	service, agents, _ := createTestWorkloadIdentityService(t)
+	orgID := uuid.New()
+	ctx := context.Background()
+	agent := createTestWorkloadAgent(agents, orgID, domain.AgentStatusVerified)
+
+	_, err := service.IssueCertificate(ctx, orgID, agent.ID, newTestCSRPEM(t))
+	assert.ErrorIs(t, err, ErrAgentCANotEnabled)
+
+	ca, err := spiffe.NewEphemeralCA("aim.example.com", 24*time.Hour)
+	require.NoError(t, err)
+	service.UseCA(ca, time.Hour)
+
+	certificate, err := service.IssueCertificate(ctx, orgID, agent.ID, newTestCSRPEM(t))
+	require.NoError(t, err)
+	assert.Equal(t, "spiffe://aim.example.com/agents/"+agent.ID.String(), certificate.SPIFFEID)
+	assert.Equal(t, "https://aim.example.com/api/v1/agents/"+agent.ID.String()+"/certificate", agent.CertificateURL)
+	assert.Equal(t, 1, agents.updated)
+
+	// The issued certificate authenticates the agent without a binding
+	chain, err := spiffe.ParseCertificatesPEM([]byte(certificate.CertificatePEM))
+	require.NoError(t, err)
+	authenticated, _, err := service.AuthenticateX509SVID(ctx, chain, nil)
+	require.NoError(t, err)
+	assert.Equal(t, agent.ID, authenticated.ID)
+
+	current, err := service.CurrentCertificate(ctx, orgID, agent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, certificate.SerialNumber, current.SerialNumber)
+	_, err = service.CurrentCertificate(ctx, uuid.New(), agent.ID)
+	assert.ErrorIs(t, err, ErrAgentCertificateNotFound)
+
+	// Renewal keeps the certificate URL; unverified agents and bad CSRs get nothing
+	_, err = service.IssueCertificate(ctx, orgID, agent.ID, newTestCSRPEM(t))
+	require.NoError(t, err)
+	assert.Equal(t, 1, agents.updated)
+	_, err = service.IssueCertificate(ctx, orgID, agent.ID, "not a CSR")
+	assert.ErrorIs(t, err, ErrInvalidCSR)
+	pending := createTestWorkloadAgent(agents, orgID, domain.AgentStatusPending)
+	_, err = service.IssueCertificate(ctx, orgID, pending.ID, newTestCSRPEM(t))
+	assert.ErrorIs(t, err, ErrAgentCertificateUnavailable)
+}
+
+func TestWorkloadIdentityService_ForwardedCertificatesOnlyFromTrustedProxies(t *testing.T) {
+	service, _, _ := createTestWorkloadIdentityService(t)
+
+	assert.Empty(t, service.ForwardedCertificateHeader(net.ParseIP("10.0.0.5")))
+	assert.Error(t, service.TrustForwardedCertificates("X-Client-Cert", nil))
+	assert.Error(t, service.TrustForwardedCertificates("X-Client-Cert", []string{"not-an-ip"}))
+
+	require.NoError(t, service.TrustForwardedCertificates("X-Client-Cert", []string{"10.0.0.0/24", "192.168.1.7"}))
+	assert.Equal(t, "X-Client-Cert", service.ForwardedCertificateHeader(net.ParseIP("10.0.0.5")))
+	assert.Equal(t, "X-Client-Cert", service.ForwardedCertificateHeader(net.ParseIP("192.168.1.7")))
+	assert.Empty(t, service.ForwardedCertificateHeader(net.ParseIP("192.168.1.8")))
+	assert.Empty(t, service.ForwardedCertificateHeader(net.ParseIP("203.0.113.9")))
+}
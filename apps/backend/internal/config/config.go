@@ -4,16 +4,24 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	OAuth    OAuthConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	OAuth     OAuthConfig
+	MCPHealth MCPHealthConfig
+	Scheduler SchedulerConfig
+	Audit     AuditConfig
+	Approval  ApprovalConfig
+	AgentToken AgentTokenConfig
+	SPIFFE     SPIFFEConfig
+	Outbound   OutboundConfig
 }
 
 // ServerConfig holds server configuration
@@ -46,9 +54,62 @@ type RedisConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret          string
-	AccessTokenTTL  time.Duration
-	RefreshTokenTTL time.Duration
+	Secret              string
+	AccessTokenTTL      time.Duration
+	RefreshTokenTTL     time.Duration
+	SigningAlgorithm    string        // EdDSA or ES256; user tokens are signed with rotating asymmetric keys
+	KeyRotationInterval time.Duration // How long a signing key stays active before the next one takes over
+	AcceptLegacyHMAC    bool          // Keep accepting HS256 tokens issued before asymmetric signing was enabled; off by default
+}
+
+// MCPHealthConfig holds scheduled MCP server re-verification configuration
+type MCPHealthConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+// SchedulerConfig holds background maintenance job configuration
+type SchedulerConfig struct {
+	Enabled bool
+}
+
+// AuditConfig holds audit log hash chain checkpoint signing configuration
+type AuditConfig struct {
+	SigningKey        string   // Base64 Ed25519 seed or private key; ephemeral when empty (development only)
+	TrustedPublicKeys []string // Base64 Ed25519 public keys of previous signing keys (after rotation)
+}
+
+// ApprovalConfig holds human-in-the-loop verification approval configuration
+type ApprovalConfig struct {
+	Timeout time.Duration // How long a high-risk action waits for an approver before it times out
+}
+
+// AgentTokenConfig holds configuration of the OAuth 2.0 authorization server that issues
+// access tokens to agents
+type AgentTokenConfig struct {
+	Issuer            string        // Public base URL of this server; the "iss" of agent access tokens
+	SigningKey        string        // Base64 Ed25519 seed or private key; ephemeral when empty (development only)
+	TrustedPublicKeys []string      // Base64 Ed25519 public keys of previous signing keys, still published in the JWKS
+	TTL               time.Duration // Lifetime of issued access tokens
+}
+
+// SPIFFEConfig holds configuration of SPIFFE workload identity for agents
+type SPIFFEConfig struct {
+	TrustBundles     []string      // "<trust domain>=<bundle file>" entries; PEM CA certificates or a SPIFFE JWKS bundle
+	JWTAudience      string        // Audience JWT-SVIDs must be issued for; the issuer URL when empty
+	ClientCertHeader string        // Header a TLS-terminating proxy forwards the URL-encoded client certificate in
+	TrustedProxies   []string      // CIDRs of the proxies ClientCertHeader is accepted from; none by default
+	CAEnabled        bool          // Issue short-lived X.509-SVIDs to agents
+	CATrustDomain    string        // Trust domain of issued SVIDs; the issuer's host when empty
+	CACertFile       string        // PEM CA certificate; ephemeral CA when empty (development only)
+	CAKeyFile        string        // PEM CA private key
+	CertificateTTL   time.Duration // Lifetime of issued agent certificates
+}
+
+// OutboundConfig holds configuration of outbound connections to customer-configured endpoints
+// (SIEM sinks, approval callbacks)
+type OutboundConfig struct {
+	AllowedNetworks []string // CIDRs of internal networks endpoints may point at; loopback, private and link-local addresses are blocked otherwise
 }
 
 // OAuthConfig holds OAuth provider configurations
@@ -99,9 +160,12 @@ func Load() (*Config, error) {
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
 	JWT: JWTConfig{
-		Secret:          getEnvRequired("JWT_SECRET"),
-		AccessTokenTTL:  getEnvAsDuration("JWT_ACCESS_TTL", 24*time.Hour),
-		RefreshTokenTTL: getEnvAsDuration("JWT_REFRESH_TTL", 7*24*time.Hour),
+		Secret:              getEnvRequired("JWT_SECRET"),
+		AccessTokenTTL:      getEnvAsDuration("JWT_ACCESS_TTL", 24*time.Hour),
+		RefreshTokenTTL:     getEnvAsDuration("JWT_REFRESH_TTL", 7*24*time.Hour),
+		SigningAlgorithm:    getEnv("JWT_SIGNING_ALGORITHM", "EdDSA"),
+		KeyRotationInterval: getEnvAsDuration("JWT_KEY_ROTATION_INTERVAL", 30*24*time.Hour),
+		AcceptLegacyHMAC:    getEnvAsBool("JWT_ACCEPT_LEGACY_HMAC", false),
 	},
 		OAuth: OAuthConfig{
 			Google: OAuthProvider{
@@ -121,6 +185,40 @@ func Load() (*Config, error) {
 				RedirectURL:  getEnv("OKTA_REDIRECT_URL", "http://localhost:8080/api/v1/auth/callback/okta"),
 			},
 		},
+		MCPHealth: MCPHealthConfig{
+			Enabled:  getEnvAsBool("MCP_HEALTH_CHECK_ENABLED", true),
+			Interval: getEnvAsDuration("MCP_HEALTH_CHECK_INTERVAL", 15*time.Minute),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled: getEnvAsBool("SCHEDULER_ENABLED", true),
+		},
+		Audit: AuditConfig{
+			SigningKey:        getEnv("AUDIT_SIGNING_KEY", ""),
+			TrustedPublicKeys: getEnvAsSlice("AUDIT_TRUSTED_PUBLIC_KEYS"),
+		},
+		Approval: ApprovalConfig{
+			Timeout: getEnvAsDuration("VERIFICATION_APPROVAL_TIMEOUT", 15*time.Minute),
+		},
+		AgentToken: AgentTokenConfig{
+			Issuer:            strings.TrimSuffix(getEnv("OAUTH_ISSUER", "http://localhost:8080"), "/"),
+			SigningKey:        getEnv("AGENT_TOKEN_SIGNING_KEY", ""),
+			TrustedPublicKeys: getEnvAsSlice("AGENT_TOKEN_TRUSTED_PUBLIC_KEYS"),
+			TTL:               getEnvAsDuration("AGENT_TOKEN_TTL", 15*time.Minute),
+		},
+		SPIFFE: SPIFFEConfig{
+			TrustBundles:     getEnvAsSlice("SPIFFE_TRUST_BUNDLES"),
+			JWTAudience:      getEnv("SPIFFE_JWT_AUDIENCE", ""),
+			ClientCertHeader: getEnv("SPIFFE_CLIENT_CERT_HEADER", ""),
+			TrustedProxies:   getEnvAsSlice("SPIFFE_TRUSTED_PROXIES"),
+			CAEnabled:        getEnvAsBool("AGENT_CA_ENABLED", false),
+			CATrustDomain:    getEnv("AGENT_CA_TRUST_DOMAIN", ""),
+			CACertFile:       getEnv("AGENT_CA_CERT_FILE", ""),
+			CAKeyFile:        getEnv("AGENT_CA_KEY_FILE", ""),
+			CertificateTTL:   getEnvAsDuration("AGENT_CA_CERT_TTL", time.Hour),
+		},
+		Outbound: OutboundConfig{
+			AllowedNetworks: getEnvAsSlice("OUTBOUND_ALLOWED_NETWORKS"),
+		},
 	}
 
 	// Validate required fields
@@ -141,6 +239,24 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("JWT_SECRET must be at least 32 characters")
 	}
 
+	if c.JWT.SigningAlgorithm != "EdDSA" && c.JWT.SigningAlgorithm != "ES256" {
+		return fmt.Errorf("JWT_SIGNING_ALGORITHM must be EdDSA or ES256")
+	}
+
+	// Ephemeral keys change on every restart and differ between replicas, so anything they
+	// signed stops verifying; they are only generated in development
+	if c.Server.Environment != "development" {
+		if c.Audit.SigningKey == "" {
+			return fmt.Errorf("AUDIT_SIGNING_KEY is required outside development")
+		}
+		if c.AgentToken.SigningKey == "" {
+			return fmt.Errorf("AGENT_TOKEN_SIGNING_KEY is required outside development")
+		}
+		if c.SPIFFE.CAEnabled && (c.SPIFFE.CACertFile == "" || c.SPIFFE.CAKeyFile == "") {
+			return fmt.Errorf("AGENT_CA_CERT_FILE and AGENT_CA_KEY_FILE are required outside development when AGENT_CA_ENABLED is set")
+		}
+	}
+
 	// OAuth providers are now optional since we support email/password authentication
 	// Validation removed - OAuth configuration is checked at runtime when needed
 
@@ -180,6 +296,29 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	return value
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsSlice splits a comma-separated environment variable, skipping empty items
+func getEnvAsSlice(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // getEnvRequired gets environment variable and panics if not set
 func getEnvRequired(key string) string {
 	value := os.Getenv(key)
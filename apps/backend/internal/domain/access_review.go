@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessReviewCampaignStatus represents the lifecycle of an access review campaign
+type AccessReviewCampaignStatus string
+
+const (
+	AccessReviewCampaignActive    AccessReviewCampaignStatus = "active"
+	AccessReviewCampaignCompleted AccessReviewCampaignStatus = "completed"
+	AccessReviewCampaignCancelled AccessReviewCampaignStatus = "cancelled"
+)
+
+// AccessReviewItemType is the kind of access being reviewed
+type AccessReviewItemType string
+
+const (
+	AccessReviewItemUserRole        AccessReviewItemType = "user_role"
+	AccessReviewItemCapabilityGrant AccessReviewItemType = "capability_grant"
+	AccessReviewItemAPIKey          AccessReviewItemType = "api_key"
+)
+
+// AccessReviewDecision is a reviewer's attestation for one item
+type AccessReviewDecision string
+
+const (
+	AccessReviewDecisionPending   AccessReviewDecision = "pending"
+	AccessReviewDecisionCertified AccessReviewDecision = "certified"
+	AccessReviewDecisionRevoked   AccessReviewDecision = "revoked"
+)
+
+// AccessReviewCampaign is a periodic review in which reviewers certify or revoke every
+// user role, agent capability grant and API key in scope before a due date
+type AccessReviewCampaign struct {
+	ID                 uuid.UUID                  `json:"id"`
+	OrganizationID     uuid.UUID                  `json:"organization_id"`
+	Name               string                     `json:"name"`
+	Description        string                     `json:"description,omitempty"`
+	Scope              []AccessReviewItemType     `json:"scope"`
+	DefaultReviewerID  uuid.UUID                  `json:"default_reviewer_id"`
+	AssignAgentOwners  bool                       `json:"assign_agent_owners"`   // Agent owners review their agents' grants and keys
+	AutoRevokeOnExpiry bool                       `json:"auto_revoke_on_expiry"` // Items still pending at the due date are revoked
+	ReminderDaysBefore int                        `json:"reminder_days_before"`  // Reviewers are reminded daily from this many days before the due date
+	DueAt              time.Time                  `json:"due_at"`
+	Status             AccessReviewCampaignStatus `json:"status"`
+	CreatedBy          uuid.UUID                  `json:"created_by"`
+	CreatedAt          time.Time                  `json:"created_at"`
+	CompletedAt        *time.Time                 `json:"completed_at,omitempty"`
+	TotalItems         int                        `json:"total_items"`
+	PendingItems       int                        `json:"pending_items"`
+	CertifiedItems     int                        `json:"certified_items"`
+	RevokedItems       int                        `json:"revoked_items"`
+}
+
+// AccessReviewItem is one piece of access a reviewer must certify or revoke
+type AccessReviewItem struct {
+	ID              uuid.UUID            `json:"id"`
+	CampaignID      uuid.UUID            `json:"campaign_id"`
+	OrganizationID  uuid.UUID            `json:"organization_id"`
+	ItemType        AccessReviewItemType `json:"item_type"`
+	ResourceID      uuid.UUID            `json:"resource_id"` // User, capability or API key ID
+	AgentID         *uuid.UUID           `json:"agent_id,omitempty"`
+	Subject         string               `json:"subject"` // User email or agent name
+	Access          string               `json:"access"`  // Role, capability type or API key name
+	ReviewerID      uuid.UUID            `json:"reviewer_id"`
+	Decision        AccessReviewDecision `json:"decision"`
+	DecidedBy       *uuid.UUID           `json:"decided_by,omitempty"` // Nil when revoked automatically at the due date
+	DecidedAt       *time.Time           `json:"decided_at,omitempty"`
+	Justification   string               `json:"justification,omitempty"`
+	RevocationError string               `json:"revocation_error,omitempty"`
+	LastRemindedAt  *time.Time           `json:"last_reminded_at,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+}
+
+// AccessReviewItemFilter narrows the items of a campaign
+type AccessReviewItemFilter struct {
+	CampaignID *uuid.UUID
+	ReviewerID *uuid.UUID
+	Decision   *AccessReviewDecision
+}
+
+// AccessReviewEvidence is the immutable record of a completed campaign. Snapshot holds the
+// campaign and all decisions as canonical JSON; SHA256 and Signature cover those bytes.
+type AccessReviewEvidence struct {
+	CampaignID     uuid.UUID       `json:"campaign_id"`
+	OrganizationID uuid.UUID       `json:"organization_id"`
+	Snapshot       json.RawMessage `json:"snapshot"`
+	SHA256         string          `json:"sha256"`
+	KeyID          string          `json:"key_id,omitempty"`
+	Signature      string          `json:"signature,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// AccessReviewRepository defines the interface for access review persistence
+type AccessReviewRepository interface {
+	// CreateCampaign stores a campaign together with its items
+	CreateCampaign(campaign *AccessReviewCampaign, items []*AccessReviewItem) error
+	GetCampaign(id uuid.UUID) (*AccessReviewCampaign, error)
+	ListCampaigns(orgID uuid.UUID, status *AccessReviewCampaignStatus, limit, offset int) ([]*AccessReviewCampaign, int, error)
+	// ListActiveCampaigns returns active campaigns of every organization
+	ListActiveCampaigns() ([]*AccessReviewCampaign, error)
+	UpdateCampaignStatus(id uuid.UUID, status AccessReviewCampaignStatus, completedAt *time.Time) error
+
+	GetItem(id uuid.UUID) (*AccessReviewItem, error)
+	ListItems(filter AccessReviewItemFilter) ([]*AccessReviewItem, error)
+	// DecideItem records a decision on a pending item; it fails if the item was already decided
+	DecideItem(item *AccessReviewItem) error
+	MarkItemsReminded(ids []uuid.UUID, at time.Time) error
+
+	CreateEvidence(evidence *AccessReviewEvidence) error
+	GetEvidence(campaignID uuid.UUID) (*AccessReviewEvidence, error)
+}
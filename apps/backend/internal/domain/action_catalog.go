@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ActionRiskTier classifies how much damage an agent action can do
+type ActionRiskTier string
+
+const (
+	ActionRiskLow    ActionRiskTier = "low"    // Read-only
+	ActionRiskMedium ActionRiskTier = "medium" // Modifications
+	ActionRiskHigh   ActionRiskTier = "high"   // Destructive or privileged
+)
+
+// IsValid reports whether the tier is known
+func (t ActionRiskTier) IsValid() bool {
+	switch t {
+	case ActionRiskLow, ActionRiskMedium, ActionRiskHigh:
+		return true
+	}
+	return false
+}
+
+// ActionCatalogEntry describes the risk of an action agents may request verification for.
+// Verification looks entries up by action name or by one of their aliases (MCP tool names).
+type ActionCatalogEntry struct {
+	ID                 uuid.UUID      `json:"id"`
+	OrganizationID     uuid.UUID      `json:"organization_id"`
+	Action             string         `json:"action"`
+	Description        string         `json:"description,omitempty"`
+	RiskTier           ActionRiskTier `json:"risk_tier"`
+	RequiredTrust      float64        `json:"required_trust"`                // Minimum effective trust score (0-1)
+	TrustMultiplier    float64        `json:"trust_multiplier"`              // Applied to the agent's trust score for this action
+	RequiredCapability string         `json:"required_capability,omitempty"` // Granted capability that authorizes the action
+	RequiresApproval   bool           `json:"requires_approval"`             // Park for human approval once the trust check passes
+	Aliases            []string       `json:"aliases"`
+	CreatedBy          *uuid.UUID     `json:"created_by,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+// Matches reports whether name is the entry's action or one of its aliases
+func (e *ActionCatalogEntry) Matches(name string) bool {
+	if e.Action == name {
+		return true
+	}
+	for _, alias := range e.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveTrust is the agent's trust score adjusted for the risk of this action
+func (e *ActionCatalogEntry) EffectiveTrust(agentTrust float64) float64 {
+	return agentTrust * e.TrustMultiplier
+}
+
+// ActionCatalogRepository persists each organization's action catalog
+type ActionCatalogRepository interface {
+	Create(entry *ActionCatalogEntry) error
+	Update(entry *ActionCatalogEntry) error
+	Delete(orgID, id uuid.UUID) error
+	GetByID(orgID, id uuid.UUID) (*ActionCatalogEntry, error)
+	// GetByName returns the entry whose action or aliases contain name, or nil when there is none
+	GetByName(orgID uuid.UUID, name string) (*ActionCatalogEntry, error)
+	List(orgID uuid.UUID) ([]*ActionCatalogEntry, error)
+	// Seed inserts entries whose action the organization does not have yet and returns how many were added
+	Seed(orgID uuid.UUID, entries []*ActionCatalogEntry) (int, error)
+}
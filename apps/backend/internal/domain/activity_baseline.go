@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ActivityDetector identifies one of the checks the anomaly detector runs against an agent's baseline
+type ActivityDetector string
+
+const (
+	ActivityDetectorRateSpike    ActivityDetector = "rate_spike"     // Volume in the sliding window far above the hourly baseline
+	ActivityDetectorOffHours     ActivityDetector = "off_hours"      // Activity in an hour of the day the agent is rarely active
+	ActivityDetectorNewAction    ActivityDetector = "new_action"     // Action type never seen for this agent
+	ActivityDetectorNewResource  ActivityDetector = "new_resource"   // Resource never seen for this agent
+	ActivityDetectorNewMCPServer ActivityDetector = "new_mcp_server" // MCP server never seen for this agent
+	ActivityDetectorNewSourceIP  ActivityDetector = "new_source_ip"  // Source IP never seen for this agent
+)
+
+// ActivityObservation is one verify-action or verification request observed for an agent
+type ActivityObservation struct {
+	OrganizationID uuid.UUID
+	AgentID        uuid.UUID
+	AgentName      string
+	Action         string
+	Resource       string
+	MCPServer      string
+	SourceIP       string
+	OccurredAt     time.Time
+}
+
+// AgentActivityBaseline is the learned profile of an agent's normal activity. Hourly volume is an
+// exponentially weighted moving average of completed hours; the categorical profiles count how
+// often each action, resource, MCP server and source IP was seen.
+type AgentActivityBaseline struct {
+	AgentID          uuid.UUID        `json:"agent_id"`
+	OrganizationID   uuid.UUID        `json:"organization_id"`
+	FirstSeenAt      time.Time        `json:"first_seen_at"`
+	LastSeenAt       time.Time        `json:"last_seen_at"`
+	TotalEvents      int64            `json:"total_events"`
+	HourlyMean       float64          `json:"hourly_mean"`
+	CurrentHour      time.Time        `json:"current_hour"` // Start of the hour being counted
+	CurrentHourCount int64            `json:"current_hour_count"`
+	HourOfDay        [24]int64        `json:"hour_of_day"` // UTC
+	Actions          map[string]int64 `json:"actions"`
+	Resources        map[string]int64 `json:"resources"`
+	MCPServers       map[string]int64 `json:"mcp_servers"`
+	SourceIPs        map[string]int64 `json:"source_ips"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// ActivityAnomalyResult is the outcome of checking one observation against the agent's baseline
+type ActivityAnomalyResult struct {
+	Anomalies  []*Anomaly `json:"anomalies"`
+	PolicyName string     `json:"policy_name,omitempty"`
+	Alerted    bool       `json:"alerted"`
+	Blocked    bool       `json:"blocked"`
+	Reason     string     `json:"reason,omitempty"`
+}
+
+// AgentActivityBaselineRepository persists learned activity baselines
+type AgentActivityBaselineRepository interface {
+	// GetByAgent returns nil when the agent has no baseline yet
+	GetByAgent(agentID uuid.UUID) (*AgentActivityBaseline, error)
+	Upsert(baseline *AgentActivityBaseline) error
+	Delete(agentID uuid.UUID) error
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// A2AProtocolVersion is the A2A protocol version of the Agent Cards AIM issues
+const A2AProtocolVersion = "0.3.0"
+
+// AgentCardIdentityExtension is the URI of the Agent Card extension that carries the agent's
+// AIM identity
+const AgentCardIdentityExtension = "https://opena2a.org/a2a/extensions/aim-identity/v1"
+
+// AgentCardRecord is a published Agent Card: what AIM cannot derive from the agent itself.
+// Its ID is named in every signature of cards issued from it.
+type AgentCardRecord struct {
+	ID                 uuid.UUID    `json:"id"`
+	AgentID            uuid.UUID    `json:"agentId"`
+	OrganizationID     uuid.UUID    `json:"organizationId"`
+	URL                string       `json:"url"`
+	Skills             []AgentSkill `json:"skills"`
+	Streaming          bool         `json:"streaming"`
+	PushNotifications  bool         `json:"pushNotifications"`
+	DefaultInputModes  []string     `json:"defaultInputModes"`
+	DefaultOutputModes []string     `json:"defaultOutputModes"`
+	PublishedBy        *uuid.UUID   `json:"publishedBy,omitempty"`
+	CreatedAt          time.Time    `json:"createdAt"`
+	UpdatedAt          time.Time    `json:"updatedAt"`
+	RevokedAt          *time.Time   `json:"revokedAt,omitempty"`
+	RevokeReason       *string      `json:"revokeReason,omitempty"`
+}
+
+// AgentCard is an A2A Agent Card
+type AgentCard struct {
+	ProtocolVersion    string                `json:"protocolVersion"`
+	Name               string                `json:"name"`
+	Description        string                `json:"description"`
+	URL                string                `json:"url"`
+	Provider           *AgentProvider        `json:"provider,omitempty"`
+	Version            string                `json:"version"`
+	DocumentationURL   string                `json:"documentationUrl,omitempty"`
+	Capabilities       AgentCardCapabilities `json:"capabilities"`
+	DefaultInputModes  []string              `json:"defaultInputModes"`
+	DefaultOutputModes []string              `json:"defaultOutputModes"`
+	Skills             []AgentSkill          `json:"skills"`
+	Signatures         []AgentCardSignature  `json:"signatures,omitempty"`
+}
+
+// AgentProvider is the organization that operates an agent
+type AgentProvider struct {
+	Organization string `json:"organization"`
+	URL          string `json:"url"`
+}
+
+// AgentCardCapabilities are the optional A2A features an agent supports
+type AgentCardCapabilities struct {
+	Streaming         bool             `json:"streaming,omitempty"`
+	PushNotifications bool             `json:"pushNotifications,omitempty"`
+	Extensions        []AgentExtension `json:"extensions,omitempty"`
+}
+
+// AgentExtension declares an A2A protocol extension
+type AgentExtension struct {
+	URI         string                 `json:"uri"`
+	Description string                 `json:"description,omitempty"`
+	Required    bool                   `json:"required,omitempty"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+}
+
+// AgentSkill is a unit of work an agent can perform
+type AgentSkill struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Examples    []string `json:"examples,omitempty"`
+	InputModes  []string `json:"inputModes,omitempty"`
+	OutputModes []string `json:"outputModes,omitempty"`
+}
+
+// AgentCardSignature is a JWS (RFC 7515) over the card without its signatures, canonicalized
+// with JCS (RFC 8785). The payload is detached.
+type AgentCardSignature struct {
+	Protected string                 `json:"protected"`
+	Signature string                 `json:"signature"`
+	Header    map[string]interface{} `json:"header,omitempty"`
+}
+
+// AgentCardVerification is the result of verifying an Agent Card
+type AgentCardVerification struct {
+	Valid        bool        `json:"valid"`
+	Errors       []string    `json:"errors,omitempty"`
+	Issuer       string      `json:"issuer,omitempty"`
+	CardID       *uuid.UUID  `json:"cardId,omitempty"`
+	AgentID      *uuid.UUID  `json:"agentId,omitempty"`
+	AgentName    string      `json:"agentName,omitempty"`
+	AgentStatus  AgentStatus `json:"agentStatus,omitempty"`
+	Revoked      bool        `json:"revoked"`
+	TrustScore   *float64    `json:"trustScore,omitempty"` // Current, not as signed
+	IssuedAt     *time.Time  `json:"issuedAt,omitempty"`
+	ExpiresAt    *time.Time  `json:"expiresAt,omitempty"`
+	Capabilities []string    `json:"capabilities,omitempty"` // Currently granted
+}
+
+// AgentCardRepository defines the interface for Agent Card persistence
+type AgentCardRepository interface {
+	// Create stores a newly published card
+	Create(record *AgentCardRecord) error
+
+	// Update replaces the declared fields of a published card
+	Update(record *AgentCardRecord) error
+
+	// GetByID retrieves a card by ID, or nil if unknown
+	GetByID(id uuid.UUID) (*AgentCardRecord, error)
+
+	// GetActiveByAgent retrieves an agent's published card, or nil if it has none
+	GetActiveByAgent(agentID uuid.UUID) (*AgentCardRecord, error)
+
+	// Revoke unpublishes a card
+	Revoke(id uuid.UUID, reason string, at time.Time) error
+}
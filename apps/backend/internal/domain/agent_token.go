@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuth 2.0 grant, client assertion and token types accepted for agent access tokens
+const (
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+	ClientAssertionTypeJWT     = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	TokenTypeAccessToken       = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeJWT               = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// AgentAccessToken records an access token issued to an agent so it can be introspected and revoked
+type AgentAccessToken struct {
+	ID             uuid.UUID  `json:"jti"`
+	AgentID        uuid.UUID  `json:"agent_id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	GrantType      string     `json:"grant_type"`
+	Subject        string     `json:"sub"`                     // Agent ID, or the user ID of delegated tokens
+	ActorUserID    *uuid.UUID `json:"actor_user_id,omitempty"` // User the agent acts on behalf of (token exchange)
+	Scope          []string   `json:"scope"`
+	Audience       []string   `json:"aud"`
+	IssuedAt       time.Time  `json:"iat"`
+	ExpiresAt      time.Time  `json:"exp"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IsActive reports whether the token is unexpired and not revoked
+func (t *AgentAccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// TokenIntrospection is an RFC 7662 introspection response
+type TokenIntrospection struct {
+	Active         bool                   `json:"active"`
+	Scope          string                 `json:"scope,omitempty"`
+	ClientID       string                 `json:"client_id,omitempty"`
+	TokenType      string                 `json:"token_type,omitempty"`
+	Subject        string                 `json:"sub,omitempty"`
+	Audience       []string               `json:"aud,omitempty"`
+	Issuer         string                 `json:"iss,omitempty"`
+	ID             string                 `json:"jti,omitempty"`
+	IssuedAt       int64                  `json:"iat,omitempty"`
+	NotBefore      int64                  `json:"nbf,omitempty"`
+	ExpiresAt      int64                  `json:"exp,omitempty"`
+	AgentID        string                 `json:"agent_id,omitempty"`
+	OrganizationID string                 `json:"organization_id,omitempty"`
+	Capabilities   []string               `json:"capabilities,omitempty"`
+	TrustScore     *float64               `json:"trust_score,omitempty"`
+	Actor          map[string]interface{} `json:"act,omitempty"`
+}
+
+// JSONWebKey is a public key published in a JWKS
+type JSONWebKey struct {
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv,omitempty"`
+	X       string `json:"x,omitempty"`
+	Y       string `json:"y,omitempty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	Alg     string `json:"alg"`
+}
+
+// JSONWebKeySet is a JWKS document
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// AgentTokenRepository persists issued agent access tokens and used client assertion IDs
+type AgentTokenRepository interface {
+	Create(token *AgentAccessToken) error
+	GetByID(id uuid.UUID) (*AgentAccessToken, error) // Returns nil, nil when the token is unknown
+	Revoke(id uuid.UUID, at time.Time) error
+	// RevokeByAgent revokes every active token of an agent and returns how many were revoked
+	RevokeByAgent(agentID uuid.UUID, at time.Time) (int64, error)
+	// RecordAssertion stores a client assertion ID; false means it was already used (replay)
+	RecordAssertion(agentID uuid.UUID, jti string, expiresAt time.Time) (bool, error)
+	DeleteExpired(before time.Time) (int64, error)
+}
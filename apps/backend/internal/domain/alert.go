@@ -17,6 +17,10 @@ const (
 	AlertSecurityBreach       AlertType = "security_breach"
 	AlertUnusualActivity      AlertType = "unusual_activity"
 	AlertTypeConfigurationDrift AlertType = "configuration_drift"
+	AlertMCPServerUnhealthy   AlertType = "mcp_server_unhealthy"
+	AlertDataExfiltration     AlertType = "data_exfiltration"
+	AlertQuotaWarning         AlertType = "quota_warning"
+	AlertRefreshTokenReuse    AlertType = "refresh_token_reuse"
 )
 
 // AlertSeverity represents alert severity level
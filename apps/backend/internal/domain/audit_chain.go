@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditChainGenesisHash is the prev_hash of the first record in an organization's audit chain
+const AuditChainGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// AuditChainHead is the latest position of an organization's audit chain
+type AuditChainHead struct {
+	OrganizationID        uuid.UUID `json:"organization_id"`
+	LastSequence          int64     `json:"last_sequence"`
+	LastHash              string    `json:"last_hash"`
+	PrunedThroughSequence int64     `json:"pruned_through_sequence"`       // Records up to here may have been purged by retention
+	PrunedThroughHash     string    `json:"pruned_through_hash,omitempty"` // entry_hash of the record at PrunedThroughSequence
+	ChainStartedAt        time.Time `json:"chain_started_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// AuditCheckpoint is a server-signed snapshot of an audit chain head
+type AuditCheckpoint struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	SequenceNumber int64     `json:"sequence_number"`
+	EntryHash      string    `json:"entry_hash"`
+	KeyID          string    `json:"key_id"`
+	PublicKey      string    `json:"public_key"` // Base64 Ed25519 public key
+	Signature      string    `json:"signature"`  // Base64 Ed25519 signature over SigningPayload
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SigningPayload is the exact message signed for a checkpoint
+func (c *AuditCheckpoint) SigningPayload() []byte {
+	return []byte(fmt.Sprintf("aim-audit-checkpoint:v1|%s|%d|%s|%s",
+		c.OrganizationID, c.SequenceNumber, c.EntryHash, c.CreatedAt.UTC().Format(time.RFC3339Nano)))
+}
+
+// AuditChainBreak describes the first point where an audit chain fails verification
+type AuditChainBreak struct {
+	SequenceNumber int64      `json:"sequence_number"`
+	EntryID        *uuid.UUID `json:"entry_id,omitempty"`
+	Reason         string     `json:"reason"`
+	Expected       string     `json:"expected,omitempty"`
+	Actual         string     `json:"actual,omitempty"`
+}
+
+// Audit chain break reasons
+const (
+	AuditChainBreakMissingEntry       = "missing_entry"       // Gap in sequence numbers (deleted record)
+	AuditChainBreakModifiedEntry      = "modified_entry"      // Recomputed hash differs from stored entry_hash
+	AuditChainBreakBrokenLink         = "broken_link"         // prev_hash does not match the previous entry_hash
+	AuditChainBreakTruncated          = "truncated"           // Chain ends before the head or a checkpoint
+	AuditChainBreakCheckpointMismatch = "checkpoint_mismatch" // Entry hash differs from a signed checkpoint
+	AuditChainBreakInvalidSignature   = "invalid_signature"   // Checkpoint signature does not verify
+	AuditChainBreakUntrustedKey       = "untrusted_key"       // Checkpoint signed by a key the server does not trust
+	AuditChainBreakUnchainedEntry     = "unchained_entry"     // Record inserted after the chain started without chaining
+	AuditChainBreakPruneAnchor        = "prune_anchor"        // Chain was purged at a point no trusted checkpoint covers
+)
+
+// AuditChainVerification is the result of verifying an organization's audit chain
+type AuditChainVerification struct {
+	OrganizationID      uuid.UUID        `json:"organization_id"`
+	Valid               bool             `json:"valid"`
+	EntriesVerified     int64            `json:"entries_verified"`
+	PrunedThrough       int64            `json:"pruned_through,omitempty"` // Verification started after this purged sequence
+	CheckpointsVerified int              `json:"checkpoints_verified"`
+	UntrustedKeyIDs     []string         `json:"untrusted_key_ids,omitempty"`
+	HeadSequence        int64            `json:"head_sequence"`
+	LastCheckpoint      *AuditCheckpoint `json:"last_checkpoint,omitempty"`
+	FirstBrokenLink     *AuditChainBreak `json:"first_broken_link,omitempty"`
+	VerifiedAt          time.Time        `json:"verified_at"`
+}
+
+// AuditChainRepository provides read access to audit chains and their checkpoints
+type AuditChainRepository interface {
+	GetChainHead(orgID uuid.UUID) (*AuditChainHead, error)
+	ListChainHeads() ([]*AuditChainHead, error)
+	GetChainedEntries(orgID uuid.UUID, afterSequence int64, limit int) ([]*AuditLog, error)
+	CountUnchainedSince(orgID uuid.UUID, since time.Time) (int, error)
+	CreateCheckpoint(checkpoint *AuditCheckpoint) error
+	GetCheckpoints(orgID uuid.UUID, limit, offset int) ([]*AuditCheckpoint, error)
+	GetLatestCheckpoint(orgID uuid.UUID) (*AuditCheckpoint, error)
+	SetPrunedThrough(orgID uuid.UUID, sequence int64, hash string) error
+}
+
+// auditEntryCanonical fixes the field order hashed for an audit record
+type auditEntryCanonical struct {
+	SequenceNumber int64           `json:"seq"`
+	PrevHash       string          `json:"prev_hash"`
+	ID             uuid.UUID       `json:"id"`
+	OrganizationID uuid.UUID       `json:"organization_id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	Action         AuditAction     `json:"action"`
+	ResourceType   string          `json:"resource_type"`
+	ResourceID     uuid.UUID       `json:"resource_id"`
+	IPAddress      string          `json:"ip_address"`
+	UserAgent      string          `json:"user_agent"`
+	Metadata       json.RawMessage `json:"metadata"`
+	Timestamp      string          `json:"timestamp"`
+}
+
+// ComputeAuditEntryHash returns the SHA-256 (hex) of the canonical form of a chained audit record.
+// Metadata is normalized through a JSON round trip so the hash is identical before and after
+// it is stored as JSONB.
+func ComputeAuditEntryHash(log *AuditLog) (string, error) {
+	if log.SequenceNumber == nil {
+		return "", fmt.Errorf("audit log %s is not chained", log.ID)
+	}
+
+	metadata, err := canonicalAuditMetadata(log.Metadata)
+	if err != nil {
+		return "", err
+	}
+
+	canonical, err := json.Marshal(auditEntryCanonical{
+		SequenceNumber: *log.SequenceNumber,
+		PrevHash:       log.PrevHash,
+		ID:             log.ID,
+		OrganizationID: log.OrganizationID,
+		UserID:         log.UserID,
+		Action:         log.Action,
+		ResourceType:   log.ResourceType,
+		ResourceID:     log.ResourceID,
+		IPAddress:      log.IPAddress,
+		UserAgent:      log.UserAgent,
+		Metadata:       metadata,
+		Timestamp:      log.Timestamp.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit log: %w", err)
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func canonicalAuditMetadata(metadata map[string]interface{}) (json.RawMessage, error) {
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit metadata: %w", err)
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil, fmt.Errorf("failed to normalize audit metadata: %w", err)
+	}
+
+	return json.Marshal(normalized)
+}
@@ -40,6 +40,12 @@ const (
 	// Webhook actions
 	AuditActionTest AuditAction = "test"
 
+	// Scheduled job actions
+	AuditActionTrigger AuditAction = "trigger"
+
+	// Data retention actions
+	AuditActionPurge AuditAction = "purge"
+
 	// Legacy constants for backward compatibility
 	ActionLogin          AuditAction = "login"
 	ActionLogout         AuditAction = "logout"
@@ -64,6 +70,11 @@ type AuditLog struct {
 	UserAgent      string      `json:"user_agent"`
 	Metadata       map[string]interface{} `json:"metadata"`
 	Timestamp      time.Time   `json:"timestamp"`
+
+	// Hash chain (set by the repository on insert; nil/empty for records written before chaining)
+	SequenceNumber *int64 `json:"sequence_number,omitempty"`
+	PrevHash       string `json:"prev_hash,omitempty"`
+	EntryHash      string `json:"entry_hash,omitempty"`
 }
 
 // AuditLogRepository defines the interface for audit log persistence
@@ -74,3 +85,30 @@ type AuditLogRepository interface {
 	GetByResource(resourceType string, resourceID uuid.UUID) ([]*AuditLog, error)
 	Search(query string, limit, offset int) ([]*AuditLog, error)
 }
+
+// AuditLogFilter selects audit logs for export; nil/empty fields do not filter
+type AuditLogFilter struct {
+	OrganizationID uuid.UUID
+	StartDate      *time.Time    // Inclusive
+	EndDate        *time.Time    // Exclusive
+	UserID         *uuid.UUID    // Actor
+	Actions        []AuditAction // Any of
+	ResourceType   string
+	ResourceID     *uuid.UUID
+	AgentID        *uuid.UUID // Records about the agent or carrying it as metadata.agent_id
+}
+
+// AuditLogCursor is a keyset position in (timestamp, id) order
+type AuditLogCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// AuditLogExportRepository reads filtered audit logs in stable (timestamp, id) order
+type AuditLogExportRepository interface {
+	// ListForExport returns up to limit records after the after cursor and up to (inclusive) the until cursor
+	ListForExport(filter AuditLogFilter, after, until *AuditLogCursor, limit int) ([]*AuditLog, error)
+	// GetExportPageEnd returns the position of the last record of a page of pageSize records
+	// after the after cursor, or nil if no records follow that page
+	GetExportPageEnd(filter AuditLogFilter, after *AuditLogCursor, pageSize int) (*AuditLogCursor, error)
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusListSize is the number of entries in a StatusList2021 list. 131,072 bits (16KB) is the
+// minimum the specification recommends, so a credential's index says little about its holder.
+const StatusListSize = 131072
+
+// Verifiable credential types AIM issues
+const (
+	CredentialTypeVerifiable    = "VerifiableCredential"
+	CredentialTypeAgentIdentity = "AgentIdentityCredential"
+	CredentialTypeStatusList    = "StatusList2021Credential"
+)
+
+// DID methods an agent can be identified by
+const (
+	DIDMethodWeb = "web" // Hosted by AIM; resolvable while the agent exists
+	DIDMethodKey = "key" // Derived from the agent's Ed25519 key; resolvable offline
+)
+
+// AgentCredential is a verifiable credential issued to an agent
+type AgentCredential struct {
+	ID              uuid.UUID  `json:"id"`
+	AgentID         uuid.UUID  `json:"agentId"`
+	OrganizationID  uuid.UUID  `json:"organizationId"`
+	SubjectDID      string     `json:"subjectDid"`
+	Capabilities    []string   `json:"capabilities"` // As granted at issuance
+	StatusListID    uuid.UUID  `json:"statusListId"`
+	StatusListIndex int        `json:"statusListIndex"`
+	IssuedBy        *uuid.UUID `json:"issuedBy,omitempty"`
+	IssuedAt        time.Time  `json:"issuedAt"`
+	ExpiresAt       time.Time  `json:"expiresAt"`
+	RevokedAt       *time.Time `json:"revokedAt,omitempty"`
+	RevokeReason    *string    `json:"revokeReason,omitempty"`
+}
+
+// VerifiableCredential is a W3C Verifiable Credential (data model 1.1), carried in the "vc" claim
+// of a VC-JWT
+type VerifiableCredential struct {
+	Context           []string               `json:"@context"`
+	ID                string                 `json:"id,omitempty"`
+	Type              []string               `json:"type"`
+	Issuer            string                 `json:"issuer"`
+	IssuanceDate      string                 `json:"issuanceDate"`
+	ExpirationDate    string                 `json:"expirationDate,omitempty"`
+	CredentialSubject map[string]interface{} `json:"credentialSubject"`
+	CredentialStatus  *CredentialStatus      `json:"credentialStatus,omitempty"`
+}
+
+// CredentialStatus is a StatusList2021Entry: the credential's bit in a published status list
+type CredentialStatus struct {
+	ID                   string `json:"id"`
+	Type                 string `json:"type"`
+	StatusPurpose        string `json:"statusPurpose"`
+	StatusListIndex      string `json:"statusListIndex"`
+	StatusListCredential string `json:"statusListCredential"`
+}
+
+// DIDDocument is a W3C DID document
+type DIDDocument struct {
+	Context            []string                `json:"@context"`
+	ID                 string                  `json:"id"`
+	Controller         string                  `json:"controller,omitempty"`
+	AlsoKnownAs        []string                `json:"alsoKnownAs,omitempty"`
+	VerificationMethod []DIDVerificationMethod `json:"verificationMethod"`
+	Authentication     []string                `json:"authentication,omitempty"`
+	AssertionMethod    []string                `json:"assertionMethod,omitempty"`
+}
+
+// DIDVerificationMethod is a public key in a DID document
+type DIDVerificationMethod struct {
+	ID                 string      `json:"id"`
+	Type               string      `json:"type"`
+	Controller         string      `json:"controller"`
+	PublicKeyJwk       *JSONWebKey `json:"publicKeyJwk,omitempty"`
+	PublicKeyMultibase string      `json:"publicKeyMultibase,omitempty"`
+}
+
+// CredentialVerification is the result of verifying an agent credential
+type CredentialVerification struct {
+	Valid          bool       `json:"valid"`
+	Errors         []string   `json:"errors,omitempty"`
+	Issuer         string     `json:"issuer,omitempty"`
+	CredentialID   string     `json:"credentialId,omitempty"`
+	Subject        string     `json:"subject,omitempty"` // The agent's DID
+	AgentID        *uuid.UUID `json:"agentId,omitempty"`
+	OrganizationID *uuid.UUID `json:"organizationId,omitempty"`
+	Capabilities   []string   `json:"capabilities,omitempty"` // As granted at issuance
+	Revoked        bool       `json:"revoked"`
+	IssuedAt       *time.Time `json:"issuedAt,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+}
+
+// AgentCredentialRepository defines the interface for agent credential persistence
+type AgentCredentialRepository interface {
+	// Create stores a credential, assigning it the next free entry of a status list
+	Create(credential *AgentCredential) error
+
+	// GetByID retrieves a credential by ID, or nil if unknown
+	GetByID(id uuid.UUID) (*AgentCredential, error)
+
+	// ListByAgent returns an agent's credentials, newest first
+	ListByAgent(agentID uuid.UUID) ([]*AgentCredential, error)
+
+	// Revoke revokes a credential
+	Revoke(id uuid.UUID, reason string, at time.Time) error
+
+	// StatusListExists reports whether a status list has been allocated
+	StatusListExists(listID uuid.UUID) (bool, error)
+
+	// RevokedIndexes returns the entries of a status list whose credentials are revoked, or
+	// whose agent has been deleted, revoked, suspended or marked compromised
+	RevokedIndexes(listID uuid.UUID) ([]int, error)
+}
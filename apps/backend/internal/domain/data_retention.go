@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionTable is a table whose rows are purged once they pass their retention period
+type RetentionTable string
+
+const (
+	RetentionTableAuditLogs          RetentionTable = "audit_logs"
+	RetentionTableVerificationEvents RetentionTable = "verification_events"
+	RetentionTableAlerts             RetentionTable = "alerts" // Only acknowledged alerts are purged
+	RetentionTableDetections         RetentionTable = "detections"
+	RetentionTableWebhookDeliveries  RetentionTable = "webhook_deliveries"
+)
+
+// RetentionTables lists every table covered by retention, in purge order
+var RetentionTables = []RetentionTable{
+	RetentionTableVerificationEvents,
+	RetentionTableAlerts,
+	RetentionTableDetections,
+	RetentionTableWebhookDeliveries,
+	RetentionTableAuditLogs,
+}
+
+// DefaultRetentionDays applies to organizations without a rule for a table
+var DefaultRetentionDays = map[RetentionTable]int{
+	RetentionTableAuditLogs:          365,
+	RetentionTableVerificationEvents: 90,
+	RetentionTableAlerts:             180,
+	RetentionTableDetections:         90,
+	RetentionTableWebhookDeliveries:  30,
+}
+
+// MinRetentionDays is the shortest retention period an organization may configure
+var MinRetentionDays = map[RetentionTable]int{
+	RetentionTableAuditLogs:          90,
+	RetentionTableVerificationEvents: 7,
+	RetentionTableAlerts:             7,
+	RetentionTableDetections:         7,
+	RetentionTableWebhookDeliveries:  7,
+}
+
+// IsValid reports whether the table is covered by retention
+func (t RetentionTable) IsValid() bool {
+	_, ok := DefaultRetentionDays[t]
+	return ok
+}
+
+// RetentionRule overrides the default retention period of one table for an organization
+type RetentionRule struct {
+	OrganizationID uuid.UUID      `json:"organization_id"`
+	Table          RetentionTable `json:"table"`
+	RetentionDays  int            `json:"retention_days"`
+	Enabled        bool           `json:"enabled"` // Disabled rules keep data indefinitely
+	UpdatedBy      *uuid.UUID     `json:"updated_by,omitempty"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// LegalHold suspends purging of an organization's data while it is active
+type LegalHold struct {
+	ID             uuid.UUID       `json:"id"`
+	OrganizationID uuid.UUID       `json:"organization_id"`
+	Table          *RetentionTable `json:"table,omitempty"` // Nil holds every table
+	Reason         string          `json:"reason"`
+	CreatedBy      uuid.UUID       `json:"created_by"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ReleasedBy     *uuid.UUID      `json:"released_by,omitempty"`
+	ReleasedAt     *time.Time      `json:"released_at,omitempty"`
+}
+
+// Covers reports whether an active hold applies to the table
+func (h *LegalHold) Covers(table RetentionTable) bool {
+	return h.ReleasedAt == nil && (h.Table == nil || *h.Table == table)
+}
+
+// RetentionPolicy is the effective retention of one table for an organization
+type RetentionPolicy struct {
+	Table         RetentionTable `json:"table"`
+	RetentionDays int            `json:"retention_days"`
+	Enabled       bool           `json:"enabled"`
+	IsDefault     bool           `json:"is_default"`
+	MinDays       int            `json:"min_days"`
+	OnLegalHold   bool           `json:"on_legal_hold"`
+	RecordCount   int64          `json:"record_count"`
+	OldestRecord  *time.Time     `json:"oldest_record,omitempty"`
+	UpdatedAt     *time.Time     `json:"updated_at,omitempty"`
+}
+
+// RetentionPurgeTable is the outcome of purging one table
+type RetentionPurgeTable struct {
+	Table       RetentionTable `json:"table"`
+	Cutoff      *time.Time     `json:"cutoff,omitempty"`
+	RowsDeleted int64          `json:"rows_deleted"`
+	Batches     int            `json:"batches"`
+	Complete    bool           `json:"complete"`          // False when the batch limit was reached first
+	Skipped     string         `json:"skipped,omitempty"` // Why the table was not purged
+	Error       string         `json:"error,omitempty"`
+}
+
+// RetentionPurgeRun is the outcome of purging one organization
+type RetentionPurgeRun struct {
+	OrganizationID uuid.UUID             `json:"organization_id"`
+	Tables         []RetentionPurgeTable `json:"tables"`
+	TotalDeleted   int64                 `json:"total_deleted"`
+	StartedAt      time.Time             `json:"started_at"`
+	FinishedAt     time.Time             `json:"finished_at"`
+}
+
+// DataRetentionRepository stores retention rules and legal holds and deletes expired rows
+type DataRetentionRepository interface {
+	ListRules(orgID uuid.UUID) ([]*RetentionRule, error)
+	UpsertRule(rule *RetentionRule) error
+	DeleteRule(orgID uuid.UUID, table RetentionTable) error
+
+	CreateLegalHold(hold *LegalHold) error
+	GetLegalHold(id uuid.UUID) (*LegalHold, error)
+	ListLegalHolds(orgID uuid.UUID, includeReleased bool) ([]*LegalHold, error)
+	ReleaseLegalHold(id, releasedBy uuid.UUID, releasedAt time.Time) error
+
+	// GetTableStats returns how many rows an organization has in a table and when the oldest was written
+	GetTableStats(orgID uuid.UUID, table RetentionTable) (int64, *time.Time, error)
+	// PurgeBatch deletes up to limit of an organization's rows written before the cutoff. Audit
+	// logs are not purged here; see PurgeAuditLogBatch.
+	PurgeBatch(ctx context.Context, orgID uuid.UUID, table RetentionTable, before time.Time, limit int) (int64, error)
+	// PurgeAuditLogBatch deletes up to limit chained audit records through throughSequence and
+	// unchained (pre-chain) audit records written before the cutoff
+	PurgeAuditLogBatch(ctx context.Context, orgID uuid.UUID, throughSequence int64, before time.Time, limit int) (int64, error)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataTransferSource identifies how a data transfer was reported
+type DataTransferSource string
+
+const (
+	DataTransferSourceVerifyAction       DataTransferSource = "verify_action"       // verify-action metadata
+	DataTransferSourceActionResult       DataTransferSource = "action_result"       // log-action result
+	DataTransferSourceVerificationResult DataTransferSource = "verification_result" // SDK verification result metadata
+	DataTransferSourceMCPCall            DataTransferSource = "mcp_call"            // SDK-instrumented MCP tool call
+)
+
+// DataTransfer is the number of bytes an agent read from or exported to a resource in one action
+type DataTransfer struct {
+	ID             uuid.UUID          `json:"id"`
+	OrganizationID uuid.UUID          `json:"organization_id"`
+	AgentID        uuid.UUID          `json:"agent_id"`
+	Source         DataTransferSource `json:"source"`
+	Resource       string             `json:"resource,omitempty"`
+	Destination    string             `json:"destination,omitempty"` // Where exported bytes were sent (URL, host, bucket, email)
+	MCPServer      string             `json:"mcp_server,omitempty"`
+	BytesRead      int64              `json:"bytes_read"`
+	BytesExported  int64              `json:"bytes_exported"`
+	AuditID        *uuid.UUID         `json:"audit_id,omitempty"`
+	OccurredAt     time.Time          `json:"occurred_at"`
+}
+
+// DataTransferDestination is the volume exported to one destination within a window
+type DataTransferDestination struct {
+	Destination   string `json:"destination"`
+	BytesExported int64  `json:"bytes_exported"`
+	Transfers     int64  `json:"transfers"`
+}
+
+// DataTransferUsage is an agent's data volume within a rolling window and how it compares to the
+// data_exfiltration policy that applies to the agent
+type DataTransferUsage struct {
+	AgentID       uuid.UUID                  `json:"agent_id"`
+	WindowStart   time.Time                  `json:"window_start"`
+	WindowEnd     time.Time                  `json:"window_end"`
+	BytesRead     int64                      `json:"bytes_read"`
+	BytesExported int64                      `json:"bytes_exported"`
+	Destinations  []*DataTransferDestination `json:"destinations"`
+	PolicyName    string                     `json:"policy_name,omitempty"`
+	ThresholdMB   float64                    `json:"threshold_mb,omitempty"`
+	Exceeded      bool                       `json:"exceeded"`
+	ExportBlocked bool                       `json:"export_blocked"` // Further data:export actions are denied
+}
+
+// DataTransferRepository persists reported data transfers
+type DataTransferRepository interface {
+	Create(transfer *DataTransfer) error
+	// SumByAgent returns the bytes read and exported by an agent since the given time
+	SumByAgent(agentID uuid.UUID, since time.Time) (bytesRead, bytesExported int64, err error)
+	// TopDestinations returns the destinations an agent exported the most bytes to since the given time
+	TopDestinations(agentID uuid.UUID, since time.Time, limit int) ([]*DataTransferDestination, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
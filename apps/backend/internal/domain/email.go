@@ -46,6 +46,15 @@ const (
 	TemplateAPIKeyCreated  EmailTemplate = "api_key_created"
 	TemplateAPIKeyExpiring EmailTemplate = "api_key_expiring"
 	TemplateAPIKeyRevoked  EmailTemplate = "api_key_revoked"
+
+	// Access review templates
+	TemplateAccessReviewReminder EmailTemplate = "access_review_reminder"
+
+	// Verification approval templates
+	TemplateVerificationApprovalRequest EmailTemplate = "verification_approval_request"
+
+	// Session templates
+	TemplateSessionRevoked EmailTemplate = "session_revoked"
 )
 
 // EmailTemplateData contains data for rendering email templates
@@ -63,9 +63,17 @@ type MCPAttestation struct {
 	IsValid           bool               `json:"is_valid"`
 	CreatedAt         time.Time          `json:"created_at"`
 
+	// Revocation (set when an admin revokes the attestation)
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy        *uuid.UUID `json:"revoked_by,omitempty"`
+	RevocationReason string     `json:"revocation_reason,omitempty"`
+
 	// Populated via JOIN queries
-	AgentName       string  `json:"agent_name,omitempty"`
-	AgentTrustScore float64 `json:"agent_trust_score,omitempty"`
+	AgentName           string     `json:"agent_name,omitempty"`
+	AgentTrustScore     float64    `json:"agent_trust_score,omitempty"`
+	AgentOrganizationID *uuid.UUID `json:"-"`
+	AgentCreatedBy      *uuid.UUID `json:"-"`
+	AgentCreatedAt      *time.Time `json:"-"`
 }
 
 // AttestationWithAgentDetails is returned from API endpoints that need agent info
@@ -80,6 +88,8 @@ type AttestationWithAgentDetails struct {
 	ConnectionLatencyMs   float64   `json:"connection_latency_ms"`
 	HealthCheckPassed     bool      `json:"health_check_passed"`
 	IsValid               bool      `json:"is_valid"`
+	RevokedAt             string    `json:"revoked_at,omitempty"`
+	RevocationReason      string    `json:"revocation_reason,omitempty"`
 }
 
 // VerificationMethod represents how an MCP server was verified
@@ -101,6 +111,8 @@ type MCPAttestationRepository interface {
 	GetAttestationsByAgent(agentID uuid.UUID) ([]*MCPAttestation, error)
 	InvalidateAttestation(id uuid.UUID) error
 	InvalidateExpiredAttestations() error // Background job
+	RevokeAttestation(id uuid.UUID, revokedBy uuid.UUID, reason string) error
+	RevokeAttestationsByAgent(agentID uuid.UUID, revokedBy uuid.UUID, reason string) ([]uuid.UUID, int, error)
 
 	// Connection operations
 	CreateConnection(connection *AgentMCPConnection) error
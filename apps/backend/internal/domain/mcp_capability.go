@@ -29,17 +29,55 @@ type MCPServerCapability struct {
 	IsActive         bool              `json:"is_active"`
 	CreatedAt        time.Time         `json:"created_at"`
 	UpdatedAt        time.Time         `json:"updated_at"`
+	// ✅ Static analysis of tool metadata (prompt injection / tool poisoning)
+	SecurityFindings []MCPCapabilityFinding `json:"security_findings"`
+	RiskLevel        MCPCapabilityRiskLevel `json:"risk_level"`
+	ScannedAt        *time.Time             `json:"scanned_at"`
+}
+
+// MCPCapabilityRiskLevel summarizes the worst finding on a capability
+type MCPCapabilityRiskLevel string
+
+const (
+	MCPCapabilityRiskNone     MCPCapabilityRiskLevel = "none"
+	MCPCapabilityRiskLow      MCPCapabilityRiskLevel = "low"
+	MCPCapabilityRiskMedium   MCPCapabilityRiskLevel = "medium"
+	MCPCapabilityRiskHigh     MCPCapabilityRiskLevel = "high"
+	MCPCapabilityRiskCritical MCPCapabilityRiskLevel = "critical"
+)
+
+// MCPFindingCategory groups metadata scanner rules
+type MCPFindingCategory string
+
+const (
+	MCPFindingHiddenInstructions     MCPFindingCategory = "hidden_instructions"
+	MCPFindingInvisibleCharacters    MCPFindingCategory = "invisible_characters"
+	MCPFindingCrossToolReference     MCPFindingCategory = "cross_tool_reference"
+	MCPFindingCredentialExfiltration MCPFindingCategory = "credential_exfiltration"
+	MCPFindingBroadSchema            MCPFindingCategory = "broad_schema"
+)
+
+// MCPCapabilityFinding is a single issue found in a capability's name, description or schema
+type MCPCapabilityFinding struct {
+	RuleID   string             `json:"rule_id"` // e.g., "hidden_instructions.ignore_previous"
+	Category MCPFindingCategory `json:"category"`
+	Severity AlertSeverity      `json:"severity"` // info, warning, high, critical
+	Field    string             `json:"field"`    // name, description or schema
+	Message  string             `json:"message"`
+	Evidence string             `json:"evidence,omitempty"` // Short excerpt (invisible characters are escaped)
 }
 
 // MCPServerCapabilityRepository defines the interface for MCP capability persistence
 type MCPServerCapabilityRepository interface {
 	Create(capability *MCPServerCapability) error
+	Upsert(capability *MCPServerCapability) error
 	GetByID(id uuid.UUID) (*MCPServerCapability, error)
 	GetByServerID(serverID uuid.UUID) ([]*MCPServerCapability, error)
 	GetByServerIDAndType(serverID uuid.UUID, capType MCPCapabilityType) ([]*MCPServerCapability, error)
 	Update(capability *MCPServerCapability) error
 	Delete(id uuid.UUID) error
 	DeleteByServerID(serverID uuid.UUID) error
+	DeactivateMissing(serverID uuid.UUID, keepIDs []uuid.UUID) error
 }
 
 // MCPCapabilitySummary represents a summary of capabilities by type
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MCPServerHealthStatus represents the health of an MCP server as seen by the scheduled monitor
+type MCPServerHealthStatus string
+
+const (
+	MCPServerHealthHealthy   MCPServerHealthStatus = "healthy"
+	MCPServerHealthDegraded  MCPServerHealthStatus = "degraded"  // Failing, below the downgrade threshold
+	MCPServerHealthUnhealthy MCPServerHealthStatus = "unhealthy" // Downgraded after consecutive failures
+	MCPServerHealthUnknown   MCPServerHealthStatus = "unknown"
+)
+
+// MCPServerHealthCheck is the result of one scheduled re-verification run
+type MCPServerHealthCheck struct {
+	ID             uuid.UUID `json:"id"`
+	MCPServerID    uuid.UUID `json:"mcp_server_id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Reachable      bool      `json:"reachable"`
+	SignatureValid *bool     `json:"signature_valid"` // nil when the server has no public key
+	Success        bool      `json:"success"`
+	LatencyMs      int       `json:"latency_ms"`
+	HTTPStatus     *int      `json:"http_status,omitempty"`
+	ErrorReason    string    `json:"error_reason,omitempty"`
+	CheckedAt      time.Time `json:"checked_at"`
+}
+
+// MCPServerHealth is the current health state of an MCP server
+type MCPServerHealth struct {
+	MCPServerID         uuid.UUID             `json:"mcp_server_id"`
+	HealthStatus        MCPServerHealthStatus `json:"health_status"`
+	ConsecutiveFailures int                   `json:"consecutive_failures"`
+	LastCheckedAt       *time.Time            `json:"last_checked_at"`
+	LastSuccessAt       *time.Time            `json:"last_success_at"`
+	LastFailureReason   string                `json:"last_failure_reason,omitempty"`
+	DowngradedAt        *time.Time            `json:"downgraded_at"` // Set while the monitor holds the server below verified
+	UpdatedAt           time.Time             `json:"updated_at"`
+}
+
+// MCPServerUptime summarizes health checks over a time window
+type MCPServerUptime struct {
+	TotalChecks      int       `json:"total_checks"`
+	SuccessfulChecks int       `json:"successful_checks"`
+	Uptime           float64   `json:"uptime"` // 0.0 - 1.0 (0 when there are no checks)
+	Since            time.Time `json:"since"`
+}
+
+// MCPServerHealthRepository defines the interface for MCP server health persistence
+type MCPServerHealthRepository interface {
+	CreateCheck(check *MCPServerHealthCheck) error
+	GetChecksByServer(serverID uuid.UUID, limit, offset int) ([]*MCPServerHealthCheck, error)
+	GetHealth(serverID uuid.UUID) (*MCPServerHealth, error) // nil when the server was never checked
+	UpsertHealth(health *MCPServerHealth) error
+	GetUptime(serverID uuid.UUID, since time.Time) (*MCPServerUptime, error)
+	// GetUptimeForServerRefs aggregates uptime over the servers of an organization matched by ID or name
+	GetUptimeForServerRefs(orgID uuid.UUID, refs []string, since time.Time) (*MCPServerUptime, error)
+	// ListServerIDsDueForCheck returns verified servers plus servers the monitor downgraded
+	ListServerIDsDueForCheck() ([]uuid.UUID, error)
+}
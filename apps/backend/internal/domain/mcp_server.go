@@ -16,6 +16,27 @@ const (
 	MCPServerStatusRevoked   MCPServerStatus = "revoked"
 )
 
+// MCPTransport is how clients connect to an MCP server
+type MCPTransport string
+
+const (
+	MCPTransportStdio MCPTransport = "stdio" // Local process launched by the client
+	MCPTransportSSE   MCPTransport = "sse"
+	MCPTransportHTTP  MCPTransport = "http" // Streamable HTTP
+)
+
+// IsRemote reports whether the server is reached over the network and therefore has a URL
+func (t MCPTransport) IsRemote() bool {
+	return t != MCPTransportStdio
+}
+
+// MCPPackageRef identifies the published package a stdio server is launched from
+type MCPPackageRef struct {
+	Registry string `json:"registry"`          // npm, pypi or docker
+	Name     string `json:"name"`              // e.g. @modelcontextprotocol/server-github
+	Version  string `json:"version,omitempty"` // Empty when the launcher resolves the latest version
+}
+
 // MCPServer represents a Model Context Protocol server
 type MCPServer struct {
 	ID                uuid.UUID       `json:"id"`
@@ -47,6 +68,13 @@ type MCPServer struct {
 	AttestedBy             []string `json:"attested_by,omitempty"`              // Agent names that have attested
 	ConnectedAgentsCount   int      `json:"connected_agents_count,omitempty"`   // Number of connected agents
 	CapabilitiesCount      int      `json:"capabilities_count,omitempty"`       // Number of capabilities
+	// Launch spec of local (stdio) servers, which have no URL
+	Transport    MCPTransport   `json:"transport"`
+	Command      string         `json:"command,omitempty"`
+	Args         []string       `json:"args,omitempty"`
+	EnvNames     []string       `json:"env_names,omitempty"` // Values are never stored
+	Package      *MCPPackageRef `json:"package,omitempty"`
+	IdentityHash string         `json:"identity_hash,omitempty"` // Hash of the launch spec; see application.ComputeMCPStdioIdentity
 }
 
 // MCPServerRepository defines the interface for MCP server persistence
@@ -55,6 +83,7 @@ type MCPServerRepository interface {
 	GetByID(id uuid.UUID) (*MCPServer, error)
 	GetByOrganization(orgID uuid.UUID) ([]*MCPServer, error)
 	GetByURL(url string) (*MCPServer, error)
+	GetByIdentityHash(orgID uuid.UUID, identityHash string) (*MCPServer, error)
 	Update(server *MCPServer) error
 	Delete(id uuid.UUID) error
 	List(limit, offset int) ([]*MCPServer, error)
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MCPToolSurfaceStatus represents the review state of a tool-surface snapshot
+type MCPToolSurfaceStatus string
+
+const (
+	MCPToolSurfaceStatusApproved MCPToolSurfaceStatus = "approved" // Baseline that future discoveries are compared against
+	MCPToolSurfaceStatusPending  MCPToolSurfaceStatus = "pending"  // Drifted from the approved baseline, awaiting admin review
+	MCPToolSurfaceStatusRejected MCPToolSurfaceStatus = "rejected" // Admin refused the new tool surface
+)
+
+// MCPToolFingerprint is the hashed identity of a single MCP capability at discovery time
+type MCPToolFingerprint struct {
+	Name            string            `json:"name"`
+	Type            MCPCapabilityType `json:"type"`
+	DescriptionHash string            `json:"description_hash"` // SHA-256 of the raw description text
+	SchemaHash      string            `json:"schema_hash"`      // SHA-256 of the canonicalized JSON schema
+	Hash            string            `json:"hash"`             // SHA-256 over name, type, description and schema hashes
+}
+
+// MCPToolChange describes how one capability differs between two snapshots
+type MCPToolChange struct {
+	Name               string            `json:"name"`
+	Type               MCPCapabilityType `json:"type"`
+	DescriptionChanged bool              `json:"description_changed"`
+	SchemaChanged      bool              `json:"schema_changed"`
+}
+
+// MCPToolSurfaceDiff summarizes the changes between the approved snapshot and a new discovery
+type MCPToolSurfaceDiff struct {
+	Added    []MCPToolFingerprint `json:"added"`
+	Removed  []MCPToolFingerprint `json:"removed"`
+	Modified []MCPToolChange      `json:"modified"`
+}
+
+// HasChanges reports whether the diff contains any additions, removals or modifications
+func (d *MCPToolSurfaceDiff) HasChanges() bool {
+	return d != nil && (len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Modified) > 0)
+}
+
+// MCPToolSurfaceSnapshot is a versioned, hashed record of everything an MCP server exposes
+type MCPToolSurfaceSnapshot struct {
+	ID            uuid.UUID            `json:"id"`
+	MCPServerID   uuid.UUID            `json:"mcp_server_id"`
+	Version       int                  `json:"version"`
+	SurfaceHash   string               `json:"surface_hash"` // SHA-256 over all tool fingerprints (order independent)
+	Tools         []MCPToolFingerprint `json:"tools"`
+	Diff          *MCPToolSurfaceDiff  `json:"diff,omitempty"` // Changes relative to the previously approved snapshot
+	Status        MCPToolSurfaceStatus `json:"status"`
+	ReviewedBy    *uuid.UUID           `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time           `json:"reviewed_at,omitempty"`
+	ReviewComment string               `json:"review_comment,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+// MCPToolSurfaceRepository defines the interface for tool-surface snapshot persistence
+type MCPToolSurfaceRepository interface {
+	Create(snapshot *MCPToolSurfaceSnapshot) error
+	GetByID(id uuid.UUID) (*MCPToolSurfaceSnapshot, error)
+	GetByServerID(serverID uuid.UUID, limit, offset int) ([]*MCPToolSurfaceSnapshot, error)
+	GetLatest(serverID uuid.UUID) (*MCPToolSurfaceSnapshot, error)
+	GetLatestApproved(serverID uuid.UUID) (*MCPToolSurfaceSnapshot, error)
+	GetRejectedBySurfaceHash(serverID uuid.UUID, surfaceHash string) (*MCPToolSurfaceSnapshot, error)
+	UpdateStatus(id uuid.UUID, status MCPToolSurfaceStatus, reviewedBy *uuid.UUID, comment string) error
+}
@@ -9,6 +9,7 @@ import (
 // Organization represents a tenant organization
 type Organization struct {
 	ID        uuid.UUID              `json:"id"`
+	ParentID  *uuid.UUID             `json:"parent_id,omitempty"` // Set for child organizations (business units)
 	Name      string                 `json:"name"`
 	Domain    string                 `json:"domain"`
 	PlanType  string                 `json:"plan_type"` // free, pro, enterprise
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationNode is an organization with its descendants
+type OrganizationNode struct {
+	*Organization
+	Children []*OrganizationNode `json:"children"`
+}
+
+// OrganizationRollup holds the analytics of one organization of a subtree
+type OrganizationRollup struct {
+	OrganizationID      uuid.UUID  `json:"organization_id"`
+	Name                string     `json:"name"`
+	ParentID            *uuid.UUID `json:"parent_id,omitempty"`
+	TotalAgents         int        `json:"total_agents"`
+	VerifiedAgents      int        `json:"verified_agents"`
+	AvgTrustScore       float64    `json:"avg_trust_score"`
+	TotalMCPServers     int        `json:"total_mcp_servers"`
+	TotalUsers          int        `json:"total_users"`
+	OpenAlerts          int        `json:"open_alerts"`
+	CriticalAlerts      int        `json:"critical_alerts"`
+	TotalVerifications  int        `json:"total_verifications"`
+	FailedVerifications int        `json:"failed_verifications"`
+}
+
+// OrganizationRollupReport rolls analytics up across an organization and its descendants
+type OrganizationRollupReport struct {
+	RootOrganizationID uuid.UUID             `json:"root_organization_id"`
+	Since              time.Time             `json:"since"`
+	Totals             *OrganizationRollup   `json:"totals"`
+	Organizations      []*OrganizationRollup `json:"organizations"`
+	GeneratedAt        time.Time             `json:"generated_at"`
+}
+
+// OrganizationHierarchyRepository navigates parent/child organizations
+type OrganizationHierarchyRepository interface {
+	GetChildren(parentID uuid.UUID) ([]*Organization, error)
+	// GetAncestorIDs returns the IDs of an organization's ancestors, nearest first
+	GetAncestorIDs(id uuid.UUID) ([]uuid.UUID, error)
+	// GetSubtree returns the organization and all of its descendants, parents before children
+	GetSubtree(rootID uuid.UUID) ([]*Organization, error)
+	// MoveUser moves a user into another organization
+	MoveUser(userID, orgID uuid.UUID) error
+	// GetRollup returns the analytics of each organization; verifications are counted since the given time
+	GetRollup(orgIDs []uuid.UUID, since time.Time) ([]*OrganizationRollup, error)
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuotaResource is something an organization's plan limits
+type QuotaResource string
+
+const (
+	QuotaAgents        QuotaResource = "agents"
+	QuotaUsers         QuotaResource = "users"
+	QuotaMCPServers    QuotaResource = "mcp_servers"
+	QuotaVerifications QuotaResource = "verifications" // verify-action calls per calendar month (UTC)
+)
+
+// QuotaResources lists every limited resource in the order usage is reported
+var QuotaResources = []QuotaResource{QuotaAgents, QuotaUsers, QuotaMCPServers, QuotaVerifications}
+
+// QuotaWarningRatio is the share of a quota at which admins are warned
+const QuotaWarningRatio = 0.8
+
+// PlanQuota holds the limits of a plan; zero means unlimited
+type PlanQuota struct {
+	MaxAgents            int `json:"max_agents"`
+	MaxUsers             int `json:"max_users"`
+	MaxMCPServers        int `json:"max_mcp_servers"`
+	MonthlyVerifications int `json:"monthly_verifications"`
+}
+
+// PlanQuotas are the limits of each plan. An organization's max_agents and max_users
+// override the plan's agent and user limits.
+var PlanQuotas = map[string]PlanQuota{
+	"free":       {MaxAgents: 100, MaxUsers: 10, MaxMCPServers: 50, MonthlyVerifications: 50000},
+	"pro":        {MaxAgents: 1000, MaxUsers: 100, MaxMCPServers: 500, MonthlyVerifications: 1000000},
+	"enterprise": {},
+}
+
+// QuotaForPlan returns the limits of a plan; unknown plans get the free plan's limits
+func QuotaForPlan(planType string) PlanQuota {
+	if quota, ok := PlanQuotas[planType]; ok {
+		return quota
+	}
+	return PlanQuotas["free"]
+}
+
+// QuotaUsage is an organization's consumption of one quota
+type QuotaUsage struct {
+	Resource QuotaResource `json:"resource"`
+	Used     int           `json:"used"`
+	Limit    int           `json:"limit"` // Zero means unlimited
+	Percent  float64       `json:"percent"`
+	Warning  bool          `json:"warning"`             // At or above QuotaWarningRatio
+	Exceeded bool          `json:"exceeded"`            // No more can be created or used
+	Period   string        `json:"period,omitempty"`    // YYYY-MM for monthly quotas
+	ResetsAt *time.Time    `json:"resets_at,omitempty"` // For monthly quotas
+}
+
+// OrganizationUsage reports usage of every quota of an organization
+type OrganizationUsage struct {
+	OrganizationID uuid.UUID     `json:"organization_id"`
+	Plan           string        `json:"plan"`
+	Quotas         []*QuotaUsage `json:"quotas"`
+}
+
+// QuotaRepository counts what an organization has created and records metered usage
+type QuotaRepository interface {
+	CountAgents(orgID uuid.UUID) (int, error)
+	CountUsers(orgID uuid.UUID) (int, error)
+	CountMCPServers(orgID uuid.UUID) (int, error)
+	// CountVerifications sums verify-action calls recorded on or after since (a UTC date)
+	CountVerifications(orgID uuid.UUID, since time.Time) (int, error)
+	RecordVerification(orgID uuid.UUID, at time.Time, success bool) error
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is a recorded user session refresh token. Tokens rotated from one another form
+// a family whose ID is the ID of the first token, issued at login.
+type RefreshToken struct {
+	ID             uuid.UUID  `json:"id"` // JWT token ID (JTI claim)
+	FamilyID       uuid.UUID  `json:"familyId"`
+	ParentID       *uuid.UUID `json:"parentId,omitempty"`
+	UserID         uuid.UUID  `json:"userId"`
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	TokenHash      string     `json:"-"` // Never expose in JSON
+	IPAddress      *string    `json:"ipAddress,omitempty"`
+	UserAgent      *string    `json:"userAgent,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	RotatedAt      *time.Time `json:"rotatedAt,omitempty"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty"`
+	RevokeReason   *string    `json:"revokeReason,omitempty"`
+}
+
+// UserSession is an active refresh token family: one signed-in device
+type UserSession struct {
+	ID           uuid.UUID `json:"id"` // Family ID
+	DeviceName   string    `json:"deviceName"`
+	IPAddress    *string   `json:"ipAddress,omitempty"`
+	UserAgent    *string   `json:"userAgent,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`    // Login time
+	LastActiveAt time.Time `json:"lastActiveAt"` // Last refresh
+	ExpiresAt    time.Time `json:"expiresAt"`
+	Current      bool      `json:"current"`
+}
+
+// RefreshTokenRepository defines the interface for refresh token persistence
+type RefreshTokenRepository interface {
+	// Create stores a new refresh token
+	Create(token *RefreshToken) error
+
+	// GetByID retrieves a token by its JWT token ID, or nil if unknown
+	GetByID(id uuid.UUID) (*RefreshToken, error)
+
+	// MarkRotated marks a token as rotated. It returns false if the token was already rotated
+	// or revoked, so only one caller can rotate a token.
+	MarkRotated(id uuid.UUID, at time.Time) (bool, error)
+
+	// RevokeFamily revokes every token of a family
+	RevokeFamily(familyID uuid.UUID, reason string, at time.Time) error
+
+	// IsFamilyActive returns true if the family has an unrevoked, unexpired token left
+	IsFamilyActive(familyID uuid.UUID) (bool, error)
+
+	// ListSessions returns a user's active families, most recently used first
+	ListSessions(userID uuid.UUID) ([]*UserSession, error)
+
+	// DeleteExpired removes families whose tokens have all expired (cleanup job)
+	DeleteExpired(before time.Time) (int64, error)
+}
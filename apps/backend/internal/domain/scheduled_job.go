@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledJobTrigger describes what started a job run
+type ScheduledJobTrigger string
+
+const (
+	ScheduledJobTriggerScheduled ScheduledJobTrigger = "scheduled"
+	ScheduledJobTriggerManual    ScheduledJobTrigger = "manual"
+)
+
+// ScheduledJobRunStatus represents the outcome of a job run
+type ScheduledJobRunStatus string
+
+const (
+	ScheduledJobRunRunning   ScheduledJobRunStatus = "running"
+	ScheduledJobRunSucceeded ScheduledJobRunStatus = "succeeded"
+	ScheduledJobRunFailed    ScheduledJobRunStatus = "failed"
+)
+
+// ScheduledJobRun is one execution of a scheduled maintenance job
+type ScheduledJobRun struct {
+	ID           uuid.UUID             `json:"id"`
+	JobName      string                `json:"job_name"`
+	Trigger      ScheduledJobTrigger   `json:"trigger"`
+	TriggeredBy  *uuid.UUID            `json:"triggered_by,omitempty"`
+	Status       ScheduledJobRunStatus `json:"status"`
+	ScheduledFor *time.Time            `json:"scheduled_for,omitempty"`
+	StartedAt    time.Time             `json:"started_at"`
+	FinishedAt   *time.Time            `json:"finished_at,omitempty"`
+	DurationMs   *int64                `json:"duration_ms,omitempty"`
+	Error        string                `json:"error,omitempty"`
+	InstanceID   string                `json:"instance_id"`
+}
+
+// ScheduledJobRepository defines the interface for job run history and leader election
+type ScheduledJobRepository interface {
+	// TryLock acquires the cluster-wide lock for a job. When acquired, the returned
+	// function must be called to release it.
+	TryLock(ctx context.Context, jobName string) (unlock func(), acquired bool, err error)
+	CreateRun(run *ScheduledJobRun) error
+	FinishRun(run *ScheduledJobRun) error
+	GetRunsByJob(jobName string, limit, offset int) ([]*ScheduledJobRun, error)
+	GetLastRun(jobName string) (*ScheduledJobRun, error)
+	HasScheduledRun(jobName string, scheduledFor time.Time) (bool, error)
+}
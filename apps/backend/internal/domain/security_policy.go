@@ -27,6 +27,18 @@ const (
 	EnforcementAllow         EnforcementAction = "allow"           // Permit action, no alert
 )
 
+// Strictness ranks enforcement actions so inherited policies can only be tightened;
+// unknown actions are treated as blocking
+func (a EnforcementAction) Strictness() int {
+	switch a {
+	case EnforcementAllow:
+		return 0
+	case EnforcementAlertOnly:
+		return 1
+	}
+	return 2
+}
+
 // SecurityPolicy represents a configurable security policy
 type SecurityPolicy struct {
 	ID               uuid.UUID         `json:"id"`
@@ -49,6 +61,10 @@ type SecurityPolicy struct {
 	IsEnabled bool      `json:"is_enabled"`
 	Priority  int       `json:"priority"` // Higher priority policies evaluated first
 
+	// Hierarchy
+	Inheritable bool `json:"inheritable"` // Also applies to descendant organizations
+	Inherited   bool `json:"inherited"`   // Set when listed for a descendant of the owning organization
+
 	// Timestamps
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SIEMSinkType is the delivery protocol of a SIEM sink
+type SIEMSinkType string
+
+const (
+	SIEMSinkSyslog     SIEMSinkType = "syslog"      // RFC 5424 over TCP or TLS (RFC 6587 octet counting)
+	SIEMSinkSplunkHEC  SIEMSinkType = "splunk_hec"  // Splunk HTTP Event Collector
+	SIEMSinkHTTPNDJSON SIEMSinkType = "http_ndjson" // Newline-delimited JSON POSTed over HTTP(S)
+)
+
+// SIEMMessageFormat is the message body format used by syslog sinks
+type SIEMMessageFormat string
+
+const (
+	SIEMFormatCEF  SIEMMessageFormat = "cef"  // ArcSight Common Event Format
+	SIEMFormatLEEF SIEMMessageFormat = "leef" // IBM QRadar Log Event Extended Format
+	SIEMFormatJSON SIEMMessageFormat = "json"
+)
+
+// SIEMEventType is the category of a forwarded event
+type SIEMEventType string
+
+const (
+	SIEMEventAudit        SIEMEventType = "audit"
+	SIEMEventAlert        SIEMEventType = "alert"
+	SIEMEventVerification SIEMEventType = "verification"
+)
+
+// SIEMSink is an organization's SIEM destination
+type SIEMSink struct {
+	ID             uuid.UUID         `json:"id"`
+	OrganizationID uuid.UUID         `json:"organization_id"`
+	Name           string            `json:"name"`
+	Type           SIEMSinkType      `json:"type"`
+	Endpoint       string            `json:"endpoint"`              // host:port for syslog, URL for HTTP sinks
+	Format         SIEMMessageFormat `json:"format,omitempty"`      // Syslog only
+	UseTLS         bool              `json:"use_tls"`               // Syslog only; HTTP sinks follow the URL scheme
+	TLSCACert      string            `json:"tls_ca_cert,omitempty"` // Optional PEM CA bundle for private CAs
+	Token          string            `json:"-"`                     // HEC token or bearer token
+	EventTypes     []SIEMEventType   `json:"event_types"`           // Empty means all
+	MinSeverity    int               `json:"min_severity"`          // 0-10 (CEF scale)
+	BufferSize     int               `json:"buffer_size"`
+	IsActive       bool              `json:"is_active"`
+	CreatedBy      uuid.UUID         `json:"created_by"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// Accepts reports whether the sink subscribes to an event
+func (s *SIEMSink) Accepts(event *SIEMEvent) bool {
+	if event.Severity < s.MinSeverity {
+		return false
+	}
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, eventType := range s.EventTypes {
+		if eventType == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// SIEMEvent is a normalized audit record, alert or verification event
+type SIEMEvent struct {
+	ID             uuid.UUID              `json:"id"`
+	OrganizationID uuid.UUID              `json:"organization_id"`
+	Type           SIEMEventType          `json:"type"`
+	Name           string                 `json:"name"`     // Audit action, alert type or verification status
+	Severity       int                    `json:"severity"` // 0-10 (CEF scale)
+	Message        string                 `json:"message"`
+	Timestamp      time.Time              `json:"timestamp"`
+	ActorID        *uuid.UUID             `json:"actor_id,omitempty"`
+	ActorType      string                 `json:"actor_type,omitempty"` // user, agent or system
+	ResourceType   string                 `json:"resource_type,omitempty"`
+	ResourceID     string                 `json:"resource_id,omitempty"`
+	SourceIP       string                 `json:"source_ip,omitempty"`
+	Data           map[string]interface{} `json:"data,omitempty"`
+}
+
+// SIEMSinkRepository defines the interface for SIEM sink persistence
+type SIEMSinkRepository interface {
+	Create(sink *SIEMSink) error
+	GetByID(id uuid.UUID) (*SIEMSink, error)
+	GetByOrganization(orgID uuid.UUID) ([]*SIEMSink, error)
+	ListActive() ([]*SIEMSink, error)
+	Update(sink *SIEMSink) error
+	Delete(id uuid.UUID) error
+}
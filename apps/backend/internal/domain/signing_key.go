@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// SigningKeyStatus is the lifecycle state of a user token signing key
+type SigningKeyStatus string
+
+const (
+	// SigningKeyStatusNext keys are published ahead of use so verifiers cache them before they sign anything
+	SigningKeyStatusNext SigningKeyStatus = "next"
+	// SigningKeyStatusActive is the single key that signs new tokens
+	SigningKeyStatusActive SigningKeyStatus = "active"
+	// SigningKeyStatusRetired keys no longer sign but stay published until the tokens they signed expire
+	SigningKeyStatusRetired SigningKeyStatus = "retired"
+)
+
+// SigningKey is an asymmetric key that signs user access and refresh tokens
+type SigningKey struct {
+	ID                  string           `json:"kid"` // RFC 7638 JWK thumbprint
+	Algorithm           string           `json:"alg"` // EdDSA or ES256
+	PublicKey           string           `json:"public_key"`
+	EncryptedPrivateKey string           `json:"-"`
+	Status              SigningKeyStatus `json:"status"`
+	CreatedAt           time.Time        `json:"created_at"`
+	ActivatedAt         *time.Time       `json:"activated_at,omitempty"`
+	RetiredAt           *time.Time       `json:"retired_at,omitempty"`
+	ExpiresAt           *time.Time       `json:"expires_at,omitempty"` // Retired keys are removed after this
+}
+
+// SigningKeyRepository persists user token signing keys
+type SigningKeyRepository interface {
+	Create(key *SigningKey) error
+	// List returns every key that has not expired, oldest first
+	List() ([]*SigningKey, error)
+	// Promote makes a next key active and retires the current active key until retiredExpiresAt
+	Promote(id string, at, retiredExpiresAt time.Time) error
+	DeleteExpired(before time.Time) (int64, error)
+}
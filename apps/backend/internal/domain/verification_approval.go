@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationApprovalStatus is the state of a verification parked for human approval
+type VerificationApprovalStatus string
+
+const (
+	VerificationApprovalPending  VerificationApprovalStatus = "pending"
+	VerificationApprovalApproved VerificationApprovalStatus = "approved"
+	VerificationApprovalDenied   VerificationApprovalStatus = "denied"
+	VerificationApprovalTimeout  VerificationApprovalStatus = "timeout" // Nobody decided before ExpiresAt
+)
+
+// VerificationApprovalChannel records how an approver reached the decision
+type VerificationApprovalChannel string
+
+const (
+	VerificationApprovalViaDashboard VerificationApprovalChannel = "dashboard"
+	VerificationApprovalViaLink      VerificationApprovalChannel = "link" // Signed one-click link from the notification email
+)
+
+// VerificationApproval is a high-risk agent action waiting for a human to approve or deny it.
+// Its ID is the verification ID returned to the SDK, which polls or receives a callback.
+type VerificationApproval struct {
+	ID                  uuid.UUID                   `json:"id"`
+	OrganizationID      uuid.UUID                   `json:"organization_id"`
+	AgentID             uuid.UUID                   `json:"agent_id"`
+	AgentName           string                      `json:"agent_name,omitempty"`
+	VerificationEventID *uuid.UUID                  `json:"verification_event_id,omitempty"`
+	ActionType          string                      `json:"action_type"`
+	Resource            string                      `json:"resource,omitempty"`
+	Context             map[string]interface{}      `json:"context,omitempty"`
+	TrustScore          float64                     `json:"trust_score"`
+	Reason              string                      `json:"reason"` // Why the action needs approval
+	Status              VerificationApprovalStatus  `json:"status"`
+	DecidedBy           *uuid.UUID                  `json:"decided_by,omitempty"`
+	DecidedByEmail      string                      `json:"decided_by_email,omitempty"`
+	DecidedVia          VerificationApprovalChannel `json:"decided_via,omitempty"`
+	DecisionReason      string                      `json:"decision_reason,omitempty"`
+	CallbackURL         string                      `json:"callback_url,omitempty"` // Notified once the request is decided or times out
+	ExpiresAt           time.Time                   `json:"expires_at"`
+	DecidedAt           *time.Time                  `json:"decided_at,omitempty"`
+	CreatedAt           time.Time                   `json:"created_at"`
+}
+
+// VerificationApprovalRepository defines the interface for verification approval persistence
+type VerificationApprovalRepository interface {
+	Create(approval *VerificationApproval) error
+	GetByID(id uuid.UUID) (*VerificationApproval, error)
+	List(orgID uuid.UUID, status *VerificationApprovalStatus, limit, offset int) ([]*VerificationApproval, int, error)
+	// Decide records the outcome of a pending approval; it fails if the approval was already
+	// decided or, unless the outcome is a timeout, has expired
+	Decide(approval *VerificationApproval) error
+	// ListExpired returns pending approvals of every organization whose deadline has passed
+	ListExpired(now time.Time) ([]*VerificationApproval, error)
+	AttachVerificationEvent(id, eventID uuid.UUID) error
+}
@@ -16,6 +16,8 @@ const (
 	WebhookEventTrustScoreChanged WebhookEvent = "trust_score.changed"
 	WebhookEventAlertCreated      WebhookEvent = "alert.created"
 	WebhookEventComplianceViolation WebhookEvent = "compliance.violation"
+	WebhookEventVerificationApprovalRequested WebhookEvent = "verification.approval_requested"
+	WebhookEventVerificationApprovalDecided   WebhookEvent = "verification.approval_decided"
 )
 
 // Webhook represents a webhook subscription
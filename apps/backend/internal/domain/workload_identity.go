@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SPIFFEBinding lets workloads whose SPIFFE ID matches a pattern authenticate as an agent
+type SPIFFEBinding struct {
+	ID              uuid.UUID  `json:"id"`
+	AgentID         uuid.UUID  `json:"agentId"`
+	OrganizationID  uuid.UUID  `json:"organizationId"`
+	SPIFFEIDPattern string     `json:"spiffeIdPattern"` // "*" matches within one path segment
+	TrustDomain     string     `json:"trustDomain"`
+	CreatedBy       *uuid.UUID `json:"createdBy,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// AgentCertificate is an X.509-SVID issued to an agent by AIM's CA
+type AgentCertificate struct {
+	ID             uuid.UUID `json:"id"`
+	AgentID        uuid.UUID `json:"agentId"`
+	OrganizationID uuid.UUID `json:"organizationId"`
+	SerialNumber   string    `json:"serialNumber"`
+	SPIFFEID       string    `json:"spiffeId"`
+	CertificatePEM string    `json:"certificatePem"`
+	NotBefore      time.Time `json:"notBefore"`
+	NotAfter       time.Time `json:"notAfter"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// SPIFFEBindingRepository defines the interface for SPIFFE binding persistence
+type SPIFFEBindingRepository interface {
+	// Create stores a binding
+	Create(binding *SPIFFEBinding) error
+
+	// ListByAgent returns an agent's bindings
+	ListByAgent(agentID uuid.UUID) ([]*SPIFFEBinding, error)
+
+	// ListByTrustDomain returns every binding in a trust domain
+	ListByTrustDomain(trustDomain string) ([]*SPIFFEBinding, error)
+
+	// Delete removes a binding
+	Delete(id uuid.UUID) error
+}
+
+// AgentCertificateRepository defines the interface for issued agent certificate persistence
+type AgentCertificateRepository interface {
+	// Create stores an issued certificate
+	Create(certificate *AgentCertificate) error
+
+	// GetLatestByAgent returns the agent's most recently issued certificate, or nil if it has none
+	GetLatestByAgent(agentID uuid.UUID) (*AgentCertificate, error)
+
+	// DeleteExpired removes certificates that expired before the given time
+	DeleteExpired(before time.Time) (int64, error)
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"crypto"
 	"fmt"
 	"os"
 	"time"
@@ -15,14 +16,46 @@ type JWTClaims struct {
 	OrganizationID string `json:"organization_id"`
 	Email          string `json:"email"`
 	Role           string `json:"role"`
+	SessionID      string `json:"sid,omitempty"` // Refresh token family of a user session
 	jwt.RegisteredClaims
 }
 
+// SigningKeySource supplies the asymmetric keys user tokens are signed and verified with
+type SigningKeySource interface {
+	// CurrentSigningKey returns the ID, algorithm and private key of the key that signs new tokens
+	CurrentSigningKey() (kid, alg string, key crypto.Signer, err error)
+	// VerificationKey returns the algorithm and public key of a published key
+	VerificationKey(kid string) (alg string, key crypto.PublicKey, err error)
+	// SigningSince returns when the oldest published key started signing; zero if none has
+	SigningSince() time.Time
+}
+
+// SessionChecker reports whether a user session (refresh token family) is still signed in
+type SessionChecker interface {
+	IsSessionActive(sessionID string) (bool, error)
+}
+
+// defaultIssuer is the "iss" of user tokens until an issuer URL is configured
+const defaultIssuer = "agent-identity-management"
+
+// sdkIssuer is the "iss" of refresh tokens embedded in downloaded SDKs
+const sdkIssuer = "agent-identity-management-sdk"
+
+// sdkRefreshExpiry is the lifetime of refresh tokens embedded in downloaded SDKs
+const sdkRefreshExpiry = 90 * 24 * time.Hour // 90 days (reduced from 365 for security)
+
 // JWTService handles JWT operations
 type JWTService struct {
 	secret         []byte
 	accessExpiry   time.Duration
 	refreshExpiry  time.Duration
+	issuer         string
+	// keys signs tokens asymmetrically (with a "kid" header) when set; otherwise tokens are
+	// signed with the HMAC secret
+	keys             SigningKeySource
+	acceptLegacyHMAC bool
+	// sessions rejects tokens of signed-out sessions when set
+	sessions SessionChecker
 }
 
 // NewJWTService creates a new JWT service
@@ -40,7 +73,105 @@ func NewJWTService() *JWTService {
 		secret:        []byte(secret),
 		accessExpiry:  accessExpiry,
 		refreshExpiry: refreshExpiry,
+		issuer:        defaultIssuer,
+	}
+}
+
+// UseSigningKeys switches to asymmetric signing with published keys so other services can
+// verify tokens without the shared secret. acceptLegacyHMAC keeps HS256 tokens issued before
+// the switch valid until they expire; HS256 tokens claiming to be issued later are rejected.
+func (s *JWTService) UseSigningKeys(keys SigningKeySource, issuer string, acceptLegacyHMAC bool) {
+	s.keys = keys
+	if issuer != "" {
+		s.issuer = issuer
+	}
+	s.acceptLegacyHMAC = acceptLegacyHMAC
+}
+
+// UseSessionChecker rejects tokens whose session has been revoked, so signing a device out also
+// invalidates its access tokens
+func (s *JWTService) UseSessionChecker(sessions SessionChecker) {
+	s.sessions = sessions
+}
+
+// Issuer returns the "iss" of user access and refresh tokens
+func (s *JWTService) Issuer() string {
+	return s.issuer
+}
+
+// MaxTokenLifetime returns the longest lifetime of any token this service issues. A signing
+// key must stay published this long after it stops signing.
+func (s *JWTService) MaxTokenLifetime() time.Duration {
+	lifetime := sdkRefreshExpiry
+	for _, expiry := range []time.Duration{s.accessExpiry, s.refreshExpiry} {
+		if expiry > lifetime {
+			lifetime = expiry
+		}
+	}
+	return lifetime
+}
+
+// sign signs claims with the active signing key, or the HMAC secret when none is configured
+func (s *JWTService) sign(claims JWTClaims) (string, error) {
+	if s.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString(s.secret)
+	}
+
+	kid, alg, key, err := s.keys.CurrentSigningKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to get signing key: %w", err)
+	}
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return "", fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
+}
+
+// keyFunc selects the verification key of a token: the published key named by its "kid", or
+// the HMAC secret for tokens without one
+func (s *JWTService) keyFunc(token *jwt.Token) (interface{}, error) {
+	if kid, ok := token.Header["kid"].(string); ok && s.keys != nil {
+		alg, key, err := s.keys.VerificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != alg {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return key, nil
+	}
+
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || (s.keys != nil && !s.acceptLegacyHMAC) {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	if s.keys != nil && !s.isLegacyHMACToken(token) {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return s.secret, nil
+}
+
+// isLegacyHMACToken reports whether an HS256 token could have been issued before asymmetric
+// signing started: it must claim an earlier issue time and no longer a lifetime than this
+// service issues, so every legacy token has expired one token lifetime after the switch
+func (s *JWTService) isLegacyHMACToken(token *jwt.Token) bool {
+	since := s.keys.SigningSince()
+	if since.IsZero() {
+		return false
 	}
+	issuedAt, err := token.Claims.GetIssuedAt()
+	if err != nil || issuedAt == nil || !issuedAt.Before(since) {
+		return false
+	}
+	expiresAt, err := token.Claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return false
+	}
+	return !expiresAt.After(issuedAt.Add(s.MaxTokenLifetime()))
 }
 
 // getEnv is a helper function to get env var with fallback
@@ -56,25 +187,22 @@ func getEnv(key, fallback string) string {
 // Security: Reduced from 1 year to 90 days to minimize exposure window
 func (s *JWTService) GenerateSDKRefreshToken(userID, orgID, email, role string) (string, error) {
 	now := time.Now()
-	sdkExpiry := 90 * 24 * time.Hour // 90 days (reduced from 365 for security)
-
 	claims := JWTClaims{
 		UserID:         userID,
 		OrganizationID: orgID,
 		Email:          email,
 		Role:           role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(sdkExpiry)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(sdkRefreshExpiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    "agent-identity-management-sdk",
+			Issuer:    sdkIssuer,
 			Subject:   userID,
 			ID:        uuid.New().String(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secret)
+	return s.sign(claims)
 }
 
 // GenerateTokenPair generates access and refresh tokens
@@ -94,26 +222,63 @@ func (s *JWTService) GenerateTokenPair(userID, orgID, email, role string) (acces
 	return accessToken, refreshToken, nil
 }
 
-// GenerateAccessToken generates an access token
-func (s *JWTService) GenerateAccessToken(userID, orgID, email, role string) (string, error) {
+// GenerateSessionTokenPair generates access and refresh tokens bound to a user session. The
+// refresh token's ID is refreshTokenID so it can be recorded before it is handed out.
+func (s *JWTService) GenerateSessionTokenPair(userID, orgID, email, role, sessionID, refreshTokenID string) (accessToken, refreshToken string, err error) {
 	now := time.Now()
+	accessToken, err = s.sign(s.accessClaims(userID, orgID, email, role, sessionID, now))
+	if err != nil {
+		return "", "", err
+	}
+
 	claims := JWTClaims{
+		UserID:         userID,
+		OrganizationID: orgID,
+		SessionID:      sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.refreshExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    s.issuer,
+			Subject:   userID,
+			ID:        refreshTokenID,
+		},
+	}
+	refreshToken, err = s.sign(claims)
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// RefreshTokenExpiry returns the lifetime of user session refresh tokens
+func (s *JWTService) RefreshTokenExpiry() time.Duration {
+	return s.refreshExpiry
+}
+
+// GenerateAccessToken generates an access token
+func (s *JWTService) GenerateAccessToken(userID, orgID, email, role string) (string, error) {
+	return s.sign(s.accessClaims(userID, orgID, email, role, "", time.Now()))
+}
+
+// accessClaims builds the claims of an access token
+func (s *JWTService) accessClaims(userID, orgID, email, role, sessionID string, now time.Time) JWTClaims {
+	return JWTClaims{
 		UserID:         userID,
 		OrganizationID: orgID,
 		Email:          email,
 		Role:           role,
+		SessionID:      sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessExpiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    "agent-identity-management",
+			Issuer:    s.issuer,
 			Subject:   userID,
 			ID:        uuid.New().String(),
 		},
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secret)
 }
 
 // GenerateRefreshToken generates a refresh token
@@ -126,34 +291,39 @@ func (s *JWTService) GenerateRefreshToken(userID, orgID string) (string, error)
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.refreshExpiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    "agent-identity-management",
+			Issuer:    s.issuer,
 			Subject:   userID,
 			ID:        uuid.New().String(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secret)
+	return s.sign(claims)
 }
 
 // ValidateToken validates and parses a JWT token
 func (s *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return s.secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, s.keyFunc)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	if claims.SessionID != "" && s.sessions != nil {
+		active, err := s.sessions.IsSessionActive(claims.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check session: %w", err)
+		}
+		if !active {
+			return nil, fmt.Errorf("session has been signed out")
+		}
 	}
 
-	return nil, fmt.Errorf("invalid token")
+	return claims, nil
 }
 
 // RefreshAccessToken generates a new access token from a refresh token
@@ -179,7 +349,7 @@ func (s *JWTService) RefreshTokenPair(refreshToken string) (string, string, erro
 	}
 
 	// Check if this is an SDK token (different issuer)
-	isSDKToken := claims.Issuer == "agent-identity-management-sdk"
+	isSDKToken := claims.Issuer == sdkIssuer
 
 	var newAccessToken, newRefreshToken string
 
@@ -205,9 +375,7 @@ func (s *JWTService) RefreshTokenPair(refreshToken string) (string, string, erro
 // GetTokenID extracts the JTI (token ID) from a JWT without full validation
 // Useful for token revocation checks before full validation
 func (s *JWTService) GetTokenID(tokenString string) (string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return s.secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, s.keyFunc)
 
 	if err != nil {
 		return "", err
@@ -66,6 +66,9 @@ func (r *TemplateRenderer) loadEmbeddedTemplates() error {
 		domain.TemplateAPIKeyCreated,
 		domain.TemplateAPIKeyExpiring,
 		domain.TemplateAPIKeyRevoked,
+		domain.TemplateAccessReviewReminder,
+		domain.TemplateVerificationApprovalRequest,
+		domain.TemplateSessionRevoked,
 	}
 
 	for _, name := range templateNames {
@@ -178,22 +181,25 @@ func (r *TemplateRenderer) getDefaultTemplate(name domain.EmailTemplate) string
 // getDefaultSubject returns a simple default subject if file doesn't exist
 func (r *TemplateRenderer) getDefaultSubject(name domain.EmailTemplate) string {
 	subjects := map[domain.EmailTemplate]string{
-		domain.TemplateWelcome:              "Welcome to Agent Identity Management",
-		domain.TemplateUserApproved:         "Your account has been approved",
-		domain.TemplateUserRejected:         "Account registration update",
-		domain.TemplatePasswordReset:        "Reset your password",
-		domain.TemplateAgentRegistered:      "Agent registered successfully",
-		domain.TemplateAgentVerified:        "Agent verified successfully",
-		domain.TemplateVerificationReminder: "Agent verification required",
-		domain.TemplateVerificationFailed:   "Agent verification failed",
-		domain.TemplateAlertCritical:        "🚨 Critical Alert",
-		domain.TemplateAlertWarning:         "⚠️ Warning Alert",
-		domain.TemplateAlertInfo:            "ℹ️ Information Alert",
-		domain.TemplateMCPServerRegistered:  "MCP Server registered successfully",
-		domain.TemplateMCPServerExpiring:    "MCP Server certificate expiring soon",
-		domain.TemplateAPIKeyCreated:        "New API key created",
-		domain.TemplateAPIKeyExpiring:       "API key expiring soon",
-		domain.TemplateAPIKeyRevoked:        "API key revoked",
+		domain.TemplateWelcome:                     "Welcome to Agent Identity Management",
+		domain.TemplateUserApproved:                "Your account has been approved",
+		domain.TemplateUserRejected:                "Account registration update",
+		domain.TemplatePasswordReset:               "Reset your password",
+		domain.TemplateAgentRegistered:             "Agent registered successfully",
+		domain.TemplateAgentVerified:               "Agent verified successfully",
+		domain.TemplateVerificationReminder:        "Agent verification required",
+		domain.TemplateVerificationFailed:          "Agent verification failed",
+		domain.TemplateAlertCritical:               "🚨 Critical Alert",
+		domain.TemplateAlertWarning:                "⚠️ Warning Alert",
+		domain.TemplateAlertInfo:                   "ℹ️ Information Alert",
+		domain.TemplateMCPServerRegistered:         "MCP Server registered successfully",
+		domain.TemplateMCPServerExpiring:           "MCP Server certificate expiring soon",
+		domain.TemplateAPIKeyCreated:               "New API key created",
+		domain.TemplateAPIKeyExpiring:              "API key expiring soon",
+		domain.TemplateAPIKeyRevoked:               "API key revoked",
+		domain.TemplateAccessReviewReminder:        "Access review awaiting your decision",
+		domain.TemplateVerificationApprovalRequest: "Agent action awaiting your approval",
+		domain.TemplateSessionRevoked:              "A session was signed out for your security",
 	}
 
 	if subject, ok := subjects[name]; ok {
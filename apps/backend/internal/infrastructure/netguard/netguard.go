@@ -0,0 +1,132 @@
+// Package netguard keeps outbound connections to customer-configured endpoints (SIEM sinks,
+// approval callbacks) away from AIM's own network: loopback, private, link-local (including
+// cloud metadata services) and other special-purpose addresses are refused unless an operator
+// allows them explicitly.
+package netguard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+)
+
+// blockedNetworks are special-purpose ranges the net.IP predicates do not cover
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "This" network
+	"100.64.0.0/10", // Carrier-grade NAT, also used by some cloud metadata services
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // Benchmarking
+	"240.0.0.0/4",   // Reserved
+	"64:ff9b::/96",  // NAT64, which maps onto IPv4 addresses
+)
+
+// Policy decides which addresses outbound connections may reach. The zero value and a nil
+// policy refuse every special-purpose address.
+type Policy struct {
+	allowed []*net.IPNet
+}
+
+// NewPolicy creates a policy that additionally allows the given networks (CIDRs or IPs),
+// e.g. a SIEM collector on the internal network
+func NewPolicy(allowedNetworks []string) (*Policy, error) {
+	policy := &Policy{}
+	for _, network := range allowedNetworks {
+		parsed, err := parseNetwork(network)
+		if err != nil {
+			return nil, err
+		}
+		policy.allowed = append(policy.allowed, parsed)
+	}
+	return policy, nil
+}
+
+// CheckIP returns an error if connections to the address are not allowed
+func (p *Policy) CheckIP(ip net.IP) error {
+	if ip == nil {
+		return fmt.Errorf("invalid address")
+	}
+	if p != nil {
+		for _, network := range p.allowed {
+			if network.Contains(ip) {
+				return nil
+			}
+		}
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("address %s is not allowed: internal and special-purpose addresses are blocked", ip)
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return fmt.Errorf("address %s is not allowed: internal and special-purpose addresses are blocked", ip)
+		}
+	}
+	return nil
+}
+
+// CheckHost returns an error if the host is, or resolves to, an address that is not allowed.
+// Hosts that do not resolve now are accepted; connections are checked again when dialed.
+func (p *Policy) CheckHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		return p.CheckIP(ip)
+	}
+	if host == "localhost" {
+		return p.CheckIP(net.IPv4(127, 0, 0, 1))
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	for _, address := range addresses {
+		if err := p.CheckIP(address.IP); err != nil {
+			return fmt.Errorf("%s resolves to a blocked address: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// Dialer returns a dialer that checks every address it connects to, after name resolution,
+// so that DNS answers cannot redirect a connection to a blocked address
+func (p *Policy) Dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			return p.CheckIP(net.ParseIP(host))
+		},
+	}
+}
+
+func parseNetwork(network string) (*net.IPNet, error) {
+	if _, parsed, err := net.ParseCIDR(network); err == nil {
+		return parsed, nil
+	}
+	ip := net.ParseIP(network)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid network %q: must be a CIDR or an IP address", network)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,59 @@
+package netguard
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_BlocksInternalAddresses(t *testing.T) {
+	policy, err := NewPolicy(nil)
+	require.NoError(t, err)
+
+	for _, blocked := range []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.100.100.200",
+		"0.0.0.0", "::1", "fd00::1", "fe80::1", "::ffff:127.0.0.1",
+	} {
+		assert.Error(t, policy.CheckIP(net.ParseIP(blocked)), blocked)
+	}
+	for _, public := range []string{"93.184.216.34", "2606:2800:220:1::1"} {
+		assert.NoError(t, policy.CheckIP(net.ParseIP(public)), public)
+	}
+	assert.Error(t, policy.CheckHost(context.Background(), "localhost"))
+
+	// A nil policy blocks the same addresses
+	var none *Policy
+	assert.Error(t, none.CheckIP(net.ParseIP("10.1.2.3")))
+
+	// Operators can allow internal collectors explicitly
+	allowing, err := NewPolicy([]string{"10.1.0.0/16", "192.168.1.1"})
+	require.NoError(t, err)
+	assert.NoError(t, allowing.CheckIP(net.ParseIP("10.1.2.3")))
+	assert.NoError(t, allowing.CheckIP(net.ParseIP("192.168.1.1")))
+	assert.Error(t, allowing.CheckIP(net.ParseIP("10.2.0.1")))
+
+	_, err = NewPolicy([]string{"not-a-network"})
+	assert.Error(t, err)
+}
+
+func TestPolicy_DialerChecksResolvedAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	blocking, err := NewPolicy(nil)
+	require.NoError(t, err)
+	_, err = blocking.Dialer(time.Second).Dial("tcp", server.Listener.Addr().String())
+	assert.Error(t, err)
+
+	allowing, err := NewPolicy([]string{"127.0.0.0/8"})
+	require.NoError(t, err)
+	conn, err := allowing.Dialer(time.Second).Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	conn.Close()
+}
@@ -0,0 +1,180 @@
+// Package parquet streams flat Apache Parquet files of strings, integers and timestamps on top of
+// github.com/parquet-go/parquet-go. Rows are buffered per row group, so memory use is bounded by
+// the row group size regardless of the total number of rows written.
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	parquetgo "github.com/parquet-go/parquet-go"
+)
+
+// ColumnType is the logical type of a column
+type ColumnType int
+
+const (
+	String          ColumnType = iota // BYTE_ARRAY annotated as STRING
+	Int64                             // INT64
+	TimestampMicros                   // INT64 annotated as TIMESTAMP(MICROS, UTC)
+)
+
+// Column describes a top-level column of the file schema
+type Column struct {
+	Name     string
+	Type     ColumnType
+	Optional bool
+}
+
+// DefaultRowGroupSize is the number of rows buffered before a row group is written
+const DefaultRowGroupSize = 10000
+
+// Writer writes rows to a Parquet file. Columns are stored in name order; readers look them up
+// by name.
+type Writer struct {
+	w            *parquetgo.Writer
+	columns      []Column
+	leafIndex    []int // File column index of each schema column
+	rowGroupSize int
+	rows         int
+	closed       bool
+}
+
+// NewWriter starts a Parquet file on w. rowGroupSize <= 0 uses DefaultRowGroupSize.
+func NewWriter(w io.Writer, columns []Column, rowGroupSize int) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("parquet schema must have at least one column")
+	}
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultRowGroupSize
+	}
+
+	group := parquetgo.Group{}
+	for _, column := range columns {
+		if _, exists := group[column.Name]; exists {
+			return nil, fmt.Errorf("duplicate parquet column %s", column.Name)
+		}
+		var node parquetgo.Node
+		switch column.Type {
+		case String:
+			node = parquetgo.String()
+		case Int64:
+			node = parquetgo.Leaf(parquetgo.Int64Type)
+		case TimestampMicros:
+			node = parquetgo.Timestamp(parquetgo.Microsecond)
+		default:
+			return nil, fmt.Errorf("column %s has unknown type %d", column.Name, column.Type)
+		}
+		if column.Optional {
+			node = parquetgo.Optional(node)
+		}
+		group[column.Name] = parquetgo.Compressed(node, &parquetgo.Gzip)
+	}
+	schema := parquetgo.NewSchema("schema", group)
+
+	leafIndex := make([]int, len(columns))
+	for i, column := range columns {
+		leaf, ok := schema.Lookup(column.Name)
+		if !ok {
+			return nil, fmt.Errorf("parquet column %s missing from schema", column.Name)
+		}
+		leafIndex[i] = leaf.ColumnIndex
+	}
+
+	return &Writer{
+		w:            parquetgo.NewWriter(w, schema, parquetgo.CreatedBy("AIM audit export", "", "")),
+		columns:      columns,
+		leafIndex:    leafIndex,
+		rowGroupSize: rowGroupSize,
+	}, nil
+}
+
+// WriteRow appends a row. Values must be given in column order: string or []byte for String,
+// int64 or int for Int64, time.Time for TimestampMicros, and nil for a null optional value.
+func (w *Writer) WriteRow(values ...interface{}) error {
+	if w.closed {
+		return fmt.Errorf("parquet writer is closed")
+	}
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("row has %d values, schema has %d columns", len(values), len(w.columns))
+	}
+
+	row := make(parquetgo.Row, len(w.columns))
+	for i, value := range values {
+		v, err := columnValue(w.columns[i], value)
+		if err != nil {
+			return err
+		}
+		definitionLevel := 0
+		if w.columns[i].Optional && !v.IsNull() {
+			definitionLevel = 1
+		}
+		row[w.leafIndex[i]] = v.Level(0, definitionLevel, w.leafIndex[i])
+	}
+	if _, err := w.w.WriteRows([]parquetgo.Row{row}); err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows >= w.rowGroupSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+func columnValue(column Column, value interface{}) (parquetgo.Value, error) {
+	if value == nil {
+		if !column.Optional {
+			return parquetgo.Value{}, fmt.Errorf("column %s is required", column.Name)
+		}
+		return parquetgo.NullValue(), nil
+	}
+
+	switch column.Type {
+	case String:
+		switch v := value.(type) {
+		case string:
+			return parquetgo.ByteArrayValue([]byte(v)), nil
+		case []byte:
+			return parquetgo.ByteArrayValue(v), nil
+		}
+		return parquetgo.Value{}, fmt.Errorf("column %s expects a string, got %T", column.Name, value)
+	case Int64:
+		switch v := value.(type) {
+		case int64:
+			return parquetgo.Int64Value(v), nil
+		case int:
+			return parquetgo.Int64Value(int64(v)), nil
+		}
+		return parquetgo.Value{}, fmt.Errorf("column %s expects an integer, got %T", column.Name, value)
+	case TimestampMicros:
+		t, ok := value.(time.Time)
+		if !ok {
+			return parquetgo.Value{}, fmt.Errorf("column %s expects a time.Time, got %T", column.Name, value)
+		}
+		return parquetgo.Int64Value(t.UnixMicro()), nil
+	}
+	return parquetgo.Value{}, fmt.Errorf("column %s has unknown type %d", column.Name, column.Type)
+}
+
+// Flush writes the buffered rows as a row group
+func (w *Writer) Flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	w.rows = 0
+	return nil
+}
+
+// Close flushes buffered rows and writes the file footer. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.w.Close()
+}
@@ -0,0 +1,83 @@
+package parquet
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	parquetgo "github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRow struct {
+	ID             string    `parquet:"id"`
+	SequenceNumber *int64    `parquet:"sequence_number,optional"`
+	Timestamp      time.Time `parquet:"timestamp,timestamp(microsecond)"`
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	columns := []Column{
+		{Name: "id", Type: String},
+		{Name: "sequence_number", Type: Int64, Optional: true},
+		{Name: "timestamp", Type: TimestampMicros},
+	}
+
+	var out bytes.Buffer
+	w, err := NewWriter(&out, columns, 2)
+	require.NoError(t, err)
+
+	ts := time.Date(2026, 10, 18, 12, 0, 0, 123456000, time.UTC)
+	require.NoError(t, w.WriteRow("a", int64(1), ts))
+	require.NoError(t, w.WriteRow("b,\"quoted\"\n", nil, ts))
+	require.NoError(t, w.WriteRow("c", 3, ts.Add(time.Second)))
+	require.Error(t, w.WriteRow(nil, int64(4), ts), "required column cannot be null")
+	require.NoError(t, w.Close())
+
+	data := out.Bytes()
+	file, err := parquetgo.OpenFile(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), file.NumRows())
+	assert.Len(t, file.RowGroups(), 2)
+
+	seq, ok := file.Schema().Lookup("sequence_number")
+	require.True(t, ok)
+	assert.Equal(t, 1, seq.MaxDefinitionLevel, "sequence_number is optional")
+	for _, group := range file.Metadata().RowGroups {
+		for _, chunk := range group.Columns {
+			assert.Equal(t, format.Gzip, chunk.MetaData.Codec)
+		}
+	}
+
+	rows, err := parquetgo.Read[testRow](bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, "a", rows[0].ID)
+	require.NotNil(t, rows[0].SequenceNumber)
+	assert.Equal(t, int64(1), *rows[0].SequenceNumber)
+	assert.True(t, ts.Equal(rows[0].Timestamp))
+
+	assert.Equal(t, "b,\"quoted\"\n", rows[1].ID)
+	assert.Nil(t, rows[1].SequenceNumber)
+
+	assert.Equal(t, "c", rows[2].ID)
+	require.NotNil(t, rows[2].SequenceNumber)
+	assert.Equal(t, int64(3), *rows[2].SequenceNumber)
+	assert.True(t, ts.Add(time.Second).Equal(rows[2].Timestamp))
+}
+
+func TestWriter_RejectsMismatchedRows(t *testing.T) {
+	w, err := NewWriter(&bytes.Buffer{}, []Column{{Name: "id", Type: String}, {Name: "n", Type: Int64}}, 0)
+	require.NoError(t, err)
+
+	assert.Error(t, w.WriteRow("a"), "too few values")
+	assert.Error(t, w.WriteRow(1, int64(1)), "wrong type for a string column")
+	assert.Error(t, w.WriteRow("a", "1"), "wrong type for an integer column")
+	require.NoError(t, w.Close())
+	assert.Error(t, w.WriteRow("a", int64(1)), "closed writer")
+
+	_, err = NewWriter(&bytes.Buffer{}, nil, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,439 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+type AccessReviewRepository struct {
+	db *sql.DB
+}
+
+func NewAccessReviewRepository(db *sql.DB) *AccessReviewRepository {
+	return &AccessReviewRepository{db: db}
+}
+
+func (r *AccessReviewRepository) CreateCampaign(campaign *domain.AccessReviewCampaign, items []*domain.AccessReviewItem) error {
+	if campaign.ID == uuid.Nil {
+		campaign.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	campaign.CreatedAt = now
+
+	scope := make([]string, len(campaign.Scope))
+	for i, itemType := range campaign.Scope {
+		scope[i] = string(itemType)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO access_review_campaigns (
+			id, organization_id, name, description, scope, default_reviewer_id, assign_agent_owners,
+			auto_revoke_on_expiry, reminder_days_before, due_at, status, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`,
+		campaign.ID,
+		campaign.OrganizationID,
+		campaign.Name,
+		sql.NullString{String: campaign.Description, Valid: campaign.Description != ""},
+		pq.Array(scope),
+		campaign.DefaultReviewerID,
+		campaign.AssignAgentOwners,
+		campaign.AutoRevokeOnExpiry,
+		campaign.ReminderDaysBefore,
+		campaign.DueAt,
+		campaign.Status,
+		campaign.CreatedBy,
+		campaign.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create access review campaign: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO access_review_items (
+			id, campaign_id, organization_id, item_type, resource_id, agent_id,
+			subject, access, reviewer_id, decision, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare access review items: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, item := range items {
+		if item.ID == uuid.Nil {
+			item.ID = uuid.New()
+		}
+		item.CampaignID = campaign.ID
+		item.OrganizationID = campaign.OrganizationID
+		item.CreatedAt = now
+
+		if _, err := stmt.Exec(
+			item.ID,
+			item.CampaignID,
+			item.OrganizationID,
+			item.ItemType,
+			item.ResourceID,
+			item.AgentID,
+			item.Subject,
+			item.Access,
+			item.ReviewerID,
+			item.Decision,
+			item.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to create access review item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Campaign columns include item counts aggregated from access_review_items
+const accessReviewCampaignColumns = `
+	c.id, c.organization_id, c.name, c.description, c.scope, c.default_reviewer_id,
+	c.assign_agent_owners, c.auto_revoke_on_expiry, c.reminder_days_before, c.due_at,
+	c.status, c.created_by, c.created_at, c.completed_at,
+	(SELECT COUNT(*) FROM access_review_items i WHERE i.campaign_id = c.id),
+	(SELECT COUNT(*) FROM access_review_items i WHERE i.campaign_id = c.id AND i.decision = 'pending'),
+	(SELECT COUNT(*) FROM access_review_items i WHERE i.campaign_id = c.id AND i.decision = 'certified'),
+	(SELECT COUNT(*) FROM access_review_items i WHERE i.campaign_id = c.id AND i.decision = 'revoked')
+`
+
+func scanAccessReviewCampaign(row rowScanner) (*domain.AccessReviewCampaign, error) {
+	campaign := &domain.AccessReviewCampaign{}
+	var description sql.NullString
+	var scope []string
+
+	if err := row.Scan(
+		&campaign.ID,
+		&campaign.OrganizationID,
+		&campaign.Name,
+		&description,
+		pq.Array(&scope),
+		&campaign.DefaultReviewerID,
+		&campaign.AssignAgentOwners,
+		&campaign.AutoRevokeOnExpiry,
+		&campaign.ReminderDaysBefore,
+		&campaign.DueAt,
+		&campaign.Status,
+		&campaign.CreatedBy,
+		&campaign.CreatedAt,
+		&campaign.CompletedAt,
+		&campaign.TotalItems,
+		&campaign.PendingItems,
+		&campaign.CertifiedItems,
+		&campaign.RevokedItems,
+	); err != nil {
+		return nil, err
+	}
+
+	campaign.Description = description.String
+	campaign.Scope = make([]domain.AccessReviewItemType, len(scope))
+	for i, itemType := range scope {
+		campaign.Scope[i] = domain.AccessReviewItemType(itemType)
+	}
+
+	return campaign, nil
+}
+
+func (r *AccessReviewRepository) GetCampaign(id uuid.UUID) (*domain.AccessReviewCampaign, error) {
+	query := `SELECT ` + accessReviewCampaignColumns + ` FROM access_review_campaigns c WHERE c.id = $1`
+
+	campaign, err := scanAccessReviewCampaign(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("access review campaign not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access review campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+func (r *AccessReviewRepository) ListCampaigns(orgID uuid.UUID, status *domain.AccessReviewCampaignStatus, limit, offset int) ([]*domain.AccessReviewCampaign, int, error) {
+	var statusFilter sql.NullString
+	if status != nil {
+		statusFilter = sql.NullString{String: string(*status), Valid: true}
+	}
+
+	var total int
+	if err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM access_review_campaigns
+		WHERE organization_id = $1 AND ($2::text IS NULL OR status = $2)
+	`, orgID, statusFilter).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count access review campaigns: %w", err)
+	}
+
+	query := `SELECT ` + accessReviewCampaignColumns + `
+		FROM access_review_campaigns c
+		WHERE c.organization_id = $1 AND ($2::text IS NULL OR c.status = $2)
+		ORDER BY c.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(query, orgID, statusFilter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list access review campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	campaigns := []*domain.AccessReviewCampaign{}
+	for rows.Next() {
+		campaign, err := scanAccessReviewCampaign(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan access review campaign: %w", err)
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, total, nil
+}
+
+func (r *AccessReviewRepository) ListActiveCampaigns() ([]*domain.AccessReviewCampaign, error) {
+	query := `SELECT ` + accessReviewCampaignColumns + `
+		FROM access_review_campaigns c
+		WHERE c.status = 'active'
+		ORDER BY c.due_at ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active access review campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	campaigns := []*domain.AccessReviewCampaign{}
+	for rows.Next() {
+		campaign, err := scanAccessReviewCampaign(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan access review campaign: %w", err)
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, nil
+}
+
+// UpdateCampaignStatus moves an active campaign to a final status
+func (r *AccessReviewRepository) UpdateCampaignStatus(id uuid.UUID, status domain.AccessReviewCampaignStatus, completedAt *time.Time) error {
+	result, err := r.db.Exec(`
+		UPDATE access_review_campaigns
+		SET status = $2, completed_at = $3
+		WHERE id = $1 AND status = 'active'
+	`, id, status, completedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update access review campaign: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("access review campaign is not active")
+	}
+
+	return nil
+}
+
+const accessReviewItemColumns = `
+	id, campaign_id, organization_id, item_type, resource_id, agent_id, subject, access,
+	reviewer_id, decision, decided_by, decided_at, justification, revocation_error,
+	last_reminded_at, created_at
+`
+
+func scanAccessReviewItem(row rowScanner) (*domain.AccessReviewItem, error) {
+	item := &domain.AccessReviewItem{}
+	var agentID, decidedBy uuid.NullUUID
+	var justification, revocationError sql.NullString
+
+	if err := row.Scan(
+		&item.ID,
+		&item.CampaignID,
+		&item.OrganizationID,
+		&item.ItemType,
+		&item.ResourceID,
+		&agentID,
+		&item.Subject,
+		&item.Access,
+		&item.ReviewerID,
+		&item.Decision,
+		&decidedBy,
+		&item.DecidedAt,
+		&justification,
+		&revocationError,
+		&item.LastRemindedAt,
+		&item.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if agentID.Valid {
+		item.AgentID = &agentID.UUID
+	}
+	if decidedBy.Valid {
+		item.DecidedBy = &decidedBy.UUID
+	}
+	item.Justification = justification.String
+	item.RevocationError = revocationError.String
+
+	return item, nil
+}
+
+func (r *AccessReviewRepository) GetItem(id uuid.UUID) (*domain.AccessReviewItem, error) {
+	query := `SELECT ` + accessReviewItemColumns + ` FROM access_review_items WHERE id = $1`
+
+	item, err := scanAccessReviewItem(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("access review item not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access review item: %w", err)
+	}
+
+	return item, nil
+}
+
+func (r *AccessReviewRepository) ListItems(filter domain.AccessReviewItemFilter) ([]*domain.AccessReviewItem, error) {
+	var decision sql.NullString
+	if filter.Decision != nil {
+		decision = sql.NullString{String: string(*filter.Decision), Valid: true}
+	}
+
+	query := `SELECT ` + accessReviewItemColumns + `
+		FROM access_review_items
+		WHERE ($1::uuid IS NULL OR campaign_id = $1)
+		  AND ($2::uuid IS NULL OR reviewer_id = $2)
+		  AND ($3::text IS NULL OR decision = $3)
+		ORDER BY item_type, subject, access, id
+	`
+
+	rows, err := r.db.Query(query, filter.CampaignID, filter.ReviewerID, decision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access review items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*domain.AccessReviewItem{}
+	for rows.Next() {
+		item, err := scanAccessReviewItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan access review item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (r *AccessReviewRepository) DecideItem(item *domain.AccessReviewItem) error {
+	result, err := r.db.Exec(`
+		UPDATE access_review_items
+		SET decision = $2, decided_by = $3, decided_at = $4, justification = $5, revocation_error = $6
+		WHERE id = $1 AND decision = 'pending'
+	`,
+		item.ID,
+		item.Decision,
+		item.DecidedBy,
+		item.DecidedAt,
+		sql.NullString{String: item.Justification, Valid: item.Justification != ""},
+		sql.NullString{String: item.RevocationError, Valid: item.RevocationError != ""},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record access review decision: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("access review item already decided")
+	}
+
+	return nil
+}
+
+func (r *AccessReviewRepository) MarkItemsReminded(ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	if _, err := r.db.Exec(`
+		UPDATE access_review_items SET last_reminded_at = $2 WHERE id = ANY($1::uuid[])
+	`, pq.Array(values), at); err != nil {
+		return fmt.Errorf("failed to mark access review items reminded: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AccessReviewRepository) CreateEvidence(evidence *domain.AccessReviewEvidence) error {
+	evidence.CreatedAt = time.Now().UTC()
+
+	_, err := r.db.Exec(`
+		INSERT INTO access_review_evidence (
+			campaign_id, organization_id, snapshot, sha256, key_id, signature, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		evidence.CampaignID,
+		evidence.OrganizationID,
+		string(evidence.Snapshot),
+		evidence.SHA256,
+		sql.NullString{String: evidence.KeyID, Valid: evidence.KeyID != ""},
+		sql.NullString{String: evidence.Signature, Valid: evidence.Signature != ""},
+		evidence.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create access review evidence: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvidence returns the sealed evidence of a campaign (nil if it was not completed)
+func (r *AccessReviewRepository) GetEvidence(campaignID uuid.UUID) (*domain.AccessReviewEvidence, error) {
+	evidence := &domain.AccessReviewEvidence{}
+	var snapshot string
+	var keyID, signature sql.NullString
+
+	err := r.db.QueryRow(`
+		SELECT campaign_id, organization_id, snapshot, sha256, key_id, signature, created_at
+		FROM access_review_evidence
+		WHERE campaign_id = $1
+	`, campaignID).Scan(
+		&evidence.CampaignID,
+		&evidence.OrganizationID,
+		&snapshot,
+		&evidence.SHA256,
+		&keyID,
+		&signature,
+		&evidence.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access review evidence: %w", err)
+	}
+
+	evidence.Snapshot = []byte(snapshot)
+	evidence.KeyID = keyID.String
+	evidence.Signature = signature.String
+
+	return evidence, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+type ActionCatalogRepository struct {
+	db *sql.DB
+}
+
+func NewActionCatalogRepository(db *sql.DB) *ActionCatalogRepository {
+	return &ActionCatalogRepository{db: db}
+}
+
+const actionCatalogInsert = `
+	INSERT INTO action_catalog (
+		id, organization_id, action, description, risk_tier, required_trust, trust_multiplier,
+		required_capability, requires_approval, aliases, created_by, created_at, updated_at
+	) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, NULLIF($8, ''), $9, $10, $11, $12, $13)
+`
+
+func actionCatalogInsertArgs(entry *domain.ActionCatalogEntry) []interface{} {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	entry.UpdatedAt = entry.CreatedAt
+	if entry.Aliases == nil {
+		entry.Aliases = []string{}
+	}
+
+	return []interface{}{
+		entry.ID,
+		entry.OrganizationID,
+		entry.Action,
+		entry.Description,
+		entry.RiskTier,
+		entry.RequiredTrust,
+		entry.TrustMultiplier,
+		entry.RequiredCapability,
+		entry.RequiresApproval,
+		pq.Array(entry.Aliases),
+		entry.CreatedBy,
+		entry.CreatedAt,
+		entry.UpdatedAt,
+	}
+}
+
+func (r *ActionCatalogRepository) Create(entry *domain.ActionCatalogEntry) error {
+	if _, err := r.db.Exec(actionCatalogInsert, actionCatalogInsertArgs(entry)...); err != nil {
+		return fmt.Errorf("failed to create action catalog entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ActionCatalogRepository) Update(entry *domain.ActionCatalogEntry) error {
+	query := `
+		UPDATE action_catalog SET
+			action = $3, description = NULLIF($4, ''), risk_tier = $5, required_trust = $6,
+			trust_multiplier = $7, required_capability = NULLIF($8, ''), requires_approval = $9,
+			aliases = $10, updated_at = $11
+		WHERE organization_id = $1 AND id = $2
+	`
+
+	if entry.Aliases == nil {
+		entry.Aliases = []string{}
+	}
+	entry.UpdatedAt = time.Now().UTC()
+
+	result, err := r.db.Exec(query,
+		entry.OrganizationID,
+		entry.ID,
+		entry.Action,
+		entry.Description,
+		entry.RiskTier,
+		entry.RequiredTrust,
+		entry.TrustMultiplier,
+		entry.RequiredCapability,
+		entry.RequiresApproval,
+		pq.Array(entry.Aliases),
+		entry.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update action catalog entry: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("action catalog entry not found")
+	}
+
+	return nil
+}
+
+func (r *ActionCatalogRepository) Delete(orgID, id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM action_catalog WHERE organization_id = $1 AND id = $2`, orgID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete action catalog entry: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("action catalog entry not found")
+	}
+
+	return nil
+}
+
+const actionCatalogColumns = `
+	id, organization_id, action, COALESCE(description, ''), risk_tier, required_trust, trust_multiplier,
+	COALESCE(required_capability, ''), requires_approval, aliases, created_by, created_at, updated_at
+`
+
+func scanActionCatalogEntry(row rowScanner) (*domain.ActionCatalogEntry, error) {
+	entry := &domain.ActionCatalogEntry{}
+	var aliases pq.StringArray
+
+	if err := row.Scan(
+		&entry.ID,
+		&entry.OrganizationID,
+		&entry.Action,
+		&entry.Description,
+		&entry.RiskTier,
+		&entry.RequiredTrust,
+		&entry.TrustMultiplier,
+		&entry.RequiredCapability,
+		&entry.RequiresApproval,
+		&aliases,
+		&entry.CreatedBy,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	entry.Aliases = []string(aliases)
+	if entry.Aliases == nil {
+		entry.Aliases = []string{}
+	}
+
+	return entry, nil
+}
+
+func (r *ActionCatalogRepository) GetByID(orgID, id uuid.UUID) (*domain.ActionCatalogEntry, error) {
+	query := `SELECT ` + actionCatalogColumns + ` FROM action_catalog WHERE organization_id = $1 AND id = $2`
+
+	entry, err := scanActionCatalogEntry(r.db.QueryRow(query, orgID, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("action catalog entry not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action catalog entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (r *ActionCatalogRepository) GetByName(orgID uuid.UUID, name string) (*domain.ActionCatalogEntry, error) {
+	// An exact action match wins over an alias of another entry
+	query := `
+		SELECT ` + actionCatalogColumns + `
+		FROM action_catalog
+		WHERE organization_id = $1 AND (action = $2 OR $2 = ANY(aliases))
+		ORDER BY (action = $2) DESC
+		LIMIT 1
+	`
+
+	entry, err := scanActionCatalogEntry(r.db.QueryRow(query, orgID, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up action catalog entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (r *ActionCatalogRepository) List(orgID uuid.UUID) ([]*domain.ActionCatalogEntry, error) {
+	query := `SELECT ` + actionCatalogColumns + ` FROM action_catalog WHERE organization_id = $1 ORDER BY action`
+
+	rows, err := r.db.Query(query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list action catalog: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.ActionCatalogEntry{}
+	for rows.Next() {
+		entry, err := scanActionCatalogEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan action catalog entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *ActionCatalogRepository) Seed(orgID uuid.UUID, entries []*domain.ActionCatalogEntry) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	added := 0
+	for _, entry := range entries {
+		entry.OrganizationID = orgID
+		result, err := tx.Exec(actionCatalogInsert+` ON CONFLICT (organization_id, action) DO NOTHING`, actionCatalogInsertArgs(entry)...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to seed action catalog entry %s: %w", entry.Action, err)
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			added++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit action catalog seed: %w", err)
+	}
+
+	return added, nil
+}
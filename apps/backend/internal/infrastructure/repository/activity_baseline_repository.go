@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+type AgentActivityBaselineRepository struct {
+	db *sql.DB
+}
+
+func NewAgentActivityBaselineRepository(db *sql.DB) *AgentActivityBaselineRepository {
+	return &AgentActivityBaselineRepository{db: db}
+}
+
+func (r *AgentActivityBaselineRepository) GetByAgent(agentID uuid.UUID) (*domain.AgentActivityBaseline, error) {
+	query := `SELECT profile FROM agent_activity_baselines WHERE agent_id = $1`
+
+	var profile []byte
+	err := r.db.QueryRow(query, agentID).Scan(&profile)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity baseline: %w", err)
+	}
+
+	baseline := &domain.AgentActivityBaseline{}
+	if err := json.Unmarshal(profile, baseline); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal activity baseline: %w", err)
+	}
+
+	return baseline, nil
+}
+
+func (r *AgentActivityBaselineRepository) Upsert(baseline *domain.AgentActivityBaseline) error {
+	query := `
+		INSERT INTO agent_activity_baselines (
+			agent_id, organization_id, profile, total_events, first_seen_at, last_seen_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (agent_id) DO UPDATE SET
+			profile = EXCLUDED.profile,
+			total_events = EXCLUDED.total_events,
+			last_seen_at = EXCLUDED.last_seen_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	baseline.UpdatedAt = time.Now().UTC()
+	profile, err := json.Marshal(baseline)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity baseline: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		query,
+		baseline.AgentID,
+		baseline.OrganizationID,
+		profile,
+		baseline.TotalEvents,
+		baseline.FirstSeenAt,
+		baseline.LastSeenAt,
+		baseline.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save activity baseline: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AgentActivityBaselineRepository) Delete(agentID uuid.UUID) error {
+	query := `DELETE FROM agent_activity_baselines WHERE agent_id = $1`
+
+	if _, err := r.db.Exec(query, agentID); err != nil {
+		return fmt.Errorf("failed to delete activity baseline: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// AgentCardRepository implements domain.AgentCardRepository
+type AgentCardRepository struct {
+	db *sql.DB
+}
+
+// NewAgentCardRepository creates a new Agent Card repository
+func NewAgentCardRepository(db *sql.DB) *AgentCardRepository {
+	return &AgentCardRepository{db: db}
+}
+
+// Create stores a newly published card
+func (r *AgentCardRepository) Create(record *domain.AgentCardRecord) error {
+	skills, inputModes, outputModes, err := marshalAgentCardFields(record)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO agent_cards (
+			id, agent_id, organization_id, url, skills, streaming, push_notifications,
+			default_input_modes, default_output_modes, published_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err = r.db.Exec(
+		query,
+		record.ID,
+		record.AgentID,
+		record.OrganizationID,
+		record.URL,
+		skills,
+		record.Streaming,
+		record.PushNotifications,
+		inputModes,
+		outputModes,
+		record.PublishedBy,
+		record.CreatedAt,
+		record.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create agent card: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces the declared fields of a published card
+func (r *AgentCardRepository) Update(record *domain.AgentCardRecord) error {
+	skills, inputModes, outputModes, err := marshalAgentCardFields(record)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE agent_cards
+		SET url = $2, skills = $3, streaming = $4, push_notifications = $5,
+		    default_input_modes = $6, default_output_modes = $7, published_by = $8, updated_at = $9
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(
+		query,
+		record.ID,
+		record.URL,
+		skills,
+		record.Streaming,
+		record.PushNotifications,
+		inputModes,
+		outputModes,
+		record.PublishedBy,
+		record.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update agent card: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("agent card not found")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a card by ID, or nil if unknown
+func (r *AgentCardRepository) GetByID(id uuid.UUID) (*domain.AgentCardRecord, error) {
+	return r.getOne(`WHERE id = $1`, id)
+}
+
+// GetActiveByAgent retrieves an agent's published card, or nil if it has none
+func (r *AgentCardRepository) GetActiveByAgent(agentID uuid.UUID) (*domain.AgentCardRecord, error) {
+	return r.getOne(`WHERE agent_id = $1 AND revoked_at IS NULL`, agentID)
+}
+
+// Revoke unpublishes a card
+func (r *AgentCardRepository) Revoke(id uuid.UUID, reason string, at time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE agent_cards SET revoked_at = $2, revoke_reason = $3
+		WHERE id = $1 AND revoked_at IS NULL
+	`, id, at, reason)
+	if err != nil {
+		return fmt.Errorf("failed to revoke agent card: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AgentCardRepository) getOne(where string, arg interface{}) (*domain.AgentCardRecord, error) {
+	query := `
+		SELECT id, agent_id, organization_id, url, skills, streaming, push_notifications,
+		       default_input_modes, default_output_modes, published_by,
+		       created_at, updated_at, revoked_at, revoke_reason
+		FROM agent_cards
+	` + where
+
+	record := &domain.AgentCardRecord{}
+	var skills, inputModes, outputModes []byte
+	err := r.db.QueryRow(query, arg).Scan(
+		&record.ID,
+		&record.AgentID,
+		&record.OrganizationID,
+		&record.URL,
+		&skills,
+		&record.Streaming,
+		&record.PushNotifications,
+		&inputModes,
+		&outputModes,
+		&record.PublishedBy,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+		&record.RevokedAt,
+		&record.RevokeReason,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent card: %w", err)
+	}
+
+	if err := json.Unmarshal(skills, &record.Skills); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal agent card skills: %w", err)
+	}
+	if err := json.Unmarshal(inputModes, &record.DefaultInputModes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal agent card input modes: %w", err)
+	}
+	if err := json.Unmarshal(outputModes, &record.DefaultOutputModes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal agent card output modes: %w", err)
+	}
+
+	return record, nil
+}
+
+func marshalAgentCardFields(record *domain.AgentCardRecord) (skills, inputModes, outputModes []byte, err error) {
+	if skills, err = json.Marshal(record.Skills); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to marshal agent card skills: %w", err)
+	}
+	if inputModes, err = json.Marshal(record.DefaultInputModes); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to marshal agent card input modes: %w", err)
+	}
+	if outputModes, err = json.Marshal(record.DefaultOutputModes); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to marshal agent card output modes: %w", err)
+	}
+	return skills, inputModes, outputModes, nil
+}
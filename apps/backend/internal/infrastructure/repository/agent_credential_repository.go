@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// AgentCredentialRepository implements domain.AgentCredentialRepository
+type AgentCredentialRepository struct {
+	db *sql.DB
+}
+
+// NewAgentCredentialRepository creates a new agent credential repository
+func NewAgentCredentialRepository(db *sql.DB) *AgentCredentialRepository {
+	return &AgentCredentialRepository{db: db}
+}
+
+// Create stores a credential, assigning it the next free entry of a status list. A new list is
+// started when every list is full.
+func (r *AgentCredentialRepository) Create(credential *domain.AgentCredential) error {
+	capabilities, err := json.Marshal(credential.Capabilities)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credential capabilities: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var listID uuid.UUID
+	var index int
+	err = tx.QueryRow(`
+		SELECT id, next_index FROM credential_status_lists
+		WHERE purpose = 'revocation' AND next_index < size
+		ORDER BY created_at
+		LIMIT 1
+		FOR UPDATE
+	`).Scan(&listID, &index)
+	if err == sql.ErrNoRows {
+		listID, index = uuid.New(), 0
+		_, err = tx.Exec(`
+			INSERT INTO credential_status_lists (id, purpose, size, next_index, created_at)
+			VALUES ($1, 'revocation', $2, 0, $3)
+		`, listID, domain.StatusListSize, credential.IssuedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to allocate status list entry: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE credential_status_lists SET next_index = $2 WHERE id = $1`, listID, index+1); err != nil {
+		return fmt.Errorf("failed to allocate status list entry: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO agent_credentials (
+			id, agent_id, organization_id, subject_did, capabilities, status_list_id,
+			status_list_index, issued_by, issued_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		credential.ID,
+		credential.AgentID,
+		credential.OrganizationID,
+		credential.SubjectDID,
+		capabilities,
+		listID,
+		index,
+		credential.IssuedBy,
+		credential.IssuedAt,
+		credential.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create agent credential: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	credential.StatusListID = listID
+	credential.StatusListIndex = index
+	return nil
+}
+
+// GetByID retrieves a credential by ID, or nil if unknown
+func (r *AgentCredentialRepository) GetByID(id uuid.UUID) (*domain.AgentCredential, error) {
+	rows, err := r.db.Query(agentCredentialSelect+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent credential: %w", err)
+	}
+	defer rows.Close()
+
+	credentials, err := scanAgentCredentials(rows)
+	if err != nil || len(credentials) == 0 {
+		return nil, err
+	}
+	return credentials[0], nil
+}
+
+// ListByAgent returns an agent's credentials, newest first
+func (r *AgentCredentialRepository) ListByAgent(agentID uuid.UUID) ([]*domain.AgentCredential, error) {
+	rows, err := r.db.Query(agentCredentialSelect+` WHERE agent_id = $1 ORDER BY issued_at DESC`, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent credentials: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAgentCredentials(rows)
+}
+
+// Revoke revokes a credential
+func (r *AgentCredentialRepository) Revoke(id uuid.UUID, reason string, at time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE agent_credentials SET revoked_at = $2, revoke_reason = $3
+		WHERE id = $1 AND revoked_at IS NULL
+	`, id, at, reason)
+	if err != nil {
+		return fmt.Errorf("failed to revoke agent credential: %w", err)
+	}
+
+	return nil
+}
+
+// StatusListExists reports whether a status list has been allocated
+func (r *AgentCredentialRepository) StatusListExists(listID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM credential_status_lists WHERE id = $1)`, listID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to get status list: %w", err)
+	}
+	return exists, nil
+}
+
+// RevokedIndexes returns the entries of a status list whose credentials are revoked, or whose
+// agent has been deleted, revoked, suspended or marked compromised
+func (r *AgentCredentialRepository) RevokedIndexes(listID uuid.UUID) ([]int, error) {
+	rows, err := r.db.Query(`
+		SELECT c.status_list_index
+		FROM agent_credentials c
+		LEFT JOIN agents a ON a.id = c.agent_id
+		WHERE c.status_list_id = $1
+		  AND (c.revoked_at IS NOT NULL
+		       OR a.id IS NULL
+		       OR a.status IN ('revoked', 'suspended')
+		       OR a.is_compromised = TRUE)
+		ORDER BY c.status_list_index
+	`, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revoked credentials: %w", err)
+	}
+	defer rows.Close()
+
+	indexes := []int{}
+	for rows.Next() {
+		var index int
+		if err := rows.Scan(&index); err != nil {
+			return nil, fmt.Errorf("failed to scan revoked credential: %w", err)
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
+
+const agentCredentialSelect = `
+	SELECT id, agent_id, organization_id, subject_did, capabilities, status_list_id,
+	       status_list_index, issued_by, issued_at, expires_at, revoked_at, revoke_reason
+	FROM agent_credentials`
+
+func scanAgentCredentials(rows *sql.Rows) ([]*domain.AgentCredential, error) {
+	credentials := []*domain.AgentCredential{}
+	for rows.Next() {
+		credential := &domain.AgentCredential{}
+		var capabilities []byte
+		err := rows.Scan(
+			&credential.ID,
+			&credential.AgentID,
+			&credential.OrganizationID,
+			&credential.SubjectDID,
+			&capabilities,
+			&credential.StatusListID,
+			&credential.StatusListIndex,
+			&credential.IssuedBy,
+			&credential.IssuedAt,
+			&credential.ExpiresAt,
+			&credential.RevokedAt,
+			&credential.RevokeReason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan agent credential: %w", err)
+		}
+		if err := json.Unmarshal(capabilities, &credential.Capabilities); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal credential capabilities: %w", err)
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, rows.Err()
+}
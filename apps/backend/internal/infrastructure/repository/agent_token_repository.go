@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// AgentTokenRepository implements domain.AgentTokenRepository
+type AgentTokenRepository struct {
+	db *sql.DB
+}
+
+// NewAgentTokenRepository creates a new agent access token repository
+func NewAgentTokenRepository(db *sql.DB) *AgentTokenRepository {
+	return &AgentTokenRepository{db: db}
+}
+
+// Create records an issued access token
+func (r *AgentTokenRepository) Create(token *domain.AgentAccessToken) error {
+	query := `
+		INSERT INTO agent_access_tokens (
+			id, agent_id, organization_id, grant_type, subject, actor_user_id,
+			scope, audience, issued_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		token.ID,
+		token.AgentID,
+		token.OrganizationID,
+		token.GrantType,
+		token.Subject,
+		token.ActorUserID,
+		pq.Array(token.Scope),
+		pq.Array(token.Audience),
+		token.IssuedAt,
+		token.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create agent access token: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns an issued token, or nil when it is unknown
+func (r *AgentTokenRepository) GetByID(id uuid.UUID) (*domain.AgentAccessToken, error) {
+	query := `
+		SELECT id, agent_id, organization_id, grant_type, subject, actor_user_id,
+		       scope, audience, issued_at, expires_at, revoked_at
+		FROM agent_access_tokens
+		WHERE id = $1
+	`
+
+	token := &domain.AgentAccessToken{}
+	var scope, audience pq.StringArray
+	err := r.db.QueryRow(query, id).Scan(
+		&token.ID,
+		&token.AgentID,
+		&token.OrganizationID,
+		&token.GrantType,
+		&token.Subject,
+		&token.ActorUserID,
+		&scope,
+		&audience,
+		&token.IssuedAt,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent access token: %w", err)
+	}
+	token.Scope = scope
+	token.Audience = audience
+
+	return token, nil
+}
+
+// Revoke marks a token revoked; revoking an unknown or revoked token is not an error
+func (r *AgentTokenRepository) Revoke(id uuid.UUID, at time.Time) error {
+	query := `UPDATE agent_access_tokens SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.db.Exec(query, id, at); err != nil {
+		return fmt.Errorf("failed to revoke agent access token: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeByAgent revokes every active token of an agent
+func (r *AgentTokenRepository) RevokeByAgent(agentID uuid.UUID, at time.Time) (int64, error) {
+	query := `
+		UPDATE agent_access_tokens SET revoked_at = $2
+		WHERE agent_id = $1 AND revoked_at IS NULL AND expires_at > $2
+	`
+
+	result, err := r.db.Exec(query, agentID, at)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke agent access tokens: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// RecordAssertion stores a client assertion ID; false means it was already used
+func (r *AgentTokenRepository) RecordAssertion(agentID uuid.UUID, jti string, expiresAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO agent_client_assertions (agent_id, jti, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (agent_id, jti) DO NOTHING
+	`
+
+	result, err := r.db.Exec(query, agentID, jti, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to record client assertion: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record client assertion: %w", err)
+	}
+
+	return rows == 1, nil
+}
+
+// DeleteExpired removes tokens and client assertion IDs that expired before the given time
+func (r *AgentTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM agent_access_tokens WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired agent access tokens: %w", err)
+	}
+	deleted, _ := result.RowsAffected()
+
+	if _, err := tx.Exec(`DELETE FROM agent_client_assertions WHERE expires_at < $1`, before); err != nil {
+		return 0, fmt.Errorf("failed to delete expired client assertions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return deleted, nil
+}
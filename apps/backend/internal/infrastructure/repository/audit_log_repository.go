@@ -3,9 +3,13 @@ package repository
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/opena2a/identity/backend/internal/domain"
 )
 
@@ -17,25 +21,65 @@ func NewAuditLogRepository(db *sql.DB) *AuditLogRepository {
 	return &AuditLogRepository{db: db}
 }
 
+// Create appends the record to its organization's hash chain. The chain head row is locked
+// for the duration of the insert so sequence numbers stay gapless under concurrent writers.
 func (r *AuditLogRepository) Create(log *domain.AuditLog) error {
-	query := `
-		INSERT INTO audit_logs (id, organization_id, user_id, action, resource_type, resource_id, ip_address, user_agent, metadata, timestamp)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-	`
-
 	if log.ID == uuid.Nil {
 		log.ID = uuid.New()
 	}
 	if log.Timestamp.IsZero() {
 		log.Timestamp = time.Now()
 	}
+	// The column has no time zone and microsecond precision; normalize so the hash survives a round trip
+	log.Timestamp = log.Timestamp.UTC().Truncate(time.Microsecond)
 
 	metadataJSON, err := json.Marshal(log.Metadata)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.Exec(query,
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin audit log transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO audit_log_chain_heads (organization_id, last_sequence, last_hash, chain_started_at)
+		VALUES ($1, 0, $2, $3)
+		ON CONFLICT (organization_id) DO NOTHING
+	`, log.OrganizationID, domain.AuditChainGenesisHash, log.Timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to initialize audit chain: %w", err)
+	}
+
+	var lastSequence int64
+	var lastHash string
+	err = tx.QueryRow(`
+		SELECT last_sequence, last_hash
+		FROM audit_log_chain_heads
+		WHERE organization_id = $1
+		FOR UPDATE
+	`, log.OrganizationID).Scan(&lastSequence, &lastHash)
+	if err != nil {
+		return fmt.Errorf("failed to lock audit chain: %w", err)
+	}
+
+	sequence := lastSequence + 1
+	log.SequenceNumber = &sequence
+	log.PrevHash = lastHash
+	log.EntryHash, err = domain.ComputeAuditEntryHash(log)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO audit_logs (
+			id, organization_id, user_id, action, resource_type, resource_id, ip_address, user_agent, metadata, timestamp,
+			sequence_number, prev_hash, entry_hash
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`,
 		log.ID,
 		log.OrganizationID,
 		log.UserID,
@@ -46,13 +90,29 @@ func (r *AuditLogRepository) Create(log *domain.AuditLog) error {
 		log.UserAgent,
 		metadataJSON,
 		log.Timestamp,
+		sequence,
+		log.PrevHash,
+		log.EntryHash,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE audit_log_chain_heads
+		SET last_sequence = $2, last_hash = $3, updated_at = NOW()
+		WHERE organization_id = $1
+	`, log.OrganizationID, sequence, log.EntryHash)
+	if err != nil {
+		return fmt.Errorf("failed to advance audit chain: %w", err)
+	}
+
+	return tx.Commit()
 }
 
 func (r *AuditLogRepository) GetByOrganization(orgID uuid.UUID, limit, offset int) ([]*domain.AuditLog, error) {
 	query := `
-		SELECT id, organization_id, user_id, action, resource_type, resource_id, ip_address, user_agent, metadata, timestamp
+		SELECT ` + auditLogColumns + `
 		FROM audit_logs
 		WHERE organization_id = $1
 		ORDER BY timestamp DESC
@@ -70,7 +130,7 @@ func (r *AuditLogRepository) GetByOrganization(orgID uuid.UUID, limit, offset in
 
 func (r *AuditLogRepository) GetByUser(userID uuid.UUID, limit, offset int) ([]*domain.AuditLog, error) {
 	query := `
-		SELECT id, organization_id, user_id, action, resource_type, resource_id, ip_address, user_agent, metadata, timestamp
+		SELECT ` + auditLogColumns + `
 		FROM audit_logs
 		WHERE user_id = $1
 		ORDER BY timestamp DESC
@@ -88,7 +148,7 @@ func (r *AuditLogRepository) GetByUser(userID uuid.UUID, limit, offset int) ([]*
 
 func (r *AuditLogRepository) GetByResource(resourceType string, resourceID uuid.UUID) ([]*domain.AuditLog, error) {
 	query := `
-		SELECT id, organization_id, user_id, action, resource_type, resource_id, ip_address, user_agent, metadata, timestamp
+		SELECT ` + auditLogColumns + `
 		FROM audit_logs
 		WHERE resource_type = $1 AND resource_id = $2
 		ORDER BY timestamp DESC
@@ -107,7 +167,7 @@ func (r *AuditLogRepository) Search(query string, limit, offset int) ([]*domain.
 	// This would integrate with Elasticsearch for full-text search
 	// For now, implement basic SQL search
 	sqlQuery := `
-		SELECT id, organization_id, user_id, action, resource_type, resource_id, ip_address, user_agent, metadata, timestamp
+		SELECT ` + auditLogColumns + `
 		FROM audit_logs
 		WHERE action LIKE $1 OR resource_type LIKE $1
 		ORDER BY timestamp DESC
@@ -124,12 +184,18 @@ func (r *AuditLogRepository) Search(query string, limit, offset int) ([]*domain.
 	return r.scanLogs(rows)
 }
 
+const auditLogColumns = `
+	id, organization_id, user_id, action, resource_type, resource_id, ip_address, user_agent, metadata, timestamp,
+	sequence_number, prev_hash, entry_hash
+`
+
 func (r *AuditLogRepository) scanLogs(rows *sql.Rows) ([]*domain.AuditLog, error) {
 	var logs []*domain.AuditLog
 
 	for rows.Next() {
 		log := &domain.AuditLog{}
 		var metadataJSON []byte
+		var prevHash, entryHash sql.NullString
 
 		err := rows.Scan(
 			&log.ID,
@@ -142,10 +208,15 @@ func (r *AuditLogRepository) scanLogs(rows *sql.Rows) ([]*domain.AuditLog, error
 			&log.UserAgent,
 			&metadataJSON,
 			&log.Timestamp,
+			&log.SequenceNumber,
+			&prevHash,
+			&entryHash,
 		)
 		if err != nil {
 			return nil, err
 		}
+		log.PrevHash = prevHash.String
+		log.EntryHash = entryHash.String
 
 		if len(metadataJSON) > 0 {
 			if err := json.Unmarshal(metadataJSON, &log.Metadata); err != nil {
@@ -158,3 +229,317 @@ func (r *AuditLogRepository) scanLogs(rows *sql.Rows) ([]*domain.AuditLog, error
 
 	return logs, nil
 }
+
+// auditLogFilterClause builds the WHERE clause (and its arguments) for an export filter
+func auditLogFilterClause(filter domain.AuditLogFilter, after, until *domain.AuditLogCursor) (string, []interface{}) {
+	conditions := []string{"organization_id = $1"}
+	args := []interface{}{filter.OrganizationID}
+
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.StartDate != nil {
+		conditions = append(conditions, "timestamp >= "+arg(filter.StartDate.UTC()))
+	}
+	if filter.EndDate != nil {
+		conditions = append(conditions, "timestamp < "+arg(filter.EndDate.UTC()))
+	}
+	if filter.UserID != nil {
+		conditions = append(conditions, "user_id = "+arg(*filter.UserID))
+	}
+	if len(filter.Actions) > 0 {
+		actions := make([]string, len(filter.Actions))
+		for i, action := range filter.Actions {
+			actions[i] = string(action)
+		}
+		conditions = append(conditions, "action = ANY("+arg(pq.Array(actions))+")")
+	}
+	if filter.ResourceType != "" {
+		conditions = append(conditions, "resource_type = "+arg(filter.ResourceType))
+	}
+	if filter.ResourceID != nil {
+		conditions = append(conditions, "resource_id = "+arg(*filter.ResourceID))
+	}
+	if filter.AgentID != nil {
+		conditions = append(conditions, fmt.Sprintf(
+			"((resource_type = 'agent' AND resource_id = %s) OR metadata->>'agent_id' = %s)",
+			arg(*filter.AgentID), arg(filter.AgentID.String()),
+		))
+	}
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(timestamp, id) > (%s, %s)", arg(after.Timestamp.UTC()), arg(after.ID)))
+	}
+	if until != nil {
+		conditions = append(conditions, fmt.Sprintf("(timestamp, id) <= (%s, %s)", arg(until.Timestamp.UTC()), arg(until.ID)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// ListForExport returns filtered records in (timestamp, id) order using keyset pagination
+func (r *AuditLogRepository) ListForExport(filter domain.AuditLogFilter, after, until *domain.AuditLogCursor, limit int) ([]*domain.AuditLog, error) {
+	where, args := auditLogFilterClause(filter, after, until)
+	args = append(args, limit)
+
+	query := `SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE ` + where + `
+		ORDER BY timestamp ASC, id ASC
+		LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs for export: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanLogs(rows)
+}
+
+// GetExportPageEnd returns the position of the pageSize-th record after the cursor when more records follow it
+func (r *AuditLogRepository) GetExportPageEnd(filter domain.AuditLogFilter, after *domain.AuditLogCursor, pageSize int) (*domain.AuditLogCursor, error) {
+	where, args := auditLogFilterClause(filter, after, nil)
+	args = append(args, pageSize-1)
+
+	query := `SELECT timestamp, id
+		FROM audit_logs
+		WHERE ` + where + `
+		ORDER BY timestamp ASC, id ASC
+		OFFSET $` + strconv.Itoa(len(args)) + `
+		LIMIT 2`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit export page end: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []domain.AuditLogCursor
+	for rows.Next() {
+		var position domain.AuditLogCursor
+		if err := rows.Scan(&position.Timestamp, &position.ID); err != nil {
+			return nil, fmt.Errorf("failed to scan audit export position: %w", err)
+		}
+		positions = append(positions, position)
+	}
+
+	// Only a following record makes this a partial page
+	if len(positions) < 2 {
+		return nil, nil
+	}
+	return &positions[0], nil
+}
+
+// GetChainHead returns an organization's audit chain head (nil if the organization has no chained records)
+func (r *AuditLogRepository) GetChainHead(orgID uuid.UUID) (*domain.AuditChainHead, error) {
+	query := `
+		SELECT organization_id, last_sequence, last_hash, pruned_through_sequence,
+		       COALESCE(pruned_through_hash, ''), chain_started_at, updated_at
+		FROM audit_log_chain_heads
+		WHERE organization_id = $1
+	`
+
+	head := &domain.AuditChainHead{}
+	err := r.db.QueryRow(query, orgID).Scan(
+		&head.OrganizationID,
+		&head.LastSequence,
+		&head.LastHash,
+		&head.PrunedThroughSequence,
+		&head.PrunedThroughHash,
+		&head.ChainStartedAt,
+		&head.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit chain head: %w", err)
+	}
+
+	return head, nil
+}
+
+// ListChainHeads returns the audit chain head of every organization
+func (r *AuditLogRepository) ListChainHeads() ([]*domain.AuditChainHead, error) {
+	query := `
+		SELECT organization_id, last_sequence, last_hash, pruned_through_sequence,
+		       COALESCE(pruned_through_hash, ''), chain_started_at, updated_at
+		FROM audit_log_chain_heads
+		ORDER BY organization_id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit chain heads: %w", err)
+	}
+	defer rows.Close()
+
+	heads := []*domain.AuditChainHead{}
+	for rows.Next() {
+		head := &domain.AuditChainHead{}
+		if err := rows.Scan(
+			&head.OrganizationID,
+			&head.LastSequence,
+			&head.LastHash,
+			&head.PrunedThroughSequence,
+			&head.PrunedThroughHash,
+			&head.ChainStartedAt,
+			&head.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit chain head: %w", err)
+		}
+		heads = append(heads, head)
+	}
+
+	return heads, nil
+}
+
+// GetChainedEntries returns chained records with a sequence number above afterSequence, in chain order
+func (r *AuditLogRepository) GetChainedEntries(orgID uuid.UUID, afterSequence int64, limit int) ([]*domain.AuditLog, error) {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE organization_id = $1 AND sequence_number > $2
+		ORDER BY sequence_number ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(query, orgID, afterSequence, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chained audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanLogs(rows)
+}
+
+// CountUnchainedSince counts records without a sequence number written at or after since
+func (r *AuditLogRepository) CountUnchainedSince(orgID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM audit_logs
+		WHERE organization_id = $1 AND sequence_number IS NULL AND timestamp >= $2
+	`
+
+	var count int
+	if err := r.db.QueryRow(query, orgID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unchained audit logs: %w", err)
+	}
+
+	return count, nil
+}
+
+// SetPrunedThrough records that chained records up to sequence may be purged. The anchor only
+// moves forward.
+func (r *AuditLogRepository) SetPrunedThrough(orgID uuid.UUID, sequence int64, hash string) error {
+	query := `
+		UPDATE audit_log_chain_heads
+		SET pruned_through_sequence = $2, pruned_through_hash = $3
+		WHERE organization_id = $1 AND pruned_through_sequence < $2 AND last_sequence >= $2
+	`
+
+	if _, err := r.db.Exec(query, orgID, sequence, hash); err != nil {
+		return fmt.Errorf("failed to set audit chain prune anchor: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuditLogRepository) CreateCheckpoint(checkpoint *domain.AuditCheckpoint) error {
+	query := `
+		INSERT INTO audit_log_checkpoints (
+			id, organization_id, sequence_number, entry_hash, key_id, public_key, signature, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	if checkpoint.ID == uuid.Nil {
+		checkpoint.ID = uuid.New()
+	}
+
+	_, err := r.db.Exec(
+		query,
+		checkpoint.ID,
+		checkpoint.OrganizationID,
+		checkpoint.SequenceNumber,
+		checkpoint.EntryHash,
+		checkpoint.KeyID,
+		checkpoint.PublicKey,
+		checkpoint.Signature,
+		checkpoint.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create audit checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+const auditCheckpointColumns = `
+	id, organization_id, sequence_number, entry_hash, key_id, public_key, signature, created_at
+`
+
+func scanAuditCheckpoint(row rowScanner) (*domain.AuditCheckpoint, error) {
+	checkpoint := &domain.AuditCheckpoint{}
+	if err := row.Scan(
+		&checkpoint.ID,
+		&checkpoint.OrganizationID,
+		&checkpoint.SequenceNumber,
+		&checkpoint.EntryHash,
+		&checkpoint.KeyID,
+		&checkpoint.PublicKey,
+		&checkpoint.Signature,
+		&checkpoint.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// GetCheckpoints returns an organization's checkpoints, newest first
+func (r *AuditLogRepository) GetCheckpoints(orgID uuid.UUID, limit, offset int) ([]*domain.AuditCheckpoint, error) {
+	query := `SELECT ` + auditCheckpointColumns + `
+		FROM audit_log_checkpoints
+		WHERE organization_id = $1
+		ORDER BY sequence_number DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, orgID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	checkpoints := []*domain.AuditCheckpoint{}
+	for rows.Next() {
+		checkpoint, err := scanAuditCheckpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, nil
+}
+
+// GetLatestCheckpoint returns an organization's most recent checkpoint (nil if there is none)
+func (r *AuditLogRepository) GetLatestCheckpoint(orgID uuid.UUID) (*domain.AuditCheckpoint, error) {
+	query := `SELECT ` + auditCheckpointColumns + `
+		FROM audit_log_checkpoints
+		WHERE organization_id = $1
+		ORDER BY sequence_number DESC
+		LIMIT 1
+	`
+
+	checkpoint, err := scanAuditCheckpoint(r.db.QueryRow(query, orgID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest audit checkpoint: %w", err)
+	}
+
+	return checkpoint, nil
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+type DataRetentionRepository struct {
+	db *sql.DB
+}
+
+func NewDataRetentionRepository(db *sql.DB) *DataRetentionRepository {
+	return &DataRetentionRepository{db: db}
+}
+
+// retentionTableScopes selects an organization's rows of each table written before a cutoff
+// ($1 organization, $2 cutoff). Detections and webhook deliveries are scoped through their parent.
+var retentionTableScopes = map[domain.RetentionTable]struct {
+	selectIDs string
+	stats     string
+}{
+	domain.RetentionTableVerificationEvents: {
+		selectIDs: `SELECT id FROM verification_events WHERE organization_id = $1 AND created_at < $2`,
+		stats:     `SELECT COUNT(*), MIN(created_at) FROM verification_events WHERE organization_id = $1`,
+	},
+	domain.RetentionTableAlerts: {
+		selectIDs: `SELECT id FROM alerts WHERE organization_id = $1 AND created_at < $2 AND is_acknowledged = TRUE`,
+		stats:     `SELECT COUNT(*), MIN(created_at) FROM alerts WHERE organization_id = $1`,
+	},
+	domain.RetentionTableDetections: {
+		selectIDs: `SELECT d.id FROM detections d JOIN agents a ON a.id = d.agent_id
+			WHERE a.organization_id = $1 AND d.detected_at < $2`,
+		stats: `SELECT COUNT(*), MIN(d.detected_at) FROM detections d JOIN agents a ON a.id = d.agent_id
+			WHERE a.organization_id = $1`,
+	},
+	domain.RetentionTableWebhookDeliveries: {
+		selectIDs: `SELECT wd.id FROM webhook_deliveries wd JOIN webhooks w ON w.id = wd.webhook_id
+			WHERE w.organization_id = $1 AND wd.created_at < $2`,
+		stats: `SELECT COUNT(*), MIN(wd.created_at) FROM webhook_deliveries wd JOIN webhooks w ON w.id = wd.webhook_id
+			WHERE w.organization_id = $1`,
+	},
+	domain.RetentionTableAuditLogs: {
+		stats: `SELECT COUNT(*), MIN(timestamp) FROM audit_logs WHERE organization_id = $1`,
+	},
+}
+
+func (r *DataRetentionRepository) ListRules(orgID uuid.UUID) ([]*domain.RetentionRule, error) {
+	query := `
+		SELECT organization_id, table_name, retention_days, enabled, updated_by, updated_at
+		FROM data_retention_rules
+		WHERE organization_id = $1
+		ORDER BY table_name
+	`
+
+	rows, err := r.db.Query(query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retention rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*domain.RetentionRule{}
+	for rows.Next() {
+		rule := &domain.RetentionRule{}
+		var updatedBy uuid.NullUUID
+		if err := rows.Scan(
+			&rule.OrganizationID,
+			&rule.Table,
+			&rule.RetentionDays,
+			&rule.Enabled,
+			&updatedBy,
+			&rule.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan retention rule: %w", err)
+		}
+		if updatedBy.Valid {
+			rule.UpdatedBy = &updatedBy.UUID
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *DataRetentionRepository) UpsertRule(rule *domain.RetentionRule) error {
+	query := `
+		INSERT INTO data_retention_rules (organization_id, table_name, retention_days, enabled, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (organization_id, table_name) DO UPDATE SET
+			retention_days = EXCLUDED.retention_days,
+			enabled = EXCLUDED.enabled,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	rule.UpdatedAt = time.Now().UTC()
+	_, err := r.db.Exec(query, rule.OrganizationID, rule.Table, rule.RetentionDays, rule.Enabled, rule.UpdatedBy, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save retention rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DataRetentionRepository) DeleteRule(orgID uuid.UUID, table domain.RetentionTable) error {
+	query := `DELETE FROM data_retention_rules WHERE organization_id = $1 AND table_name = $2`
+
+	if _, err := r.db.Exec(query, orgID, table); err != nil {
+		return fmt.Errorf("failed to delete retention rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DataRetentionRepository) CreateLegalHold(hold *domain.LegalHold) error {
+	query := `
+		INSERT INTO data_legal_holds (id, organization_id, table_name, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if hold.ID == uuid.Nil {
+		hold.ID = uuid.New()
+	}
+	hold.CreatedAt = time.Now().UTC()
+
+	_, err := r.db.Exec(query, hold.ID, hold.OrganizationID, hold.Table, hold.Reason, hold.CreatedBy, hold.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create legal hold: %w", err)
+	}
+
+	return nil
+}
+
+const legalHoldColumns = `
+	id, organization_id, table_name, reason, created_by, created_at, released_by, released_at
+`
+
+func scanLegalHold(row rowScanner) (*domain.LegalHold, error) {
+	hold := &domain.LegalHold{}
+	var table sql.NullString
+	var releasedBy uuid.NullUUID
+	var releasedAt sql.NullTime
+
+	if err := row.Scan(
+		&hold.ID,
+		&hold.OrganizationID,
+		&table,
+		&hold.Reason,
+		&hold.CreatedBy,
+		&hold.CreatedAt,
+		&releasedBy,
+		&releasedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if table.Valid {
+		t := domain.RetentionTable(table.String)
+		hold.Table = &t
+	}
+	if releasedBy.Valid {
+		hold.ReleasedBy = &releasedBy.UUID
+	}
+	if releasedAt.Valid {
+		hold.ReleasedAt = &releasedAt.Time
+	}
+
+	return hold, nil
+}
+
+func (r *DataRetentionRepository) GetLegalHold(id uuid.UUID) (*domain.LegalHold, error) {
+	query := `SELECT ` + legalHoldColumns + ` FROM data_legal_holds WHERE id = $1`
+
+	hold, err := scanLegalHold(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("legal hold not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get legal hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+func (r *DataRetentionRepository) ListLegalHolds(orgID uuid.UUID, includeReleased bool) ([]*domain.LegalHold, error) {
+	query := `
+		SELECT ` + legalHoldColumns + `
+		FROM data_legal_holds
+		WHERE organization_id = $1 AND ($2 OR released_at IS NULL)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, orgID, includeReleased)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list legal holds: %w", err)
+	}
+	defer rows.Close()
+
+	holds := []*domain.LegalHold{}
+	for rows.Next() {
+		hold, err := scanLegalHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan legal hold: %w", err)
+		}
+		holds = append(holds, hold)
+	}
+
+	return holds, nil
+}
+
+func (r *DataRetentionRepository) ReleaseLegalHold(id, releasedBy uuid.UUID, releasedAt time.Time) error {
+	query := `
+		UPDATE data_legal_holds
+		SET released_by = $2, released_at = $3
+		WHERE id = $1 AND released_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id, releasedBy, releasedAt)
+	if err != nil {
+		return fmt.Errorf("failed to release legal hold: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("legal hold not found or already released")
+	}
+
+	return nil
+}
+
+func (r *DataRetentionRepository) GetTableStats(orgID uuid.UUID, table domain.RetentionTable) (int64, *time.Time, error) {
+	scope, ok := retentionTableScopes[table]
+	if !ok {
+		return 0, nil, fmt.Errorf("unknown retention table: %s", table)
+	}
+
+	var count int64
+	var oldest sql.NullTime
+	if err := r.db.QueryRow(scope.stats, orgID).Scan(&count, &oldest); err != nil {
+		return 0, nil, fmt.Errorf("failed to get %s stats: %w", table, err)
+	}
+	if !oldest.Valid {
+		return count, nil, nil
+	}
+
+	return count, &oldest.Time, nil
+}
+
+// PurgeBatch deletes one batch in its own short transaction. SKIP LOCKED leaves rows other
+// transactions are using for the next run instead of waiting on them.
+func (r *DataRetentionRepository) PurgeBatch(ctx context.Context, orgID uuid.UUID, table domain.RetentionTable, before time.Time, limit int) (int64, error) {
+	scope, ok := retentionTableScopes[table]
+	if !ok || scope.selectIDs == "" {
+		return 0, fmt.Errorf("unknown retention table: %s", table)
+	}
+
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE id IN (%s LIMIT $3 FOR UPDATE SKIP LOCKED)
+	`, table, scope.selectIDs)
+
+	result, err := r.db.ExecContext(ctx, query, orgID, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge %s: %w", table, err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *DataRetentionRepository) PurgeAuditLogBatch(ctx context.Context, orgID uuid.UUID, throughSequence int64, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM audit_logs
+		WHERE id IN (
+			SELECT id FROM audit_logs
+			WHERE organization_id = $1
+			  AND ((sequence_number IS NOT NULL AND sequence_number <= $2)
+			       OR (sequence_number IS NULL AND timestamp < $3))
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, orgID, throughSequence, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge audit_logs: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+type DataTransferRepository struct {
+	db *sql.DB
+}
+
+func NewDataTransferRepository(db *sql.DB) *DataTransferRepository {
+	return &DataTransferRepository{db: db}
+}
+
+func (r *DataTransferRepository) Create(transfer *domain.DataTransfer) error {
+	query := `
+		INSERT INTO agent_data_transfers (
+			id, organization_id, agent_id, source, resource, destination, mcp_server,
+			bytes_read, bytes_exported, audit_id, occurred_at
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10, $11)
+	`
+
+	if transfer.ID == uuid.Nil {
+		transfer.ID = uuid.New()
+	}
+	if transfer.OccurredAt.IsZero() {
+		transfer.OccurredAt = time.Now().UTC()
+	}
+
+	_, err := r.db.Exec(
+		query,
+		transfer.ID,
+		transfer.OrganizationID,
+		transfer.AgentID,
+		transfer.Source,
+		transfer.Resource,
+		transfer.Destination,
+		transfer.MCPServer,
+		transfer.BytesRead,
+		transfer.BytesExported,
+		transfer.AuditID,
+		transfer.OccurredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create data transfer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DataTransferRepository) SumByAgent(agentID uuid.UUID, since time.Time) (int64, int64, error) {
+	query := `
+		SELECT COALESCE(SUM(bytes_read), 0), COALESCE(SUM(bytes_exported), 0)
+		FROM agent_data_transfers
+		WHERE agent_id = $1 AND occurred_at > $2
+	`
+
+	var bytesRead, bytesExported int64
+	if err := r.db.QueryRow(query, agentID, since).Scan(&bytesRead, &bytesExported); err != nil {
+		return 0, 0, fmt.Errorf("failed to sum data transfers: %w", err)
+	}
+
+	return bytesRead, bytesExported, nil
+}
+
+func (r *DataTransferRepository) TopDestinations(agentID uuid.UUID, since time.Time, limit int) ([]*domain.DataTransferDestination, error) {
+	query := `
+		SELECT COALESCE(destination, ''), SUM(bytes_exported), COUNT(*)
+		FROM agent_data_transfers
+		WHERE agent_id = $1 AND occurred_at > $2 AND bytes_exported > 0
+		GROUP BY COALESCE(destination, '')
+		ORDER BY SUM(bytes_exported) DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(query, agentID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data transfer destinations: %w", err)
+	}
+	defer rows.Close()
+
+	destinations := []*domain.DataTransferDestination{}
+	for rows.Next() {
+		destination := &domain.DataTransferDestination{}
+		if err := rows.Scan(&destination.Destination, &destination.BytesExported, &destination.Transfers); err != nil {
+			return nil, fmt.Errorf("failed to scan data transfer destination: %w", err)
+		}
+		destinations = append(destinations, destination)
+	}
+
+	return destinations, rows.Err()
+}
+
+func (r *DataTransferRepository) DeleteBefore(before time.Time) (int64, error) {
+	query := `DELETE FROM agent_data_transfers WHERE occurred_at < $1`
+
+	result, err := r.db.Exec(query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete data transfers: %w", err)
+	}
+
+	return result.RowsAffected()
+}
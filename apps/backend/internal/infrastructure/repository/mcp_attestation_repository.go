@@ -60,13 +60,15 @@ func (r *MCPAttestationRepository) GetAttestationByID(id uuid.UUID) (*domain.MCP
 	query := `
 		SELECT
 			id, mcp_server_id, agent_id, attestation_data, signature,
-			signature_verified, verified_at, expires_at, is_valid, created_at
+			signature_verified, verified_at, expires_at, is_valid, created_at,
+			revoked_at, revoked_by, revocation_reason
 		FROM mcp_attestations
 		WHERE id = $1
 	`
 
 	attestation := &domain.MCPAttestation{}
 	var attestationJSON []byte
+	var revocationReason sql.NullString
 
 	err := r.db.QueryRow(query, id).Scan(
 		&attestation.ID,
@@ -79,6 +81,9 @@ func (r *MCPAttestationRepository) GetAttestationByID(id uuid.UUID) (*domain.MCP
 		&attestation.ExpiresAt,
 		&attestation.IsValid,
 		&attestation.CreatedAt,
+		&attestation.RevokedAt,
+		&attestation.RevokedBy,
+		&revocationReason,
 	)
 
 	if err == sql.ErrNoRows {
@@ -87,6 +92,7 @@ func (r *MCPAttestationRepository) GetAttestationByID(id uuid.UUID) (*domain.MCP
 	if err != nil {
 		return nil, fmt.Errorf("failed to get attestation: %w", err)
 	}
+	attestation.RevocationReason = revocationReason.String
 
 	// Unmarshal attestation data
 	if err := json.Unmarshal(attestationJSON, &attestation.AttestationData); err != nil {
@@ -101,8 +107,12 @@ func (r *MCPAttestationRepository) GetAttestationsByMCP(mcpServerID uuid.UUID) (
 		SELECT
 			a.id, a.mcp_server_id, a.agent_id, a.attestation_data, a.signature,
 			a.signature_verified, a.verified_at, a.expires_at, a.is_valid, a.created_at,
+			a.revoked_at, a.revoked_by, a.revocation_reason,
 			ag.name AS agent_name,
-			ag.trust_score AS agent_trust_score
+			ag.trust_score AS agent_trust_score,
+			ag.organization_id AS agent_organization_id,
+			ag.created_by AS agent_created_by,
+			ag.created_at AS agent_created_at
 		FROM mcp_attestations a
 		LEFT JOIN agents ag ON ag.id = a.agent_id
 		WHERE a.mcp_server_id = $1
@@ -119,6 +129,7 @@ func (r *MCPAttestationRepository) GetAttestationsByMCP(mcpServerID uuid.UUID) (
 	for rows.Next() {
 		attestation := &domain.MCPAttestation{}
 		var attestationJSON []byte
+		var revocationReason sql.NullString
 
 		err := rows.Scan(
 			&attestation.ID,
@@ -131,12 +142,19 @@ func (r *MCPAttestationRepository) GetAttestationsByMCP(mcpServerID uuid.UUID) (
 			&attestation.ExpiresAt,
 			&attestation.IsValid,
 			&attestation.CreatedAt,
+			&attestation.RevokedAt,
+			&attestation.RevokedBy,
+			&revocationReason,
 			&attestation.AgentName,
 			&attestation.AgentTrustScore,
+			&attestation.AgentOrganizationID,
+			&attestation.AgentCreatedBy,
+			&attestation.AgentCreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan attestation: %w", err)
 		}
+		attestation.RevocationReason = revocationReason.String
 
 		// Unmarshal attestation data
 		if err := json.Unmarshal(attestationJSON, &attestation.AttestationData); err != nil {
@@ -154,12 +172,17 @@ func (r *MCPAttestationRepository) GetValidAttestationsByMCP(mcpServerID uuid.UU
 		SELECT
 			a.id, a.mcp_server_id, a.agent_id, a.attestation_data, a.signature,
 			a.signature_verified, a.verified_at, a.expires_at, a.is_valid, a.created_at,
+			a.revoked_at, a.revoked_by, a.revocation_reason,
 			ag.name AS agent_name,
-			ag.trust_score AS agent_trust_score
+			ag.trust_score AS agent_trust_score,
+			ag.organization_id AS agent_organization_id,
+			ag.created_by AS agent_created_by,
+			ag.created_at AS agent_created_at
 		FROM mcp_attestations a
 		LEFT JOIN agents ag ON ag.id = a.agent_id
 		WHERE a.mcp_server_id = $1
 			AND a.is_valid = true
+			AND a.revoked_at IS NULL
 			AND a.expires_at > NOW()
 		ORDER BY a.verified_at DESC
 	`
@@ -174,6 +197,7 @@ func (r *MCPAttestationRepository) GetValidAttestationsByMCP(mcpServerID uuid.UU
 	for rows.Next() {
 		attestation := &domain.MCPAttestation{}
 		var attestationJSON []byte
+		var revocationReason sql.NullString
 
 		err := rows.Scan(
 			&attestation.ID,
@@ -186,12 +210,19 @@ func (r *MCPAttestationRepository) GetValidAttestationsByMCP(mcpServerID uuid.UU
 			&attestation.ExpiresAt,
 			&attestation.IsValid,
 			&attestation.CreatedAt,
+			&attestation.RevokedAt,
+			&attestation.RevokedBy,
+			&revocationReason,
 			&attestation.AgentName,
 			&attestation.AgentTrustScore,
+			&attestation.AgentOrganizationID,
+			&attestation.AgentCreatedBy,
+			&attestation.AgentCreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan attestation: %w", err)
 		}
+		attestation.RevocationReason = revocationReason.String
 
 		// Unmarshal attestation data
 		if err := json.Unmarshal(attestationJSON, &attestation.AttestationData); err != nil {
@@ -287,6 +318,61 @@ func (r *MCPAttestationRepository) InvalidateExpiredAttestations() error {
 	return nil
 }
 
+// RevokeAttestation marks a single attestation as revoked by an admin
+func (r *MCPAttestationRepository) RevokeAttestation(id uuid.UUID, revokedBy uuid.UUID, reason string) error {
+	query := `
+		UPDATE mcp_attestations
+		SET is_valid = false, revoked_at = $2, revoked_by = $3, revocation_reason = $4
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id, time.Now().UTC(), revokedBy, reason)
+	if err != nil {
+		return fmt.Errorf("failed to revoke attestation: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("attestation not found or already revoked")
+	}
+
+	return nil
+}
+
+// RevokeAttestationsByAgent revokes every unrevoked attestation made by an agent and
+// returns the distinct MCP servers that were affected along with the number revoked
+func (r *MCPAttestationRepository) RevokeAttestationsByAgent(agentID uuid.UUID, revokedBy uuid.UUID, reason string) ([]uuid.UUID, int, error) {
+	query := `
+		UPDATE mcp_attestations
+		SET is_valid = false, revoked_at = $2, revoked_by = $3, revocation_reason = $4
+		WHERE agent_id = $1 AND revoked_at IS NULL
+		RETURNING mcp_server_id
+	`
+
+	rows, err := r.db.Query(query, agentID, time.Now().UTC(), revokedBy, reason)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to revoke agent attestations: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[uuid.UUID]bool)
+	mcpServerIDs := []uuid.UUID{}
+	revoked := 0
+	for rows.Next() {
+		var mcpServerID uuid.UUID
+		if err := rows.Scan(&mcpServerID); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan revoked attestation: %w", err)
+		}
+		revoked++
+		if !seen[mcpServerID] {
+			seen[mcpServerID] = true
+			mcpServerIDs = append(mcpServerIDs, mcpServerID)
+		}
+	}
+
+	return mcpServerIDs, revoked, nil
+}
+
 // ==================== Connection Operations ====================
 
 func (r *MCPAttestationRepository) CreateConnection(connection *domain.AgentMCPConnection) error {
@@ -552,7 +638,7 @@ func (r *MCPAttestationRepository) UpdateMCPConfidenceScore(
 		query,
 		score,
 		attestationCount,
-		lastAttestedAt,
+		sql.NullTime{Time: lastAttestedAt, Valid: !lastAttestedAt.IsZero()},
 		time.Now().UTC(),
 		mcpServerID,
 	)
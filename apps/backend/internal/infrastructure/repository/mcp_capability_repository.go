@@ -2,10 +2,12 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/opena2a/identity/backend/internal/domain"
 )
 
@@ -17,17 +19,28 @@ func NewMCPServerCapabilityRepository(db *sql.DB) *MCPServerCapabilityRepository
 	return &MCPServerCapabilityRepository{db: db}
 }
 
+const mcpCapabilityColumns = `
+	id, mcp_server_id, name, capability_type, description,
+	capability_schema, detected_at, last_verified_at, is_active,
+	created_at, updated_at, security_findings, risk_level, scanned_at
+`
+
 func (r *MCPServerCapabilityRepository) Create(capability *domain.MCPServerCapability) error {
 	query := `
 		INSERT INTO mcp_server_capabilities (
 			id, mcp_server_id, name, capability_type, description,
 			capability_schema, detected_at, last_verified_at, is_active,
-			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			created_at, updated_at, security_findings, risk_level, scanned_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.db.QueryRow(
+	findingsJSON, err := marshalCapabilityFindings(capability.SecurityFindings)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRow(
 		query,
 		capability.ID,
 		capability.MCPServerID,
@@ -40,6 +53,9 @@ func (r *MCPServerCapabilityRepository) Create(capability *domain.MCPServerCapab
 		capability.IsActive,
 		time.Now().UTC(),
 		time.Now().UTC(),
+		findingsJSON,
+		riskLevelOrNone(capability.RiskLevel),
+		capability.ScannedAt,
 	).Scan(&capability.ID, &capability.CreatedAt, &capability.UpdatedAt)
 
 	if err != nil {
@@ -49,32 +65,85 @@ func (r *MCPServerCapabilityRepository) Create(capability *domain.MCPServerCapab
 	return nil
 }
 
-func (r *MCPServerCapabilityRepository) GetByID(id uuid.UUID) (*domain.MCPServerCapability, error) {
+// Upsert creates a capability or refreshes the existing row with the same server, name and type
+func (r *MCPServerCapabilityRepository) Upsert(capability *domain.MCPServerCapability) error {
 	query := `
-		SELECT
+		INSERT INTO mcp_server_capabilities (
 			id, mcp_server_id, name, capability_type, description,
 			capability_schema, detected_at, last_verified_at, is_active,
-			created_at, updated_at
-		FROM mcp_server_capabilities
-		WHERE id = $1
+			created_at, updated_at, security_findings, risk_level, scanned_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (mcp_server_id, name, capability_type) DO UPDATE SET
+			description = EXCLUDED.description,
+			capability_schema = EXCLUDED.capability_schema,
+			last_verified_at = EXCLUDED.detected_at,
+			is_active = EXCLUDED.is_active,
+			updated_at = EXCLUDED.updated_at,
+			security_findings = EXCLUDED.security_findings,
+			risk_level = EXCLUDED.risk_level,
+			scanned_at = EXCLUDED.scanned_at
+		RETURNING id, detected_at, created_at, updated_at
 	`
 
-	capability := &domain.MCPServerCapability{}
+	findingsJSON, err := marshalCapabilityFindings(capability.SecurityFindings)
+	if err != nil {
+		return err
+	}
 
-	err := r.db.QueryRow(query, id).Scan(
-		&capability.ID,
-		&capability.MCPServerID,
-		&capability.Name,
-		&capability.CapabilityType,
-		&capability.Description,
-		&capability.CapabilitySchema,
-		&capability.DetectedAt,
-		&capability.LastVerifiedAt,
-		&capability.IsActive,
-		&capability.CreatedAt,
-		&capability.UpdatedAt,
-	)
+	err = r.db.QueryRow(
+		query,
+		capability.ID,
+		capability.MCPServerID,
+		capability.Name,
+		capability.CapabilityType,
+		capability.Description,
+		capability.CapabilitySchema,
+		capability.DetectedAt,
+		capability.LastVerifiedAt,
+		capability.IsActive,
+		time.Now().UTC(),
+		time.Now().UTC(),
+		findingsJSON,
+		riskLevelOrNone(capability.RiskLevel),
+		capability.ScannedAt,
+	).Scan(&capability.ID, &capability.DetectedAt, &capability.CreatedAt, &capability.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert mcp server capability: %w", err)
+	}
+
+	return nil
+}
+
+// DeactivateMissing marks every active capability of a server that is not in keepIDs as inactive
+func (r *MCPServerCapabilityRepository) DeactivateMissing(serverID uuid.UUID, keepIDs []uuid.UUID) error {
+	query := `
+		UPDATE mcp_server_capabilities
+		SET is_active = false, updated_at = $1
+		WHERE mcp_server_id = $2 AND is_active = true AND NOT (id = ANY($3::uuid[]))
+	`
+
+	ids := make([]string, 0, len(keepIDs))
+	for _, id := range keepIDs {
+		ids = append(ids, id.String())
+	}
+
+	_, err := r.db.Exec(query, time.Now().UTC(), serverID, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to deactivate removed capabilities: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MCPServerCapabilityRepository) GetByID(id uuid.UUID) (*domain.MCPServerCapability, error) {
+	query := `
+		SELECT ` + mcpCapabilityColumns + `
+		FROM mcp_server_capabilities
+		WHERE id = $1
+	`
 
+	capability, err := scanMCPCapability(r.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("mcp server capability not found")
 	}
@@ -87,10 +156,7 @@ func (r *MCPServerCapabilityRepository) GetByID(id uuid.UUID) (*domain.MCPServer
 
 func (r *MCPServerCapabilityRepository) GetByServerID(serverID uuid.UUID) ([]*domain.MCPServerCapability, error) {
 	query := `
-		SELECT
-			id, mcp_server_id, name, capability_type, description,
-			capability_schema, detected_at, last_verified_at, is_active,
-			created_at, updated_at
+		SELECT ` + mcpCapabilityColumns + `
 		FROM mcp_server_capabilities
 		WHERE mcp_server_id = $1 AND is_active = true
 		ORDER BY capability_type, name
@@ -104,21 +170,7 @@ func (r *MCPServerCapabilityRepository) GetByServerID(serverID uuid.UUID) ([]*do
 
 	var capabilities []*domain.MCPServerCapability
 	for rows.Next() {
-		capability := &domain.MCPServerCapability{}
-
-		err := rows.Scan(
-			&capability.ID,
-			&capability.MCPServerID,
-			&capability.Name,
-			&capability.CapabilityType,
-			&capability.Description,
-			&capability.CapabilitySchema,
-			&capability.DetectedAt,
-			&capability.LastVerifiedAt,
-			&capability.IsActive,
-			&capability.CreatedAt,
-			&capability.UpdatedAt,
-		)
+		capability, err := scanMCPCapability(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan mcp server capability: %w", err)
 		}
@@ -131,10 +183,7 @@ func (r *MCPServerCapabilityRepository) GetByServerID(serverID uuid.UUID) ([]*do
 
 func (r *MCPServerCapabilityRepository) GetByServerIDAndType(serverID uuid.UUID, capType domain.MCPCapabilityType) ([]*domain.MCPServerCapability, error) {
 	query := `
-		SELECT
-			id, mcp_server_id, name, capability_type, description,
-			capability_schema, detected_at, last_verified_at, is_active,
-			created_at, updated_at
+		SELECT ` + mcpCapabilityColumns + `
 		FROM mcp_server_capabilities
 		WHERE mcp_server_id = $1 AND capability_type = $2 AND is_active = true
 		ORDER BY name
@@ -148,21 +197,7 @@ func (r *MCPServerCapabilityRepository) GetByServerIDAndType(serverID uuid.UUID,
 
 	var capabilities []*domain.MCPServerCapability
 	for rows.Next() {
-		capability := &domain.MCPServerCapability{}
-
-		err := rows.Scan(
-			&capability.ID,
-			&capability.MCPServerID,
-			&capability.Name,
-			&capability.CapabilityType,
-			&capability.Description,
-			&capability.CapabilitySchema,
-			&capability.DetectedAt,
-			&capability.LastVerifiedAt,
-			&capability.IsActive,
-			&capability.CreatedAt,
-			&capability.UpdatedAt,
-		)
+		capability, err := scanMCPCapability(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan mcp server capability: %w", err)
 		}
@@ -182,12 +217,20 @@ func (r *MCPServerCapabilityRepository) Update(capability *domain.MCPServerCapab
 			capability_schema = $3,
 			last_verified_at = $4,
 			is_active = $5,
-			updated_at = $6
-		WHERE id = $7
+			updated_at = $6,
+			security_findings = $7,
+			risk_level = $8,
+			scanned_at = $9
+		WHERE id = $10
 		RETURNING updated_at
 	`
 
-	err := r.db.QueryRow(
+	findingsJSON, err := marshalCapabilityFindings(capability.SecurityFindings)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRow(
 		query,
 		capability.Name,
 		capability.Description,
@@ -195,6 +238,9 @@ func (r *MCPServerCapabilityRepository) Update(capability *domain.MCPServerCapab
 		capability.LastVerifiedAt,
 		capability.IsActive,
 		time.Now().UTC(),
+		findingsJSON,
+		riskLevelOrNone(capability.RiskLevel),
+		capability.ScannedAt,
 		capability.ID,
 	).Scan(&capability.UpdatedAt)
 
@@ -231,3 +277,54 @@ func (r *MCPServerCapabilityRepository) DeleteByServerID(serverID uuid.UUID) err
 
 	return nil
 }
+
+func scanMCPCapability(row rowScanner) (*domain.MCPServerCapability, error) {
+	capability := &domain.MCPServerCapability{}
+	var findingsJSON []byte
+
+	if err := row.Scan(
+		&capability.ID,
+		&capability.MCPServerID,
+		&capability.Name,
+		&capability.CapabilityType,
+		&capability.Description,
+		&capability.CapabilitySchema,
+		&capability.DetectedAt,
+		&capability.LastVerifiedAt,
+		&capability.IsActive,
+		&capability.CreatedAt,
+		&capability.UpdatedAt,
+		&findingsJSON,
+		&capability.RiskLevel,
+		&capability.ScannedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	capability.SecurityFindings = []domain.MCPCapabilityFinding{}
+	if len(findingsJSON) > 0 {
+		if err := json.Unmarshal(findingsJSON, &capability.SecurityFindings); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal capability findings: %w", err)
+		}
+	}
+
+	return capability, nil
+}
+
+func marshalCapabilityFindings(findings []domain.MCPCapabilityFinding) ([]byte, error) {
+	if findings == nil {
+		findings = []domain.MCPCapabilityFinding{}
+	}
+	data, err := json.Marshal(findings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal capability findings: %w", err)
+	}
+	return data, nil
+}
+
+func riskLevelOrNone(level domain.MCPCapabilityRiskLevel) domain.MCPCapabilityRiskLevel {
+	if level == "" {
+		return domain.MCPCapabilityRiskNone
+	}
+	return level
+}
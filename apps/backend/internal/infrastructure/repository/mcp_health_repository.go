@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+type MCPServerHealthRepository struct {
+	db *sql.DB
+}
+
+func NewMCPServerHealthRepository(db *sql.DB) *MCPServerHealthRepository {
+	return &MCPServerHealthRepository{db: db}
+}
+
+func (r *MCPServerHealthRepository) CreateCheck(check *domain.MCPServerHealthCheck) error {
+	query := `
+		INSERT INTO mcp_server_health_checks (
+			id, mcp_server_id, organization_id, reachable, signature_valid,
+			success, latency_ms, http_status, error_reason, checked_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	if check.ID == uuid.Nil {
+		check.ID = uuid.New()
+	}
+	if check.CheckedAt.IsZero() {
+		check.CheckedAt = time.Now().UTC()
+	}
+
+	_, err := r.db.Exec(
+		query,
+		check.ID,
+		check.MCPServerID,
+		check.OrganizationID,
+		check.Reachable,
+		check.SignatureValid,
+		check.Success,
+		check.LatencyMs,
+		check.HTTPStatus,
+		sql.NullString{String: check.ErrorReason, Valid: check.ErrorReason != ""},
+		check.CheckedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create mcp server health check: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MCPServerHealthRepository) GetChecksByServer(serverID uuid.UUID, limit, offset int) ([]*domain.MCPServerHealthCheck, error) {
+	query := `
+		SELECT
+			id, mcp_server_id, organization_id, reachable, signature_valid,
+			success, latency_ms, http_status, error_reason, checked_at
+		FROM mcp_server_health_checks
+		WHERE mcp_server_id = $1
+		ORDER BY checked_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, serverID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mcp server health checks: %w", err)
+	}
+	defer rows.Close()
+
+	checks := []*domain.MCPServerHealthCheck{}
+	for rows.Next() {
+		check := &domain.MCPServerHealthCheck{}
+		var signatureValid sql.NullBool
+		var httpStatus sql.NullInt64
+		var errorReason sql.NullString
+
+		if err := rows.Scan(
+			&check.ID,
+			&check.MCPServerID,
+			&check.OrganizationID,
+			&check.Reachable,
+			&signatureValid,
+			&check.Success,
+			&check.LatencyMs,
+			&httpStatus,
+			&errorReason,
+			&check.CheckedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan mcp server health check: %w", err)
+		}
+
+		if signatureValid.Valid {
+			check.SignatureValid = &signatureValid.Bool
+		}
+		if httpStatus.Valid {
+			status := int(httpStatus.Int64)
+			check.HTTPStatus = &status
+		}
+		check.ErrorReason = errorReason.String
+
+		checks = append(checks, check)
+	}
+
+	return checks, nil
+}
+
+// GetHealth returns the current health state of a server (nil if it was never checked)
+func (r *MCPServerHealthRepository) GetHealth(serverID uuid.UUID) (*domain.MCPServerHealth, error) {
+	query := `
+		SELECT
+			mcp_server_id, health_status, consecutive_failures, last_checked_at,
+			last_success_at, last_failure_reason, downgraded_at, updated_at
+		FROM mcp_server_health
+		WHERE mcp_server_id = $1
+	`
+
+	health := &domain.MCPServerHealth{}
+	var lastFailureReason sql.NullString
+
+	err := r.db.QueryRow(query, serverID).Scan(
+		&health.MCPServerID,
+		&health.HealthStatus,
+		&health.ConsecutiveFailures,
+		&health.LastCheckedAt,
+		&health.LastSuccessAt,
+		&lastFailureReason,
+		&health.DowngradedAt,
+		&health.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mcp server health: %w", err)
+	}
+
+	health.LastFailureReason = lastFailureReason.String
+
+	return health, nil
+}
+
+func (r *MCPServerHealthRepository) UpsertHealth(health *domain.MCPServerHealth) error {
+	query := `
+		INSERT INTO mcp_server_health (
+			mcp_server_id, health_status, consecutive_failures, last_checked_at,
+			last_success_at, last_failure_reason, downgraded_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (mcp_server_id) DO UPDATE SET
+			health_status = EXCLUDED.health_status,
+			consecutive_failures = EXCLUDED.consecutive_failures,
+			last_checked_at = EXCLUDED.last_checked_at,
+			last_success_at = EXCLUDED.last_success_at,
+			last_failure_reason = EXCLUDED.last_failure_reason,
+			downgraded_at = EXCLUDED.downgraded_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	health.UpdatedAt = time.Now().UTC()
+
+	_, err := r.db.Exec(
+		query,
+		health.MCPServerID,
+		health.HealthStatus,
+		health.ConsecutiveFailures,
+		health.LastCheckedAt,
+		health.LastSuccessAt,
+		sql.NullString{String: health.LastFailureReason, Valid: health.LastFailureReason != ""},
+		health.DowngradedAt,
+		health.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert mcp server health: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MCPServerHealthRepository) GetUptime(serverID uuid.UUID, since time.Time) (*domain.MCPServerUptime, error) {
+	query := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE success)
+		FROM mcp_server_health_checks
+		WHERE mcp_server_id = $1 AND checked_at >= $2
+	`
+
+	uptime := &domain.MCPServerUptime{Since: since}
+	if err := r.db.QueryRow(query, serverID, since).Scan(&uptime.TotalChecks, &uptime.SuccessfulChecks); err != nil {
+		return nil, fmt.Errorf("failed to get mcp server uptime: %w", err)
+	}
+	if uptime.TotalChecks > 0 {
+		uptime.Uptime = float64(uptime.SuccessfulChecks) / float64(uptime.TotalChecks)
+	}
+
+	return uptime, nil
+}
+
+// GetUptimeForServerRefs aggregates uptime across the organization's servers whose ID or name is in refs
+func (r *MCPServerHealthRepository) GetUptimeForServerRefs(orgID uuid.UUID, refs []string, since time.Time) (*domain.MCPServerUptime, error) {
+	query := `
+		SELECT COUNT(c.id), COUNT(c.id) FILTER (WHERE c.success)
+		FROM mcp_server_health_checks c
+		JOIN mcp_servers m ON m.id = c.mcp_server_id
+		WHERE m.organization_id = $1
+			AND (m.id::text = ANY($2) OR m.name = ANY($2))
+			AND c.checked_at >= $3
+	`
+
+	uptime := &domain.MCPServerUptime{Since: since}
+	if len(refs) == 0 {
+		return uptime, nil
+	}
+
+	if err := r.db.QueryRow(query, orgID, pq.Array(refs), since).Scan(&uptime.TotalChecks, &uptime.SuccessfulChecks); err != nil {
+		return nil, fmt.Errorf("failed to get mcp server uptime: %w", err)
+	}
+	if uptime.TotalChecks > 0 {
+		uptime.Uptime = float64(uptime.SuccessfulChecks) / float64(uptime.TotalChecks)
+	}
+
+	return uptime, nil
+}
+
+// ListServerIDsDueForCheck returns verified servers and servers currently downgraded by the monitor
+func (r *MCPServerHealthRepository) ListServerIDsDueForCheck() ([]uuid.UUID, error) {
+	query := `
+		SELECT m.id
+		FROM mcp_servers m
+		LEFT JOIN mcp_server_health h ON h.mcp_server_id = m.id
+		WHERE m.status = $1
+			OR (m.status = $2 AND h.downgraded_at IS NOT NULL)
+		ORDER BY h.last_checked_at ASC NULLS FIRST
+	`
+
+	rows, err := r.db.Query(query, domain.MCPServerStatusVerified, domain.MCPServerStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mcp servers due for health check: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan mcp server id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
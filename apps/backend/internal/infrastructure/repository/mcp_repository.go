@@ -24,8 +24,10 @@ func (r *MCPServerRepository) Create(server *domain.MCPServer) error {
 		INSERT INTO mcp_servers (
 			id, organization_id, name, description, url, version,
 			public_key, status, is_verified, verification_url,
-			capabilities, trust_score, registered_by_agent, created_by, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			capabilities, trust_score, registered_by_agent, created_by, created_at, updated_at,
+			transport, command, args, env_names, package_registry, package_name, package_version, identity_hash
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+			$17, $18, $19, $20, $21, $22, $23, NULLIF($24, ''))
 		RETURNING id, created_at, updated_at
 	`
 
@@ -35,6 +37,11 @@ func (r *MCPServerRepository) Create(server *domain.MCPServer) error {
 		return fmt.Errorf("failed to marshal capabilities: %w", err)
 	}
 
+	launch, err := newMCPServerLaunch(server)
+	if err != nil {
+		return err
+	}
+
 	err = r.db.QueryRow(
 		query,
 		server.ID,
@@ -53,6 +60,14 @@ func (r *MCPServerRepository) Create(server *domain.MCPServer) error {
 		server.CreatedBy,          // ✅ FIXED: Added created_by field
 		time.Now().UTC(),
 		time.Now().UTC(),
+		launch.transport,
+		server.Command,
+		launch.args,
+		launch.envNames,
+		launch.pkg.Registry,
+		launch.pkg.Name,
+		launch.pkg.Version,
+		server.IdentityHash,
 	).Scan(&server.ID, &server.CreatedAt, &server.UpdatedAt)
 
 	if err != nil {
@@ -63,20 +78,26 @@ func (r *MCPServerRepository) Create(server *domain.MCPServer) error {
 }
 
 func (r *MCPServerRepository) GetByID(id uuid.UUID) (*domain.MCPServer, error) {
+	return r.getOne("id = $1", id)
+}
+
+// getOne returns the single server matching condition
+func (r *MCPServerRepository) getOne(condition string, args ...interface{}) (*domain.MCPServer, error) {
 	query := `
 		SELECT
-			id, organization_id, name, description, url, version,
+			id, organization_id, name, description, COALESCE(url, ''), version,
 			public_key, status, is_verified, last_verified_at, verification_url,
 			capabilities, trust_score, registered_by_agent, created_by, created_at, updated_at,
-			verification_method, attestation_count, confidence_score, last_attested_at
+			verification_method, attestation_count, confidence_score, last_attested_at,
+			transport, command, args, env_names, package_registry, package_name, package_version, COALESCE(identity_hash, '')
 		FROM mcp_servers
-		WHERE id = $1
-	`
+		WHERE ` + condition
 
 	server := &domain.MCPServer{}
 	var capabilitiesJSON []byte
+	var launch mcpServerLaunch
 
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRow(query, args...).Scan(
 		&server.ID,
 		&server.OrganizationID,
 		&server.Name,
@@ -98,6 +119,14 @@ func (r *MCPServerRepository) GetByID(id uuid.UUID) (*domain.MCPServer, error) {
 		&server.AttestationCount,
 		&server.ConfidenceScore,
 		&server.LastAttestedAt,
+		&server.Transport,
+		&server.Command,
+		&launch.args,
+		&launch.envNames,
+		&launch.pkg.Registry,
+		&launch.pkg.Name,
+		&launch.pkg.Version,
+		&server.IdentityHash,
 	)
 
 	if err == sql.ErrNoRows {
@@ -113,6 +142,9 @@ func (r *MCPServerRepository) GetByID(id uuid.UUID) (*domain.MCPServer, error) {
 			return nil, fmt.Errorf("failed to unmarshal capabilities: %w", err)
 		}
 	}
+	if err := launch.apply(server); err != nil {
+		return nil, err
+	}
 
 	return server, nil
 }
@@ -120,10 +152,12 @@ func (r *MCPServerRepository) GetByID(id uuid.UUID) (*domain.MCPServer, error) {
 func (r *MCPServerRepository) GetByOrganization(orgID uuid.UUID) ([]*domain.MCPServer, error) {
 	query := `
 		SELECT
-			m.id, m.organization_id, m.name, m.description, m.url, m.version,
+			m.id, m.organization_id, m.name, m.description, COALESCE(m.url, ''), m.version,
 			m.public_key, m.status, m.is_verified, m.last_verified_at, m.verification_url,
 			m.capabilities, m.trust_score, m.registered_by_agent, m.created_by, m.created_at, m.updated_at,
 			m.verification_method, m.attestation_count, m.confidence_score, m.last_attested_at,
+			m.transport, m.command, m.args, m.env_names, m.package_registry, m.package_name, m.package_version,
+			COALESCE(m.identity_hash, ''),
 			COALESCE(COUNT(v.id), 0) AS verification_count
 		FROM mcp_servers m
 		LEFT JOIN verification_events v ON v.mcp_server_id = m.id
@@ -131,7 +165,9 @@ func (r *MCPServerRepository) GetByOrganization(orgID uuid.UUID) ([]*domain.MCPS
 		GROUP BY m.id, m.organization_id, m.name, m.description, m.url, m.version,
 			m.public_key, m.status, m.is_verified, m.last_verified_at, m.verification_url,
 			m.capabilities, m.trust_score, m.registered_by_agent, m.created_by, m.created_at, m.updated_at,
-			m.verification_method, m.attestation_count, m.confidence_score, m.last_attested_at
+			m.verification_method, m.attestation_count, m.confidence_score, m.last_attested_at,
+			m.transport, m.command, m.args, m.env_names, m.package_registry, m.package_name, m.package_version,
+			m.identity_hash
 		ORDER BY m.created_at DESC
 	`
 
@@ -145,6 +181,7 @@ func (r *MCPServerRepository) GetByOrganization(orgID uuid.UUID) ([]*domain.MCPS
 	for rows.Next() {
 		server := &domain.MCPServer{}
 		var capabilitiesJSON []byte
+		var launch mcpServerLaunch
 
 		err := rows.Scan(
 			&server.ID,
@@ -168,6 +205,14 @@ func (r *MCPServerRepository) GetByOrganization(orgID uuid.UUID) ([]*domain.MCPS
 			&server.AttestationCount,
 			&server.ConfidenceScore,
 			&server.LastAttestedAt,
+			&server.Transport,
+			&server.Command,
+			&launch.args,
+			&launch.envNames,
+			&launch.pkg.Registry,
+			&launch.pkg.Name,
+			&launch.pkg.Version,
+			&server.IdentityHash,
 			&server.VerificationCount,
 		)
 		if err != nil {
@@ -180,6 +225,9 @@ func (r *MCPServerRepository) GetByOrganization(orgID uuid.UUID) ([]*domain.MCPS
 				return nil, fmt.Errorf("failed to unmarshal capabilities: %w", err)
 			}
 		}
+		if err := launch.apply(server); err != nil {
+			return nil, err
+		}
 
 		servers = append(servers, server)
 	}
@@ -188,58 +236,12 @@ func (r *MCPServerRepository) GetByOrganization(orgID uuid.UUID) ([]*domain.MCPS
 }
 
 func (r *MCPServerRepository) GetByURL(url string) (*domain.MCPServer, error) {
-	query := `
-		SELECT
-			id, organization_id, name, description, url, version,
-			public_key, status, is_verified, last_verified_at, verification_url,
-			capabilities, trust_score, registered_by_agent, created_by, created_at, updated_at,
-			verification_method, attestation_count, confidence_score, last_attested_at
-		FROM mcp_servers
-		WHERE url = $1
-	`
-
-	server := &domain.MCPServer{}
-	var capabilitiesJSON []byte
-
-	err := r.db.QueryRow(query, url).Scan(
-		&server.ID,
-		&server.OrganizationID,
-		&server.Name,
-		&server.Description,
-		&server.URL,
-		&server.Version,
-		&server.PublicKey,
-		&server.Status,
-		&server.IsVerified,
-		&server.LastVerifiedAt,
-		&server.VerificationURL,
-		&capabilitiesJSON, // Read as JSON bytes
-		&server.TrustScore,
-		&server.RegisteredByAgent,
-		&server.CreatedBy,
-		&server.CreatedAt,
-		&server.UpdatedAt,
-		&server.VerificationMethod,
-		&server.AttestationCount,
-		&server.ConfidenceScore,
-		&server.LastAttestedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("mcp server not found")
-	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to get mcp server: %w", err)
-	}
-
-	// Unmarshal capabilities from JSONB
-	if len(capabilitiesJSON) > 0 {
-		if err := json.Unmarshal(capabilitiesJSON, &server.Capabilities); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal capabilities: %w", err)
-		}
-	}
+	return r.getOne("url = $1", url)
+}
 
-	return server, nil
+// GetByIdentityHash finds the stdio server with the given launch spec hash in an organization
+func (r *MCPServerRepository) GetByIdentityHash(orgID uuid.UUID, identityHash string) (*domain.MCPServer, error) {
+	return r.getOne("organization_id = $1 AND identity_hash = $2", orgID, identityHash)
 }
 
 func (r *MCPServerRepository) Update(server *domain.MCPServer) error {
@@ -248,7 +250,7 @@ func (r *MCPServerRepository) Update(server *domain.MCPServer) error {
 		SET
 			name = $1,
 			description = $2,
-			url = $3,
+			url = NULLIF($3, ''),
 			version = $4,
 			public_key = $5,
 			status = $6,
@@ -257,7 +259,15 @@ func (r *MCPServerRepository) Update(server *domain.MCPServer) error {
 			verification_url = $9,
 			capabilities = $10,
 			trust_score = $11,
-			updated_at = $12
+			updated_at = $12,
+			transport = $14,
+			command = $15,
+			args = $16,
+			env_names = $17,
+			package_registry = $18,
+			package_name = $19,
+			package_version = $20,
+			identity_hash = NULLIF($21, '')
 		WHERE id = $13
 		RETURNING updated_at
 	`
@@ -268,6 +278,11 @@ func (r *MCPServerRepository) Update(server *domain.MCPServer) error {
 		return fmt.Errorf("failed to marshal capabilities: %w", err)
 	}
 
+	launch, err := newMCPServerLaunch(server)
+	if err != nil {
+		return err
+	}
+
 	err = r.db.QueryRow(
 		query,
 		server.Name,
@@ -283,6 +298,14 @@ func (r *MCPServerRepository) Update(server *domain.MCPServer) error {
 		server.TrustScore,
 		time.Now().UTC(),
 		server.ID,
+		launch.transport,
+		server.Command,
+		launch.args,
+		launch.envNames,
+		launch.pkg.Registry,
+		launch.pkg.Name,
+		launch.pkg.Version,
+		server.IdentityHash,
 	).Scan(&server.UpdatedAt)
 
 	if err != nil {
@@ -311,9 +334,10 @@ func (r *MCPServerRepository) Delete(id uuid.UUID) error {
 func (r *MCPServerRepository) List(limit, offset int) ([]*domain.MCPServer, error) {
 	query := `
 		SELECT
-			id, organization_id, name, description, url, version,
+			id, organization_id, name, description, COALESCE(url, ''), version,
 			public_key, status, is_verified, last_verified_at, verification_url,
-			capabilities, trust_score, registered_by_agent, created_by, created_at, updated_at
+			capabilities, trust_score, registered_by_agent, created_by, created_at, updated_at,
+			transport, command, args, env_names, package_registry, package_name, package_version, COALESCE(identity_hash, '')
 		FROM mcp_servers
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -329,6 +353,7 @@ func (r *MCPServerRepository) List(limit, offset int) ([]*domain.MCPServer, erro
 	for rows.Next() {
 		server := &domain.MCPServer{}
 		var capabilitiesJSON []byte
+		var launch mcpServerLaunch
 
 		err := rows.Scan(
 			&server.ID,
@@ -348,6 +373,14 @@ func (r *MCPServerRepository) List(limit, offset int) ([]*domain.MCPServer, erro
 			&server.CreatedBy,
 			&server.CreatedAt,
 			&server.UpdatedAt,
+			&server.Transport,
+			&server.Command,
+			&launch.args,
+			&launch.envNames,
+			&launch.pkg.Registry,
+			&launch.pkg.Name,
+			&launch.pkg.Version,
+			&server.IdentityHash,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan mcp server: %w", err)
@@ -359,6 +392,9 @@ func (r *MCPServerRepository) List(limit, offset int) ([]*domain.MCPServer, erro
 				return nil, fmt.Errorf("failed to unmarshal capabilities: %w", err)
 			}
 		}
+		if err := launch.apply(server); err != nil {
+			return nil, err
+		}
 
 		servers = append(servers, server)
 	}
@@ -451,3 +487,59 @@ func (r *MCPServerRepository) VerifyServer(ctx context.Context, serverID uuid.UU
 
 	return nil
 }
+
+// mcpServerLaunch holds the launch spec columns of a server while it is written or scanned
+type mcpServerLaunch struct {
+	transport domain.MCPTransport
+	args      []byte
+	envNames  []byte
+	pkg       domain.MCPPackageRef
+}
+
+func newMCPServerLaunch(server *domain.MCPServer) (*mcpServerLaunch, error) {
+	launch := &mcpServerLaunch{transport: server.Transport}
+	if launch.transport == "" {
+		launch.transport = domain.MCPTransportHTTP
+	}
+	if server.Package != nil {
+		launch.pkg = *server.Package
+	}
+
+	args, envNames := server.Args, server.EnvNames
+	if args == nil {
+		args = []string{}
+	}
+	if envNames == nil {
+		envNames = []string{}
+	}
+
+	var err error
+	if launch.args, err = json.Marshal(args); err != nil {
+		return nil, fmt.Errorf("failed to marshal args: %w", err)
+	}
+	if launch.envNames, err = json.Marshal(envNames); err != nil {
+		return nil, fmt.Errorf("failed to marshal env names: %w", err)
+	}
+
+	return launch, nil
+}
+
+// apply copies the scanned launch spec onto server
+func (l *mcpServerLaunch) apply(server *domain.MCPServer) error {
+	if len(l.args) > 0 {
+		if err := json.Unmarshal(l.args, &server.Args); err != nil {
+			return fmt.Errorf("failed to unmarshal args: %w", err)
+		}
+	}
+	if len(l.envNames) > 0 {
+		if err := json.Unmarshal(l.envNames, &server.EnvNames); err != nil {
+			return fmt.Errorf("failed to unmarshal env names: %w", err)
+		}
+	}
+	if l.pkg.Name != "" {
+		pkg := l.pkg
+		server.Package = &pkg
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+type MCPToolSurfaceRepository struct {
+	db *sql.DB
+}
+
+func NewMCPToolSurfaceRepository(db *sql.DB) *MCPToolSurfaceRepository {
+	return &MCPToolSurfaceRepository{db: db}
+}
+
+const mcpToolSurfaceColumns = `
+	id, mcp_server_id, version, surface_hash, tools, diff,
+	status, reviewed_by, reviewed_at, review_comment, created_at
+`
+
+// Create stores a new snapshot, assigning the next version number for the server
+func (r *MCPToolSurfaceRepository) Create(snapshot *domain.MCPToolSurfaceSnapshot) error {
+	query := `
+		INSERT INTO mcp_tool_surface_snapshots (
+			id, mcp_server_id, version, surface_hash, tools, diff,
+			status, reviewed_by, reviewed_at, review_comment, created_at
+		) VALUES (
+			$1, $2,
+			(SELECT COALESCE(MAX(version), 0) + 1 FROM mcp_tool_surface_snapshots WHERE mcp_server_id = $2),
+			$3, $4, $5, $6, $7, $8, $9, $10
+		)
+		RETURNING version, created_at
+	`
+
+	if snapshot.ID == uuid.Nil {
+		snapshot.ID = uuid.New()
+	}
+
+	toolsJSON, err := json.Marshal(snapshot.Tools)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool fingerprints: %w", err)
+	}
+
+	var diffJSON []byte
+	if snapshot.Diff != nil {
+		diffJSON, err = json.Marshal(snapshot.Diff)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tool surface diff: %w", err)
+		}
+	}
+
+	err = r.db.QueryRow(
+		query,
+		snapshot.ID,
+		snapshot.MCPServerID,
+		snapshot.SurfaceHash,
+		toolsJSON,
+		diffJSON,
+		snapshot.Status,
+		snapshot.ReviewedBy,
+		snapshot.ReviewedAt,
+		snapshot.ReviewComment,
+		time.Now().UTC(),
+	).Scan(&snapshot.Version, &snapshot.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create tool surface snapshot: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MCPToolSurfaceRepository) GetByID(id uuid.UUID) (*domain.MCPToolSurfaceSnapshot, error) {
+	query := `SELECT ` + mcpToolSurfaceColumns + ` FROM mcp_tool_surface_snapshots WHERE id = $1`
+
+	snapshot, err := scanToolSurfaceSnapshot(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tool surface snapshot not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool surface snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (r *MCPToolSurfaceRepository) GetByServerID(serverID uuid.UUID, limit, offset int) ([]*domain.MCPToolSurfaceSnapshot, error) {
+	query := `
+		SELECT ` + mcpToolSurfaceColumns + `
+		FROM mcp_tool_surface_snapshots
+		WHERE mcp_server_id = $1
+		ORDER BY version DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, serverID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool surface snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []*domain.MCPToolSurfaceSnapshot{}
+	for rows.Next() {
+		snapshot, err := scanToolSurfaceSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tool surface snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// GetLatest returns the most recent snapshot regardless of status (nil if none exists)
+func (r *MCPToolSurfaceRepository) GetLatest(serverID uuid.UUID) (*domain.MCPToolSurfaceSnapshot, error) {
+	query := `
+		SELECT ` + mcpToolSurfaceColumns + `
+		FROM mcp_tool_surface_snapshots
+		WHERE mcp_server_id = $1
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	snapshot, err := scanToolSurfaceSnapshot(r.db.QueryRow(query, serverID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest tool surface snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// GetLatestApproved returns the current trusted baseline (nil if none exists)
+func (r *MCPToolSurfaceRepository) GetLatestApproved(serverID uuid.UUID) (*domain.MCPToolSurfaceSnapshot, error) {
+	query := `
+		SELECT ` + mcpToolSurfaceColumns + `
+		FROM mcp_tool_surface_snapshots
+		WHERE mcp_server_id = $1 AND status = $2
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	snapshot, err := scanToolSurfaceSnapshot(r.db.QueryRow(query, serverID, domain.MCPToolSurfaceStatusApproved))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approved tool surface snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// GetRejectedBySurfaceHash returns the newest rejected snapshot with the given surface hash (nil if none exists)
+func (r *MCPToolSurfaceRepository) GetRejectedBySurfaceHash(serverID uuid.UUID, surfaceHash string) (*domain.MCPToolSurfaceSnapshot, error) {
+	query := `
+		SELECT ` + mcpToolSurfaceColumns + `
+		FROM mcp_tool_surface_snapshots
+		WHERE mcp_server_id = $1 AND surface_hash = $2 AND status = $3
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	snapshot, err := scanToolSurfaceSnapshot(r.db.QueryRow(query, serverID, surfaceHash, domain.MCPToolSurfaceStatusRejected))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rejected tool surface snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (r *MCPToolSurfaceRepository) UpdateStatus(id uuid.UUID, status domain.MCPToolSurfaceStatus, reviewedBy *uuid.UUID, comment string) error {
+	query := `
+		UPDATE mcp_tool_surface_snapshots
+		SET status = $1, reviewed_by = $2, reviewed_at = $3, review_comment = $4
+		WHERE id = $5
+	`
+
+	result, err := r.db.Exec(query, status, reviewedBy, time.Now().UTC(), comment, id)
+	if err != nil {
+		return fmt.Errorf("failed to update tool surface snapshot: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("tool surface snapshot not found")
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanToolSurfaceSnapshot(row rowScanner) (*domain.MCPToolSurfaceSnapshot, error) {
+	snapshot := &domain.MCPToolSurfaceSnapshot{}
+	var toolsJSON, diffJSON []byte
+	var reviewComment sql.NullString
+
+	if err := row.Scan(
+		&snapshot.ID,
+		&snapshot.MCPServerID,
+		&snapshot.Version,
+		&snapshot.SurfaceHash,
+		&toolsJSON,
+		&diffJSON,
+		&snapshot.Status,
+		&snapshot.ReviewedBy,
+		&snapshot.ReviewedAt,
+		&reviewComment,
+		&snapshot.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	snapshot.ReviewComment = reviewComment.String
+
+	if len(toolsJSON) > 0 {
+		if err := json.Unmarshal(toolsJSON, &snapshot.Tools); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tool fingerprints: %w", err)
+		}
+	}
+	if len(diffJSON) > 0 {
+		snapshot.Diff = &domain.MCPToolSurfaceDiff{}
+		if err := json.Unmarshal(diffJSON, snapshot.Diff); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tool surface diff: %w", err)
+		}
+	}
+
+	return snapshot, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/opena2a/identity/backend/internal/domain"
 )
 
@@ -22,8 +23,8 @@ func NewOrganizationRepository(db *sql.DB) *OrganizationRepository {
 // Create creates a new organization
 func (r *OrganizationRepository) Create(org *domain.Organization) error {
 	query := `
-		INSERT INTO organizations (id, name, domain, plan_type, max_agents, max_users, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO organizations (id, name, domain, plan_type, max_agents, max_users, is_active, created_at, updated_at, parent_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	now := time.Now()
@@ -41,6 +42,7 @@ func (r *OrganizationRepository) Create(org *domain.Organization) error {
 		org.IsActive,
 		org.CreatedAt,
 		org.UpdatedAt,
+		org.ParentID,
 	)
 
 	return err
@@ -49,7 +51,7 @@ func (r *OrganizationRepository) Create(org *domain.Organization) error {
 // GetByID retrieves an organization by ID
 func (r *OrganizationRepository) GetByID(id uuid.UUID) (*domain.Organization, error) {
 	query := `
-		SELECT id, name, domain, plan_type, max_agents, max_users, is_active, created_at, updated_at
+		SELECT id, name, domain, plan_type, max_agents, max_users, is_active, created_at, updated_at, parent_id
 		FROM organizations
 		WHERE id = $1
 	`
@@ -65,6 +67,7 @@ func (r *OrganizationRepository) GetByID(id uuid.UUID) (*domain.Organization, er
 		&org.IsActive,
 		&org.CreatedAt,
 		&org.UpdatedAt,
+		&org.ParentID,
 	)
 
 	if err == sql.ErrNoRows {
@@ -80,7 +83,7 @@ func (r *OrganizationRepository) GetByID(id uuid.UUID) (*domain.Organization, er
 // GetByDomain retrieves an organization by domain
 func (r *OrganizationRepository) GetByDomain(domainName string) (*domain.Organization, error) {
 	query := `
-		SELECT id, name, domain, plan_type, max_agents, max_users, is_active, created_at, updated_at
+		SELECT id, name, domain, plan_type, max_agents, max_users, is_active, created_at, updated_at, parent_id
 		FROM organizations
 		WHERE domain = $1
 	`
@@ -96,6 +99,7 @@ func (r *OrganizationRepository) GetByDomain(domainName string) (*domain.Organiz
 		&org.IsActive,
 		&org.CreatedAt,
 		&org.UpdatedAt,
+		&org.ParentID,
 	)
 
 	if err == sql.ErrNoRows {
@@ -137,3 +141,190 @@ func (r *OrganizationRepository) Delete(id uuid.UUID) error {
 	_, err := r.db.Exec(query, id)
 	return err
 }
+
+// ListActiveIDs returns the IDs of all active organizations (used by scheduled jobs)
+func (r *OrganizationRepository) ListActiveIDs() ([]uuid.UUID, error) {
+	query := `SELECT id FROM organizations WHERE is_active = true ORDER BY created_at`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan organization id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+const organizationColumns = `id, name, domain, plan_type, max_agents, max_users, is_active, created_at, updated_at, parent_id`
+
+func scanOrganizations(rows *sql.Rows) ([]*domain.Organization, error) {
+	orgs := []*domain.Organization{}
+	for rows.Next() {
+		org := &domain.Organization{}
+		if err := rows.Scan(
+			&org.ID,
+			&org.Name,
+			&org.Domain,
+			&org.PlanType,
+			&org.MaxAgents,
+			&org.MaxUsers,
+			&org.IsActive,
+			&org.CreatedAt,
+			&org.UpdatedAt,
+			&org.ParentID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan organization: %w", err)
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, rows.Err()
+}
+
+// GetChildren returns the direct children of an organization
+func (r *OrganizationRepository) GetChildren(parentID uuid.UUID) ([]*domain.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations WHERE parent_id = $1 ORDER BY name`
+
+	rows, err := r.db.Query(query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list child organizations: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOrganizations(rows)
+}
+
+// GetAncestorIDs returns the IDs of an organization's ancestors, nearest first
+func (r *OrganizationRepository) GetAncestorIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_id, 1 AS depth FROM organizations WHERE id = $1
+			UNION ALL
+			SELECT o.parent_id, a.depth + 1
+			FROM organizations o
+			JOIN ancestors a ON o.id = a.parent_id
+			WHERE a.depth < 32
+		)
+		SELECT parent_id FROM ancestors WHERE parent_id IS NOT NULL ORDER BY depth
+	`
+
+	rows, err := r.db.Query(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization ancestors: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var ancestorID uuid.UUID
+		if err := rows.Scan(&ancestorID); err != nil {
+			return nil, fmt.Errorf("failed to scan organization id: %w", err)
+		}
+		ids = append(ids, ancestorID)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetSubtree returns the organization and all of its descendants, parents before children
+func (r *OrganizationRepository) GetSubtree(rootID uuid.UUID) ([]*domain.Organization, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth FROM organizations WHERE id = $1
+			UNION ALL
+			SELECT o.id, s.depth + 1
+			FROM organizations o
+			JOIN subtree s ON o.parent_id = s.id
+			WHERE s.depth < 32
+		)
+		SELECT ` + organizationColumns + `
+		FROM organizations
+		JOIN subtree USING (id)
+		ORDER BY subtree.depth, name
+	`
+
+	rows, err := r.db.Query(query, rootID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization subtree: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOrganizations(rows)
+}
+
+// MoveUser moves a user into another organization
+func (r *OrganizationRepository) MoveUser(userID, orgID uuid.UUID) error {
+	query := `UPDATE users SET organization_id = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := r.db.Exec(query, orgID, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to move user: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// GetRollup returns the analytics of each organization; verifications are counted since the given time
+func (r *OrganizationRepository) GetRollup(orgIDs []uuid.UUID, since time.Time) ([]*domain.OrganizationRollup, error) {
+	query := `
+		SELECT
+			o.id, o.name, o.parent_id,
+			(SELECT COUNT(*) FROM agents a WHERE a.organization_id = o.id),
+			(SELECT COUNT(*) FROM agents a WHERE a.organization_id = o.id AND a.status = 'verified'),
+			(SELECT COALESCE(AVG(a.trust_score), 0) FROM agents a WHERE a.organization_id = o.id),
+			(SELECT COUNT(*) FROM mcp_servers m WHERE m.organization_id = o.id),
+			(SELECT COUNT(*) FROM users u WHERE u.organization_id = o.id AND u.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM alerts al WHERE al.organization_id = o.id AND al.is_acknowledged = false),
+			(SELECT COUNT(*) FROM alerts al WHERE al.organization_id = o.id AND al.is_acknowledged = false AND al.severity = 'critical'),
+			(SELECT COUNT(*) FROM verification_events v WHERE v.organization_id = o.id AND v.started_at >= $2),
+			(SELECT COUNT(*) FROM verification_events v WHERE v.organization_id = o.id AND v.started_at >= $2 AND v.status = 'failed')
+		FROM organizations o
+		WHERE o.id = ANY($1::uuid[])
+	`
+
+	ids := make([]string, len(orgIDs))
+	for i, id := range orgIDs {
+		ids[i] = id.String()
+	}
+
+	rows, err := r.db.Query(query, pq.Array(ids), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization rollup: %w", err)
+	}
+	defer rows.Close()
+
+	rollups := []*domain.OrganizationRollup{}
+	for rows.Next() {
+		rollup := &domain.OrganizationRollup{}
+		if err := rows.Scan(
+			&rollup.OrganizationID,
+			&rollup.Name,
+			&rollup.ParentID,
+			&rollup.TotalAgents,
+			&rollup.VerifiedAgents,
+			&rollup.AvgTrustScore,
+			&rollup.TotalMCPServers,
+			&rollup.TotalUsers,
+			&rollup.OpenAlerts,
+			&rollup.CriticalAlerts,
+			&rollup.TotalVerifications,
+			&rollup.FailedVerifications,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan organization rollup: %w", err)
+		}
+		rollups = append(rollups, rollup)
+	}
+
+	return rollups, rows.Err()
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuotaRepository counts what organizations have created for plan quota enforcement and
+// meters verify-action calls in organization_daily_metrics
+type QuotaRepository struct {
+	db *sql.DB
+}
+
+func NewQuotaRepository(db *sql.DB) *QuotaRepository {
+	return &QuotaRepository{db: db}
+}
+
+func (r *QuotaRepository) count(query string, args ...interface{}) (int, error) {
+	var count int
+	if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *QuotaRepository) CountAgents(orgID uuid.UUID) (int, error) {
+	count, err := r.count(`SELECT COUNT(*) FROM agents WHERE organization_id = $1`, orgID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count agents: %w", err)
+	}
+	return count, nil
+}
+
+func (r *QuotaRepository) CountUsers(orgID uuid.UUID) (int, error) {
+	count, err := r.count(`
+		SELECT COUNT(*) FROM users
+		WHERE organization_id = $1 AND deleted_at IS NULL AND status <> 'deactivated'
+	`, orgID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+func (r *QuotaRepository) CountMCPServers(orgID uuid.UUID) (int, error) {
+	count, err := r.count(`SELECT COUNT(*) FROM mcp_servers WHERE organization_id = $1`, orgID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count mcp servers: %w", err)
+	}
+	return count, nil
+}
+
+func (r *QuotaRepository) CountVerifications(orgID uuid.UUID, since time.Time) (int, error) {
+	count, err := r.count(`
+		SELECT COALESCE(SUM(total_verifications), 0) FROM organization_daily_metrics
+		WHERE organization_id = $1 AND date >= $2::date
+	`, orgID, since.UTC().Format("2006-01-02"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count verifications: %w", err)
+	}
+	return count, nil
+}
+
+func (r *QuotaRepository) RecordVerification(orgID uuid.UUID, at time.Time, success bool) error {
+	query := `
+		INSERT INTO organization_daily_metrics (
+			organization_id, date, total_verifications, successful_verifications, failed_verifications
+		) VALUES ($1, $2::date, 1, $3, $4)
+		ON CONFLICT (organization_id, date) DO UPDATE SET
+			total_verifications = organization_daily_metrics.total_verifications + 1,
+			successful_verifications = organization_daily_metrics.successful_verifications + EXCLUDED.successful_verifications,
+			failed_verifications = organization_daily_metrics.failed_verifications + EXCLUDED.failed_verifications,
+			updated_at = NOW()
+	`
+
+	successful, failed := 0, 1
+	if success {
+		successful, failed = 1, 0
+	}
+	if _, err := r.db.Exec(query, orgID, at.UTC().Format("2006-01-02"), successful, failed); err != nil {
+		return fmt.Errorf("failed to record verification: %w", err)
+	}
+
+	return nil
+}
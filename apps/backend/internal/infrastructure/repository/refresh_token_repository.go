@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// RefreshTokenRepository implements domain.RefreshTokenRepository
+type RefreshTokenRepository struct {
+	db *sql.DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Create stores a new refresh token
+func (r *RefreshTokenRepository) Create(token *domain.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (
+			id, family_id, parent_id, user_id, organization_id, token_hash,
+			ip_address, user_agent, created_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		token.ID,
+		token.FamilyID,
+		token.ParentID,
+		token.UserID,
+		token.OrganizationID,
+		token.TokenHash,
+		token.IPAddress,
+		token.UserAgent,
+		token.CreatedAt,
+		token.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a token by its JWT token ID, or nil if unknown
+func (r *RefreshTokenRepository) GetByID(id uuid.UUID) (*domain.RefreshToken, error) {
+	query := `
+		SELECT id, family_id, parent_id, user_id, organization_id, token_hash,
+		       ip_address, user_agent, created_at, expires_at, rotated_at, revoked_at, revoke_reason
+		FROM refresh_tokens
+		WHERE id = $1
+	`
+
+	token := &domain.RefreshToken{}
+	err := r.db.QueryRow(query, id).Scan(
+		&token.ID,
+		&token.FamilyID,
+		&token.ParentID,
+		&token.UserID,
+		&token.OrganizationID,
+		&token.TokenHash,
+		&token.IPAddress,
+		&token.UserAgent,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.RotatedAt,
+		&token.RevokedAt,
+		&token.RevokeReason,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// MarkRotated marks a token as rotated; false means another request already rotated or revoked it
+func (r *RefreshTokenRepository) MarkRotated(id uuid.UUID, at time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE refresh_tokens SET rotated_at = $2
+		WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL
+	`, id, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark refresh token rotated: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark refresh token rotated: %w", err)
+	}
+
+	return rows == 1, nil
+}
+
+// RevokeFamily revokes every token of a family
+func (r *RefreshTokenRepository) RevokeFamily(familyID uuid.UUID, reason string, at time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE refresh_tokens SET revoked_at = $2, revoke_reason = $3
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`, familyID, at, reason)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+// IsFamilyActive returns true if the family has an unrevoked, unexpired token left
+func (r *RefreshTokenRepository) IsFamilyActive(familyID uuid.UUID) (bool, error) {
+	var active bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM refresh_tokens
+			WHERE family_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		)
+	`, familyID).Scan(&active)
+	if err != nil {
+		return false, fmt.Errorf("failed to check refresh token family: %w", err)
+	}
+
+	return active, nil
+}
+
+// ListSessions returns a user's active families, most recently used first. The newest token of
+// a family is the only one not yet rotated.
+func (r *RefreshTokenRepository) ListSessions(userID uuid.UUID) ([]*domain.UserSession, error) {
+	query := `
+		SELECT t.family_id, t.ip_address, t.user_agent, root.created_at, t.created_at, t.expires_at
+		FROM refresh_tokens t
+		JOIN refresh_tokens root ON root.id = t.family_id
+		WHERE t.user_id = $1
+		  AND t.rotated_at IS NULL
+		  AND t.revoked_at IS NULL
+		  AND t.expires_at > NOW()
+		ORDER BY t.created_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*domain.UserSession{}
+	for rows.Next() {
+		session := &domain.UserSession{}
+		if err := rows.Scan(
+			&session.ID,
+			&session.IPAddress,
+			&session.UserAgent,
+			&session.CreatedAt,
+			&session.LastActiveAt,
+			&session.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// DeleteExpired removes families whose tokens have all expired. Revoked and rotated tokens are
+// kept until then so a replayed token is still recognised.
+func (r *RefreshTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`
+		DELETE FROM refresh_tokens
+		WHERE family_id IN (
+			SELECT family_id FROM refresh_tokens
+			GROUP BY family_id
+			HAVING MAX(expires_at) < $1
+		)
+	`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+type ScheduledJobRepository struct {
+	db *sql.DB
+}
+
+func NewScheduledJobRepository(db *sql.DB) *ScheduledJobRepository {
+	return &ScheduledJobRepository{db: db}
+}
+
+// TryLock takes a session-level Postgres advisory lock for the job on a dedicated connection,
+// so only one replica runs a job at a time. The lock is released by the returned function
+// (or automatically if the connection dies).
+func (r *ScheduledJobRepository) TryLock(ctx context.Context, jobName string) (func(), bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get connection for job lock: %w", err)
+	}
+
+	key := scheduledJobLockKey(jobName)
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to acquire job lock: %w", err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		// Use a fresh context so the lock is released even if the job context was cancelled
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.ExecContext(unlockCtx, "SELECT pg_advisory_unlock($1)", key); err != nil {
+			fmt.Printf("⚠️  Failed to release lock for job %s: %v\n", jobName, err)
+		}
+		conn.Close()
+	}
+
+	return unlock, true, nil
+}
+
+// scheduledJobLockKey maps a job name to a stable advisory lock key
+func scheduledJobLockKey(jobName string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("scheduled_job:" + jobName))
+	return int64(h.Sum64())
+}
+
+func (r *ScheduledJobRepository) CreateRun(run *domain.ScheduledJobRun) error {
+	query := `
+		INSERT INTO scheduled_job_runs (
+			id, job_name, trigger, triggered_by, status, scheduled_for, started_at, instance_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	if run.ID == uuid.Nil {
+		run.ID = uuid.New()
+	}
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now().UTC()
+	}
+
+	_, err := r.db.Exec(
+		query,
+		run.ID,
+		run.JobName,
+		run.Trigger,
+		run.TriggeredBy,
+		run.Status,
+		run.ScheduledFor,
+		run.StartedAt,
+		run.InstanceID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled job run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ScheduledJobRepository) FinishRun(run *domain.ScheduledJobRun) error {
+	query := `
+		UPDATE scheduled_job_runs
+		SET status = $2, finished_at = $3, duration_ms = $4, error = $5
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(
+		query,
+		run.ID,
+		run.Status,
+		run.FinishedAt,
+		run.DurationMs,
+		sql.NullString{String: run.Error, Valid: run.Error != ""},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish scheduled job run: %w", err)
+	}
+
+	return nil
+}
+
+const scheduledJobRunColumns = `
+	id, job_name, trigger, triggered_by, status, scheduled_for,
+	started_at, finished_at, duration_ms, error, instance_id
+`
+
+func scanScheduledJobRun(row rowScanner) (*domain.ScheduledJobRun, error) {
+	run := &domain.ScheduledJobRun{}
+	var errorText sql.NullString
+
+	if err := row.Scan(
+		&run.ID,
+		&run.JobName,
+		&run.Trigger,
+		&run.TriggeredBy,
+		&run.Status,
+		&run.ScheduledFor,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.DurationMs,
+		&errorText,
+		&run.InstanceID,
+	); err != nil {
+		return nil, err
+	}
+
+	run.Error = errorText.String
+	return run, nil
+}
+
+func (r *ScheduledJobRepository) GetRunsByJob(jobName string, limit, offset int) ([]*domain.ScheduledJobRun, error) {
+	query := `SELECT ` + scheduledJobRunColumns + `
+		FROM scheduled_job_runs
+		WHERE job_name = $1
+		ORDER BY started_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, jobName, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled job runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []*domain.ScheduledJobRun{}
+	for rows.Next() {
+		run, err := scanScheduledJobRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled job run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// GetLastRun returns the most recent run of a job (nil if it never ran)
+func (r *ScheduledJobRepository) GetLastRun(jobName string) (*domain.ScheduledJobRun, error) {
+	query := `SELECT ` + scheduledJobRunColumns + `
+		FROM scheduled_job_runs
+		WHERE job_name = $1
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+
+	run, err := scanScheduledJobRun(r.db.QueryRow(query, jobName))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last scheduled job run: %w", err)
+	}
+
+	return run, nil
+}
+
+// HasScheduledRun reports whether any replica already ran the given cron slot
+func (r *ScheduledJobRepository) HasScheduledRun(jobName string, scheduledFor time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM scheduled_job_runs
+			WHERE job_name = $1 AND scheduled_for >= $2
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRow(query, jobName, scheduledFor).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check scheduled job run: %w", err)
+	}
+
+	return exists, nil
+}
@@ -22,8 +22,8 @@ func NewSecurityPolicyRepository(db *sql.DB) *SecurityPolicyRepository {
 // Create creates a new security policy
 func (r *SecurityPolicyRepository) Create(policy *domain.SecurityPolicy) error {
 	query := `
-		INSERT INTO security_policies (id, organization_id, name, description, policy_type, enforcement_action, severity_threshold, rules, applies_to, is_enabled, priority, created_at, updated_at, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		INSERT INTO security_policies (id, organization_id, name, description, policy_type, enforcement_action, severity_threshold, rules, applies_to, is_enabled, priority, created_at, updated_at, created_by, inheritable)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
 	if policy.ID == uuid.Nil {
@@ -57,6 +57,7 @@ func (r *SecurityPolicyRepository) Create(policy *domain.SecurityPolicy) error {
 		policy.CreatedAt,
 		policy.UpdatedAt,
 		policy.CreatedBy,
+		policy.Inheritable,
 	)
 	return err
 }
@@ -64,7 +65,7 @@ func (r *SecurityPolicyRepository) Create(policy *domain.SecurityPolicy) error {
 // GetByID retrieves a security policy by ID
 func (r *SecurityPolicyRepository) GetByID(id uuid.UUID) (*domain.SecurityPolicy, error) {
 	query := `
-		SELECT id, organization_id, name, description, policy_type, enforcement_action, severity_threshold, rules, applies_to, is_enabled, priority, created_at, updated_at, created_by
+		SELECT id, organization_id, name, description, policy_type, enforcement_action, severity_threshold, rules, applies_to, is_enabled, priority, created_at, updated_at, created_by, inheritable
 		FROM security_policies
 		WHERE id = $1
 	`
@@ -87,6 +88,7 @@ func (r *SecurityPolicyRepository) GetByID(id uuid.UUID) (*domain.SecurityPolicy
 		&policy.CreatedAt,
 		&policy.UpdatedAt,
 		&policy.CreatedBy,
+		&policy.Inheritable,
 	)
 	if err != nil {
 		return nil, err
@@ -103,7 +105,7 @@ func (r *SecurityPolicyRepository) GetByID(id uuid.UUID) (*domain.SecurityPolicy
 // GetByOrganization retrieves all security policies for an organization
 func (r *SecurityPolicyRepository) GetByOrganization(orgID uuid.UUID) ([]*domain.SecurityPolicy, error) {
 	query := `
-		SELECT id, organization_id, name, description, policy_type, enforcement_action, severity_threshold, rules, applies_to, is_enabled, priority, created_at, updated_at, created_by
+		SELECT id, organization_id, name, description, policy_type, enforcement_action, severity_threshold, rules, applies_to, is_enabled, priority, created_at, updated_at, created_by, inheritable
 		FROM security_policies
 		WHERE organization_id = $1
 		ORDER BY priority DESC, created_at DESC
@@ -135,6 +137,7 @@ func (r *SecurityPolicyRepository) GetByOrganization(orgID uuid.UUID) ([]*domain
 			&policy.CreatedAt,
 			&policy.UpdatedAt,
 			&policy.CreatedBy,
+			&policy.Inheritable,
 		); err != nil {
 			return nil, err
 		}
@@ -153,7 +156,7 @@ func (r *SecurityPolicyRepository) GetByOrganization(orgID uuid.UUID) ([]*domain
 // GetActiveByOrganization retrieves all active security policies for an organization
 func (r *SecurityPolicyRepository) GetActiveByOrganization(orgID uuid.UUID) ([]*domain.SecurityPolicy, error) {
 	query := `
-		SELECT id, organization_id, name, description, policy_type, enforcement_action, severity_threshold, rules, applies_to, is_enabled, priority, created_at, updated_at, created_by
+		SELECT id, organization_id, name, description, policy_type, enforcement_action, severity_threshold, rules, applies_to, is_enabled, priority, created_at, updated_at, created_by, inheritable
 		FROM security_policies
 		WHERE organization_id = $1 AND is_enabled = true
 		ORDER BY priority DESC, created_at DESC
@@ -185,6 +188,7 @@ func (r *SecurityPolicyRepository) GetActiveByOrganization(orgID uuid.UUID) ([]*
 			&policy.CreatedAt,
 			&policy.UpdatedAt,
 			&policy.CreatedBy,
+			&policy.Inheritable,
 		); err != nil {
 			return nil, err
 		}
@@ -203,7 +207,7 @@ func (r *SecurityPolicyRepository) GetActiveByOrganization(orgID uuid.UUID) ([]*
 // GetByType retrieves security policies by type for an organization
 func (r *SecurityPolicyRepository) GetByType(orgID uuid.UUID, policyType domain.PolicyType) ([]*domain.SecurityPolicy, error) {
 	query := `
-		SELECT id, organization_id, name, description, policy_type, enforcement_action, severity_threshold, rules, applies_to, is_enabled, priority, created_at, updated_at, created_by
+		SELECT id, organization_id, name, description, policy_type, enforcement_action, severity_threshold, rules, applies_to, is_enabled, priority, created_at, updated_at, created_by, inheritable
 		FROM security_policies
 		WHERE organization_id = $1 AND policy_type = $2 AND is_enabled = true
 		ORDER BY priority DESC, created_at DESC
@@ -235,6 +239,7 @@ func (r *SecurityPolicyRepository) GetByType(orgID uuid.UUID, policyType domain.
 			&policy.CreatedAt,
 			&policy.UpdatedAt,
 			&policy.CreatedBy,
+			&policy.Inheritable,
 		); err != nil {
 			return nil, err
 		}
@@ -254,8 +259,8 @@ func (r *SecurityPolicyRepository) GetByType(orgID uuid.UUID, policyType domain.
 func (r *SecurityPolicyRepository) Update(policy *domain.SecurityPolicy) error {
 	query := `
 		UPDATE security_policies
-		SET name = $1, description = $2, policy_type = $3, enforcement_action = $4, severity_threshold = $5, rules = $6, applies_to = $7, is_enabled = $8, priority = $9, updated_at = $10
-		WHERE id = $11
+		SET name = $1, description = $2, policy_type = $3, enforcement_action = $4, severity_threshold = $5, rules = $6, applies_to = $7, is_enabled = $8, priority = $9, updated_at = $10, inheritable = $11
+		WHERE id = $12
 	`
 
 	policy.UpdatedAt = time.Now()
@@ -277,6 +282,7 @@ func (r *SecurityPolicyRepository) Update(policy *domain.SecurityPolicy) error {
 		policy.IsEnabled,
 		policy.Priority,
 		policy.UpdatedAt,
+		policy.Inheritable,
 		policy.ID,
 	)
 	return err
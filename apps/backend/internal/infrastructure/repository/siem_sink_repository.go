@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+type SIEMSinkRepository struct {
+	db *sql.DB
+}
+
+func NewSIEMSinkRepository(db *sql.DB) *SIEMSinkRepository {
+	return &SIEMSinkRepository{db: db}
+}
+
+func siemEventTypesToStrings(eventTypes []domain.SIEMEventType) []string {
+	values := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		values[i] = string(eventType)
+	}
+	return values
+}
+
+func (r *SIEMSinkRepository) Create(sink *domain.SIEMSink) error {
+	query := `
+		INSERT INTO siem_sinks (
+			id, organization_id, name, type, endpoint, format, use_tls, tls_ca_cert, token,
+			event_types, min_severity, buffer_size, is_active, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`
+
+	if sink.ID == uuid.Nil {
+		sink.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	sink.CreatedAt = now
+	sink.UpdatedAt = now
+
+	_, err := r.db.Exec(
+		query,
+		sink.ID,
+		sink.OrganizationID,
+		sink.Name,
+		sink.Type,
+		sink.Endpoint,
+		sql.NullString{String: string(sink.Format), Valid: sink.Format != ""},
+		sink.UseTLS,
+		sql.NullString{String: sink.TLSCACert, Valid: sink.TLSCACert != ""},
+		sql.NullString{String: sink.Token, Valid: sink.Token != ""},
+		pq.Array(siemEventTypesToStrings(sink.EventTypes)),
+		sink.MinSeverity,
+		sink.BufferSize,
+		sink.IsActive,
+		sink.CreatedBy,
+		sink.CreatedAt,
+		sink.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create siem sink: %w", err)
+	}
+
+	return nil
+}
+
+const siemSinkColumns = `
+	id, organization_id, name, type, endpoint, format, use_tls, tls_ca_cert, token,
+	event_types, min_severity, buffer_size, is_active, created_by, created_at, updated_at
+`
+
+func scanSIEMSink(row rowScanner) (*domain.SIEMSink, error) {
+	sink := &domain.SIEMSink{}
+	var format, caCert, token sql.NullString
+	var eventTypes []string
+	var createdBy uuid.NullUUID
+
+	if err := row.Scan(
+		&sink.ID,
+		&sink.OrganizationID,
+		&sink.Name,
+		&sink.Type,
+		&sink.Endpoint,
+		&format,
+		&sink.UseTLS,
+		&caCert,
+		&token,
+		pq.Array(&eventTypes),
+		&sink.MinSeverity,
+		&sink.BufferSize,
+		&sink.IsActive,
+		&createdBy,
+		&sink.CreatedAt,
+		&sink.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	sink.Format = domain.SIEMMessageFormat(format.String)
+	sink.TLSCACert = caCert.String
+	sink.Token = token.String
+	sink.CreatedBy = createdBy.UUID
+	sink.EventTypes = make([]domain.SIEMEventType, len(eventTypes))
+	for i, eventType := range eventTypes {
+		sink.EventTypes[i] = domain.SIEMEventType(eventType)
+	}
+
+	return sink, nil
+}
+
+func (r *SIEMSinkRepository) GetByID(id uuid.UUID) (*domain.SIEMSink, error) {
+	query := `SELECT ` + siemSinkColumns + ` FROM siem_sinks WHERE id = $1`
+
+	sink, err := scanSIEMSink(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("siem sink not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get siem sink: %w", err)
+	}
+
+	return sink, nil
+}
+
+func (r *SIEMSinkRepository) GetByOrganization(orgID uuid.UUID) ([]*domain.SIEMSink, error) {
+	query := `SELECT ` + siemSinkColumns + `
+		FROM siem_sinks
+		WHERE organization_id = $1
+		ORDER BY created_at ASC
+	`
+
+	return r.query(query, orgID)
+}
+
+// ListActive returns the active sinks of every organization
+func (r *SIEMSinkRepository) ListActive() ([]*domain.SIEMSink, error) {
+	query := `SELECT ` + siemSinkColumns + `
+		FROM siem_sinks
+		WHERE is_active = true
+		ORDER BY organization_id, created_at ASC
+	`
+
+	return r.query(query)
+}
+
+func (r *SIEMSinkRepository) query(query string, args ...interface{}) ([]*domain.SIEMSink, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list siem sinks: %w", err)
+	}
+	defer rows.Close()
+
+	sinks := []*domain.SIEMSink{}
+	for rows.Next() {
+		sink, err := scanSIEMSink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan siem sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, nil
+}
+
+func (r *SIEMSinkRepository) Update(sink *domain.SIEMSink) error {
+	query := `
+		UPDATE siem_sinks
+		SET name = $2, endpoint = $3, format = $4, use_tls = $5, tls_ca_cert = $6, token = $7,
+			event_types = $8, min_severity = $9, buffer_size = $10, is_active = $11, updated_at = $12
+		WHERE id = $1
+	`
+
+	sink.UpdatedAt = time.Now().UTC()
+
+	result, err := r.db.Exec(
+		query,
+		sink.ID,
+		sink.Name,
+		sink.Endpoint,
+		sql.NullString{String: string(sink.Format), Valid: sink.Format != ""},
+		sink.UseTLS,
+		sql.NullString{String: sink.TLSCACert, Valid: sink.TLSCACert != ""},
+		sql.NullString{String: sink.Token, Valid: sink.Token != ""},
+		pq.Array(siemEventTypesToStrings(sink.EventTypes)),
+		sink.MinSeverity,
+		sink.BufferSize,
+		sink.IsActive,
+		sink.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update siem sink: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("siem sink not found")
+	}
+
+	return nil
+}
+
+func (r *SIEMSinkRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM siem_sinks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete siem sink: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("siem sink not found")
+	}
+
+	return nil
+}
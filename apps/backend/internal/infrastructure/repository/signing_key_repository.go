@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// SigningKeyRepository implements domain.SigningKeyRepository
+type SigningKeyRepository struct {
+	db *sql.DB
+}
+
+// NewSigningKeyRepository creates a new user token signing key repository
+func NewSigningKeyRepository(db *sql.DB) *SigningKeyRepository {
+	return &SigningKeyRepository{db: db}
+}
+
+// Create stores a new signing key
+func (r *SigningKeyRepository) Create(key *domain.SigningKey) error {
+	query := `
+		INSERT INTO jwt_signing_keys (id, algorithm, public_key, encrypted_private_key, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(query, key.ID, key.Algorithm, key.PublicKey, key.EncryptedPrivateKey, key.Status, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create signing key: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every key that has not expired, oldest first
+func (r *SigningKeyRepository) List() ([]*domain.SigningKey, error) {
+	query := `
+		SELECT id, algorithm, public_key, encrypted_private_key, status,
+		       created_at, activated_at, retired_at, expires_at
+		FROM jwt_signing_keys
+		WHERE expires_at IS NULL OR expires_at > NOW()
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*domain.SigningKey{}
+	for rows.Next() {
+		key := &domain.SigningKey{}
+		if err := rows.Scan(
+			&key.ID,
+			&key.Algorithm,
+			&key.PublicKey,
+			&key.EncryptedPrivateKey,
+			&key.Status,
+			&key.CreatedAt,
+			&key.ActivatedAt,
+			&key.RetiredAt,
+			&key.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Promote makes a next key active and retires the current active key until retiredExpiresAt
+func (r *SigningKeyRepository) Promote(id string, at, retiredExpiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE jwt_signing_keys SET status = 'retired', retired_at = $1, expires_at = $2
+		WHERE status = 'active'
+	`, at, retiredExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to retire signing key: %w", err)
+	}
+
+	result, err := tx.Exec(`
+		UPDATE jwt_signing_keys SET status = 'active', activated_at = $2
+		WHERE id = $1 AND status = 'next'
+	`, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to activate signing key: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("signing key not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes retired keys whose tokens have all expired
+func (r *SigningKeyRepository) DeleteExpired(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM jwt_signing_keys WHERE expires_at IS NOT NULL AND expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired signing keys: %w", err)
+	}
+
+	return result.RowsAffected()
+}
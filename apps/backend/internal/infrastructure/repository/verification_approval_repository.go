@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+type VerificationApprovalRepository struct {
+	db *sql.DB
+}
+
+func NewVerificationApprovalRepository(db *sql.DB) *VerificationApprovalRepository {
+	return &VerificationApprovalRepository{db: db}
+}
+
+func (r *VerificationApprovalRepository) Create(approval *domain.VerificationApproval) error {
+	if approval.ID == uuid.Nil {
+		approval.ID = uuid.New()
+	}
+	if approval.CreatedAt.IsZero() {
+		approval.CreatedAt = time.Now().UTC()
+	}
+	if approval.Status == "" {
+		approval.Status = domain.VerificationApprovalPending
+	}
+
+	contextJSON, err := json.Marshal(approval.Context)
+	if err != nil {
+		return fmt.Errorf("failed to marshal verification approval context: %w", err)
+	}
+	if approval.Context == nil {
+		contextJSON = []byte("{}")
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO verification_approvals (
+			id, organization_id, agent_id, verification_event_id, action_type, resource, context,
+			trust_score, reason, status, callback_url, expires_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, NULLIF($11, ''), $12, $13)
+	`,
+		approval.ID,
+		approval.OrganizationID,
+		approval.AgentID,
+		approval.VerificationEventID,
+		approval.ActionType,
+		approval.Resource,
+		contextJSON,
+		approval.TrustScore,
+		approval.Reason,
+		approval.Status,
+		approval.CallbackURL,
+		approval.ExpiresAt,
+		approval.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create verification approval: %w", err)
+	}
+
+	return nil
+}
+
+const verificationApprovalColumns = `
+	v.id, v.organization_id, v.agent_id, COALESCE(a.display_name, a.name, ''), v.verification_event_id,
+	v.action_type, COALESCE(v.resource, ''), v.context, v.trust_score, v.reason, v.status,
+	v.decided_by, COALESCE(u.email, ''), COALESCE(v.decided_via, ''), COALESCE(v.decision_reason, ''),
+	COALESCE(v.callback_url, ''), v.expires_at, v.decided_at, v.created_at
+`
+
+const verificationApprovalJoins = `
+	FROM verification_approvals v
+	LEFT JOIN agents a ON a.id = v.agent_id
+	LEFT JOIN users u ON u.id = v.decided_by
+`
+
+func scanVerificationApproval(row rowScanner) (*domain.VerificationApproval, error) {
+	approval := &domain.VerificationApproval{}
+	var contextJSON []byte
+
+	if err := row.Scan(
+		&approval.ID,
+		&approval.OrganizationID,
+		&approval.AgentID,
+		&approval.AgentName,
+		&approval.VerificationEventID,
+		&approval.ActionType,
+		&approval.Resource,
+		&contextJSON,
+		&approval.TrustScore,
+		&approval.Reason,
+		&approval.Status,
+		&approval.DecidedBy,
+		&approval.DecidedByEmail,
+		&approval.DecidedVia,
+		&approval.DecisionReason,
+		&approval.CallbackURL,
+		&approval.ExpiresAt,
+		&approval.DecidedAt,
+		&approval.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(contextJSON) > 0 {
+		if err := json.Unmarshal(contextJSON, &approval.Context); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal verification approval context: %w", err)
+		}
+	}
+
+	return approval, nil
+}
+
+func (r *VerificationApprovalRepository) GetByID(id uuid.UUID) (*domain.VerificationApproval, error) {
+	query := `SELECT ` + verificationApprovalColumns + verificationApprovalJoins + ` WHERE v.id = $1`
+
+	approval, err := scanVerificationApproval(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("verification approval not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get verification approval: %w", err)
+	}
+
+	return approval, nil
+}
+
+func (r *VerificationApprovalRepository) List(orgID uuid.UUID, status *domain.VerificationApprovalStatus, limit, offset int) ([]*domain.VerificationApproval, int, error) {
+	var statusFilter sql.NullString
+	if status != nil {
+		statusFilter = sql.NullString{String: string(*status), Valid: true}
+	}
+
+	var total int
+	if err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM verification_approvals
+		WHERE organization_id = $1 AND ($2::text IS NULL OR status = $2)
+	`, orgID, statusFilter).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count verification approvals: %w", err)
+	}
+
+	query := `SELECT ` + verificationApprovalColumns + verificationApprovalJoins + `
+		WHERE v.organization_id = $1 AND ($2::text IS NULL OR v.status = $2)
+		ORDER BY v.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(query, orgID, statusFilter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list verification approvals: %w", err)
+	}
+	defer rows.Close()
+
+	approvals := []*domain.VerificationApproval{}
+	for rows.Next() {
+		approval, err := scanVerificationApproval(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan verification approval: %w", err)
+		}
+		approvals = append(approvals, approval)
+	}
+
+	return approvals, total, rows.Err()
+}
+
+func (r *VerificationApprovalRepository) Decide(approval *domain.VerificationApproval) error {
+	// Approvers can only decide while the approval is open; timing it out is what closes it
+	open := " AND expires_at > now()"
+	if approval.Status == domain.VerificationApprovalTimeout {
+		open = ""
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE verification_approvals
+		SET status = $2, decided_by = $3, decided_via = NULLIF($4, ''), decision_reason = NULLIF($5, ''), decided_at = $6
+		WHERE id = $1 AND status = 'pending'`+open,
+		approval.ID,
+		approval.Status,
+		approval.DecidedBy,
+		approval.DecidedVia,
+		approval.DecisionReason,
+		approval.DecidedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record verification approval decision: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("verification approval already decided or expired")
+	}
+
+	return nil
+}
+
+func (r *VerificationApprovalRepository) ListExpired(now time.Time) ([]*domain.VerificationApproval, error) {
+	query := `SELECT ` + verificationApprovalColumns + verificationApprovalJoins + `
+		WHERE v.status = 'pending' AND v.expires_at <= $1
+		ORDER BY v.expires_at ASC
+	`
+
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired verification approvals: %w", err)
+	}
+	defer rows.Close()
+
+	approvals := []*domain.VerificationApproval{}
+	for rows.Next() {
+		approval, err := scanVerificationApproval(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan verification approval: %w", err)
+		}
+		approvals = append(approvals, approval)
+	}
+
+	return approvals, rows.Err()
+}
+
+func (r *VerificationApprovalRepository) AttachVerificationEvent(id, eventID uuid.UUID) error {
+	if _, err := r.db.Exec(`
+		UPDATE verification_approvals SET verification_event_id = $2 WHERE id = $1
+	`, id, eventID); err != nil {
+		return fmt.Errorf("failed to attach verification event: %w", err)
+	}
+
+	return nil
+}
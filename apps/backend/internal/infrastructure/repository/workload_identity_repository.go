@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// SPIFFEBindingRepository implements domain.SPIFFEBindingRepository
+type SPIFFEBindingRepository struct {
+	db *sql.DB
+}
+
+// NewSPIFFEBindingRepository creates a new SPIFFE binding repository
+func NewSPIFFEBindingRepository(db *sql.DB) *SPIFFEBindingRepository {
+	return &SPIFFEBindingRepository{db: db}
+}
+
+// Create stores a binding
+func (r *SPIFFEBindingRepository) Create(binding *domain.SPIFFEBinding) error {
+	_, err := r.db.Exec(`
+		INSERT INTO agent_spiffe_bindings (
+			id, agent_id, organization_id, spiffe_id_pattern, trust_domain, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		binding.ID,
+		binding.AgentID,
+		binding.OrganizationID,
+		binding.SPIFFEIDPattern,
+		binding.TrustDomain,
+		binding.CreatedBy,
+		binding.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create SPIFFE binding: %w", err)
+	}
+
+	return nil
+}
+
+// ListByAgent returns an agent's bindings
+func (r *SPIFFEBindingRepository) ListByAgent(agentID uuid.UUID) ([]*domain.SPIFFEBinding, error) {
+	return r.list(`WHERE agent_id = $1 ORDER BY created_at`, agentID)
+}
+
+// ListByTrustDomain returns every binding in a trust domain
+func (r *SPIFFEBindingRepository) ListByTrustDomain(trustDomain string) ([]*domain.SPIFFEBinding, error) {
+	return r.list(`WHERE trust_domain = $1`, trustDomain)
+}
+
+// Delete removes a binding
+func (r *SPIFFEBindingRepository) Delete(id uuid.UUID) error {
+	if _, err := r.db.Exec(`DELETE FROM agent_spiffe_bindings WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete SPIFFE binding: %w", err)
+	}
+	return nil
+}
+
+func (r *SPIFFEBindingRepository) list(where string, arg interface{}) ([]*domain.SPIFFEBinding, error) {
+	rows, err := r.db.Query(`
+		SELECT id, agent_id, organization_id, spiffe_id_pattern, trust_domain, created_by, created_at
+		FROM agent_spiffe_bindings
+	`+where, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SPIFFE bindings: %w", err)
+	}
+	defer rows.Close()
+
+	bindings := []*domain.SPIFFEBinding{}
+	for rows.Next() {
+		binding := &domain.SPIFFEBinding{}
+		err := rows.Scan(
+			&binding.ID,
+			&binding.AgentID,
+			&binding.OrganizationID,
+			&binding.SPIFFEIDPattern,
+			&binding.TrustDomain,
+			&binding.CreatedBy,
+			&binding.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan SPIFFE binding: %w", err)
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, rows.Err()
+}
+
+// AgentCertificateRepository implements domain.AgentCertificateRepository
+type AgentCertificateRepository struct {
+	db *sql.DB
+}
+
+// NewAgentCertificateRepository creates a new agent certificate repository
+func NewAgentCertificateRepository(db *sql.DB) *AgentCertificateRepository {
+	return &AgentCertificateRepository{db: db}
+}
+
+// Create stores an issued certificate
+func (r *AgentCertificateRepository) Create(certificate *domain.AgentCertificate) error {
+	_, err := r.db.Exec(`
+		INSERT INTO agent_certificates (
+			id, agent_id, organization_id, serial_number, spiffe_id, certificate_pem,
+			not_before, not_after, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		certificate.ID,
+		certificate.AgentID,
+		certificate.OrganizationID,
+		certificate.SerialNumber,
+		certificate.SPIFFEID,
+		certificate.CertificatePEM,
+		certificate.NotBefore,
+		certificate.NotAfter,
+		certificate.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create agent certificate: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestByAgent returns the agent's most recently issued certificate, or nil if it has none
+func (r *AgentCertificateRepository) GetLatestByAgent(agentID uuid.UUID) (*domain.AgentCertificate, error) {
+	certificate := &domain.AgentCertificate{}
+	err := r.db.QueryRow(`
+		SELECT id, agent_id, organization_id, serial_number, spiffe_id, certificate_pem,
+		       not_before, not_after, created_at
+		FROM agent_certificates
+		WHERE agent_id = $1
+		ORDER BY not_after DESC
+		LIMIT 1
+	`, agentID).Scan(
+		&certificate.ID,
+		&certificate.AgentID,
+		&certificate.OrganizationID,
+		&certificate.SerialNumber,
+		&certificate.SPIFFEID,
+		&certificate.CertificatePEM,
+		&certificate.NotBefore,
+		&certificate.NotAfter,
+		&certificate.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent certificate: %w", err)
+	}
+
+	return certificate, nil
+}
+
+// DeleteExpired removes certificates that expired before the given time
+func (r *AgentCertificateRepository) DeleteExpired(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM agent_certificates WHERE not_after < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired agent certificates: %w", err)
+	}
+	deleted, _ := result.RowsAffected()
+	return deleted, nil
+}
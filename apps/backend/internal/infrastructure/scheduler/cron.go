@@ -0,0 +1,245 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes the next activation time after a given time
+type Schedule interface {
+	// Next returns the next activation strictly after t (zero time if there is none)
+	Next(t time.Time) time.Time
+}
+
+// Parse parses a standard 5-field cron expression ("minute hour day-of-month month day-of-week")
+// or one of the descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly
+// and "@every <duration>".
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty cron expression")
+	}
+
+	if strings.HasPrefix(expr, "@") {
+		return parseDescriptor(expr)
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(fields))
+	}
+
+	minute, err := parseField(fields[0], minuteBounds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	hour, err := parseField(fields[1], hourBounds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	dom, err := parseField(fields[2], domBounds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day-of-month field: %w", err)
+	}
+	month, err := parseField(fields[3], monthBounds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	dow, err := parseField(fields[4], dowBounds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day-of-week field: %w", err)
+	}
+
+	// Both 0 and 7 mean Sunday
+	if dow&(1<<7) != 0 {
+		dow |= 1
+	}
+
+	return &cronSchedule{
+		minute:        minute,
+		hour:          hour,
+		dom:           dom,
+		month:         month,
+		dow:           dow,
+		domRestricted: fields[2] != "*" && fields[2] != "?",
+		dowRestricted: fields[4] != "*" && fields[4] != "?",
+	}, nil
+}
+
+func parseDescriptor(expr string) (Schedule, error) {
+	switch expr {
+	case "@yearly", "@annually":
+		return Parse("0 0 1 1 *")
+	case "@monthly":
+		return Parse("0 0 1 * *")
+	case "@weekly":
+		return Parse("0 0 * * 0")
+	case "@daily", "@midnight":
+		return Parse("0 0 * * *")
+	case "@hourly":
+		return Parse("0 * * * *")
+	}
+
+	if strings.HasPrefix(expr, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid @every interval: %w", err)
+		}
+		if interval < time.Second {
+			return nil, fmt.Errorf("@every interval must be at least 1s")
+		}
+		return everySchedule{interval: interval.Truncate(time.Second)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown cron descriptor %q", expr)
+}
+
+// everySchedule fires at a fixed interval. Activations are aligned to multiples of the
+// interval (since the zero time) so every replica computes the same slots.
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.interval).Add(s.interval)
+}
+
+// cronSchedule is a parsed 5-field cron expression; each field is a bitset of allowed values
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domRestricted, dowRestricted  bool
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	// Start at the next whole minute
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches follows cron semantics: when both day-of-month and day-of-week are restricted,
+// a day matches if either field matches
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domRestricted && s.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = bounds{min: 0, max: 59}
+	hourBounds   = bounds{min: 0, max: 23}
+	domBounds    = bounds{min: 1, max: 31}
+	monthBounds  = bounds{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = bounds{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// parseField parses a comma-separated list of values, ranges (a-b) and steps (*/n, a-b/n)
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		partBits, err := parsePart(part, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= partBits
+	}
+	return bits, nil
+}
+
+func parsePart(part string, b bounds) (uint64, error) {
+	rangePart, step := part, 1
+	if idx := strings.Index(part, "/"); idx >= 0 {
+		rangePart = part[:idx]
+		n, err := strconv.Atoi(part[idx+1:])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid step in %q", part)
+		}
+		step = n
+	}
+
+	var start, end int
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		start, end = b.min, b.max
+	case strings.Contains(rangePart, "-"):
+		ends := strings.SplitN(rangePart, "-", 2)
+		var err error
+		if start, err = parseValue(ends[0], b); err != nil {
+			return 0, err
+		}
+		if end, err = parseValue(ends[1], b); err != nil {
+			return 0, err
+		}
+	default:
+		value, err := parseValue(rangePart, b)
+		if err != nil {
+			return 0, err
+		}
+		start, end = value, value
+		// "5/15" means starting at 5, every 15 through the maximum
+		if step > 1 {
+			end = b.max
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("invalid range %q", part)
+	}
+
+	var bits uint64
+	for v := start; v <= end; v += step {
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (int, error) {
+	if n, ok := b.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if n < b.min || n > b.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d]", n, b.min, b.max)
+	}
+	return n, nil
+}
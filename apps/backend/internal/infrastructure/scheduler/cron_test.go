@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustNext(t *testing.T, expr string, from time.Time) time.Time {
+	schedule, err := Parse(expr)
+	require.NoError(t, err)
+	return schedule.Next(from)
+}
+
+func TestParse_NextActivation(t *testing.T) {
+	// Wednesday 2026-10-14 10:17:30 UTC
+	from := time.Date(2026, 10, 14, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 14, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)},
+		{"5 * * * *", time.Date(2026, 10, 14, 11, 5, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC)},
+		{"30 9-17/4 * * *", time.Date(2026, 10, 14, 13, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 8 * * mon", time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)},
+		{"@every 15m", time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)},
+		{"@every 1h", time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mustNext(t, tt.expr, from))
+		})
+	}
+}
+
+func TestParse_DayOfMonthOrDayOfWeek(t *testing.T) {
+	// When both day fields are restricted, either one matching is enough
+	from := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), mustNext(t, "0 0 15 * fri", from))
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), mustNext(t, "0 0 20 * fri", from))
+}
+
+func TestParse_InvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"@every 10ms",
+		"@fortnightly",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,219 @@
+package siem
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// syslogFacility is "log audit" (13)
+const syslogFacility = 13
+
+// FormatCEF renders an event as an ArcSight Common Event Format message
+func FormatCEF(event *domain.SIEMEvent) string {
+	var b strings.Builder
+	b.WriteString("CEF:0|")
+	b.WriteString(cefHeader(vendor) + "|")
+	b.WriteString(cefHeader(product) + "|")
+	b.WriteString(cefHeader(version) + "|")
+	b.WriteString(cefHeader(string(event.Type)+":"+event.Name) + "|")
+	b.WriteString(cefHeader(eventTitle(event)) + "|")
+	b.WriteString(strconv.Itoa(clampSeverity(event.Severity)) + "|")
+
+	ext := [][2]string{
+		{"rt", strconv.FormatInt(event.Timestamp.UnixMilli(), 10)},
+		{"externalId", event.ID.String()},
+		{"cat", string(event.Type)},
+		{"act", event.Name},
+		{"msg", event.Message},
+		{"src", event.SourceIP},
+		{"cs1Label", "organizationId"},
+		{"cs1", event.OrganizationID.String()},
+	}
+	if event.ActorID != nil {
+		ext = append(ext, [2]string{"suid", event.ActorID.String()}, [2]string{"cs2Label", "actorType"}, [2]string{"cs2", event.ActorType})
+	}
+	if event.ResourceType != "" {
+		ext = append(ext, [2]string{"cs3Label", "resourceType"}, [2]string{"cs3", event.ResourceType})
+	}
+	if event.ResourceID != "" {
+		ext = append(ext, [2]string{"cs4Label", "resourceId"}, [2]string{"cs4", event.ResourceID})
+	}
+
+	first := true
+	for _, kv := range ext {
+		if kv[1] == "" {
+			continue
+		}
+		if !first {
+			b.WriteByte(' ')
+		}
+		first = false
+		b.WriteString(kv[0] + "=" + cefExtension(kv[1]))
+	}
+
+	return b.String()
+}
+
+// cefHeader escapes backslashes and pipes in CEF header fields
+func cefHeader(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
+
+// cefExtension escapes backslashes, equals signs and line breaks in CEF extension values
+func cefExtension(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "=", `\=`, "\r\n", `\n`, "\n", `\n`, "\r", `\r`).Replace(value)
+}
+
+// FormatLEEF renders an event as an IBM QRadar LEEF 1.0 message (tab-delimited attributes)
+func FormatLEEF(event *domain.SIEMEvent) string {
+	var b strings.Builder
+	b.WriteString("LEEF:1.0|")
+	b.WriteString(leefHeader(vendor) + "|")
+	b.WriteString(leefHeader(product) + "|")
+	b.WriteString(leefHeader(version) + "|")
+	b.WriteString(leefHeader(string(event.Type)+":"+event.Name) + "|")
+
+	attrs := [][2]string{
+		{"devTime", event.Timestamp.UTC().Format("Jan 02 2006 15:04:05")},
+		{"cat", string(event.Type)},
+		{"sev", strconv.Itoa(leefSeverity(event.Severity))},
+		{"src", event.SourceIP},
+		{"identSrc", event.SourceIP},
+		{"externalId", event.ID.String()},
+		{"organizationId", event.OrganizationID.String()},
+		{"resourceType", event.ResourceType},
+		{"resourceId", event.ResourceID},
+		{"msg", event.Message},
+	}
+	if event.ActorID != nil {
+		attrs = append(attrs, [2]string{"usrName", event.ActorID.String()}, [2]string{"actorType", event.ActorType})
+	}
+
+	first := true
+	for _, kv := range attrs {
+		if kv[1] == "" {
+			continue
+		}
+		if !first {
+			b.WriteByte('\t')
+		}
+		first = false
+		b.WriteString(kv[0] + "=" + leefValue(kv[1]))
+	}
+
+	return b.String()
+}
+
+func leefHeader(value string) string {
+	return strings.NewReplacer("|", `\|`, "\t", " ", "\r", " ", "\n", " ").Replace(value)
+}
+
+// leefValue strips the attribute delimiter and line breaks from LEEF values
+func leefValue(value string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(value)
+}
+
+// leefSeverity maps the 0-10 scale onto LEEF's 1-10
+func leefSeverity(severity int) int {
+	severity = clampSeverity(severity)
+	if severity < 1 {
+		return 1
+	}
+	return severity
+}
+
+// FormatJSON renders an event as a single-line JSON object
+func FormatJSON(event *domain.SIEMEvent) (string, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return string(data), nil
+}
+
+// FormatSyslog renders an RFC 5424 syslog message whose MSG part uses the given format
+func FormatSyslog(event *domain.SIEMEvent, format domain.SIEMMessageFormat, host string) (string, error) {
+	var msg string
+	switch format {
+	case domain.SIEMFormatCEF, "":
+		msg = FormatCEF(event)
+	case domain.SIEMFormatLEEF:
+		msg = FormatLEEF(event)
+	case domain.SIEMFormatJSON:
+		var err error
+		if msg, err = FormatJSON(event); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unsupported message format: %s", format)
+	}
+
+	pri := syslogFacility*8 + syslogSeverity(event.Severity)
+	timestamp := event.Timestamp.UTC().Format(time.RFC3339Nano)
+
+	return fmt.Sprintf("<%d>1 %s %s %s - %s - %s",
+		pri,
+		timestamp,
+		syslogHeaderField(host, 255),
+		appName,
+		syslogHeaderField(string(event.Type), 32),
+		msg,
+	), nil
+}
+
+// syslogSeverity maps the 0-10 scale onto syslog severities (2 critical ... 6 informational)
+func syslogSeverity(severity int) int {
+	switch severity = clampSeverity(severity); {
+	case severity >= 9:
+		return 2
+	case severity >= 7:
+		return 3
+	case severity >= 5:
+		return 4
+	case severity >= 3:
+		return 5
+	default:
+		return 6
+	}
+}
+
+// syslogHeaderField keeps header fields to printable US-ASCII without spaces (RFC 5424 6.2)
+func syslogHeaderField(value string, maxLen int) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+		if b.Len() >= maxLen {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+func clampSeverity(severity int) int {
+	if severity < 0 {
+		return 0
+	}
+	if severity > 10 {
+		return 10
+	}
+	return severity
+}
+
+func eventTitle(event *domain.SIEMEvent) string {
+	if title, ok := event.Data["title"].(string); ok && title != "" {
+		return title
+	}
+	return event.Name
+}
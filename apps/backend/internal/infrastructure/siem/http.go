@@ -0,0 +1,147 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/netguard"
+)
+
+const httpTimeout = 30 * time.Second
+
+// httpTransport POSTs a batch of events as a single request body
+type httpTransport struct {
+	endpoint    string
+	contentType string
+	authHeader  string
+	client      *http.Client
+	encode      func(buf *bytes.Buffer, event *domain.SIEMEvent) error
+}
+
+func newHTTPClient(sink *domain.SIEMSink, endpoint *url.URL, outbound *netguard.Policy) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = outbound.Dialer(dialTimeout).DialContext
+	if endpoint.Scheme == "https" && sink.TLSCACert != "" {
+		config, err := tlsConfig(sink, endpoint.Hostname())
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = config
+	}
+	return &http.Client{Timeout: httpTimeout, Transport: transport}, nil
+}
+
+func parseHTTPEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("endpoint must be an http(s) URL")
+	}
+	return u, nil
+}
+
+// newHECTransport sends events to a Splunk HTTP Event Collector. Several event objects are
+// concatenated in one request, which HEC accepts as a batch.
+func newHECTransport(sink *domain.SIEMSink, outbound *netguard.Policy) (*httpTransport, error) {
+	u, err := parseHTTPEndpoint(sink.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/services/collector/event"
+	}
+	if sink.Token == "" {
+		return nil, fmt.Errorf("splunk HEC token is required")
+	}
+
+	client, err := newHTTPClient(sink, u, outbound)
+	if err != nil {
+		return nil, err
+	}
+
+	host := hostname()
+	return &httpTransport{
+		endpoint:    u.String(),
+		contentType: "application/json",
+		authHeader:  "Splunk " + sink.Token,
+		client:      client,
+		encode: func(buf *bytes.Buffer, event *domain.SIEMEvent) error {
+			return json.NewEncoder(buf).Encode(map[string]interface{}{
+				"time":       float64(event.Timestamp.UnixMilli()) / 1000,
+				"host":       host,
+				"source":     appName,
+				"sourcetype": "aim:" + string(event.Type),
+				"event":      event,
+			})
+		},
+	}, nil
+}
+
+// newNDJSONTransport POSTs newline-delimited JSON events to a generic HTTP collector
+func newNDJSONTransport(sink *domain.SIEMSink, outbound *netguard.Policy) (*httpTransport, error) {
+	u, err := parseHTTPEndpoint(sink.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newHTTPClient(sink, u, outbound)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &httpTransport{
+		endpoint:    u.String(),
+		contentType: "application/x-ndjson",
+		client:      client,
+		encode: func(buf *bytes.Buffer, event *domain.SIEMEvent) error {
+			return json.NewEncoder(buf).Encode(event)
+		},
+	}
+	if sink.Token != "" {
+		t.authHeader = "Bearer " + sink.Token
+	}
+	return t, nil
+}
+
+func (t *httpTransport) Send(ctx context.Context, events []*domain.SIEMEvent) error {
+	var buf bytes.Buffer
+	for _, event := range events {
+		if err := t.encode(&buf, event); err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", t.contentType)
+	req.Header.Set("User-Agent", "AIM-SIEM-Forwarder/1.0")
+	if t.authHeader != "" {
+		req.Header.Set("Authorization", t.authHeader)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The response body is not included: errors are shown to sink administrators, and the
+	// collector's reply is not theirs to read
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (t *httpTransport) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,317 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/netguard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOutbound allows the loopback collectors the tests start
+var testOutbound, _ = netguard.NewPolicy([]string{"127.0.0.0/8"})
+
+func testEvent(name string) *domain.SIEMEvent {
+	actorID := uuid.New()
+	return &domain.SIEMEvent{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Type:           domain.SIEMEventAlert,
+		Name:           name,
+		Severity:       8,
+		Message:        "agent a=b | c\nnext line",
+		Timestamp:      time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC),
+		ActorID:        &actorID,
+		ActorType:      "agent",
+		ResourceType:   "agent",
+		ResourceID:     "res-1",
+		SourceIP:       "10.0.0.1",
+		Data:           map[string]interface{}{"title": "Pipe | title"},
+	}
+}
+
+func TestFormatCEF(t *testing.T) {
+	event := testEvent("capability_violation")
+
+	msg := FormatCEF(event)
+
+	assert.True(t, strings.HasPrefix(msg, `CEF:0|OpenA2A|AIM|1.0|alert:capability_violation|Pipe \| title|8|`), msg)
+	assert.Contains(t, msg, `msg=agent a\=b | c\nnext line`)
+	assert.Contains(t, msg, "rt=1792326600000")
+	assert.Contains(t, msg, "src=10.0.0.1")
+	assert.Contains(t, msg, "suid="+event.ActorID.String())
+	assert.NotContains(t, msg, "\n")
+}
+
+func TestFormatLEEF(t *testing.T) {
+	event := testEvent("capability_violation")
+
+	msg := FormatLEEF(event)
+
+	assert.True(t, strings.HasPrefix(msg, "LEEF:1.0|OpenA2A|AIM|1.0|alert:capability_violation|devTime=Oct 18 2026 12:30:00\t"), msg)
+	assert.Contains(t, msg, "\tsev=8\t")
+	assert.Contains(t, msg, "\tmsg=agent a=b | c next line")
+	assert.NotContains(t, msg, "\n")
+}
+
+func TestFormatSyslog(t *testing.T) {
+	event := testEvent("capability_violation")
+
+	msg, err := FormatSyslog(event, domain.SIEMFormatCEF, "aim host")
+	require.NoError(t, err)
+
+	// facility 13 (log audit) * 8 + severity 3 (error)
+	assert.True(t, strings.HasPrefix(msg, "<107>1 2026-10-18T12:30:00Z aimhost aim - alert - CEF:0|"), msg)
+
+	_, err = FormatSyslog(event, "xml", "host")
+	assert.Error(t, err)
+}
+
+// readOctetFrames reads RFC 6587 octet-counted frames from a connection
+func readOctetFrames(t *testing.T, conn net.Conn, count int) []string {
+	t.Helper()
+	reader := bufio.NewReader(conn)
+	frames := make([]string, 0, count)
+	for len(frames) < count {
+		length, err := reader.ReadString(' ')
+		require.NoError(t, err)
+		n, err := strconv.Atoi(strings.TrimSpace(length))
+		require.NoError(t, err)
+		frame := make([]byte, n)
+		_, err = io.ReadFull(reader, frame)
+		require.NoError(t, err)
+		frames = append(frames, string(frame))
+	}
+	return frames
+}
+
+func acceptFrames(t *testing.T, listener net.Listener, count int) <-chan []string {
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer conn.Close()
+		received <- readOctetFrames(t, conn, count)
+	}()
+	return received
+}
+
+func TestSyslogTransportTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	received := acceptFrames(t, listener, 2)
+
+	transport, err := NewTransport(&domain.SIEMSink{
+		Type:     domain.SIEMSinkSyslog,
+		Endpoint: listener.Addr().String(),
+		Format:   domain.SIEMFormatLEEF,
+	}, testOutbound)
+	require.NoError(t, err)
+	defer transport.Close()
+
+	err = transport.Send(context.Background(), []*domain.SIEMEvent{testEvent("first"), testEvent("second")})
+	require.NoError(t, err)
+
+	select {
+	case frames := <-received:
+		require.Len(t, frames, 2)
+		assert.Contains(t, frames[0], "LEEF:1.0|OpenA2A|AIM|1.0|alert:first|")
+		assert.Contains(t, frames[1], "LEEF:1.0|OpenA2A|AIM|1.0|alert:second|")
+	case <-time.After(5 * time.Second):
+		t.Fatal("syslog server received nothing")
+	}
+}
+
+func TestSyslogTransportTLS(t *testing.T) {
+	cert, caPEM := selfSignedCert(t)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	defer listener.Close()
+	received := acceptFrames(t, listener, 1)
+
+	sink := &domain.SIEMSink{
+		Type:      domain.SIEMSinkSyslog,
+		Endpoint:  listener.Addr().String(),
+		Format:    domain.SIEMFormatCEF,
+		UseTLS:    true,
+		TLSCACert: caPEM,
+	}
+	transport, err := NewTransport(sink, testOutbound)
+	require.NoError(t, err)
+	defer transport.Close()
+
+	require.NoError(t, transport.Send(context.Background(), []*domain.SIEMEvent{testEvent("tls")}))
+
+	select {
+	case frames := <-received:
+		require.Len(t, frames, 1)
+		assert.Contains(t, frames[0], "CEF:0|OpenA2A|AIM|1.0|alert:tls|")
+	case <-time.After(5 * time.Second):
+		t.Fatal("syslog server received nothing")
+	}
+
+	// Without the CA the server certificate is rejected
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	sink.TLSCACert = ""
+	untrusted, err := NewTransport(sink, testOutbound)
+	require.NoError(t, err)
+	assert.Error(t, untrusted.Send(context.Background(), []*domain.SIEMEvent{testEvent("tls")}))
+}
+
+func TestSplunkHECTransport(t *testing.T) {
+	var auth, path string
+	var events []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		path = r.URL.Path
+		decoder := json.NewDecoder(r.Body)
+		for decoder.More() {
+			var event map[string]interface{}
+			if err := decoder.Decode(&event); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			events = append(events, event)
+		}
+		w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer server.Close()
+
+	transport, err := NewTransport(&domain.SIEMSink{
+		Type:     domain.SIEMSinkSplunkHEC,
+		Endpoint: server.URL,
+		Token:    "hec-token",
+	}, testOutbound)
+	require.NoError(t, err)
+	defer transport.Close()
+
+	require.NoError(t, transport.Send(context.Background(), []*domain.SIEMEvent{testEvent("one"), testEvent("two")}))
+
+	assert.Equal(t, "Splunk hec-token", auth)
+	assert.Equal(t, "/services/collector/event", path)
+	require.Len(t, events, 2)
+	assert.Equal(t, "aim:alert", events[0]["sourcetype"])
+	assert.Equal(t, float64(1792326600), events[0]["time"])
+	assert.Equal(t, "two", events[1]["event"].(map[string]interface{})["name"])
+
+	_, err = NewTransport(&domain.SIEMSink{Type: domain.SIEMSinkSplunkHEC, Endpoint: server.URL}, testOutbound)
+	assert.Error(t, err)
+}
+
+func TestNDJSONTransport(t *testing.T) {
+	var contentType, auth string
+	var lines []string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		lines = strings.Split(strings.TrimSpace(string(body)), "\n")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	transport, err := NewTransport(&domain.SIEMSink{
+		Type:     domain.SIEMSinkHTTPNDJSON,
+		Endpoint: server.URL + "/ingest",
+		Token:    "secret",
+	}, testOutbound)
+	require.NoError(t, err)
+
+	require.NoError(t, transport.Send(context.Background(), []*domain.SIEMEvent{testEvent("one"), testEvent("two")}))
+	assert.Equal(t, "application/x-ndjson", contentType)
+	assert.Equal(t, "Bearer secret", auth)
+	require.Len(t, lines, 2)
+
+	var decoded domain.SIEMEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &decoded))
+	assert.Equal(t, "two", decoded.Name)
+
+	status = http.StatusServiceUnavailable
+	assert.Error(t, transport.Send(context.Background(), []*domain.SIEMEvent{testEvent("three")}))
+}
+
+func TestTransportsRefuseBlockedAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("internal details"))
+	}))
+	defer server.Close()
+
+	blocking, err := netguard.NewPolicy(nil)
+	require.NoError(t, err)
+
+	sink := &domain.SIEMSink{Type: domain.SIEMSinkHTTPNDJSON, Endpoint: server.URL}
+	assert.Error(t, CheckEndpoint(context.Background(), sink, blocking))
+	assert.NoError(t, CheckEndpoint(context.Background(), sink, testOutbound))
+
+	// The dial itself is refused, even when validation was skipped
+	transport, err := NewTransport(sink, blocking)
+	require.NoError(t, err)
+	assert.Error(t, transport.Send(context.Background(), []*domain.SIEMEvent{testEvent("blocked")}))
+
+	syslogSink := &domain.SIEMSink{Type: domain.SIEMSinkSyslog, Endpoint: "169.254.169.254:514"}
+	assert.Error(t, CheckEndpoint(context.Background(), syslogSink, blocking))
+	syslog, err := NewTransport(syslogSink, blocking)
+	require.NoError(t, err)
+	assert.Error(t, syslog.Send(context.Background(), []*domain.SIEMEvent{testEvent("blocked")}))
+
+	// Collector responses are not echoed back in delivery errors
+	allowed, err := NewTransport(sink, testOutbound)
+	require.NoError(t, err)
+	err = allowed.Send(context.Background(), []*domain.SIEMEvent{testEvent("rejected")})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "internal details")
+}
+
+func selfSignedCert(t *testing.T) (tls.Certificate, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "siem.test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key},
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
@@ -0,0 +1,114 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/netguard"
+)
+
+const (
+	dialTimeout  = 10 * time.Second
+	writeTimeout = 30 * time.Second
+)
+
+// syslogTransport streams RFC 5424 messages over TCP or TLS using octet-counting framing
+// (RFC 6587 3.4.1). The connection is kept open and re-established after a failure.
+type syslogTransport struct {
+	address   string
+	format    domain.SIEMMessageFormat
+	tlsConfig *tls.Config
+	host      string
+	outbound  *netguard.Policy
+	conn      net.Conn
+}
+
+func newSyslogTransport(sink *domain.SIEMSink, outbound *netguard.Policy) (*syslogTransport, error) {
+	host, _, err := net.SplitHostPort(sink.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("syslog endpoint must be host:port: %w", err)
+	}
+
+	t := &syslogTransport{
+		address:  sink.Endpoint,
+		format:   sink.Format,
+		host:     hostname(),
+		outbound: outbound,
+	}
+
+	if sink.UseTLS {
+		if t.tlsConfig, err = tlsConfig(sink, host); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func (t *syslogTransport) Send(ctx context.Context, events []*domain.SIEMEvent) error {
+	var buf bytes.Buffer
+	for _, event := range events {
+		msg, err := FormatSyslog(event, t.format, t.host)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(strconv.Itoa(len(msg)))
+		buf.WriteByte(' ')
+		buf.WriteString(msg)
+	}
+
+	if t.conn == nil {
+		if err := t.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	deadline := time.Now().Add(writeTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	t.conn.SetWriteDeadline(deadline)
+
+	if _, err := t.conn.Write(buf.Bytes()); err != nil {
+		t.Close()
+		return fmt.Errorf("failed to write to syslog server: %w", err)
+	}
+
+	return nil
+}
+
+func (t *syslogTransport) connect(ctx context.Context) error {
+	dialer := t.outbound.Dialer(dialTimeout)
+
+	if t.tlsConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: t.tlsConfig}
+		conn, err := tlsDialer.DialContext(ctx, "tcp", t.address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog server: %w", err)
+		}
+		t.conn = conn
+		return nil
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", t.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog server: %w", err)
+	}
+	t.conn = conn
+	return nil
+}
+
+func (t *syslogTransport) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
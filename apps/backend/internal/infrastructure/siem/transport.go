@@ -0,0 +1,92 @@
+package siem
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/netguard"
+)
+
+const (
+	vendor  = "OpenA2A"
+	product = "AIM"
+	version = "1.0"
+	appName = "aim"
+)
+
+// Transport delivers batches of events to a SIEM
+type Transport interface {
+	// Send delivers the events in order; on error the whole batch may be retried
+	Send(ctx context.Context, events []*domain.SIEMEvent) error
+	Close() error
+}
+
+// NewTransport creates the transport for a sink. Every connection it opens is checked against
+// the outbound policy, so a sink cannot reach internal addresses the operator has not allowed.
+func NewTransport(sink *domain.SIEMSink, outbound *netguard.Policy) (Transport, error) {
+	switch sink.Type {
+	case domain.SIEMSinkSyslog:
+		return newSyslogTransport(sink, outbound)
+	case domain.SIEMSinkSplunkHEC:
+		return newHECTransport(sink, outbound)
+	case domain.SIEMSinkHTTPNDJSON:
+		return newNDJSONTransport(sink, outbound)
+	}
+	return nil, fmt.Errorf("unsupported sink type: %s", sink.Type)
+}
+
+// CheckEndpoint resolves the sink's endpoint host and rejects it if it points at an address
+// the outbound policy blocks
+func CheckEndpoint(ctx context.Context, sink *domain.SIEMSink, outbound *netguard.Policy) error {
+	var host string
+	if sink.Type == domain.SIEMSinkSyslog {
+		h, _, err := net.SplitHostPort(sink.Endpoint)
+		if err != nil {
+			return fmt.Errorf("syslog endpoint must be host:port: %w", err)
+		}
+		host = h
+	} else {
+		u, err := url.Parse(sink.Endpoint)
+		if err != nil {
+			return fmt.Errorf("endpoint must be an http(s) URL")
+		}
+		host = u.Hostname()
+	}
+	return outbound.CheckHost(ctx, host)
+}
+
+// tlsConfig builds the client TLS config, trusting the sink's CA bundle in addition to the
+// system roots when one is configured
+func tlsConfig(sink *domain.SIEMSink, serverName string) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+
+	if sink.TLSCACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(sink.TLSCACert)) {
+			return nil, fmt.Errorf("invalid TLS CA certificate")
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "-"
+	}
+	return name
+}
@@ -0,0 +1,173 @@
+package spiffe
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// Bundle holds the authorities of a trust domain: the CA certificates X.509-SVIDs chain to and
+// the keys JWT-SVIDs are signed with
+type Bundle struct {
+	TrustDomain     string
+	X509Authorities []*x509.Certificate
+	JWTAuthorities  map[string]crypto.PublicKey // By key ID
+}
+
+// bundleKey is a JWK in a SPIFFE bundle document
+type bundleKey struct {
+	Use   string   `json:"use"`
+	KeyID string   `json:"kid"`
+	Type  string   `json:"kty"`
+	Curve string   `json:"crv"`
+	X     string   `json:"x"`
+	Y     string   `json:"y"`
+	N     string   `json:"n"`
+	E     string   `json:"e"`
+	X5C   []string `json:"x5c"`
+}
+
+// LoadBundles loads trust bundles from "trust-domain=path" entries
+func LoadBundles(entries []string) (map[string]*Bundle, error) {
+	bundles := map[string]*Bundle{}
+	for _, entry := range entries {
+		trustDomain, file, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || trustDomain == "" || file == "" {
+			return nil, fmt.Errorf("trust bundle %q must be trust-domain=path", entry)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trust bundle of %s: %w", trustDomain, err)
+		}
+		bundle, err := ParseBundle(trustDomain, data)
+		if err != nil {
+			return nil, err
+		}
+		bundles[trustDomain] = bundle
+	}
+	return bundles, nil
+}
+
+// ParseBundle parses a trust bundle: either PEM CA certificates (X.509 authorities only) or a
+// SPIFFE bundle document, the JWKS with "x509-svid" and "jwt-svid" keys SPIRE publishes
+func ParseBundle(trustDomain string, data []byte) (*Bundle, error) {
+	if err := validateTrustDomain(trustDomain); err != nil {
+		return nil, err
+	}
+	bundle := &Bundle{TrustDomain: trustDomain, JWTAuthorities: map[string]crypto.PublicKey{}}
+
+	data = bytes.TrimSpace(data)
+	if !bytes.HasPrefix(data, []byte("{")) {
+		for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid certificate in trust bundle of %s: %w", trustDomain, err)
+			}
+			bundle.X509Authorities = append(bundle.X509Authorities, certificate)
+		}
+		if len(bundle.X509Authorities) == 0 {
+			return nil, fmt.Errorf("trust bundle of %s has no certificates", trustDomain)
+		}
+		return bundle, nil
+	}
+
+	var document struct {
+		Keys []bundleKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid trust bundle of %s: %w", trustDomain, err)
+	}
+	for _, key := range document.Keys {
+		switch key.Use {
+		case "x509-svid":
+			if len(key.X5C) != 1 {
+				return nil, fmt.Errorf("x509-svid key in trust bundle of %s must have one certificate", trustDomain)
+			}
+			der, err := base64.StdEncoding.DecodeString(key.X5C[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid x5c in trust bundle of %s: %w", trustDomain, err)
+			}
+			certificate, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, fmt.Errorf("invalid certificate in trust bundle of %s: %w", trustDomain, err)
+			}
+			bundle.X509Authorities = append(bundle.X509Authorities, certificate)
+		case "jwt-svid":
+			if key.KeyID == "" {
+				return nil, fmt.Errorf("jwt-svid key in trust bundle of %s has no kid", trustDomain)
+			}
+			publicKey, err := key.publicKey()
+			if err != nil {
+				return nil, fmt.Errorf("invalid jwt-svid key %s in trust bundle of %s: %w", key.KeyID, trustDomain, err)
+			}
+			bundle.JWTAuthorities[key.KeyID] = publicKey
+		}
+	}
+	return bundle, nil
+}
+
+// publicKey decodes an EC, RSA or Ed25519 JWK
+func (k bundleKey) publicKey() (crypto.PublicKey, error) {
+	decode := func(value string) ([]byte, error) {
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	}
+
+	switch k.Type {
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if _, err := key.ECDH(); err != nil {
+			return nil, fmt.Errorf("point is not on the curve")
+		}
+		return key, nil
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "OKP":
+		x, err := decode(k.X)
+		if err != nil || k.Curve != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("unsupported OKP key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Type)
+}
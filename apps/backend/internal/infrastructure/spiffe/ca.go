@@ -0,0 +1,175 @@
+package spiffe
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+)
+
+// CA is a small issuing CA that mints short-lived X.509-SVIDs in its trust domain
+type CA struct {
+	TrustDomain string
+	certificate *x509.Certificate
+	key         crypto.Signer
+}
+
+// NewCA loads an issuing CA from a PEM certificate and a PEM private key (PKCS#8, EC or PKCS#1)
+func NewCA(trustDomain string, certificatePEM, keyPEM []byte) (*CA, error) {
+	if err := validateTrustDomain(trustDomain); err != nil {
+		return nil, err
+	}
+	chain, err := ParseCertificatesPEM(certificatePEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate: %w", err)
+	}
+	certificate := chain[0]
+	if !certificate.IsCA || certificate.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, fmt.Errorf("CA certificate is not allowed to sign certificates")
+	}
+
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("invalid CA private key: no PEM block")
+	}
+	var parsed interface{}
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA private key: %w", err)
+	}
+	key, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported CA private key type %T", parsed)
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil || !bytes.Equal(publicKey, certificate.RawSubjectPublicKeyInfo) {
+		return nil, fmt.Errorf("CA private key does not match the CA certificate")
+	}
+
+	return &CA{TrustDomain: trustDomain, certificate: certificate, key: key}, nil
+}
+
+// NewEphemeralCA creates a self-signed CA whose key lives only in memory (development only)
+func NewEphemeralCA(trustDomain string, validity time.Duration) (*CA, error) {
+	if err := validateTrustDomain(trustDomain); err != nil {
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "AIM agent CA " + trustDomain},
+		URIs:                  []*url.URL{{Scheme: "spiffe", Host: trustDomain}},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	return &CA{TrustDomain: trustDomain, certificate: certificate, key: key}, nil
+}
+
+// Bundle returns the trust bundle of the CA's trust domain, so certificates it issues are
+// accepted as X.509-SVIDs
+func (ca *CA) Bundle() *Bundle {
+	return &Bundle{TrustDomain: ca.TrustDomain, X509Authorities: []*x509.Certificate{ca.certificate}}
+}
+
+// CertificatePEM returns the CA certificate
+func (ca *CA) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.certificate.Raw})
+}
+
+// ParseCSR parses and checks the signature of a PEM certificate signing request
+func ParseCSR(csrPEM []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("CSR must be a PEM CERTIFICATE REQUEST")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSR: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("CSR signature is invalid: %w", err)
+	}
+	return csr, nil
+}
+
+// Sign issues an X.509-SVID for the CSR's key. Only the key is taken from the CSR; the SPIFFE ID
+// is the one given, and the certificate never outlives the CA.
+func (ca *CA) Sign(csr *x509.CertificateRequest, id ID, notBefore time.Time, ttl time.Duration) (*x509.Certificate, error) {
+	if id.TrustDomain != ca.TrustDomain {
+		return nil, fmt.Errorf("CA only issues certificates in trust domain %s", ca.TrustDomain)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	notAfter := notBefore.Add(ttl)
+	if notAfter.After(ca.certificate.NotAfter) {
+		notAfter = ca.certificate.NotAfter
+	}
+
+	uri, err := url.Parse(id.String())
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		URIs:                  []*url.URL{uri},
+		NotBefore:             notBefore.Add(-time.Minute), // Allow for clock skew
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue certificate: %w", err)
+	}
+	return x509.ParseCertificate(der)
+}
+
+// randomSerial returns a random 128-bit certificate serial number
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial, nil
+}
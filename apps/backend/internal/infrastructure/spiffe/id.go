@@ -0,0 +1,133 @@
+package spiffe
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ID is a SPIFFE ID: spiffe://<trust domain>/<path>
+type ID struct {
+	TrustDomain string
+	Path        string // Empty or starting with "/"
+}
+
+// String returns the ID as a URI
+func (id ID) String() string {
+	return "spiffe://" + id.TrustDomain + id.Path
+}
+
+// ParseID parses and validates a SPIFFE ID
+func ParseID(raw string) (ID, error) {
+	if !strings.HasPrefix(raw, "spiffe://") {
+		return ID{}, fmt.Errorf("SPIFFE ID must use the spiffe scheme")
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return ID{}, fmt.Errorf("invalid SPIFFE ID: %w", err)
+	}
+	if parsed.User != nil || parsed.Port() != "" || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return ID{}, fmt.Errorf("SPIFFE ID must not have user info, a port, a query or a fragment")
+	}
+	if err := validateTrustDomain(parsed.Host); err != nil {
+		return ID{}, err
+	}
+	if parsed.Path != "" {
+		for _, segment := range strings.Split(parsed.Path[1:], "/") {
+			if segment == "" || segment == "." || segment == ".." {
+				return ID{}, fmt.Errorf("SPIFFE ID path must not have empty, '.' or '..' segments")
+			}
+		}
+	}
+	return ID{TrustDomain: parsed.Host, Path: parsed.Path}, nil
+}
+
+func validateTrustDomain(trustDomain string) error {
+	if trustDomain == "" {
+		return fmt.Errorf("SPIFFE ID must have a trust domain")
+	}
+	for _, r := range trustDomain {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '.' && r != '-' && r != '_' {
+			return fmt.Errorf("trust domain %q must be lowercase letters, digits, '.', '-' or '_'", trustDomain)
+		}
+	}
+	return nil
+}
+
+// ValidatePattern checks a SPIFFE ID pattern: a SPIFFE ID whose path segments may contain "*"
+// wildcards, each matching within a single segment. The trust domain cannot be a wildcard.
+func ValidatePattern(pattern string) (trustDomain string, err error) {
+	// Validate the pattern as an ID with the wildcards replaced by a legal segment character
+	id, err := ParseID(strings.ReplaceAll(pattern, "*", "x"))
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(pattern[len("spiffe://"):len("spiffe://")+len(id.TrustDomain)], "*") {
+		return "", fmt.Errorf("trust domain of a SPIFFE ID pattern cannot be a wildcard")
+	}
+	if strings.ContainsAny(pattern, `[]\`) {
+		return "", fmt.Errorf("SPIFFE ID patterns only support the \"*\" wildcard")
+	}
+	return id.TrustDomain, nil
+}
+
+// PatternsOverlap reports whether some SPIFFE ID matches both patterns
+func PatternsOverlap(a, b string) bool {
+	aSegments, bSegments := strings.Split(a, "/"), strings.Split(b, "/")
+	if len(aSegments) != len(bSegments) {
+		return false
+	}
+	for i := range aSegments {
+		if !segmentsOverlap(aSegments[i], bSegments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentsOverlap reports whether some string matches both single-segment globs, by walking
+// both patterns at once
+func segmentsOverlap(a, b string) bool {
+	seen := map[[2]int]bool{}
+	var overlap func(i, j int) bool
+	overlap = func(i, j int) bool {
+		if i == len(a) && j == len(b) {
+			return true
+		}
+		if seen[[2]int{i, j}] {
+			return false
+		}
+		seen[[2]int{i, j}] = true
+
+		// A wildcard may match nothing
+		if i < len(a) && a[i] == '*' && overlap(i+1, j) {
+			return true
+		}
+		if j < len(b) && b[j] == '*' && overlap(i, j+1) {
+			return true
+		}
+		if i == len(a) || j == len(b) {
+			return false
+		}
+		// ...or both consume the same character
+		switch {
+		case a[i] == '*' && b[j] == '*':
+			return false
+		case a[i] == '*':
+			return overlap(i, j+1)
+		case b[j] == '*':
+			return overlap(i+1, j)
+		case a[i] == b[j]:
+			return overlap(i+1, j+1)
+		}
+		return false
+	}
+	return overlap(0, 0)
+}
+
+// MatchPattern reports whether a SPIFFE ID matches a pattern
+func MatchPattern(pattern string, id ID) bool {
+	matched, err := path.Match(pattern, id.String())
+	return err == nil && matched
+}
@@ -0,0 +1,148 @@
+package spiffe
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatterns(t *testing.T) {
+	id, err := ParseID("spiffe://prod.example.org/ns/payments/sa/invoice-bot")
+	require.NoError(t, err)
+	assert.Equal(t, "prod.example.org", id.TrustDomain)
+
+	trustDomain, err := ValidatePattern("spiffe://prod.example.org/ns/payments/sa/*")
+	require.NoError(t, err)
+	assert.Equal(t, "prod.example.org", trustDomain)
+	assert.True(t, MatchPattern("spiffe://prod.example.org/ns/payments/sa/*", id))
+	assert.False(t, MatchPattern("spiffe://prod.example.org/ns/*/sa/other", id))
+	// A wildcard never crosses a path segment
+	assert.False(t, MatchPattern("spiffe://prod.example.org/ns/*", id))
+
+	for _, invalid := range []string{
+		"https://prod.example.org/ns/payments",
+		"spiffe://*.example.org/ns/payments",
+		"spiffe://Prod.example.org/ns/payments",
+		"spiffe://prod.example.org/ns//payments",
+		"spiffe://prod.example.org:443/ns/payments",
+		"spiffe://prod.example.org/ns/[a-z]*",
+	} {
+		_, err := ValidatePattern(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPatternsOverlap(t *testing.T) {
+	for _, tc := range []struct {
+		a, b    string
+		overlap bool
+	}{
+		{"spiffe://td/ns/payments/sa/*", "spiffe://td/ns/payments/sa/worker", true},
+		{"spiffe://td/ns/*/sa/worker", "spiffe://td/ns/payments/sa/*", true},
+		{"spiffe://td/*", "spiffe://td/ns/payments", false},
+		{"spiffe://td/ns/pay*", "spiffe://td/ns/*ments", true},
+		{"spiffe://td/ns/pay*", "spiffe://td/ns/bill*", false},
+		{"spiffe://td/ns/a*b", "spiffe://td/ns/*c", false},
+		{"spiffe://td/ns/payments", "spiffe://other/ns/payments", false},
+	} {
+		assert.Equal(t, tc.overlap, PatternsOverlap(tc.a, tc.b), "%s / %s", tc.a, tc.b)
+		assert.Equal(t, tc.overlap, PatternsOverlap(tc.b, tc.a), "%s / %s", tc.b, tc.a)
+	}
+}
+
+func newCSR(t *testing.T) *x509.CertificateRequest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+	require.NoError(t, err)
+	csr, err := ParseCSR(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+	require.NoError(t, err)
+	return csr
+}
+
+func TestCA_IssuesX509SVIDs(t *testing.T) {
+	ca, err := NewEphemeralCA("aim.example.com", 24*time.Hour)
+	require.NoError(t, err)
+	other, err := NewEphemeralCA("other.example.com", 24*time.Hour)
+	require.NoError(t, err)
+
+	id := ID{TrustDomain: "aim.example.com", Path: "/agents/invoice-bot"}
+	now := time.Now()
+	certificate, err := ca.Sign(newCSR(t), id, now, time.Hour)
+	require.NoError(t, err)
+
+	bundles := map[string]*Bundle{"aim.example.com": ca.Bundle()}
+	verified, err := VerifyX509SVID([]*x509.Certificate{certificate}, bundles, now)
+	require.NoError(t, err)
+	assert.Equal(t, id, verified)
+
+	// Expired certificates and certificates from another CA are rejected
+	_, err = VerifyX509SVID([]*x509.Certificate{certificate}, bundles, now.Add(2*time.Hour))
+	assert.Error(t, err)
+	_, err = VerifyX509SVID([]*x509.Certificate{certificate}, map[string]*Bundle{"aim.example.com": other.Bundle()}, now)
+	assert.Error(t, err)
+
+	// The CA does not issue outside its trust domain
+	_, err = ca.Sign(newCSR(t), ID{TrustDomain: "other.example.com", Path: "/x"}, now, time.Hour)
+	assert.Error(t, err)
+
+	// A loaded CA must hold the key of its certificate
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(otherKey)
+	require.NoError(t, err)
+	_, err = NewCA("aim.example.com", ca.CertificatePEM(), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	assert.Error(t, err)
+}
+
+func TestVerifyJWTSVID(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecdhKey, err := key.PublicKey.ECDH()
+	require.NoError(t, err)
+	point := ecdhKey.Bytes()
+	document := fmt.Sprintf(`{"keys":[{"use":"jwt-svid","kid":"k1","kty":"EC","crv":"P-256","x":"%s","y":"%s"}]}`,
+		base64.RawURLEncoding.EncodeToString(point[1:33]),
+		base64.RawURLEncoding.EncodeToString(point[33:]),
+	)
+	bundle, err := ParseBundle("prod.example.org", []byte(document))
+	require.NoError(t, err)
+	bundles := map[string]*Bundle{"prod.example.org": bundle}
+
+	now := time.Now()
+	sign := func(subject, audience string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		})
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	token := sign("spiffe://prod.example.org/ns/payments/sa/invoice-bot", "https://aim.example.com")
+	assert.True(t, IsJWTSVID(token))
+	id, err := VerifyJWTSVID(token, bundles, "https://aim.example.com", now)
+	require.NoError(t, err)
+	assert.Equal(t, "/ns/payments/sa/invoice-bot", id.Path)
+
+	// Tokens for another audience or from an untrusted trust domain are rejected
+	_, err = VerifyJWTSVID(token, bundles, "https://other.example.com", now)
+	assert.Error(t, err)
+	_, err = VerifyJWTSVID(sign("spiffe://dev.example.org/ns/payments", "https://aim.example.com"), bundles, "https://aim.example.com", now)
+	assert.Error(t, err)
+	_, err = VerifyJWTSVID(token, bundles, "https://aim.example.com", now.Add(10*time.Minute))
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package spiffe
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtSVIDAlgorithms are the signature algorithms the JWT-SVID specification allows
+var jwtSVIDAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256", "PS384", "PS512"}
+
+// CertificateID returns the SPIFFE ID of an X.509-SVID: its single spiffe URI SAN
+func CertificateID(certificate *x509.Certificate) (ID, error) {
+	if len(certificate.URIs) != 1 {
+		return ID{}, fmt.Errorf("X.509-SVID must have exactly one URI SAN")
+	}
+	return ParseID(certificate.URIs[0].String())
+}
+
+// VerifyX509SVID verifies an X.509-SVID chain (leaf first) against the bundle of the leaf's trust
+// domain and returns its SPIFFE ID
+func VerifyX509SVID(chain []*x509.Certificate, bundles map[string]*Bundle, now time.Time) (ID, error) {
+	if len(chain) == 0 {
+		return ID{}, fmt.Errorf("no X.509-SVID presented")
+	}
+	leaf := chain[0]
+	id, err := CertificateID(leaf)
+	if err != nil {
+		return ID{}, err
+	}
+	if leaf.IsCA {
+		return ID{}, fmt.Errorf("X.509-SVID must not be a CA certificate")
+	}
+	if leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return ID{}, fmt.Errorf("X.509-SVID must allow digital signatures")
+	}
+
+	bundle, ok := bundles[id.TrustDomain]
+	if !ok || len(bundle.X509Authorities) == 0 {
+		return ID{}, fmt.Errorf("trust domain %s is not trusted for X.509-SVIDs", id.TrustDomain)
+	}
+	roots := x509.NewCertPool()
+	for _, authority := range bundle.X509Authorities {
+		roots.AddCert(authority)
+	}
+	intermediates := x509.NewCertPool()
+	for _, certificate := range chain[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return ID{}, fmt.Errorf("X.509-SVID does not chain to the %s trust bundle: %w", id.TrustDomain, err)
+	}
+	return id, nil
+}
+
+// IsJWTSVID reports whether a bearer token claims to be a JWT-SVID, so it can be told apart from
+// user tokens before it is verified
+func IsJWTSVID(token string) bool {
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return false
+	}
+	return strings.HasPrefix(claims.Subject, "spiffe://")
+}
+
+// VerifyJWTSVID verifies a JWT-SVID against the bundle of its subject's trust domain and returns
+// its SPIFFE ID. The token must be issued for the given audience.
+func VerifyJWTSVID(token string, bundles map[string]*Bundle, audience string, now time.Time) (ID, error) {
+	var id ID
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		subject, _ := t.Claims.GetSubject()
+		parsed, err := ParseID(subject)
+		if err != nil {
+			return nil, err
+		}
+		bundle, ok := bundles[parsed.TrustDomain]
+		if !ok {
+			return nil, fmt.Errorf("trust domain %s is not trusted for JWT-SVIDs", parsed.TrustDomain)
+		}
+		kid, _ := t.Header["kid"].(string)
+		key, ok := bundle.JWTAuthorities[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown JWT-SVID key %q", kid)
+		}
+		id = parsed
+		return key, nil
+	}
+
+	_, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, keyFunc,
+		jwt.WithValidMethods(jwtSVIDAlgorithms),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(func() time.Time { return now }),
+	)
+	if err != nil {
+		return ID{}, fmt.Errorf("invalid JWT-SVID: %w", err)
+	}
+	return id, nil
+}
+
+// ParseCertificatesPEM parses a PEM certificate chain
+func ParseCertificatesPEM(data []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+		chain = append(chain, certificate)
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no certificates found")
+	}
+	return chain, nil
+}
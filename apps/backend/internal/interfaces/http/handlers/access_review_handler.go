@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// AccessReviewHandler exposes access review campaigns to admins and reviewers
+type AccessReviewHandler struct {
+	accessReviewService *application.AccessReviewService
+	auditService        *application.AuditService
+}
+
+func NewAccessReviewHandler(
+	accessReviewService *application.AccessReviewService,
+	auditService *application.AuditService,
+) *AccessReviewHandler {
+	return &AccessReviewHandler{
+		accessReviewService: accessReviewService,
+		auditService:        auditService,
+	}
+}
+
+// accessReviewError maps service errors to HTTP responses
+func accessReviewError(c fiber.Ctx, err error, fallback string) error {
+	msg := err.Error()
+	switch {
+	case msg == "access review campaign not found", msg == "access review item not found", msg == "access review evidence not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
+	case msg == "not the assigned reviewer", msg == "reviewers cannot decide on their own access":
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": msg})
+	case msg == "access review item already decided", msg == "access review campaign is not active",
+		strings.HasPrefix(msg, "access review campaign has"):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": msg})
+	case strings.HasPrefix(msg, "invalid access review"), strings.HasPrefix(msg, "invalid decision"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fallback})
+}
+
+// CreateCampaign starts an access review campaign
+// @Summary Create access review campaign
+// @Description Snapshot every user role, agent capability grant and API key in scope and assign each to a reviewer (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body application.AccessReviewCampaignRequest true "Campaign"
+// @Success 201 {object} domain.AccessReviewCampaign
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/admin/access-reviews [post]
+func (h *AccessReviewHandler) CreateCampaign(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	var req application.AccessReviewCampaignRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	campaign, err := h.accessReviewService.CreateCampaign(c.Context(), orgID, userID, req)
+	if err != nil {
+		return accessReviewError(c, err, "Failed to create access review campaign")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionCreate,
+		"access_review_campaign",
+		campaign.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"name":        campaign.Name,
+			"scope":       campaign.Scope,
+			"due_at":      campaign.DueAt,
+			"total_items": campaign.TotalItems,
+		},
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(campaign)
+}
+
+// ListCampaigns lists access review campaigns
+// @Summary List access review campaigns
+// @Description List the organization's access review campaigns, newest first (Admin only)
+// @Tags admin
+// @Produce json
+// @Param status query string false "Filter by status (active, completed, cancelled)"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/access-reviews [get]
+func (h *AccessReviewHandler) ListCampaigns(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var status *domain.AccessReviewCampaignStatus
+	if value := c.Query("status"); value != "" {
+		s := domain.AccessReviewCampaignStatus(value)
+		status = &s
+	}
+
+	campaigns, total, err := h.accessReviewService.ListCampaigns(c.Context(), orgID, status, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list access review campaigns",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"campaigns": campaigns,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// GetCampaign returns a campaign with its items
+// @Summary Get access review campaign
+// @Description Get a campaign with its items, optionally filtered by reviewer and decision (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Param reviewer_id query string false "Reviewer user ID"
+// @Param decision query string false "Decision (pending, certified, revoked)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/access-reviews/{id} [get]
+func (h *AccessReviewHandler) GetCampaign(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	campaignID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid campaign ID",
+		})
+	}
+
+	var reviewerID *uuid.UUID
+	if value := c.Query("reviewer_id"); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid reviewer_id",
+			})
+		}
+		reviewerID = &parsed
+	}
+
+	var decision *domain.AccessReviewDecision
+	if value := c.Query("decision"); value != "" {
+		d := domain.AccessReviewDecision(value)
+		decision = &d
+	}
+
+	campaign, err := h.accessReviewService.GetCampaign(c.Context(), orgID, campaignID)
+	if err != nil {
+		return accessReviewError(c, err, "Failed to fetch access review campaign")
+	}
+
+	items, err := h.accessReviewService.GetCampaignItems(c.Context(), orgID, campaignID, reviewerID, decision)
+	if err != nil {
+		return accessReviewError(c, err, "Failed to fetch access review items")
+	}
+
+	return c.JSON(fiber.Map{
+		"campaign": campaign,
+		"items":    items,
+	})
+}
+
+// CompleteCampaign completes a campaign whose items are all decided
+// @Summary Complete access review campaign
+// @Description Complete a campaign and seal its decisions as signed evidence; every item must be decided (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} domain.AccessReviewCampaign
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/access-reviews/{id}/complete [post]
+func (h *AccessReviewHandler) CompleteCampaign(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	campaignID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid campaign ID",
+		})
+	}
+
+	campaign, err := h.accessReviewService.CompleteCampaign(c.Context(), orgID, campaignID)
+	if err != nil {
+		return accessReviewError(c, err, "Failed to complete access review campaign")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionUpdate,
+		"access_review_campaign",
+		campaignID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"status":          campaign.Status,
+			"certified_items": campaign.CertifiedItems,
+			"revoked_items":   campaign.RevokedItems,
+		},
+	)
+
+	return c.JSON(campaign)
+}
+
+// CancelCampaign cancels an active campaign
+// @Summary Cancel access review campaign
+// @Description Cancel an active campaign; decisions already made stay applied (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/access-reviews/{id}/cancel [post]
+func (h *AccessReviewHandler) CancelCampaign(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	campaignID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid campaign ID",
+		})
+	}
+
+	if err := h.accessReviewService.CancelCampaign(c.Context(), orgID, campaignID); err != nil {
+		return accessReviewError(c, err, "Failed to cancel access review campaign")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionUpdate,
+		"access_review_campaign",
+		campaignID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"status": domain.AccessReviewCampaignCancelled,
+		},
+	)
+
+	return c.JSON(fiber.Map{
+		"message": "Access review campaign cancelled",
+	})
+}
+
+// GetEvidence returns the sealed evidence of a completed campaign
+// @Summary Get access review evidence
+// @Description Get the immutable, signed snapshot of a completed campaign and whether it still verifies (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/access-reviews/{id}/evidence [get]
+func (h *AccessReviewHandler) GetEvidence(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	campaignID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid campaign ID",
+		})
+	}
+
+	evidence, verified, err := h.accessReviewService.GetEvidence(c.Context(), orgID, campaignID)
+	if err != nil {
+		return accessReviewError(c, err, "Failed to fetch access review evidence")
+	}
+
+	return c.JSON(fiber.Map{
+		"evidence": evidence,
+		"verified": verified,
+	})
+}
+
+// ListMyItems lists the items awaiting the current user's decision
+// @Summary List my access review items
+// @Description List undecided access review items assigned to the current user
+// @Tags access-reviews
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/access-reviews/my-items [get]
+func (h *AccessReviewHandler) ListMyItems(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	items, err := h.accessReviewService.ListPendingItemsForReviewer(c.Context(), orgID, userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch access review items",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"items": items,
+		"total": len(items),
+	})
+}
+
+// DecideItem certifies or revokes an access review item
+// @Summary Decide access review item
+// @Description Certify or revoke an item assigned to the current user. Revocations take effect immediately.
+// @Tags access-reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param request body map[string]string true "decision (certified or revoked) and optional justification"
+// @Success 200 {object} domain.AccessReviewItem
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/access-reviews/items/{id}/decision [post]
+func (h *AccessReviewHandler) DecideItem(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+	role, _ := c.Locals("role").(string)
+
+	itemID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid item ID",
+		})
+	}
+
+	var req struct {
+		Decision      domain.AccessReviewDecision `json:"decision"`
+		Justification string                      `json:"justification"`
+	}
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	item, err := h.accessReviewService.DecideItem(
+		c.Context(),
+		orgID,
+		itemID,
+		userID,
+		role == string(domain.RoleAdmin),
+		req.Decision,
+		req.Justification,
+	)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "failed to revoke access") {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return accessReviewError(c, err, "Failed to record access review decision")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionUpdate,
+		"access_review_item",
+		item.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"campaign_id":   item.CampaignID,
+			"item_type":     item.ItemType,
+			"resource_id":   item.ResourceID,
+			"decision":      item.Decision,
+			"justification": item.Justification,
+		},
+	)
+
+	return c.JSON(item)
+}
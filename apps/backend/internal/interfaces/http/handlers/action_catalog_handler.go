@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// ActionCatalogHandler lets admins manage the risk tier, required trust, capability and
+// approval requirement of the actions their agents request
+type ActionCatalogHandler struct {
+	actionCatalogService *application.ActionCatalogService
+	auditService         *application.AuditService
+}
+
+func NewActionCatalogHandler(
+	actionCatalogService *application.ActionCatalogService,
+	auditService *application.AuditService,
+) *ActionCatalogHandler {
+	return &ActionCatalogHandler{
+		actionCatalogService: actionCatalogService,
+		auditService:         auditService,
+	}
+}
+
+// actionCatalogError maps service errors to HTTP responses
+func actionCatalogError(c fiber.Ctx, err error, fallback string) error {
+	msg := err.Error()
+	switch {
+	case msg == "action catalog entry not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
+	case strings.HasPrefix(msg, "action catalog entry already exists"):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": msg})
+	case strings.HasPrefix(msg, "invalid action catalog entry"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fallback})
+}
+
+func (h *ActionCatalogHandler) logChange(c fiber.Ctx, action domain.AuditAction, entry *domain.ActionCatalogEntry) {
+	h.auditService.LogAction(
+		c.Context(),
+		c.Locals("organization_id").(uuid.UUID),
+		c.Locals("user_id").(uuid.UUID),
+		action,
+		"action_catalog_entry",
+		entry.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"action":              entry.Action,
+			"risk_tier":           entry.RiskTier,
+			"required_trust":      entry.RequiredTrust,
+			"required_capability": entry.RequiredCapability,
+			"requires_approval":   entry.RequiresApproval,
+			"aliases":             entry.Aliases,
+		},
+	)
+}
+
+// ListActions returns the organization's action catalog
+// @Summary List action catalog
+// @Description Risk tier, required trust, required capability, approval requirement and aliases of every cataloged action. Actions not in the catalog are treated as medium risk (Admin only)
+// @Tags security
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/action-catalog [get]
+func (h *ActionCatalogHandler) ListActions(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	entries, err := h.actionCatalogService.List(c.Context(), orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch action catalog",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"actions": entries,
+	})
+}
+
+// GetAction returns a single action catalog entry
+// @Summary Get action catalog entry
+// @Tags security
+// @Produce json
+// @Param id path string true "Entry ID"
+// @Success 200 {object} domain.ActionCatalogEntry
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/action-catalog/{id} [get]
+func (h *ActionCatalogHandler) GetAction(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid action catalog entry ID",
+		})
+	}
+
+	entry, err := h.actionCatalogService.Get(c.Context(), orgID, id)
+	if err != nil {
+		return actionCatalogError(c, err, "Failed to fetch action catalog entry")
+	}
+
+	return c.JSON(entry)
+}
+
+// CreateAction adds an action to the catalog
+// @Summary Create action catalog entry
+// @Description Catalog an action. required_trust, trust_multiplier and requires_approval default from risk_tier; aliases map MCP tool names onto the action (Admin only)
+// @Tags security
+// @Accept json
+// @Produce json
+// @Param request body application.ActionCatalogRequest true "Catalog entry"
+// @Success 201 {object} domain.ActionCatalogEntry
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/action-catalog [post]
+func (h *ActionCatalogHandler) CreateAction(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	var req application.ActionCatalogRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	entry, err := h.actionCatalogService.Create(c.Context(), orgID, userID, req)
+	if err != nil {
+		return actionCatalogError(c, err, "Failed to create action catalog entry")
+	}
+	h.logChange(c, domain.AuditActionCreate, entry)
+
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+// UpdateAction replaces an action catalog entry
+// @Summary Update action catalog entry
+// @Tags security
+// @Accept json
+// @Produce json
+// @Param id path string true "Entry ID"
+// @Param request body application.ActionCatalogRequest true "Catalog entry"
+// @Success 200 {object} domain.ActionCatalogEntry
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/action-catalog/{id} [put]
+func (h *ActionCatalogHandler) UpdateAction(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid action catalog entry ID",
+		})
+	}
+
+	var req application.ActionCatalogRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	entry, err := h.actionCatalogService.Update(c.Context(), orgID, id, req)
+	if err != nil {
+		return actionCatalogError(c, err, "Failed to update action catalog entry")
+	}
+	h.logChange(c, domain.AuditActionUpdate, entry)
+
+	return c.JSON(entry)
+}
+
+// DeleteAction removes an action from the catalog
+// @Summary Delete action catalog entry
+// @Description Remove an action from the catalog; it is then treated as medium risk (Admin only)
+// @Tags security
+// @Param id path string true "Entry ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/action-catalog/{id} [delete]
+func (h *ActionCatalogHandler) DeleteAction(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid action catalog entry ID",
+		})
+	}
+
+	entry, err := h.actionCatalogService.Get(c.Context(), orgID, id)
+	if err != nil {
+		return actionCatalogError(c, err, "Failed to delete action catalog entry")
+	}
+	if err := h.actionCatalogService.Delete(c.Context(), orgID, id); err != nil {
+		return actionCatalogError(c, err, "Failed to delete action catalog entry")
+	}
+	h.logChange(c, domain.AuditActionDelete, entry)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RestoreDefaults re-adds default entries missing from the catalog
+// @Summary Restore default action catalog
+// @Description Re-add default catalog entries that were deleted; edited entries are kept (Admin only)
+// @Tags security
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/action-catalog/restore-defaults [post]
+func (h *ActionCatalogHandler) RestoreDefaults(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	added, err := h.actionCatalogService.SeedDefaults(c.Context(), orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to restore default action catalog",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionUpdate,
+		"action_catalog",
+		orgID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"restored_defaults": added,
+		},
+	)
+
+	return c.JSON(fiber.Map{
+		"restored": added,
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"context"
 	"fmt"
 	"log"
@@ -1026,6 +1027,10 @@ func (h *AdminHandler) ApproveRegistrationRequest(c fiber.Ctx) error {
 	// Approve registration request
 	newUser, err := h.registrationService.ApproveRegistrationRequest(c.Context(), requestID, adminID, orgID)
 	if err != nil {
+		var quotaErr *application.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceededResponse(c, quotaErr)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": fmt.Sprintf("Failed to approve registration: %v", err),
 		})
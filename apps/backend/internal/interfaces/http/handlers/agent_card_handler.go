@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// AgentCardHandler publishes agents as signed A2A Agent Cards and verifies cards for other agents
+type AgentCardHandler struct {
+	cardService  *application.AgentCardService
+	auditService *application.AuditService
+}
+
+// NewAgentCardHandler creates a new Agent Card handler
+func NewAgentCardHandler(
+	cardService *application.AgentCardService,
+	auditService *application.AuditService,
+) *AgentCardHandler {
+	return &AgentCardHandler{
+		cardService:  cardService,
+		auditService: auditService,
+	}
+}
+
+// VerifyAgentCardRequest is a request to verify an Agent Card
+type VerifyAgentCardRequest struct {
+	Card          json.RawMessage `json:"card"`
+	MinTrustScore *float64        `json:"minTrustScore,omitempty"`
+}
+
+// PublishCard publishes an agent as an A2A Agent Card
+// @Summary Publish A2A Agent Card
+// @Description Publishes the agent at a public Agent Card URL. The card is generated from the agent's metadata, granted capabilities and the declared A2A endpoint and skills, and signed by AIM. Publishing again replaces the declared fields
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Param request body application.AgentCardRequest true "A2A endpoint and skills"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/card [put]
+func (h *AgentCardHandler) PublishCard(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	var req application.AgentCardRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	record, err := h.cardService.Publish(c.Context(), orgID, agentID, userID, &req)
+	if err != nil {
+		if errors.Is(err, application.ErrInvalidAgentCard) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if err.Error() == "agent not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to publish agent card",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionUpdate,
+		"agent_card",
+		agentID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"card_id": record.ID,
+			"url":     record.URL,
+			"skills":  len(record.Skills),
+		},
+	)
+
+	return c.JSON(fiber.Map{
+		"card":     record,
+		"card_url": h.cardService.CardURL(agentID),
+	})
+}
+
+// GetCard returns an agent's published card
+// @Summary Get A2A Agent Card
+// @Description Returns the declared fields of the agent's published card and the signed card as currently served
+// @Tags agents
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/card [get]
+func (h *AgentCardHandler) GetCard(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	record, err := h.cardService.GetRecord(c.Context(), orgID, agentID)
+	if err != nil {
+		if errors.Is(err, application.ErrAgentCardNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Agent has no published card"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get agent card",
+		})
+	}
+
+	response := fiber.Map{
+		"card":     record,
+		"card_url": h.cardService.CardURL(agentID),
+	}
+	// The signed card is only served while the agent is verified
+	signed, err := h.cardService.SignedCard(c.Context(), agentID)
+	if err == nil {
+		response["signed_card"] = signed
+	} else if errors.Is(err, application.ErrAgentCardUnavailable) {
+		response["unavailable_reason"] = err.Error()
+	} else {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to sign agent card",
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// UnpublishCard revokes an agent's published card
+// @Summary Unpublish A2A Agent Card
+// @Description Stops serving the agent's card. Cards already handed out fail verification from now on
+// @Tags agents
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/card [delete]
+func (h *AgentCardHandler) UnpublishCard(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	if err := h.cardService.Unpublish(c.Context(), orgID, agentID); err != nil {
+		if errors.Is(err, application.ErrAgentCardNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Agent has no published card"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to unpublish agent card",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionRevoke,
+		"agent_card",
+		agentID,
+		c.IP(),
+		c.Get("User-Agent"),
+		nil,
+	)
+
+	return c.JSON(fiber.Map{
+		"message": "Agent card unpublished",
+	})
+}
+
+// WellKnownCard serves an agent's signed Agent Card
+// @Summary A2A Agent Card
+// @Description Signed A2A Agent Card of a published agent. Cards are valid for 24 hours; verify them with the signature's jku key set or the verification endpoint
+// @Tags a2a
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} domain.AgentCard
+// @Failure 404 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Router /a2a/agents/{id}/.well-known/agent.json [get]
+func (h *AgentCardHandler) WellKnownCard(c fiber.Ctx) error {
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Agent card not found"})
+	}
+
+	card, err := h.cardService.SignedCard(c.Context(), agentID)
+	if err != nil {
+		if errors.Is(err, application.ErrAgentCardNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Agent card not found"})
+		}
+		if errors.Is(err, application.ErrAgentCardUnavailable) {
+			return c.Status(fiber.StatusGone).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to sign agent card",
+		})
+	}
+
+	c.Set("Cache-Control", "public, max-age=300")
+	return c.JSON(card)
+}
+
+// VerifyCard verifies an Agent Card for an agent about to delegate work
+// @Summary Verify A2A Agent Card
+// @Description Checks the card's AIM signature and issuer, whether the card or its agent has been revoked, and the agent's current trust score (against minTrustScore when given). Problems are listed in errors; valid is true only if there are none
+// @Tags a2a
+// @Accept json
+// @Produce json
+// @Param request body VerifyAgentCardRequest true "Agent Card to verify"
+// @Success 200 {object} domain.AgentCardVerification
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/public/a2a/verify [post]
+func (h *AgentCardHandler) VerifyCard(c fiber.Ctx) error {
+	var req VerifyAgentCardRequest
+	if err := c.Bind().JSON(&req); err != nil || len(req.Card) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "card is required",
+		})
+	}
+
+	result, err := h.cardService.Verify(c.Context(), req.Card, req.MinTrustScore)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to verify agent card",
+		})
+	}
+
+	return c.JSON(result)
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
@@ -18,6 +21,9 @@ type AgentHandler struct {
 	trustScoreHandler         *TrustScoreHandler
 	alertService              *application.AlertService
 	verificationEventService  *application.VerificationEventService
+	anomalyDetectionService   *application.AnomalyDetectionService
+	dataExfiltrationService   *application.DataExfiltrationService
+	quotaService              *application.QuotaService
 }
 
 func NewAgentHandler(
@@ -28,6 +34,9 @@ func NewAgentHandler(
 	trustScoreHandler *TrustScoreHandler,
 	alertService *application.AlertService,
 	verificationEventService *application.VerificationEventService,
+	anomalyDetectionService *application.AnomalyDetectionService,
+	dataExfiltrationService *application.DataExfiltrationService,
+	quotaService *application.QuotaService,
 ) *AgentHandler {
 	return &AgentHandler{
 		agentService:             agentService,
@@ -37,6 +46,9 @@ func NewAgentHandler(
 		trustScoreHandler:        trustScoreHandler,
 		alertService:             alertService,
 		verificationEventService: verificationEventService,
+		anomalyDetectionService:  anomalyDetectionService,
+		dataExfiltrationService:  dataExfiltrationService,
+		quotaService:             quotaService,
 	}
 }
 
@@ -71,6 +83,10 @@ func (h *AgentHandler) CreateAgent(c fiber.Ctx) error {
 
 	agent, err := h.agentService.CreateAgent(c.Context(), &req, orgID, userID)
 	if err != nil {
+		var quotaErr *application.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceededResponse(c, quotaErr)
+		}
 		// Log the full error for debugging
 		fmt.Printf("ERROR creating agent: %v\n", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -326,6 +342,16 @@ func (h *AgentHandler) VerifyAction(c fiber.Ctx) error {
 	orgID := agent.OrganizationID
 	startTime := c.Context().Time()
 
+	// Enforce the plan's monthly verification quota
+	if h.quotaService != nil {
+		if err := h.quotaService.CheckQuota(c.Context(), orgID, domain.QuotaVerifications); err != nil {
+			var quotaErr *application.QuotaExceededError
+			if errors.As(err, &quotaErr) {
+				return quotaExceededResponse(c, quotaErr)
+			}
+		}
+	}
+
 	// Fetch agent and verify capabilities
 	decision, reason, auditID, err := h.agentService.VerifyAction(
 		c.Context(),
@@ -341,6 +367,49 @@ func (h *AgentHandler) VerifyAction(c fiber.Ctx) error {
 		})
 	}
 
+	// Deny further data exports once the agent exceeded its data_exfiltration threshold
+	if decision && h.dataExfiltrationService != nil {
+		usage, err := h.dataExfiltrationService.CheckExport(c.Context(), agent, req.ActionType)
+		if err != nil {
+			fmt.Printf("⚠️  Data export check failed for agent %s: %v\n", agentID, err)
+		} else if usage != nil && usage.ExportBlocked {
+			decision = false
+			reason = h.dataExfiltrationService.ExportDeniedReason(usage)
+		}
+	}
+
+	// Compare allowed actions against the agent's learned activity baseline.
+	// Detection fails open: an unavailable baseline never denies an action.
+	var anomalyResult *domain.ActivityAnomalyResult
+	if decision && h.anomalyDetectionService != nil {
+		anomalyResult, err = h.anomalyDetectionService.ObserveActivity(c.Context(), agent, domain.ActivityObservation{
+			Action:    req.ActionType,
+			Resource:  req.Resource,
+			MCPServer: application.ActivityMCPServer(req.Metadata),
+			SourceIP:  c.IP(),
+		})
+		if err != nil {
+			fmt.Printf("⚠️  Anomaly detection failed for agent %s: %v\n", agentID, err)
+		} else if anomalyResult.Blocked {
+			decision = false
+			reason = anomalyResult.Reason
+		}
+	}
+
+	// Account for the bytes the agent reported reading or exporting
+	if decision && h.dataExfiltrationService != nil {
+		if _, err := h.dataExfiltrationService.RecordReportedTransfer(
+			c.Context(), agent, domain.DataTransferSourceVerifyAction, req.Resource, &auditID, req.Metadata,
+		); err != nil {
+			fmt.Printf("⚠️  Failed to record data transfer for agent %s: %v\n", agentID, err)
+		}
+	}
+
+	// Meter the call against the monthly verification quota
+	if h.quotaService != nil {
+		h.quotaService.RecordVerification(c.Context(), orgID, decision)
+	}
+
 	// Calculate duration
 	durationMs := int(c.Context().Time().Sub(startTime).Milliseconds())
 
@@ -355,6 +424,10 @@ func (h *AgentHandler) VerifyAction(c fiber.Ctx) error {
 	if req.Metadata != nil {
 		auditMetadata["request_metadata"] = req.Metadata
 	}
+	if anomalyResult != nil && len(anomalyResult.Anomalies) > 0 {
+		auditMetadata["anomalies"] = len(anomalyResult.Anomalies)
+		auditMetadata["anomaly_blocked"] = anomalyResult.Blocked
+	}
 
 	userID := uuid.Nil // System action - no specific user
 	if userIDLocal := c.Locals("user_id"); userIDLocal != nil {
@@ -513,6 +586,21 @@ func (h *AgentHandler) LogActionResult(c fiber.Ctx) error {
 		})
 	}
 
+	// Account for the bytes the action read or exported (result.bytes_read, result.bytes_exported)
+	if h.dataExfiltrationService != nil && application.DataTransferFromMetadata(req.Result) != nil {
+		agent, err := h.agentService.GetAgent(c.Context(), agentID)
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Agent not found",
+			})
+		}
+		if _, err := h.dataExfiltrationService.RecordReportedTransfer(
+			c.Context(), agent, domain.DataTransferSourceActionResult, "", &auditID, req.Result,
+		); err != nil {
+			fmt.Printf("⚠️  Failed to record data transfer for agent %s: %v\n", agentID, err)
+		}
+	}
+
 	return c.JSON(fiber.Map{
 		"success": true,
 	})
@@ -948,9 +1036,10 @@ func (h *AgentHandler) GetAgentMCPServers(c fiber.Ctx) error {
 
 // DetectAndMapMCPServers auto-detects MCP servers from Claude Desktop config and maps them to agent
 // @Summary Auto-detect and map MCP servers
-// @Description Automatically detect MCP servers from Claude Desktop config file and map them to agent's talks_to list
+// @Description Detect MCP servers in an uploaded Claude Desktop, Cursor, VS Code, Windsurf or Continue config and map them to agent's talks_to list.
+// @Description Send the config as JSON (config_content) or as a multipart "config" file with config_format, auto_register and dry_run form fields.
 // @Tags agents
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Param id path string true "Agent ID"
 // @Param request body application.DetectMCPServersRequest true "Auto-detection configuration"
@@ -968,18 +1057,29 @@ func (h *AgentHandler) DetectAndMapMCPServers(c fiber.Ctx) error {
 		})
 	}
 
-	// Parse request body
+	// Parse request body (JSON or multipart upload)
 	var req application.DetectMCPServersRequest
-	if err := c.Bind().JSON(&req); err != nil {
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		content, err := readMCPConfigUpload(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		req.ConfigContent = content
+		req.ConfigFormat = application.MCPConfigFormat(c.FormValue("config_format"))
+		req.AutoRegister = c.FormValue("auto_register") == "true"
+		req.DryRun = c.FormValue("dry_run") == "true"
+	} else if err := c.Bind().JSON(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
 		})
 	}
 
 	// Validate input
-	if req.ConfigPath == "" {
+	if strings.TrimSpace(req.ConfigContent) == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "config_path is required",
+			"error": "config_content is required",
 		})
 	}
 
@@ -1006,7 +1106,11 @@ func (h *AgentHandler) DetectAndMapMCPServers(c fiber.Ctx) error {
 		userID,
 	)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		status := fiber.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid config") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -1027,7 +1131,7 @@ func (h *AgentHandler) DetectAndMapMCPServers(c fiber.Ctx) error {
 				"detected_count":     len(result.DetectedServers),
 				"registered_count":   result.RegisteredCount,
 				"mapped_count":       result.MappedCount,
-				"config_path":        req.ConfigPath,
+				"config_format":      result.ConfigFormat,
 				"auto_register":      req.AutoRegister,
 			},
 		)
@@ -1036,6 +1140,30 @@ func (h *AgentHandler) DetectAndMapMCPServers(c fiber.Ctx) error {
 	return c.JSON(result)
 }
 
+// readMCPConfigUpload reads the "config" file of a multipart MCP config import
+func readMCPConfigUpload(c fiber.Ctx) (string, error) {
+	header, err := c.FormFile("config")
+	if err != nil {
+		return "", fmt.Errorf("config file is required")
+	}
+	if header.Size > application.MaxMCPConfigSize {
+		return "", fmt.Errorf("config file exceeds %d bytes", application.MaxMCPConfigSize)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file")
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, application.MaxMCPConfigSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file")
+	}
+
+	return string(content), nil
+}
+
 // GetAgentByIdentifier returns agent by ID or name (SDK API endpoint with API key auth)
 // @Summary Get agent by ID or name
 // @Description Get agent details by UUID or name. Works with API key authentication for SDK usage.
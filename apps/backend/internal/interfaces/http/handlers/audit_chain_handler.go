@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// AuditChainHandler exposes audit log tamper detection to admins
+type AuditChainHandler struct {
+	auditChainService *application.AuditChainService
+	auditService      *application.AuditService
+}
+
+func NewAuditChainHandler(
+	auditChainService *application.AuditChainService,
+	auditService *application.AuditService,
+) *AuditChainHandler {
+	return &AuditChainHandler{
+		auditChainService: auditChainService,
+		auditService:      auditService,
+	}
+}
+
+// VerifyChain verifies the organization's audit log hash chain
+// @Summary Verify audit log integrity
+// @Description Walk the organization's hash-chained audit log and signed checkpoints and report the first broken link (Admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.AuditChainVerification
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/audit-logs/verify [get]
+func (h *AuditChainHandler) VerifyChain(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	result, err := h.auditChainService.VerifyChain(c.Context(), orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to verify audit log chain",
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// GetCheckpoints lists signed audit chain checkpoints
+// @Summary List audit log checkpoints
+// @Description List Ed25519-signed checkpoints of the organization's audit log chain, newest first (Admin only)
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/audit-logs/checkpoints [get]
+func (h *AuditChainHandler) GetCheckpoints(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	checkpoints, err := h.auditChainService.GetCheckpoints(c.Context(), orgID, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch audit log checkpoints",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"checkpoints": checkpoints,
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
+
+// CreateCheckpoint signs the current head of the organization's audit chain
+// @Summary Create audit log checkpoint
+// @Description Verify the records appended since the last checkpoint and sign the current chain head (Admin only)
+// @Tags admin
+// @Produce json
+// @Success 201 {object} domain.AuditCheckpoint
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/audit-logs/checkpoints [post]
+func (h *AuditChainHandler) CreateCheckpoint(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	checkpoint, err := h.auditChainService.CreateCheckpoint(c.Context(), orgID)
+	if err != nil {
+		if err.Error() == "audit signing key not configured" {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if checkpoint == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "No chained audit records to checkpoint",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionCreate,
+		"audit_checkpoint",
+		checkpoint.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"sequence_number": checkpoint.SequenceNumber,
+			"key_id":          checkpoint.KeyID,
+		},
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(checkpoint)
+}
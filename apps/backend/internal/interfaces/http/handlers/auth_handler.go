@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
 	"github.com/opena2a/identity/backend/internal/application"
@@ -9,20 +11,23 @@ import (
 )
 
 type AuthHandler struct {
-	authService  *application.AuthService
-	jwtService   *auth.JWTService
-	orgRepo      domain.OrganizationRepository
+	authService         *application.AuthService
+	jwtService          *auth.JWTService
+	orgRepo             domain.OrganizationRepository
+	refreshTokenService *application.RefreshTokenService
 }
 
 func NewAuthHandler(
 	authService *application.AuthService,
 	jwtService *auth.JWTService,
 	orgRepo domain.OrganizationRepository,
+	refreshTokenService *application.RefreshTokenService,
 ) *AuthHandler {
 	return &AuthHandler{
-		authService:  authService,
-		jwtService:   jwtService,
-		orgRepo:      orgRepo,
+		authService:         authService,
+		jwtService:          jwtService,
+		orgRepo:             orgRepo,
+		refreshTokenService: refreshTokenService,
 	}
 }
 
@@ -91,13 +96,8 @@ func (h *AuthHandler) LocalLogin(c fiber.Ctx) error {
 		})
 	}
 
-	// Generate JWT tokens
-	accessToken, refreshToken, err := h.jwtService.GenerateTokenPair(
-		user.ID.String(),
-		user.OrganizationID.String(),
-		user.Email,
-		string(user.Role),
-	)
+	// Generate JWT tokens and start a new session (refresh token family)
+	accessToken, refreshToken, err := h.refreshTokenService.StartSession(c.Context(), user, c.IP(), c.Get("User-Agent"))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to generate tokens",
@@ -212,6 +212,7 @@ func (h *AuthHandler) GetCurrentOrganization(c fiber.Ctx) error {
 	// Return organization info
 	return c.JSON(fiber.Map{
 		"id":         org.ID,
+		"parent_id":  org.ParentID,
 		"name":       org.Name,
 		"plan":       org.PlanType,
 		"max_agents": org.MaxAgents,
@@ -221,8 +222,23 @@ func (h *AuthHandler) GetCurrentOrganization(c fiber.Ctx) error {
 	})
 }
 
-// Logout clears authentication
+// Logout clears authentication and signs the session out
 func (h *AuthHandler) Logout(c fiber.Ctx) error {
+	token := c.Cookies("refresh_token")
+	if token == "" {
+		token = c.Cookies("access_token")
+	}
+	if token == "" {
+		token = strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
+	}
+	if token != "" {
+		if err := h.refreshTokenService.Logout(c.Context(), token); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to sign out session",
+			})
+		}
+	}
+
 	// Clear cookies
 	c.Cookie(&fiber.Cookie{
 		Name:     "access_token",
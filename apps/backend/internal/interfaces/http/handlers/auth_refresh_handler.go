@@ -3,6 +3,7 @@ package handlers
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
@@ -14,21 +15,27 @@ import (
 
 // AuthRefreshHandler handles token refresh operations
 type AuthRefreshHandler struct {
-	jwtService      *auth.JWTService
-	sdkTokenService *application.SDKTokenService
+	jwtService          *auth.JWTService
+	sdkTokenService     *application.SDKTokenService
+	refreshTokenService *application.RefreshTokenService
 }
 
 // NewAuthRefreshHandler creates a new auth refresh handler
-func NewAuthRefreshHandler(jwtService *auth.JWTService, sdkTokenService *application.SDKTokenService) *AuthRefreshHandler {
+func NewAuthRefreshHandler(
+	jwtService *auth.JWTService,
+	sdkTokenService *application.SDKTokenService,
+	refreshTokenService *application.RefreshTokenService,
+) *AuthRefreshHandler {
 	return &AuthRefreshHandler{
-		jwtService:      jwtService,
-		sdkTokenService: sdkTokenService,
+		jwtService:          jwtService,
+		sdkTokenService:     sdkTokenService,
+		refreshTokenService: refreshTokenService,
 	}
 }
 
 // RefreshToken godoc
 // @Summary Refresh access token
-// @Description Refresh access token using refresh token (with token rotation)
+// @Description Refresh access token using refresh token (with token rotation). Presenting a session refresh token that was already rotated signs the whole session out
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -52,6 +59,12 @@ func (h *AuthRefreshHandler) RefreshToken(c fiber.Ctx) error {
 		})
 	}
 
+	// Login session tokens are rotated within their refresh token family; SDK and recovered
+	// tokens are tracked in sdk_tokens below
+	if claims, err := h.jwtService.ValidateToken(req.RefreshToken); err == nil && claims.SessionID != "" {
+		return h.refreshSession(c, req.RefreshToken)
+	}
+
 	// Check if this is an SDK token and verify it's not revoked BEFORE rotating
 	tokenID, err := h.jwtService.GetTokenID(req.RefreshToken)
 	if err == nil && tokenID != "" {
@@ -148,6 +161,33 @@ func (h *AuthRefreshHandler) RefreshToken(c fiber.Ctx) error {
 	})
 }
 
+// refreshSession rotates a user session refresh token
+func (h *AuthRefreshHandler) refreshSession(c fiber.Ctx, refreshToken string) error {
+	newAccessToken, newRefreshToken, err := h.refreshTokenService.Refresh(c.Context(), refreshToken, c.IP(), c.Get("User-Agent"))
+	if errors.Is(err, application.ErrRefreshTokenReused) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Refresh token has already been used; the session has been signed out",
+		})
+	}
+	if errors.Is(err, application.ErrInvalidRefreshToken) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid or expired refresh token",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to refresh token",
+		})
+	}
+
+	return c.JSON(RefreshTokenResponse{
+		AccessToken:  newAccessToken,
+		RefreshToken: newRefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    86400, // 24 hours in seconds
+	})
+}
+
 // Request/Response types
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
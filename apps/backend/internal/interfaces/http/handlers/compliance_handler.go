@@ -1,6 +1,12 @@
 package handlers
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
@@ -11,15 +17,18 @@ import (
 
 type ComplianceHandler struct {
 	complianceService *application.ComplianceService
+	evidenceService   *application.ComplianceEvidenceService
 	auditService      *application.AuditService
 }
 
 func NewComplianceHandler(
 	complianceService *application.ComplianceService,
+	evidenceService *application.ComplianceEvidenceService,
 	auditService *application.AuditService,
 ) *ComplianceHandler {
 	return &ComplianceHandler{
 		complianceService: complianceService,
+		evidenceService:   evidenceService,
 		auditService:      auditService,
 	}
 }
@@ -247,19 +256,10 @@ func (h *ComplianceHandler) ExportComplianceReport(c fiber.Ctx) error {
 		}
 	}
 
-	// Get compliance data
-	status, err := h.complianceService.GetComplianceStatus(c.Context(), orgID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch compliance status",
-		})
-	}
-
-	// Get metrics
-	metricsData, err := h.complianceService.GetComplianceMetrics(c.Context(), orgID, startDate, endDate, "day")
-	if err != nil {
+	var report bytes.Buffer
+	if err := h.complianceService.ExportComplianceReport(c.Context(), orgID, startDate, endDate, format, &report); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch compliance metrics",
+			"error": "Failed to generate compliance report",
 		})
 	}
 
@@ -282,19 +282,250 @@ func (h *ComplianceHandler) ExportComplianceReport(c fiber.Ctx) error {
 
 	if format == "json" {
 		c.Set("Content-Type", "application/json")
-		c.Set("Content-Disposition", "attachment; filename=compliance-report.json")
-		return c.JSON(fiber.Map{
-			"generated_at":    time.Now().Format(time.RFC3339),
-			"organization_id": orgID,
-			"status":          status,
-			"metrics":         metricsData,
+	} else {
+		c.Set("Content-Type", "text/csv")
+	}
+	c.Set("Content-Disposition", "attachment; filename=compliance-report."+format)
+
+	return c.Send(report.Bytes())
+}
+
+// ExportAuditLog streams the organization's audit log
+// @Summary Export audit log
+// @Description Stream audit log records in (timestamp, id) order as CSV, NDJSON or Parquet with server-side filters.
+// @Description With limit set, X-Next-Cursor holds the cursor for the next page when more records follow.
+// @Tags compliance
+// @Produce text/csv,application/x-ndjson,application/vnd.apache.parquet
+// @Param format query string false "Export format (csv, ndjson or parquet)" default(csv)
+// @Param start_date query string false "Include records at or after (RFC3339)"
+// @Param end_date query string false "Include records before (RFC3339)"
+// @Param user_id query string false "Actor user ID"
+// @Param action query string false "Comma-separated actions"
+// @Param resource_type query string false "Resource type"
+// @Param resource_id query string false "Resource ID"
+// @Param agent_id query string false "Agent ID (records about the agent or referencing it)"
+// @Param cursor query string false "Cursor from a previous page's X-Next-Cursor"
+// @Param limit query int false "Maximum records in this page (0 = all)" default(0)
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/compliance/audit-log/export [get]
+func (h *ComplianceHandler) ExportAuditLog(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	filter := domain.AuditLogFilter{
+		OrganizationID: orgID,
+		ResourceType:   c.Query("resource_type"),
+	}
+
+	for param, target := range map[string]**time.Time{
+		"start_date": &filter.StartDate,
+		"end_date":   &filter.EndDate,
+	} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid " + param + ", expected RFC3339",
+				})
+			}
+			*target = &parsed
+		}
+	}
+
+	for param, target := range map[string]**uuid.UUID{
+		"user_id":     &filter.UserID,
+		"resource_id": &filter.ResourceID,
+		"agent_id":    &filter.AgentID,
+	} {
+		if value := c.Query(param); value != "" {
+			parsed, err := uuid.Parse(value)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid " + param,
+				})
+			}
+			*target = &parsed
+		}
+	}
+
+	if actions := c.Query("action"); actions != "" {
+		for _, action := range strings.Split(actions, ",") {
+			if action = strings.TrimSpace(action); action != "" {
+				filter.Actions = append(filter.Actions, domain.AuditAction(action))
+			}
+		}
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "0"))
+	if err != nil || limit < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid limit",
+		})
+	}
+
+	format := c.Query("format", application.AuditExportFormatCSV)
+	export, err := h.complianceService.ExportAuditLog(c.Context(), filter, format, c.Query("cursor"), limit)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unsupported export format") || err.Error() == "invalid cursor" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to export audit log",
 		})
 	}
 
-	// CSV format - simplified since status type is interface{}
-	c.Set("Content-Type", "text/csv")
-	c.Set("Content-Disposition", "attachment; filename=compliance-report.csv")
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionExport,
+		"audit_log",
+		orgID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"format":        format,
+			"start_date":    filter.StartDate,
+			"end_date":      filter.EndDate,
+			"user_id":       filter.UserID,
+			"actions":       filter.Actions,
+			"resource_type": filter.ResourceType,
+			"resource_id":   filter.ResourceID,
+			"agent_id":      filter.AgentID,
+			"cursor":        c.Query("cursor"),
+			"limit":         limit,
+		},
+	)
+
+	c.Set("Content-Type", export.ContentType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=audit-log-%s.%s", time.Now().UTC().Format("20060102T150405Z"), export.FileExtension))
+	if export.NextCursor != "" {
+		c.Set("X-Next-Cursor", export.NextCursor)
+	}
+
+	// The body is written after the handler returns, so it cannot use the request context
+	c.Response().SetBodyStreamWriter(func(w *bufio.Writer) {
+		written, err := export.WriteTo(context.Background(), w)
+		if err != nil {
+			fmt.Printf("⚠️  Audit log export for org %s stopped after %d records: %v\n", orgID, written, err)
+		}
+	})
+
+	return nil
+}
+
+// ListFrameworks lists the compliance frameworks with evidence catalogs
+// @Summary List compliance frameworks
+// @Description List the framework control catalogs (SOC 2, ISO 27001, EU AI Act) and the AIM evidence each control is tested against
+// @Tags compliance
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/compliance/frameworks [get]
+func (h *ComplianceHandler) ListFrameworks(c fiber.Ctx) error {
+	catalogs := h.evidenceService.ListCatalogs()
+
+	return c.JSON(fiber.Map{
+		"frameworks": catalogs,
+		"total":      len(catalogs),
+	})
+}
+
+// GetEvidencePack generates a framework evidence pack
+// @Summary Generate compliance evidence pack
+// @Description Evaluate a framework's controls against AIM data. format=json returns the pack;
+// @Description format=zip returns pack.json, CSV artifacts and an Ed25519 signature.json over pack.json.
+// @Tags compliance
+// @Produce json,application/zip
+// @Param framework path string true "Framework (soc2, iso27001, eu_ai_act)"
+// @Param format query string false "Output format (json or zip)" default(json)
+// @Param start_date query string false "Period start (RFC3339, default 90 days before end)"
+// @Param end_date query string false "Period end (RFC3339, default now)"
+// @Success 200 {object} application.EvidencePack
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/compliance/evidence-packs/{framework} [get]
+func (h *ComplianceHandler) GetEvidencePack(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+	framework := c.Params("framework")
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "zip" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid format. Supported formats: json, zip",
+		})
+	}
+
+	var startDate, endDate time.Time
+	for param, target := range map[string]*time.Time{
+		"start_date": &startDate,
+		"end_date":   &endDate,
+	} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid " + param + ", expected RFC3339",
+				})
+			}
+			*target = parsed
+		}
+	}
+
+	pack, err := h.evidenceService.GenerateEvidencePack(c.Context(), orgID, userID, framework, startDate, endDate)
+	if err != nil {
+		switch {
+		case strings.HasPrefix(err.Error(), "unknown compliance framework"):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case strings.HasPrefix(err.Error(), "invalid period"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate evidence pack",
+		})
+	}
+
+	var archive bytes.Buffer
+	if format == "zip" {
+		if err := h.evidenceService.WriteZip(pack, &archive); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to build evidence pack archive",
+			})
+		}
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionExport,
+		"evidence_pack",
+		pack.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"framework":    pack.Framework,
+			"format":       format,
+			"period_start": pack.PeriodStart,
+			"period_end":   pack.PeriodEnd,
+			"passed":       pack.Summary.Passed,
+			"failed":       pack.Summary.Failed,
+			"manual":       pack.Summary.Manual,
+		},
+	)
+
+	if format == "zip" {
+		c.Set("Content-Type", "application/zip")
+		c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=evidence-%s-%s.zip", pack.Framework, pack.GeneratedAt.Format("20060102")))
+		return c.Send(archive.Bytes())
+	}
 
-	// Simple CSV export - just return status and metrics as JSON representation
-	return c.SendString("Compliance Report Export\nPlease use JSON format for full report details.")
+	return c.JSON(pack)
 }
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// CredentialHandler serves agent DIDs and issues, revokes and verifies agent verifiable credentials
+type CredentialHandler struct {
+	credentialService *application.CredentialService
+	auditService      *application.AuditService
+}
+
+// NewCredentialHandler creates a new credential handler
+func NewCredentialHandler(
+	credentialService *application.CredentialService,
+	auditService *application.AuditService,
+) *CredentialHandler {
+	return &CredentialHandler{
+		credentialService: credentialService,
+		auditService:      auditService,
+	}
+}
+
+// RevokeCredentialRequest is a request to revoke an agent credential
+type RevokeCredentialRequest struct {
+	Reason string `json:"reason"`
+}
+
+// VerifyCredentialRequest is a request to verify an agent credential
+type VerifyCredentialRequest struct {
+	Credential string `json:"credential"` // VC-JWT
+}
+
+// GetAgentDIDs returns an agent's DIDs
+// @Summary Get agent DIDs
+// @Description Returns the agent's did:web (hosted by AIM), its did:key (derived from its Ed25519 key, when it has one) and its DID document
+// @Tags agents
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} application.AgentDIDs
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/did [get]
+func (h *CredentialHandler) GetAgentDIDs(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	dids, err := h.credentialService.GetAgentDIDs(c.Context(), orgID, agentID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(dids)
+}
+
+// IssueCredential issues a verifiable credential to an agent
+// @Summary Issue agent credential
+// @Description Issues a W3C Verifiable Credential (VC-JWT) asserting the agent's verification status, owner organization and granted capabilities. The credential is returned only once
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Param request body application.IssueCredentialRequest false "DID method and validity"
+// @Success 201 {object} application.IssuedCredential
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/verifiable-credentials [post]
+func (h *CredentialHandler) IssueCredential(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	var req application.IssueCredentialRequest
+	if len(c.Body()) > 0 {
+		if err := c.Bind().JSON(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	issued, err := h.credentialService.Issue(c.Context(), orgID, agentID, userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrInvalidCredentialRequest):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrCredentialUnavailable):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		case err.Error() == "agent not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to issue credential",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionCreate,
+		"agent_credential",
+		agentID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"credential_id": issued.Credential.ID,
+			"subject":       issued.Credential.SubjectDID,
+			"expires_at":    issued.Credential.ExpiresAt,
+		},
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(issued)
+}
+
+// ListCredentials lists the credentials issued to an agent
+// @Summary List agent credentials
+// @Tags agents
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/verifiable-credentials [get]
+func (h *CredentialHandler) ListCredentials(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	credentials, err := h.credentialService.ListCredentials(c.Context(), orgID, agentID)
+	if err != nil {
+		if err.Error() == "agent not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list credentials",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"credentials": credentials,
+		"total":       len(credentials),
+	})
+}
+
+// RevokeCredential revokes an agent credential
+// @Summary Revoke agent credential
+// @Description Marks the credential as revoked in its StatusList2021 list, which verifiers check offline
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Param credentialId path string true "Credential ID"
+// @Param request body RevokeCredentialRequest false "Revocation reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/verifiable-credentials/{credentialId} [delete]
+func (h *CredentialHandler) RevokeCredential(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+	credentialID, err := uuid.Parse(c.Params("credentialId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid credential ID",
+		})
+	}
+
+	var req RevokeCredentialRequest
+	if len(c.Body()) > 0 {
+		if err := c.Bind().JSON(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	if err := h.credentialService.Revoke(c.Context(), orgID, agentID, credentialID, req.Reason); err != nil {
+		if errors.Is(err, application.ErrCredentialNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to revoke credential",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionRevoke,
+		"agent_credential",
+		agentID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"credential_id": credentialID,
+			"reason":        req.Reason,
+		},
+	)
+
+	return c.JSON(fiber.Map{
+		"message": "Credential revoked",
+	})
+}
+
+// IssuerDIDDocument serves AIM's DID document
+// @Summary AIM DID document
+// @Description did:web document of AIM, listing the keys agent credentials are signed with
+// @Tags credentials
+// @Produce json
+// @Success 200 {object} domain.DIDDocument
+// @Router /.well-known/did.json [get]
+func (h *CredentialHandler) IssuerDIDDocument(c fiber.Ctx) error {
+	c.Set("Cache-Control", "public, max-age=300")
+	return c.JSON(h.credentialService.IssuerDIDDocument())
+}
+
+// AgentDIDDocument serves an agent's did:web document
+// @Summary Agent DID document
+// @Tags credentials
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} domain.DIDDocument
+// @Failure 404 {object} ErrorResponse
+// @Router /agents/{id}/did.json [get]
+func (h *CredentialHandler) AgentDIDDocument(c fiber.Ctx) error {
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "DID not found"})
+	}
+
+	document, err := h.credentialService.AgentDIDDocument(c.Context(), agentID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "DID not found"})
+	}
+
+	c.Set("Cache-Control", "public, max-age=300")
+	return c.JSON(document)
+}
+
+// StatusList serves a signed StatusList2021 credential
+// @Summary Credential status list
+// @Description StatusList2021Credential (VC-JWT) listing revoked agent credentials. Lists are valid for an hour
+// @Tags credentials
+// @Produce plain
+// @Param id path string true "Status list ID"
+// @Success 200 {string} string "VC-JWT"
+// @Failure 404 {object} ErrorResponse
+// @Router /credentials/status/{id} [get]
+func (h *CredentialHandler) StatusList(c fiber.Ctx) error {
+	listID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Status list not found"})
+	}
+
+	list, err := h.credentialService.StatusListCredential(c.Context(), listID)
+	if err != nil {
+		if errors.Is(err, application.ErrStatusListNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Status list not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to sign status list",
+		})
+	}
+
+	c.Set("Content-Type", "application/vc+jwt")
+	c.Set("Cache-Control", "public, max-age=300")
+	return c.SendString(list)
+}
+
+// VerifyCredential verifies an agent credential
+// @Summary Verify agent credential
+// @Description Checks the credential's signature against AIM's DID document, its validity period and its revocation status. Problems are listed in errors; valid is true only if there are none
+// @Tags credentials
+// @Accept json
+// @Produce json
+// @Param request body VerifyCredentialRequest true "Credential to verify"
+// @Success 200 {object} domain.CredentialVerification
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/public/credentials/verify [post]
+func (h *CredentialHandler) VerifyCredential(c fiber.Ctx) error {
+	var req VerifyCredentialRequest
+	if err := c.Bind().JSON(&req); err != nil || req.Credential == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "credential is required",
+		})
+	}
+
+	result, err := h.credentialService.Verify(c.Context(), req.Credential)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to verify credential",
+		})
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// DataRetentionHandler exposes retention rules, legal holds and manual purges to admins
+type DataRetentionHandler struct {
+	retentionService *application.DataRetentionService
+	auditService     *application.AuditService
+}
+
+func NewDataRetentionHandler(
+	retentionService *application.DataRetentionService,
+	auditService *application.AuditService,
+) *DataRetentionHandler {
+	return &DataRetentionHandler{
+		retentionService: retentionService,
+		auditService:     auditService,
+	}
+}
+
+// dataRetentionError maps service errors to HTTP responses
+func dataRetentionError(c fiber.Ctx, err error, fallback string) error {
+	msg := err.Error()
+	switch {
+	case msg == "legal hold not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
+	case msg == "legal hold already released", msg == "legal hold not found or already released":
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": msg})
+	case strings.HasPrefix(msg, "invalid retention"), strings.HasPrefix(msg, "invalid legal hold"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fallback})
+}
+
+// GetRetentionPolicies returns the effective retention of every table
+// @Summary Get data retention policies
+// @Description Effective retention period, legal hold state and current size of every purged table (Admin only)
+// @Tags compliance
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/compliance/retention [get]
+func (h *DataRetentionHandler) GetRetentionPolicies(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	policies, err := h.retentionService.GetPolicies(c.Context(), orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch retention policies",
+		})
+	}
+
+	holds, err := h.retentionService.ListLegalHolds(c.Context(), orgID, false)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch legal holds",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"policies":    policies,
+		"legal_holds": holds,
+	})
+}
+
+// SetRetentionRule overrides the retention period of a table
+// @Summary Set data retention rule
+// @Description Override how long a table's records are kept; disabled rules keep records indefinitely (Admin only)
+// @Tags compliance
+// @Accept json
+// @Produce json
+// @Param table path string true "Table (audit_logs, verification_events, alerts, detections, webhook_deliveries)"
+// @Success 200 {object} domain.RetentionRule
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/compliance/retention/{table} [put]
+func (h *DataRetentionHandler) SetRetentionRule(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+	table := domain.RetentionTable(c.Params("table"))
+
+	var req struct {
+		RetentionDays int   `json:"retention_days"`
+		Enabled       *bool `json:"enabled"`
+	}
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	enabled := req.Enabled == nil || *req.Enabled
+
+	rule, err := h.retentionService.SetRule(c.Context(), orgID, userID, table, req.RetentionDays, enabled)
+	if err != nil {
+		return dataRetentionError(c, err, "Failed to save retention rule")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionUpdate,
+		"data_retention_rule",
+		orgID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"table":          table,
+			"retention_days": rule.RetentionDays,
+			"enabled":        rule.Enabled,
+		},
+	)
+
+	return c.JSON(rule)
+}
+
+// DeleteRetentionRule reverts a table to the default retention period
+// @Summary Reset data retention rule
+// @Description Revert a table to its default retention period (Admin only)
+// @Tags compliance
+// @Param table path string true "Table"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/compliance/retention/{table} [delete]
+func (h *DataRetentionHandler) DeleteRetentionRule(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+	table := domain.RetentionTable(c.Params("table"))
+
+	if err := h.retentionService.DeleteRule(c.Context(), orgID, table); err != nil {
+		return dataRetentionError(c, err, "Failed to reset retention rule")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionDelete,
+		"data_retention_rule",
+		orgID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"table":          table,
+			"retention_days": domain.DefaultRetentionDays[table],
+		},
+	)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListLegalHolds lists legal holds
+// @Summary List legal holds
+// @Description List the organization's legal holds, newest first (Admin only)
+// @Tags compliance
+// @Produce json
+// @Param include_released query bool false "Include released holds"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/compliance/legal-holds [get]
+func (h *DataRetentionHandler) ListLegalHolds(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	holds, err := h.retentionService.ListLegalHolds(c.Context(), orgID, c.Query("include_released") == "true")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list legal holds",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"legal_holds": holds,
+	})
+}
+
+// CreateLegalHold places a legal hold
+// @Summary Create legal hold
+// @Description Suspend purging of one table, or of every table when table is omitted, until the hold is released (Admin only)
+// @Tags compliance
+// @Accept json
+// @Produce json
+// @Success 201 {object} domain.LegalHold
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/compliance/legal-holds [post]
+func (h *DataRetentionHandler) CreateLegalHold(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	var req struct {
+		Table  *domain.RetentionTable `json:"table"`
+		Reason string                 `json:"reason"`
+	}
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	hold, err := h.retentionService.CreateLegalHold(c.Context(), orgID, userID, req.Table, req.Reason)
+	if err != nil {
+		return dataRetentionError(c, err, "Failed to create legal hold")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionCreate,
+		"legal_hold",
+		hold.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"table":  hold.Table,
+			"reason": hold.Reason,
+		},
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(hold)
+}
+
+// ReleaseLegalHold lifts a legal hold
+// @Summary Release legal hold
+// @Description Lift a legal hold; purging resumes with the next run (Admin only)
+// @Tags compliance
+// @Produce json
+// @Param id path string true "Legal hold ID"
+// @Success 200 {object} domain.LegalHold
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/compliance/legal-holds/{id}/release [post]
+func (h *DataRetentionHandler) ReleaseLegalHold(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	holdID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid legal hold ID",
+		})
+	}
+
+	hold, err := h.retentionService.ReleaseLegalHold(c.Context(), orgID, holdID, userID)
+	if err != nil {
+		return dataRetentionError(c, err, "Failed to release legal hold")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionUpdate,
+		"legal_hold",
+		hold.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"released": true,
+			"table":    hold.Table,
+		},
+	)
+
+	return c.JSON(hold)
+}
+
+// RunPurge purges the organization's expired records now
+// @Summary Run data retention purge
+// @Description Delete records past their retention period now instead of waiting for the nightly job. The run is recorded in the audit log (Admin only)
+// @Tags compliance
+// @Produce json
+// @Success 200 {object} domain.RetentionPurgeRun
+// @Router /api/v1/compliance/retention/purge [post]
+func (h *DataRetentionHandler) RunPurge(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	// The purge records its own audit entry with row counts
+	run, err := h.retentionService.PurgeOrganization(c.Context(), orgID, &userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to run data retention purge",
+		})
+	}
+
+	return c.JSON(run)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// maxDataTransfersPerReport bounds the batch an SDK may report in one request
+const maxDataTransfersPerReport = 500
+
+// DataTransferHandler accepts data transfer reports from SDKs and exposes per-agent data volume
+type DataTransferHandler struct {
+	dataExfiltrationService *application.DataExfiltrationService
+	agentService            *application.AgentService
+}
+
+func NewDataTransferHandler(
+	dataExfiltrationService *application.DataExfiltrationService,
+	agentService *application.AgentService,
+) *DataTransferHandler {
+	return &DataTransferHandler{
+		dataExfiltrationService: dataExfiltrationService,
+		agentService:            agentService,
+	}
+}
+
+// DataTransferReport is one transfer observed by an SDK around an MCP tool call
+type DataTransferReport struct {
+	Resource      string     `json:"resource"`
+	Destination   string     `json:"destination,omitempty"`
+	MCPServer     string     `json:"mcp_server,omitempty"`
+	BytesRead     int64      `json:"bytes_read"`
+	BytesExported int64      `json:"bytes_exported"`
+	OccurredAt    *time.Time `json:"occurred_at,omitempty"`
+}
+
+// ReportDataTransfers records bytes read and exported by an agent's MCP calls
+// @Summary Report data transfers
+// @Description SDKs report the bytes each MCP tool call read or exported, per resource and destination. The response is the agent's data volume in the window of its data_exfiltration policy.
+// @Tags sdk
+// @Accept json
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} domain.DataTransferUsage
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/sdk-api/agents/{id}/data-transfers [post]
+func (h *DataTransferHandler) ReportDataTransfers(c fiber.Ctx) error {
+	agent, err := organizationAgent(c, h.agentService)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		Transfers []DataTransferReport `json:"transfers"`
+	}
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if len(req.Transfers) == 0 || len(req.Transfers) > maxDataTransfersPerReport {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "transfers must contain between 1 and 500 entries",
+		})
+	}
+
+	for _, report := range req.Transfers {
+		if report.BytesRead < 0 || report.BytesExported < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "bytes_read and bytes_exported must not be negative",
+			})
+		}
+	}
+
+	var usage *domain.DataTransferUsage
+	for _, report := range req.Transfers {
+		transfer := &domain.DataTransfer{
+			Source:        domain.DataTransferSourceMCPCall,
+			Resource:      report.Resource,
+			Destination:   report.Destination,
+			MCPServer:     report.MCPServer,
+			BytesRead:     report.BytesRead,
+			BytesExported: report.BytesExported,
+		}
+		if report.OccurredAt != nil {
+			transfer.OccurredAt = report.OccurredAt.UTC()
+		}
+
+		usage, err = h.dataExfiltrationService.RecordTransfer(c.Context(), agent, transfer)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to record data transfers",
+			})
+		}
+	}
+
+	return c.JSON(usage)
+}
+
+// GetAgentDataUsage returns an agent's data volume in its policy window
+// @Summary Get agent data usage
+// @Description Bytes read and exported by an agent within the window of its data_exfiltration policy, top destinations, and whether data exports are currently blocked
+// @Tags security
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} domain.DataTransferUsage
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/security/agents/{id}/data-usage [get]
+func (h *DataTransferHandler) GetAgentDataUsage(c fiber.Ctx) error {
+	agent, err := organizationAgent(c, h.agentService)
+	if err != nil {
+		return err
+	}
+
+	usage, err := h.dataExfiltrationService.GetUsage(c.Context(), agent)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch data usage",
+		})
+	}
+
+	return c.JSON(usage)
+}
@@ -59,7 +59,7 @@ func (h *DetectionHandler) ReportDetection(c fiber.Ctx) error {
 	authMethod := c.Locals("auth_method")
 	var userID uuid.UUID
 
-	if authMethod == "api_key" || authMethod == "ed25519" {
+	if authMethod == "api_key" || authMethod == "ed25519" || authMethod == "spiffe" {
 		// For API key or Ed25519 auth, use a nil UUID to indicate system/agent action
 		userID = uuid.Nil
 	} else {
@@ -193,7 +193,7 @@ func (h *DetectionHandler) ReportCapabilities(c fiber.Ctx) error {
 	authMethod := c.Locals("auth_method")
 	var userID uuid.UUID
 
-	if authMethod == "api_key" || authMethod == "ed25519" {
+	if authMethod == "api_key" || authMethod == "ed25519" || authMethod == "spiffe" {
 		// For API key or Ed25519 auth, use a nil UUID to indicate system/agent action
 		userID = uuid.Nil
 	} else {
@@ -213,3 +213,146 @@ func (h *MCPAttestationHandler) GetAgentMCPServers(c fiber.Ctx) error {
 		"total":       len(mcpServers),
 	})
 }
+
+// RevokeAttestationRequest is the body for attestation revocation endpoints
+type RevokeAttestationRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RevokeAttestation revokes a single attestation of an MCP server
+// @Summary Revoke MCP attestation
+// @Description Revoke one agent attestation and recompute the MCP server's confidence score (Admin only)
+// @Tags mcp-servers
+// @Accept json
+// @Produce json
+// @Param id path string true "MCP Server ID"
+// @Param attestationId path string true "Attestation ID"
+// @Param request body RevokeAttestationRequest true "Revocation reason"
+// @Success 200 {object} application.AttestationRevocationResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/mcp-servers/{id}/attestations/{attestationId}/revoke [post]
+func (h *MCPAttestationHandler) RevokeAttestation(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	mcpServerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid MCP server ID",
+		})
+	}
+
+	attestationID, err := uuid.Parse(c.Params("attestationId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid attestation ID",
+		})
+	}
+
+	var req RevokeAttestationRequest
+	if err := c.Bind().JSON(&req); err != nil || req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "A revocation reason is required",
+		})
+	}
+
+	result, err := h.attestationService.RevokeAttestation(c.Context(), orgID, mcpServerID, attestationID, userID, req.Reason)
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		switch err.Error() {
+		case "mcp server not found", "attestation not found":
+			statusCode = fiber.StatusNotFound
+		case "attestation already revoked":
+			statusCode = fiber.StatusConflict
+		}
+		return c.Status(statusCode).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionRevoke,
+		"mcp_attestation",
+		attestationID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"mcp_server_id":    mcpServerID.String(),
+			"reason":           req.Reason,
+			"confidence_score": result.MCPServers[0].ConfidenceScore,
+		},
+	)
+
+	return c.JSON(result)
+}
+
+// RevokeAgentAttestations revokes every attestation made by an agent
+// @Summary Revoke all attestations by an agent
+// @Description Revoke every attestation made by a compromised agent and recompute affected MCP confidence scores (Admin only)
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Param request body RevokeAttestationRequest true "Revocation reason"
+// @Success 200 {object} application.AttestationRevocationResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/agents/{id}/attestations/revoke [post]
+func (h *MCPAttestationHandler) RevokeAgentAttestations(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	var req RevokeAttestationRequest
+	if err := c.Bind().JSON(&req); err != nil || req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "A revocation reason is required",
+		})
+	}
+
+	result, err := h.attestationService.RevokeAgentAttestations(c.Context(), orgID, agentID, userID, req.Reason)
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		if err.Error() == "agent not found" {
+			statusCode = fiber.StatusNotFound
+		}
+		return c.Status(statusCode).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	affected := make([]string, 0, len(result.MCPServers))
+	for _, update := range result.MCPServers {
+		affected = append(affected, update.MCPServerID.String())
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionRevoke,
+		"agent",
+		agentID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"action":               "revoke_attestations",
+			"reason":               req.Reason,
+			"revoked_count":        result.RevokedCount,
+			"affected_mcp_servers": affected,
+		},
+	)
+
+	return c.JSON(result)
+}
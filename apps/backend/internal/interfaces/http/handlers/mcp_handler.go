@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
@@ -59,15 +61,24 @@ func (h *MCPHandler) CreateMCPServer(c fiber.Ctx) error {
 
 	server, err := h.mcpService.CreateMCPServer(c.Context(), &req, orgID, userID)
 	if err != nil {
+		var quotaErr *application.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceededResponse(c, quotaErr)
+		}
 		// Log the actual error for debugging
 		fmt.Printf("❌ Error creating MCP server: %v\n", err)
 
-		// Return 409 Conflict for duplicate URL errors
-		if err.Error() == "mcp server with this URL already exists" {
+		// Return 409 Conflict for duplicate URL or launch spec errors
+		if err.Error() == "mcp server with this URL already exists" || err.Error() == "mcp server with this identity already exists" {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 				"error": err.Error(),
 			})
 		}
+		if strings.HasPrefix(err.Error(), "invalid mcp server") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -85,8 +96,10 @@ func (h *MCPHandler) CreateMCPServer(c fiber.Ctx) error {
 		c.IP(),
 		c.Get("User-Agent"),
 		map[string]interface{}{
-			"server_name": server.Name,
-			"server_url":  server.URL,
+			"server_name":   server.Name,
+			"server_url":    server.URL,
+			"transport":     server.Transport,
+			"identity_hash": server.IdentityHash,
 		},
 	)
 
@@ -197,6 +210,11 @@ func (h *MCPHandler) UpdateMCPServer(c fiber.Ctx) error {
 
 	server, err := h.mcpService.UpdateMCPServer(c.Context(), serverID, &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid mcp server") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -306,6 +324,11 @@ func (h *MCPHandler) VerifyMCPServer(c fiber.Ctx) error {
 			"error": "Access denied",
 		})
 	}
+	if !server.Transport.IsRemote() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "stdio MCP servers have no URL to challenge; they are identified by their launch spec and cannot be verified over the network",
+		})
+	}
 
 	// Generate verification challenge
 	challenge, err := h.mcpService.GenerateVerificationChallenge(c.Context(), serverID)
@@ -504,10 +527,11 @@ func (h *MCPHandler) GetMCPServerCapabilities(c fiber.Ctx) error {
 		})
 	}
 
-	// Return detailed capabilities with full metadata
+	// Return detailed capabilities with full metadata and metadata scanner results
 	return c.JSON(fiber.Map{
-		"capabilities": capabilities,
-		"total":        len(capabilities),
+		"capabilities":     capabilities,
+		"total":            len(capabilities),
+		"findings_summary": application.SummarizeMetadataFindings(capabilities),
 	})
 }
 
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// MCPHealthHandler exposes scheduled MCP server re-verification results
+type MCPHealthHandler struct {
+	mcpService           *application.MCPService
+	healthMonitorService *application.MCPHealthMonitorService
+	auditService         *application.AuditService
+}
+
+func NewMCPHealthHandler(
+	mcpService *application.MCPService,
+	healthMonitorService *application.MCPHealthMonitorService,
+	auditService *application.AuditService,
+) *MCPHealthHandler {
+	return &MCPHealthHandler{
+		mcpService:           mcpService,
+		healthMonitorService: healthMonitorService,
+		auditService:         auditService,
+	}
+}
+
+// GetMCPServerHealth returns the health state, recent checks and uptime of an MCP server
+// @Summary Get MCP server health
+// @Description Get the health status, recent scheduled re-verification checks and 24h/7d/30d uptime of an MCP server
+// @Tags mcp-servers
+// @Produce json
+// @Param id path string true "MCP Server ID"
+// @Param limit query int false "Number of checks to return" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/mcp-servers/{id}/health [get]
+func (h *MCPHealthHandler) GetMCPServerHealth(c fiber.Ctx) error {
+	server, err := loadOrganizationMCPServer(c, h.mcpService)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return nil
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	health, checks, uptime, err := h.healthMonitorService.GetHealth(c.Context(), server.ID, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch MCP server health",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"health":           health,
+		"checks":           checks,
+		"uptime":           uptime,
+		"server_status":    server.Status,
+		"last_verified_at": server.LastVerifiedAt,
+	})
+}
+
+// CheckMCPServerHealth runs a re-verification of an MCP server immediately
+// @Summary Run MCP server health check
+// @Description Probe and re-challenge an MCP server now instead of waiting for the scheduler (Manager+)
+// @Tags mcp-servers
+// @Produce json
+// @Param id path string true "MCP Server ID"
+// @Success 200 {object} application.MCPHealthCheckResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/mcp-servers/{id}/health/check [post]
+func (h *MCPHealthHandler) CheckMCPServerHealth(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	server, err := loadOrganizationMCPServer(c, h.mcpService)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return nil
+	}
+
+	result, err := h.healthMonitorService.CheckServer(c.Context(), server.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionCheck,
+		"mcp_server",
+		server.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"action":     "health_check",
+			"success":    result.Check.Success,
+			"downgraded": result.Downgraded,
+			"restored":   result.Restored,
+		},
+	)
+
+	return c.JSON(result)
+}
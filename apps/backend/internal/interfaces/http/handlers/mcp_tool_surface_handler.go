@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// MCPToolSurfaceHandler exposes MCP tool-surface snapshots and the admin review workflow
+type MCPToolSurfaceHandler struct {
+	mcpService         *application.MCPService
+	toolSurfaceService *application.MCPToolSurfaceService
+	auditService       *application.AuditService
+}
+
+func NewMCPToolSurfaceHandler(
+	mcpService *application.MCPService,
+	toolSurfaceService *application.MCPToolSurfaceService,
+	auditService *application.AuditService,
+) *MCPToolSurfaceHandler {
+	return &MCPToolSurfaceHandler{
+		mcpService:         mcpService,
+		toolSurfaceService: toolSurfaceService,
+		auditService:       auditService,
+	}
+}
+
+// ReviewToolSurfaceRequest is the body for approving or rejecting a pending tool surface
+type ReviewToolSurfaceRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ListToolSurfaceSnapshots returns the tool-surface snapshot history of an MCP server
+// @Summary List MCP tool-surface snapshots
+// @Description Get the versioned, hashed tool-surface history of an MCP server (newest first)
+// @Tags mcp-servers
+// @Produce json
+// @Param id path string true "MCP Server ID"
+// @Param limit query int false "Number of snapshots to return" default(20)
+// @Param offset query int false "Number of snapshots to skip" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/mcp-servers/{id}/tool-surface [get]
+func (h *MCPToolSurfaceHandler) ListToolSurfaceSnapshots(c fiber.Ctx) error {
+	server, err := h.authorizeServer(c)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return nil
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	snapshots, err := h.toolSurfaceService.ListSnapshots(c.Context(), server.ID, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch tool surface snapshots",
+		})
+	}
+
+	pendingReview := len(snapshots) > 0 && snapshots[0].Status == domain.MCPToolSurfaceStatusPending
+
+	return c.JSON(fiber.Map{
+		"snapshots":      snapshots,
+		"pending_review": pendingReview,
+		"server_status":  server.Status,
+		"limit":          limit,
+		"offset":         offset,
+	})
+}
+
+// ApproveToolSurface approves the pending tool surface as the new baseline
+// @Summary Approve MCP tool-surface change
+// @Description Approve the newest pending tool-surface snapshot and lift a drift suspension (Admin only)
+// @Tags mcp-servers
+// @Accept json
+// @Produce json
+// @Param id path string true "MCP Server ID"
+// @Param request body ReviewToolSurfaceRequest false "Review comment"
+// @Success 200 {object} domain.MCPToolSurfaceSnapshot
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/mcp-servers/{id}/tool-surface/approve [post]
+func (h *MCPToolSurfaceHandler) ApproveToolSurface(c fiber.Ctx) error {
+	return h.reviewToolSurface(c, true)
+}
+
+// RejectToolSurface rejects the pending tool surface and suspends the server until it reverts
+// to the approved one
+// @Summary Reject MCP tool-surface change
+// @Description Reject the newest pending tool-surface snapshot and suspend the server until it serves the approved tool surface again (Admin only)
+// @Tags mcp-servers
+// @Accept json
+// @Produce json
+// @Param id path string true "MCP Server ID"
+// @Param request body ReviewToolSurfaceRequest false "Review comment"
+// @Success 200 {object} domain.MCPToolSurfaceSnapshot
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/mcp-servers/{id}/tool-surface/reject [post]
+func (h *MCPToolSurfaceHandler) RejectToolSurface(c fiber.Ctx) error {
+	return h.reviewToolSurface(c, false)
+}
+
+func (h *MCPToolSurfaceHandler) reviewToolSurface(c fiber.Ctx, approve bool) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	server, err := h.authorizeServer(c)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return nil
+	}
+
+	var req ReviewToolSurfaceRequest
+	if len(c.Body()) > 0 {
+		if err := c.Bind().JSON(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	var snapshot *domain.MCPToolSurfaceSnapshot
+	action := "approve_tool_surface"
+	if approve {
+		snapshot, err = h.toolSurfaceService.ApproveLatest(c.Context(), server.ID, userID, req.Comment)
+	} else {
+		action = "reject_tool_surface"
+		snapshot, err = h.toolSurfaceService.RejectLatest(c.Context(), server.ID, userID, req.Comment)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionUpdate,
+		"mcp_server",
+		server.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"action":       action,
+			"version":      snapshot.Version,
+			"surface_hash": snapshot.SurfaceHash,
+			"comment":      req.Comment,
+		},
+	)
+
+	return c.JSON(snapshot)
+}
+
+// authorizeServer loads the MCP server from the :id param and checks organization ownership.
+// It returns (nil, nil) after writing an error response.
+func (h *MCPToolSurfaceHandler) authorizeServer(c fiber.Ctx) (*domain.MCPServer, error) {
+	return loadOrganizationMCPServer(c, h.mcpService)
+}
+
+// loadOrganizationMCPServer loads the MCP server from the :id param and checks that it belongs
+// to the caller's organization. It returns (nil, nil) after writing an error response.
+func loadOrganizationMCPServer(c fiber.Ctx, mcpService *application.MCPService) (*domain.MCPServer, error) {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	serverID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid MCP server ID",
+		})
+	}
+
+	server, err := mcpService.GetMCPServer(c.Context(), serverID)
+	if err != nil {
+		return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "MCP server not found",
+		})
+	}
+	if server.OrganizationID != orgID {
+		return nil, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied",
+		})
+	}
+
+	return server, nil
+}
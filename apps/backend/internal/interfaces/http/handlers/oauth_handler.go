@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// OAuthHandler serves the OAuth 2.0 authorization server that issues access tokens to agents
+type OAuthHandler struct {
+	tokenService *application.AgentTokenService
+	auditService *application.AuditService
+}
+
+func NewOAuthHandler(
+	tokenService *application.AgentTokenService,
+	auditService *application.AuditService,
+) *OAuthHandler {
+	return &OAuthHandler{
+		tokenService: tokenService,
+		auditService: auditService,
+	}
+}
+
+// oauthErrorResponse writes an RFC 6749 error response
+func oauthErrorResponse(c fiber.Ctx, err error) error {
+	var oauthErr *application.OAuthError
+	if !errors.As(err, &oauthErr) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":             "server_error",
+			"error_description": "Failed to process token request",
+		})
+	}
+
+	status := fiber.StatusBadRequest
+	if oauthErr.Code == application.OAuthErrorInvalidClient {
+		status = fiber.StatusUnauthorized
+		c.Set("WWW-Authenticate", `Bearer error="invalid_client"`)
+	}
+	return c.Status(status).JSON(oauthErr)
+}
+
+// authenticateClient authenticates the calling agent from the client assertion form parameters
+func (h *OAuthHandler) authenticateClient(c fiber.Ctx) (*domain.Agent, error) {
+	return h.tokenService.AuthenticateClient(
+		c.Context(),
+		c.FormValue("client_id"),
+		c.FormValue("client_assertion_type"),
+		c.FormValue("client_assertion"),
+	)
+}
+
+// Token issues an access token to an agent
+// @Summary OAuth 2.0 token endpoint
+// @Description Issue a short-lived access token to an agent. Agents authenticate with private_key_jwt: a client assertion signed (EdDSA) with their registered Ed25519 key whose iss and sub are the agent ID and whose audience is this endpoint. Supports the client_credentials grant and token exchange (RFC 8693) to act on behalf of a user. The token carries the agent ID, organization, granted capabilities and trust score
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "client_credentials or urn:ietf:params:oauth:grant-type:token-exchange"
+// @Param client_id formData string false "Agent ID"
+// @Param client_assertion_type formData string true "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+// @Param client_assertion formData string true "Signed client assertion"
+// @Param scope formData string false "Space-separated capabilities; defaults to all active capabilities"
+// @Param audience formData string false "Intended audience of the token"
+// @Param subject_token formData string false "Token exchange: user access token"
+// @Param subject_token_type formData string false "Token exchange: urn:ietf:params:oauth:token-type:access_token"
+// @Success 200 {object} application.AgentTokenResponse
+// @Failure 400 {object} application.OAuthError
+// @Failure 401 {object} application.OAuthError
+// @Router /api/v1/oauth/token [post]
+func (h *OAuthHandler) Token(c fiber.Ctx) error {
+	audience := c.FormValue("audience")
+	if audience == "" {
+		audience = c.FormValue("resource")
+	}
+
+	resp, err := h.tokenService.IssueToken(c.Context(), application.AgentTokenRequest{
+		GrantType:           c.FormValue("grant_type"),
+		ClientID:            c.FormValue("client_id"),
+		ClientAssertionType: c.FormValue("client_assertion_type"),
+		ClientAssertion:     c.FormValue("client_assertion"),
+		Scope:               c.FormValue("scope"),
+		Audience:            audience,
+		SubjectToken:        c.FormValue("subject_token"),
+		SubjectTokenType:    c.FormValue("subject_token_type"),
+	})
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	c.Set("Cache-Control", "no-store")
+	return c.JSON(resp)
+}
+
+// Introspect reports whether an access token is active
+// @Summary OAuth 2.0 token introspection
+// @Description Introspect an agent access token (RFC 7662). The caller authenticates like at the token endpoint; tokens of other organizations are reported inactive
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Access token"
+// @Param client_assertion_type formData string true "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+// @Param client_assertion formData string true "Signed client assertion"
+// @Success 200 {object} domain.TokenIntrospection
+// @Failure 401 {object} application.OAuthError
+// @Router /api/v1/oauth/introspect [post]
+func (h *OAuthHandler) Introspect(c fiber.Ctx) error {
+	caller, err := h.authenticateClient(c)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	return c.JSON(h.tokenService.Introspect(c.Context(), caller, c.FormValue("token")))
+}
+
+// Revoke revokes one of the calling agent's access tokens
+// @Summary OAuth 2.0 token revocation
+// @Description Revoke an access token (RFC 7009). Agents may revoke only their own tokens; the response is the same for unknown tokens
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Param token formData string true "Access token"
+// @Param client_assertion_type formData string true "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+// @Param client_assertion formData string true "Signed client assertion"
+// @Success 200
+// @Failure 401 {object} application.OAuthError
+// @Router /api/v1/oauth/revoke [post]
+func (h *OAuthHandler) Revoke(c fiber.Ctx) error {
+	caller, err := h.authenticateClient(c)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	if err := h.tokenService.Revoke(c.Context(), caller, c.FormValue("token")); err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// JWKS publishes the keys agent access tokens are signed with
+// @Summary Agent token signing keys
+// @Tags oauth
+// @Produce json
+// @Success 200 {object} domain.JSONWebKeySet
+// @Router /api/v1/oauth/jwks [get]
+func (h *OAuthHandler) JWKS(c fiber.Ctx) error {
+	c.Set("Cache-Control", "public, max-age=300")
+	return c.JSON(h.tokenService.JWKS())
+}
+
+// Metadata publishes the authorization server metadata (RFC 8414)
+// @Summary OAuth 2.0 authorization server metadata
+// @Tags oauth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /.well-known/oauth-authorization-server [get]
+func (h *OAuthHandler) Metadata(c fiber.Ctx) error {
+	issuer := h.tokenService.Issuer()
+	authMethods := []string{"private_key_jwt"}
+	signingAlgs := []string{"EdDSA"}
+
+	return c.JSON(fiber.Map{
+		"issuer":                                issuer,
+		"token_endpoint":                        h.tokenService.TokenEndpoint(),
+		"jwks_uri":                              issuer + "/api/v1/oauth/jwks",
+		"introspection_endpoint":                issuer + "/api/v1/oauth/introspect",
+		"revocation_endpoint":                   issuer + "/api/v1/oauth/revoke",
+		"grant_types_supported":                 []string{domain.GrantTypeClientCredentials, domain.GrantTypeTokenExchange},
+		"token_endpoint_auth_methods_supported": authMethods,
+		"token_endpoint_auth_signing_alg_values_supported":         signingAlgs,
+		"introspection_endpoint_auth_methods_supported":            authMethods,
+		"introspection_endpoint_auth_signing_alg_values_supported": signingAlgs,
+		"revocation_endpoint_auth_methods_supported":               authMethods,
+		"revocation_endpoint_auth_signing_alg_values_supported":    signingAlgs,
+		"response_types_supported":                                 []string{},
+	})
+}
+
+// RevokeAgentTokens revokes every active access token of an agent
+// @Summary Revoke agent access tokens
+// @Description Immediately revoke all active OAuth access tokens issued to an agent (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/agents/{id}/tokens/revoke [post]
+func (h *OAuthHandler) RevokeAgentTokens(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	revoked, err := h.tokenService.RevokeAgentTokens(c.Context(), orgID, agentID)
+	if err != nil {
+		if err.Error() == "agent not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to revoke agent access tokens",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionRevoke,
+		"agent_access_token",
+		agentID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"revoked_tokens": revoked,
+		},
+	)
+
+	return c.JSON(fiber.Map{
+		"agent_id":       agentID,
+		"revoked_tokens": revoked,
+	})
+}
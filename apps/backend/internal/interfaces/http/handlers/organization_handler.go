@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// OrganizationHandler manages child organizations (business units), moves users between
+// them and rolls analytics up across a subtree
+type OrganizationHandler struct {
+	hierarchyService *application.OrganizationHierarchyService
+	auditService     *application.AuditService
+}
+
+func NewOrganizationHandler(
+	hierarchyService *application.OrganizationHierarchyService,
+	auditService *application.AuditService,
+) *OrganizationHandler {
+	return &OrganizationHandler{
+		hierarchyService: hierarchyService,
+		auditService:     auditService,
+	}
+}
+
+// organizationError maps hierarchy service errors to HTTP responses
+func organizationError(c fiber.Ctx, err error, fallback string) error {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, application.ErrOutsideOrganizationScope):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": msg})
+	case msg == "organization not found", msg == "user not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
+	case strings.HasPrefix(msg, "organization domain already in use"):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": msg})
+	case strings.HasPrefix(msg, "invalid organization"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fallback})
+}
+
+// homeOrganizationID returns the caller's own organization, even when acting on a descendant
+func homeOrganizationID(c fiber.Ctx) uuid.UUID {
+	if orgID, ok := c.Locals("home_organization_id").(uuid.UUID); ok {
+		return orgID
+	}
+	return c.Locals("organization_id").(uuid.UUID)
+}
+
+// ListChildren returns the direct child organizations of the current organization
+// @Summary List child organizations
+// @Tags organizations
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/organizations/current/children [get]
+func (h *OrganizationHandler) ListChildren(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	children, err := h.hierarchyService.ListChildren(c.Context(), orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch child organizations",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"organizations": children,
+	})
+}
+
+// CreateChild creates a child organization (business unit) under the current organization
+// @Summary Create child organization
+// @Description Create a business unit with its own admins, agents and policies. It starts on the parent's plan, inherits the parent's inheritable security policies and is reachable by the parent's users through the X-Organization-ID header (Admin only)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param request body application.CreateChildOrganizationRequest true "Child organization"
+// @Success 201 {object} domain.Organization
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/organizations/current/children [post]
+func (h *OrganizationHandler) CreateChild(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	var req application.CreateChildOrganizationRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	child, err := h.hierarchyService.CreateChild(c.Context(), orgID, req)
+	if err != nil {
+		return organizationError(c, err, "Failed to create child organization")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionCreate,
+		"organization",
+		child.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"name":      child.Name,
+			"domain":    child.Domain,
+			"parent_id": orgID,
+		},
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(child)
+}
+
+// GetTree returns the current organization with all of its descendants
+// @Summary Get organization tree
+// @Tags organizations
+// @Produce json
+// @Success 200 {object} domain.OrganizationNode
+// @Router /api/v1/organizations/current/tree [get]
+func (h *OrganizationHandler) GetTree(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	tree, err := h.hierarchyService.GetTree(c.Context(), orgID)
+	if err != nil {
+		return organizationError(c, err, "Failed to fetch organization tree")
+	}
+
+	return c.JSON(tree)
+}
+
+// MoveUserRequest selects the organization a user moves into
+type MoveUserRequest struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+}
+
+// MoveUser moves a user into another organization of the admin's subtree
+// @Summary Move user to organization
+// @Description Move a user between organizations of the admin's subtree, e.g. to appoint a business unit admin. The user keeps their role (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body MoveUserRequest true "Target organization"
+// @Success 200 {object} domain.User
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/users/{id}/organization [put]
+func (h *OrganizationHandler) MoveUser(c fiber.Ctx) error {
+	adminID := c.Locals("user_id").(uuid.UUID)
+	targetUserID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var req MoveUserRequest
+	if err := c.Bind().JSON(&req); err != nil || req.OrganizationID == uuid.Nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "organization_id is required",
+		})
+	}
+
+	fromOrgID := c.Locals("organization_id").(uuid.UUID)
+	user, err := h.hierarchyService.MoveUser(c.Context(), homeOrganizationID(c), targetUserID, req.OrganizationID)
+	if err != nil {
+		return organizationError(c, err, "Failed to move user")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		fromOrgID,
+		adminID,
+		domain.AuditActionUpdate,
+		"user",
+		targetUserID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"moved_to_organization_id": req.OrganizationID,
+		},
+	)
+
+	return c.JSON(user)
+}
+
+// GetRollup rolls analytics up across the current organization and its descendants
+// @Summary Get rolled-up analytics
+// @Description Agent, MCP server, user, alert and verification counts of every organization in the subtree, with totals
+// @Tags analytics
+// @Produce json
+// @Param days query int false "Verification window in days" default(30)
+// @Success 200 {object} domain.OrganizationRollupReport
+// @Router /api/v1/analytics/rollup [get]
+func (h *OrganizationHandler) GetRollup(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	days, err := strconv.Atoi(c.Query("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		days = 30
+	}
+
+	report, err := h.hierarchyService.GetRollup(c.Context(), orgID, days)
+	if err != nil {
+		return organizationError(c, err, "Failed to fetch rolled-up analytics")
+	}
+
+	return c.JSON(report)
+}
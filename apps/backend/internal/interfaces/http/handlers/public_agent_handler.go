@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -119,6 +120,10 @@ func (h *PublicAgentHandler) Register(c fiber.Ctx) error {
 		DocumentationURL: req.DocumentationURL,
 	}, orgID, userID)
 	if err != nil {
+		var quotaErr *application.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceededResponse(c, quotaErr)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": fmt.Sprintf("Failed to create agent: %v", err),
 		})
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"encoding/base64"
 	"strconv"
 
@@ -166,6 +167,10 @@ func (h *PublicMCPHandler) RegisterMCPServer(c fiber.Ctx) error {
 
 	server, err := h.mcpService.CreateMCPServer(c.Context(), createReq, agent.OrganizationID, agentID)
 	if err != nil {
+		var quotaErr *application.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return quotaExceededResponse(c, quotaErr)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -16,6 +16,7 @@ type PublicRegistrationHandler struct {
 	registrationService *application.RegistrationService
 	authService         *application.AuthService
 	jwtService          *auth.JWTService
+	refreshTokenService *application.RefreshTokenService
 }
 
 // NewPublicRegistrationHandler creates a new public registration handler
@@ -23,11 +24,13 @@ func NewPublicRegistrationHandler(
 	registrationService *application.RegistrationService,
 	authService *application.AuthService,
 	jwtService *auth.JWTService,
+	refreshTokenService *application.RefreshTokenService,
 ) *PublicRegistrationHandler {
 	return &PublicRegistrationHandler{
 		registrationService: registrationService,
 		authService:         authService,
 		jwtService:          jwtService,
+		refreshTokenService: refreshTokenService,
 	}
 }
 
@@ -344,12 +347,7 @@ func (h *PublicRegistrationHandler) generateApprovedLoginResponse(c fiber.Ctx, u
 
 	// Generate tokens
 	fmt.Printf("🔍 DEBUG: Generating JWT for user %s (email: %s, role: '%s', role type: %T)\n", user.ID, user.Email, user.Role, user.Role)
-	accessToken, refreshToken, err := h.jwtService.GenerateTokenPair(
-		user.ID.String(),
-		user.OrganizationID.String(),
-		user.Email,
-		string(user.Role),
-	)
+	accessToken, refreshToken, err := h.refreshTokenService.StartSession(c.Context(), user, c.IP(), c.Get("User-Agent"))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"success": false,
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// QuotaHandler reports an organization's usage of its plan quotas
+type QuotaHandler struct {
+	quotaService *application.QuotaService
+}
+
+func NewQuotaHandler(quotaService *application.QuotaService) *QuotaHandler {
+	return &QuotaHandler{
+		quotaService: quotaService,
+	}
+}
+
+// quotaExceededResponse rejects a request that would exceed a plan quota. Running out of the
+// metered monthly verifications is 402 Payment Required; being at an agent, user or MCP server
+// limit is 409 Conflict, since removing one frees capacity.
+func quotaExceededResponse(c fiber.Ctx, err *application.QuotaExceededError) error {
+	status := fiber.StatusConflict
+	if err.Resource == domain.QuotaVerifications {
+		status = fiber.StatusPaymentRequired
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error":    err.Error(),
+		"code":     "quota_exceeded",
+		"resource": err.Resource,
+		"plan":     err.Plan,
+		"limit":    err.Limit,
+		"used":     err.Used,
+	})
+}
+
+// GetUsage returns the organization's usage of every plan quota
+// @Summary Get organization quota usage
+// @Description Agents, users and MCP servers against the plan's limits, and verify-action calls this month against the monthly quota. A limit of 0 is unlimited.
+// @Tags organizations
+// @Produce json
+// @Success 200 {object} domain.OrganizationUsage
+// @Router /api/v1/organizations/current/usage [get]
+func (h *QuotaHandler) GetUsage(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	usage, err := h.quotaService.GetUsage(c.Context(), orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch quota usage",
+		})
+	}
+
+	return c.JSON(usage)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// ScheduledJobHandler exposes the background maintenance job scheduler to admins
+type ScheduledJobHandler struct {
+	jobScheduler *application.JobSchedulerService
+	auditService *application.AuditService
+}
+
+func NewScheduledJobHandler(
+	jobScheduler *application.JobSchedulerService,
+	auditService *application.AuditService,
+) *ScheduledJobHandler {
+	return &ScheduledJobHandler{
+		jobScheduler: jobScheduler,
+		auditService: auditService,
+	}
+}
+
+// ListJobs lists the registered maintenance jobs
+// @Summary List scheduled jobs
+// @Description List registered maintenance jobs with their cron schedule, next run and last run (Admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/jobs [get]
+func (h *ScheduledJobHandler) ListJobs(c fiber.Ctx) error {
+	jobs, err := h.jobScheduler.ListJobs(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list scheduled jobs",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"jobs":  jobs,
+		"total": len(jobs),
+	})
+}
+
+// GetJobRuns returns the run history of a job
+// @Summary Get scheduled job runs
+// @Description Get the run history of a maintenance job, newest first (Admin only)
+// @Tags admin
+// @Produce json
+// @Param name path string true "Job name"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/jobs/{name}/runs [get]
+func (h *ScheduledJobHandler) GetJobRuns(c fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	runs, err := h.jobScheduler.GetJobRuns(c.Context(), c.Params("name"), limit, offset)
+	if err != nil {
+		if err.Error() == "scheduled job not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch job runs",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"runs":   runs,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// TriggerJob runs a job immediately
+// @Summary Trigger scheduled job
+// @Description Run a maintenance job now; it executes in the background and its run record is returned (Admin only)
+// @Tags admin
+// @Produce json
+// @Param name path string true "Job name"
+// @Success 202 {object} domain.ScheduledJobRun
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/jobs/{name}/trigger [post]
+func (h *ScheduledJobHandler) TriggerJob(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+	name := c.Params("name")
+
+	run, err := h.jobScheduler.TriggerJob(c.Context(), name, userID)
+	if err != nil {
+		switch err.Error() {
+		case "scheduled job not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case "job is already running":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to trigger job",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionTrigger,
+		"scheduled_job",
+		run.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"job_name": name,
+		},
+	)
+
+	return c.Status(fiber.StatusAccepted).JSON(run)
+}
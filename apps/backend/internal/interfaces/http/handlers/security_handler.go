@@ -6,20 +6,27 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
 	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
 )
 
 type SecurityHandler struct {
-	securityService *application.SecurityService
-	auditService    *application.AuditService
+	securityService         *application.SecurityService
+	auditService            *application.AuditService
+	agentService            *application.AgentService
+	anomalyDetectionService *application.AnomalyDetectionService
 }
 
 func NewSecurityHandler(
 	securityService *application.SecurityService,
 	auditService *application.AuditService,
+	agentService *application.AgentService,
+	anomalyDetectionService *application.AnomalyDetectionService,
 ) *SecurityHandler {
 	return &SecurityHandler{
-		securityService: securityService,
-		auditService:    auditService,
+		securityService:         securityService,
+		auditService:            auditService,
+		agentService:            agentService,
+		anomalyDetectionService: anomalyDetectionService,
 	}
 }
 
@@ -105,3 +112,92 @@ func (h *SecurityHandler) GetSecurityMetrics(c fiber.Ctx) error {
 
 	return c.JSON(metrics)
 }
+
+// GetAgentBaseline returns the learned activity baseline of an agent
+// @Summary Get agent activity baseline
+// @Description Get the hourly volume, hour-of-day distribution, actions, resources, MCP servers and source IPs learned for an agent
+// @Tags security
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} domain.AgentActivityBaseline
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/security/agents/{id}/baseline [get]
+func (h *SecurityHandler) GetAgentBaseline(c fiber.Ctx) error {
+	agent, err := organizationAgent(c, h.agentService)
+	if err != nil {
+		return err
+	}
+
+	baseline, err := h.anomalyDetectionService.GetBaseline(c.Context(), agent)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch activity baseline",
+		})
+	}
+	if baseline == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "No activity has been observed for this agent yet",
+		})
+	}
+
+	return c.JSON(baseline)
+}
+
+// ResetAgentBaseline discards the learned activity baseline of an agent
+// @Summary Reset agent activity baseline
+// @Description Discard an agent's learned baseline so it is learned again, e.g. after its role changed. No anomalies are flagged while it is relearned.
+// @Tags security
+// @Param id path string true "Agent ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/security/agents/{id}/baseline [delete]
+func (h *SecurityHandler) ResetAgentBaseline(c fiber.Ctx) error {
+	agent, err := organizationAgent(c, h.agentService)
+	if err != nil {
+		return err
+	}
+
+	if err := h.anomalyDetectionService.ResetBaseline(c.Context(), agent.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to reset activity baseline",
+		})
+	}
+
+	userID, _ := c.Locals("user_id").(uuid.UUID)
+	h.auditService.LogAction(
+		c.Context(),
+		agent.OrganizationID,
+		userID,
+		domain.AuditActionDelete,
+		"agent_activity_baseline",
+		agent.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"agent_name": agent.Name,
+		},
+	)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// organizationAgent loads the agent in the path, which must belong to the caller's organization
+func organizationAgent(c fiber.Ctx, agentService *application.AgentService) (*domain.Agent, error) {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid agent ID")
+	}
+
+	agent, err := agentService.GetAgent(c.Context(), agentID)
+	if err != nil || agent.OrganizationID != orgID {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Agent not found")
+	}
+
+	return agent, nil
+}
@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
 	"github.com/opena2a/identity/backend/internal/application"
@@ -17,6 +20,24 @@ func NewSecurityPolicyHandler(policyService *application.SecurityPolicyService)
 	}
 }
 
+// policyError maps policy service errors to HTTP responses
+func policyError(c fiber.Ctx, err error, fallback string) error {
+	if strings.HasPrefix(err.Error(), "policy loosens inherited policy") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fallback})
+}
+
+// ownPolicy fetches a policy of the current organization. Policies inherited from a parent
+// organization can only be changed there.
+func (h *SecurityPolicyHandler) ownPolicy(c fiber.Ctx, policyID uuid.UUID) (*domain.SecurityPolicy, error) {
+	policy, err := h.policyService.GetPolicy(c.Context(), policyID)
+	if err != nil || policy.OrganizationID != c.Locals("organization_id").(uuid.UUID) {
+		return nil, errors.New("policy not found")
+	}
+	return policy, nil
+}
+
 // ListPolicies lists all security policies for the organization (admin only)
 func (h *SecurityPolicyHandler) ListPolicies(c fiber.Ctx) error {
 	// 🔍 Safe type assertion with error checking
@@ -53,7 +74,7 @@ func (h *SecurityPolicyHandler) GetPolicy(c fiber.Ctx) error {
 		})
 	}
 
-	policy, err := h.policyService.GetPolicy(c.Context(), policyID)
+	policy, err := h.ownPolicy(c, policyID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Policy not found",
@@ -74,6 +95,11 @@ type CreatePolicyRequest struct {
 	AppliesTo         string                   `json:"appliesTo" validate:"required"`
 	IsEnabled         bool                     `json:"isEnabled"`
 	Priority          int                      `json:"priority" validate:"required"`
+	Inheritable       *bool                    `json:"inheritable"` // Applies to child organizations too (default true)
+}
+
+func (r *CreatePolicyRequest) inheritable() bool {
+	return r.Inheritable == nil || *r.Inheritable
 }
 
 // CreatePolicy creates a new security policy (admin only)
@@ -99,13 +125,12 @@ func (h *SecurityPolicyHandler) CreatePolicy(c fiber.Ctx) error {
 		AppliesTo:         req.AppliesTo,
 		IsEnabled:         req.IsEnabled,
 		Priority:          req.Priority,
+		Inheritable:       req.inheritable(),
 		CreatedBy:         userID,
 	}
 
 	if err := h.policyService.CreatePolicy(c.Context(), policy); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create policy",
-		})
+		return policyError(c, err, "Failed to create policy")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(policy)
@@ -127,7 +152,7 @@ func (h *SecurityPolicyHandler) UpdatePolicy(c fiber.Ctx) error {
 		})
 	}
 
-	policy, err := h.policyService.GetPolicy(c.Context(), policyID)
+	policy, err := h.ownPolicy(c, policyID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Policy not found",
@@ -144,11 +169,10 @@ func (h *SecurityPolicyHandler) UpdatePolicy(c fiber.Ctx) error {
 	policy.AppliesTo = req.AppliesTo
 	policy.IsEnabled = req.IsEnabled
 	policy.Priority = req.Priority
+	policy.Inheritable = req.inheritable()
 
 	if err := h.policyService.UpdatePolicy(c.Context(), policy); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update policy",
-		})
+		return policyError(c, err, "Failed to update policy")
 	}
 
 	return c.JSON(policy)
@@ -163,6 +187,12 @@ func (h *SecurityPolicyHandler) DeletePolicy(c fiber.Ctx) error {
 		})
 	}
 
+	if _, err := h.ownPolicy(c, policyID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Policy not found",
+		})
+	}
+
 	if err := h.policyService.DeletePolicy(c.Context(), policyID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to delete policy",
@@ -193,6 +223,12 @@ func (h *SecurityPolicyHandler) TogglePolicy(c fiber.Ctx) error {
 		})
 	}
 
+	if _, err := h.ownPolicy(c, policyID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Policy not found",
+		})
+	}
+
 	if req.IsEnabled {
 		if err := h.policyService.EnablePolicy(c.Context(), policyID); err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// SessionHandler lets users see where they are signed in and sign devices out
+type SessionHandler struct {
+	refreshTokenService *application.RefreshTokenService
+	auditService        *application.AuditService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(
+	refreshTokenService *application.RefreshTokenService,
+	auditService *application.AuditService,
+) *SessionHandler {
+	return &SessionHandler{
+		refreshTokenService: refreshTokenService,
+		auditService:        auditService,
+	}
+}
+
+// ListSessions godoc
+// @Summary List active sessions
+// @Description Devices the authenticated user is signed in on, one per login. The caller's own session is marked current
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/sessions [get]
+// @Security BearerAuth
+func (h *SessionHandler) ListSessions(c fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+	currentSessionID, _ := c.Locals("session_id").(string)
+
+	sessions, err := h.refreshTokenService.ListSessions(c.Context(), userID, currentSessionID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list sessions",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"sessions": sessions,
+	})
+}
+
+// RevokeSession godoc
+// @Summary Sign out a session
+// @Description Signs one of the authenticated user's devices out. Its refresh token stops working immediately and its access tokens within 30 seconds
+// @Tags auth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/sessions/{id} [delete]
+// @Security BearerAuth
+func (h *SessionHandler) RevokeSession(c fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User not authenticated",
+		})
+	}
+	orgID, _ := c.Locals("organization_id").(uuid.UUID)
+
+	sessionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid session ID",
+		})
+	}
+
+	if err := h.refreshTokenService.RevokeSession(c.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, application.ErrSessionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Session not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to sign out session",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionLogout,
+		"session",
+		sessionID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"remote": true,
+		},
+	)
+
+	return c.JSON(fiber.Map{
+		"message": "Session signed out",
+	})
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// SIEMSinkHandler manages an organization's SIEM forwarding destinations
+type SIEMSinkHandler struct {
+	siemForwarder *application.SIEMForwarderService
+	auditService  *application.AuditService
+}
+
+func NewSIEMSinkHandler(
+	siemForwarder *application.SIEMForwarderService,
+	auditService *application.AuditService,
+) *SIEMSinkHandler {
+	return &SIEMSinkHandler{
+		siemForwarder: siemForwarder,
+		auditService:  auditService,
+	}
+}
+
+// siemSinkError maps service errors onto HTTP responses
+func siemSinkError(c fiber.Ctx, err error, fallback string) error {
+	switch {
+	case err.Error() == "siem sink not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "SIEM sink not found",
+		})
+	case strings.HasPrefix(err.Error(), "invalid siem sink"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
+
+// CreateSink creates a SIEM sink
+// @Summary Create SIEM sink
+// @Description Forward audit records, alerts and verification events to a SIEM over syslog (RFC 5424, CEF/LEEF), Splunk HEC or NDJSON over HTTP (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body application.SIEMSinkRequest true "Sink configuration"
+// @Success 201 {object} domain.SIEMSink
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/admin/siem-sinks [post]
+func (h *SIEMSinkHandler) CreateSink(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	var req application.SIEMSinkRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	sink, err := h.siemForwarder.CreateSink(c.Context(), orgID, userID, &req)
+	if err != nil {
+		return siemSinkError(c, err, "Failed to create SIEM sink")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionCreate,
+		"siem_sink",
+		sink.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"sink_name": sink.Name,
+			"sink_type": sink.Type,
+			"endpoint":  sink.Endpoint,
+		},
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(sink)
+}
+
+// ListSinks lists the organization's SIEM sinks
+// @Summary List SIEM sinks
+// @Description List the organization's SIEM forwarding destinations (Admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/siem-sinks [get]
+func (h *SIEMSinkHandler) ListSinks(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	sinks, err := h.siemForwarder.ListSinks(c.Context(), orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch SIEM sinks",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"sinks": sinks,
+		"total": len(sinks),
+	})
+}
+
+// GetSink returns a SIEM sink
+// @Summary Get SIEM sink
+// @Description Get a SIEM sink's configuration; the token is never returned (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Sink ID"
+// @Success 200 {object} domain.SIEMSink
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/siem-sinks/{id} [get]
+func (h *SIEMSinkHandler) GetSink(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	sinkID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid sink ID",
+		})
+	}
+
+	sink, err := h.siemForwarder.GetSink(c.Context(), orgID, sinkID)
+	if err != nil {
+		return siemSinkError(c, err, "Failed to fetch SIEM sink")
+	}
+
+	return c.JSON(sink)
+}
+
+// UpdateSink replaces a SIEM sink's configuration
+// @Summary Update SIEM sink
+// @Description Update a SIEM sink; omit token to keep the current one (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Sink ID"
+// @Param request body application.SIEMSinkRequest true "Sink configuration"
+// @Success 200 {object} domain.SIEMSink
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/siem-sinks/{id} [put]
+func (h *SIEMSinkHandler) UpdateSink(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+	sinkID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid sink ID",
+		})
+	}
+
+	var req application.SIEMSinkRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	sink, err := h.siemForwarder.UpdateSink(c.Context(), orgID, sinkID, &req)
+	if err != nil {
+		return siemSinkError(c, err, "Failed to update SIEM sink")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionUpdate,
+		"siem_sink",
+		sink.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"sink_name": sink.Name,
+			"endpoint":  sink.Endpoint,
+			"is_active": sink.IsActive,
+		},
+	)
+
+	return c.JSON(sink)
+}
+
+// DeleteSink deletes a SIEM sink
+// @Summary Delete SIEM sink
+// @Description Delete a SIEM sink; events already buffered for it are still flushed (Admin only)
+// @Tags admin
+// @Param id path string true "Sink ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/siem-sinks/{id} [delete]
+func (h *SIEMSinkHandler) DeleteSink(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+	sinkID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid sink ID",
+		})
+	}
+
+	if err := h.siemForwarder.DeleteSink(c.Context(), orgID, sinkID); err != nil {
+		return siemSinkError(c, err, "Failed to delete SIEM sink")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionDelete,
+		"siem_sink",
+		sinkID,
+		c.IP(),
+		c.Get("User-Agent"),
+		nil,
+	)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetSinkMetrics returns delivery metrics of a SIEM sink
+// @Summary Get SIEM sink metrics
+// @Description Queued, delivered, dropped (buffer full) and failed event counts and queue depth of a sink on the serving instance (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Sink ID"
+// @Success 200 {object} application.SIEMSinkMetrics
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/siem-sinks/{id}/metrics [get]
+func (h *SIEMSinkHandler) GetSinkMetrics(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	sinkID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid sink ID",
+		})
+	}
+
+	metrics, err := h.siemForwarder.GetSinkMetrics(c.Context(), orgID, sinkID)
+	if err != nil {
+		return siemSinkError(c, err, "Failed to fetch SIEM sink metrics")
+	}
+
+	return c.JSON(metrics)
+}
+
+// TestSink sends a test event to a SIEM sink
+// @Summary Test SIEM sink
+// @Description Synchronously deliver a test event to verify connectivity and credentials (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Sink ID"
+// @Success 200 {object} application.SIEMSinkTestResult
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/siem-sinks/{id}/test [post]
+func (h *SIEMSinkHandler) TestSink(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+	sinkID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid sink ID",
+		})
+	}
+
+	result, err := h.siemForwarder.TestSink(c.Context(), orgID, sinkID)
+	if err != nil {
+		return siemSinkError(c, err, "Failed to test SIEM sink")
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionTest,
+		"siem_sink",
+		sinkID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"success": result.Success,
+		},
+	)
+
+	return c.JSON(result)
+}
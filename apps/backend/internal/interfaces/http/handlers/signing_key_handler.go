@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/auth"
+)
+
+// SigningKeyHandler publishes the keys AIM signs tokens with and the OpenID Connect discovery
+// document, so other services can verify AIM-issued identities without a shared secret
+type SigningKeyHandler struct {
+	signingKeyService *application.SigningKeyService
+	agentTokenService *application.AgentTokenService
+	jwtService        *auth.JWTService
+}
+
+func NewSigningKeyHandler(
+	signingKeyService *application.SigningKeyService,
+	agentTokenService *application.AgentTokenService,
+	jwtService *auth.JWTService,
+) *SigningKeyHandler {
+	return &SigningKeyHandler{
+		signingKeyService: signingKeyService,
+		agentTokenService: agentTokenService,
+		jwtService:        jwtService,
+	}
+}
+
+// JWKS publishes the keys of user tokens and agent access tokens
+// @Summary JSON Web Key Set
+// @Description Public keys that sign user access and refresh tokens (including the next key and retired keys whose tokens have not expired yet) and agent access tokens. Keys are identified by the token's "kid" header
+// @Tags discovery
+// @Produce json
+// @Success 200 {object} domain.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (h *SigningKeyHandler) JWKS(c fiber.Ctx) error {
+	set := h.signingKeyService.JWKS()
+	if h.agentTokenService != nil {
+		set.Keys = append(set.Keys, h.agentTokenService.JWKS().Keys...)
+	}
+
+	c.Set("Cache-Control", "public, max-age=300")
+	return c.JSON(set)
+}
+
+// OpenIDConfiguration publishes the OpenID Connect discovery document
+// @Summary OpenID Connect discovery
+// @Description Issuer and key set of AIM-issued user tokens. Users sign in through AIM's own login API, so the document describes how to verify tokens rather than an authorization code flow
+// @Tags discovery
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /.well-known/openid-configuration [get]
+func (h *SigningKeyHandler) OpenIDConfiguration(c fiber.Ctx) error {
+	issuer := h.jwtService.Issuer()
+	signingAlgs := []string{h.signingKeyService.Algorithm()}
+
+	document := fiber.Map{
+		"issuer":                                issuer,
+		"jwks_uri":                              issuer + "/.well-known/jwks.json",
+		"response_types_supported":              []string{"token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": signingAlgs,
+		"claims_supported": []string{
+			"iss", "sub", "exp", "iat", "nbf", "jti", "user_id", "organization_id", "email", "role",
+		},
+	}
+	if h.agentTokenService != nil {
+		document["token_endpoint"] = h.agentTokenService.TokenEndpoint()
+		document["grant_types_supported"] = []string{domain.GrantTypeClientCredentials, domain.GrantTypeTokenExchange}
+		document["token_endpoint_auth_methods_supported"] = []string{"private_key_jwt"}
+		document["token_endpoint_auth_signing_alg_values_supported"] = []string{"EdDSA"}
+		document["introspection_endpoint"] = issuer + "/api/v1/oauth/introspect"
+		document["revocation_endpoint"] = issuer + "/api/v1/oauth/revoke"
+	}
+
+	c.Set("Cache-Control", "public, max-age=300")
+	return c.JSON(document)
+}
+
+// ListSigningKeys lists the user token signing keys
+// @Summary List signing keys
+// @Description Next, active and retired user token signing keys. Keys rotate on the rotate_jwt_signing_keys job, which can also be triggered manually (Admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/signing-keys [get]
+func (h *SigningKeyHandler) ListSigningKeys(c fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"algorithm": h.signingKeyService.Algorithm(),
+		"keys":      h.signingKeyService.ListKeys(),
+	})
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"bytes"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// VerificationApprovalHandler lets approvers decide agent actions parked for human approval,
+// from the dashboard or through signed one-click links
+type VerificationApprovalHandler struct {
+	verificationApprovalService *application.VerificationApprovalService
+	auditService                *application.AuditService
+}
+
+func NewVerificationApprovalHandler(
+	verificationApprovalService *application.VerificationApprovalService,
+	auditService *application.AuditService,
+) *VerificationApprovalHandler {
+	return &VerificationApprovalHandler{
+		verificationApprovalService: verificationApprovalService,
+		auditService:                auditService,
+	}
+}
+
+// verificationApprovalError maps service errors to HTTP responses
+func verificationApprovalError(c fiber.Ctx, err error, fallback string) error {
+	msg := err.Error()
+	switch {
+	case msg == "verification approval not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": msg})
+	case msg == "verification approval already decided", msg == "verification approval already decided or expired",
+		msg == "verification approval has expired":
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": msg})
+	case strings.HasPrefix(msg, "invalid decision"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": msg})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fallback})
+}
+
+// ListApprovals lists agent actions awaiting or past human approval
+// @Summary List verification approvals
+// @Description List agent actions parked for human approval, newest first. Filter by status (pending, approved, denied, timeout).
+// @Tags verifications
+// @Produce json
+// @Param status query string false "Status filter"
+// @Param limit query int false "Page size (max 200)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/verification-approvals [get]
+func (h *VerificationApprovalHandler) ListApprovals(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var status *domain.VerificationApprovalStatus
+	if value := c.Query("status"); value != "" {
+		s := domain.VerificationApprovalStatus(value)
+		status = &s
+	}
+
+	approvals, total, err := h.verificationApprovalService.ListApprovals(c.Context(), orgID, status, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list verification approvals",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"approvals": approvals,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// GetApproval returns one verification approval
+// @Summary Get verification approval
+// @Tags verifications
+// @Produce json
+// @Param id path string true "Verification ID"
+// @Success 200 {object} domain.VerificationApproval
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/verification-approvals/{id} [get]
+func (h *VerificationApprovalHandler) GetApproval(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	approvalID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid verification ID",
+		})
+	}
+
+	approval, err := h.verificationApprovalService.GetApproval(c.Context(), orgID, approvalID)
+	if err != nil {
+		return verificationApprovalError(c, err, "Failed to fetch verification approval")
+	}
+
+	return c.JSON(approval)
+}
+
+// ApproveVerification approves a pending agent action
+// @Summary Approve agent action
+// @Description Approve an agent action awaiting human approval (Manager or Admin)
+// @Tags verifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Verification ID"
+// @Success 200 {object} domain.VerificationApproval
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/verification-approvals/{id}/approve [post]
+func (h *VerificationApprovalHandler) ApproveVerification(c fiber.Ctx) error {
+	return h.decide(c, domain.VerificationApprovalApproved)
+}
+
+// DenyVerification denies a pending agent action
+// @Summary Deny agent action
+// @Description Deny an agent action awaiting human approval; a reason is required (Manager or Admin)
+// @Tags verifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Verification ID"
+// @Success 200 {object} domain.VerificationApproval
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/verification-approvals/{id}/deny [post]
+func (h *VerificationApprovalHandler) DenyVerification(c fiber.Ctx) error {
+	return h.decide(c, domain.VerificationApprovalDenied)
+}
+
+func (h *VerificationApprovalHandler) decide(c fiber.Ctx, decision domain.VerificationApprovalStatus) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	approvalID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid verification ID",
+		})
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.Bind().JSON(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	approval, err := h.verificationApprovalService.Decide(c.Context(), orgID, approvalID, userID, decision, req.Reason)
+	if err != nil {
+		return verificationApprovalError(c, err, "Failed to record approval decision")
+	}
+
+	h.logDecision(c, approval, userID)
+	return c.JSON(approval)
+}
+
+func (h *VerificationApprovalHandler) logDecision(c fiber.Ctx, approval *domain.VerificationApproval, userID uuid.UUID) {
+	h.auditService.LogAction(
+		c.Context(),
+		approval.OrganizationID,
+		userID,
+		domain.AuditActionUpdate,
+		"verification_approval",
+		approval.ID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"agent_id":        approval.AgentID,
+			"action_type":     approval.ActionType,
+			"resource":        approval.Resource,
+			"decision":        approval.Status,
+			"decision_reason": approval.DecisionReason,
+			"decided_via":     approval.DecidedVia,
+		},
+	)
+}
+
+// decisionLinkPage is the confirmation page behind a one-click link. Deciding needs a POST from
+// this page, so mail scanners and link previews that follow the link decide nothing.
+var decisionLinkPage = template.Must(template.New("decision").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1.0">
+<meta name="robots" content="noindex">
+<title>Agent action approval</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', 'Inter', Arial, sans-serif; background: #fafafa; color: #18181b; }
+.card { max-width: 520px; margin: 48px auto; background: #fff; border: 1px solid #e4e4e7; border-radius: 12px; padding: 32px; }
+dt { font-weight: 600; margin-top: 12px; } dd { margin: 4px 0 0 0; color: #52525b; }
+textarea { width: 100%; min-height: 80px; margin-top: 16px; box-sizing: border-box; }
+button { margin-top: 16px; padding: 10px 20px; border: 0; border-radius: 8px; color: #fff; font-size: 15px; cursor: pointer; }
+.approved { background: #16a34a; } .denied { background: #dc2626; }
+</style>
+</head>
+<body>
+<div class="card">
+{{if .Message}}
+<h2>{{.Message}}</h2>
+{{else}}
+<h2>{{if eq .Decision "approved"}}Approve{{else}}Deny{{end}} this agent action?</h2>
+<dl>
+<dt>Agent</dt><dd>{{.Approval.AgentName}}</dd>
+<dt>Action</dt><dd>{{.Approval.ActionType}}</dd>
+{{if .Approval.Resource}}<dt>Resource</dt><dd>{{.Approval.Resource}}</dd>{{end}}
+<dt>Why approval is needed</dt><dd>{{.Approval.Reason}}</dd>
+<dt>Times out</dt><dd>{{.Approval.ExpiresAt.Format "January 2, 2006 15:04 MST"}}</dd>
+</dl>
+<form method="POST">
+<textarea name="reason" placeholder="Reason{{if eq .Decision "denied"}} (required){{else}} (optional){{end}}"{{if eq .Decision "denied"}} required{{end}}></textarea>
+<button type="submit" class="{{.Decision}}">{{if eq .Decision "approved"}}Approve{{else}}Deny{{end}}</button>
+</form>
+{{end}}
+</div>
+</body>
+</html>`))
+
+type decisionLinkView struct {
+	Approval *domain.VerificationApproval
+	Decision domain.VerificationApprovalStatus
+	Message  string
+}
+
+func renderDecisionLinkPage(c fiber.Ctx, status int, view decisionLinkView) error {
+	var page bytes.Buffer
+	if err := decisionLinkPage.Execute(&page, view); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to render page")
+	}
+	c.Set("Content-Type", "text/html; charset=utf-8")
+	c.Set("Cache-Control", "no-store")
+	c.Set("Referrer-Policy", "no-referrer")
+	return c.Status(status).Send(page.Bytes())
+}
+
+// decisionLinkMessage renders a final page for link errors and already-decided approvals
+func decisionLinkMessage(c fiber.Ctx, err error) error {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "invalid decision"):
+		return renderDecisionLinkPage(c, fiber.StatusBadRequest, decisionLinkView{Message: strings.TrimPrefix(msg, "invalid decision: ")})
+	case msg == "verification approval already decided":
+		return renderDecisionLinkPage(c, fiber.StatusConflict, decisionLinkView{Message: "This request has already been decided."})
+	case msg == "verification approval has expired", msg == "verification approval already decided or expired":
+		return renderDecisionLinkPage(c, fiber.StatusConflict, decisionLinkView{Message: "This request is no longer open for a decision."})
+	case msg == "approval link has expired":
+		return renderDecisionLinkPage(c, fiber.StatusGone, decisionLinkView{Message: "This approval link has expired."})
+	case msg == "invalid approval link", msg == "approval link is no longer valid", msg == "verification approval not found":
+		return renderDecisionLinkPage(c, fiber.StatusNotFound, decisionLinkView{Message: "This approval link is not valid."})
+	}
+	return renderDecisionLinkPage(c, fiber.StatusInternalServerError, decisionLinkView{Message: "Something went wrong. Please decide from the dashboard."})
+}
+
+// ShowDecisionLink renders the confirmation page of a signed one-click link
+// @Summary Confirm approval link
+// @Description Confirmation page for a signed approve or deny link from an approval email. Does not decide anything.
+// @Tags public
+// @Produce html
+// @Param token path string true "Signed link token"
+// @Router /api/v1/public/verification-approvals/{token} [get]
+func (h *VerificationApprovalHandler) ShowDecisionLink(c fiber.Ctx) error {
+	link, err := h.verificationApprovalService.ResolveDecisionLink(c.Context(), c.Params("token"))
+	if err != nil {
+		return decisionLinkMessage(c, err)
+	}
+	if link.Approval.Status != domain.VerificationApprovalPending {
+		return renderDecisionLinkPage(c, fiber.StatusConflict, decisionLinkView{Message: "This request has already been decided."})
+	}
+
+	return renderDecisionLinkPage(c, fiber.StatusOK, decisionLinkView{Approval: link.Approval, Decision: link.Decision})
+}
+
+// SubmitDecisionLink records the decision of a signed one-click link
+// @Summary Decide through approval link
+// @Description Records the approve or deny decision carried by a signed link, with an optional reason (required to deny)
+// @Tags public
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param token path string true "Signed link token"
+// @Router /api/v1/public/verification-approvals/{token} [post]
+func (h *VerificationApprovalHandler) SubmitDecisionLink(c fiber.Ctx) error {
+	link, err := h.verificationApprovalService.DecideWithLink(c.Context(), c.Params("token"), c.FormValue("reason"))
+	if err != nil {
+		return decisionLinkMessage(c, err)
+	}
+
+	h.logDecision(c, link.Approval, link.Approver.ID)
+
+	message := "The agent action was approved."
+	if link.Approval.Status == domain.VerificationApprovalDenied {
+		message = "The agent action was denied."
+	}
+	return renderDecisionLinkPage(c, fiber.StatusOK, decisionLinkView{Message: message})
+}
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"bytes"
 	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,10 +19,15 @@ import (
 
 // VerificationHandler handles agent action verification requests
 type VerificationHandler struct {
-	agentService              *application.AgentService
-	auditService              *application.AuditService
-	trustService              *application.TrustCalculator
-	verificationEventService  *application.VerificationEventService
+	agentService                *application.AgentService
+	auditService                *application.AuditService
+	trustService                *application.TrustCalculator
+	verificationEventService    *application.VerificationEventService
+	anomalyDetectionService     *application.AnomalyDetectionService
+	dataExfiltrationService     *application.DataExfiltrationService
+	verificationApprovalService *application.VerificationApprovalService
+	actionCatalogService        *application.ActionCatalogService
+	quotaService                *application.QuotaService
 }
 
 // NewVerificationHandler creates a new verification handler
@@ -29,33 +36,44 @@ func NewVerificationHandler(
 	auditService *application.AuditService,
 	trustService *application.TrustCalculator,
 	verificationEventService *application.VerificationEventService,
+	anomalyDetectionService *application.AnomalyDetectionService,
+	dataExfiltrationService *application.DataExfiltrationService,
+	verificationApprovalService *application.VerificationApprovalService,
+	actionCatalogService *application.ActionCatalogService,
+	quotaService *application.QuotaService,
 ) *VerificationHandler {
 	return &VerificationHandler{
-		agentService:             agentService,
-		auditService:             auditService,
-		trustService:             trustService,
-		verificationEventService: verificationEventService,
+		agentService:                agentService,
+		auditService:                auditService,
+		trustService:                trustService,
+		verificationEventService:    verificationEventService,
+		anomalyDetectionService:     anomalyDetectionService,
+		dataExfiltrationService:     dataExfiltrationService,
+		verificationApprovalService: verificationApprovalService,
+		actionCatalogService:        actionCatalogService,
+		quotaService:                quotaService,
 	}
 }
 
 // VerificationRequest represents an action verification request from an agent
 type VerificationRequest struct {
-	AgentID    string                 `json:"agent_id" validate:"required"`
-	ActionType string                 `json:"action_type" validate:"required"`
-	Resource   string                 `json:"resource"`
-	Context    map[string]interface{} `json:"context"`
-	Timestamp  string                 `json:"timestamp" validate:"required"`
-	RiskLevel  string                 `json:"risk_level,omitempty"` // Optional risk assessment
-	Signature  string                 `json:"signature" validate:"required"`
-	PublicKey  string                 `json:"public_key" validate:"required"`
+	AgentID     string                 `json:"agent_id" validate:"required"`
+	ActionType  string                 `json:"action_type" validate:"required"`
+	Resource    string                 `json:"resource"`
+	Context     map[string]interface{} `json:"context"`
+	Timestamp   string                 `json:"timestamp" validate:"required"`
+	RiskLevel   string                 `json:"risk_level,omitempty"`   // Optional risk assessment
+	CallbackURL string                 `json:"callback_url,omitempty"` // Notified when a pending request is decided
+	Signature   string                 `json:"signature" validate:"required"`
+	PublicKey   string                 `json:"public_key" validate:"required"`
 }
 
 // VerificationResponse represents the verification result
 type VerificationResponse struct {
 	ID          string    `json:"id"`
-	Status      string    `json:"status"` // "approved", "denied", "pending"
+	Status      string    `json:"status"` // "approved", "denied", "pending", "timeout"
 	ApprovedBy  string    `json:"approved_by,omitempty"`
-	ExpiresAt   time.Time `json:"expires_at,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty"` // For pending requests, when they time out
 	DenialReason string   `json:"denial_reason,omitempty"`
 	TrustScore  float64   `json:"trust_score"`
 }
@@ -68,6 +86,7 @@ type VerificationResponse struct {
 // @Produce json
 // @Param request body VerificationRequest true "Verification request"
 // @Success 201 {object} VerificationResponse "Verification created"
+// @Success 202 {object} VerificationResponse "Action parked for human approval"
 // @Failure 400 {object} ErrorResponse "Invalid request"
 // @Failure 401 {object} ErrorResponse "Invalid signature"
 // @Failure 403 {object} ErrorResponse "Action denied"
@@ -87,6 +106,13 @@ func (h *VerificationHandler) CreateVerification(c fiber.Ctx) error {
 			"error": "agent_id, action_type, signature, and public_key are required",
 		})
 	}
+	if h.verificationApprovalService != nil {
+		if err := h.verificationApprovalService.ValidateCallbackURL(c.Context(), req.CallbackURL); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
 
 	// Parse agent ID
 	agentID, err := uuid.Parse(req.AgentID)
@@ -125,18 +151,95 @@ func (h *VerificationHandler) CreateVerification(c fiber.Ctx) error {
 		})
 	}
 
-	// Calculate trust score for this action
-	trustScore := h.calculateActionTrustScore(agent, req.ActionType, req.Resource)
+	// Enforce the plan's monthly verification quota
+	if h.quotaService != nil {
+		if err := h.quotaService.CheckQuota(c.Context(), agent.OrganizationID, domain.QuotaVerifications); err != nil {
+			var quotaErr *application.QuotaExceededError
+			if errors.As(err, &quotaErr) {
+				return quotaExceededResponse(c, quotaErr)
+			}
+		}
+	}
+
+	// Look up the action's risk in the organization's action catalog
+	action := application.BuiltinAction(req.ActionType)
+	if h.actionCatalogService != nil {
+		action = h.actionCatalogService.Resolve(c.Context(), agent.OrganizationID, req.ActionType)
+	}
+
+	// Calculate trust score for this action (high-risk actions reduce effective trust)
+	trustScore := action.EffectiveTrust(agent.TrustScore)
 
 	// Determine auto-approval based on trust score and action type
-	status, denialReason := h.determineVerificationStatus(agent, req.ActionType, trustScore)
+	status, reason := h.determineVerificationStatus(action, req.ActionType, trustScore)
+	denialReason, approvalReason := "", ""
+	if status == "denied" {
+		denialReason = reason
+	} else if status == "pending" {
+		approvalReason = reason
+	}
+
+	// ✅ CHECK DATA VOLUME - block further data exports once the exfiltration threshold is exceeded
+	if status != "denied" && h.dataExfiltrationService != nil {
+		usage, err := h.dataExfiltrationService.CheckExport(c.Context(), agent, req.ActionType)
+		if err != nil {
+			fmt.Printf("⚠️  Data export check failed for agent %s: %v\n", agent.Name, err)
+		} else if usage != nil && usage.ExportBlocked {
+			status = "denied"
+			denialReason = h.dataExfiltrationService.ExportDeniedReason(usage)
+		}
+	}
+
+	// ✅ CHECK AGAINST THE AGENT'S ACTIVITY BASELINE - unusual_activity policies may deny the action
+	if status != "denied" && h.anomalyDetectionService != nil {
+		anomalyResult, err := h.anomalyDetectionService.ObserveActivity(c.Context(), agent, domain.ActivityObservation{
+			Action:    req.ActionType,
+			Resource:  req.Resource,
+			MCPServer: application.ActivityMCPServer(req.Context),
+			SourceIP:  c.IP(),
+		})
+		if err != nil {
+			fmt.Printf("⚠️  Anomaly detection failed for agent %s: %v\n", agent.Name, err)
+		} else if anomalyResult.Blocked {
+			status = "denied"
+			denialReason = anomalyResult.Reason
+		}
+	}
+
+	// Account for the bytes the agent reported reading or exporting (context.bytes_read, context.bytes_exported)
+	if status == "approved" && h.dataExfiltrationService != nil {
+		if _, err := h.dataExfiltrationService.RecordReportedTransfer(
+			c.Context(), agent, domain.DataTransferSourceVerifyAction, req.Resource, nil, req.Context,
+		); err != nil {
+			fmt.Printf("⚠️  Failed to record data transfer for agent %s: %v\n", agent.Name, err)
+		}
+	}
 
 	// Create verification ID
 	verificationID := uuid.New()
 
+	// ✅ PARK HIGH-RISK ACTIONS FOR HUMAN APPROVAL - the SDK polls or receives a callback
+	var approval *domain.VerificationApproval
+	if status == "pending" {
+		approval, err = h.verificationApprovalService.Enqueue(c.Context(), agent, application.VerificationApprovalRequest{
+			VerificationID: verificationID,
+			ActionType:     req.ActionType,
+			Resource:       req.Resource,
+			Context:        req.Context,
+			TrustScore:     trustScore,
+			Reason:         approvalReason,
+			CallbackURL:    req.CallbackURL,
+		})
+		if err != nil {
+			fmt.Printf("❌ Failed to queue verification %s for approval: %v\n", verificationID, err)
+			status = "denied"
+			denialReason = fmt.Sprintf("Action %s requires human approval, but the approval queue is unavailable", req.ActionType)
+		}
+	}
+
 	// ✅ CHECK FOR CAPABILITY VIOLATIONS - Create alert if agent doesn't have permission
 	shouldCreateAlert := false
-	if status == "approved" {
+	if status != "denied" {
 		// Check if agent has the capability for this action
 		hasCapability, err := h.agentService.HasCapability(c.Context(), agentID, req.ActionType, req.Resource)
 		if err != nil {
@@ -163,6 +266,7 @@ func (h *VerificationHandler) CreateVerification(c fiber.Ctx) error {
 			"trust_score":     trustScore,
 			"auto_approved":   status == "approved",
 			"action_type":     req.ActionType,
+			"risk_tier":       action.RiskTier,
 			"resource":        req.Resource,
 			"context":         req.Context,
 		},
@@ -171,6 +275,9 @@ func (h *VerificationHandler) CreateVerification(c fiber.Ctx) error {
 
 	if status == "denied" {
 		auditEntry.Metadata["denial_reason"] = denialReason
+	} else if status == "pending" {
+		auditEntry.Metadata["approval_required"] = true
+		auditEntry.Metadata["approval_reason"] = approvalReason
 	}
 
 	// Save audit log
@@ -179,6 +286,11 @@ func (h *VerificationHandler) CreateVerification(c fiber.Ctx) error {
 		fmt.Printf("Failed to create audit log: %v\n", err)
 	}
 
+	// Meter the call against the monthly verification quota
+	if h.quotaService != nil {
+		h.quotaService.RecordVerification(c.Context(), agent.OrganizationID, status != "denied")
+	}
+
 	// ✅ CREATE SECURITY ALERT if capability violation detected
 	if shouldCreateAlert {
 		// Determine severity based on action type and context
@@ -258,6 +370,8 @@ func (h *VerificationHandler) CreateVerification(c fiber.Ctx) error {
 	}
 	if status == "denied" {
 		eventMetadata["denial_reason"] = denialReason
+	} else if status == "pending" {
+		eventMetadata["approval_reason"] = approvalReason
 	}
 
 	// Create verification event using service
@@ -266,7 +380,10 @@ func (h *VerificationHandler) CreateVerification(c fiber.Ctx) error {
 		errorReasonPtr = &denialReason
 	}
 
-	completedAt := startTime
+	var completedAt *time.Time
+	if status != "pending" {
+		completedAt = &startTime
+	}
 	verificationEventReq := &application.CreateVerificationEventRequest{
 		OrganizationID:   agent.OrganizationID,
 		AgentID:          agentID,
@@ -284,7 +401,7 @@ func (h *VerificationHandler) CreateVerification(c fiber.Ctx) error {
 		Action:           &req.ActionType,
 		ResourceType:     &req.Resource,
 		StartedAt:        startTime.Add(-time.Duration(verificationDurationMs) * time.Millisecond),
-		CompletedAt:      &completedAt,
+		CompletedAt:      completedAt,
 		Metadata:         eventMetadata,
 	}
 
@@ -296,6 +413,13 @@ func (h *VerificationHandler) CreateVerification(c fiber.Ctx) error {
 	} else {
 		fmt.Printf("✅ Verification event created: ID=%s, OrgID=%s, AgentID=%s\n",
 			event.ID, event.OrganizationID, *event.AgentID)
+
+		// The event is completed when an approver decides
+		if approval != nil {
+			if err := h.verificationApprovalService.AttachVerificationEvent(c.Context(), approval, event.ID); err != nil {
+				fmt.Printf("⚠️  Failed to link verification event to approval %s: %v\n", approval.ID, err)
+			}
+		}
 	}
 
 	// Build response
@@ -310,11 +434,15 @@ func (h *VerificationHandler) CreateVerification(c fiber.Ctx) error {
 		response.ExpiresAt = time.Now().Add(24 * time.Hour)
 	} else if status == "denied" {
 		response.DenialReason = denialReason
+	} else if status == "pending" {
+		response.ExpiresAt = approval.ExpiresAt
 	}
 
 	statusCode := fiber.StatusCreated
 	if status == "denied" {
 		statusCode = fiber.StatusForbidden
+	} else if status == "pending" {
+		statusCode = fiber.StatusAccepted
 	}
 
 	return c.Status(statusCode).JSON(response)
@@ -345,6 +473,11 @@ func (h *VerificationHandler) verifySignature(req VerificationRequest) error {
 		signaturePayload["risk_level"] = req.RiskLevel
 	}
 
+	// Include callback_url if provided so it cannot be redirected in transit
+	if req.CallbackURL != "" {
+		signaturePayload["callback_url"] = req.CallbackURL
+	}
+
 	// Create deterministic JSON matching Python's json.dumps(sort_keys=True)
 	// Python adds spaces after colons and commas, Go doesn't by default
 	// We need to use MarshalIndent with empty prefix and indent to get spaces
@@ -394,72 +527,21 @@ func (h *VerificationHandler) verifySignature(req VerificationRequest) error {
 	return nil
 }
 
-// calculateActionTrustScore calculates trust score for specific action
-func (h *VerificationHandler) calculateActionTrustScore(agent *domain.Agent, actionType, resource string) float64 {
-	// Start with agent's base trust score
-	score := agent.TrustScore
-
-	// Adjust based on action type (high-risk actions reduce effective trust)
-	riskAdjustment := h.getActionRiskAdjustment(actionType)
-	score = score * riskAdjustment
-
-	return score
-}
-
-// getActionRiskAdjustment returns multiplier based on action risk
-func (h *VerificationHandler) getActionRiskAdjustment(actionType string) float64 {
-	riskLevels := map[string]float64{
-		// Low risk (read-only)
-		"read_database":   1.0,
-		"read_file":       1.0,
-		"query_api":       1.0,
-		// Medium risk (modifications)
-		"write_database":  0.8,
-		"write_file":      0.8,
-		"send_email":      0.8,
-		"modify_config":   0.7,
-		// High risk (destructive)
-		"delete_data":     0.5,
-		"delete_file":     0.5,
-		"execute_command": 0.3,
-		"admin_action":    0.3,
-	}
-
-	if adjustment, ok := riskLevels[actionType]; ok {
-		return adjustment
-	}
-
-	// Default: medium risk
-	return 0.8
-}
-
-// determineVerificationStatus determines if action should be auto-approved
+// determineVerificationStatus determines if action should be auto-approved, denied, or parked
+// for human approval. reason explains a denial or why approval is required.
 func (h *VerificationHandler) determineVerificationStatus(
-	agent *domain.Agent,
+	action *domain.ActionCatalogEntry,
 	actionType string,
 	trustScore float64,
-) (status string, denialReason string) {
-	// Minimum trust score thresholds
-	const (
-		MinTrustForLowRisk    = 0.3  // 30%
-		MinTrustForMediumRisk = 0.5  // 50%
-		MinTrustForHighRisk   = 0.7  // 70%
-	)
-
-	// Determine required trust based on action type
-	var requiredTrust float64
-	switch actionType {
-	case "read_database", "read_file", "query_api":
-		requiredTrust = MinTrustForLowRisk
-	case "delete_data", "delete_file", "execute_command", "admin_action":
-		requiredTrust = MinTrustForHighRisk
-	default:
-		requiredTrust = MinTrustForMediumRisk
-	}
-
-	// Check if trust score meets requirement
-	if trustScore < requiredTrust {
-		return "denied", fmt.Sprintf("Trust score %.2f below required %.2f for action %s", trustScore, requiredTrust, actionType)
+) (status string, reason string) {
+	// The action catalog sets the trust each action requires
+	if trustScore < action.RequiredTrust {
+		return "denied", fmt.Sprintf("Trust score %.2f below required %.2f for action %s", trustScore, action.RequiredTrust, actionType)
+	}
+
+	// Actions the catalog flags also need a human decision
+	if action.RequiresApproval && h.verificationApprovalService != nil {
+		return "pending", fmt.Sprintf("Action %s is %s risk and requires human approval", actionType, action.RiskTier)
 	}
 
 	// Auto-approve
@@ -468,10 +550,11 @@ func (h *VerificationHandler) determineVerificationStatus(
 
 // GetVerification retrieves verification status by ID
 // @Summary Get verification status
-// @Description Retrieve the status of a verification request by ID
+// @Description Retrieve the status of a verification request by ID. For requests awaiting human approval, wait long-polls up to 25 seconds for a decision.
 // @Tags verifications
 // @Produce json
 // @Param id path string true "Verification ID (UUID)"
+// @Param wait query int false "Seconds to wait for a pending approval to be decided"
 // @Success 200 {object} VerificationResponse "Verification found"
 // @Failure 400 {object} ErrorResponse "Invalid verification ID"
 // @Failure 404 {object} ErrorResponse "Verification not found"
@@ -493,6 +576,16 @@ func (h *VerificationHandler) GetVerification(c fiber.Ctx) error {
 		})
 	}
 
+	// Requests parked for human approval are answered from the approval queue
+	if orgID, ok := c.Locals("organization_id").(uuid.UUID); ok && h.verificationApprovalService != nil {
+		waitSeconds, _ := strconv.Atoi(c.Query("wait", "0"))
+		wait := time.Duration(waitSeconds) * time.Second
+		approval, err := h.verificationApprovalService.Wait(c.Context(), orgID, vid, wait)
+		if err == nil {
+			return c.Status(fiber.StatusOK).JSON(approvalVerificationResponse(approval))
+		}
+	}
+
 	// Query verification event from database
 	event, err := h.verificationEventService.GetVerificationEvent(c.Context(), vid)
 	if err != nil {
@@ -531,6 +624,29 @@ func (h *VerificationHandler) GetVerification(c fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// approvalVerificationResponse maps a queued approval to the SDK's verification response
+func approvalVerificationResponse(approval *domain.VerificationApproval) VerificationResponse {
+	response := VerificationResponse{
+		ID:         approval.ID.String(),
+		Status:     string(approval.Status),
+		TrustScore: approval.TrustScore,
+	}
+
+	switch approval.Status {
+	case domain.VerificationApprovalPending:
+		response.ExpiresAt = approval.ExpiresAt
+	case domain.VerificationApprovalApproved:
+		response.ApprovedBy = approval.DecidedByEmail
+		if approval.DecidedAt != nil {
+			response.ExpiresAt = approval.DecidedAt.Add(24 * time.Hour)
+		}
+	case domain.VerificationApprovalDenied, domain.VerificationApprovalTimeout:
+		response.DenialReason = approval.DecisionReason
+	}
+
+	return response
+}
+
 // SubmitVerificationResult handles verification result submission
 // @Summary Submit verification result
 // @Description Submit the result of a verification request (success/failure)
@@ -601,6 +717,11 @@ func (h *VerificationHandler) SubmitVerificationResult(c fiber.Ctx) error {
 		})
 	}
 
+	// Account for the bytes the action read or exported (metadata.bytes_read, metadata.bytes_exported)
+	if h.dataExfiltrationService != nil && application.DataTransferFromMetadata(req.Metadata) != nil {
+		h.recordResultTransfer(c, vid, req.Metadata)
+	}
+
 	// Return success response
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"id":     vid.String(),
@@ -609,6 +730,29 @@ func (h *VerificationHandler) SubmitVerificationResult(c fiber.Ctx) error {
 	})
 }
 
+// recordResultTransfer records the bytes reported with a verification result against the
+// verification's agent
+func (h *VerificationHandler) recordResultTransfer(c fiber.Ctx, verificationID uuid.UUID, metadata map[string]interface{}) {
+	event, err := h.verificationEventService.GetVerificationEvent(c.Context(), verificationID)
+	if err != nil || event.AgentID == nil {
+		return
+	}
+	agent, err := h.agentService.GetAgent(c.Context(), *event.AgentID)
+	if err != nil {
+		return
+	}
+
+	resource := ""
+	if event.ResourceType != nil {
+		resource = *event.ResourceType
+	}
+	if _, err := h.dataExfiltrationService.RecordReportedTransfer(
+		c.Context(), agent, domain.DataTransferSourceVerificationResult, resource, nil, metadata,
+	); err != nil {
+		fmt.Printf("⚠️  Failed to record data transfer for agent %s: %v\n", agent.Name, err)
+	}
+}
+
 // determineAlertSeverity determines the alert severity based on action type and context
 func (h *VerificationHandler) determineAlertSeverity(actionType string, context map[string]interface{}, riskLevel string) domain.AlertSeverity {
 	// 1. Check explicit risk_level from context or request
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// WorkloadIdentityHandler manages the SPIFFE IDs agents authenticate as and the X.509-SVIDs
+// AIM's CA issues to agents
+type WorkloadIdentityHandler struct {
+	workloadIdentityService *application.WorkloadIdentityService
+	auditService            *application.AuditService
+}
+
+// NewWorkloadIdentityHandler creates a new workload identity handler
+func NewWorkloadIdentityHandler(
+	workloadIdentityService *application.WorkloadIdentityService,
+	auditService *application.AuditService,
+) *WorkloadIdentityHandler {
+	return &WorkloadIdentityHandler{
+		workloadIdentityService: workloadIdentityService,
+		auditService:            auditService,
+	}
+}
+
+// CreateSPIFFEBindingRequest is a request to bind a SPIFFE ID pattern to an agent
+type CreateSPIFFEBindingRequest struct {
+	SPIFFEIDPattern string `json:"spiffeIdPattern"` // e.g. spiffe://prod.example.org/ns/payments/sa/*
+}
+
+// IssueCertificateRequest is a request for an agent X.509-SVID
+type IssueCertificateRequest struct {
+	CSR string `json:"csr"` // PEM certificate signing request
+}
+
+// ListBindings lists the SPIFFE ID patterns bound to an agent
+// @Summary List SPIFFE bindings
+// @Tags agents
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/spiffe-bindings [get]
+func (h *WorkloadIdentityHandler) ListBindings(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	bindings, err := h.workloadIdentityService.ListBindings(c.Context(), orgID, agentID)
+	if err != nil {
+		if err.Error() == "agent not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list SPIFFE bindings",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"bindings": bindings,
+		"total":    len(bindings),
+	})
+}
+
+// CreateBinding binds a SPIFFE ID pattern to an agent
+// @Summary Bind SPIFFE ID
+// @Description Lets workloads whose X.509-SVID or JWT-SVID carries a matching SPIFFE ID authenticate as the agent. "*" matches within one path segment. A trust bundle must be configured for the pattern's trust domain
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Param request body CreateSPIFFEBindingRequest true "SPIFFE ID pattern"
+// @Success 201 {object} domain.SPIFFEBinding
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/spiffe-bindings [post]
+func (h *WorkloadIdentityHandler) CreateBinding(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	var req CreateSPIFFEBindingRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	binding, err := h.workloadIdentityService.CreateBinding(c.Context(), orgID, agentID, userID, req.SPIFFEIDPattern)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrInvalidSPIFFEBinding):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case err.Error() == "agent not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create SPIFFE binding",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionCreate,
+		"spiffe_binding",
+		agentID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"binding_id":        binding.ID,
+			"spiffe_id_pattern": binding.SPIFFEIDPattern,
+		},
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(binding)
+}
+
+// DeleteBinding removes a SPIFFE ID pattern from an agent
+// @Summary Remove SPIFFE binding
+// @Tags agents
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Param bindingId path string true "Binding ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/spiffe-bindings/{bindingId} [delete]
+func (h *WorkloadIdentityHandler) DeleteBinding(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+	userID := c.Locals("user_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+	bindingID, err := uuid.Parse(c.Params("bindingId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid binding ID",
+		})
+	}
+
+	if err := h.workloadIdentityService.DeleteBinding(c.Context(), orgID, agentID, bindingID); err != nil {
+		if errors.Is(err, application.ErrSPIFFEBindingNotFound) || err.Error() == "agent not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete SPIFFE binding",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionDelete,
+		"spiffe_binding",
+		agentID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"binding_id": bindingID,
+		},
+	)
+
+	return c.JSON(fiber.Map{
+		"message": "SPIFFE binding removed",
+	})
+}
+
+// IssueCertificate issues a short-lived X.509-SVID to an agent
+// @Summary Issue agent certificate
+// @Description Signs the CSR's public key into an X.509-SVID (spiffe://<trust domain>/agents/<agent ID>) from AIM's CA and points the agent's certificateUrl at it. Agents renew their own certificate; managers may issue one for any agent in their organization. Only verified agents get certificates
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Param id path string true "Agent ID"
+// @Param request body IssueCertificateRequest true "PEM certificate signing request"
+// @Success 201 {object} domain.AgentCertificate
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/certificate [post]
+func (h *WorkloadIdentityHandler) IssueCertificate(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	// Agents may only renew their own certificate; users need manager access
+	userID := uuid.Nil
+	if authenticatedAgentID, ok := c.Locals("agent_id").(uuid.UUID); ok {
+		if authenticatedAgentID != agentID {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Agents can only request their own certificate",
+			})
+		}
+	} else {
+		role, _ := c.Locals("role").(string)
+		if role != string(domain.RoleAdmin) && role != string(domain.RoleManager) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Manager or admin access required",
+			})
+		}
+		userID = c.Locals("user_id").(uuid.UUID)
+	}
+
+	var req IssueCertificateRequest
+	if err := c.Bind().JSON(&req); err != nil || req.CSR == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "csr is required",
+		})
+	}
+
+	certificate, err := h.workloadIdentityService.IssueCertificate(c.Context(), orgID, agentID, req.CSR)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrAgentCANotEnabled):
+			return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrInvalidCSR):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrAgentCertificateUnavailable):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		case err.Error() == "agent not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to issue certificate",
+		})
+	}
+
+	h.auditService.LogAction(
+		c.Context(),
+		orgID,
+		userID,
+		domain.AuditActionCreate,
+		"agent_certificate",
+		agentID,
+		c.IP(),
+		c.Get("User-Agent"),
+		map[string]interface{}{
+			"serial_number": certificate.SerialNumber,
+			"spiffe_id":     certificate.SPIFFEID,
+			"not_after":     certificate.NotAfter,
+		},
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(certificate)
+}
+
+// CACertificate serves the certificate of AIM's agent CA
+// @Summary Agent CA certificate
+// @Description Trust anchor for X.509-SVIDs issued to agents; add it to the SPIFFE trust bundle of AIM's trust domain
+// @Tags pki
+// @Produce application/x-pem-file
+// @Success 200 {string} string "PEM certificate"
+// @Failure 404 {object} ErrorResponse
+// @Router /pki/ca.pem [get]
+func (h *WorkloadIdentityHandler) CACertificate(c fiber.Ctx) error {
+	certificatePEM, err := h.workloadIdentityService.CACertificatePEM()
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Set("Content-Type", "application/x-pem-file")
+	c.Set("Cache-Control", "public, max-age=3600")
+	return c.Send(certificatePEM)
+}
+
+// AgentCertificate serves an agent's current certificate
+// @Summary Agent certificate
+// @Description The agent's current X.509-SVID; this is the agent's certificateUrl
+// @Tags agents
+// @Produce application/x-pem-file
+// @Param id path string true "Agent ID"
+// @Success 200 {string} string "PEM certificate"
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/agents/{id}/certificate [get]
+func (h *WorkloadIdentityHandler) AgentCertificate(c fiber.Ctx) error {
+	orgID := c.Locals("organization_id").(uuid.UUID)
+
+	agentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid agent ID",
+		})
+	}
+
+	certificate, err := h.workloadIdentityService.CurrentCertificate(c.Context(), orgID, agentID)
+	if err != nil {
+		if errors.Is(err, application.ErrAgentCertificateNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Certificate not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load certificate",
+		})
+	}
+
+	c.Set("Content-Type", "application/x-pem-file")
+	c.Set("Cache-Control", "private, no-store")
+	return c.SendString(certificate.CertificatePEM)
+}
@@ -15,9 +15,9 @@ func AuthMiddleware(jwtService *auth.JWTService) fiber.Handler {
 		// Check if already authenticated by Ed25519 middleware
 		authenticatedVia := c.Locals("authenticated_via")
 		fmt.Printf("🔒 JWT middleware: authenticated_via = %v\n", authenticatedVia)
-		if authenticatedVia == "ed25519" {
+		if authenticatedVia == "ed25519" || authenticatedVia == "spiffe" {
 			// Already authenticated - skip JWT validation
-			fmt.Printf("✅ JWT middleware: Skipping JWT - Ed25519 already authenticated\n")
+			fmt.Printf("✅ JWT middleware: Skipping JWT - agent already authenticated via %v\n", authenticatedVia)
 			return c.Next()
 		}
 
@@ -74,6 +74,7 @@ func AuthMiddleware(jwtService *auth.JWTService) fiber.Handler {
 		c.Locals("organization_id", organizationID)
 		c.Locals("email", claims.Email)
 		c.Locals("role", claims.Role)
+		c.Locals("session_id", claims.SessionID)
 
 		return c.Next()
 	}
@@ -76,8 +76,16 @@ func sortValue(v interface{}) interface{} {
 // - X-Signature: Base64-encoded Ed25519 signature
 // - X-Timestamp: Unix timestamp of request
 // - X-Public-Key: Agent's Ed25519 public key (base64)
-func Ed25519AgentMiddleware(agentService *application.AgentService) fiber.Handler {
+// When workloadIdentity is set, agents running as SPIFFE workloads may instead present an
+// X.509-SVID client certificate or a JWT-SVID bearer token (X-Agent-ID is then optional)
+func Ed25519AgentMiddleware(agentService *application.AgentService, workloadIdentity *application.WorkloadIdentityService) fiber.Handler {
 	return func(c fiber.Ctx) error {
+		if workloadIdentity != nil {
+			if handled, err := authenticateSVID(c, workloadIdentity); handled {
+				return err
+			}
+		}
+
 		// If Authorization header is present (JWT), skip Ed25519 and let JWT middleware handle it
 		// This is critical for key registration workflow where SDK needs JWT auth before Ed25519
 		authHeader := c.Get("Authorization")
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+)
+
+// OrganizationScopeHeader selects a descendant organization to act on
+const OrganizationScopeHeader = "X-Organization-ID"
+
+// OrganizationScopeMiddleware lets users act on any organization in their organization's
+// subtree by sending its ID in the X-Organization-ID header. Admins keep their role there;
+// everyone else may only read.
+// The user's own organization is kept in the "home_organization_id" local.
+// Must be used AFTER AuthMiddleware
+func OrganizationScopeMiddleware(hierarchyService *application.OrganizationHierarchyService) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		homeOrgID, ok := c.Locals("organization_id").(uuid.UUID)
+		if !ok {
+			return c.Next()
+		}
+		c.Locals("home_organization_id", homeOrgID)
+
+		header := c.Get(OrganizationScopeHeader)
+		// Agents authenticated by signature or SVID always act on their own organization
+		if header == "" || c.Locals("authenticated_via") == "ed25519" || c.Locals("authenticated_via") == "spiffe" {
+			return c.Next()
+		}
+
+		targetOrgID, err := uuid.Parse(header)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid " + OrganizationScopeHeader + " header",
+			})
+		}
+		if targetOrgID == homeOrgID {
+			return c.Next()
+		}
+
+		inScope, err := hierarchyService.IsWithinScope(c.Context(), homeOrgID, targetOrgID)
+		if err != nil {
+			fmt.Printf("⚠️  Organization scope check failed for %s -> %s: %v\n", homeOrgID, targetOrgID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check organization scope",
+			})
+		}
+		if !inScope {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": application.ErrOutsideOrganizationScope.Error(),
+			})
+		}
+
+		if role, _ := c.Locals("role").(string); role != string(domain.RoleAdmin) && !isReadOnlyMethod(c.Method()) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Only admins can make changes in descendant organizations",
+			})
+		}
+
+		c.Locals("organization_id", targetOrgID)
+		return c.Next()
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == fiber.MethodGet || method == fiber.MethodHead || method == fiber.MethodOptions
+}
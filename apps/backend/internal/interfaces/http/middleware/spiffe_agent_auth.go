@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"github.com/opena2a/identity/backend/internal/application"
+	"github.com/opena2a/identity/backend/internal/domain"
+	"github.com/opena2a/identity/backend/internal/infrastructure/spiffe"
+)
+
+// authenticateSVID authenticates an agent by a JWT-SVID bearer token or an X.509-SVID client
+// certificate. It reports whether the request carried an SVID; if so the request has been
+// answered or passed on.
+func authenticateSVID(c fiber.Ctx, workloadIdentity *application.WorkloadIdentityService) (bool, error) {
+	var method string
+	var token string
+	var chain []*x509.Certificate
+	if bearer, ok := strings.CutPrefix(c.Get("Authorization"), "Bearer "); ok && spiffe.IsJWTSVID(bearer) {
+		method, token = "JWT-SVID", bearer
+	} else if chain = clientCertificates(c, workloadIdentity.ForwardedCertificateHeader(c.Context().RemoteIP())); len(chain) > 0 {
+		method = "X.509-SVID"
+	} else {
+		return false, nil
+	}
+
+	// X-Agent-ID is optional; it picks the agent when the SPIFFE ID is bound to several
+	var agentHint *uuid.UUID
+	if header := c.Get("X-Agent-ID"); header != "" {
+		agentID, err := uuid.Parse(header)
+		if err != nil {
+			return true, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid agent ID format",
+			})
+		}
+		agentHint = &agentID
+	}
+
+	var agent *domain.Agent
+	var id spiffe.ID
+	var err error
+	if token != "" {
+		agent, id, err = workloadIdentity.AuthenticateJWTSVID(c.Context(), token, agentHint)
+	} else {
+		agent, id, err = workloadIdentity.AuthenticateX509SVID(c.Context(), chain, agentHint)
+	}
+	if err != nil {
+		fmt.Printf("❌ %s authentication FAILED: %v\n", method, err)
+		return true, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	fmt.Printf("✅ %s authentication PASSED for agent %s (%s)\n", method, agent.ID, id)
+
+	c.Locals("agent_id", agent.ID)
+	c.Locals("organization_id", agent.OrganizationID)
+	c.Locals("authenticated_via", "spiffe")
+	c.Locals("auth_method", "spiffe")
+	c.Locals("spiffe_id", id.String())
+
+	return true, c.Next()
+}
+
+// clientCertificates returns the client's certificate chain if its leaf is an X.509-SVID: from
+// the TLS connection, or from the header a trusted TLS-terminating proxy forwards it in
+// (forwardedHeader is empty unless the connection comes from such a proxy)
+func clientCertificates(c fiber.Ctx, forwardedHeader string) []*x509.Certificate {
+	var chain []*x509.Certificate
+	if state := c.Context().TLSConnectionState(); state != nil && len(state.PeerCertificates) > 0 {
+		chain = state.PeerCertificates
+	} else if forwardedHeader != "" && c.Get(forwardedHeader) != "" {
+		// Percent-encoded PEM, as nginx's $ssl_client_escaped_cert
+		decoded, err := url.PathUnescape(c.Get(forwardedHeader))
+		if err != nil {
+			return nil
+		}
+		if chain, err = spiffe.ParseCertificatesPEM([]byte(decoded)); err != nil {
+			return nil
+		}
+	}
+
+	if len(chain) == 0 {
+		return nil
+	}
+	for _, uri := range chain[0].URIs {
+		if uri.Scheme == "spiffe" {
+			return chain
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+-- Migration: Create mcp_tool_surface_snapshots table for MCP rug-pull detection
+-- Created: 2026-10-18
+-- Purpose: Keep a versioned, hashed history of every MCP server tool surface so that
+--          silently added tools or rewritten descriptions/schemas are detected as drift
+
+CREATE TABLE IF NOT EXISTS mcp_tool_surface_snapshots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    mcp_server_id UUID NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+
+    -- SHA-256 over all tool fingerprints (order independent)
+    surface_hash VARCHAR(64) NOT NULL,
+
+    -- Per-tool fingerprints:
+    -- [{"name": "read_file", "type": "tool", "description_hash": "...", "schema_hash": "...", "hash": "..."}]
+    tools JSONB NOT NULL DEFAULT '[]'::jsonb,
+
+    -- Changes relative to the approved snapshot at discovery time (NULL for baselines)
+    diff JSONB,
+
+    -- Review state
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (
+        status IN ('approved', 'pending', 'rejected')
+    ),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    review_comment TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE(mcp_server_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mcp_tool_surface_snapshots_server ON mcp_tool_surface_snapshots(mcp_server_id, version DESC);
+CREATE INDEX IF NOT EXISTS idx_mcp_tool_surface_snapshots_status ON mcp_tool_surface_snapshots(mcp_server_id, status);
+
+COMMENT ON TABLE mcp_tool_surface_snapshots IS 'Versioned hashes of MCP server tool names, descriptions and schemas for rug-pull detection';
+COMMENT ON COLUMN mcp_tool_surface_snapshots.surface_hash IS 'SHA-256 over all tool fingerprints, identical for identical tool surfaces';
+COMMENT ON COLUMN mcp_tool_surface_snapshots.status IS 'approved = trusted baseline, pending = drift awaiting admin review, rejected = refused by admin';
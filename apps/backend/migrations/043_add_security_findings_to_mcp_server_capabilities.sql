@@ -0,0 +1,19 @@
+-- Migration: Add metadata scanner results to mcp_server_capabilities
+-- Created: 2026-10-18
+-- Purpose: Store prompt-injection / tool-poisoning findings detected in MCP tool names,
+--          descriptions and schemas at discovery time
+
+ALTER TABLE mcp_server_capabilities
+    ADD COLUMN IF NOT EXISTS security_findings JSONB NOT NULL DEFAULT '[]'::jsonb,
+    ADD COLUMN IF NOT EXISTS risk_level VARCHAR(20) NOT NULL DEFAULT 'none' CHECK (
+        risk_level IN ('none', 'low', 'medium', 'high', 'critical')
+    ),
+    ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_mcp_server_capabilities_risk_level
+    ON mcp_server_capabilities(mcp_server_id, risk_level)
+    WHERE risk_level IN ('high', 'critical');
+
+COMMENT ON COLUMN mcp_server_capabilities.security_findings IS 'Metadata scanner findings: [{"rule_id", "category", "severity", "field", "message", "evidence"}]';
+COMMENT ON COLUMN mcp_server_capabilities.risk_level IS 'Worst finding severity: none, low, medium, high, critical';
+COMMENT ON COLUMN mcp_server_capabilities.scanned_at IS 'When the metadata scanner last analyzed this capability';
@@ -0,0 +1,46 @@
+-- Migration: Create MCP server health monitoring tables
+-- Created: 2026-10-18
+-- Purpose: Record the outcome of every scheduled MCP server re-verification (reachability
+--          probe + Ed25519 challenge-response) and keep per-server health state so servers
+--          can be downgraded after consecutive failures and restored when they recover
+
+CREATE TABLE IF NOT EXISTS mcp_server_health_checks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    mcp_server_id UUID NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+
+    -- Probe results
+    reachable BOOLEAN NOT NULL,
+    signature_valid BOOLEAN,          -- NULL when the server has no public key to challenge
+    success BOOLEAN NOT NULL,
+    latency_ms INT NOT NULL DEFAULT 0,
+    http_status INT,
+    error_reason TEXT,
+
+    checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mcp_server_health_checks_server ON mcp_server_health_checks(mcp_server_id, checked_at DESC);
+CREATE INDEX IF NOT EXISTS idx_mcp_server_health_checks_org ON mcp_server_health_checks(organization_id, checked_at DESC);
+
+CREATE TABLE IF NOT EXISTS mcp_server_health (
+    mcp_server_id UUID PRIMARY KEY REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    health_status VARCHAR(20) NOT NULL DEFAULT 'unknown' CHECK (
+        health_status IN ('healthy', 'degraded', 'unhealthy', 'unknown')
+    ),
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    last_checked_at TIMESTAMPTZ,
+    last_success_at TIMESTAMPTZ,
+    last_failure_reason TEXT,
+
+    -- Set when the health monitor downgraded the server from verified; cleared on recovery
+    downgraded_at TIMESTAMPTZ,
+
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mcp_server_health_downgraded ON mcp_server_health(downgraded_at) WHERE downgraded_at IS NOT NULL;
+
+COMMENT ON TABLE mcp_server_health_checks IS 'History of scheduled MCP server re-verification runs, used for uptime calculation';
+COMMENT ON TABLE mcp_server_health IS 'Current health state of each monitored MCP server';
+COMMENT ON COLUMN mcp_server_health.consecutive_failures IS 'Failed checks since the last successful one; the server is downgraded once this reaches the failure threshold';
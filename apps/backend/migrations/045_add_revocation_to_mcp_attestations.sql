@@ -0,0 +1,14 @@
+-- Migration: Add revocation tracking to MCP attestations
+-- Created: 2026-10-18
+-- Purpose: Allow admins to revoke a single attestation (or every attestation made by a
+--          compromised agent) and keep who revoked it and why for the audit trail
+
+ALTER TABLE mcp_attestations ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+ALTER TABLE mcp_attestations ADD COLUMN IF NOT EXISTS revoked_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE mcp_attestations ADD COLUMN IF NOT EXISTS revocation_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_mcp_attestations_revoked ON mcp_attestations(revoked_at) WHERE revoked_at IS NOT NULL;
+
+COMMENT ON COLUMN mcp_attestations.revoked_at IS 'When an admin revoked this attestation (NULL if never revoked)';
+COMMENT ON COLUMN mcp_attestations.revoked_by IS 'User who revoked this attestation';
+COMMENT ON COLUMN mcp_attestations.revocation_reason IS 'Admin-supplied reason for the revocation (e.g. compromised agent)';
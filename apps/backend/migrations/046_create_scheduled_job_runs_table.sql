@@ -0,0 +1,27 @@
+-- Migration: Create scheduled job run history
+-- Created: 2026-10-18
+-- Purpose: Record every execution of the in-process maintenance job scheduler (attestation
+--          expiry, confidence recalculation, SDK token cleanup, proactive alert checks, MCP
+--          health checks) with duration, outcome and the replica that ran it
+
+CREATE TABLE IF NOT EXISTS scheduled_job_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name VARCHAR(100) NOT NULL,
+    trigger VARCHAR(20) NOT NULL DEFAULT 'scheduled' CHECK (trigger IN ('scheduled', 'manual')),
+    triggered_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+    scheduled_for TIMESTAMPTZ,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    duration_ms BIGINT,
+    error TEXT,
+    instance_id VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_job_runs_job_started ON scheduled_job_runs(job_name, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_scheduled_job_runs_job_scheduled ON scheduled_job_runs(job_name, scheduled_for) WHERE scheduled_for IS NOT NULL;
+
+COMMENT ON TABLE scheduled_job_runs IS 'Run history for scheduled maintenance jobs (one row per execution)';
+COMMENT ON COLUMN scheduled_job_runs.trigger IS 'scheduled (cron) or manual (triggered by an admin)';
+COMMENT ON COLUMN scheduled_job_runs.scheduled_for IS 'Cron slot this run belongs to; used so only one replica runs each slot';
+COMMENT ON COLUMN scheduled_job_runs.instance_id IS 'Replica (hostname:pid) that executed the job';
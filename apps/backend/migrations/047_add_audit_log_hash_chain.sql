@@ -0,0 +1,47 @@
+-- Migration: Tamper-evident hash-chained audit log
+-- Created: 2026-10-18
+-- Purpose: Link every audit record to its predecessor with a SHA-256 hash, forming one chain per
+--          organization, and store Ed25519-signed checkpoints of the chain head so edits,
+--          deletions and truncation of audit history can be detected.
+--          Rows written before this migration stay unchained (sequence_number IS NULL).
+
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS sequence_number BIGINT;
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS prev_hash VARCHAR(64);
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS entry_hash VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_logs_org_sequence
+    ON audit_logs(organization_id, sequence_number)
+    WHERE sequence_number IS NOT NULL;
+
+COMMENT ON COLUMN audit_logs.sequence_number IS 'Position in the organization audit chain (1-based, gapless)';
+COMMENT ON COLUMN audit_logs.prev_hash IS 'entry_hash of the previous record in the chain (64 zeros for the first record)';
+COMMENT ON COLUMN audit_logs.entry_hash IS 'SHA-256 over the canonical record including prev_hash';
+
+-- Chain head per organization; appends lock this row so sequence numbers stay gapless
+CREATE TABLE IF NOT EXISTS audit_log_chain_heads (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    last_sequence BIGINT NOT NULL DEFAULT 0,
+    last_hash VARCHAR(64) NOT NULL,
+    chain_started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE audit_log_chain_heads IS 'Latest sequence number and hash of each organization audit chain';
+
+-- Signed checkpoints of the chain head
+CREATE TABLE IF NOT EXISTS audit_log_checkpoints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    sequence_number BIGINT NOT NULL,
+    entry_hash VARCHAR(64) NOT NULL,
+    key_id VARCHAR(64) NOT NULL,
+    public_key TEXT NOT NULL,
+    signature TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(organization_id, sequence_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_checkpoints_org_sequence ON audit_log_checkpoints(organization_id, sequence_number DESC);
+
+COMMENT ON TABLE audit_log_checkpoints IS 'Ed25519-signed snapshots of audit chain heads (anchors for tamper detection)';
+COMMENT ON COLUMN audit_log_checkpoints.key_id IS 'Fingerprint of the signing key (first 16 bytes of SHA-256 of the public key, hex)';
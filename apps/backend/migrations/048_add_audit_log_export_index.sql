@@ -0,0 +1,8 @@
+-- Migration: Audit log export keyset index
+-- Created: 2026-10-18
+-- Purpose: Streamed audit log exports page through an organization's records in
+--          (timestamp, id) order; this index keeps each page an index range scan.
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_org_timestamp_id ON audit_logs(organization_id, timestamp, id);
+
+COMMENT ON INDEX idx_audit_logs_org_timestamp_id IS 'Keyset pagination for streamed audit log exports';
@@ -0,0 +1,33 @@
+-- Migration: SIEM sinks
+-- Created: 2026-10-18
+-- Purpose: Per-organization SIEM destinations that receive audit records, alerts and
+--          verification events in near real time (syslog RFC 5424 with CEF/LEEF,
+--          Splunk HEC, or NDJSON over HTTP).
+
+CREATE TABLE IF NOT EXISTS siem_sinks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(32) NOT NULL CHECK (type IN ('syslog', 'splunk_hec', 'http_ndjson')),
+    endpoint TEXT NOT NULL,
+    format VARCHAR(16) CHECK (format IN ('cef', 'leef', 'json')),
+    use_tls BOOLEAN NOT NULL DEFAULT false,
+    tls_ca_cert TEXT,
+    token TEXT,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    min_severity INTEGER NOT NULL DEFAULT 0 CHECK (min_severity BETWEEN 0 AND 10),
+    buffer_size INTEGER NOT NULL DEFAULT 10000 CHECK (buffer_size > 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_siem_sinks_organization_id ON siem_sinks(organization_id);
+CREATE INDEX IF NOT EXISTS idx_siem_sinks_active ON siem_sinks(is_active) WHERE is_active = true;
+
+COMMENT ON TABLE siem_sinks IS 'Per-organization SIEM forwarding destinations';
+COMMENT ON COLUMN siem_sinks.endpoint IS 'host:port for syslog sinks, URL for HTTP sinks';
+COMMENT ON COLUMN siem_sinks.event_types IS 'Forwarded event types (audit, alert, verification); empty means all';
+COMMENT ON COLUMN siem_sinks.min_severity IS 'Minimum event severity on the CEF 0-10 scale';
+COMMENT ON COLUMN siem_sinks.buffer_size IS 'In-memory queue capacity; events are dropped (and counted) when it is full';
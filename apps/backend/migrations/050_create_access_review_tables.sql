@@ -0,0 +1,77 @@
+-- Migration: Access review campaigns
+-- Created: 2026-10-18
+-- Purpose: Periodic access reviews. A campaign assigns every user role, agent capability grant
+--          and API key in scope to a reviewer, who certifies or revokes it before the due date.
+--          Completed campaigns are sealed as evidence rows that cannot be updated or deleted.
+
+CREATE TABLE IF NOT EXISTS access_review_campaigns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    scope TEXT[] NOT NULL,
+    default_reviewer_id UUID NOT NULL REFERENCES users(id),
+    assign_agent_owners BOOLEAN NOT NULL DEFAULT FALSE,
+    auto_revoke_on_expiry BOOLEAN NOT NULL DEFAULT FALSE,
+    reminder_days_before INTEGER NOT NULL DEFAULT 3,
+    due_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'cancelled')),
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_review_campaigns_org ON access_review_campaigns(organization_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_access_review_campaigns_active ON access_review_campaigns(due_at) WHERE status = 'active';
+
+COMMENT ON TABLE access_review_campaigns IS 'Periodic access certification campaigns';
+
+CREATE TABLE IF NOT EXISTS access_review_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES access_review_campaigns(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    item_type VARCHAR(30) NOT NULL CHECK (item_type IN ('user_role', 'capability_grant', 'api_key')),
+    resource_id UUID NOT NULL,
+    agent_id UUID,
+    subject VARCHAR(255) NOT NULL,
+    access VARCHAR(255) NOT NULL,
+    reviewer_id UUID NOT NULL REFERENCES users(id),
+    decision VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (decision IN ('pending', 'certified', 'revoked')),
+    decided_by UUID REFERENCES users(id),
+    decided_at TIMESTAMPTZ,
+    justification TEXT,
+    revocation_error TEXT,
+    last_reminded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(campaign_id, item_type, resource_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_review_items_campaign ON access_review_items(campaign_id, decision);
+CREATE INDEX IF NOT EXISTS idx_access_review_items_reviewer ON access_review_items(reviewer_id) WHERE decision = 'pending';
+
+COMMENT ON TABLE access_review_items IS 'Individual accesses under review and the attestation recorded for each';
+
+CREATE TABLE IF NOT EXISTS access_review_evidence (
+    campaign_id UUID PRIMARY KEY REFERENCES access_review_campaigns(id),
+    organization_id UUID NOT NULL REFERENCES organizations(id),
+    snapshot TEXT NOT NULL,
+    sha256 VARCHAR(64) NOT NULL,
+    key_id VARCHAR(64),
+    signature TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE access_review_evidence IS 'Immutable, signed snapshots of completed access review campaigns';
+COMMENT ON COLUMN access_review_evidence.snapshot IS 'Campaign and decisions as JSON; kept as TEXT so the signed bytes are preserved exactly';
+
+-- Evidence is append-only
+CREATE OR REPLACE FUNCTION prevent_access_review_evidence_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'access review evidence is immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS access_review_evidence_immutable ON access_review_evidence;
+CREATE TRIGGER access_review_evidence_immutable
+    BEFORE UPDATE OR DELETE ON access_review_evidence
+    FOR EACH ROW EXECUTE FUNCTION prevent_access_review_evidence_change();
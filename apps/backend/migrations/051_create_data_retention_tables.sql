@@ -0,0 +1,48 @@
+-- Migration: Data retention policies and legal holds
+-- Created: 2026-10-18
+-- Purpose: Per-organization retention periods for high-volume tables (audit logs, verification
+--          events, acknowledged alerts, detections, webhook deliveries), legal holds that suspend
+--          purging, and a pruned-through anchor on audit chain heads so a purged chain still
+--          verifies from the signed checkpoint it was cut at.
+--          audit_logs.user_id loses its foreign key: scheduled purges are recorded with the nil
+--          UUID as actor, and deleting a user must not cascade into the audit chain.
+
+CREATE TABLE IF NOT EXISTS data_retention_rules (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    table_name VARCHAR(50) NOT NULL CHECK (table_name IN (
+        'audit_logs', 'verification_events', 'alerts', 'detections', 'webhook_deliveries'
+    )),
+    retention_days INTEGER NOT NULL CHECK (retention_days > 0),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, table_name)
+);
+
+COMMENT ON TABLE data_retention_rules IS 'Per-organization overrides of the built-in retention periods';
+
+CREATE TABLE IF NOT EXISTS data_legal_holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    table_name VARCHAR(50), -- NULL holds every table
+    reason TEXT NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    released_by UUID REFERENCES users(id),
+    released_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_legal_holds_active ON data_legal_holds(organization_id) WHERE released_at IS NULL;
+
+COMMENT ON TABLE data_legal_holds IS 'Legal holds; while active, matching data is never purged';
+
+-- Purges delete in small batches by organization and age
+CREATE INDEX IF NOT EXISTS idx_verification_events_org_created_at ON verification_events(organization_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_alerts_org_created_at ON alerts(organization_id, created_at) WHERE is_acknowledged = TRUE;
+
+ALTER TABLE audit_log_chain_heads ADD COLUMN IF NOT EXISTS pruned_through_sequence BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE audit_log_chain_heads ADD COLUMN IF NOT EXISTS pruned_through_hash VARCHAR(64);
+
+COMMENT ON COLUMN audit_log_chain_heads.pruned_through_sequence IS 'Records up to this sequence may have been purged; verification starts at the signed checkpoint here';
+
+ALTER TABLE audit_logs DROP CONSTRAINT IF EXISTS audit_logs_user_id_fkey;
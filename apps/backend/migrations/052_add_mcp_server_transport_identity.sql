@@ -0,0 +1,37 @@
+-- Migration: Transport-aware MCP server identity
+-- Created: 2026-10-19
+-- Purpose: Local stdio MCP servers have no URL. Record their launch command, args, environment
+--          variable names and package reference (npm, pypi or docker) instead, and identify them
+--          by a hash of those so the same server launched by several agents is one record and a
+--          changed command line is detected as drift.
+--          Servers auto-registered with the old mcp://<name> placeholder URL become stdio servers
+--          without an identity; the next config import of the same name adopts its launch spec.
+
+ALTER TABLE mcp_servers
+    ADD COLUMN IF NOT EXISTS transport VARCHAR(20) NOT NULL DEFAULT 'http'
+        CHECK (transport IN ('stdio', 'sse', 'http')),
+    ADD COLUMN IF NOT EXISTS command TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS args JSONB NOT NULL DEFAULT '[]'::jsonb,
+    ADD COLUMN IF NOT EXISTS env_names JSONB NOT NULL DEFAULT '[]'::jsonb,
+    ADD COLUMN IF NOT EXISTS package_registry VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS package_name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS package_version VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS identity_hash VARCHAR(64);
+
+-- stdio servers store NULL, which UNIQUE(organization_id, url) does not compare
+ALTER TABLE mcp_servers ALTER COLUMN url DROP NOT NULL;
+
+UPDATE mcp_servers
+SET transport = 'stdio', url = NULL
+WHERE url LIKE 'mcp://%';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mcp_servers_org_identity_hash
+    ON mcp_servers(organization_id, identity_hash)
+    WHERE identity_hash IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_mcp_servers_package
+    ON mcp_servers(package_registry, package_name)
+    WHERE package_name <> '';
+
+COMMENT ON COLUMN mcp_servers.transport IS 'stdio (local process), sse or http (remote)';
+COMMENT ON COLUMN mcp_servers.env_names IS 'Names of environment variables passed to stdio servers; values are never stored';
+COMMENT ON COLUMN mcp_servers.identity_hash IS 'SHA-256 of the canonical launch spec of stdio servers';
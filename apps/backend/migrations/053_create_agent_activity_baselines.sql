@@ -0,0 +1,25 @@
+-- Migration: Agent activity baselines for anomaly detection
+-- Created: 2026-10-19
+-- Purpose: Learned per-agent activity profile (hourly volume, hour-of-day distribution, action mix,
+--          resources, MCP servers and source IPs) that verify-action and verification requests are
+--          compared against. Deviations are recorded in security_anomalies.
+--          security_anomalies.severity is widened to the alert severities (info, warning) the
+--          application uses.
+
+CREATE TABLE IF NOT EXISTS agent_activity_baselines (
+    agent_id UUID PRIMARY KEY REFERENCES agents(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    profile JSONB NOT NULL,
+    total_events BIGINT NOT NULL DEFAULT 0,
+    first_seen_at TIMESTAMPTZ NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_activity_baselines_organization_id ON agent_activity_baselines(organization_id);
+
+ALTER TABLE security_anomalies DROP CONSTRAINT IF EXISTS security_anomalies_severity_check;
+ALTER TABLE security_anomalies ADD CONSTRAINT security_anomalies_severity_check
+    CHECK (severity IN ('info', 'low', 'warning', 'medium', 'high', 'critical'));
+
+COMMENT ON TABLE agent_activity_baselines IS 'Learned normal activity of each agent; see domain.AgentActivityBaseline';
@@ -0,0 +1,25 @@
+-- Migration: Agent data transfer accounting for data exfiltration detection
+-- Created: 2026-10-19
+-- Purpose: Bytes read and exported per agent and resource, reported through verify-action metadata,
+--          action results and SDK-instrumented MCP calls. data_exfiltration policies compare the
+--          rolling-window totals against data_threshold_mb. Rows are pruned once they are older
+--          than the longest policy window.
+
+CREATE TABLE IF NOT EXISTS agent_data_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    agent_id UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    source VARCHAR(50) NOT NULL CHECK (source IN ('verify_action', 'action_result', 'verification_result', 'mcp_call')),
+    resource TEXT,
+    destination TEXT,
+    mcp_server VARCHAR(255),
+    bytes_read BIGINT NOT NULL DEFAULT 0 CHECK (bytes_read >= 0),
+    bytes_exported BIGINT NOT NULL DEFAULT 0 CHECK (bytes_exported >= 0),
+    audit_id UUID,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_data_transfers_agent_occurred ON agent_data_transfers(agent_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_agent_data_transfers_occurred_at ON agent_data_transfers(occurred_at);
+
+COMMENT ON TABLE agent_data_transfers IS 'Bytes read and exported by agents; see domain.DataTransfer';
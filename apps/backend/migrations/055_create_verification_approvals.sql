@@ -0,0 +1,31 @@
+-- Migration: Human-in-the-loop approval queue for high-risk agent actions
+-- Created: 2026-10-19
+-- Purpose: Verifications of high-risk actions (delete_data, execute_command, ...) that pass the
+--          trust check are parked here as pending. Approvers decide from the dashboard or a signed
+--          one-click email link; requests nobody decides before expires_at become 'timeout'.
+--          The row ID is the verification ID the SDK polls.
+
+CREATE TABLE IF NOT EXISTS verification_approvals (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    agent_id UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    verification_event_id UUID,
+    action_type VARCHAR(255) NOT NULL,
+    resource TEXT,
+    context JSONB NOT NULL DEFAULT '{}',
+    trust_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied', 'timeout')),
+    decided_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    decided_via VARCHAR(20) CHECK (decided_via IN ('dashboard', 'link')),
+    decision_reason TEXT,
+    callback_url TEXT,
+    expires_at TIMESTAMPTZ NOT NULL,
+    decided_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_approvals_org_status ON verification_approvals(organization_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_verification_approvals_pending_expiry ON verification_approvals(expires_at) WHERE status = 'pending';
+
+COMMENT ON TABLE verification_approvals IS 'Agent actions awaiting human approval; see domain.VerificationApproval';
@@ -0,0 +1,29 @@
+-- Migration: Per-organization action risk catalog
+-- Created: 2026-10-19
+-- Purpose: Replaces the action names hard-coded in verification and MCP capability detection.
+--          Each entry sets an action's risk tier, the trust it requires, the capability that
+--          authorizes it and whether a human must approve it. Aliases map MCP tool names onto
+--          an entry. The default catalog is seeded by the application when an organization
+--          is created, or on first use for organizations that predate this migration.
+
+CREATE TABLE IF NOT EXISTS action_catalog (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    action VARCHAR(255) NOT NULL,
+    description TEXT,
+    risk_tier VARCHAR(20) NOT NULL CHECK (risk_tier IN ('low', 'medium', 'high')),
+    required_trust DOUBLE PRECISION NOT NULL CHECK (required_trust >= 0 AND required_trust <= 1),
+    trust_multiplier DOUBLE PRECISION NOT NULL DEFAULT 1 CHECK (trust_multiplier > 0 AND trust_multiplier <= 1),
+    required_capability VARCHAR(255),
+    requires_approval BOOLEAN NOT NULL DEFAULT FALSE,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, action)
+);
+
+CREATE INDEX IF NOT EXISTS idx_action_catalog_aliases ON action_catalog USING GIN (aliases);
+
+COMMENT ON TABLE action_catalog IS 'Risk tier, required trust, capability and approval requirement per agent action; see domain.ActionCatalogEntry';
+COMMENT ON COLUMN action_catalog.aliases IS 'Other names for the action, e.g. MCP tool names';
@@ -0,0 +1,17 @@
+-- Migration: Hierarchical organizations
+-- Created: 2026-10-19
+-- Purpose: Lets an organization own child organizations (business units) with their own
+--          admins, agents and policies. Enabled, inheritable security policies of every
+--          ancestor also apply to a child; a child's own policies may only be stricter.
+--          Users of an organization act with their role on its whole subtree.
+
+ALTER TABLE organizations
+    ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES organizations(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_organizations_parent_id ON organizations(parent_id) WHERE parent_id IS NOT NULL;
+
+ALTER TABLE security_policies
+    ADD COLUMN IF NOT EXISTS inheritable BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN organizations.parent_id IS 'Parent organization; NULL for top-level tenants';
+COMMENT ON COLUMN security_policies.inheritable IS 'Whether the policy also applies to descendant organizations';
@@ -0,0 +1,32 @@
+-- Migration: OAuth 2.0 access tokens for agents
+-- Created: 2026-10-19
+-- Purpose: Agents obtain short-lived JWT access tokens with client_credentials (private_key_jwt
+--          signed with their registered Ed25519 key) or RFC 8693 token exchange on behalf of a
+--          user. Issued tokens are recorded for introspection and revocation; client assertion
+--          IDs are recorded until they expire to reject replays.
+
+CREATE TABLE IF NOT EXISTS agent_access_tokens (
+    id UUID PRIMARY KEY,
+    agent_id UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    grant_type VARCHAR(100) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    actor_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    scope TEXT[] NOT NULL DEFAULT '{}',
+    audience TEXT[] NOT NULL DEFAULT '{}',
+    issued_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_access_tokens_agent_id ON agent_access_tokens(agent_id) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_agent_access_tokens_expires_at ON agent_access_tokens(expires_at);
+
+CREATE TABLE IF NOT EXISTS agent_client_assertions (
+    agent_id UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    jti VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (agent_id, jti)
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_client_assertions_expires_at ON agent_client_assertions(expires_at);
@@ -0,0 +1,22 @@
+-- Migration: Asymmetric signing keys for user JWTs
+-- Created: 2026-10-19
+-- Purpose: User access and refresh tokens are signed with rotating EdDSA or ES256 keys published
+--          at /.well-known/jwks.json, so other services can verify AIM-issued identities without
+--          a shared secret. A "next" key is published ahead of use, one "active" key signs, and
+--          "retired" keys stay published until the tokens they signed have expired.
+
+CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+    id VARCHAR(64) PRIMARY KEY, -- RFC 7638 JWK thumbprint, used as the "kid" header
+    algorithm VARCHAR(10) NOT NULL CHECK (algorithm IN ('EdDSA', 'ES256')),
+    public_key TEXT NOT NULL, -- Base64 PKIX DER
+    encrypted_private_key TEXT NOT NULL, -- Base64 PKCS#8 DER encrypted with the key vault master key
+    status VARCHAR(10) NOT NULL CHECK (status IN ('next', 'active', 'retired')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    activated_at TIMESTAMP,
+    retired_at TIMESTAMP,
+    expires_at TIMESTAMP
+);
+
+-- At most one key signs at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jwt_signing_keys_active ON jwt_signing_keys(status) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_jwt_signing_keys_expires_at ON jwt_signing_keys(expires_at) WHERE expires_at IS NOT NULL;
@@ -0,0 +1,27 @@
+-- Migration: Refresh token families
+-- Created: 2026-10-19
+-- Purpose: Every user session refresh token is recorded with its family (the login session it
+--          descends from) and the token it was rotated from. Presenting a token that has already
+--          been rotated means it was copied, so the whole family is revoked. Active families are
+--          listed to users as their signed-in devices.
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY, -- "jti" claim of the refresh token
+    family_id UUID NOT NULL, -- "sid" claim; the id of the family's first token
+    parent_id UUID REFERENCES refresh_tokens(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL, -- SHA-256 of the token
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    revoke_reason TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_active ON refresh_tokens(user_id)
+    WHERE rotated_at IS NULL AND revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
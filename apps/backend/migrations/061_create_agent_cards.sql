@@ -0,0 +1,27 @@
+-- Migration: A2A Agent Cards
+-- Created: 2026-10-19
+-- Purpose: Agents can be published as A2A Agent Cards. A row holds what AIM cannot derive from
+--          the agent itself (its A2A endpoint and declared skills); the card is generated from
+--          it and the agent's current metadata, signed by AIM and served at a public per-agent
+--          URL. Signatures name the row ID, so unpublishing revokes every card issued from it.
+
+CREATE TABLE IF NOT EXISTS agent_cards (
+    id UUID PRIMARY KEY,
+    agent_id UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    url TEXT NOT NULL, -- The agent's A2A service endpoint
+    skills JSONB NOT NULL DEFAULT '[]',
+    streaming BOOLEAN NOT NULL DEFAULT FALSE,
+    push_notifications BOOLEAN NOT NULL DEFAULT FALSE,
+    default_input_modes JSONB NOT NULL DEFAULT '["text/plain"]',
+    default_output_modes JSONB NOT NULL DEFAULT '["text/plain"]',
+    published_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP,
+    revoke_reason TEXT
+);
+
+-- An agent has at most one published card
+CREATE UNIQUE INDEX IF NOT EXISTS idx_agent_cards_active_agent ON agent_cards(agent_id) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_agent_cards_organization_id ON agent_cards(organization_id);
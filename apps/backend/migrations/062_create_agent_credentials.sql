@@ -0,0 +1,35 @@
+-- Migration: Agent verifiable credentials
+-- Created: 2026-10-19
+-- Purpose: AIM issues W3C Verifiable Credentials asserting an agent's verification status, owner
+--          organization and granted capabilities, so agents can prove their identity to other
+--          organizations without a call to AIM. Each credential holds an index in a
+--          StatusList2021 revocation list; the published list is rebuilt from these rows.
+
+CREATE TABLE IF NOT EXISTS credential_status_lists (
+    id UUID PRIMARY KEY,
+    purpose VARCHAR(20) NOT NULL DEFAULT 'revocation',
+    size INTEGER NOT NULL,
+    next_index INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- agent_id and organization_id deliberately have no foreign keys: credentials outlive their agent,
+-- and the credentials of a deleted agent must stay revoked in the published list
+CREATE TABLE IF NOT EXISTS agent_credentials (
+    id UUID PRIMARY KEY,
+    agent_id UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    subject_did TEXT NOT NULL,
+    capabilities JSONB NOT NULL DEFAULT '[]',
+    status_list_id UUID NOT NULL REFERENCES credential_status_lists(id),
+    status_list_index INTEGER NOT NULL,
+    issued_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    issued_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    revoke_reason TEXT,
+    UNIQUE (status_list_id, status_list_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_credentials_agent_id ON agent_credentials(agent_id);
+CREATE INDEX IF NOT EXISTS idx_agent_credentials_status_list_id ON agent_credentials(status_list_id);
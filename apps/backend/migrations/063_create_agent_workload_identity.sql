@@ -0,0 +1,33 @@
+-- Migration: SPIFFE workload identity for agents
+-- Created: 2026-10-19
+-- Purpose: Agents running as SPIFFE workloads can authenticate with an X.509-SVID or JWT-SVID
+--          instead of Ed25519 request signatures. Admins bind SPIFFE ID patterns to agents;
+--          when AIM's issuing CA is enabled it also mints short-lived X.509-SVIDs for agents.
+
+CREATE TABLE IF NOT EXISTS agent_spiffe_bindings (
+    id UUID PRIMARY KEY,
+    agent_id UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    spiffe_id_pattern TEXT NOT NULL, -- e.g. spiffe://prod.example.org/ns/payments/sa/*
+    trust_domain TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (agent_id, spiffe_id_pattern)
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_spiffe_bindings_trust_domain ON agent_spiffe_bindings(trust_domain);
+
+CREATE TABLE IF NOT EXISTS agent_certificates (
+    id UUID PRIMARY KEY,
+    agent_id UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    serial_number TEXT NOT NULL UNIQUE,
+    spiffe_id TEXT NOT NULL,
+    certificate_pem TEXT NOT NULL,
+    not_before TIMESTAMP NOT NULL,
+    not_after TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_certificates_agent_id ON agent_certificates(agent_id, not_after DESC);
+CREATE INDEX IF NOT EXISTS idx_agent_certificates_not_after ON agent_certificates(not_after);
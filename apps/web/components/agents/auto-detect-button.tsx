@@ -27,9 +27,11 @@ interface AutoDetectButtonProps {
 interface DetectionResult {
   detected_servers: Array<{
     name: string
-    command: string
-    args: string[]
+    command?: string
+    args?: string[]
     env?: Record<string, string>
+    url?: string
+    transport: string
     confidence: number
     source: string
     metadata?: Record<string, any>
@@ -37,25 +39,26 @@ interface DetectionResult {
   registered_count: number
   mapped_count: number
   total_talks_to: number
+  config_format: string
   dry_run: boolean
   errors_encountered?: string[]
 }
 
-// Helper to get default config path based on platform
-function getClaudeDesktopConfigPath(): string {
-  if (typeof window === 'undefined') return ''
+type ConfigFormat = '' | 'claude_desktop' | 'cursor' | 'vscode' | 'windsurf' | 'continue'
 
-  const platform = navigator.platform.toLowerCase()
-
-  if (platform.includes('mac')) {
-    return '~/Library/Application Support/Claude/claude_desktop_config.json'
-  } else if (platform.includes('win')) {
-    return '%APPDATA%/Claude/claude_desktop_config.json'
-  } else {
-    // Linux
-    return '~/.config/Claude/claude_desktop_config.json'
-  }
-}
+// Where each client keeps its MCP config, shown as a hint next to the file picker
+const CONFIG_FORMATS: Array<{ value: ConfigFormat; label: string; hint: string }> = [
+  { value: '', label: 'Detect automatically', hint: 'Any supported config file' },
+  {
+    value: 'claude_desktop',
+    label: 'Claude Desktop',
+    hint: '~/Library/Application Support/Claude/claude_desktop_config.json (macOS), %APPDATA%/Claude/claude_desktop_config.json (Windows)',
+  },
+  { value: 'cursor', label: 'Cursor', hint: '~/.cursor/mcp.json or .cursor/mcp.json in your project' },
+  { value: 'vscode', label: 'VS Code', hint: '.vscode/mcp.json in your project, or settings.json' },
+  { value: 'windsurf', label: 'Windsurf', hint: '~/.codeium/windsurf/mcp_config.json' },
+  { value: 'continue', label: 'Continue', hint: '~/.continue/config.json' },
+]
 
 export function AutoDetectButton({
   agentId,
@@ -65,15 +68,16 @@ export function AutoDetectButton({
 }: AutoDetectButtonProps) {
   const [isOpen, setIsOpen] = useState(false)
   const [isLoading, setIsLoading] = useState(false)
-  const [configPath, setConfigPath] = useState(getClaudeDesktopConfigPath())
+  const [configFile, setConfigFile] = useState<File | null>(null)
+  const [configFormat, setConfigFormat] = useState<ConfigFormat>('')
   const [autoRegister, setAutoRegister] = useState(true)
   const [dryRun, setDryRun] = useState(false)
   const [result, setResult] = useState<DetectionResult | null>(null)
   const [error, setError] = useState<string | null>(null)
 
   const handleDetect = async () => {
-    if (!configPath.trim()) {
-      setError('Config path is required')
+    if (!configFile) {
+      setError('Choose a config file to upload')
       return
     }
 
@@ -83,7 +87,8 @@ export function AutoDetectButton({
 
     try {
       const detectionResult = await api.detectAndMapMCPServers(agentId, {
-        config_path: configPath,
+        config_content: await configFile.text(),
+        config_format: configFormat || undefined,
         auto_register: autoRegister,
         dry_run: dryRun,
       })
@@ -96,7 +101,7 @@ export function AutoDetectButton({
       }
     } catch (err: any) {
       console.error('Auto-detection failed:', err)
-      setError(err.message || 'Failed to auto-detect MCP servers. Please check the config path.')
+      setError(err.message || 'Failed to auto-detect MCP servers. Please check the config file.')
     } finally {
       setIsLoading(false)
     }
@@ -128,23 +133,42 @@ export function AutoDetectButton({
               Auto-Detect MCP Servers
             </DialogTitle>
             <DialogDescription>
-              Automatically detect MCP servers from your Claude Desktop configuration file.
+              Upload the MCP configuration file of Claude Desktop, Cursor, VS Code, Windsurf or
+              Continue to detect the MCP servers this agent uses.
             </DialogDescription>
           </DialogHeader>
 
           <div className="space-y-4 py-4">
-            {/* Config Path Input */}
+            {/* Config Upload */}
+            <div className="space-y-2">
+              <Label htmlFor="config-format">AI Client</Label>
+              <select
+                id="config-format"
+                value={configFormat}
+                onChange={(e) => setConfigFormat(e.target.value as ConfigFormat)}
+                className="w-full px-3 py-2 bg-background border rounded-md text-sm"
+                disabled={isLoading}
+              >
+                {CONFIG_FORMATS.map((format) => (
+                  <option key={format.value} value={format.value}>
+                    {format.label}
+                  </option>
+                ))}
+              </select>
+            </div>
+
             <div className="space-y-2">
-              <Label htmlFor="config-path">Claude Desktop Config Path</Label>
+              <Label htmlFor="config-file">Config File</Label>
               <Input
-                id="config-path"
-                value={configPath}
-                onChange={(e) => setConfigPath(e.target.value)}
-                placeholder="~/Library/Application Support/Claude/claude_desktop_config.json"
+                id="config-file"
+                type="file"
+                accept=".json,application/json"
+                onChange={(e) => setConfigFile(e.target.files?.[0] ?? null)}
                 disabled={isLoading}
               />
               <p className="text-xs text-muted-foreground">
-                Default path has been auto-detected for your platform.
+                {CONFIG_FORMATS.find((format) => format.value === configFormat)?.hint}. Environment
+                variable values are never stored.
               </p>
             </div>
 
@@ -261,9 +285,16 @@ export function AutoDetectButton({
                                 </Badge>
                               </div>
                               <div className="text-xs text-muted-foreground space-y-1">
-                                <div>
-                                  <span className="font-medium">Command:</span> {server.command}
-                                </div>
+                                {server.command ? (
+                                  <div>
+                                    <span className="font-medium">Command:</span> {server.command}
+                                  </div>
+                                ) : (
+                                  <div>
+                                    <span className="font-medium">URL:</span> {server.url} (
+                                    {server.transport})
+                                  </div>
+                                )}
                                 {server.args && server.args.length > 0 && (
                                   <div>
                                     <span className="font-medium">Args:</span>{' '}
@@ -304,8 +335,8 @@ export function AutoDetectButton({
                   <div className="flex items-start gap-2 p-3 rounded-lg bg-muted">
                     <AlertTriangle className="h-5 w-5 mt-0.5 flex-shrink-0" />
                     <div className="text-sm text-muted-foreground">
-                      No MCP servers found in the configuration file. Make sure the config path is
-                      correct and that you have MCP servers configured in Claude Desktop.
+                      No MCP servers found in the configuration file. Make sure you uploaded the
+                      right file and that it has MCP servers configured.
                     </div>
                   </div>
                 )}
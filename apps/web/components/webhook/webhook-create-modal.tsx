@@ -40,6 +40,8 @@ const AVAILABLE_EVENTS = [
   { id: 'api_key.revoked', label: 'API Key Revoked', description: 'Triggered when an API key is revoked' },
   { id: 'api_key.expired', label: 'API Key Expired', description: 'Triggered when an API key expires' },
   { id: 'verification.failed', label: 'Verification Failed', description: 'Triggered when agent verification fails' },
+  { id: 'verification.approval_requested', label: 'Approval Requested', description: 'Triggered when a high-risk agent action is waiting for human approval' },
+  { id: 'verification.approval_decided', label: 'Approval Decided', description: 'Triggered when a pending agent action is approved, denied or times out' },
   { id: 'compliance.violation', label: 'Compliance Violation', description: 'Triggered when a compliance rule is violated' },
 ];
 
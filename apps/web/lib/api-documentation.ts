@@ -844,7 +844,7 @@ export const apiDocumentation: EndpointCategory[] = [
         method: "POST",
         path: "/api/v1/agents/:id/mcp-servers/detect",
         description:
-          "Auto-detect MCP servers from agent config. Parses Claude Desktop config.",
+          "Auto-detect MCP servers from an uploaded AI client config. Parses Claude Desktop, Cursor, VS Code, Windsurf and Continue configs. Also accepts a multipart upload with a \"config\" file.",
         summary: "Auto-detect MCP servers",
         auth: "Bearer Token (JWT)",
         requiresAuth: true,
@@ -853,15 +853,23 @@ export const apiDocumentation: EndpointCategory[] = [
         requestSchema: {
           type: "object",
           properties: {
-            configPath: {
+            config_content: {
               type: "string",
-              description: "Path to Claude Desktop config",
+              description: "Contents of the client's MCP config file",
             },
-            configData: { type: "object", description: "Config JSON data" },
+            config_format: {
+              type: "string",
+              description:
+                "claude_desktop, cursor, vscode, windsurf or continue (detected when omitted)",
+            },
+            auto_register: { type: "boolean", description: "Register new MCP servers" },
+            dry_run: { type: "boolean", description: "Preview without applying" },
           },
         },
         example: `{
-  "configPath": "~/Library/Application Support/Claude/claude_desktop_config.json"
+  "config_content": "{\\"mcpServers\\": {\\"filesystem\\": {\\"command\\": \\"npx\\", \\"args\\": [\\"-y\\", \\"@modelcontextprotocol/server-filesystem\\", \\"/tmp\\"]}}}",
+  "config_format": "claude_desktop",
+  "dry_run": true
 }`,
       },
     ],
@@ -1338,20 +1338,26 @@ class APIClient {
     );
   }
 
-  // Auto-detect MCP servers from Claude Desktop config
+  // Auto-detect MCP servers from an uploaded AI client config
+  // (Claude Desktop, Cursor, VS Code, Windsurf or Continue)
   async detectAndMapMCPServers(
     agentId: string,
     data: {
-      config_path: string;
+      config_content: string;
+      config_format?: "claude_desktop" | "cursor" | "vscode" | "windsurf" | "continue";
       auto_register?: boolean;
       dry_run?: boolean;
     }
   ): Promise<{
     detected_servers: Array<{
       name: string;
-      command: string;
-      args: string[];
+      command?: string;
+      args?: string[];
       env?: Record<string, string>;
+      url?: string;
+      transport: string;
+      package?: { registry: string; name: string; version?: string };
+      identity_hash?: string;
       confidence: number;
       source: string;
       metadata?: Record<string, any>;
@@ -1359,7 +1365,15 @@ class APIClient {
     registered_count: number;
     mapped_count: number;
     total_talks_to: number;
+    config_format: string;
     dry_run: boolean;
+    identity_drift?: Array<{
+      server_id: string;
+      name: string;
+      registered_identity_hash: string;
+      detected_identity_hash: string;
+      changed_fields: string[];
+    }>;
     errors_encountered?: string[];
   }> {
     return this.request(`/api/v1/agents/${agentId}/mcp-servers/detect`, {
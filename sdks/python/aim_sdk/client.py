@@ -298,7 +298,8 @@ class AIMClient:
         action_type: str,
         resource: Optional[str] = None,
         context: Optional[Dict[str, Any]] = None,
-        timeout_seconds: int = 300
+        timeout_seconds: int = 300,
+        callback_url: Optional[str] = None
     ) -> Dict:
         """
         Request verification for an action from AIM.
@@ -307,7 +308,8 @@ class AIMClient:
         1. Creates a verification request with action details
         2. Signs the request with the agent's private key
         3. Sends the request to AIM
-        4. Waits for approval/denial (up to timeout_seconds)
+        4. Waits for approval/denial (up to timeout_seconds). High-risk actions
+           (e.g. delete_data, execute_command) wait for a human approver.
         5. Returns verification result
 
         Args:
@@ -315,6 +317,8 @@ class AIMClient:
             resource: Resource being accessed (e.g., "users_table", "admin@example.com")
             context: Additional context about the action
             timeout_seconds: Maximum time to wait for approval (default: 300s = 5min)
+            callback_url: URL AIM POSTs the outcome to once a pending request is
+                decided or times out (optional; the SDK still waits for the result)
 
         Returns:
             Verification result dict with keys:
@@ -324,7 +328,7 @@ class AIMClient:
             - expires_at: str (ISO timestamp when approval expires)
 
         Raises:
-            ActionDeniedError: If action is explicitly denied
+            ActionDeniedError: If action is denied or no approver decided in time
             VerificationError: If verification request fails
         """
         # Create verification request payload
@@ -341,6 +345,9 @@ class AIMClient:
             "resource": resource,
             "timestamp": timestamp
         }
+        if callback_url:
+            # Signed so the callback cannot be redirected in transit
+            signature_payload["callback_url"] = callback_url
         
         # Create deterministic JSON (sorted keys, spaces after colons and commas)
         signature_message = json.dumps(signature_payload, sort_keys=True, separators=(', ', ': '))
@@ -357,6 +364,8 @@ class AIMClient:
             "signature": signature,  # Ed25519 signature in body
             "public_key": self.public_key  # Public key in body
         }
+        if callback_url:
+            request_payload["callback_url"] = callback_url
 
         # SDK API endpoint
         endpoint = "/api/v1/sdk-api/verifications"
@@ -404,7 +413,10 @@ class AIMClient:
 
     def _wait_for_approval(self, verification_id: str, timeout_seconds: int) -> Dict:
         """
-        Poll AIM server for verification approval.
+        Long-poll AIM server for verification approval.
+
+        Each request asks the server to hold the response until an approver
+        decides (``wait`` query parameter), so decisions arrive within moments.
 
         Args:
             verification_id: ID of the verification request
@@ -414,17 +426,21 @@ class AIMClient:
             Verification result dict
 
         Raises:
-            ActionDeniedError: If action is denied
+            ActionDeniedError: If action is denied or the approval request timed out
             VerificationError: If timeout or polling fails
         """
         start_time = time.time()
-        poll_interval = 2  # Start with 2 second polls
+        poll_interval = 2  # Backoff when the server answers without waiting
 
         while time.time() - start_time < timeout_seconds:
+            remaining = timeout_seconds - (time.time() - start_time)
+            # Stay below the HTTP timeout and the server's 25s long-poll limit
+            wait = max(0, int(min(remaining, self.timeout - 5, 25)))
+            request_started = time.time()
             try:
                 result = self._make_request(
                     method="GET",
-                    endpoint=f"/api/v1/sdk-api/verifications/{verification_id}"
+                    endpoint=f"/api/v1/sdk-api/verifications/{verification_id}?wait={wait}"
                 )
 
                 status = result.get("status")
@@ -441,9 +457,13 @@ class AIMClient:
                     reason = result.get("denial_reason", "Action denied")
                     raise ActionDeniedError(f"Action denied: {reason}")
 
-                # Still pending, wait and retry
-                time.sleep(poll_interval)
-                poll_interval = min(poll_interval * 1.5, 10)  # Exponential backoff up to 10s
+                if status in ("timeout", "expired"):
+                    raise ActionDeniedError("Action not approved: no approver decided before the request timed out")
+
+                # Still pending; back off if the server did not hold the request
+                if time.time() - request_started < 1:
+                    time.sleep(poll_interval)
+                    poll_interval = min(poll_interval * 1.5, 10)  # Exponential backoff up to 10s
 
             except (AuthenticationError, ActionDeniedError):
                 raise
@@ -458,7 +478,10 @@ class AIMClient:
         verification_id: str,
         success: bool,
         result_summary: Optional[str] = None,
-        error_message: Optional[str] = None
+        error_message: Optional[str] = None,
+        bytes_read: Optional[int] = None,
+        bytes_exported: Optional[int] = None,
+        destination: Optional[str] = None
     ):
         """
         Log the result of an action execution to AIM.
@@ -470,7 +493,12 @@ class AIMClient:
             success: Whether the action succeeded
             result_summary: Brief summary of the result
             error_message: Error message if action failed
+            bytes_read: Bytes the action read (counted towards data exfiltration thresholds)
+            bytes_exported: Bytes the action sent out of the agent
+            destination: Where exported bytes were sent (URL, host, bucket, email)
         """
+        metadata = _data_transfer_metadata(bytes_read, bytes_exported, destination)
+
         try:
             self._make_request(
                 method="POST",
@@ -479,6 +507,7 @@ class AIMClient:
                     "result": "success" if success else "failure",
                     "result_summary": result_summary,
                     "error_message": error_message,
+                    "metadata": metadata,
                     "timestamp": datetime.now(timezone.utc).isoformat()
                 }
             )
@@ -486,6 +515,65 @@ class AIMClient:
             # Don't fail the action if logging fails
             pass
 
+    def report_data_transfer(
+        self,
+        resource: str,
+        bytes_read: int = 0,
+        bytes_exported: int = 0,
+        destination: Optional[str] = None,
+        mcp_server: Optional[str] = None
+    ) -> Dict:
+        """
+        Report the bytes an action (typically an MCP tool call) read or exported.
+
+        AIM aggregates these per agent in rolling windows and evaluates the
+        organization's data_exfiltration policy: once the agent exceeds the
+        policy's data_threshold_mb, an alert naming the destinations is raised
+        and blocking policies deny further data:export actions.
+
+        Args:
+            resource: Resource that was read or exported (e.g., "customers_table")
+            bytes_read: Bytes read from the resource
+            bytes_exported: Bytes sent out of the agent
+            destination: Where exported bytes were sent (URL, host, bucket, email)
+            mcp_server: MCP server that performed the call
+
+        Returns:
+            Dict with the agent's usage in the policy window:
+                - bytes_read: int
+                - bytes_exported: int
+                - destinations: List[Dict] - top destinations by bytes exported
+                - exceeded: bool - whether the policy threshold is exceeded
+                - export_blocked: bool - whether data:export actions are now denied
+
+        Raises:
+            ConfigurationError: If byte counts are negative
+            VerificationError: If the report fails
+        """
+        if bytes_read < 0 or bytes_exported < 0:
+            raise ConfigurationError("bytes_read and bytes_exported must not be negative")
+
+        transfer = {
+            "resource": resource,
+            "bytes_read": bytes_read,
+            "bytes_exported": bytes_exported,
+        }
+        if destination:
+            transfer["destination"] = destination
+        if mcp_server:
+            transfer["mcp_server"] = mcp_server
+
+        try:
+            return self._make_request(
+                method="POST",
+                endpoint=f"/api/v1/sdk-api/agents/{self.agent_id}/data-transfers",
+                data={"transfers": [transfer]}
+            )
+        except (AuthenticationError, VerificationError):
+            raise
+        except Exception as e:
+            raise VerificationError(f"Data transfer report failed: {e}")
+
     def request_capability(
         self,
         capability_type: str,
@@ -913,6 +1001,22 @@ import os
 import pathlib
 
 
+def _data_transfer_metadata(
+    bytes_read: Optional[int],
+    bytes_exported: Optional[int],
+    destination: Optional[str]
+) -> Dict[str, Any]:
+    """Build the data transfer fields AIM reads from action result metadata."""
+    metadata: Dict[str, Any] = {}
+    if bytes_read is not None:
+        metadata["bytes_read"] = bytes_read
+    if bytes_exported is not None:
+        metadata["bytes_exported"] = bytes_exported
+    if destination:
+        metadata["destination"] = destination
+    return metadata
+
+
 def _get_credentials_path():
     """Get path to credentials file (~/.aim/credentials.json)."""
     home = pathlib.Path.home()
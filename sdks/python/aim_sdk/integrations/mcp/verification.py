@@ -5,6 +5,7 @@ Functions for verifying MCP tool/resource/prompt usage through AIM.
 """
 
 from typing import Any, Dict, Optional
+import json
 import requests
 
 from aim_sdk.client import AIMClient
@@ -165,6 +166,18 @@ def log_mcp_action_result(
         return False
 
 
+def _result_size(result: Any) -> int:
+    """Size in bytes of a tool result, or 0 when it cannot be measured."""
+    if isinstance(result, (bytes, bytearray)):
+        return len(result)
+    if isinstance(result, str):
+        return len(result.encode("utf-8"))
+    try:
+        return len(json.dumps(result).encode("utf-8")) if result is not None else 0
+    except (TypeError, ValueError):
+        return 0
+
+
 class MCPActionWrapper:
     """
     Wrapper for MCP actions that automatically handles AIM verification.
@@ -268,6 +281,20 @@ class MCPActionWrapper:
                     result_summary=f"Tool '{tool_name}' completed successfully"
                 )
 
+            # Report the size of the tool result for data exfiltration accounting
+            result_size = _result_size(result)
+            if result_size:
+                try:
+                    self.aim_client.report_data_transfer(
+                        resource=f"mcp_tool:{tool_name}",
+                        bytes_read=result_size,
+                        mcp_server=self.mcp_server_id
+                    )
+                except Exception as e:
+                    # Don't fail the tool call if reporting fails
+                    if self.verbose:
+                        print(f"⚠️  AIM: Data transfer report failed: {e}")
+
             if self.verbose:
                 print(f"✅ AIM: Tool execution completed and logged")
 
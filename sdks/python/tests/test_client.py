@@ -219,6 +219,53 @@ class TestVerifyAction:
         assert result["verified"] is True
         assert result["approved_by"] == "admin@example.com"
 
+    @responses.activate
+    def test_verify_action_pending_long_polls_with_callback(self, aim_client):
+        """Test that pending verifications long-poll and sign the callback URL"""
+        responses.add(
+            responses.POST,
+            "https://aim.example.com/api/v1/sdk-api/verifications",
+            json={"id": "verification-123", "status": "pending", "expires_at": "2025-10-07T13:15:00Z"},
+            status=202
+        )
+        responses.add(
+            responses.GET,
+            "https://aim.example.com/api/v1/sdk-api/verifications/verification-123",
+            json={"id": "verification-123", "status": "approved", "approved_by": "admin@example.com"},
+            status=200
+        )
+
+        result = aim_client.verify_action(
+            action_type="delete_data",
+            resource="customers",
+            timeout_seconds=10,
+            callback_url="https://agent.example.com/approvals"
+        )
+
+        assert result["approved_by"] == "admin@example.com"
+        request_body = json.loads(responses.calls[0].request.body)
+        assert request_body["callback_url"] == "https://agent.example.com/approvals"
+        assert "wait=" in responses.calls[1].request.url
+
+    @responses.activate
+    def test_verify_action_pending_then_timed_out(self, aim_client):
+        """Test that an approval request nobody decided is treated as not approved"""
+        responses.add(
+            responses.POST,
+            "https://aim.example.com/api/v1/sdk-api/verifications",
+            json={"id": "verification-123", "status": "pending"},
+            status=202
+        )
+        responses.add(
+            responses.GET,
+            "https://aim.example.com/api/v1/sdk-api/verifications/verification-123",
+            json={"id": "verification-123", "status": "timeout"},
+            status=200
+        )
+
+        with pytest.raises(ActionDeniedError, match="timed out"):
+            aim_client.verify_action(action_type="execute_command", resource="rm -rf /tmp/cache", timeout_seconds=10)
+
     @responses.activate
     def test_verify_action_authentication_error(self, aim_client):
         """Test action verification with authentication failure"""
@@ -299,6 +346,42 @@ class TestLogActionResult:
         )
 
 
+class TestReportDataTransfer:
+    """Test data transfer reporting"""
+
+    @responses.activate
+    def test_report_data_transfer(self, aim_client):
+        """Test reporting bytes exported by an MCP call"""
+        responses.add(
+            responses.POST,
+            "https://aim.example.com/api/v1/sdk-api/agents/550e8400-e29b-41d4-a716-446655440000/data-transfers",
+            json={"bytes_exported": 2048, "exceeded": False, "export_blocked": False},
+            status=200
+        )
+
+        usage = aim_client.report_data_transfer(
+            resource="customers_table",
+            bytes_exported=2048,
+            destination="s3://reports-bucket",
+            mcp_server="postgres"
+        )
+
+        assert usage["export_blocked"] is False
+        request_body = json.loads(responses.calls[0].request.body)
+        assert request_body["transfers"] == [{
+            "resource": "customers_table",
+            "bytes_read": 0,
+            "bytes_exported": 2048,
+            "destination": "s3://reports-bucket",
+            "mcp_server": "postgres"
+        }]
+
+    def test_report_data_transfer_negative_bytes(self, aim_client):
+        """Test that negative byte counts are rejected before sending"""
+        with pytest.raises(ConfigurationError):
+            aim_client.report_data_transfer(resource="customers_table", bytes_read=-1)
+
+
 class TestPerformActionDecorator:
     """Test @perform_action decorator"""
 